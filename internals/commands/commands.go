@@ -0,0 +1,121 @@
+// internals/commands/commands.go
+package commands
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"gorm.io/gorm"
+
+	database "madinahsalam_backend/internals/databases"
+)
+
+/* =========================================================
+   Subcommand binary (tanpa argumen → server HTTP biasa)
+
+   ./app migrate up [N] | down [N] | status | to <ver> | force <ver> | baseline <ver>
+   ./app schema-check [--table=a,b] [--json] [--strict]
+   ./app seed demo [--schools=3] [--students=500] [--seed=42]
+========================================================= */
+
+const usage = `Pemakaian:
+  migrate status                     daftar migrasi (applied/pending/dirty/missing)
+  migrate up [N]                     jalankan N migrasi pending (default: semua)
+  migrate down [N]                   rollback N migrasi terakhir (default: 1)
+  migrate to <version>               naik/turun sampai versi tsb
+  migrate force <version>            hapus flag dirty pada versi tsb (setelah perbaikan manual)
+  migrate baseline <version>         tandai semua migrasi <= versi sebagai applied (DB lama)
+    --sources=a,b                    folder sumber (default: migrations); --all = semua folder
+
+  schema-check                       bandingkan kolom model GORM vs skema live
+    --table=a,b                      hanya tabel tertentu
+    --json                           output JSON
+    --strict                         warning juga dianggap gagal (exit 1)
+
+  seed demo                          data demo multi-tenant (yayasan + sekolah + transaksi)
+    --schools=N --students=N         jumlah sekolah & siswa per sekolah (default 3 / 500)
+    --seed=N                         seed RNG; seed sama → data sama (default 42)
+    --password=...                   password semua akun demo (default demo12345)
+    --today=YYYY-MM-DD               tanggal acuan (default hari ini, Asia/Jakarta)
+    --weeks=N --next-weeks=N         rentang jadwal/absensi ke belakang & ke depan
+
+ENV: MIGRATE_DATABASE_URL (koneksi langsung, bukan PgBouncer) → fallback DATABASE_URL / DB_*`
+
+// Run mengeksekusi subcommand & mengembalikan exit code.
+func Run(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+	switch strings.ToLower(args[0]) {
+	case "migrate":
+		return runMigrate(args[1:])
+	case "schema-check":
+		return runSchemaCheck(args[1:])
+	case "seed":
+		return runSeed(args[1:])
+	case "help", "-h", "--help":
+		fmt.Println(usage)
+		return 0
+	default:
+		fmt.Fprintf(os.Stderr, "❌ Subcommand '%s' tidak dikenali\n\n%s\n", args[0], usage)
+		return 2
+	}
+}
+
+// connect: MIGRATE_DATABASE_URL diprioritaskan (advisory lock butuh session asli).
+func connect() *gorm.DB {
+	if dsn := strings.TrimSpace(os.Getenv("MIGRATE_DATABASE_URL")); dsn != "" {
+		database.ConnectWithDSN(dsn)
+	} else {
+		database.ConnectDB()
+	}
+	return database.DB
+}
+
+/* =========================================================
+   flag ringan: --key=value / --flag, sisanya positional
+========================================================= */
+
+type cliArgs struct {
+	pos   []string
+	flags map[string]string
+}
+
+func parseArgs(args []string) cliArgs {
+	out := cliArgs{flags: map[string]string{}}
+	for _, a := range args {
+		if !strings.HasPrefix(a, "--") {
+			out.pos = append(out.pos, a)
+			continue
+		}
+		k, v, ok := strings.Cut(strings.TrimPrefix(a, "--"), "=")
+		if !ok {
+			v = "true"
+		}
+		out.flags[strings.ToLower(k)] = v
+	}
+	return out
+}
+
+func (a cliArgs) has(k string) bool {
+	v, ok := a.flags[k]
+	return ok && v != "false"
+}
+
+func (a cliArgs) list(k string) []string {
+	var out []string
+	for _, s := range strings.Split(a.flags[k], ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func fail(format string, args ...any) int {
+	log.Printf("❌ "+format, args...)
+	return 1
+}
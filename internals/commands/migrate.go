@@ -0,0 +1,140 @@
+// internals/commands/migrate.go
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	database "madinahsalam_backend/internals/databases"
+	"madinahsalam_backend/internals/databases/migrate"
+)
+
+func runMigrate(raw []string) int {
+	a := parseArgs(raw)
+	if len(a.pos) == 0 {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+
+	sources := []string{database.MigrationSourceCore}
+	if a.has("all") {
+		sources = []string{
+			database.MigrationSourceCore,
+			database.MigrationSourceUpcoming,
+			database.MigrationSourceSchoolUpcoming,
+		}
+	} else if s := a.list("sources"); len(s) > 0 {
+		sources = s
+	}
+
+	migs, err := migrate.Load(database.MigrationFS, sources...)
+	if err != nil {
+		return fail("load migrasi: %v", err)
+	}
+
+	db := connect()
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fail("ambil sql.DB: %v", err)
+	}
+	defer sqlDB.Close()
+
+	r := &migrate.Runner{DB: sqlDB, Migrations: migs, Logf: log.Printf}
+	ctx := context.Background()
+
+	cmd, rest := a.pos[0], a.pos[1:]
+	switch cmd {
+	case "status":
+		rows, err := r.Status(ctx)
+		if err != nil {
+			return fail("status: %v", err)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tSTATE\tSOURCE\tNAME\tAPPLIED_AT")
+		pending := 0
+		for _, row := range rows {
+			at := "-"
+			if row.AppliedAt != nil {
+				at = row.AppliedAt.Local().Format("2006-01-02 15:04:05")
+			}
+			if row.State == "pending" {
+				pending++
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", row.Version, row.State, row.Source, row.Name, at)
+		}
+		w.Flush()
+		fmt.Printf("\n%d migrasi, %d pending\n", len(rows), pending)
+		return 0
+
+	case "up":
+		n, err := optInt(rest, 0)
+		if err != nil {
+			return fail("%v", err)
+		}
+		done, err := r.Up(ctx, n)
+		log.Printf("⬆️  %d migrasi diterapkan", done)
+		if err != nil {
+			return fail("up: %v", err)
+		}
+		return 0
+
+	case "down":
+		n, err := optInt(rest, 1)
+		if err != nil {
+			return fail("%v", err)
+		}
+		done, err := r.Down(ctx, n)
+		log.Printf("⬇️  %d migrasi di-rollback", done)
+		if err != nil {
+			return fail("down: %v", err)
+		}
+		return 0
+
+	case "to", "force", "baseline":
+		if len(rest) != 1 {
+			return fail("migrate %s butuh <version>", cmd)
+		}
+		ver, err := strconv.ParseInt(rest[0], 10, 64)
+		if err != nil {
+			return fail("versi tidak valid: %s", rest[0])
+		}
+		switch cmd {
+		case "to":
+			up, down, err := r.To(ctx, ver)
+			log.Printf("🎯 to %d: %d up, %d down", ver, up, down)
+			if err != nil {
+				return fail("to: %v", err)
+			}
+		case "force":
+			if err := r.Force(ctx, ver); err != nil {
+				return fail("force: %v", err)
+			}
+			log.Printf("✅ versi %d tidak lagi dirty", ver)
+		case "baseline":
+			n, err := r.Baseline(ctx, ver)
+			if err != nil {
+				return fail("baseline: %v", err)
+			}
+			log.Printf("✅ baseline %d: %d versi ditandai applied", ver, n)
+		}
+		return 0
+	}
+
+	fmt.Fprintf(os.Stderr, "❌ migrate %s tidak dikenali\n\n%s\n", cmd, usage)
+	return 2
+}
+
+func optInt(pos []string, def int) (int, error) {
+	if len(pos) == 0 {
+		return def, nil
+	}
+	n, err := strconv.Atoi(pos[0])
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("jumlah tidak valid: %s", pos[0])
+	}
+	return n, nil
+}
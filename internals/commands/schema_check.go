@@ -0,0 +1,57 @@
+// internals/commands/schema_check.go
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"madinahsalam_backend/internals/databases/schemacheck"
+)
+
+func runSchemaCheck(raw []string) int {
+	a := parseArgs(raw)
+	db := connect()
+	if sqlDB, err := db.DB(); err == nil {
+		defer sqlDB.Close()
+	}
+
+	issues, err := schemacheck.Check(context.Background(), db, schemacheck.Options{Tables: a.list("table")})
+	if err != nil {
+		return fail("schema-check: %v", err)
+	}
+
+	errs, warns := 0, 0
+	for _, is := range issues {
+		if is.Severity == schemacheck.SevError {
+			errs++
+		} else {
+			warns++
+		}
+	}
+
+	if a.has("json") {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(map[string]any{
+			"errors":   errs,
+			"warnings": warns,
+			"issues":   issues,
+		})
+	} else {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "SEVERITY\tTABLE\tCOLUMN\tKIND\tMODEL\tDETAIL")
+		for _, is := range issues {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", is.Severity, is.Table, is.Column, is.Kind, is.Model, is.Detail)
+		}
+		w.Flush()
+		fmt.Printf("\n%d model dicek: %d error, %d warning\n", len(schemacheck.Models), errs, warns)
+	}
+
+	if errs > 0 || (a.has("strict") && warns > 0) {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,33 @@
+// internals/commands/seed.go
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"madinahsalam_backend/internals/seeds/demo"
+)
+
+func runSeed(raw []string) int {
+	if len(raw) == 0 || strings.ToLower(raw[0]) != "demo" {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+	opt, err := demo.ParseArgs(raw[1:])
+	if err != nil {
+		return fail("argumen seed: %v", err)
+	}
+
+	stats, err := demo.Run(context.Background(), connect(), opt)
+	if errors.Is(err, demo.ErrAlreadySeeded) {
+		return fail("%v", err)
+	}
+	if err != nil {
+		return fail("seed demo: %v", err)
+	}
+	stats.Print()
+	return 0
+}
@@ -24,6 +24,12 @@ const (
 	RoleAuthor    = "author"
 	RoleStudent   = "student"
 
+	// Scope yayasan (lintas school); disimpan di tabel yayasan_admins, bukan user_roles
+	RoleYayasanAdmin = "yayasan_admin"
+
+	// Principal mesin (API key sekolah); tidak pernah disimpan di user_roles
+	RoleAPIKey = "api_key"
+
 	// Deprecated: gunakan RoleTreasurer
 	RoleAccountantDeprecated = "accountant"
 )
@@ -73,6 +79,11 @@ var ContentCreators = []string{
 	RoleAuthor, RoleTeacher, RoleAdmin, RoleDKM, RoleOwner,
 }
 
+// Role yang boleh diwajibkan 2FA lewat kebijakan sekolah (school_mfa_policies)
+var TwoFactorEligibleRoles = []string{
+	RoleDKM, RoleAdmin, RoleTreasurer, RoleOwner, RoleTeacher, RoleAuthor,
+}
+
 var (
 	OwnerOnly = []string{RoleOwner}
 	AdminOnly = []string{RoleAdmin}
@@ -54,6 +54,11 @@ func ConnectDB() {
 	openWithDSN(dsn)
 }
 
+// ConnectWithDSN: koneksi eksplisit (mis. MIGRATE_DATABASE_URL tanpa PgBouncer).
+func ConnectWithDSN(dsn string) {
+	openWithDSN(dsn)
+}
+
 func openWithDSN(dsn string) {
 	log.Printf("🔎 Connecting with DSN: %s", redactDSN(dsn))
 
@@ -0,0 +1,17 @@
+// internals/databases/embed.go
+package database
+
+import "embed"
+
+// MigrationFS: file .up.sql/.down.sql ikut ter-embed di binary (dipakai `migrate`).
+// migrations_plan TIDAK ikut — isinya catatan desain, bukan migrasi ber-versi.
+//
+//go:embed migrations/*.sql migrations_upcoming/*.sql migration_school_upcoming/*.sql
+var MigrationFS embed.FS
+
+// Urutan default sumber migrasi (folder di dalam MigrationFS)
+const (
+	MigrationSourceCore           = "migrations"
+	MigrationSourceUpcoming       = "migrations_upcoming"
+	MigrationSourceSchoolUpcoming = "migration_school_upcoming"
+)
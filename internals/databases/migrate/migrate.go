@@ -0,0 +1,485 @@
+// internals/databases/migrate/migrate.go
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/* =========================================================
+   Migration runner (embed .up.sql / .down.sql)
+   - nama file: <version>_<nama>.(up|down).sql
+   - tabel versi: schema_migrations
+   - pg_advisory_lock: hanya 1 proses migrasi dalam satu waktu
+========================================================= */
+
+const (
+	VersionTable = "schema_migrations"
+
+	// kunci advisory lock (konstan; sama untuk semua instance)
+	advisoryLockKey int64 = 7_231_104_512_001
+)
+
+var (
+	ErrDirty        = errors.New("ada migrasi dirty (gagal di tengah); perbaiki manual lalu jalankan `migrate force <version>`")
+	ErrNoDownScript = errors.New("file .down.sql tidak ada")
+	ErrUnknownVer   = errors.New("versi tidak dikenal")
+)
+
+var (
+	reFileName = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+	// file yang mengatur transaksinya sendiri (BEGIN; ... COMMIT;)
+	reOwnTx = regexp.MustCompile(`(?im)^\s*(BEGIN|START\s+TRANSACTION)\s*;`)
+	// statement yang tidak boleh di dalam transaksi
+	reNoTx = regexp.MustCompile(`(?i)\bCONCURRENTLY\b|\bALTER\s+TYPE\s+\S+\s+ADD\s+VALUE\b`)
+)
+
+type Migration struct {
+	Version int64
+	Name    string
+	Source  string
+	Up      string
+	Down    string
+
+	hasUp, hasDown bool // file ada (isi boleh kosong = placeholder no-op)
+}
+
+type Applied struct {
+	Version   int64
+	Name      string
+	Source    string
+	Dirty     bool
+	AppliedAt time.Time
+}
+
+// Load membaca migrasi dari beberapa folder di fsys (urut versi, lintas folder).
+func Load(fsys fs.FS, sources ...string) ([]Migration, error) {
+	byVer := map[int64]*Migration{}
+	for _, src := range sources {
+		entries, err := fs.ReadDir(fsys, src)
+		if err != nil {
+			return nil, fmt.Errorf("baca %s: %w", src, err)
+		}
+		for _, e := range entries {
+			if e.IsDir() {
+				continue
+			}
+			m := reFileName.FindStringSubmatch(e.Name())
+			if m == nil {
+				continue
+			}
+			ver, err := strconv.ParseInt(m[1], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("versi tidak valid: %s", e.Name())
+			}
+			raw, err := fs.ReadFile(fsys, path.Join(src, e.Name()))
+			if err != nil {
+				return nil, err
+			}
+
+			mg, ok := byVer[ver]
+			if !ok {
+				mg = &Migration{Version: ver, Name: m[2], Source: src}
+				byVer[ver] = mg
+			} else if mg.Source != src {
+				return nil, fmt.Errorf("versi %d dobel: %s & %s", ver, mg.Source, src)
+			}
+			if m[3] == "up" {
+				mg.Up, mg.hasUp = string(raw), true
+			} else {
+				mg.Down, mg.hasDown = string(raw), true
+			}
+		}
+	}
+
+	out := make([]Migration, 0, len(byVer))
+	for _, m := range byVer {
+		if !m.hasUp {
+			return nil, fmt.Errorf("migrasi %d_%s tidak punya .up.sql", m.Version, m.Name)
+		}
+		out = append(out, *m)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out, nil
+}
+
+/* =========================================================
+   Runner
+========================================================= */
+
+type Runner struct {
+	DB         *sql.DB
+	Migrations []Migration
+	Logf       func(format string, args ...any)
+
+	conn *sql.Conn
+}
+
+// withLock: ambil 1 koneksi khusus + advisory lock (session level).
+// Catatan: PgBouncer mode transaction tidak menjamin lock session →
+// gunakan MIGRATE_DATABASE_URL ke koneksi langsung.
+func (r *Runner) withLock(ctx context.Context, fn func(ctx context.Context) error) error {
+	conn, err := r.DB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// DSN aplikasi memasang statement_timeout pendek; migrasi butuh lebih lama
+	if _, err := conn.ExecContext(ctx, `SET statement_timeout = 0`); err != nil {
+		return err
+	}
+	r.logf("menunggu advisory lock...")
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, advisoryLockKey); err != nil {
+		return fmt.Errorf("advisory lock: %w", err)
+	}
+	defer func() {
+		_, _ = conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, advisoryLockKey)
+	}()
+
+	r.conn = conn
+	defer func() { r.conn = nil }()
+
+	if err := r.ensureVersionTable(ctx); err != nil {
+		return err
+	}
+	return fn(ctx)
+}
+
+func (r *Runner) logf(format string, args ...any) {
+	if r.Logf != nil {
+		r.Logf(format, args...)
+	}
+}
+
+func (r *Runner) ensureVersionTable(ctx context.Context) error {
+	_, err := r.conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS `+VersionTable+` (
+		  version    BIGINT PRIMARY KEY,
+		  name       TEXT NOT NULL,
+		  source     TEXT NOT NULL,
+		  dirty      BOOLEAN NOT NULL DEFAULT FALSE,
+		  applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		)`)
+	return err
+}
+
+func (r *Runner) applied(ctx context.Context) (map[int64]Applied, error) {
+	rows, err := r.conn.QueryContext(ctx,
+		`SELECT version, name, source, dirty, applied_at FROM `+VersionTable+` ORDER BY version`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := map[int64]Applied{}
+	for rows.Next() {
+		var a Applied
+		if err := rows.Scan(&a.Version, &a.Name, &a.Source, &a.Dirty, &a.AppliedAt); err != nil {
+			return nil, err
+		}
+		out[a.Version] = a
+	}
+	return out, rows.Err()
+}
+
+func checkDirty(applied map[int64]Applied) error {
+	for _, a := range applied {
+		if a.Dirty {
+			return fmt.Errorf("%w (version=%d)", ErrDirty, a.Version)
+		}
+	}
+	return nil
+}
+
+/* =========================================================
+   Status
+========================================================= */
+
+type StatusRow struct {
+	Version   int64
+	Name      string
+	Source    string
+	State     string // applied | pending | dirty | missing (ada di DB, file tidak ada)
+	AppliedAt *time.Time
+}
+
+func (r *Runner) Status(ctx context.Context) ([]StatusRow, error) {
+	var out []StatusRow
+	err := r.withLock(ctx, func(ctx context.Context) error {
+		applied, err := r.applied(ctx)
+		if err != nil {
+			return err
+		}
+		known := map[int64]struct{}{}
+		for _, m := range r.Migrations {
+			known[m.Version] = struct{}{}
+			row := StatusRow{Version: m.Version, Name: m.Name, Source: m.Source, State: "pending"}
+			if a, ok := applied[m.Version]; ok {
+				t := a.AppliedAt
+				row.AppliedAt = &t
+				row.State = "applied"
+				if a.Dirty {
+					row.State = "dirty"
+				}
+			}
+			out = append(out, row)
+		}
+		for v, a := range applied {
+			if _, ok := known[v]; ok {
+				continue
+			}
+			t := a.AppliedAt
+			out = append(out, StatusRow{Version: v, Name: a.Name, Source: a.Source, State: "missing", AppliedAt: &t})
+		}
+		sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+		return nil
+	})
+	return out, err
+}
+
+/* =========================================================
+   Up / Down / To / Force / Baseline
+========================================================= */
+
+// Up menjalankan migrasi pending (limit<=0 → semua).
+func (r *Runner) Up(ctx context.Context, limit int) (int, error) {
+	n := 0
+	err := r.withLock(ctx, func(ctx context.Context) error {
+		applied, err := r.applied(ctx)
+		if err != nil {
+			return err
+		}
+		if err := checkDirty(applied); err != nil {
+			return err
+		}
+		for _, m := range r.Migrations {
+			if _, ok := applied[m.Version]; ok {
+				continue
+			}
+			if limit > 0 && n >= limit {
+				break
+			}
+			if err := r.apply(ctx, m, true); err != nil {
+				return err
+			}
+			n++
+		}
+		return nil
+	})
+	return n, err
+}
+
+// Down me-rollback `limit` migrasi terakhir (default 1).
+func (r *Runner) Down(ctx context.Context, limit int) (int, error) {
+	if limit <= 0 {
+		limit = 1
+	}
+	n := 0
+	err := r.withLock(ctx, func(ctx context.Context) error {
+		applied, err := r.applied(ctx)
+		if err != nil {
+			return err
+		}
+		if err := checkDirty(applied); err != nil {
+			return err
+		}
+		for i := len(r.Migrations) - 1; i >= 0 && n < limit; i-- {
+			m := r.Migrations[i]
+			if _, ok := applied[m.Version]; !ok {
+				continue
+			}
+			if err := r.apply(ctx, m, false); err != nil {
+				return err
+			}
+			n++
+		}
+		return nil
+	})
+	return n, err
+}
+
+// To: naik/turun sampai versi target (inklusif). target=0 → rollback semua.
+func (r *Runner) To(ctx context.Context, target int64) (up, down int, err error) {
+	if target != 0 && !r.known(target) {
+		return 0, 0, fmt.Errorf("%w: %d", ErrUnknownVer, target)
+	}
+	err = r.withLock(ctx, func(ctx context.Context) error {
+		applied, err := r.applied(ctx)
+		if err != nil {
+			return err
+		}
+		if err := checkDirty(applied); err != nil {
+			return err
+		}
+		// turun dulu (urut mundur), baru naik
+		for i := len(r.Migrations) - 1; i >= 0; i-- {
+			m := r.Migrations[i]
+			if m.Version <= target {
+				break
+			}
+			if _, ok := applied[m.Version]; !ok {
+				continue
+			}
+			if err := r.apply(ctx, m, false); err != nil {
+				return err
+			}
+			down++
+		}
+		for _, m := range r.Migrations {
+			if m.Version > target {
+				break
+			}
+			if _, ok := applied[m.Version]; ok {
+				continue
+			}
+			if err := r.apply(ctx, m, true); err != nil {
+				return err
+			}
+			up++
+		}
+		return nil
+	})
+	return up, down, err
+}
+
+// Force membersihkan flag dirty (setelah perbaikan manual).
+func (r *Runner) Force(ctx context.Context, version int64) error {
+	return r.withLock(ctx, func(ctx context.Context) error {
+		res, err := r.conn.ExecContext(ctx,
+			`UPDATE `+VersionTable+` SET dirty = FALSE WHERE version = $1`, version)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return fmt.Errorf("%w: %d belum tercatat", ErrUnknownVer, version)
+		}
+		return nil
+	})
+}
+
+// Baseline menandai semua migrasi <= version sebagai applied TANPA menjalankan SQL
+// (untuk DB lama yang skemanya dibuat manual).
+func (r *Runner) Baseline(ctx context.Context, version int64) (int, error) {
+	if !r.known(version) {
+		return 0, fmt.Errorf("%w: %d", ErrUnknownVer, version)
+	}
+	n := 0
+	err := r.withLock(ctx, func(ctx context.Context) error {
+		for _, m := range r.Migrations {
+			if m.Version > version {
+				break
+			}
+			res, err := r.conn.ExecContext(ctx, `
+				INSERT INTO `+VersionTable+` (version, name, source, dirty)
+				VALUES ($1, $2, $3, FALSE)
+				ON CONFLICT (version) DO NOTHING`, m.Version, m.Name, m.Source)
+			if err != nil {
+				return err
+			}
+			if c, _ := res.RowsAffected(); c > 0 {
+				n++
+			}
+		}
+		return nil
+	})
+	return n, err
+}
+
+func (r *Runner) known(version int64) bool {
+	for _, m := range r.Migrations {
+		if m.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+/* =========================================================
+   Apply 1 migrasi
+========================================================= */
+
+func (r *Runner) apply(ctx context.Context, m Migration, up bool) error {
+	script, dir := m.Up, "up"
+	if !up {
+		script, dir = m.Down, "down"
+		if !m.hasDown {
+			return fmt.Errorf("%w: %d_%s", ErrNoDownScript, m.Version, m.Name)
+		}
+	}
+	r.logf("→ %s %d_%s (%s)", dir, m.Version, m.Name, m.Source)
+	start := time.Now()
+
+	var err error
+	if reOwnTx.MatchString(script) || reNoTx.MatchString(script) {
+		err = r.applyUnmanaged(ctx, m, script, up)
+	} else {
+		err = r.applyInTx(ctx, m, script, up)
+	}
+	if err != nil {
+		return fmt.Errorf("%s %d_%s: %w", dir, m.Version, m.Name, err)
+	}
+	r.logf("  ok (%s)", time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+// Script + catat versi dalam 1 transaksi (atomik).
+func (r *Runner) applyInTx(ctx context.Context, m Migration, script string, up bool) error {
+	tx, err := r.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if strings.TrimSpace(script) != "" {
+		if _, err := tx.ExecContext(ctx, script); err != nil {
+			return err
+		}
+	}
+	if up {
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO `+VersionTable+` (version, name, source, dirty) VALUES ($1, $2, $3, FALSE)`,
+			m.Version, m.Name, m.Source)
+	} else {
+		_, err = tx.ExecContext(ctx, `DELETE FROM `+VersionTable+` WHERE version = $1`, m.Version)
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Script yang mengatur transaksi sendiri: tandai dirty dulu, jalankan, lalu bersihkan.
+func (r *Runner) applyUnmanaged(ctx context.Context, m Migration, script string, up bool) error {
+	if _, err := r.conn.ExecContext(ctx, `
+		INSERT INTO `+VersionTable+` (version, name, source, dirty)
+		VALUES ($1, $2, $3, TRUE)
+		ON CONFLICT (version) DO UPDATE SET dirty = TRUE`,
+		m.Version, m.Name, m.Source); err != nil {
+		return err
+	}
+
+	if _, err := r.conn.ExecContext(ctx, script); err != nil {
+		// BEGIN di dalam script bisa meninggalkan transaksi aborted di koneksi ini
+		_, _ = r.conn.ExecContext(context.Background(), `ROLLBACK`)
+		return err
+	}
+
+	var err error
+	if up {
+		_, err = r.conn.ExecContext(ctx,
+			`UPDATE `+VersionTable+` SET dirty = FALSE, applied_at = now() WHERE version = $1`, m.Version)
+	} else {
+		_, err = r.conn.ExecContext(ctx, `DELETE FROM `+VersionTable+` WHERE version = $1`, m.Version)
+	}
+	return err
+}
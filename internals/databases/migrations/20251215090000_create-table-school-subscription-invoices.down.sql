@@ -0,0 +1,37 @@
+-- +migrate Down
+BEGIN;
+
+-- ============================ --
+-- DOWN: SCHOOL SERVICE INVOICES
+-- ============================ --
+DROP INDEX IF EXISTS ux_ssi_number_alive;
+DROP INDEX IF EXISTS ux_ssi_subscription_period_alive;
+DROP INDEX IF EXISTS idx_ssi_school_status_alive;
+DROP INDEX IF EXISTS idx_ssi_payment_alive;
+DROP INDEX IF EXISTS idx_ssi_status_due_alive;
+DROP INDEX IF EXISTS brin_ssi_created_at;
+
+DROP TABLE IF EXISTS school_service_invoices;
+
+DROP TYPE IF EXISTS school_service_invoice_status_enum;
+
+-- ============================ --
+-- DOWN: kolom billing di school_service_subscriptions
+-- ============================ --
+DROP INDEX IF EXISTS idx_mss_status_period_end_alive;
+DROP INDEX IF EXISTS idx_mss_status_grace_alive;
+
+ALTER TABLE school_service_subscriptions
+  DROP CONSTRAINT IF EXISTS ck_mss_billing_cycle,
+  DROP CONSTRAINT IF EXISTS ck_mss_lapse_action,
+  DROP CONSTRAINT IF EXISTS ck_mss_current_period_order;
+
+ALTER TABLE school_service_subscriptions
+  DROP COLUMN IF EXISTS school_service_subscription_billing_cycle,
+  DROP COLUMN IF EXISTS school_service_subscription_current_period_start,
+  DROP COLUMN IF EXISTS school_service_subscription_current_period_end,
+  DROP COLUMN IF EXISTS school_service_subscription_grace_until,
+  DROP COLUMN IF EXISTS school_service_subscription_lapsed_at,
+  DROP COLUMN IF EXISTS school_service_subscription_lapse_action;
+
+COMMIT;
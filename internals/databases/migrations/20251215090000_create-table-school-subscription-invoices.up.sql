@@ -0,0 +1,140 @@
+-- +migrate Up
+/* =====================================================================
+   SCHOOL SUBSCRIPTION BILLING (platform invoicing)
+   - Alter: school_service_subscriptions (siklus, periode berjalan, grace)
+   - Enum : school_service_invoice_status_enum
+   - Table: school_service_invoices
+   Catatan:
+   - Pembayaran invoice memakai tabel payments dengan payment_school_id = NULL
+     (pendapatan platform), relasi lewat school_service_invoice_payment_id.
+   ===================================================================== */
+
+BEGIN;
+
+-- ---------------------------------------------------------------------
+-- ALTER: school_service_subscriptions
+-- ---------------------------------------------------------------------
+ALTER TABLE school_service_subscriptions
+  ADD COLUMN IF NOT EXISTS school_service_subscription_billing_cycle VARCHAR(10) NOT NULL DEFAULT 'monthly',
+  ADD COLUMN IF NOT EXISTS school_service_subscription_current_period_start TIMESTAMPTZ,
+  ADD COLUMN IF NOT EXISTS school_service_subscription_current_period_end   TIMESTAMPTZ,
+  ADD COLUMN IF NOT EXISTS school_service_subscription_grace_until          TIMESTAMPTZ,
+  ADD COLUMN IF NOT EXISTS school_service_subscription_lapsed_at            TIMESTAMPTZ,
+  ADD COLUMN IF NOT EXISTS school_service_subscription_lapse_action         VARCHAR(16) NOT NULL DEFAULT 'downgrade';
+
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'ck_mss_billing_cycle') THEN
+    ALTER TABLE school_service_subscriptions
+      ADD CONSTRAINT ck_mss_billing_cycle
+      CHECK (school_service_subscription_billing_cycle IN ('monthly','yearly'));
+  END IF;
+  IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'ck_mss_lapse_action') THEN
+    ALTER TABLE school_service_subscriptions
+      ADD CONSTRAINT ck_mss_lapse_action
+      CHECK (school_service_subscription_lapse_action IN ('downgrade','read_only'));
+  END IF;
+  IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'ck_mss_current_period_order') THEN
+    ALTER TABLE school_service_subscriptions
+      ADD CONSTRAINT ck_mss_current_period_order
+      CHECK (
+        school_service_subscription_current_period_end IS NULL
+        OR school_service_subscription_current_period_start IS NULL
+        OR school_service_subscription_current_period_end > school_service_subscription_current_period_start
+      );
+  END IF;
+END$$;
+
+-- Cron: cari periode yang mau habis / grace yang lewat
+CREATE INDEX IF NOT EXISTS idx_mss_status_period_end_alive
+  ON school_service_subscriptions (school_service_subscription_status, school_service_subscription_current_period_end)
+  WHERE school_service_subscription_deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_mss_status_grace_alive
+  ON school_service_subscriptions (school_service_subscription_status, school_service_subscription_grace_until)
+  WHERE school_service_subscription_deleted_at IS NULL;
+
+-- ---------------------------------------------------------------------
+-- ENUM: status invoice
+-- ---------------------------------------------------------------------
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'school_service_invoice_status_enum') THEN
+    CREATE TYPE school_service_invoice_status_enum AS ENUM (
+      'open','paid','overdue','void'
+    );
+  END IF;
+END$$;
+
+-- ---------------------------------------------------------------------
+-- TABLE: school_service_invoices
+-- ---------------------------------------------------------------------
+CREATE TABLE IF NOT EXISTS school_service_invoices (
+  school_service_invoice_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+
+  school_service_invoice_school_id UUID NOT NULL
+    REFERENCES schools(school_id) ON DELETE CASCADE,
+  school_service_invoice_subscription_id UUID NOT NULL
+    REFERENCES school_service_subscriptions(school_service_subscription_id) ON DELETE CASCADE,
+  school_service_invoice_plan_id UUID NOT NULL
+    REFERENCES school_service_plans(school_service_plan_id) ON DELETE RESTRICT,
+
+  school_service_invoice_number VARCHAR(40) NOT NULL,
+
+  -- Periode yang ditagih
+  school_service_invoice_billing_cycle VARCHAR(10) NOT NULL
+    CHECK (school_service_invoice_billing_cycle IN ('monthly','yearly')),
+  school_service_invoice_period_start TIMESTAMPTZ NOT NULL,
+  school_service_invoice_period_end   TIMESTAMPTZ NOT NULL,
+
+  -- Nominal (IDR, bulat)
+  school_service_invoice_amount_idr INT NOT NULL CHECK (school_service_invoice_amount_idr >= 0),
+
+  school_service_invoice_status school_service_invoice_status_enum NOT NULL DEFAULT 'open',
+  school_service_invoice_due_at  TIMESTAMPTZ NOT NULL,
+  school_service_invoice_paid_at TIMESTAMPTZ,
+
+  -- Payment header (payments.payment_school_id = NULL → revenue platform)
+  school_service_invoice_payment_id UUID
+    REFERENCES payments(payment_id) ON DELETE SET NULL,
+
+  -- Snapshot
+  school_service_invoice_plan_name_snapshot   VARCHAR(100) NOT NULL,
+  school_service_invoice_school_name_snapshot VARCHAR(100),
+
+  school_service_invoice_created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  school_service_invoice_updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  school_service_invoice_deleted_at TIMESTAMPTZ,
+
+  CONSTRAINT ck_ssi_period_order CHECK (
+    school_service_invoice_period_end > school_service_invoice_period_start
+  )
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_ssi_number_alive
+  ON school_service_invoices (school_service_invoice_number)
+  WHERE school_service_invoice_deleted_at IS NULL;
+
+-- 1 invoice hidup per (subscription, awal periode)
+CREATE UNIQUE INDEX IF NOT EXISTS ux_ssi_subscription_period_alive
+  ON school_service_invoices (school_service_invoice_subscription_id, school_service_invoice_period_start)
+  WHERE school_service_invoice_deleted_at IS NULL
+    AND school_service_invoice_status <> 'void';
+
+CREATE INDEX IF NOT EXISTS idx_ssi_school_status_alive
+  ON school_service_invoices (school_service_invoice_school_id, school_service_invoice_status)
+  WHERE school_service_invoice_deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_ssi_payment_alive
+  ON school_service_invoices (school_service_invoice_payment_id)
+  WHERE school_service_invoice_deleted_at IS NULL
+    AND school_service_invoice_payment_id IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_ssi_status_due_alive
+  ON school_service_invoices (school_service_invoice_status, school_service_invoice_due_at)
+  WHERE school_service_invoice_deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS brin_ssi_created_at
+  ON school_service_invoices USING brin (school_service_invoice_created_at);
+
+COMMIT;
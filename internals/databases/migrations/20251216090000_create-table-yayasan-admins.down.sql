@@ -0,0 +1,9 @@
+-- +migrate Down
+BEGIN;
+
+DROP INDEX IF EXISTS idx_yayasan_admins_user_alive;
+DROP INDEX IF EXISTS ux_yayasan_admins_alive;
+
+DROP TABLE IF EXISTS yayasan_admins;
+
+COMMIT;
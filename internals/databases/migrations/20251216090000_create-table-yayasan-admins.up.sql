@@ -0,0 +1,37 @@
+-- +migrate Up
+/* =====================================================================
+   YAYASAN ADMINS (scope: yayasan_admin)
+   - Otorisasi lintas semua school di bawah satu yayasan
+     (schools.school_yayasan_id = yayasan_admin_yayasan_id)
+   - Sengaja TIDAK di user_roles: school_id NULL di sana berarti role global
+   ===================================================================== */
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS yayasan_admins (
+  yayasan_admin_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+
+  yayasan_admin_yayasan_id UUID NOT NULL
+    REFERENCES yayasans(yayasan_id) ON DELETE CASCADE,
+  yayasan_admin_user_id UUID NOT NULL
+    REFERENCES users(id) ON DELETE CASCADE,
+
+  yayasan_admin_assigned_by UUID
+    REFERENCES users(id) ON DELETE SET NULL,
+
+  yayasan_admin_created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  yayasan_admin_updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  yayasan_admin_deleted_at TIMESTAMPTZ
+);
+
+-- 1 baris hidup per (yayasan, user)
+CREATE UNIQUE INDEX IF NOT EXISTS ux_yayasan_admins_alive
+  ON yayasan_admins (yayasan_admin_yayasan_id, yayasan_admin_user_id)
+  WHERE yayasan_admin_deleted_at IS NULL;
+
+-- Lookup dari sisi user (guard middleware)
+CREATE INDEX IF NOT EXISTS idx_yayasan_admins_user_alive
+  ON yayasan_admins (yayasan_admin_user_id)
+  WHERE yayasan_admin_deleted_at IS NULL;
+
+COMMIT;
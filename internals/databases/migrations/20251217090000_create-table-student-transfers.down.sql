@@ -0,0 +1,12 @@
+-- +migrate Down
+BEGIN;
+
+DROP INDEX IF EXISTS idx_st_destination_status_alive;
+DROP INDEX IF EXISTS idx_st_origin_status_alive;
+DROP INDEX IF EXISTS ux_st_origin_student_pending;
+
+DROP TABLE IF EXISTS student_transfers;
+
+DROP TYPE IF EXISTS student_transfer_status_enum;
+
+COMMIT;
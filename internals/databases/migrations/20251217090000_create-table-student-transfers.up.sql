@@ -0,0 +1,78 @@
+-- +migrate Up
+/* =====================================================================
+   STUDENT TRANSFERS (mutasi siswa antar school di platform)
+   - Diajukan oleh school asal, disetujui/ditolak oleh school tujuan
+   - Approve: school_students asal ditutup (left_at, inactive),
+              school_students tujuan dibuat (active)
+   - Transcript bundle dibekukan di kolom JSONB → school tujuan
+     TIDAK pernah membaca tabel milik school asal secara langsung
+   ===================================================================== */
+
+BEGIN;
+
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'student_transfer_status_enum') THEN
+    CREATE TYPE student_transfer_status_enum AS ENUM (
+      'pending','approved','rejected','canceled'
+    );
+  END IF;
+END$$;
+
+CREATE TABLE IF NOT EXISTS student_transfers (
+  student_transfer_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+
+  -- Asal
+  student_transfer_origin_school_id UUID NOT NULL
+    REFERENCES schools(school_id) ON DELETE CASCADE,
+  student_transfer_origin_school_student_id UUID NOT NULL,
+  CONSTRAINT fk_st_origin_student_tenant
+    FOREIGN KEY (student_transfer_origin_school_student_id, student_transfer_origin_school_id)
+    REFERENCES school_students (school_student_id, school_student_school_id)
+    ON UPDATE CASCADE ON DELETE CASCADE,
+
+  -- Tujuan
+  student_transfer_destination_school_id UUID NOT NULL
+    REFERENCES schools(school_id) ON DELETE CASCADE,
+  student_transfer_destination_school_student_id UUID,
+
+  -- Identitas siswa (portable)
+  student_transfer_user_profile_id UUID NOT NULL
+    REFERENCES user_profiles(user_profile_id) ON DELETE CASCADE,
+  student_transfer_student_name_snapshot VARCHAR(80),
+
+  student_transfer_status student_transfer_status_enum NOT NULL DEFAULT 'pending',
+  student_transfer_reason TEXT,
+  student_transfer_decision_note TEXT,
+
+  -- Bundle transkrip (nilai mapel, rekap kehadiran, tunggakan) — snapshot
+  student_transfer_transcript JSONB NOT NULL DEFAULT '{}'::jsonb,
+
+  student_transfer_requested_by_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+  student_transfer_decided_by_user_id   UUID REFERENCES users(id) ON DELETE SET NULL,
+  student_transfer_decided_at TIMESTAMPTZ,
+
+  student_transfer_created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  student_transfer_updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  student_transfer_deleted_at TIMESTAMPTZ,
+
+  CONSTRAINT ck_st_distinct_schools CHECK (
+    student_transfer_origin_school_id <> student_transfer_destination_school_id
+  )
+);
+
+-- 1 transfer pending per siswa asal
+CREATE UNIQUE INDEX IF NOT EXISTS ux_st_origin_student_pending
+  ON student_transfers (student_transfer_origin_school_student_id)
+  WHERE student_transfer_deleted_at IS NULL
+    AND student_transfer_status = 'pending';
+
+CREATE INDEX IF NOT EXISTS idx_st_origin_status_alive
+  ON student_transfers (student_transfer_origin_school_id, student_transfer_status, student_transfer_created_at DESC)
+  WHERE student_transfer_deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_st_destination_status_alive
+  ON student_transfers (student_transfer_destination_school_id, student_transfer_status, student_transfer_created_at DESC)
+  WHERE student_transfer_deleted_at IS NULL;
+
+COMMIT;
@@ -0,0 +1,17 @@
+-- +migrate Down
+BEGIN;
+
+DROP INDEX IF EXISTS idx_import_jobs_status_created_alive;
+DROP INDEX IF EXISTS idx_import_jobs_school_created_alive;
+
+DROP TABLE IF EXISTS import_jobs;
+
+DROP TYPE IF EXISTS import_job_kind_enum;
+DROP TYPE IF EXISTS import_job_status_enum;
+
+DROP INDEX IF EXISTS ux_user_profile_nisn_alive;
+
+ALTER TABLE user_profiles
+  DROP COLUMN IF EXISTS user_profile_nisn;
+
+COMMIT;
@@ -0,0 +1,92 @@
+-- +migrate Up
+/* =====================================================================
+   BULK IMPORT (siswa / guru dari XLSX/CSV, termasuk ekspor Dapodik)
+   - user_profiles.user_profile_nisn → kunci de-dup lintas tenant
+   - import_jobs → job async (antri → validasi/dry-run → proses batch)
+   ===================================================================== */
+
+BEGIN;
+
+-- ---------------------------------------------------------------------
+-- NISN di user_profiles (nasional, unik bila terisi)
+-- ---------------------------------------------------------------------
+ALTER TABLE user_profiles
+  ADD COLUMN IF NOT EXISTS user_profile_nisn VARCHAR(20);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_user_profile_nisn_alive
+  ON user_profiles (user_profile_nisn)
+  WHERE user_profile_deleted_at IS NULL
+    AND user_profile_nisn IS NOT NULL;
+
+-- ---------------------------------------------------------------------
+-- ENUMS
+-- ---------------------------------------------------------------------
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'import_job_status_enum') THEN
+    CREATE TYPE import_job_status_enum AS ENUM (
+      'queued','validating','validated','running','completed','failed','canceled'
+    );
+  END IF;
+  IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'import_job_kind_enum') THEN
+    CREATE TYPE import_job_kind_enum AS ENUM ('students','teachers');
+  END IF;
+END$$;
+
+-- ---------------------------------------------------------------------
+-- TABLE: import_jobs
+-- ---------------------------------------------------------------------
+CREATE TABLE IF NOT EXISTS import_jobs (
+  import_job_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+
+  import_job_school_id UUID NOT NULL
+    REFERENCES schools(school_id) ON DELETE CASCADE,
+
+  import_job_kind   import_job_kind_enum   NOT NULL,
+  import_job_status import_job_status_enum NOT NULL DEFAULT 'queued',
+  import_job_dry_run BOOLEAN NOT NULL DEFAULT TRUE,
+
+  -- sumber
+  import_job_file_name     VARCHAR(255),
+  import_job_source_format VARCHAR(10) NOT NULL
+    CHECK (import_job_source_format IN ('csv','xlsx')),
+  import_job_layout        VARCHAR(20) NOT NULL DEFAULT 'generic'
+    CHECK (import_job_layout IN ('generic','dapodik')),
+
+  -- opsi (mis. default_class_section_id)
+  import_job_options JSONB NOT NULL DEFAULT '{}'::jsonb,
+
+  -- baris ter-normalisasi (hasil parse file)
+  import_job_rows JSONB NOT NULL DEFAULT '[]'::jsonb,
+
+  -- progres
+  import_job_total_rows     INT NOT NULL DEFAULT 0,
+  import_job_processed_rows INT NOT NULL DEFAULT 0,
+  import_job_created_count  INT NOT NULL DEFAULT 0,
+  import_job_skipped_count  INT NOT NULL DEFAULT 0,
+  import_job_error_count    INT NOT NULL DEFAULT 0,
+
+  -- error per baris: [{row, field, message}, ...]
+  import_job_errors JSONB NOT NULL DEFAULT '[]'::jsonb,
+  import_job_failure_reason TEXT,
+
+  import_job_created_by_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+
+  import_job_started_at  TIMESTAMPTZ,
+  import_job_finished_at TIMESTAMPTZ,
+
+  import_job_created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  import_job_updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  import_job_deleted_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_import_jobs_school_created_alive
+  ON import_jobs (import_job_school_id, import_job_created_at DESC)
+  WHERE import_job_deleted_at IS NULL;
+
+-- worker: ambil job antri paling lama
+CREATE INDEX IF NOT EXISTS idx_import_jobs_status_created_alive
+  ON import_jobs (import_job_status, import_job_created_at)
+  WHERE import_job_deleted_at IS NULL;
+
+COMMIT;
@@ -0,0 +1,18 @@
+-- +migrate Down
+BEGIN;
+
+DROP TABLE IF EXISTS audit_settings;
+
+DROP TRIGGER IF EXISTS trg_audit_logs_no_truncate ON audit_logs;
+DROP TRIGGER IF EXISTS trg_audit_logs_append_only ON audit_logs;
+DROP FUNCTION IF EXISTS fn_audit_logs_append_only();
+
+DROP INDEX IF EXISTS brin_audit_logs_created;
+DROP INDEX IF EXISTS idx_audit_logs_request;
+DROP INDEX IF EXISTS idx_audit_logs_actor_created;
+DROP INDEX IF EXISTS idx_audit_logs_school_created;
+DROP INDEX IF EXISTS idx_audit_logs_entity;
+
+DROP TABLE IF EXISTS audit_logs;
+
+COMMIT;
@@ -0,0 +1,102 @@
+-- +migrate Up
+/* =====================================================================
+   AUDIT TRAIL (append-only)
+   - audit_logs     → 1 baris per entitas yang berubah (create/update/delete)
+                      diisi callback GORM; aktor dari middleware (Locals)
+   - audit_settings → retensi per sekolah (default global dari ENV)
+   Sengaja TANPA FK: log harus tetap ada walau entitas/sekolah dihapus.
+   ===================================================================== */
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS audit_logs (
+  audit_log_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+
+  -- tenant: dari kolom *_school_id baris terkait, fallback sekolah aktif aktor
+  audit_log_school_id UUID,
+
+  -- aktor & request
+  audit_log_actor_user_id UUID,
+  audit_log_actor_role    VARCHAR(32),
+  audit_log_request_id    VARCHAR(64),
+  audit_log_ip            VARCHAR(64),
+  audit_log_user_agent    TEXT,
+  audit_log_method        VARCHAR(10),
+  audit_log_path          TEXT,
+
+  -- entitas
+  audit_log_table     VARCHAR(80) NOT NULL,
+  audit_log_entity_id TEXT        NOT NULL, -- PK (komposit → "a,b")
+  audit_log_action    VARCHAR(10) NOT NULL
+    CHECK (audit_log_action IN ('create','update','delete')),
+
+  -- diff: update → hanya kolom yang berubah; create/delete → baris penuh
+  audit_log_before          JSONB,
+  audit_log_after           JSONB,
+  audit_log_changed_columns TEXT[] NOT NULL DEFAULT '{}',
+
+  audit_log_created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- riwayat 1 entitas
+CREATE INDEX IF NOT EXISTS idx_audit_logs_entity
+  ON audit_logs (audit_log_table, audit_log_entity_id, audit_log_created_at DESC);
+
+-- feed per sekolah
+CREATE INDEX IF NOT EXISTS idx_audit_logs_school_created
+  ON audit_logs (audit_log_school_id, audit_log_created_at DESC);
+
+-- jejak per aktor
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor_created
+  ON audit_logs (audit_log_actor_user_id, audit_log_created_at DESC)
+  WHERE audit_log_actor_user_id IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_request
+  ON audit_logs (audit_log_request_id)
+  WHERE audit_log_request_id IS NOT NULL;
+
+-- purge retensi (scan by waktu)
+CREATE INDEX IF NOT EXISTS brin_audit_logs_created
+  ON audit_logs USING BRIN (audit_log_created_at);
+
+-- ---------------------------------------------------------------------
+-- Append-only: UPDATE/DELETE/TRUNCATE ditolak.
+-- Pengecualian: purge retensi (SET LOCAL audit.allow_purge = 'on').
+-- ---------------------------------------------------------------------
+CREATE OR REPLACE FUNCTION fn_audit_logs_append_only()
+RETURNS trigger
+LANGUAGE plpgsql AS $$
+BEGIN
+  IF TG_OP = 'DELETE' AND current_setting('audit.allow_purge', true) = 'on' THEN
+    RETURN OLD;
+  END IF;
+  RAISE EXCEPTION 'audit_logs bersifat append-only (% ditolak)', TG_OP;
+END$$;
+
+DROP TRIGGER IF EXISTS trg_audit_logs_append_only ON audit_logs;
+CREATE TRIGGER trg_audit_logs_append_only
+  BEFORE UPDATE OR DELETE ON audit_logs
+  FOR EACH ROW EXECUTE FUNCTION fn_audit_logs_append_only();
+
+DROP TRIGGER IF EXISTS trg_audit_logs_no_truncate ON audit_logs;
+CREATE TRIGGER trg_audit_logs_no_truncate
+  BEFORE TRUNCATE ON audit_logs
+  FOR EACH STATEMENT EXECUTE FUNCTION fn_audit_logs_append_only();
+
+-- ---------------------------------------------------------------------
+-- TABLE: audit_settings (retensi per sekolah)
+-- ---------------------------------------------------------------------
+CREATE TABLE IF NOT EXISTS audit_settings (
+  audit_setting_school_id UUID PRIMARY KEY
+    REFERENCES schools(school_id) ON DELETE CASCADE,
+
+  audit_setting_retention_days INT NOT NULL
+    CHECK (audit_setting_retention_days BETWEEN 30 AND 3650),
+
+  audit_setting_updated_by_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+
+  audit_setting_created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  audit_setting_updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+COMMIT;
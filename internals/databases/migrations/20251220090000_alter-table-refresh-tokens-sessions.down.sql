@@ -0,0 +1,13 @@
+-- +migrate Down
+BEGIN;
+
+DROP INDEX IF EXISTS idx_rt_user_last_used_active;
+
+ALTER TABLE refresh_tokens
+  DROP COLUMN IF EXISTS revoke_reason,
+  DROP COLUMN IF EXISTS revoked_by,
+  DROP COLUMN IF EXISTS access_expires_at,
+  DROP COLUMN IF EXISTS access_token_hash,
+  DROP COLUMN IF EXISTS last_used_at;
+
+COMMIT;
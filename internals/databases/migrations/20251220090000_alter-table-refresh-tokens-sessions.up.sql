@@ -0,0 +1,23 @@
+-- +migrate Up
+/* =====================================================================
+   SESI / PERANGKAT (refresh_tokens = 1 sesi login)
+   - id sesi stabil: rotasi refresh meng-update baris yang sama
+   - access_token_hash → HMAC access token terakhir sesi ini (format sama
+     dengan token_blacklist.token) agar revoke langsung mem-blacklist access
+   ===================================================================== */
+
+BEGIN;
+
+ALTER TABLE refresh_tokens
+  ADD COLUMN IF NOT EXISTS last_used_at      TIMESTAMPTZ,
+  ADD COLUMN IF NOT EXISTS access_token_hash TEXT,
+  ADD COLUMN IF NOT EXISTS access_expires_at TIMESTAMPTZ,
+  ADD COLUMN IF NOT EXISTS revoked_by        UUID REFERENCES users(id) ON DELETE SET NULL,
+  ADD COLUMN IF NOT EXISTS revoke_reason     VARCHAR(32);
+
+-- listing sesi aktif per user: urut pemakaian terakhir
+CREATE INDEX IF NOT EXISTS idx_rt_user_last_used_active
+  ON refresh_tokens (user_id, last_used_at DESC NULLS LAST)
+  WHERE revoked_at IS NULL;
+
+COMMIT;
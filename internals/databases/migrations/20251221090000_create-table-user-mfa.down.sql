@@ -0,0 +1,8 @@
+-- +migrate Down
+BEGIN;
+
+DROP TABLE IF EXISTS school_mfa_policies;
+DROP TABLE IF EXISTS user_recovery_codes;
+DROP TABLE IF EXISTS user_totp;
+
+COMMIT;
@@ -0,0 +1,48 @@
+-- +migrate Up
+/* =====================================================================
+   TWO-FACTOR (TOTP, RFC 6238)
+   - user_totp            : 1 baris per user; secret terenkripsi (AES-GCM di app)
+                            enabled_at NULL = enrollment belum dikonfirmasi
+   - user_recovery_codes  : kode cadangan sekali pakai (hash SHA-256)
+   - school_mfa_policies  : role yang WAJIB 2FA per sekolah
+   ===================================================================== */
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS user_totp (
+  user_id          UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+  secret_enc       TEXT        NOT NULL,
+  enabled_at       TIMESTAMPTZ,
+  last_used_step   BIGINT      NOT NULL DEFAULT 0,   -- anti replay kode yang sama
+  failed_attempts  INT         NOT NULL DEFAULT 0,
+  locked_until     TIMESTAMPTZ,
+  created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS user_recovery_codes (
+  id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id     UUID        NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  code_hash   CHAR(64)    NOT NULL,
+  used_at     TIMESTAMPTZ,
+  created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_user_recovery_codes_hash
+  ON user_recovery_codes (user_id, code_hash);
+
+CREATE INDEX IF NOT EXISTS idx_user_recovery_codes_unused
+  ON user_recovery_codes (user_id) WHERE used_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS school_mfa_policies (
+  school_id       UUID PRIMARY KEY REFERENCES schools(school_id) ON DELETE CASCADE,
+  required_roles  TEXT[]      NOT NULL DEFAULT '{}',
+  updated_by      UUID REFERENCES users(id) ON DELETE SET NULL,
+  created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  CONSTRAINT ck_school_mfa_roles CHECK (
+    required_roles <@ ARRAY['owner','teacher','treasurer','admin','dkm','author']::text[]
+  )
+);
+
+COMMIT;
@@ -0,0 +1,8 @@
+-- +migrate Down
+BEGIN;
+
+DROP TABLE IF EXISTS school_custom_role_members;
+DROP TABLE IF EXISTS school_custom_roles;
+DROP TABLE IF EXISTS school_role_permission_overrides;
+
+COMMIT;
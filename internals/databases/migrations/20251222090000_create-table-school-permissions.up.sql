@@ -0,0 +1,57 @@
+-- +migrate Up
+/* =====================================================================
+   PERMISSION MATRIX (resource.action)
+   - registry & default role→permission ada di kode (lembaga/permissions)
+   - school_role_permission_overrides : allow/deny per role bawaan per sekolah
+   - school_custom_roles               : role buatan sekolah (mis. wakasek kurikulum)
+   - school_custom_role_members        : penugasan custom role ke user
+   ===================================================================== */
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS school_role_permission_overrides (
+  school_role_permission_school_id   UUID        NOT NULL REFERENCES schools(school_id) ON DELETE CASCADE,
+  school_role_permission_role        VARCHAR(32) NOT NULL,
+  school_role_permission_permission  VARCHAR(64) NOT NULL,
+  school_role_permission_effect      VARCHAR(8)  NOT NULL,
+  school_role_permission_updated_by  UUID REFERENCES users(id) ON DELETE SET NULL,
+  school_role_permission_created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+  PRIMARY KEY (school_role_permission_school_id, school_role_permission_role, school_role_permission_permission),
+  CONSTRAINT ck_srp_effect CHECK (school_role_permission_effect IN ('allow','deny')),
+  CONSTRAINT ck_srp_role CHECK (school_role_permission_role IN ('teacher','treasurer','author','student','user'))
+);
+
+CREATE TABLE IF NOT EXISTS school_custom_roles (
+  school_custom_role_id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  school_custom_role_school_id    UUID         NOT NULL REFERENCES schools(school_id) ON DELETE CASCADE,
+  school_custom_role_key          VARCHAR(48)  NOT NULL,
+  school_custom_role_name         VARCHAR(120) NOT NULL,
+  school_custom_role_description  TEXT,
+  school_custom_role_permissions  TEXT[]       NOT NULL DEFAULT '{}',
+  school_custom_role_created_at   TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+  school_custom_role_updated_at   TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+  school_custom_role_deleted_at   TIMESTAMPTZ,
+
+  CONSTRAINT ck_scr_key CHECK (school_custom_role_key ~ '^[a-z][a-z0-9_]{1,47}$')
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_school_custom_roles_key_alive
+  ON school_custom_roles (school_custom_role_school_id, school_custom_role_key)
+  WHERE school_custom_role_deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS school_custom_role_members (
+  school_custom_role_member_role_id      UUID        NOT NULL REFERENCES school_custom_roles(school_custom_role_id) ON DELETE CASCADE,
+  school_custom_role_member_user_id      UUID        NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  school_custom_role_member_school_id    UUID        NOT NULL REFERENCES schools(school_id) ON DELETE CASCADE,
+  school_custom_role_member_assigned_by  UUID REFERENCES users(id) ON DELETE SET NULL,
+  school_custom_role_member_created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+  PRIMARY KEY (school_custom_role_member_role_id, school_custom_role_member_user_id)
+);
+
+-- lookup per request: permission custom role milik user di sekolah aktif
+CREATE INDEX IF NOT EXISTS idx_scrm_user_school
+  ON school_custom_role_members (school_custom_role_member_user_id, school_custom_role_member_school_id);
+
+COMMIT;
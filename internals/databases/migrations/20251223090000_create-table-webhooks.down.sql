@@ -0,0 +1,8 @@
+-- +migrate Down
+BEGIN;
+
+DROP TABLE IF EXISTS webhook_delivery_attempts;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_subscriptions;
+
+COMMIT;
@@ -0,0 +1,80 @@
+-- +migrate Up
+/* =====================================================================
+   OUTBOUND WEBHOOK (integrasi sekolah: bot WA, akuntansi, dll)
+   - webhook_subscriptions       : endpoint per sekolah + filter event
+   - webhook_deliveries          : antrean durable (1 baris = 1 event × 1 endpoint)
+   - webhook_delivery_attempts   : log tiap percobaan kirim
+   ===================================================================== */
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+  webhook_subscription_id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  webhook_subscription_school_id   UUID         NOT NULL REFERENCES schools(school_id) ON DELETE CASCADE,
+  webhook_subscription_name        VARCHAR(120) NOT NULL,
+  webhook_subscription_url         TEXT         NOT NULL,
+  webhook_subscription_secret      VARCHAR(128) NOT NULL,
+  -- kosong = semua event
+  webhook_subscription_events      TEXT[]       NOT NULL DEFAULT '{}',
+  webhook_subscription_is_active   BOOLEAN      NOT NULL DEFAULT TRUE,
+  webhook_subscription_created_by  UUID REFERENCES users(id) ON DELETE SET NULL,
+  webhook_subscription_created_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+  webhook_subscription_updated_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+  webhook_subscription_deleted_at  TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_school_active
+  ON webhook_subscriptions (webhook_subscription_school_id)
+  WHERE webhook_subscription_deleted_at IS NULL AND webhook_subscription_is_active;
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+  webhook_delivery_id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  webhook_delivery_school_id        UUID         NOT NULL REFERENCES schools(school_id) ON DELETE CASCADE,
+  webhook_delivery_subscription_id  UUID         NOT NULL REFERENCES webhook_subscriptions(webhook_subscription_id) ON DELETE CASCADE,
+  webhook_delivery_event_id         UUID         NOT NULL,
+  webhook_delivery_event            VARCHAR(64)  NOT NULL,
+  -- kunci idempoten sumber (mis. payment:<id>:paid) → event yang sama tidak diantre 2x
+  webhook_delivery_dedupe_key       VARCHAR(160),
+  webhook_delivery_redelivery_of    UUID REFERENCES webhook_deliveries(webhook_delivery_id) ON DELETE SET NULL,
+  webhook_delivery_payload          JSONB        NOT NULL,
+  webhook_delivery_status           VARCHAR(16)  NOT NULL DEFAULT 'pending',
+  webhook_delivery_attempts         INT          NOT NULL DEFAULT 0,
+  webhook_delivery_max_attempts     INT          NOT NULL DEFAULT 8,
+  webhook_delivery_next_attempt_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+  webhook_delivery_last_status_code INT,
+  webhook_delivery_last_error       TEXT,
+  webhook_delivery_delivered_at     TIMESTAMPTZ,
+  webhook_delivery_created_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+  webhook_delivery_updated_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+
+  CONSTRAINT ck_webhook_delivery_status
+    CHECK (webhook_delivery_status IN ('pending','delivering','succeeded','dead'))
+);
+
+-- worker: ambil yang jatuh tempo
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due
+  ON webhook_deliveries (webhook_delivery_next_attempt_at)
+  WHERE webhook_delivery_status = 'pending';
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_school_created
+  ON webhook_deliveries (webhook_delivery_school_id, webhook_delivery_created_at DESC);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_webhook_deliveries_dedupe
+  ON webhook_deliveries (webhook_delivery_subscription_id, webhook_delivery_event, webhook_delivery_dedupe_key)
+  WHERE webhook_delivery_dedupe_key IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS webhook_delivery_attempts (
+  webhook_delivery_attempt_id            UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  webhook_delivery_attempt_delivery_id   UUID        NOT NULL REFERENCES webhook_deliveries(webhook_delivery_id) ON DELETE CASCADE,
+  webhook_delivery_attempt_no            INT         NOT NULL,
+  webhook_delivery_attempt_status_code   INT,
+  webhook_delivery_attempt_response_body TEXT,
+  webhook_delivery_attempt_error         TEXT,
+  webhook_delivery_attempt_duration_ms   INT         NOT NULL DEFAULT 0,
+  webhook_delivery_attempt_created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_attempts_delivery
+  ON webhook_delivery_attempts (webhook_delivery_attempt_delivery_id, webhook_delivery_attempt_no);
+
+COMMIT;
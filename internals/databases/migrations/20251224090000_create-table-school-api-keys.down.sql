@@ -0,0 +1,7 @@
+-- +migrate Down
+BEGIN;
+
+DROP TABLE IF EXISTS school_api_key_usage_daily;
+DROP TABLE IF EXISTS school_api_keys;
+
+COMMIT;
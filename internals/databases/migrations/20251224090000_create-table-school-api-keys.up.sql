@@ -0,0 +1,48 @@
+-- +migrate Up
+/* =====================================================================
+   API KEY SEKOLAH (akses mesin-ke-mesin: mesin absen, ekspor akuntansi)
+   - school_api_keys             : key per sekolah (hanya hash yang disimpan)
+   - school_api_key_usage_daily  : hitungan request per key per hari
+   ===================================================================== */
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS school_api_keys (
+  school_api_key_id            UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  school_api_key_school_id     UUID         NOT NULL REFERENCES schools(school_id) ON DELETE CASCADE,
+  school_api_key_name          VARCHAR(120) NOT NULL,
+  -- bagian depan key (msk_xxxxxxxx) → lookup + ditampilkan di UI
+  school_api_key_prefix        VARCHAR(16)  NOT NULL,
+  -- sha256 hex dari key utuh
+  school_api_key_hash          CHAR(64)     NOT NULL,
+  -- pola permission (payments.read, students.*), hanya resource grantable
+  school_api_key_scopes        TEXT[]       NOT NULL DEFAULT '{}',
+  -- IP / CIDR; kosong = semua IP
+  school_api_key_allowed_ips   TEXT[]       NOT NULL DEFAULT '{}',
+  school_api_key_expires_at    TIMESTAMPTZ  NOT NULL,
+
+  school_api_key_last_used_at  TIMESTAMPTZ,
+  school_api_key_last_used_ip  VARCHAR(64),
+  school_api_key_usage_count   BIGINT       NOT NULL DEFAULT 0,
+
+  school_api_key_created_by    UUID REFERENCES users(id) ON DELETE SET NULL,
+  school_api_key_revoked_at    TIMESTAMPTZ,
+  school_api_key_revoked_by    UUID REFERENCES users(id) ON DELETE SET NULL,
+  school_api_key_created_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+  school_api_key_updated_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_school_api_keys_prefix
+  ON school_api_keys (school_api_key_prefix);
+
+CREATE INDEX IF NOT EXISTS idx_school_api_keys_school
+  ON school_api_keys (school_api_key_school_id, school_api_key_created_at DESC);
+
+CREATE TABLE IF NOT EXISTS school_api_key_usage_daily (
+  school_api_key_usage_key_id  UUID   NOT NULL REFERENCES school_api_keys(school_api_key_id) ON DELETE CASCADE,
+  school_api_key_usage_date    DATE   NOT NULL,
+  school_api_key_usage_count   BIGINT NOT NULL DEFAULT 0,
+  PRIMARY KEY (school_api_key_usage_key_id, school_api_key_usage_date)
+);
+
+COMMIT;
@@ -0,0 +1,20 @@
+-- +migrate Down
+BEGIN;
+
+UPDATE class_attendance_session_participants
+   SET class_attendance_session_participant_method = 'api'
+ WHERE class_attendance_session_participant_method = 'device';
+
+ALTER TABLE class_attendance_session_participants
+  DROP CONSTRAINT IF EXISTS chk_cas_participant_method;
+ALTER TABLE class_attendance_session_participants
+  ADD CONSTRAINT chk_cas_participant_method CHECK (
+    class_attendance_session_participant_method IS NULL
+    OR class_attendance_session_participant_method IN ('manual','qr','geo','import','api','self')
+  );
+
+DROP TABLE IF EXISTS attendance_device_punches;
+DROP TABLE IF EXISTS attendance_device_users;
+DROP TABLE IF EXISTS attendance_devices;
+
+COMMIT;
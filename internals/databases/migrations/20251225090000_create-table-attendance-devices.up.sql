@@ -0,0 +1,117 @@
+-- +migrate Up
+/* =====================================================================
+   MESIN ABSEN (fingerprint / RFID / face, protokol ZKTeco ADMS atau API)
+   - attendance_devices          : mesin terdaftar per sekolah (dikenali dari SN)
+   - attendance_device_users     : PIN di mesin → school_students / school_teachers
+   - attendance_device_punches   : log tap mentah (dedupe per mesin+PIN+waktu)
+   - method participant 'device' : absensi yang diisi dari mesin
+   ===================================================================== */
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS attendance_devices (
+  attendance_device_id             UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  attendance_device_school_id      UUID         NOT NULL REFERENCES schools(school_id) ON DELETE CASCADE,
+  attendance_device_name           VARCHAR(120) NOT NULL,
+  -- serial number mesin (query ?SN= pada ADMS)
+  attendance_device_serial_number  VARCHAR(64)  NOT NULL,
+  attendance_device_kind           VARCHAR(16)  NOT NULL DEFAULT 'fingerprint'
+    CHECK (attendance_device_kind IN ('fingerprint','rfid','face','other')),
+  attendance_device_location       VARCHAR(160),
+  attendance_device_is_active      BOOLEAN      NOT NULL DEFAULT TRUE,
+
+  -- ADMS: stamp ATTLOG terakhir yang diterima (0 = minta kirim ulang semua log)
+  attendance_device_attlog_stamp   BIGINT       NOT NULL DEFAULT 0,
+  attendance_device_last_seen_at   TIMESTAMPTZ,
+  attendance_device_last_ip        VARCHAR(64),
+  attendance_device_firmware       VARCHAR(80),
+
+  attendance_device_created_at     TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+  attendance_device_updated_at     TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+  attendance_device_deleted_at     TIMESTAMPTZ
+);
+
+-- SN unik global (ADMS tidak membawa school_id)
+CREATE UNIQUE INDEX IF NOT EXISTS uq_attendance_devices_sn_alive
+  ON attendance_devices (upper(attendance_device_serial_number))
+  WHERE attendance_device_deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_attendance_devices_school
+  ON attendance_devices (attendance_device_school_id)
+  WHERE attendance_device_deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS attendance_device_users (
+  attendance_device_user_id                 UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  attendance_device_user_school_id          UUID        NOT NULL REFERENCES schools(school_id) ON DELETE CASCADE,
+  -- PIN/ID user di mesin (berlaku untuk semua mesin sekolah)
+  attendance_device_user_pin                VARCHAR(32) NOT NULL,
+  attendance_device_user_card_number        VARCHAR(64),
+  attendance_device_user_school_student_id  UUID REFERENCES school_students(school_student_id) ON DELETE CASCADE,
+  attendance_device_user_school_teacher_id  UUID REFERENCES school_teachers(school_teacher_id) ON DELETE CASCADE,
+  attendance_device_user_created_at         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  attendance_device_user_updated_at         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  attendance_device_user_deleted_at         TIMESTAMPTZ,
+
+  CONSTRAINT chk_attendance_device_user_target CHECK (
+    (attendance_device_user_school_student_id IS NOT NULL)::int
+    + (attendance_device_user_school_teacher_id IS NOT NULL)::int = 1
+  )
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_attendance_device_users_pin_alive
+  ON attendance_device_users (attendance_device_user_school_id, attendance_device_user_pin)
+  WHERE attendance_device_user_deleted_at IS NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_attendance_device_users_student_alive
+  ON attendance_device_users (attendance_device_user_school_id, attendance_device_user_school_student_id)
+  WHERE attendance_device_user_deleted_at IS NULL AND attendance_device_user_school_student_id IS NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_attendance_device_users_teacher_alive
+  ON attendance_device_users (attendance_device_user_school_id, attendance_device_user_school_teacher_id)
+  WHERE attendance_device_user_deleted_at IS NULL AND attendance_device_user_school_teacher_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS attendance_device_punches (
+  attendance_device_punch_id                UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  attendance_device_punch_school_id         UUID        NOT NULL REFERENCES schools(school_id) ON DELETE CASCADE,
+  attendance_device_punch_device_id         UUID        NOT NULL REFERENCES attendance_devices(attendance_device_id) ON DELETE CASCADE,
+  attendance_device_punch_pin               VARCHAR(32) NOT NULL,
+  attendance_device_punch_punched_at        TIMESTAMPTZ NOT NULL,
+  -- kode mesin: state 0=in 1=out 2/3=break 4/5=lembur; verify 1=finger 4=kartu 15=wajah
+  attendance_device_punch_state             SMALLINT,
+  attendance_device_punch_verify            SMALLINT,
+  attendance_device_punch_source            VARCHAR(8)  NOT NULL DEFAULT 'adms'
+    CHECK (attendance_device_punch_source IN ('adms','api')),
+
+  -- hasil proses
+  attendance_device_punch_status            VARCHAR(16) NOT NULL DEFAULT 'pending'
+    CHECK (attendance_device_punch_status IN ('pending','applied','unmapped','no_session','skipped','failed')),
+  attendance_device_punch_person_kind       VARCHAR(16),
+  attendance_device_punch_school_student_id UUID,
+  attendance_device_punch_school_teacher_id UUID,
+  attendance_device_punch_applied_count     INT         NOT NULL DEFAULT 0,
+  attendance_device_punch_error             TEXT,
+  attendance_device_punch_processed_at      TIMESTAMPTZ,
+  attendance_device_punch_created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- dedupe: kirim ulang / backfill log lama tidak menggandakan tap
+CREATE UNIQUE INDEX IF NOT EXISTS uq_attendance_device_punches_dedupe
+  ON attendance_device_punches (attendance_device_punch_device_id, attendance_device_punch_pin, attendance_device_punch_punched_at);
+
+CREATE INDEX IF NOT EXISTS idx_attendance_device_punches_pending
+  ON attendance_device_punches (attendance_device_punch_punched_at)
+  WHERE attendance_device_punch_status = 'pending';
+
+CREATE INDEX IF NOT EXISTS idx_attendance_device_punches_school_time
+  ON attendance_device_punches (attendance_device_punch_school_id, attendance_device_punch_punched_at DESC);
+
+-- participant: tambah method 'device'
+ALTER TABLE class_attendance_session_participants
+  DROP CONSTRAINT IF EXISTS chk_cas_participant_method;
+ALTER TABLE class_attendance_session_participants
+  ADD CONSTRAINT chk_cas_participant_method CHECK (
+    class_attendance_session_participant_method IS NULL
+    OR class_attendance_session_participant_method IN ('manual','qr','geo','import','api','self','device')
+  );
+
+COMMIT;
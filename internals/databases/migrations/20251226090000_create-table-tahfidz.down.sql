@@ -0,0 +1,7 @@
+-- +migrate Down
+BEGIN;
+
+DROP TABLE IF EXISTS tahfidz_targets;
+DROP TABLE IF EXISTS tahfidz_records;
+
+COMMIT;
@@ -0,0 +1,101 @@
+-- +migrate Up
+/* =====================================================================
+   TAHFIDZ (setoran hafalan Al-Qur'an terstruktur)
+   - tahfidz_records : 1 baris = 1 setoran (ziyadah / murojaah) per siswa
+                       rentang ayat disimpan sebagai posisi surah:ayat + indeks
+                       global 0..6235 (validasi & coverage dihitung di aplikasi)
+   - tahfidz_targets : target per semester (sekolah / rombel / siswa)
+   ===================================================================== */
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS tahfidz_records (
+  tahfidz_record_id                 UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  tahfidz_record_school_id          UUID        NOT NULL REFERENCES schools(school_id) ON DELETE CASCADE,
+  tahfidz_record_school_student_id  UUID        NOT NULL REFERENCES school_students(school_student_id) ON DELETE CASCADE,
+  -- penyimak
+  tahfidz_record_school_teacher_id  UUID        REFERENCES school_teachers(school_teacher_id) ON DELETE SET NULL,
+  -- konteks sesi kehadiran (opsional; halaqah di luar jadwal boleh tanpa sesi)
+  tahfidz_record_session_id         UUID        REFERENCES class_attendance_sessions(class_attendance_session_id) ON DELETE SET NULL,
+  tahfidz_record_participant_id     UUID        REFERENCES class_attendance_session_participants(class_attendance_session_participant_id) ON DELETE SET NULL,
+  tahfidz_record_term_id            UUID        REFERENCES academic_terms(academic_term_id) ON DELETE SET NULL,
+
+  tahfidz_record_type               VARCHAR(10) NOT NULL
+    CHECK (tahfidz_record_type IN ('ziyadah','murojaah')),
+  tahfidz_record_scope              VARCHAR(8)  NOT NULL DEFAULT 'surah'
+    CHECK (tahfidz_record_scope IN ('surah','juz')),
+  tahfidz_record_juz                SMALLINT    CHECK (tahfidz_record_juz BETWEEN 1 AND 30),
+
+  tahfidz_record_surah_from         SMALLINT    NOT NULL CHECK (tahfidz_record_surah_from BETWEEN 1 AND 114),
+  tahfidz_record_ayah_from          SMALLINT    NOT NULL CHECK (tahfidz_record_ayah_from >= 1),
+  tahfidz_record_surah_to           SMALLINT    NOT NULL CHECK (tahfidz_record_surah_to BETWEEN 1 AND 114),
+  tahfidz_record_ayah_to            SMALLINT    NOT NULL CHECK (tahfidz_record_ayah_to >= 1),
+  tahfidz_record_index_from         SMALLINT    NOT NULL,
+  tahfidz_record_index_to           SMALLINT    NOT NULL,
+  tahfidz_record_ayah_count         INT         NOT NULL,
+  tahfidz_record_pages              NUMERIC(6,2) NOT NULL,
+
+  -- penilaian 0..100 (null = tidak dinilai)
+  tahfidz_record_score_tajwid       NUMERIC(5,2) CHECK (tahfidz_record_score_tajwid BETWEEN 0 AND 100),
+  tahfidz_record_score_fluency      NUMERIC(5,2) CHECK (tahfidz_record_score_fluency BETWEEN 0 AND 100),
+  tahfidz_record_score_makhraj      NUMERIC(5,2) CHECK (tahfidz_record_score_makhraj BETWEEN 0 AND 100),
+  tahfidz_record_score_avg          NUMERIC(5,2),
+  -- false = harus mengulang; tidak dihitung sebagai hafalan
+  tahfidz_record_is_passed          BOOLEAN     NOT NULL DEFAULT TRUE,
+
+  tahfidz_record_recorded_on        DATE        NOT NULL,
+  tahfidz_record_note               TEXT,
+  tahfidz_record_created_by         UUID,
+  tahfidz_record_created_at         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  tahfidz_record_updated_at         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  tahfidz_record_deleted_at         TIMESTAMPTZ,
+
+  CONSTRAINT chk_tahfidz_record_range CHECK (tahfidz_record_index_from <= tahfidz_record_index_to),
+  CONSTRAINT chk_tahfidz_record_juz_scope CHECK (
+    (tahfidz_record_scope = 'juz') = (tahfidz_record_juz IS NOT NULL)
+  )
+);
+
+CREATE INDEX IF NOT EXISTS idx_tahfidz_records_student_date
+  ON tahfidz_records (tahfidz_record_school_id, tahfidz_record_school_student_id, tahfidz_record_recorded_on DESC)
+  WHERE tahfidz_record_deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_tahfidz_records_session
+  ON tahfidz_records (tahfidz_record_session_id)
+  WHERE tahfidz_record_deleted_at IS NULL AND tahfidz_record_session_id IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_tahfidz_records_term
+  ON tahfidz_records (tahfidz_record_school_id, tahfidz_record_term_id)
+  WHERE tahfidz_record_deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS tahfidz_targets (
+  tahfidz_target_id                 UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  tahfidz_target_school_id          UUID         NOT NULL REFERENCES schools(school_id) ON DELETE CASCADE,
+  tahfidz_target_term_id            UUID         NOT NULL REFERENCES academic_terms(academic_term_id) ON DELETE CASCADE,
+  -- keduanya null = default sekolah; prioritas siswa > rombel > sekolah
+  tahfidz_target_class_section_id   UUID         REFERENCES class_sections(class_section_id) ON DELETE CASCADE,
+  tahfidz_target_school_student_id  UUID         REFERENCES school_students(school_student_id) ON DELETE CASCADE,
+  -- hafalan baru (ziyadah) dalam semester
+  tahfidz_target_pages              NUMERIC(6,2) NOT NULL CHECK (tahfidz_target_pages > 0),
+  -- total hafalan kumulatif di akhir semester (opsional)
+  tahfidz_target_juz_total          NUMERIC(4,1) CHECK (tahfidz_target_juz_total BETWEEN 0 AND 30),
+  tahfidz_target_note               TEXT,
+  tahfidz_target_created_at         TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+  tahfidz_target_updated_at         TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+  tahfidz_target_deleted_at         TIMESTAMPTZ,
+
+  CONSTRAINT chk_tahfidz_target_subject CHECK (
+    tahfidz_target_class_section_id IS NULL OR tahfidz_target_school_student_id IS NULL
+  )
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_tahfidz_targets_alive
+  ON tahfidz_targets (
+    tahfidz_target_school_id,
+    tahfidz_target_term_id,
+    COALESCE(tahfidz_target_class_section_id, '00000000-0000-0000-0000-000000000000'::uuid),
+    COALESCE(tahfidz_target_school_student_id, '00000000-0000-0000-0000-000000000000'::uuid)
+  )
+  WHERE tahfidz_target_deleted_at IS NULL;
+
+COMMIT;
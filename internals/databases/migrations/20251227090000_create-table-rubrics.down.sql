@@ -0,0 +1,13 @@
+-- +migrate Down
+BEGIN;
+
+DROP TABLE IF EXISTS submission_rubric_scores;
+
+ALTER TABLE assessments
+  DROP COLUMN IF EXISTS assessment_rubric_id;
+
+DROP TABLE IF EXISTS rubric_levels;
+DROP TABLE IF EXISTS rubric_criteria;
+DROP TABLE IF EXISTS rubrics;
+
+COMMIT;
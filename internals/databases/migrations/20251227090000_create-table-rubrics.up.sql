@@ -0,0 +1,94 @@
+-- +migrate Up
+/* =====================================================================
+   RUBRIK PENILAIAN (tugas assignment_upload)
+   - rubrics                  : rubrik reusable per sekolah / per mapel
+   - rubric_criteria          : kriteria (urut) dalam rubrik
+   - rubric_levels            : tingkat capaian per kriteria + poin
+   - assessments.assessment_rubric_id : rubrik yang dipakai tugas
+   - submission_rubric_scores : nilai per kriteria per submission
+                                (nama kriteria/level & poin maks di-snapshot
+                                 agar nilai lama tidak berubah saat rubrik diedit)
+   ===================================================================== */
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS rubrics (
+  rubric_id                     UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  rubric_school_id              UUID         NOT NULL REFERENCES schools(school_id) ON DELETE CASCADE,
+  -- null = rubrik umum sekolah
+  rubric_class_subject_id       UUID         REFERENCES class_subjects(class_subject_id) ON DELETE SET NULL,
+  rubric_name                   VARCHAR(160) NOT NULL,
+  rubric_description            TEXT,
+  -- jumlah poin maksimal seluruh kriteria (dihitung aplikasi)
+  rubric_max_points             NUMERIC(7,2) NOT NULL DEFAULT 0,
+  rubric_is_active              BOOLEAN      NOT NULL DEFAULT TRUE,
+  rubric_created_by_teacher_id  UUID         REFERENCES school_teachers(school_teacher_id) ON DELETE SET NULL,
+  rubric_created_at             TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+  rubric_updated_at             TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+  rubric_deleted_at             TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_rubrics_school_subject
+  ON rubrics (rubric_school_id, rubric_class_subject_id)
+  WHERE rubric_deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS rubric_criteria (
+  rubric_criterion_id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  rubric_criterion_school_id    UUID         NOT NULL REFERENCES schools(school_id) ON DELETE CASCADE,
+  rubric_criterion_rubric_id    UUID         NOT NULL REFERENCES rubrics(rubric_id) ON DELETE CASCADE,
+  rubric_criterion_name         VARCHAR(160) NOT NULL,
+  rubric_criterion_description  TEXT,
+  rubric_criterion_order        INT          NOT NULL DEFAULT 0,
+  -- poin level tertinggi (dihitung aplikasi)
+  rubric_criterion_max_points   NUMERIC(6,2) NOT NULL DEFAULT 0,
+  rubric_criterion_created_at   TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+  rubric_criterion_updated_at   TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_rubric_criteria_rubric
+  ON rubric_criteria (rubric_criterion_rubric_id, rubric_criterion_order);
+
+CREATE TABLE IF NOT EXISTS rubric_levels (
+  rubric_level_id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  rubric_level_school_id        UUID         NOT NULL REFERENCES schools(school_id) ON DELETE CASCADE,
+  rubric_level_criterion_id     UUID         NOT NULL REFERENCES rubric_criteria(rubric_criterion_id) ON DELETE CASCADE,
+  rubric_level_label            VARCHAR(80)  NOT NULL,
+  rubric_level_description      TEXT,
+  rubric_level_points           NUMERIC(6,2) NOT NULL CHECK (rubric_level_points >= 0),
+  rubric_level_order            INT          NOT NULL DEFAULT 0,
+  rubric_level_created_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_rubric_levels_criterion
+  ON rubric_levels (rubric_level_criterion_id, rubric_level_order);
+
+ALTER TABLE assessments
+  ADD COLUMN IF NOT EXISTS assessment_rubric_id UUID REFERENCES rubrics(rubric_id) ON DELETE SET NULL;
+
+CREATE TABLE IF NOT EXISTS submission_rubric_scores (
+  submission_rubric_score_id                 UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  submission_rubric_score_school_id          UUID         NOT NULL REFERENCES schools(school_id) ON DELETE CASCADE,
+  submission_rubric_score_submission_id      UUID         NOT NULL REFERENCES submissions(submission_id) ON DELETE CASCADE,
+  submission_rubric_score_rubric_id          UUID         NOT NULL REFERENCES rubrics(rubric_id) ON DELETE CASCADE,
+  submission_rubric_score_criterion_id       UUID         REFERENCES rubric_criteria(rubric_criterion_id) ON DELETE SET NULL,
+  submission_rubric_score_level_id           UUID         REFERENCES rubric_levels(rubric_level_id) ON DELETE SET NULL,
+
+  submission_rubric_score_criterion_name     VARCHAR(160) NOT NULL,
+  submission_rubric_score_criterion_order    INT          NOT NULL DEFAULT 0,
+  submission_rubric_score_level_label        VARCHAR(80),
+  submission_rubric_score_points             NUMERIC(6,2) NOT NULL CHECK (submission_rubric_score_points >= 0),
+  submission_rubric_score_max_points         NUMERIC(6,2) NOT NULL,
+  submission_rubric_score_comment            TEXT,
+
+  submission_rubric_score_graded_by_teacher_id UUID       REFERENCES school_teachers(school_teacher_id) ON DELETE SET NULL,
+  submission_rubric_score_created_at         TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+  submission_rubric_score_updated_at         TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+
+  CONSTRAINT chk_submission_rubric_score_points
+    CHECK (submission_rubric_score_points <= submission_rubric_score_max_points)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_submission_rubric_scores_criterion
+  ON submission_rubric_scores (submission_rubric_score_submission_id, submission_rubric_score_criterion_id);
+
+COMMIT;
@@ -0,0 +1,19 @@
+-- +migrate Down
+BEGIN;
+
+ALTER TABLE student_quiz_attempts
+  DROP COLUMN IF EXISTS student_quiz_attempt_question_set;
+
+DROP TABLE IF EXISTS quiz_draw_rules;
+
+DROP INDEX IF EXISTS uq_quiz_questions_pool_item;
+DROP INDEX IF EXISTS idx_quiz_questions_bank_item;
+
+ALTER TABLE quiz_questions
+  DROP COLUMN IF EXISTS quiz_question_is_pool,
+  DROP COLUMN IF EXISTS quiz_question_bank_mode,
+  DROP COLUMN IF EXISTS quiz_question_bank_item_id;
+
+DROP TABLE IF EXISTS question_bank_items;
+
+COMMIT;
@@ -0,0 +1,115 @@
+-- +migrate Up
+/* =====================================================================
+   BANK SOAL (tingkat sekolah) + PENARIKAN ACAK KE QUIZ
+   - question_bank_items       : soal reusable, ditandai mapel / class parent /
+                                 topik / tingkat kesulitan
+   - quiz_questions.*bank*     : asal soal bank (mode copy / reference) &
+                                 penanda soal "pool" hasil tarikan acak
+   - quiz_draw_rules           : aturan tarik N soal acak per tag per siswa
+   - student_quiz_attempts.student_quiz_attempt_question_set :
+                                 set soal (urutan + urutan opsi) untuk attempt
+                                 yang sedang berjalan, agar penilaian deterministik
+   ===================================================================== */
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS question_bank_items (
+  question_bank_item_id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  question_bank_item_school_id        UUID         NOT NULL REFERENCES schools(school_id) ON DELETE CASCADE,
+
+  -- tag
+  question_bank_item_subject_id       UUID         REFERENCES subjects(subject_id) ON DELETE SET NULL,
+  question_bank_item_class_parent_id  UUID         REFERENCES class_parents(class_parent_id) ON DELETE SET NULL,
+  question_bank_item_topic            VARCHAR(120),
+  question_bank_item_difficulty       VARCHAR(8)   NOT NULL DEFAULT 'medium'
+    CHECK (question_bank_item_difficulty IN ('easy','medium','hard')),
+
+  -- isi (bentuk sama dengan quiz_questions)
+  question_bank_item_type             VARCHAR(8)   NOT NULL
+    CHECK (question_bank_item_type IN ('single','essay')),
+  question_bank_item_text             TEXT         NOT NULL,
+  question_bank_item_points           NUMERIC(6,2) NOT NULL DEFAULT 1
+    CHECK (question_bank_item_points >= 0),
+  question_bank_item_answers          JSONB,
+  question_bank_item_correct          TEXT,
+  question_bank_item_explanation      TEXT,
+
+  question_bank_item_version          INT          NOT NULL DEFAULT 1,
+  question_bank_item_history          JSONB        NOT NULL DEFAULT '[]'::jsonb,
+  question_bank_item_is_active        BOOLEAN      NOT NULL DEFAULT TRUE,
+  question_bank_item_created_by_teacher_id UUID    REFERENCES school_teachers(school_teacher_id) ON DELETE SET NULL,
+
+  question_bank_item_created_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+  question_bank_item_updated_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+  question_bank_item_deleted_at       TIMESTAMPTZ,
+
+  CONSTRAINT ck_qbi_essay_shape
+    CHECK (
+      question_bank_item_type <> 'essay'
+      OR (question_bank_item_answers IS NULL AND question_bank_item_correct IS NULL)
+    ),
+  CONSTRAINT ck_qbi_single_shape
+    CHECK (
+      question_bank_item_type <> 'single'
+      OR (
+        jsonb_typeof(question_bank_item_answers) = 'object'
+        AND question_bank_item_correct IS NOT NULL
+        AND question_bank_item_answers ? question_bank_item_correct
+      )
+    )
+);
+
+CREATE INDEX IF NOT EXISTS idx_qbi_school_tags
+  ON question_bank_items (question_bank_item_school_id, question_bank_item_subject_id,
+                          question_bank_item_class_parent_id, question_bank_item_difficulty)
+  WHERE question_bank_item_deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_qbi_topic
+  ON question_bank_items (question_bank_item_school_id, LOWER(question_bank_item_topic))
+  WHERE question_bank_item_deleted_at IS NULL;
+
+-- asal soal bank di quiz
+ALTER TABLE quiz_questions
+  ADD COLUMN IF NOT EXISTS quiz_question_bank_item_id UUID
+    REFERENCES question_bank_items(question_bank_item_id) ON DELETE SET NULL,
+  -- copy = salinan lepas; reference = ikut diperbarui saat soal bank diubah
+  ADD COLUMN IF NOT EXISTS quiz_question_bank_mode VARCHAR(10)
+    CHECK (quiz_question_bank_mode IN ('copy','reference')),
+  -- true = soal hasil tarikan acak (tidak ikut set soal tetap quiz)
+  ADD COLUMN IF NOT EXISTS quiz_question_is_pool BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_quiz_questions_bank_item
+  ON quiz_questions (quiz_question_bank_item_id)
+  WHERE quiz_question_bank_item_id IS NOT NULL AND quiz_question_deleted_at IS NULL;
+
+-- 1 soal bank = 1 baris pool per quiz (dipakai ulang lintas siswa)
+CREATE UNIQUE INDEX IF NOT EXISTS uq_quiz_questions_pool_item
+  ON quiz_questions (quiz_question_quiz_id, quiz_question_bank_item_id)
+  WHERE quiz_question_is_pool AND quiz_question_deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS quiz_draw_rules (
+  quiz_draw_rule_id                UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  quiz_draw_rule_school_id         UUID         NOT NULL REFERENCES schools(school_id) ON DELETE CASCADE,
+  quiz_draw_rule_quiz_id           UUID         NOT NULL REFERENCES quizzes(quiz_id) ON DELETE CASCADE,
+
+  -- filter tag (null = semua)
+  quiz_draw_rule_subject_id        UUID         REFERENCES subjects(subject_id) ON DELETE SET NULL,
+  quiz_draw_rule_class_parent_id   UUID         REFERENCES class_parents(class_parent_id) ON DELETE SET NULL,
+  quiz_draw_rule_topic             VARCHAR(120),
+  quiz_draw_rule_difficulty        VARCHAR(8)
+    CHECK (quiz_draw_rule_difficulty IN ('easy','medium','hard')),
+
+  quiz_draw_rule_count             INT          NOT NULL CHECK (quiz_draw_rule_count > 0),
+  quiz_draw_rule_order             INT          NOT NULL DEFAULT 0,
+
+  quiz_draw_rule_created_at        TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+  quiz_draw_rule_updated_at        TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_quiz_draw_rules_quiz
+  ON quiz_draw_rules (quiz_draw_rule_quiz_id, quiz_draw_rule_order);
+
+ALTER TABLE student_quiz_attempts
+  ADD COLUMN IF NOT EXISTS student_quiz_attempt_question_set JSONB;
+
+COMMIT;
@@ -0,0 +1,12 @@
+-- +migrate Down
+BEGIN;
+
+DROP TABLE IF EXISTS student_quiz_attempt_events;
+
+DROP INDEX IF EXISTS idx_sqa_deadline_pending;
+
+ALTER TABLE student_quiz_attempts
+  DROP COLUMN IF EXISTS student_quiz_attempt_deadline_at,
+  DROP COLUMN IF EXISTS student_quiz_attempt_session;
+
+COMMIT;
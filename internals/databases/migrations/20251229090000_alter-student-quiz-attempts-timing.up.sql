@@ -0,0 +1,50 @@
+-- +migrate Up
+/* =====================================================================
+   WAKTU ATTEMPT DI SISI SERVER + LOG INTEGRITAS
+   - student_quiz_attempts.student_quiz_attempt_session :
+       sesi attempt berjalan (token, mulai & deadline versi server,
+       timer per soal untuk strict mode, jawaban draft)
+   - student_quiz_attempts.student_quiz_attempt_deadline_at :
+       salinan deadline sesi → dipindai worker untuk auto-submit
+   - student_quiz_attempt_events :
+       sinyal dari klien (pindah tab, hilang fokus, reconnect, ...) +
+       kejadian server (timeout soal, submit telat, auto-submit)
+   ===================================================================== */
+
+BEGIN;
+
+ALTER TABLE student_quiz_attempts
+  ADD COLUMN IF NOT EXISTS student_quiz_attempt_session     JSONB,
+  ADD COLUMN IF NOT EXISTS student_quiz_attempt_deadline_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_sqa_deadline_pending
+  ON student_quiz_attempts (student_quiz_attempt_deadline_at)
+  WHERE student_quiz_attempt_deadline_at IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS student_quiz_attempt_events (
+  student_quiz_attempt_event_id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  student_quiz_attempt_event_school_id        UUID         NOT NULL REFERENCES schools(school_id) ON DELETE CASCADE,
+  student_quiz_attempt_event_attempt_id       UUID         NOT NULL
+    REFERENCES student_quiz_attempts(student_quiz_attempt_id) ON DELETE CASCADE,
+  student_quiz_attempt_event_attempt_no       INT          NOT NULL,
+
+  student_quiz_attempt_event_kind             VARCHAR(24)  NOT NULL
+    CHECK (student_quiz_attempt_event_kind IN (
+      'tab_switch','focus_lost','reconnect','fullscreen_exit','copy_paste',
+      'resume','question_timeout','late_submit','auto_submit'
+    )),
+  student_quiz_attempt_event_quiz_question_id UUID,
+
+  -- waktu versi klien (informasi saja) & waktu diterima server
+  student_quiz_attempt_event_client_at        TIMESTAMPTZ,
+  student_quiz_attempt_event_meta             JSONB,
+
+  student_quiz_attempt_event_created_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_sqa_events_attempt
+  ON student_quiz_attempt_events (student_quiz_attempt_event_attempt_id,
+                                  student_quiz_attempt_event_attempt_no,
+                                  student_quiz_attempt_event_created_at);
+
+COMMIT;
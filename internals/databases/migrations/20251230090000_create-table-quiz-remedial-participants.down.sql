@@ -0,0 +1,10 @@
+-- +migrate Down
+BEGIN;
+
+DROP TABLE IF EXISTS quiz_remedial_participants;
+
+ALTER TABLE quizzes
+  DROP COLUMN IF EXISTS quiz_remedial_passing_percent,
+  DROP COLUMN IF EXISTS quiz_remedial_policy;
+
+COMMIT;
@@ -0,0 +1,48 @@
+-- +migrate Up
+/* =====================================================================
+   REMEDIAL QUIZ OTOMATIS
+   - quizzes.quiz_remedial_policy          : cara menggabung nilai remedial
+                                              dengan nilai asal (cap_kkm / max / average)
+   - quizzes.quiz_remedial_passing_percent : KKM yang dipakai saat remedial dibuat
+   - quiz_remedial_participants            : siswa yang wajib / boleh mengerjakan
+                                              quiz remedial (quiz hanya terlihat oleh mereka)
+                                              + nilai asal, nilai remedial & nilai akhir
+   ===================================================================== */
+
+BEGIN;
+
+ALTER TABLE quizzes
+  ADD COLUMN IF NOT EXISTS quiz_remedial_policy VARCHAR(10)
+    CHECK (quiz_remedial_policy IN ('cap_kkm','max','average')),
+  ADD COLUMN IF NOT EXISTS quiz_remedial_passing_percent NUMERIC(5,2)
+    CHECK (quiz_remedial_passing_percent BETWEEN 0 AND 100);
+
+CREATE TABLE IF NOT EXISTS quiz_remedial_participants (
+  quiz_remedial_participant_id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  quiz_remedial_participant_school_id        UUID         NOT NULL REFERENCES schools(school_id) ON DELETE CASCADE,
+  quiz_remedial_participant_quiz_id          UUID         NOT NULL REFERENCES quizzes(quiz_id) ON DELETE CASCADE,
+  -- quiz utama (bukan remedial) — rantai remedial dihitung per root
+  quiz_remedial_participant_root_quiz_id     UUID         NOT NULL REFERENCES quizzes(quiz_id) ON DELETE CASCADE,
+  quiz_remedial_participant_student_id       UUID         NOT NULL REFERENCES school_students(school_student_id) ON DELETE CASCADE,
+  quiz_remedial_participant_round            INT          NOT NULL CHECK (quiz_remedial_participant_round >= 1),
+
+  -- nilai (persen 0..100)
+  quiz_remedial_participant_original_percent NUMERIC(6,3) NOT NULL,
+  quiz_remedial_participant_remedial_percent NUMERIC(6,3),
+  quiz_remedial_participant_final_percent    NUMERIC(6,3),
+  quiz_remedial_participant_completed_at     TIMESTAMPTZ,
+
+  quiz_remedial_participant_created_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+  quiz_remedial_participant_updated_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+
+  CONSTRAINT uq_quiz_remedial_participant UNIQUE (quiz_remedial_participant_quiz_id, quiz_remedial_participant_student_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_quiz_remedial_participants_root
+  ON quiz_remedial_participants (quiz_remedial_participant_root_quiz_id, quiz_remedial_participant_student_id,
+                                 quiz_remedial_participant_round);
+
+CREATE INDEX IF NOT EXISTS idx_quiz_remedial_participants_student
+  ON quiz_remedial_participants (quiz_remedial_participant_school_id, quiz_remedial_participant_student_id);
+
+COMMIT;
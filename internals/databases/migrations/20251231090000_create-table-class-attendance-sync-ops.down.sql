@@ -0,0 +1,6 @@
+-- +migrate Down
+BEGIN;
+
+DROP TABLE IF EXISTS class_attendance_sync_ops;
+
+COMMIT;
@@ -0,0 +1,39 @@
+-- +migrate Up
+/* =====================================================================
+   SINKRONISASI ABSENSI OFFLINE (guru di daerah minim sinyal)
+   - klien unduh bundle sesi hari ini + roster (version = updated_at peserta)
+   - penandaan offline dikirim batch; tiap baris membawa client_op_id (UUID klien)
+   - class_attendance_sync_ops : log operasi per client_op_id → kirim ulang batch
+     yang sama mengembalikan hasil yang sama (idempotent), tanpa menulis ulang
+   ===================================================================== */
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS class_attendance_sync_ops (
+  class_attendance_sync_op_id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  class_attendance_sync_op_school_id       UUID        NOT NULL REFERENCES schools(school_id) ON DELETE CASCADE,
+  -- ID operasi buatan klien (unik per sekolah)
+  class_attendance_sync_op_client_op_id    UUID        NOT NULL,
+  class_attendance_sync_op_client_id       VARCHAR(80),
+  class_attendance_sync_op_teacher_id      UUID REFERENCES school_teachers(school_teacher_id) ON DELETE SET NULL,
+  class_attendance_sync_op_session_id      UUID        NOT NULL,
+  class_attendance_sync_op_student_id      UUID        NOT NULL,
+  class_attendance_sync_op_participant_id  UUID,
+
+  class_attendance_sync_op_status          VARCHAR(16) NOT NULL
+    CHECK (class_attendance_sync_op_status IN ('applied','conflict','locked','rejected')),
+  -- waktu tandai menurut jam klien (setelah dinormalisasi)
+  class_attendance_sync_op_client_marked_at TIMESTAMPTZ NOT NULL,
+  -- hasil per baris yang dikembalikan ke klien
+  class_attendance_sync_op_result          JSONB       NOT NULL DEFAULT '{}'::jsonb,
+
+  class_attendance_sync_op_created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_class_attendance_sync_ops_client_op
+  ON class_attendance_sync_ops (class_attendance_sync_op_school_id, class_attendance_sync_op_client_op_id);
+
+CREATE INDEX IF NOT EXISTS idx_class_attendance_sync_ops_session
+  ON class_attendance_sync_ops (class_attendance_sync_op_session_id, class_attendance_sync_op_created_at DESC);
+
+COMMIT;
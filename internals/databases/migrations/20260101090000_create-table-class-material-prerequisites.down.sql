@@ -0,0 +1,10 @@
+-- +migrate Down
+BEGIN;
+
+DROP TABLE IF EXISTS class_material_prerequisites;
+
+ALTER TABLE class_materials
+  DROP COLUMN IF EXISTS class_material_release_with_session,
+  DROP COLUMN IF EXISTS class_material_release_at;
+
+COMMIT;
@@ -0,0 +1,55 @@
+-- +migrate Up
+/* =====================================================================
+   LEARNING PATH MATERI KELAS
+   - class_material_prerequisites : syarat buka materi
+       kind 'material' → materi lain (CSST sama) sudah selesai
+       kind 'quiz'     → quiz sudah dikerjakan dengan nilai terbaik ≥ min_percent
+   - class_materials.class_material_release_at           : materi baru terbuka mulai waktu ini
+   - class_materials.class_material_release_with_session : terbuka saat sesi absensi (class_material_session_id) mulai
+   ===================================================================== */
+
+BEGIN;
+
+ALTER TABLE class_materials
+  ADD COLUMN IF NOT EXISTS class_material_release_at TIMESTAMPTZ,
+  ADD COLUMN IF NOT EXISTS class_material_release_with_session BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS class_material_prerequisites (
+  class_material_prerequisite_id                    UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  class_material_prerequisite_school_id             UUID        NOT NULL REFERENCES schools(school_id) ON DELETE CASCADE,
+  -- materi yang dikunci
+  class_material_prerequisite_class_material_id     UUID        NOT NULL REFERENCES class_materials(class_material_id) ON DELETE CASCADE,
+  class_material_prerequisite_kind                  VARCHAR(16) NOT NULL
+    CHECK (class_material_prerequisite_kind IN ('material','quiz')),
+  class_material_prerequisite_required_material_id  UUID REFERENCES class_materials(class_material_id) ON DELETE CASCADE,
+  class_material_prerequisite_required_quiz_id      UUID REFERENCES quizzes(quiz_id) ON DELETE CASCADE,
+  -- khusus quiz: nilai minimal (persen); NULL = cukup sudah mengerjakan
+  class_material_prerequisite_min_percent           NUMERIC(5,2)
+    CHECK (class_material_prerequisite_min_percent IS NULL
+        OR class_material_prerequisite_min_percent BETWEEN 0 AND 100),
+  class_material_prerequisite_created_at            TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+  CONSTRAINT chk_class_material_prerequisite_target CHECK (
+    (class_material_prerequisite_kind = 'material'
+       AND class_material_prerequisite_required_material_id IS NOT NULL
+       AND class_material_prerequisite_required_quiz_id IS NULL
+       AND class_material_prerequisite_required_material_id <> class_material_prerequisite_class_material_id)
+    OR
+    (class_material_prerequisite_kind = 'quiz'
+       AND class_material_prerequisite_required_quiz_id IS NOT NULL
+       AND class_material_prerequisite_required_material_id IS NULL)
+  )
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_class_material_prerequisites_material
+  ON class_material_prerequisites (class_material_prerequisite_class_material_id, class_material_prerequisite_required_material_id)
+  WHERE class_material_prerequisite_required_material_id IS NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_class_material_prerequisites_quiz
+  ON class_material_prerequisites (class_material_prerequisite_class_material_id, class_material_prerequisite_required_quiz_id)
+  WHERE class_material_prerequisite_required_quiz_id IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_class_material_prerequisites_school
+  ON class_material_prerequisites (class_material_prerequisite_school_id);
+
+COMMIT;
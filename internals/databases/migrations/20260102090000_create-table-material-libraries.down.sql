@@ -0,0 +1,21 @@
+-- +migrate Down
+BEGIN;
+
+DROP INDEX IF EXISTS idx_quizzes_origin;
+ALTER TABLE quizzes
+  DROP COLUMN IF EXISTS quiz_origin_version,
+  DROP COLUMN IF EXISTS quiz_origin_source_id,
+  DROP COLUMN IF EXISTS quiz_origin_library_id;
+
+DROP INDEX IF EXISTS idx_school_materials_origin;
+ALTER TABLE school_materials
+  DROP COLUMN IF EXISTS school_material_origin_version,
+  DROP COLUMN IF EXISTS school_material_origin_source_id,
+  DROP COLUMN IF EXISTS school_material_origin_library_id;
+
+DROP TABLE IF EXISTS material_library_imports;
+DROP TABLE IF EXISTS material_library_versions;
+DROP TABLE IF EXISTS material_library_items;
+DROP TABLE IF EXISTS material_libraries;
+
+COMMIT;
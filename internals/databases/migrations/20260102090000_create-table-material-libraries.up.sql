@@ -0,0 +1,126 @@
+-- +migrate Up
+/* =====================================================================
+   PERPUSTAKAAN MATERI LINTAS SEKOLAH
+   - material_libraries         : paket materi + quiz yang diterbitkan 1 sekolah
+       visibility 'yayasan' → hanya sekolah di yayasan yang sama
+       visibility 'public'  → semua sekolah
+   - material_library_items     : sumber paket (school_materials / quizzes milik penerbit)
+   - material_library_versions  : snapshot isi per versi (dasar import & sinkron)
+   - material_library_imports   : sekolah pengimpor + versi yang sedang dipakai
+   - school_materials / quizzes : kolom *_origin_* → asal salinan (lisensi & atribusi)
+   ===================================================================== */
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS material_libraries (
+  material_library_id                 UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  material_library_school_id          UUID         NOT NULL REFERENCES schools(school_id) ON DELETE CASCADE,
+  -- yayasan penerbit saat terbit (wajib untuk visibility 'yayasan')
+  material_library_yayasan_id         UUID REFERENCES yayasans(yayasan_id) ON DELETE SET NULL,
+  material_library_visibility         VARCHAR(10)  NOT NULL
+    CHECK (material_library_visibility IN ('yayasan','public')),
+
+  material_library_title              VARCHAR(180) NOT NULL,
+  material_library_description        TEXT,
+  material_library_license            VARCHAR(40)  NOT NULL,
+  material_library_attribution        TEXT         NOT NULL,
+
+  material_library_current_version    INT          NOT NULL DEFAULT 1,
+  material_library_is_active          BOOLEAN      NOT NULL DEFAULT TRUE,
+  material_library_created_by_user_id UUID,
+
+  material_library_created_at         TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+  material_library_updated_at         TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+  material_library_deleted_at         TIMESTAMPTZ,
+
+  CONSTRAINT chk_material_library_yayasan CHECK (
+    material_library_visibility <> 'yayasan' OR material_library_yayasan_id IS NOT NULL
+  )
+);
+
+CREATE INDEX IF NOT EXISTS idx_material_libraries_school
+  ON material_libraries (material_library_school_id)
+  WHERE material_library_deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_material_libraries_catalog
+  ON material_libraries (material_library_visibility, material_library_yayasan_id)
+  WHERE material_library_deleted_at IS NULL AND material_library_is_active;
+
+CREATE TABLE IF NOT EXISTS material_library_items (
+  material_library_item_id                  UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  material_library_item_library_id          UUID        NOT NULL REFERENCES material_libraries(material_library_id) ON DELETE CASCADE,
+  material_library_item_kind                VARCHAR(16) NOT NULL
+    CHECK (material_library_item_kind IN ('material','quiz')),
+  material_library_item_school_material_id  UUID REFERENCES school_materials(school_material_id) ON DELETE CASCADE,
+  material_library_item_quiz_id             UUID REFERENCES quizzes(quiz_id) ON DELETE CASCADE,
+  material_library_item_order               INT         NOT NULL DEFAULT 0,
+
+  CONSTRAINT chk_material_library_item_target CHECK (
+    (material_library_item_kind = 'material'
+       AND material_library_item_school_material_id IS NOT NULL
+       AND material_library_item_quiz_id IS NULL)
+    OR
+    (material_library_item_kind = 'quiz'
+       AND material_library_item_quiz_id IS NOT NULL
+       AND material_library_item_school_material_id IS NULL)
+  )
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_material_library_items_material
+  ON material_library_items (material_library_item_library_id, material_library_item_school_material_id)
+  WHERE material_library_item_school_material_id IS NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_material_library_items_quiz
+  ON material_library_items (material_library_item_library_id, material_library_item_quiz_id)
+  WHERE material_library_item_quiz_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS material_library_versions (
+  material_library_version_id                 UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  material_library_version_library_id         UUID        NOT NULL REFERENCES material_libraries(material_library_id) ON DELETE CASCADE,
+  material_library_version_number             INT         NOT NULL CHECK (material_library_version_number >= 1),
+  material_library_version_changelog          TEXT,
+  -- {license, attribution, materials:[...], quizzes:[{..., questions:[...]}]}
+  material_library_version_snapshot           JSONB       NOT NULL,
+  material_library_version_created_by_user_id UUID,
+  material_library_version_created_at         TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_material_library_versions_number
+  ON material_library_versions (material_library_version_library_id, material_library_version_number);
+
+CREATE TABLE IF NOT EXISTS material_library_imports (
+  material_library_import_id                  UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  material_library_import_library_id          UUID        NOT NULL REFERENCES material_libraries(material_library_id) ON DELETE CASCADE,
+  material_library_import_school_id           UUID        NOT NULL REFERENCES schools(school_id) ON DELETE CASCADE,
+  material_library_import_version             INT         NOT NULL,
+  material_library_import_imported_by_user_id UUID,
+  material_library_import_created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  material_library_import_synced_at           TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_material_library_imports_school
+  ON material_library_imports (material_library_import_library_id, material_library_import_school_id);
+
+CREATE INDEX IF NOT EXISTS idx_material_library_imports_school
+  ON material_library_imports (material_library_import_school_id);
+
+-- asal salinan hasil import
+ALTER TABLE school_materials
+  ADD COLUMN IF NOT EXISTS school_material_origin_library_id UUID REFERENCES material_libraries(material_library_id) ON DELETE SET NULL,
+  ADD COLUMN IF NOT EXISTS school_material_origin_source_id  UUID,
+  ADD COLUMN IF NOT EXISTS school_material_origin_version    INT;
+
+CREATE INDEX IF NOT EXISTS idx_school_materials_origin
+  ON school_materials (school_material_school_id, school_material_origin_library_id, school_material_origin_source_id)
+  WHERE school_material_origin_library_id IS NOT NULL;
+
+ALTER TABLE quizzes
+  ADD COLUMN IF NOT EXISTS quiz_origin_library_id UUID REFERENCES material_libraries(material_library_id) ON DELETE SET NULL,
+  ADD COLUMN IF NOT EXISTS quiz_origin_source_id  UUID,
+  ADD COLUMN IF NOT EXISTS quiz_origin_version    INT;
+
+CREATE INDEX IF NOT EXISTS idx_quizzes_origin
+  ON quizzes (quiz_school_id, quiz_origin_library_id, quiz_origin_source_id)
+  WHERE quiz_origin_library_id IS NOT NULL;
+
+COMMIT;
@@ -0,0 +1,8 @@
+-- +migrate Down
+BEGIN;
+
+ALTER TABLE attendance_devices
+  DROP COLUMN IF EXISTS attendance_device_allowed_ips,
+  DROP COLUMN IF EXISTS attendance_device_token_hash;
+
+COMMIT;
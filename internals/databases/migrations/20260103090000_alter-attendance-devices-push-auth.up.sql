@@ -0,0 +1,16 @@
+-- +migrate Up
+/* =====================================================================
+   MESIN ABSEN: autentikasi push ADMS
+   - SN tercetak di mesin & muncul di access log → tidak cukup sebagai kredensial
+   - attendance_device_token_hash  : sha256(token push) per mesin; NULL = mesin belum
+                                     punya token → push ditolak sampai admin rotate token
+   - attendance_device_allowed_ips : IP/CIDR sumber yang boleh push (kosong = bebas)
+   ===================================================================== */
+
+BEGIN;
+
+ALTER TABLE attendance_devices
+  ADD COLUMN IF NOT EXISTS attendance_device_token_hash  CHAR(64),
+  ADD COLUMN IF NOT EXISTS attendance_device_allowed_ips TEXT[] NOT NULL DEFAULT '{}';
+
+COMMIT;
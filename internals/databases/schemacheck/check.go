@@ -0,0 +1,192 @@
+// internals/databases/schemacheck/check.go
+package schemacheck
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+/* =========================================================
+   schema-check: kolom model GORM vs skema live (information_schema)
+========================================================= */
+
+type Severity string
+
+const (
+	SevError Severity = "error" // query akan gagal (tabel/kolom tidak ada, insert kena NOT NULL)
+	SevWarn  Severity = "warn"  // beda nullability / kolom DB tanpa field model
+)
+
+type Issue struct {
+	Severity Severity `json:"severity"`
+	Model    string   `json:"model"`
+	Table    string   `json:"table"`
+	Column   string   `json:"column,omitempty"`
+	Kind     string   `json:"kind"`
+	Detail   string   `json:"detail,omitempty"`
+}
+
+type dbColumn struct {
+	Table      string  `gorm:"column:table_name"`
+	Column     string  `gorm:"column:column_name"`
+	DataType   string  `gorm:"column:data_type"`
+	UDTName    string  `gorm:"column:udt_name"`
+	IsNullable string  `gorm:"column:is_nullable"`
+	Default    *string `gorm:"column:column_default"`
+	Generated  string  `gorm:"column:is_generated"`
+}
+
+type Options struct {
+	Tables []string // kosong → semua model di registry
+}
+
+// Check membandingkan setiap model di Models dengan skema aktif (current_schema()).
+func Check(ctx context.Context, db *gorm.DB, opts Options) ([]Issue, error) {
+	var cols []dbColumn
+	if err := db.WithContext(ctx).Raw(`
+		SELECT table_name, column_name, data_type, udt_name, is_nullable, column_default, is_generated
+		FROM information_schema.columns
+		WHERE table_schema = current_schema()
+	`).Scan(&cols).Error; err != nil {
+		return nil, err
+	}
+	live := map[string]map[string]dbColumn{}
+	for _, c := range cols {
+		if live[c.Table] == nil {
+			live[c.Table] = map[string]dbColumn{}
+		}
+		live[c.Table][c.Column] = c
+	}
+
+	only := map[string]bool{}
+	for _, t := range opts.Tables {
+		only[strings.TrimSpace(t)] = true
+	}
+
+	var (
+		issues []Issue
+		cache  = &sync.Map{}
+		namer  = db.NamingStrategy
+	)
+	for _, m := range Models {
+		s, err := schema.Parse(m, cache, namer)
+		if err != nil {
+			return nil, fmt.Errorf("parse %T: %w", m, err)
+		}
+		if len(only) > 0 && !only[s.Table] {
+			continue
+		}
+		issues = append(issues, checkModel(s, live[s.Table])...)
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Severity != issues[j].Severity {
+			return issues[i].Severity == SevError
+		}
+		if issues[i].Table != issues[j].Table {
+			return issues[i].Table < issues[j].Table
+		}
+		return issues[i].Column < issues[j].Column
+	})
+	return issues, nil
+}
+
+func checkModel(s *schema.Schema, live map[string]dbColumn) []Issue {
+	name := s.Name
+	if live == nil {
+		return []Issue{{Severity: SevError, Model: name, Table: s.Table, Kind: "missing_table",
+			Detail: "tabel tidak ada di database"}}
+	}
+
+	var out []Issue
+	inModel := map[string]bool{}
+	for _, f := range s.Fields {
+		if f.DBName == "" || f.IgnoreMigration && !f.Readable {
+			continue
+		}
+		inModel[f.DBName] = true
+
+		col, ok := live[f.DBName]
+		if !ok {
+			out = append(out, Issue{Severity: SevError, Model: name, Table: s.Table, Column: f.DBName,
+				Kind: "missing_column", Detail: fmt.Sprintf("field %s tidak punya kolom di DB", f.Name)})
+			continue
+		}
+
+		// kolom NOT NULL tapi field bisa nil (pointer) → insert nil akan gagal kalau tanpa default
+		if col.IsNullable == "NO" && col.Default == nil && col.Generated != "ALWAYS" &&
+			isNillable(f) && !f.PrimaryKey && f.DefaultValueInterface == nil && f.DefaultValue == "" {
+			out = append(out, Issue{Severity: SevWarn, Model: name, Table: s.Table, Column: f.DBName,
+				Kind: "nullability", Detail: "kolom NOT NULL tanpa default, tapi field model nullable"})
+		}
+
+		if want := typeFamily(f.DataType); want != "" {
+			if got := dbFamily(col); got != "" && got != want {
+				out = append(out, Issue{Severity: SevWarn, Model: name, Table: s.Table, Column: f.DBName,
+					Kind: "type_mismatch", Detail: fmt.Sprintf("model=%s db=%s", f.DataType, col.UDTName)})
+			}
+		}
+	}
+
+	for cname, col := range live {
+		if inModel[cname] {
+			continue
+		}
+		if col.IsNullable == "NO" && col.Default == nil && col.Generated != "ALWAYS" {
+			out = append(out, Issue{Severity: SevError, Model: name, Table: s.Table, Column: cname,
+				Kind: "unmapped_required_column", Detail: "kolom NOT NULL tanpa default tidak ada di model → insert via GORM gagal"})
+			continue
+		}
+		out = append(out, Issue{Severity: SevWarn, Model: name, Table: s.Table, Column: cname,
+			Kind: "unmapped_column", Detail: "kolom DB tidak dipetakan di model"})
+	}
+	return out
+}
+
+func isNillable(f *schema.Field) bool {
+	if f.FieldType == nil {
+		return false
+	}
+	switch f.FieldType.Kind().String() {
+	case "ptr", "slice", "map", "interface":
+		return true
+	}
+	return false
+}
+
+// typeFamily: kelompok kasar tipe GORM (hanya yang jelas saja).
+func typeFamily(dt schema.DataType) string {
+	switch dt {
+	case schema.Bool:
+		return "bool"
+	case schema.Int, schema.Uint:
+		return "int"
+	case schema.Float:
+		return "number"
+	case schema.Time:
+		return "time"
+	}
+	return ""
+}
+
+func dbFamily(c dbColumn) string {
+	switch c.UDTName {
+	case "bool":
+		return "bool"
+	case "int2", "int4", "int8":
+		return "int"
+	case "numeric", "float4", "float8":
+		return "number"
+	case "date", "timestamp", "timestamptz", "time", "timetz":
+		return "time"
+	case "text", "varchar", "bpchar", "citext", "uuid", "jsonb", "json":
+		return "other"
+	}
+	return ""
+}
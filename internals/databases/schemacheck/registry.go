@@ -0,0 +1,164 @@
+// internals/databases/schemacheck/registry.go
+package schemacheck
+
+import (
+	billingsModel "madinahsalam_backend/internals/features/finance/billings/model"
+	generalBillingsModel "madinahsalam_backend/internals/features/finance/general_billings/model"
+	paymentsModel "madinahsalam_backend/internals/features/finance/payments/model"
+	schoolSubscriptionsModel "madinahsalam_backend/internals/features/finance/school_subscriptions/model"
+	importsModel "madinahsalam_backend/internals/features/lembaga/school_yayasans/imports/model"
+	schoolsModel "madinahsalam_backend/internals/features/lembaga/school_yayasans/schools/model"
+	schoolsMoreModel "madinahsalam_backend/internals/features/lembaga/school_yayasans/schools_more/model"
+	studentTransfersModel "madinahsalam_backend/internals/features/lembaga/school_yayasans/student_transfers/model"
+	teachersStudentsModel "madinahsalam_backend/internals/features/lembaga/school_yayasans/teachers_students/model"
+	userFollowSchoolsModel "madinahsalam_backend/internals/features/lembaga/school_yayasans/user_follow_schools/model"
+	yayasansModel "madinahsalam_backend/internals/features/lembaga/school_yayasans/yayasans/model"
+	lembagaStatsModel "madinahsalam_backend/internals/features/lembaga/stats/lembaga_stats/model"
+	semesterStatsModel "madinahsalam_backend/internals/features/lembaga/stats/semester_stats/model"
+	themeModel "madinahsalam_backend/internals/features/lembaga/ui/theme/model"
+	academicTermsModel "madinahsalam_backend/internals/features/school/academics/academic_terms/model"
+	booksModel "madinahsalam_backend/internals/features/school/academics/books/model"
+	certificatesModel "madinahsalam_backend/internals/features/school/academics/certificates/model"
+	roomsModel "madinahsalam_backend/internals/features/school/academics/rooms/model"
+	subjectsModel "madinahsalam_backend/internals/features/school/academics/subjects/model"
+	classAttendanceSessionsModel "madinahsalam_backend/internals/features/school/class_others/class_attendance_sessions/model"
+	classEventsModel "madinahsalam_backend/internals/features/school/class_others/class_events/model"
+	classMaterialsModel "madinahsalam_backend/internals/features/school/class_others/class_materials/model"
+	classSchedulesModel "madinahsalam_backend/internals/features/school/class_others/class_schedules/model"
+	classParentsModel "madinahsalam_backend/internals/features/school/classes/class_parents/model"
+	classSectionSubjectTeachersModel "madinahsalam_backend/internals/features/school/classes/class_section_subject_teachers/model"
+	classSectionsModel "madinahsalam_backend/internals/features/school/classes/class_sections/model"
+	classesModel "madinahsalam_backend/internals/features/school/classes/classes/model"
+	assesmentsSettingsModel "madinahsalam_backend/internals/features/school/others/assesments_settings/model"
+	postModel "madinahsalam_backend/internals/features/school/others/post/model"
+	assesmentsModel "madinahsalam_backend/internals/features/school/submissions_assesments/assesments/model"
+	quizzesModel "madinahsalam_backend/internals/features/school/submissions_assesments/quizzes/model"
+	submissionsModel "madinahsalam_backend/internals/features/school/submissions_assesments/submissions/model"
+	authModel "madinahsalam_backend/internals/features/users/auth/model"
+	surveyModel "madinahsalam_backend/internals/features/users/survey/model"
+	userProfilesModel "madinahsalam_backend/internals/features/users/user_profiles/model"
+	userTeachersModel "madinahsalam_backend/internals/features/users/user_teachers/model"
+	usersModel "madinahsalam_backend/internals/features/users/users/model"
+)
+
+// Models: semua model GORM yang punya tabel sendiri.
+// Tambahkan di sini setiap kali membuat model baru supaya ikut dicek `schema-check`.
+var Models = []any{
+	&billingsModel.BillBatchModel{},
+	&billingsModel.FeeRuleModel{},
+
+	&generalBillingsModel.GeneralBillingModel{},
+	&generalBillingsModel.UserGeneralBillingModel{},
+
+	&paymentsModel.PaymentGatewayEventModel{},
+	&paymentsModel.PaymentItemModel{},
+	&paymentsModel.PaymentModel{},
+
+	&schoolSubscriptionsModel.SchoolServiceInvoiceModel{},
+	&schoolSubscriptionsModel.SchoolServiceSubscriptionModel{},
+
+	&importsModel.ImportJobModel{},
+
+	&schoolsModel.SchoolProfileModel{},
+	&schoolsModel.SchoolServicePlan{},
+	&schoolsModel.SchoolModel{},
+
+	&schoolsMoreModel.SchoolProfileTeacherDkmModel{},
+	&schoolsMoreModel.SchoolStatsModel{},
+	&schoolsMoreModel.SchoolTagModel{},
+	&schoolsMoreModel.SchoolTagRelationModel{},
+
+	&studentTransfersModel.StudentTransferModel{},
+
+	&teachersStudentsModel.SchoolStudentModel{},
+	&teachersStudentsModel.SchoolTeacherModel{},
+
+	&userFollowSchoolsModel.UserFollowSchoolModel{},
+
+	&yayasansModel.YayasanAdminModel{},
+	&yayasansModel.YayasanModel{},
+
+	&lembagaStatsModel.LembagaStats{},
+
+	&semesterStatsModel.UserClassAttendanceSemesterStatsModel{},
+
+	&themeModel.UIThemeChoice{},
+	&themeModel.UIThemeCustomPreset{},
+	&themeModel.UIThemePreset{},
+
+	&academicTermsModel.AcademicTermModel{},
+
+	&booksModel.BookURLModel{},
+	&booksModel.BookModel{},
+	&booksModel.ClassSubjectBookModel{},
+
+	&certificatesModel.UserSubjectSummary{},
+
+	&roomsModel.ClassRoomModel{},
+
+	&subjectsModel.ClassSubjectModel{},
+	&subjectsModel.SubjectModel{},
+
+	&classAttendanceSessionsModel.ClassAttendanceSessionParticipantTypeModel{},
+	&classAttendanceSessionsModel.ClassAttendanceSessionParticipantURLModel{},
+	&classAttendanceSessionsModel.ClassAttendanceSessionTypeModel{},
+	&classAttendanceSessionsModel.ClassAttendanceSessionModel{},
+	&classAttendanceSessionsModel.ClassAttendanceSessionURLModel{},
+	&classAttendanceSessionsModel.ClassAttendanceSessionParticipantModel{},
+
+	&classEventsModel.ClassEventThemeModel{},
+	&classEventsModel.ClassEventURLModel{},
+	&classEventsModel.ClassEventModel{},
+
+	&classMaterialsModel.ClassMaterialsModel{},
+	&classMaterialsModel.SchoolMaterialModel{},
+	&classMaterialsModel.StudentClassMaterialProgressModel{},
+
+	&classSchedulesModel.NationalHolidayModel{},
+	&classSchedulesModel.ClassScheduleRuleModel{},
+	&classSchedulesModel.ClassScheduleModel{},
+	&classSchedulesModel.SchoolHoliday{},
+
+	&classParentsModel.ClassParentModel{},
+
+	&classSectionSubjectTeachersModel.ClassSectionSubjectTeacherModel{},
+	&classSectionSubjectTeachersModel.StudentClassSectionSubjectTeacherModel{},
+
+	&classSectionsModel.ClassSectionModel{},
+	&classSectionsModel.StudentClassSection{},
+
+	&classesModel.ClassModel{},
+	&classesModel.StudentClassEnrollmentModel{},
+
+	&assesmentsSettingsModel.ClassAttendanceSetting{},
+
+	&postModel.Post{},
+	&postModel.PostThemeModel{},
+	&postModel.PostURL{},
+
+	&assesmentsModel.AssessmentTypeModel{},
+	&assesmentsModel.AssessmentURLModel{},
+	&assesmentsModel.AssessmentModel{},
+
+	&quizzesModel.QuizQuestionModel{},
+	&quizzesModel.QuizModel{},
+	&quizzesModel.StudentQuizAttemptModel{},
+
+	&submissionsModel.SubmissionURLModel{},
+	&submissionsModel.SubmissionModel{},
+
+	&authModel.RefreshTokenModel{},
+	&authModel.TokenBlacklistModel{},
+
+	&surveyModel.SurveyQuestion{},
+	&surveyModel.UserSurvey{},
+
+	&userProfilesModel.UsersProfileDocumentModel{},
+	&userProfilesModel.UsersProfileFormalModel{},
+
+	&userTeachersModel.UserTeacherModel{},
+
+	&usersModel.UserProfileModel{},
+	&usersModel.UserRole{},
+	&usersModel.UserModel{},
+}
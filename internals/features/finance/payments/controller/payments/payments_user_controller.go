@@ -174,12 +174,15 @@ func (h *PaymentController) CreatePayment(c *fiber.Ctx) error {
 	if m.PaymentMethod != model.PaymentMethodGateway && m.PaymentStatus == model.PaymentStatusPaid {
 		_ = svc.ApplyStudentBillSideEffects(c.Context(), h.DB, m)
 		_ = svc.ApplyEnrollmentSideEffects(c.Context(), h.DB, m, paymentSnapshot(c, m)) // ✅
+		svc.EmitPaymentWebhooks(c.Context(), h.DB, m)
 	}
 
 	return helper.JsonCreated(c, "payment created", dto.FromModel(c, m))
 }
 
 // PATCH /payments/:id
+// Hanya payment milik sekolah aktif; payment platform (payment_school_id NULL, mis. invoice
+// langganan) tidak bisa diubah dari sini — pelunasannya lewat webhook Midtrans / owner mark-paid.
 func (h *PaymentController) PatchPayment(c *fiber.Ctx) error {
 	idStr := c.Params("id")
 	id, err := uuid.Parse(idStr)
@@ -187,9 +190,17 @@ func (h *PaymentController) PatchPayment(c *fiber.Ctx) error {
 		return helper.JsonError(c, fiber.StatusBadRequest, "invalid id")
 	}
 
+	schoolID, err := helperAuth.GetActiveSchoolIDFromToken(c)
+	if err != nil || schoolID == uuid.Nil {
+		return helper.JsonError(c, fiber.StatusUnauthorized, "school context not found in token")
+	}
+	if err := helperAuth.EnsureDKMSchool(c, schoolID); err != nil {
+		return err
+	}
+
 	var m model.PaymentModel
 	if err := h.DB.WithContext(c.Context()).
-		First(&m, "payment_id = ? AND payment_deleted_at IS NULL", id).Error; err != nil {
+		First(&m, "payment_id = ? AND payment_school_id = ? AND payment_deleted_at IS NULL", id, schoolID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return helper.JsonError(c, fiber.StatusNotFound, "payment not found")
 		}
@@ -200,6 +211,10 @@ func (h *PaymentController) PatchPayment(c *fiber.Ctx) error {
 	if err := c.BodyParser(&patch); err != nil {
 		return helper.JsonError(c, fiber.StatusBadRequest, "invalid json: "+err.Error())
 	}
+	if patch.PaymentSchoolID.Set &&
+		(patch.PaymentSchoolID.Null || patch.PaymentSchoolID.Value == nil || *patch.PaymentSchoolID.Value != schoolID) {
+		return helper.JsonError(c, fiber.StatusBadRequest, "payment_school_id tidak boleh diubah")
+	}
 	if err := patch.Apply(&m); err != nil {
 		return helper.JsonError(c, fiber.StatusBadRequest, err.Error())
 	}
@@ -212,6 +227,7 @@ func (h *PaymentController) PatchPayment(c *fiber.Ctx) error {
 	// Side effects
 	_ = svc.ApplyStudentBillSideEffects(c.Context(), h.DB, &m)
 	_ = svc.ApplyEnrollmentSideEffects(c.Context(), h.DB, &m, paymentSnapshot(c, &m)) // ✅
+	svc.EmitPaymentWebhooks(c.Context(), h.DB, &m)
 
 	return helper.JsonUpdated(c, "payment updated", dto.FromModel(c, &m)) // ✅
 
@@ -367,9 +383,11 @@ func (h *PaymentController) MidtransWebhook(c *fiber.Ctx) error {
 		return helper.JsonError(c, fiber.StatusInternalServerError, "update payment failed: "+err.Error())
 	}
 
-	// 7) Side effects ke student_bills & enrollment (jika ada target/meta)
+	// 7) Side effects ke student_bills, enrollment & langganan sekolah (jika ada target/meta)
 	_ = svc.ApplyStudentBillSideEffects(c.Context(), h.DB, &p)
 	_ = svc.ApplyEnrollmentSideEffects(c.Context(), h.DB, &p, paymentSnapshot(c, &p)) // ✅
+	_ = svc.ApplySchoolSubscriptionSideEffects(c.Context(), h.DB, &p)
+	svc.EmitPaymentWebhooks(c.Context(), h.DB, &p)
 
 	_ = h.updateEventStatus(notif, "processed", "")
 
@@ -316,6 +316,7 @@ func ApplyEnrollmentSideEffects(ctx context.Context, db *gorm.DB, p *model.Payme
 				return err
 			}
 		}
+		emitEnrollmentWebhooks(ctx, db, p, ids)
 
 	case model.PaymentStatusCanceled,
 		model.PaymentStatusFailed,
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"madinahsalam_backend/internals/features/finance/payments/model"
+	subsModel "madinahsalam_backend/internals/features/finance/school_subscriptions/model"
+	schoolModel "madinahsalam_backend/internals/features/lembaga/school_yayasans/schools/model"
+)
+
+/* =========================================================
+   School subscription side-effects (platform invoicing)
+   - payment header: payment_school_id = NULL
+   - relasi: school_service_invoices.school_service_invoice_payment_id
+========================================================= */
+
+type subscriptionInvoiceRow struct {
+	InvoiceID      uuid.UUID  `gorm:"column:school_service_invoice_id"`
+	SchoolID       uuid.UUID  `gorm:"column:school_service_invoice_school_id"`
+	SubscriptionID uuid.UUID  `gorm:"column:school_service_invoice_subscription_id"`
+	PlanID         uuid.UUID  `gorm:"column:school_service_invoice_plan_id"`
+	BillingCycle   string     `gorm:"column:school_service_invoice_billing_cycle"`
+	PeriodStart    time.Time  `gorm:"column:school_service_invoice_period_start"`
+	Status         string     `gorm:"column:school_service_invoice_status"`
+	PaidAt         *time.Time `gorm:"column:school_service_invoice_paid_at"`
+	SubStatus      string     `gorm:"column:school_service_subscription_status"`
+}
+
+// AddBillingCycle menggeser waktu sebanyak 1 siklus (monthly/yearly).
+func AddBillingCycle(t time.Time, cycle string) time.Time {
+	if cycle == "yearly" {
+		return t.AddDate(1, 0, 0)
+	}
+	return t.AddDate(0, 1, 0)
+}
+
+// ApplySchoolSubscriptionSideEffects menyelaraskan invoice & subscription sekolah dengan payment.
+// Hanya berlaku untuk payment platform (payment_school_id NULL) yang tertaut ke invoice.
+// Pemanggil: webhook Midtrans & owner mark-paid saja (bukan PATCH/POST payment sekolah).
+func ApplySchoolSubscriptionSideEffects(ctx context.Context, db *gorm.DB, p *model.PaymentModel) error {
+	if p == nil || p.PaymentSchoolID != nil {
+		return nil
+	}
+
+	var inv subscriptionInvoiceRow
+	err := db.WithContext(ctx).Raw(`
+		SELECT i.school_service_invoice_id,
+		       i.school_service_invoice_school_id,
+		       i.school_service_invoice_subscription_id,
+		       i.school_service_invoice_plan_id,
+		       i.school_service_invoice_billing_cycle,
+		       i.school_service_invoice_period_start,
+		       i.school_service_invoice_status,
+		       i.school_service_invoice_paid_at,
+		       s.school_service_subscription_status
+		  FROM school_service_invoices i
+		  JOIN school_service_subscriptions s
+		    ON s.school_service_subscription_id = i.school_service_invoice_subscription_id
+		 WHERE i.school_service_invoice_payment_id = ?
+		   AND i.school_service_invoice_deleted_at IS NULL
+		 LIMIT 1
+	`, p.PaymentID).Scan(&inv).Error
+	if err != nil {
+		return err
+	}
+	if inv.InvoiceID == uuid.Nil {
+		return nil
+	}
+
+	switch p.PaymentStatus {
+	case model.PaymentStatusPaid:
+		if inv.Status == "paid" {
+			return nil // idempotent (webhook retry)
+		}
+		now := time.Now()
+		paidAt := now
+		if p.PaymentPaidAt != nil {
+			paidAt = *p.PaymentPaidAt
+		}
+
+		// Langganan yang sudah lapse dimulai ulang dari saat bayar
+		start := inv.PeriodStart
+		if inv.SubStatus == "expired" {
+			start = paidAt
+		}
+		end := AddBillingCycle(start, inv.BillingCycle)
+
+		return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			// Semua tulis lewat model (bukan Exec) supaya tercatat di audit_logs.
+			if err := tx.Model(&subsModel.SchoolServiceInvoiceModel{SchoolServiceInvoiceID: inv.InvoiceID}).
+				Updates(map[string]any{
+					"school_service_invoice_status":     subsModel.InvoiceStatusPaid,
+					"school_service_invoice_paid_at":    paidAt,
+					"school_service_invoice_updated_at": now,
+				}).Error; err != nil {
+				return err
+			}
+			// Langganan lapse (downgrade) sudah ditutup (end_at terisi): jadikan current lagi.
+			// Baris current lain milik sekolah (mis. plan gratis hasil downgrade) ditutup dulu,
+			// karena GetCurrentSubscription / IsSchoolReadOnly hanya melihat end_at IS NULL.
+			if err := tx.Model(&subsModel.SchoolServiceSubscriptionModel{}).
+				Where(`school_service_subscription_school_id = ?
+					AND school_service_subscription_id <> ?
+					AND school_service_subscription_end_at IS NULL
+					AND school_service_subscription_deleted_at IS NULL`, inv.SchoolID, inv.SubscriptionID).
+				Updates(map[string]any{
+					"school_service_subscription_end_at":     paidAt,
+					"school_service_subscription_status":     subsModel.SubscriptionStatusCanceled,
+					"school_service_subscription_updated_at": now,
+				}).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&subsModel.SchoolServiceSubscriptionModel{SchoolServiceSubscriptionID: inv.SubscriptionID}).
+				Where("school_service_subscription_deleted_at IS NULL").
+				Updates(map[string]any{
+					"school_service_subscription_status":               subsModel.SubscriptionStatusActive,
+					"school_service_subscription_end_at":               nil,
+					"school_service_subscription_current_period_start": start,
+					"school_service_subscription_current_period_end":   end,
+					"school_service_subscription_grace_until":          nil,
+					"school_service_subscription_lapsed_at":            nil,
+					"school_service_subscription_updated_at":           now,
+				}).Error; err != nil {
+				return err
+			}
+			// plan kembali / naik ke plan yang dibayar
+			return tx.Model(&schoolModel.SchoolModel{SchoolID: inv.SchoolID}).
+				Update("school_current_plan_id", inv.PlanID).Error
+		})
+
+	case model.PaymentStatusCanceled,
+		model.PaymentStatusFailed,
+		model.PaymentStatusExpired:
+		if inv.Status == "paid" {
+			return errors.New("invoice already paid; refund must be handled manually")
+		}
+		// lepas payment supaya invoice bisa dibayar ulang
+		return db.WithContext(ctx).
+			Model(&subsModel.SchoolServiceInvoiceModel{SchoolServiceInvoiceID: inv.InvoiceID}).
+			Where("school_service_invoice_payment_id = ?", p.PaymentID).
+			Updates(map[string]any{
+				"school_service_invoice_payment_id": nil,
+				"school_service_invoice_updated_at": time.Now(),
+			}).Error
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"madinahsalam_backend/internals/features/finance/payments/model"
+	webhookmodel "madinahsalam_backend/internals/features/lembaga/webhooks/model"
+	webhooksvc "madinahsalam_backend/internals/features/lembaga/webhooks/service"
+)
+
+/* =========================================================
+   Outbound webhook: payment.paid
+   - hanya payment milik sekolah (langganan platform: school_id NULL → skip)
+   - dedupe per payment → callback gateway yang berulang tidak dobel
+========================================================= */
+
+// EmitPaymentWebhooks dipanggil bersama side-effect lain setelah status payment disimpan.
+func EmitPaymentWebhooks(ctx context.Context, db *gorm.DB, p *model.PaymentModel) {
+	if p == nil || p.PaymentSchoolID == nil || p.PaymentStatus != model.PaymentStatusPaid {
+		return
+	}
+
+	var items []struct {
+		SchoolStudentID *uuid.UUID `gorm:"column:payment_item_school_student_id" json:"school_student_id,omitempty"`
+		EnrollmentID    *uuid.UUID `gorm:"column:payment_item_enrollment_id" json:"enrollment_id,omitempty"`
+		Title           *string    `gorm:"column:payment_item_title" json:"title,omitempty"`
+		AmountIDR       int        `gorm:"column:payment_item_amount_idr" json:"amount_idr"`
+	}
+	_ = db.WithContext(ctx).
+		Table("payment_items").
+		Select("payment_item_school_student_id, payment_item_enrollment_id, payment_item_title, payment_item_amount_idr").
+		Where("payment_item_payment_id = ? AND payment_item_deleted_at IS NULL", p.PaymentID).
+		Order("payment_item_index ASC").
+		Scan(&items).Error
+
+	_ = webhooksvc.Emit(ctx, db, *p.PaymentSchoolID, webhookmodel.EventPaymentPaid,
+		"payment:"+p.PaymentID.String(),
+		map[string]any{
+			"payment_id":          p.PaymentID,
+			"payment_number":      p.PaymentNumber,
+			"payment_user_id":     p.PaymentUserID,
+			"payment_amount_idr":  p.PaymentAmountIDR,
+			"payment_currency":    p.PaymentCurrency,
+			"payment_method":      p.PaymentMethod,
+			"payment_paid_at":     p.PaymentPaidAt,
+			"payment_description": p.PaymentDescription,
+			"items":               items,
+		})
+}
+
+/* =========================================================
+   Outbound webhook: student.enrolled (enrollment → accepted karena lunas)
+========================================================= */
+
+func emitEnrollmentWebhooks(ctx context.Context, db *gorm.DB, p *model.PaymentModel, enrollmentIDs []uuid.UUID) {
+	if p.PaymentSchoolID == nil {
+		return
+	}
+	for _, eid := range enrollmentIDs {
+		var row struct {
+			StudentID *uuid.UUID `gorm:"column:student_class_enrollments_school_student_id"`
+			ClassID   *uuid.UUID `gorm:"column:student_class_enrollments_class_id"`
+			ClassName *string    `gorm:"column:student_class_enrollments_class_name_cache"`
+			Status    string     `gorm:"column:student_class_enrollments_status"`
+		}
+		if err := db.WithContext(ctx).
+			Table("student_class_enrollments").
+			Select("student_class_enrollments_school_student_id, student_class_enrollments_class_id, student_class_enrollments_class_name_cache, student_class_enrollments_status").
+			Where("student_class_enrollments_id = ? AND student_class_enrollments_deleted_at IS NULL", eid).
+			Take(&row).Error; err != nil {
+			continue
+		}
+		_ = webhooksvc.Emit(ctx, db, *p.PaymentSchoolID, webhookmodel.EventStudentEnrolled,
+			"enrollment:"+eid.String(),
+			map[string]any{
+				"student_class_enrollment_id": eid,
+				"school_student_id":           row.StudentID,
+				"class_id":                    row.ClassID,
+				"class_name":                  row.ClassName,
+				"status":                      row.Status,
+				"payment_id":                  p.PaymentID,
+				"payment_amount_idr":          p.PaymentAmountIDR,
+			})
+	}
+}
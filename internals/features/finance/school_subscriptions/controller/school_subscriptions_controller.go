@@ -0,0 +1,196 @@
+// file: internals/features/finance/school_subscriptions/controller/school_subscriptions_controller.go
+package controller
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	paymodel "madinahsalam_backend/internals/features/finance/payments/model"
+	dto "madinahsalam_backend/internals/features/finance/school_subscriptions/dto"
+	model "madinahsalam_backend/internals/features/finance/school_subscriptions/model"
+	svc "madinahsalam_backend/internals/features/finance/school_subscriptions/service"
+	helper "madinahsalam_backend/internals/helpers"
+	helperAuth "madinahsalam_backend/internals/helpers/auth"
+)
+
+/* =======================================================================
+   Controller
+======================================================================= */
+
+type SchoolSubscriptionController struct {
+	DB        *gorm.DB
+	Validator *validator.Validate
+	Config    svc.BillingConfig
+}
+
+func NewSchoolSubscriptionController(db *gorm.DB) *SchoolSubscriptionController {
+	return &SchoolSubscriptionController{
+		DB:        db,
+		Validator: validator.New(),
+		Config:    svc.LoadBillingConfig(),
+	}
+}
+
+// fiber.Error dari service → JsonError; selain itu 500
+func writeErr(c *fiber.Ctx, err error) error {
+	var fe *fiber.Error
+	if errors.As(err, &fe) {
+		return helper.JsonError(c, fe.Code, fe.Message)
+	}
+	return helper.JsonError(c, fiber.StatusInternalServerError, err.Error())
+}
+
+func resolveAdminSchool(c *fiber.Ctx) (uuid.UUID, error) {
+	schoolID, err := helperAuth.ResolveSchoolIDFromContext(c)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if err := helperAuth.EnsureDKMSchool(c, schoolID); err != nil {
+		return uuid.Nil, err
+	}
+	return schoolID, nil
+}
+
+/* =======================================================================
+   ADMIN (per school)
+======================================================================= */
+
+// GET /api/a/subscription
+func (h *SchoolSubscriptionController) GetMine(c *fiber.Ctx) error {
+	schoolID, err := resolveAdminSchool(c)
+	if err != nil {
+		return err
+	}
+
+	sub, err := svc.GetCurrentSubscription(c.Context(), h.DB, schoolID)
+	if err != nil {
+		return writeErr(c, err)
+	}
+
+	var open []model.SchoolServiceInvoiceModel
+	if err := h.DB.WithContext(c.Context()).
+		Where("school_service_invoice_school_id = ? AND school_service_invoice_status IN ('open','overdue') AND school_service_invoice_deleted_at IS NULL", schoolID).
+		Order("school_service_invoice_due_at ASC").
+		Find(&open).Error; err != nil {
+		return writeErr(c, err)
+	}
+
+	return helper.JsonOK(c, "ok", fiber.Map{
+		"subscription":  dto.FromSubscriptionModel(sub),
+		"open_invoices": dto.FromInvoiceModels(open),
+	})
+}
+
+// POST /api/a/subscription
+func (h *SchoolSubscriptionController) Subscribe(c *fiber.Ctx) error {
+	schoolID, err := resolveAdminSchool(c)
+	if err != nil {
+		return err
+	}
+
+	var req dto.SubscribeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "invalid json: "+err.Error())
+	}
+	req.Normalize()
+	if err := h.Validator.Struct(&req); err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	res, err := svc.Subscribe(c.Context(), h.DB, h.Config, schoolID, req.PlanID, req.BillingCycle, time.Now())
+	if err != nil {
+		return writeErr(c, err)
+	}
+
+	return helper.JsonCreated(c, "subscription created", fiber.Map{
+		"subscription": dto.FromSubscriptionModel(res.Subscription),
+		"invoice":      dto.FromInvoiceModel(res.Invoice),
+	})
+}
+
+// POST /api/a/subscription/cancel — berhenti di akhir periode berjalan
+func (h *SchoolSubscriptionController) Cancel(c *fiber.Ctx) error {
+	schoolID, err := resolveAdminSchool(c)
+	if err != nil {
+		return err
+	}
+
+	sub, err := svc.CancelAtPeriodEnd(c.Context(), h.DB, schoolID, time.Now())
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonUpdated(c, "subscription will end at current period end", dto.FromSubscriptionModel(sub))
+}
+
+// GET /api/a/subscription/invoices
+func (h *SchoolSubscriptionController) ListMyInvoices(c *fiber.Ctx) error {
+	schoolID, err := resolveAdminSchool(c)
+	if err != nil {
+		return err
+	}
+	return h.listInvoices(c, &schoolID)
+}
+
+// POST /api/a/subscription/invoices/:id/pay — checkout Midtrans (revenue platform)
+func (h *SchoolSubscriptionController) PayInvoice(c *fiber.Ctx) error {
+	schoolID, err := resolveAdminSchool(c)
+	if err != nil {
+		return err
+	}
+	invoiceID, err := uuid.Parse(strings.TrimSpace(c.Params("id")))
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "invalid id")
+	}
+
+	var payer *uuid.UUID
+	if uid, err := helperAuth.GetUserIDFromToken(c); err == nil && uid != uuid.Nil {
+		payer = &uid
+	}
+
+	p, inv, err := svc.CreateInvoicePayment(c.Context(), h.DB, invoiceID, &schoolID, payer, paymodel.PaymentMethodGateway, nil)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonCreated(c, "invoice payment created", dto.FromInvoicePayment(inv, p))
+}
+
+/* =======================================================================
+   Shared list
+======================================================================= */
+
+func (h *SchoolSubscriptionController) listInvoices(c *fiber.Ctx, schoolID *uuid.UUID) error {
+	pg := helper.ResolvePaging(c, 20, 200)
+
+	q := h.DB.WithContext(c.Context()).Model(&model.SchoolServiceInvoiceModel{}).
+		Where("school_service_invoice_deleted_at IS NULL")
+	if schoolID != nil {
+		q = q.Where("school_service_invoice_school_id = ?", *schoolID)
+	} else if s := strings.TrimSpace(c.Query("school_id")); s != "" {
+		if id, err := uuid.Parse(s); err == nil {
+			q = q.Where("school_service_invoice_school_id = ?", id)
+		}
+	}
+	if s := strings.ToLower(strings.TrimSpace(c.Query("status"))); s != "" {
+		q = q.Where("school_service_invoice_status = ?", s)
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return writeErr(c, err)
+	}
+
+	var rows []model.SchoolServiceInvoiceModel
+	if err := q.Order("school_service_invoice_period_start DESC").
+		Limit(pg.Limit).Offset(pg.Offset).
+		Find(&rows).Error; err != nil {
+		return writeErr(c, err)
+	}
+
+	return helper.JsonList(c, "ok", dto.FromInvoiceModels(rows), helper.BuildPaginationFromPage(total, pg.Page, pg.PerPage))
+}
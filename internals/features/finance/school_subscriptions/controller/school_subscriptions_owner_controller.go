@@ -0,0 +1,109 @@
+// file: internals/features/finance/school_subscriptions/controller/school_subscriptions_owner_controller.go
+package controller
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	dto "madinahsalam_backend/internals/features/finance/school_subscriptions/dto"
+	model "madinahsalam_backend/internals/features/finance/school_subscriptions/model"
+	svc "madinahsalam_backend/internals/features/finance/school_subscriptions/service"
+	helper "madinahsalam_backend/internals/helpers"
+	helperAuth "madinahsalam_backend/internals/helpers/auth"
+)
+
+/* =======================================================================
+   OWNER (global)
+======================================================================= */
+
+// GET /api/o/school-subscriptions/list?status=&school_id=
+func (h *SchoolSubscriptionController) OwnerList(c *fiber.Ctx) error {
+	pg := helper.ResolvePaging(c, 20, 200)
+
+	q := h.DB.WithContext(c.Context()).Model(&model.SchoolServiceSubscriptionModel{}).
+		Where("school_service_subscription_deleted_at IS NULL")
+
+	// default: hanya langganan current
+	if !strings.EqualFold(c.Query("include_history"), "true") {
+		q = q.Where("school_service_subscription_end_at IS NULL")
+	}
+	if s := strings.ToLower(strings.TrimSpace(c.Query("status"))); s != "" {
+		q = q.Where("school_service_subscription_status = ?", s)
+	}
+	if s := strings.TrimSpace(c.Query("school_id")); s != "" {
+		if id, err := uuid.Parse(s); err == nil {
+			q = q.Where("school_service_subscription_school_id = ?", id)
+		}
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return writeErr(c, err)
+	}
+	var rows []model.SchoolServiceSubscriptionModel
+	if err := q.Order("school_service_subscription_start_at DESC").
+		Limit(pg.Limit).Offset(pg.Offset).
+		Find(&rows).Error; err != nil {
+		return writeErr(c, err)
+	}
+
+	return helper.JsonList(c, "ok", dto.FromSubscriptionModels(rows), helper.BuildPaginationFromPage(total, pg.Page, pg.PerPage))
+}
+
+// GET /api/o/school-subscriptions/invoices?status=&school_id=
+func (h *SchoolSubscriptionController) OwnerListInvoices(c *fiber.Ctx) error {
+	return h.listInvoices(c, nil)
+}
+
+// GET /api/o/school-subscriptions/metrics?window_days=30
+func (h *SchoolSubscriptionController) OwnerMetrics(c *fiber.Ctx) error {
+	window, _ := strconv.Atoi(strings.TrimSpace(c.Query("window_days", "30")))
+	m, err := svc.ComputeMetrics(c.Context(), h.DB, time.Now(), window)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonOK(c, "ok", m)
+}
+
+// POST /api/o/school-subscriptions/invoices/:id/mark-paid
+func (h *SchoolSubscriptionController) OwnerMarkInvoicePaid(c *fiber.Ctx) error {
+	invoiceID, err := uuid.Parse(strings.TrimSpace(c.Params("id")))
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "invalid id")
+	}
+
+	var req dto.MarkInvoicePaidRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return helper.JsonError(c, fiber.StatusBadRequest, "invalid json: "+err.Error())
+		}
+	}
+	req.Normalize()
+	if err := h.Validator.Struct(&req); err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	var verifier *uuid.UUID
+	if uid, err := helperAuth.GetUserIDFromToken(c); err == nil && uid != uuid.Nil {
+		verifier = &uid
+	}
+
+	p, inv, err := svc.CreateInvoicePayment(c.Context(), h.DB, invoiceID, nil, verifier, req.PaymentMethod, req.ManualReference)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonUpdated(c, "invoice marked as paid", dto.FromInvoicePayment(inv, p))
+}
+
+// POST /api/o/school-subscriptions/run-billing — jalankan siklus billing sekarang (manual)
+func (h *SchoolSubscriptionController) OwnerRunBilling(c *fiber.Ctx) error {
+	res, err := svc.RunBillingCycle(c.Context(), h.DB, h.Config, time.Now())
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonOK(c, "billing cycle processed", res)
+}
@@ -0,0 +1,195 @@
+// file: internals/features/finance/school_subscriptions/dto/school_subscriptions_dto.go
+package dto
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	paymodel "madinahsalam_backend/internals/features/finance/payments/model"
+	model "madinahsalam_backend/internals/features/finance/school_subscriptions/model"
+)
+
+/* =========================================================
+   REQUESTS
+========================================================= */
+
+// POST /api/a/subscription — pilih / ganti plan
+type SubscribeRequest struct {
+	PlanID       uuid.UUID          `json:"plan_id" validate:"required"`
+	BillingCycle model.BillingCycle `json:"billing_cycle" validate:"omitempty,oneof=monthly yearly"`
+}
+
+func (r *SubscribeRequest) Normalize() {
+	r.BillingCycle = model.BillingCycle(strings.ToLower(strings.TrimSpace(string(r.BillingCycle))))
+	if r.BillingCycle == "" {
+		r.BillingCycle = model.BillingCycleMonthly
+	}
+}
+
+// POST /api/o/school-subscriptions/invoices/:id/mark-paid — pelunasan manual (transfer dsb)
+type MarkInvoicePaidRequest struct {
+	PaymentMethod   paymodel.PaymentMethod `json:"payment_method" validate:"omitempty,oneof=bank_transfer cash qris other"`
+	ManualReference *string                `json:"manual_reference" validate:"omitempty,max=120"`
+}
+
+func (r *MarkInvoicePaidRequest) Normalize() {
+	r.PaymentMethod = paymodel.PaymentMethod(strings.ToLower(strings.TrimSpace(string(r.PaymentMethod))))
+	if r.PaymentMethod == "" {
+		r.PaymentMethod = paymodel.PaymentMethodBankTransfer
+	}
+	if r.ManualReference != nil {
+		v := strings.TrimSpace(*r.ManualReference)
+		if v == "" {
+			r.ManualReference = nil
+		} else {
+			r.ManualReference = &v
+		}
+	}
+}
+
+/* =========================================================
+   RESPONSES
+========================================================= */
+
+type SubscriptionResponse struct {
+	SchoolServiceSubscriptionID        uuid.UUID  `json:"school_service_subscription_id"`
+	SchoolServiceSubscriptionSchoolID  uuid.UUID  `json:"school_service_subscription_school_id"`
+	SchoolServiceSubscriptionPlanID    uuid.UUID  `json:"school_service_subscription_plan_id"`
+	SchoolServiceSubscriptionPlanName  string     `json:"school_service_subscription_name_plan_snapshot"`
+	SchoolServiceSubscriptionStatus    string     `json:"school_service_subscription_status"`
+	SchoolServiceSubscriptionCycle     string     `json:"school_service_subscription_billing_cycle"`
+	SchoolServiceSubscriptionAutoRenew bool       `json:"school_service_subscription_is_auto_renew"`
+	SchoolServiceSubscriptionStartAt   time.Time  `json:"school_service_subscription_start_at"`
+	SchoolServiceSubscriptionEndAt     *time.Time `json:"school_service_subscription_end_at,omitempty"`
+	SchoolServiceSubscriptionTrialEnd  *time.Time `json:"school_service_subscription_trial_end_at,omitempty"`
+
+	SchoolServiceSubscriptionCurrentPeriodStart *time.Time `json:"school_service_subscription_current_period_start,omitempty"`
+	SchoolServiceSubscriptionCurrentPeriodEnd   *time.Time `json:"school_service_subscription_current_period_end,omitempty"`
+	SchoolServiceSubscriptionGraceUntil         *time.Time `json:"school_service_subscription_grace_until,omitempty"`
+	SchoolServiceSubscriptionLapsedAt           *time.Time `json:"school_service_subscription_lapsed_at,omitempty"`
+	SchoolServiceSubscriptionLapseAction        string     `json:"school_service_subscription_lapse_action"`
+	SchoolServiceSubscriptionCanceledAt         *time.Time `json:"school_service_subscription_canceled_at,omitempty"`
+
+	SchoolServiceSubscriptionPriceMonthly *float64 `json:"school_service_subscription_price_monthly,omitempty"`
+	SchoolServiceSubscriptionPriceYearly  *float64 `json:"school_service_subscription_price_yearly,omitempty"`
+
+	// turunan
+	IsReadOnly bool `json:"is_read_only"`
+}
+
+func FromSubscriptionModel(m *model.SchoolServiceSubscriptionModel) *SubscriptionResponse {
+	if m == nil {
+		return nil
+	}
+	return &SubscriptionResponse{
+		SchoolServiceSubscriptionID:        m.SchoolServiceSubscriptionID,
+		SchoolServiceSubscriptionSchoolID:  m.SchoolServiceSubscriptionSchoolID,
+		SchoolServiceSubscriptionPlanID:    m.SchoolServiceSubscriptionPlanID,
+		SchoolServiceSubscriptionPlanName:  m.SchoolServiceSubscriptionNamePlanSnapshot,
+		SchoolServiceSubscriptionStatus:    string(m.SchoolServiceSubscriptionStatus),
+		SchoolServiceSubscriptionCycle:     string(m.SchoolServiceSubscriptionBillingCycle),
+		SchoolServiceSubscriptionAutoRenew: m.SchoolServiceSubscriptionIsAutoRenew,
+		SchoolServiceSubscriptionStartAt:   m.SchoolServiceSubscriptionStartAt,
+		SchoolServiceSubscriptionEndAt:     m.SchoolServiceSubscriptionEndAt,
+		SchoolServiceSubscriptionTrialEnd:  m.SchoolServiceSubscriptionTrialEndAt,
+
+		SchoolServiceSubscriptionCurrentPeriodStart: m.SchoolServiceSubscriptionCurrentPeriodStart,
+		SchoolServiceSubscriptionCurrentPeriodEnd:   m.SchoolServiceSubscriptionCurrentPeriodEnd,
+		SchoolServiceSubscriptionGraceUntil:         m.SchoolServiceSubscriptionGraceUntil,
+		SchoolServiceSubscriptionLapsedAt:           m.SchoolServiceSubscriptionLapsedAt,
+		SchoolServiceSubscriptionLapseAction:        string(m.SchoolServiceSubscriptionLapseAction),
+		SchoolServiceSubscriptionCanceledAt:         m.SchoolServiceSubscriptionCanceledAt,
+
+		SchoolServiceSubscriptionPriceMonthly: m.SchoolServiceSubscriptionPriceMonthly,
+		SchoolServiceSubscriptionPriceYearly:  m.SchoolServiceSubscriptionPriceYearly,
+
+		IsReadOnly: m.SchoolServiceSubscriptionStatus == model.SubscriptionStatusExpired &&
+			m.SchoolServiceSubscriptionLapseAction == model.LapseActionReadOnly &&
+			m.SchoolServiceSubscriptionEndAt == nil,
+	}
+}
+
+func FromSubscriptionModels(rows []model.SchoolServiceSubscriptionModel) []SubscriptionResponse {
+	out := make([]SubscriptionResponse, 0, len(rows))
+	for i := range rows {
+		out = append(out, *FromSubscriptionModel(&rows[i]))
+	}
+	return out
+}
+
+type InvoiceResponse struct {
+	SchoolServiceInvoiceID             uuid.UUID  `json:"school_service_invoice_id"`
+	SchoolServiceInvoiceSchoolID       uuid.UUID  `json:"school_service_invoice_school_id"`
+	SchoolServiceInvoiceSubscriptionID uuid.UUID  `json:"school_service_invoice_subscription_id"`
+	SchoolServiceInvoicePlanID         uuid.UUID  `json:"school_service_invoice_plan_id"`
+	SchoolServiceInvoiceNumber         string     `json:"school_service_invoice_number"`
+	SchoolServiceInvoiceBillingCycle   string     `json:"school_service_invoice_billing_cycle"`
+	SchoolServiceInvoicePeriodStart    time.Time  `json:"school_service_invoice_period_start"`
+	SchoolServiceInvoicePeriodEnd      time.Time  `json:"school_service_invoice_period_end"`
+	SchoolServiceInvoiceAmountIDR      int        `json:"school_service_invoice_amount_idr"`
+	SchoolServiceInvoiceStatus         string     `json:"school_service_invoice_status"`
+	SchoolServiceInvoiceDueAt          time.Time  `json:"school_service_invoice_due_at"`
+	SchoolServiceInvoicePaidAt         *time.Time `json:"school_service_invoice_paid_at,omitempty"`
+	SchoolServiceInvoicePaymentID      *uuid.UUID `json:"school_service_invoice_payment_id,omitempty"`
+	SchoolServiceInvoicePlanName       string     `json:"school_service_invoice_plan_name_snapshot"`
+	SchoolServiceInvoiceSchoolName     *string    `json:"school_service_invoice_school_name_snapshot,omitempty"`
+	SchoolServiceInvoiceCreatedAt      time.Time  `json:"school_service_invoice_created_at"`
+}
+
+func FromInvoiceModel(m *model.SchoolServiceInvoiceModel) *InvoiceResponse {
+	if m == nil {
+		return nil
+	}
+	return &InvoiceResponse{
+		SchoolServiceInvoiceID:             m.SchoolServiceInvoiceID,
+		SchoolServiceInvoiceSchoolID:       m.SchoolServiceInvoiceSchoolID,
+		SchoolServiceInvoiceSubscriptionID: m.SchoolServiceInvoiceSubscriptionID,
+		SchoolServiceInvoicePlanID:         m.SchoolServiceInvoicePlanID,
+		SchoolServiceInvoiceNumber:         m.SchoolServiceInvoiceNumber,
+		SchoolServiceInvoiceBillingCycle:   string(m.SchoolServiceInvoiceBillingCycle),
+		SchoolServiceInvoicePeriodStart:    m.SchoolServiceInvoicePeriodStart,
+		SchoolServiceInvoicePeriodEnd:      m.SchoolServiceInvoicePeriodEnd,
+		SchoolServiceInvoiceAmountIDR:      m.SchoolServiceInvoiceAmountIDR,
+		SchoolServiceInvoiceStatus:         string(m.SchoolServiceInvoiceStatus),
+		SchoolServiceInvoiceDueAt:          m.SchoolServiceInvoiceDueAt,
+		SchoolServiceInvoicePaidAt:         m.SchoolServiceInvoicePaidAt,
+		SchoolServiceInvoicePaymentID:      m.SchoolServiceInvoicePaymentID,
+		SchoolServiceInvoicePlanName:       m.SchoolServiceInvoicePlanNameSnapshot,
+		SchoolServiceInvoiceSchoolName:     m.SchoolServiceInvoiceSchoolNameSnapshot,
+		SchoolServiceInvoiceCreatedAt:      m.SchoolServiceInvoiceCreatedAt,
+	}
+}
+
+func FromInvoiceModels(rows []model.SchoolServiceInvoiceModel) []InvoiceResponse {
+	out := make([]InvoiceResponse, 0, len(rows))
+	for i := range rows {
+		out = append(out, *FromInvoiceModel(&rows[i]))
+	}
+	return out
+}
+
+// Payment ringkas untuk checkout invoice
+type InvoicePaymentResponse struct {
+	Invoice            *InvoiceResponse `json:"invoice"`
+	PaymentID          uuid.UUID        `json:"payment_id"`
+	PaymentStatus      string           `json:"payment_status"`
+	PaymentAmountIDR   int              `json:"payment_amount_idr"`
+	PaymentExternalID  *string          `json:"payment_external_id,omitempty"`
+	PaymentCheckoutURL *string          `json:"payment_checkout_url,omitempty"`
+	PaymentSnapToken   *string          `json:"payment_snap_token,omitempty"`
+}
+
+func FromInvoicePayment(inv *model.SchoolServiceInvoiceModel, p *paymodel.PaymentModel) InvoicePaymentResponse {
+	out := InvoicePaymentResponse{Invoice: FromInvoiceModel(inv)}
+	if p != nil {
+		out.PaymentID = p.PaymentID
+		out.PaymentStatus = string(p.PaymentStatus)
+		out.PaymentAmountIDR = p.PaymentAmountIDR
+		out.PaymentExternalID = p.PaymentExternalID
+		out.PaymentCheckoutURL = p.PaymentCheckoutURL
+		out.PaymentSnapToken = p.PaymentGatewayRef
+	}
+	return out
+}
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/*
+  school_service_invoices = tagihan platform ke sekolah per periode langganan
+  - dibayar lewat payments (payment_school_id = NULL → revenue platform)
+  - status: open → paid | overdue | void
+*/
+
+type InvoiceStatus string
+
+const (
+	InvoiceStatusOpen    InvoiceStatus = "open"
+	InvoiceStatusPaid    InvoiceStatus = "paid"
+	InvoiceStatusOverdue InvoiceStatus = "overdue"
+	InvoiceStatusVoid    InvoiceStatus = "void"
+)
+
+type SchoolServiceInvoiceModel struct {
+	SchoolServiceInvoiceID uuid.UUID `gorm:"column:school_service_invoice_id;type:uuid;default:gen_random_uuid();primaryKey" json:"school_service_invoice_id"`
+
+	SchoolServiceInvoiceSchoolID       uuid.UUID `gorm:"column:school_service_invoice_school_id;type:uuid;not null" json:"school_service_invoice_school_id"`
+	SchoolServiceInvoiceSubscriptionID uuid.UUID `gorm:"column:school_service_invoice_subscription_id;type:uuid;not null" json:"school_service_invoice_subscription_id"`
+	SchoolServiceInvoicePlanID         uuid.UUID `gorm:"column:school_service_invoice_plan_id;type:uuid;not null" json:"school_service_invoice_plan_id"`
+
+	SchoolServiceInvoiceNumber string `gorm:"column:school_service_invoice_number;type:varchar(40);not null" json:"school_service_invoice_number"`
+
+	// Periode yang ditagih
+	SchoolServiceInvoiceBillingCycle BillingCycle `gorm:"column:school_service_invoice_billing_cycle;type:varchar(10);not null" json:"school_service_invoice_billing_cycle"`
+	SchoolServiceInvoicePeriodStart  time.Time    `gorm:"column:school_service_invoice_period_start;not null" json:"school_service_invoice_period_start"`
+	SchoolServiceInvoicePeriodEnd    time.Time    `gorm:"column:school_service_invoice_period_end;not null" json:"school_service_invoice_period_end"`
+
+	SchoolServiceInvoiceAmountIDR int `gorm:"column:school_service_invoice_amount_idr;not null" json:"school_service_invoice_amount_idr"`
+
+	SchoolServiceInvoiceStatus InvoiceStatus `gorm:"column:school_service_invoice_status;type:school_service_invoice_status_enum;not null;default:'open'" json:"school_service_invoice_status"`
+	SchoolServiceInvoiceDueAt  time.Time     `gorm:"column:school_service_invoice_due_at;not null" json:"school_service_invoice_due_at"`
+	SchoolServiceInvoicePaidAt *time.Time    `gorm:"column:school_service_invoice_paid_at" json:"school_service_invoice_paid_at"`
+
+	// Payment header (payments.payment_school_id = NULL)
+	SchoolServiceInvoicePaymentID *uuid.UUID `gorm:"column:school_service_invoice_payment_id;type:uuid" json:"school_service_invoice_payment_id"`
+
+	// Snapshot
+	SchoolServiceInvoicePlanNameSnapshot   string  `gorm:"column:school_service_invoice_plan_name_snapshot;type:varchar(100);not null" json:"school_service_invoice_plan_name_snapshot"`
+	SchoolServiceInvoiceSchoolNameSnapshot *string `gorm:"column:school_service_invoice_school_name_snapshot;type:varchar(100)" json:"school_service_invoice_school_name_snapshot"`
+
+	SchoolServiceInvoiceCreatedAt time.Time  `gorm:"column:school_service_invoice_created_at;not null;default:now()" json:"school_service_invoice_created_at"`
+	SchoolServiceInvoiceUpdatedAt time.Time  `gorm:"column:school_service_invoice_updated_at;not null;default:now()" json:"school_service_invoice_updated_at"`
+	SchoolServiceInvoiceDeletedAt *time.Time `gorm:"column:school_service_invoice_deleted_at" json:"school_service_invoice_deleted_at"`
+}
+
+func (SchoolServiceInvoiceModel) TableName() string {
+	return "school_service_invoices"
+}
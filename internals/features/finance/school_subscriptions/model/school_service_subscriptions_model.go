@@ -0,0 +1,83 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/*
+  school_service_subscriptions = langganan paket platform per sekolah
+  - maks. 1 baris "current" (end_at IS NULL) per sekolah
+  - status: trial → active ⇄ grace → expired / canceled
+  - periode berjalan (current_period_*) diperpanjang saat invoice lunas
+*/
+
+type SubscriptionStatus string
+type BillingCycle string
+type LapseAction string
+
+const (
+	SubscriptionStatusTrial    SubscriptionStatus = "trial"
+	SubscriptionStatusActive   SubscriptionStatus = "active"
+	SubscriptionStatusGrace    SubscriptionStatus = "grace"
+	SubscriptionStatusCanceled SubscriptionStatus = "canceled"
+	SubscriptionStatusExpired  SubscriptionStatus = "expired"
+)
+
+const (
+	BillingCycleMonthly BillingCycle = "monthly"
+	BillingCycleYearly  BillingCycle = "yearly"
+)
+
+const (
+	LapseActionDowngrade LapseAction = "downgrade"
+	LapseActionReadOnly  LapseAction = "read_only"
+)
+
+type SchoolServiceSubscriptionModel struct {
+	SchoolServiceSubscriptionID uuid.UUID `gorm:"column:school_service_subscription_id;type:uuid;default:gen_random_uuid();primaryKey" json:"school_service_subscription_id"`
+
+	SchoolServiceSubscriptionSchoolID uuid.UUID `gorm:"column:school_service_subscription_school_id;type:uuid;not null" json:"school_service_subscription_school_id"`
+	SchoolServiceSubscriptionPlanID   uuid.UUID `gorm:"column:school_service_subscription_plan_id;type:uuid;not null" json:"school_service_subscription_plan_id"`
+
+	SchoolServiceSubscriptionStatus      SubscriptionStatus `gorm:"column:school_service_subscription_status;type:school_subscription_status_enum;not null;default:'active'" json:"school_service_subscription_status"`
+	SchoolServiceSubscriptionIsAutoRenew bool               `gorm:"column:school_service_subscription_is_auto_renew;not null;default:false" json:"school_service_subscription_is_auto_renew"`
+
+	SchoolServiceSubscriptionStartAt    time.Time  `gorm:"column:school_service_subscription_start_at;not null;default:now()" json:"school_service_subscription_start_at"`
+	SchoolServiceSubscriptionEndAt      *time.Time `gorm:"column:school_service_subscription_end_at" json:"school_service_subscription_end_at"`
+	SchoolServiceSubscriptionTrialEndAt *time.Time `gorm:"column:school_service_subscription_trial_end_at" json:"school_service_subscription_trial_end_at"`
+
+	// Snapshot harga saat checkout
+	SchoolServiceSubscriptionPriceMonthly *float64 `gorm:"column:school_service_subscription_price_monthly;type:numeric(12,2)" json:"school_service_subscription_price_monthly"`
+	SchoolServiceSubscriptionPriceYearly  *float64 `gorm:"column:school_service_subscription_price_yearly;type:numeric(12,2)" json:"school_service_subscription_price_yearly"`
+
+	// Metadata billing
+	SchoolServiceSubscriptionProvider      *string    `gorm:"column:school_service_subscription_provider;type:varchar(40)" json:"school_service_subscription_provider"`
+	SchoolServiceSubscriptionProviderRefID *string    `gorm:"column:school_service_subscription_provider_ref_id;type:varchar(100)" json:"school_service_subscription_provider_ref_id"`
+	SchoolServiceSubscriptionCanceledAt    *time.Time `gorm:"column:school_service_subscription_canceled_at" json:"school_service_subscription_canceled_at"`
+
+	// Siklus & periode berjalan
+	SchoolServiceSubscriptionBillingCycle       BillingCycle `gorm:"column:school_service_subscription_billing_cycle;type:varchar(10);not null;default:'monthly'" json:"school_service_subscription_billing_cycle"`
+	SchoolServiceSubscriptionCurrentPeriodStart *time.Time   `gorm:"column:school_service_subscription_current_period_start" json:"school_service_subscription_current_period_start"`
+	SchoolServiceSubscriptionCurrentPeriodEnd   *time.Time   `gorm:"column:school_service_subscription_current_period_end" json:"school_service_subscription_current_period_end"`
+	SchoolServiceSubscriptionGraceUntil         *time.Time   `gorm:"column:school_service_subscription_grace_until" json:"school_service_subscription_grace_until"`
+	SchoolServiceSubscriptionLapsedAt           *time.Time   `gorm:"column:school_service_subscription_lapsed_at" json:"school_service_subscription_lapsed_at"`
+	SchoolServiceSubscriptionLapseAction        LapseAction  `gorm:"column:school_service_subscription_lapse_action;type:varchar(16);not null;default:'downgrade'" json:"school_service_subscription_lapse_action"`
+
+	// Override kuota (NULL = ikut plan)
+	SchoolServiceSubscriptionMaxTeachersOverride     *int `gorm:"column:school_service_subscription_max_teachers_override" json:"school_service_subscription_max_teachers_override"`
+	SchoolServiceSubscriptionMaxStudentsOverride     *int `gorm:"column:school_service_subscription_max_students_override" json:"school_service_subscription_max_students_override"`
+	SchoolServiceSubscriptionMaxStorageMBOverride    *int `gorm:"column:school_service_subscription_max_storage_mb_override" json:"school_service_subscription_max_storage_mb_override"`
+	SchoolServiceSubscriptionMaxCustomThemesOverride *int `gorm:"column:school_service_subscription_max_custom_themes_override" json:"school_service_subscription_max_custom_themes_override"`
+
+	SchoolServiceSubscriptionNamePlanSnapshot string `gorm:"column:school_service_subscription_name_plan_snapshot;type:varchar(100);not null" json:"school_service_subscription_name_plan_snapshot"`
+
+	SchoolServiceSubscriptionCreatedAt time.Time  `gorm:"column:school_service_subscription_created_at;not null;default:now()" json:"school_service_subscription_created_at"`
+	SchoolServiceSubscriptionUpdatedAt time.Time  `gorm:"column:school_service_subscription_updated_at;not null;default:now()" json:"school_service_subscription_updated_at"`
+	SchoolServiceSubscriptionDeletedAt *time.Time `gorm:"column:school_service_subscription_deleted_at" json:"school_service_subscription_deleted_at"`
+}
+
+func (SchoolServiceSubscriptionModel) TableName() string {
+	return "school_service_subscriptions"
+}
@@ -0,0 +1,30 @@
+package route
+
+import (
+	subsController "madinahsalam_backend/internals/features/finance/school_subscriptions/controller"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+/*
+Admin routes: langganan platform milik sekolah
+Contoh mount: SchoolSubscriptionAdminRoutes(app.Group("/api/a"), db)
+Final paths:
+- /api/a/subscription ...
+*/
+func SchoolSubscriptionAdminRoutes(r fiber.Router, db *gorm.DB) {
+	ctl := subsController.NewSchoolSubscriptionController(db)
+
+	sub := r.Group("/subscription")
+
+	// status langganan berjalan + pilih / ganti plan
+	sub.Get("/", ctl.GetMine)
+	sub.Post("/", ctl.Subscribe)
+	// berhenti di akhir periode
+	sub.Post("/cancel", ctl.Cancel)
+
+	// invoice milik sekolah
+	sub.Get("/invoices", ctl.ListMyInvoices)
+	sub.Post("/invoices/:id/pay", ctl.PayInvoice)
+}
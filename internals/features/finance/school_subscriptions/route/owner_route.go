@@ -0,0 +1,29 @@
+package route
+
+import (
+	subsController "madinahsalam_backend/internals/features/finance/school_subscriptions/controller"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+/*
+Owner routes: billing langganan seluruh sekolah
+Contoh mount: SchoolSubscriptionOwnerRoutes(app.Group("/api/o"), db)
+Final paths:
+- /api/o/school-subscriptions ...
+*/
+func SchoolSubscriptionOwnerRoutes(r fiber.Router, db *gorm.DB) {
+	ctl := subsController.NewSchoolSubscriptionController(db)
+
+	g := r.Group("/school-subscriptions")
+
+	g.Get("/list", ctl.OwnerList)
+	g.Get("/metrics", ctl.OwnerMetrics) // MRR, churn, dsb
+
+	g.Get("/invoices", ctl.OwnerListInvoices)
+	g.Post("/invoices/:id/mark-paid", ctl.OwnerMarkInvoicePaid)
+
+	// jalankan siklus billing manual (biasanya via cron)
+	g.Post("/run-billing", ctl.OwnerRunBilling)
+}
@@ -0,0 +1,42 @@
+// internals/features/finance/school_subscriptions/scheduler/school_subscription_scheduler.go
+package scheduler
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"gorm.io/gorm"
+
+	svc "madinahsalam_backend/internals/features/finance/school_subscriptions/service"
+)
+
+// Jalan harian: trial → invoice, perpanjangan H-lead, grace, lapse (downgrade / read-only)
+func StartSchoolSubscriptionScheduler(db *gorm.DB) {
+	schedule := os.Getenv("SUBSCRIPTION_BILLING_CRON")
+	if schedule == "" {
+		schedule = "30 1 * * *"
+	}
+	cfg := svc.LoadBillingConfig()
+
+	c := cron.New(cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger)))
+	_, err := c.AddFunc(schedule, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 4*time.Minute)
+		defer cancel()
+
+		res, err := svc.RunBillingCycle(ctx, db, cfg, time.Now())
+		if err != nil {
+			log.Printf("[SUB-BILLING] error: %v", err)
+			return
+		}
+		log.Printf("[SUB-BILLING] trials=%d renewals=%d grace=%d canceled=%d lapsed=%d overdue=%d",
+			res.TrialsConverted, res.RenewalsInvoiced, res.MovedToGrace, res.Canceled, res.Lapsed, res.InvoicesOverdue)
+	})
+	if err != nil {
+		log.Fatalf("[SUB-BILLING] add cron gagal: %v", err)
+	}
+	log.Printf("[SUB-BILLING] started schedule=%q", schedule)
+	c.Start()
+}
@@ -0,0 +1,822 @@
+// file: internals/features/finance/school_subscriptions/service/school_subscription_service.go
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	paymodel "madinahsalam_backend/internals/features/finance/payments/model"
+	paysvc "madinahsalam_backend/internals/features/finance/payments/service"
+	model "madinahsalam_backend/internals/features/finance/school_subscriptions/model"
+	schoolModel "madinahsalam_backend/internals/features/lembaga/school_yayasans/schools/model"
+)
+
+/* =========================================================
+   Config (ENV)
+========================================================= */
+
+type BillingConfig struct {
+	TrialDays       int               // SUBSCRIPTION_TRIAL_DAYS
+	GraceDays       int               // SUBSCRIPTION_GRACE_DAYS
+	InvoiceLeadDays int               // SUBSCRIPTION_INVOICE_LEAD_DAYS (invoice perpanjangan dibuat H-n)
+	LapseAction     model.LapseAction // SUBSCRIPTION_LAPSE_ACTION: downgrade | read_only
+	FreePlanCode    string            // SUBSCRIPTION_FREE_PLAN_CODE (target downgrade)
+}
+
+func envInt(key string, def int) int {
+	if v := strings.TrimSpace(os.Getenv(key)); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			return n
+		}
+	}
+	return def
+}
+
+func envStr(key, def string) string {
+	if v := strings.TrimSpace(os.Getenv(key)); v != "" {
+		return v
+	}
+	return def
+}
+
+func LoadBillingConfig() BillingConfig {
+	action := model.LapseAction(strings.ToLower(envStr("SUBSCRIPTION_LAPSE_ACTION", string(model.LapseActionDowngrade))))
+	if action != model.LapseActionReadOnly {
+		action = model.LapseActionDowngrade
+	}
+	return BillingConfig{
+		TrialDays:       envInt("SUBSCRIPTION_TRIAL_DAYS", 14),
+		GraceDays:       envInt("SUBSCRIPTION_GRACE_DAYS", 7),
+		InvoiceLeadDays: envInt("SUBSCRIPTION_INVOICE_LEAD_DAYS", 7),
+		LapseAction:     action,
+		FreePlanCode:    envStr("SUBSCRIPTION_FREE_PLAN_CODE", "basic"),
+	}
+}
+
+/* =========================================================
+   Plan lookup
+========================================================= */
+
+type planRow struct {
+	ID           uuid.UUID `gorm:"column:school_service_plan_id"`
+	Code         string    `gorm:"column:school_service_plan_code"`
+	Name         string    `gorm:"column:school_service_plan_name"`
+	PriceMonthly *float64  `gorm:"column:school_service_plan_price_monthly"`
+	PriceYearly  *float64  `gorm:"column:school_service_plan_price_yearly"`
+}
+
+func loadActivePlan(tx *gorm.DB, planID uuid.UUID) (*planRow, error) {
+	var p planRow
+	if err := tx.Raw(`
+		SELECT school_service_plan_id, school_service_plan_code, school_service_plan_name,
+		       school_service_plan_price_monthly, school_service_plan_price_yearly
+		  FROM school_service_plans
+		 WHERE school_service_plan_id = ?
+		   AND school_service_plan_is_active = TRUE
+		   AND school_service_plan_deleted_at IS NULL
+		 LIMIT 1
+	`, planID).Scan(&p).Error; err != nil {
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Gagal memuat plan")
+	}
+	if p.ID == uuid.Nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Plan tidak ditemukan / tidak aktif")
+	}
+	return &p, nil
+}
+
+func freePlanID(tx *gorm.DB, code string) (uuid.UUID, error) {
+	var id uuid.UUID
+	err := tx.Raw(`
+		SELECT school_service_plan_id
+		  FROM school_service_plans
+		 WHERE LOWER(school_service_plan_code) = LOWER(?)
+		   AND school_service_plan_deleted_at IS NULL
+		 LIMIT 1
+	`, code).Scan(&id).Error
+	return id, err
+}
+
+// PriceIDR: harga plan per siklus, dibulatkan ke rupiah.
+func PriceIDR(monthly, yearly *float64, cycle model.BillingCycle) int {
+	var v *float64
+	if cycle == model.BillingCycleYearly {
+		v = yearly
+	} else {
+		v = monthly
+	}
+	if v == nil || *v <= 0 {
+		return 0
+	}
+	return int(math.Round(*v))
+}
+
+/* =========================================================
+   Current subscription
+========================================================= */
+
+// GetCurrentSubscription: langganan "current" (end_at NULL) milik sekolah; nil jika belum ada.
+func GetCurrentSubscription(ctx context.Context, db *gorm.DB, schoolID uuid.UUID) (*model.SchoolServiceSubscriptionModel, error) {
+	var m model.SchoolServiceSubscriptionModel
+	err := db.WithContext(ctx).
+		Where("school_service_subscription_school_id = ? AND school_service_subscription_end_at IS NULL AND school_service_subscription_deleted_at IS NULL", schoolID).
+		First(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+/* =========================================================
+   Subscribe / change plan
+========================================================= */
+
+type SubscribeResult struct {
+	Subscription *model.SchoolServiceSubscriptionModel
+	Invoice      *model.SchoolServiceInvoiceModel
+}
+
+// Subscribe memulai langganan baru (atau ganti plan) untuk sekolah.
+// - Ditolak selama langganan current grace/expired atau masih ada invoice open/overdue.
+// - Plan gratis → langsung active tanpa invoice.
+// - Sekolah yang belum pernah berlangganan plan berbayar → trial (TrialDays).
+// - Ganti plan saat trial → sisa trial dipertahankan (tenggat tidak direset).
+// - Selain itu → grace sampai invoice pertama dibayar (tenggat ≥ akhir periode yang sudah dibayar).
+//
+// Plan berbayar: school_current_plan_id baru pindah saat invoice lunas
+// (lihat paysvc.ApplySchoolSubscriptionSideEffects).
+func Subscribe(ctx context.Context, db *gorm.DB, cfg BillingConfig, schoolID, planID uuid.UUID, cycle model.BillingCycle, now time.Time) (*SubscribeResult, error) {
+	if cycle != model.BillingCycleYearly {
+		cycle = model.BillingCycleMonthly
+	}
+
+	out := &SubscribeResult{}
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		plan, err := loadActivePlan(tx, planID)
+		if err != nil {
+			return err
+		}
+		amount := PriceIDR(plan.PriceMonthly, plan.PriceYearly, cycle)
+
+		var cur *model.SchoolServiceSubscriptionModel
+		var curRow model.SchoolServiceSubscriptionModel
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("school_service_subscription_school_id = ? AND school_service_subscription_end_at IS NULL AND school_service_subscription_deleted_at IS NULL", schoolID).
+			First(&curRow).Error
+		switch {
+		case err == nil:
+			cur = &curRow
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return err
+		}
+		if cur != nil {
+			switch cur.SchoolServiceSubscriptionStatus {
+			case model.SubscriptionStatusGrace, model.SubscriptionStatusExpired:
+				return fiber.NewError(fiber.StatusConflict, "Langganan dalam masa tenggang / berakhir; lunasi tagihan berjalan sebelum ganti plan")
+			}
+		}
+
+		var unpaid int64
+		if err := tx.Model(&model.SchoolServiceInvoiceModel{}).
+			Where("school_service_invoice_school_id = ? AND school_service_invoice_status IN ('open','overdue') AND school_service_invoice_deleted_at IS NULL", schoolID).
+			Count(&unpaid).Error; err != nil {
+			return err
+		}
+		if unpaid > 0 {
+			return fiber.NewError(fiber.StatusConflict, "Masih ada tagihan langganan yang belum dibayar; lunasi dulu sebelum ganti plan")
+		}
+
+		// Pernah trial / langganan berbayar?
+		var paidHistory int64
+		if err := tx.Model(&model.SchoolServiceSubscriptionModel{}).
+			Where("school_service_subscription_school_id = ? AND school_service_subscription_deleted_at IS NULL", schoolID).
+			Where("school_service_subscription_trial_end_at IS NOT NULL OR COALESCE(school_service_subscription_price_monthly,0) > 0 OR COALESCE(school_service_subscription_price_yearly,0) > 0").
+			Count(&paidHistory).Error; err != nil {
+			return err
+		}
+
+		// Tutup langganan current (kalau ada)
+		if cur != nil {
+			upd := map[string]any{
+				"school_service_subscription_end_at":     now,
+				"school_service_subscription_status":     model.SubscriptionStatusCanceled,
+				"school_service_subscription_updated_at": now,
+			}
+			if cur.SchoolServiceSubscriptionCanceledAt == nil {
+				upd["school_service_subscription_canceled_at"] = now
+			}
+			if err := tx.Model(cur).Updates(upd).Error; err != nil {
+				return err
+			}
+		}
+
+		sub := model.SchoolServiceSubscriptionModel{
+			SchoolServiceSubscriptionSchoolID:         schoolID,
+			SchoolServiceSubscriptionPlanID:           plan.ID,
+			SchoolServiceSubscriptionStartAt:          now,
+			SchoolServiceSubscriptionPriceMonthly:     plan.PriceMonthly,
+			SchoolServiceSubscriptionPriceYearly:      plan.PriceYearly,
+			SchoolServiceSubscriptionBillingCycle:     cycle,
+			SchoolServiceSubscriptionLapseAction:      cfg.LapseAction,
+			SchoolServiceSubscriptionIsAutoRenew:      true,
+			SchoolServiceSubscriptionNamePlanSnapshot: plan.Name,
+		}
+
+		var invoiceStart, invoiceDue time.Time
+		switch {
+		case amount == 0:
+			sub.SchoolServiceSubscriptionStatus = model.SubscriptionStatusActive
+			sub.SchoolServiceSubscriptionCurrentPeriodStart = &now
+		case cur != nil && cur.SchoolServiceSubscriptionStatus == model.SubscriptionStatusTrial &&
+			cur.SchoolServiceSubscriptionTrialEndAt != nil && cur.SchoolServiceSubscriptionTrialEndAt.After(now):
+			trialEnd := *cur.SchoolServiceSubscriptionTrialEndAt
+			sub.SchoolServiceSubscriptionStatus = model.SubscriptionStatusTrial
+			sub.SchoolServiceSubscriptionTrialEndAt = &trialEnd
+			sub.SchoolServiceSubscriptionCurrentPeriodStart = &now
+			sub.SchoolServiceSubscriptionCurrentPeriodEnd = &trialEnd
+		case paidHistory == 0 && cfg.TrialDays > 0:
+			trialEnd := now.AddDate(0, 0, cfg.TrialDays)
+			sub.SchoolServiceSubscriptionStatus = model.SubscriptionStatusTrial
+			sub.SchoolServiceSubscriptionTrialEndAt = &trialEnd
+			sub.SchoolServiceSubscriptionCurrentPeriodStart = &now
+			sub.SchoolServiceSubscriptionCurrentPeriodEnd = &trialEnd
+		default:
+			graceUntil := now.AddDate(0, 0, cfg.GraceDays)
+			if cur != nil && cur.SchoolServiceSubscriptionCurrentPeriodEnd != nil &&
+				cur.SchoolServiceSubscriptionCurrentPeriodEnd.After(graceUntil) {
+				graceUntil = *cur.SchoolServiceSubscriptionCurrentPeriodEnd
+			}
+			sub.SchoolServiceSubscriptionStatus = model.SubscriptionStatusGrace
+			sub.SchoolServiceSubscriptionGraceUntil = &graceUntil
+			invoiceStart, invoiceDue = now, graceUntil
+		}
+
+		if err := tx.Create(&sub).Error; err != nil {
+			return err
+		}
+
+		// tanpa invoice (gratis/trial) → plan aktif sekolah langsung ikut langganan baru
+		if invoiceStart.IsZero() {
+			if err := setSchoolPlan(tx, schoolID, &plan.ID); err != nil {
+				return err
+			}
+		}
+
+		out.Subscription = &sub
+		if !invoiceStart.IsZero() {
+			inv, _, err := IssueInvoice(tx, &sub, invoiceStart, invoiceDue)
+			if err != nil {
+				return err
+			}
+			out.Invoice = inv
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CancelAtPeriodEnd: matikan auto-renew; langganan berakhir di akhir periode berjalan.
+func CancelAtPeriodEnd(ctx context.Context, db *gorm.DB, schoolID uuid.UUID, now time.Time) (*model.SchoolServiceSubscriptionModel, error) {
+	sub, err := GetCurrentSubscription(ctx, db, schoolID)
+	if err != nil {
+		return nil, err
+	}
+	if sub == nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Sekolah belum memiliki langganan")
+	}
+	if err := db.WithContext(ctx).Model(sub).Updates(map[string]any{
+		"school_service_subscription_is_auto_renew": false,
+		"school_service_subscription_canceled_at":   now,
+		"school_service_subscription_updated_at":    now,
+	}).Error; err != nil {
+		return nil, err
+	}
+	sub.SchoolServiceSubscriptionIsAutoRenew = false
+	sub.SchoolServiceSubscriptionCanceledAt = &now
+	return sub, nil
+}
+
+/* =========================================================
+   Invoice
+========================================================= */
+
+func newInvoiceNumber(now time.Time) string {
+	u := strings.ToUpper(strings.ReplaceAll(uuid.New().String(), "-", ""))
+	return fmt.Sprintf("SUB-%s-%s", now.Format("20060102"), u[:8])
+}
+
+// IssueInvoice membuat invoice untuk 1 siklus mulai periodStart (idempotent per periode).
+// created=false jika invoice periode tsb sudah ada.
+func IssueInvoice(tx *gorm.DB, sub *model.SchoolServiceSubscriptionModel, periodStart, dueAt time.Time) (inv *model.SchoolServiceInvoiceModel, created bool, err error) {
+	var existing model.SchoolServiceInvoiceModel
+	err = tx.Where(`school_service_invoice_subscription_id = ?
+		AND school_service_invoice_period_start = ?
+		AND school_service_invoice_status <> 'void'
+		AND school_service_invoice_deleted_at IS NULL`, sub.SchoolServiceSubscriptionID, periodStart).
+		First(&existing).Error
+	if err == nil {
+		return &existing, false, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, err
+	}
+
+	var schoolName *string
+	_ = tx.Raw(`SELECT school_name FROM schools WHERE school_id = ?`, sub.SchoolServiceSubscriptionSchoolID).Scan(&schoolName).Error
+
+	inv = &model.SchoolServiceInvoiceModel{
+		SchoolServiceInvoiceSchoolID:           sub.SchoolServiceSubscriptionSchoolID,
+		SchoolServiceInvoiceSubscriptionID:     sub.SchoolServiceSubscriptionID,
+		SchoolServiceInvoicePlanID:             sub.SchoolServiceSubscriptionPlanID,
+		SchoolServiceInvoiceNumber:             newInvoiceNumber(periodStart),
+		SchoolServiceInvoiceBillingCycle:       sub.SchoolServiceSubscriptionBillingCycle,
+		SchoolServiceInvoicePeriodStart:        periodStart,
+		SchoolServiceInvoicePeriodEnd:          paysvc.AddBillingCycle(periodStart, string(sub.SchoolServiceSubscriptionBillingCycle)),
+		SchoolServiceInvoiceAmountIDR:          PriceIDR(sub.SchoolServiceSubscriptionPriceMonthly, sub.SchoolServiceSubscriptionPriceYearly, sub.SchoolServiceSubscriptionBillingCycle),
+		SchoolServiceInvoiceStatus:             model.InvoiceStatusOpen,
+		SchoolServiceInvoiceDueAt:              dueAt,
+		SchoolServiceInvoicePlanNameSnapshot:   sub.SchoolServiceSubscriptionNamePlanSnapshot,
+		SchoolServiceInvoiceSchoolNameSnapshot: schoolName,
+	}
+	if err := tx.Create(inv).Error; err != nil {
+		return nil, false, err
+	}
+	return inv, true, nil
+}
+
+// CreateInvoicePayment membuat payment platform (payment_school_id NULL) untuk invoice.
+// method gateway → Snap Midtrans; selain itu → manual paid (dicatat owner).
+func CreateInvoicePayment(ctx context.Context, db *gorm.DB, invoiceID uuid.UUID, schoolID *uuid.UUID, payerUserID *uuid.UUID, method paymodel.PaymentMethod, manualRef *string) (*paymodel.PaymentModel, *model.SchoolServiceInvoiceModel, error) {
+	var inv model.SchoolServiceInvoiceModel
+	q := db.WithContext(ctx).Where("school_service_invoice_id = ? AND school_service_invoice_deleted_at IS NULL", invoiceID)
+	if schoolID != nil {
+		q = q.Where("school_service_invoice_school_id = ?", *schoolID)
+	}
+	if err := q.First(&inv).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, fiber.NewError(fiber.StatusNotFound, "Invoice tidak ditemukan")
+		}
+		return nil, nil, err
+	}
+	switch inv.SchoolServiceInvoiceStatus {
+	case model.InvoiceStatusPaid:
+		return nil, nil, fiber.NewError(fiber.StatusConflict, "Invoice sudah lunas")
+	case model.InvoiceStatusVoid:
+		return nil, nil, fiber.NewError(fiber.StatusConflict, "Invoice sudah dibatalkan")
+	}
+	if inv.SchoolServiceInvoiceAmountIDR <= 0 {
+		return nil, nil, fiber.NewError(fiber.StatusBadRequest, "Invoice tidak memiliki nominal")
+	}
+
+	// payment gateway yang masih pending → pakai ulang
+	if inv.SchoolServiceInvoicePaymentID != nil && method == paymodel.PaymentMethodGateway {
+		var p paymodel.PaymentModel
+		if err := db.WithContext(ctx).First(&p, "payment_id = ? AND payment_deleted_at IS NULL", *inv.SchoolServiceInvoicePaymentID).Error; err == nil {
+			if p.PaymentStatus == paymodel.PaymentStatusPending || p.PaymentStatus == paymodel.PaymentStatusInitiated {
+				return &p, &inv, nil
+			}
+		}
+	}
+
+	now := time.Now()
+	desc := fmt.Sprintf("Langganan %s (%s) %s", inv.SchoolServiceInvoicePlanNameSnapshot, inv.SchoolServiceInvoiceBillingCycle, inv.SchoolServiceInvoiceNumber)
+	meta := fmt.Sprintf(`{"school_service_invoice_id":%q,"school_service_invoice_school_id":%q}`, inv.SchoolServiceInvoiceID, inv.SchoolServiceInvoiceSchoolID)
+
+	p := paymodel.PaymentModel{
+		PaymentSchoolID:    nil, // revenue platform
+		PaymentUserID:      payerUserID,
+		PaymentAmountIDR:   inv.SchoolServiceInvoiceAmountIDR,
+		PaymentCurrency:    "IDR",
+		PaymentStatus:      paymodel.PaymentStatusInitiated,
+		PaymentMethod:      method,
+		PaymentEntryType:   paymodel.PaymentEntryPayment,
+		PaymentDescription: &desc,
+		PaymentMeta:        []byte(meta),
+		PaymentRequestedAt: &now,
+		PaymentCreatedAt:   now,
+		PaymentUpdatedAt:   now,
+	}
+	if method == paymodel.PaymentMethodGateway {
+		prov := paymodel.GatewayProviderMidtrans
+		orderID := paysvc.GenOrderID("SUB")
+		p.PaymentGatewayProvider = &prov
+		p.PaymentExternalID = &orderID
+	} else {
+		p.PaymentStatus = paymodel.PaymentStatusPaid
+		p.PaymentPaidAt = &now
+		p.PaymentManualReference = manualRef
+		p.PaymentManualVerifiedByUser = payerUserID
+		p.PaymentManualVerifiedAt = &now
+	}
+	if payerUserID != nil {
+		if un, fn, em, dn, er := paysvc.HydrateUserSnapshots(ctx, db, *payerUserID); er == nil {
+			p.PaymentUserNameSnapshot, p.PaymentFullNameSnapshot = un, fn
+			p.PaymentEmailSnapshot, p.PaymentDonationNameSnapshot = em, dn
+		}
+	}
+
+	if err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&p).Error; err != nil {
+			return err
+		}
+		return tx.Model(&inv).Updates(map[string]any{
+			"school_service_invoice_payment_id": p.PaymentID,
+			"school_service_invoice_updated_at": now,
+		}).Error
+	}); err != nil {
+		return nil, nil, err
+	}
+	inv.SchoolServiceInvoicePaymentID = &p.PaymentID
+
+	if method == paymodel.PaymentMethodGateway {
+		cust := paysvc.CustomerInput{}
+		if p.PaymentFullNameSnapshot != nil {
+			cust.FirstName = *p.PaymentFullNameSnapshot
+		}
+		if p.PaymentEmailSnapshot != nil {
+			cust.Email = *p.PaymentEmailSnapshot
+		}
+		token, redirectURL, err := paysvc.GenerateSnapToken(p, cust, "")
+		if err != nil {
+			return nil, nil, fiber.NewError(fiber.StatusBadGateway, "midtrans error: "+err.Error())
+		}
+		p.PaymentCheckoutURL = &redirectURL
+		p.PaymentGatewayRef = &token
+		p.PaymentStatus = paymodel.PaymentStatusPending
+		if err := db.WithContext(ctx).Save(&p).Error; err != nil {
+			return nil, nil, err
+		}
+		return &p, &inv, nil
+	}
+
+	// manual paid → langsung terapkan ke invoice & langganan
+	if err := paysvc.ApplySchoolSubscriptionSideEffects(ctx, db, &p); err != nil {
+		return nil, nil, err
+	}
+	_ = db.WithContext(ctx).First(&inv, "school_service_invoice_id = ?", inv.SchoolServiceInvoiceID).Error
+	return &p, &inv, nil
+}
+
+/* =========================================================
+   Billing run (dipanggil scheduler)
+========================================================= */
+
+type BillingRunResult struct {
+	TrialsConverted  int64 `json:"trials_converted"`
+	RenewalsInvoiced int64 `json:"renewals_invoiced"`
+	MovedToGrace     int64 `json:"moved_to_grace"`
+	Canceled         int64 `json:"canceled"`
+	Lapsed           int64 `json:"lapsed"`
+	InvoicesOverdue  int64 `json:"invoices_overdue"`
+}
+
+func listSubs(tx *gorm.DB, where string, args ...any) ([]model.SchoolServiceSubscriptionModel, error) {
+	var rows []model.SchoolServiceSubscriptionModel
+	err := tx.Where("school_service_subscription_end_at IS NULL AND school_service_subscription_deleted_at IS NULL").
+		Where(where, args...).
+		Limit(500).
+		Find(&rows).Error
+	return rows, err
+}
+
+// RunBillingCycle memproses transisi status & invoice perpanjangan.
+// Tiap langganan diproses dalam transaksi sendiri: gagal di tengah → langganan tsb
+// tidak setengah jalan (mis. invoice terbit tapi status tidak pindah).
+func RunBillingCycle(ctx context.Context, db *gorm.DB, cfg BillingConfig, now time.Time) (BillingRunResult, error) {
+	var res BillingRunResult
+	base := db.WithContext(ctx)
+
+	// 1) Trial habis → invoice pertama + grace
+	trials, err := listSubs(base, "school_service_subscription_status = 'trial' AND school_service_subscription_trial_end_at <= ?", now)
+	if err != nil {
+		return res, err
+	}
+	for i := range trials {
+		s := &trials[i]
+		graceUntil := s.SchoolServiceSubscriptionTrialEndAt.AddDate(0, 0, cfg.GraceDays)
+		if err := base.Transaction(func(tx *gorm.DB) error {
+			if _, _, err := IssueInvoice(tx, s, *s.SchoolServiceSubscriptionTrialEndAt, graceUntil); err != nil {
+				return err
+			}
+			return tx.Model(s).Updates(map[string]any{
+				"school_service_subscription_status":      model.SubscriptionStatusGrace,
+				"school_service_subscription_grace_until": graceUntil,
+				"school_service_subscription_updated_at":  now,
+			}).Error
+		}); err != nil {
+			return res, err
+		}
+		res.TrialsConverted++
+	}
+
+	// 2) Active: dibatalkan & periode habis → canceled (turun ke plan gratis)
+	canceled, err := listSubs(base, `school_service_subscription_status = 'active'
+		AND school_service_subscription_is_auto_renew = FALSE
+		AND school_service_subscription_current_period_end <= ?`, now)
+	if err != nil {
+		return res, err
+	}
+	for i := range canceled {
+		s := &canceled[i]
+		if err := base.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Model(s).Updates(map[string]any{
+				"school_service_subscription_status":     model.SubscriptionStatusCanceled,
+				"school_service_subscription_end_at":     now,
+				"school_service_subscription_updated_at": now,
+			}).Error; err != nil {
+				return err
+			}
+			return downgradeSchool(tx, cfg, s.SchoolServiceSubscriptionSchoolID)
+		}); err != nil {
+			return res, err
+		}
+		res.Canceled++
+	}
+
+	// 3) Active auto-renew: invoice perpanjangan H-InvoiceLeadDays
+	lead := now.AddDate(0, 0, cfg.InvoiceLeadDays)
+	renewals, err := listSubs(base, `school_service_subscription_status = 'active'
+		AND school_service_subscription_is_auto_renew = TRUE
+		AND school_service_subscription_current_period_end IS NOT NULL
+		AND school_service_subscription_current_period_end <= ?`, lead)
+	if err != nil {
+		return res, err
+	}
+	for i := range renewals {
+		s := &renewals[i]
+		if PriceIDR(s.SchoolServiceSubscriptionPriceMonthly, s.SchoolServiceSubscriptionPriceYearly, s.SchoolServiceSubscriptionBillingCycle) == 0 {
+			continue
+		}
+		periodEnd := *s.SchoolServiceSubscriptionCurrentPeriodEnd
+		var created, moved bool
+		if err := base.Transaction(func(tx *gorm.DB) error {
+			inv, c, err := IssueInvoice(tx, s, periodEnd, periodEnd.AddDate(0, 0, cfg.GraceDays))
+			if err != nil {
+				return err
+			}
+			created = c
+
+			// 4) Periode habis & invoice belum lunas → grace
+			if periodEnd.After(now) || inv.SchoolServiceInvoiceStatus == model.InvoiceStatusPaid {
+				return nil
+			}
+			if err := tx.Model(s).Updates(map[string]any{
+				"school_service_subscription_status":      model.SubscriptionStatusGrace,
+				"school_service_subscription_grace_until": inv.SchoolServiceInvoiceDueAt,
+				"school_service_subscription_updated_at":  now,
+			}).Error; err != nil {
+				return err
+			}
+			moved = true
+			return nil
+		}); err != nil {
+			return res, err
+		}
+		if created {
+			res.RenewalsInvoiced++
+		}
+		if moved {
+			res.MovedToGrace++
+		}
+	}
+
+	// 5) Grace lewat → expired + aksi lapse (downgrade / read_only)
+	lapsed, err := listSubs(base, "school_service_subscription_status = 'grace' AND school_service_subscription_grace_until <= ?", now)
+	if err != nil {
+		return res, err
+	}
+	for i := range lapsed {
+		s := &lapsed[i]
+		downgrade := s.SchoolServiceSubscriptionLapseAction != model.LapseActionReadOnly
+		upd := map[string]any{
+			"school_service_subscription_status":     model.SubscriptionStatusExpired,
+			"school_service_subscription_lapsed_at":  now,
+			"school_service_subscription_updated_at": now,
+		}
+		if downgrade {
+			// downgrade: langganan ditutup, sekolah kembali ke plan gratis
+			upd["school_service_subscription_end_at"] = now
+		}
+		if err := base.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Model(s).Updates(upd).Error; err != nil {
+				return err
+			}
+			if !downgrade {
+				return nil
+			}
+			return downgradeSchool(tx, cfg, s.SchoolServiceSubscriptionSchoolID)
+		}); err != nil {
+			return res, err
+		}
+		res.Lapsed++
+	}
+
+	// 6) Invoice lewat jatuh tempo (1 statement → atomik)
+	r := base.Model(&model.SchoolServiceInvoiceModel{}).
+		Where(`school_service_invoice_status = 'open'
+			AND school_service_invoice_due_at <= ?
+			AND school_service_invoice_deleted_at IS NULL`, now).
+		Updates(map[string]any{
+			"school_service_invoice_status":     model.InvoiceStatusOverdue,
+			"school_service_invoice_updated_at": now,
+		})
+	if r.Error != nil {
+		return res, r.Error
+	}
+	res.InvoicesOverdue = r.RowsAffected
+
+	return res, nil
+}
+
+func downgradeSchool(tx *gorm.DB, cfg BillingConfig, schoolID uuid.UUID) error {
+	id, err := freePlanID(tx, cfg.FreePlanCode)
+	if err != nil {
+		return err
+	}
+	var planID *uuid.UUID
+	if id != uuid.Nil {
+		planID = &id
+	}
+	return setSchoolPlan(tx, schoolID, planID)
+}
+
+// setSchoolPlan: lewat model (bukan Exec) supaya perubahan plan ikut tercatat di audit_logs.
+func setSchoolPlan(tx *gorm.DB, schoolID uuid.UUID, planID *uuid.UUID) error {
+	return tx.Model(&schoolModel.SchoolModel{SchoolID: schoolID}).
+		Update("school_current_plan_id", planID).Error
+}
+
+/* =========================================================
+   Read-only check (dipakai middleware)
+========================================================= */
+
+// IsSchoolReadOnly: true jika langganan current sekolah expired dengan lapse_action read_only.
+func IsSchoolReadOnly(ctx context.Context, db *gorm.DB, schoolID uuid.UUID) (bool, error) {
+	var n int64
+	err := db.WithContext(ctx).Model(&model.SchoolServiceSubscriptionModel{}).
+		Where(`school_service_subscription_school_id = ?
+			AND school_service_subscription_end_at IS NULL
+			AND school_service_subscription_deleted_at IS NULL
+			AND school_service_subscription_status = 'expired'
+			AND school_service_subscription_lapse_action = 'read_only'`, schoolID).
+		Count(&n).Error
+	return n > 0, err
+}
+
+/* =========================================================
+   Metrics (owner): MRR & churn
+========================================================= */
+
+type Metrics struct {
+	AsOf               time.Time `json:"as_of"`
+	WindowDays         int       `json:"window_days"`
+	MRRIDR             int64     `json:"mrr_idr"`
+	ARRIDR             int64     `json:"arr_idr"`
+	ActiveCount        int64     `json:"active_count"`
+	TrialCount         int64     `json:"trial_count"`
+	GraceCount         int64     `json:"grace_count"`
+	ReadOnlyCount      int64     `json:"read_only_count"`
+	ActiveAtStart      int64     `json:"active_at_window_start"`
+	Churned            int64     `json:"churned"`
+	ChurnRate          float64   `json:"churn_rate"`
+	NewPaid            int64     `json:"new_paid"`
+	CollectedIDR       int64     `json:"collected_idr"`
+	OutstandingIDR     int64     `json:"outstanding_idr"`
+	OutstandingInvoice int64     `json:"outstanding_invoices"`
+}
+
+// ComputeMetrics menghitung MRR (yearly dinormalisasi /12) & churn di jendela windowDays.
+// MRR hanya dari langganan active (sudah dibayar); grace = tagihan belum lunas → tidak dihitung.
+func ComputeMetrics(ctx context.Context, db *gorm.DB, now time.Time, windowDays int) (Metrics, error) {
+	if windowDays <= 0 {
+		windowDays = 30
+	}
+	from := now.AddDate(0, 0, -windowDays)
+	m := Metrics{AsOf: now, WindowDays: windowDays}
+	tx := db.WithContext(ctx)
+
+	var mrr float64
+	if err := tx.Raw(`
+		SELECT COALESCE(SUM(CASE
+		         WHEN school_service_subscription_billing_cycle = 'yearly'
+		           THEN COALESCE(school_service_subscription_price_yearly,0) / 12.0
+		         ELSE COALESCE(school_service_subscription_price_monthly,0)
+		       END), 0)
+		  FROM school_service_subscriptions
+		 WHERE school_service_subscription_deleted_at IS NULL
+		   AND school_service_subscription_end_at IS NULL
+		   AND school_service_subscription_status = 'active'
+	`).Scan(&mrr).Error; err != nil {
+		return m, err
+	}
+	m.MRRIDR = int64(math.Round(mrr))
+	m.ARRIDR = m.MRRIDR * 12
+
+	var counts struct {
+		Active   int64
+		Trial    int64
+		Grace    int64
+		ReadOnly int64
+	}
+	if err := tx.Raw(`
+		SELECT
+		  COUNT(*) FILTER (WHERE school_service_subscription_status = 'active') AS active,
+		  COUNT(*) FILTER (WHERE school_service_subscription_status = 'trial')  AS trial,
+		  COUNT(*) FILTER (WHERE school_service_subscription_status = 'grace')  AS grace,
+		  COUNT(*) FILTER (WHERE school_service_subscription_status = 'expired') AS read_only
+		  FROM school_service_subscriptions
+		 WHERE school_service_subscription_deleted_at IS NULL
+		   AND school_service_subscription_end_at IS NULL
+	`).Scan(&counts).Error; err != nil {
+		return m, err
+	}
+	m.ActiveCount, m.TrialCount, m.GraceCount, m.ReadOnlyCount = counts.Active, counts.Trial, counts.Grace, counts.ReadOnly
+
+	// Basis churn: langganan berbayar yang hidup di awal jendela
+	if err := tx.Raw(`
+		SELECT COUNT(*)
+		  FROM school_service_subscriptions
+		 WHERE school_service_subscription_deleted_at IS NULL
+		   AND school_service_subscription_start_at < ?
+		   AND (school_service_subscription_end_at IS NULL OR school_service_subscription_end_at >= ?)
+		   AND school_service_subscription_current_period_end IS NOT NULL
+		   AND school_service_subscription_status <> 'trial'
+	`, from, from).Scan(&m.ActiveAtStart).Error; err != nil {
+		return m, err
+	}
+
+	// Churn: lapse (expired) atau batal di akhir periode dalam jendela — ganti plan tidak dihitung
+	if err := tx.Raw(`
+		SELECT COUNT(DISTINCT school_service_subscription_school_id)
+		  FROM school_service_subscriptions s
+		 WHERE s.school_service_subscription_deleted_at IS NULL
+		   AND (
+		     (s.school_service_subscription_status = 'expired' AND s.school_service_subscription_lapsed_at >= ?)
+		     OR
+		     (s.school_service_subscription_status = 'canceled' AND s.school_service_subscription_end_at >= ?
+		      AND s.school_service_subscription_is_auto_renew = FALSE)
+		   )
+		   AND NOT EXISTS (
+		     SELECT 1 FROM school_service_subscriptions n
+		      WHERE n.school_service_subscription_school_id = s.school_service_subscription_school_id
+		        AND n.school_service_subscription_end_at IS NULL
+		        AND n.school_service_subscription_deleted_at IS NULL
+		        AND n.school_service_subscription_status IN ('active','grace','trial')
+		   )
+	`, from, from).Scan(&m.Churned).Error; err != nil {
+		return m, err
+	}
+	if m.ActiveAtStart > 0 {
+		m.ChurnRate = math.Round(float64(m.Churned)/float64(m.ActiveAtStart)*10000) / 10000
+	}
+
+	var inv struct {
+		NewPaid          int64
+		Collected        int64
+		Outstanding      int64
+		OutstandingCount int64
+	}
+	if err := tx.Raw(`
+		SELECT
+		  COUNT(DISTINCT school_service_invoice_school_id) FILTER (
+		    WHERE school_service_invoice_status = 'paid' AND school_service_invoice_paid_at >= ?
+		      AND NOT EXISTS (
+		        SELECT 1 FROM school_service_invoices p
+		         WHERE p.school_service_invoice_school_id = i.school_service_invoice_school_id
+		           AND p.school_service_invoice_status = 'paid'
+		           AND p.school_service_invoice_paid_at < ?
+		      )
+		  ) AS new_paid,
+		  COALESCE(SUM(school_service_invoice_amount_idr) FILTER (
+		    WHERE school_service_invoice_status = 'paid' AND school_service_invoice_paid_at >= ?
+		  ), 0) AS collected,
+		  COALESCE(SUM(school_service_invoice_amount_idr) FILTER (
+		    WHERE school_service_invoice_status IN ('open','overdue')
+		  ), 0) AS outstanding,
+		  COUNT(*) FILTER (WHERE school_service_invoice_status IN ('open','overdue')) AS outstanding_count
+		  FROM school_service_invoices i
+		 WHERE school_service_invoice_deleted_at IS NULL
+	`, from, from, from).Scan(&inv).Error; err != nil {
+		return m, err
+	}
+	m.NewPaid, m.CollectedIDR = inv.NewPaid, inv.Collected
+	m.OutstandingIDR, m.OutstandingInvoice = inv.Outstanding, inv.OutstandingCount
+
+	return m, nil
+}
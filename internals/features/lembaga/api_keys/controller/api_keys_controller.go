@@ -0,0 +1,154 @@
+// file: internals/features/lembaga/api_keys/controller/api_keys_controller.go
+package controller
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	dto "madinahsalam_backend/internals/features/lembaga/api_keys/dto"
+	svc "madinahsalam_backend/internals/features/lembaga/api_keys/service"
+	permsvc "madinahsalam_backend/internals/features/lembaga/permissions/service"
+	helper "madinahsalam_backend/internals/helpers"
+	helperAuth "madinahsalam_backend/internals/helpers/auth"
+)
+
+/*
+API key sekolah (akses mesin-ke-mesin ke /api/a)
+
+GET    /api/a/api-keys?include_revoked=true
+POST   /api/a/api-keys              {"name","scopes":[...],"allowed_ips":[...],"expires_at"} → key (sekali)
+GET    /api/a/api-keys/:id          + rekap pemakaian 30 hari
+PATCH  /api/a/api-keys/:id          {"name","scopes","allowed_ips","expires_at"}
+POST   /api/a/api-keys/:id/revoke
+
+Pemakaian: header X-API-Key: msk_... (atau Authorization: Bearer msk_...)
+*/
+
+type APIKeyController struct {
+	DB *gorm.DB
+}
+
+func NewAPIKeyController(db *gorm.DB) *APIKeyController {
+	return &APIKeyController{DB: db}
+}
+
+func writeErr(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, svc.ErrKeyNotFound):
+		return helper.JsonError(c, fiber.StatusNotFound, err.Error())
+	case errors.Is(err, svc.ErrKeyRevoked), errors.Is(err, svc.ErrTooManyKeys):
+		return helper.JsonError(c, fiber.StatusConflict, err.Error())
+	case errors.Is(err, svc.ErrNameEmpty), errors.Is(err, svc.ErrScopesEmpty), errors.Is(err, svc.ErrInvalidScope),
+		errors.Is(err, svc.ErrInvalidIP), errors.Is(err, svc.ErrInvalidExpiry), errors.Is(err, svc.ErrTooManyIPRules):
+		return helper.JsonError(c, fiber.StatusBadRequest, err.Error())
+	}
+	var fe *fiber.Error
+	if errors.As(err, &fe) {
+		return helper.JsonError(c, fe.Code, fe.Message)
+	}
+	return helper.JsonError(c, fiber.StatusInternalServerError, err.Error())
+}
+
+// scope: school aktif + :id (bila ada di route).
+func scope(c *fiber.Ctx) (uuid.UUID, uuid.UUID, error) {
+	schoolID, err := permsvc.SchoolFromRequest(c)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	if c.Params("id") == "" {
+		return schoolID, uuid.Nil, nil
+	}
+	id, err := uuid.Parse(strings.TrimSpace(c.Params("id")))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fiber.NewError(fiber.StatusBadRequest, "id tidak valid")
+	}
+	return schoolID, id, nil
+}
+
+func actor(c *fiber.Ctx) *uuid.UUID {
+	if uid, err := helperAuth.GetUserIDFromToken(c); err == nil && uid != uuid.Nil {
+		return &uid
+	}
+	return nil
+}
+
+// GET /api/a/api-keys
+func (h *APIKeyController) List(c *fiber.Ctx) error {
+	schoolID, _, err := scope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	rows, err := svc.ListKeys(c.Context(), h.DB, schoolID, c.QueryBool("include_revoked", false))
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusInternalServerError, "Gagal mengambil api key")
+	}
+	return helper.JsonOK(c, "OK", dto.FromKeys(rows))
+}
+
+// GET /api/a/api-keys/:id
+func (h *APIKeyController) Detail(c *fiber.Ctx) error {
+	schoolID, id, err := scope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	m, err := svc.GetKey(c.Context(), h.DB, schoolID, id)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	usage, err := svc.DailyUsage(c.Context(), h.DB, m.SchoolAPIKeyID, 30)
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusInternalServerError, "Gagal mengambil pemakaian api key")
+	}
+	return helper.JsonOK(c, "OK", dto.FromKeyDetail(m, usage))
+}
+
+// POST /api/a/api-keys
+func (h *APIKeyController) Create(c *fiber.Ctx) error {
+	schoolID, _, err := scope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	var req dto.CreateKeyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "Payload tidak valid")
+	}
+	m, raw, err := svc.CreateKey(c.Context(), h.DB, schoolID, req.ToInput(), actor(c))
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonCreated(c, "API key dibuat (simpan key, tidak ditampilkan lagi)", dto.FromKey(m, raw))
+}
+
+// PATCH /api/a/api-keys/:id
+func (h *APIKeyController) Update(c *fiber.Ctx) error {
+	schoolID, id, err := scope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	var req dto.UpdateKeyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "Payload tidak valid")
+	}
+	m, err := svc.UpdateKey(c.Context(), h.DB, schoolID, id, req.ToInput())
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonUpdated(c, "API key diperbarui", dto.FromKey(m, ""))
+}
+
+// POST /api/a/api-keys/:id/revoke
+func (h *APIKeyController) Revoke(c *fiber.Ctx) error {
+	schoolID, id, err := scope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	m, err := svc.RevokeKey(c.Context(), h.DB, schoolID, id, actor(c))
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonUpdated(c, "API key dicabut", dto.FromKey(m, ""))
+}
@@ -0,0 +1,143 @@
+// file: internals/features/lembaga/api_keys/dto/api_keys_dto.go
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	model "madinahsalam_backend/internals/features/lembaga/api_keys/model"
+	svc "madinahsalam_backend/internals/features/lembaga/api_keys/service"
+)
+
+/* =========================================================
+   REQUEST
+========================================================= */
+
+type CreateKeyRequest struct {
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	AllowedIPs []string   `json:"allowed_ips"`
+	ExpiresAt  *time.Time `json:"expires_at"` // kosong = 1 tahun
+}
+
+func (r CreateKeyRequest) ToInput() svc.KeyInput {
+	return svc.KeyInput{
+		Name:       &r.Name,
+		Scopes:     r.Scopes,
+		SetScopes:  true,
+		AllowedIPs: r.AllowedIPs,
+		SetIPs:     true,
+		ExpiresAt:  r.ExpiresAt,
+	}
+}
+
+// PATCH: field nil = tidak diubah; scopes/allowed_ips (bila dikirim) mengganti seluruh isi.
+type UpdateKeyRequest struct {
+	Name       *string    `json:"name"`
+	Scopes     *[]string  `json:"scopes"`
+	AllowedIPs *[]string  `json:"allowed_ips"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+}
+
+func (r UpdateKeyRequest) ToInput() svc.KeyInput {
+	in := svc.KeyInput{Name: r.Name, ExpiresAt: r.ExpiresAt}
+	if r.Scopes != nil {
+		in.Scopes, in.SetScopes = *r.Scopes, true
+	}
+	if r.AllowedIPs != nil {
+		in.AllowedIPs, in.SetIPs = *r.AllowedIPs, true
+	}
+	return in
+}
+
+/* =========================================================
+   RESPONSE
+========================================================= */
+
+type KeyResponse struct {
+	SchoolAPIKeyID         uuid.UUID `json:"school_api_key_id"`
+	SchoolAPIKeySchoolID   uuid.UUID `json:"school_api_key_school_id"`
+	SchoolAPIKeyName       string    `json:"school_api_key_name"`
+	SchoolAPIKeyPrefix     string    `json:"school_api_key_prefix"`
+	SchoolAPIKeyScopes     []string  `json:"school_api_key_scopes"`
+	SchoolAPIKeyAllowedIPs []string  `json:"school_api_key_allowed_ips"`
+	SchoolAPIKeyExpiresAt  time.Time `json:"school_api_key_expires_at"`
+	SchoolAPIKeyStatus     string    `json:"school_api_key_status"` // active | expired | revoked
+
+	SchoolAPIKeyLastUsedAt *time.Time `json:"school_api_key_last_used_at,omitempty"`
+	SchoolAPIKeyLastUsedIP *string    `json:"school_api_key_last_used_ip,omitempty"`
+	SchoolAPIKeyUsageCount int64      `json:"school_api_key_usage_count"`
+
+	// hanya terisi saat create
+	SchoolAPIKeyKey *string `json:"school_api_key_key,omitempty"`
+
+	SchoolAPIKeyCreatedBy *uuid.UUID `json:"school_api_key_created_by,omitempty"`
+	SchoolAPIKeyRevokedAt *time.Time `json:"school_api_key_revoked_at,omitempty"`
+	SchoolAPIKeyRevokedBy *uuid.UUID `json:"school_api_key_revoked_by,omitempty"`
+	SchoolAPIKeyCreatedAt time.Time  `json:"school_api_key_created_at"`
+	SchoolAPIKeyUpdatedAt time.Time  `json:"school_api_key_updated_at"`
+}
+
+func status(m *model.SchoolAPIKeyModel) string {
+	switch {
+	case m.SchoolAPIKeyRevokedAt != nil:
+		return "revoked"
+	case !m.Active(time.Now()):
+		return "expired"
+	}
+	return "active"
+}
+
+func orEmpty(xs []string) []string {
+	if xs == nil {
+		return []string{}
+	}
+	return xs
+}
+
+// FromKey: raw hanya diisi saat create (key mentah tidak disimpan).
+func FromKey(m *model.SchoolAPIKeyModel, raw string) KeyResponse {
+	out := KeyResponse{
+		SchoolAPIKeyID:         m.SchoolAPIKeyID,
+		SchoolAPIKeySchoolID:   m.SchoolAPIKeySchoolID,
+		SchoolAPIKeyName:       m.SchoolAPIKeyName,
+		SchoolAPIKeyPrefix:     m.SchoolAPIKeyPrefix,
+		SchoolAPIKeyScopes:     orEmpty(m.SchoolAPIKeyScopes),
+		SchoolAPIKeyAllowedIPs: orEmpty(m.SchoolAPIKeyAllowedIPs),
+		SchoolAPIKeyExpiresAt:  m.SchoolAPIKeyExpiresAt,
+		SchoolAPIKeyStatus:     status(m),
+		SchoolAPIKeyLastUsedAt: m.SchoolAPIKeyLastUsedAt,
+		SchoolAPIKeyLastUsedIP: m.SchoolAPIKeyLastUsedIP,
+		SchoolAPIKeyUsageCount: m.SchoolAPIKeyUsageCount,
+		SchoolAPIKeyCreatedBy:  m.SchoolAPIKeyCreatedBy,
+		SchoolAPIKeyRevokedAt:  m.SchoolAPIKeyRevokedAt,
+		SchoolAPIKeyRevokedBy:  m.SchoolAPIKeyRevokedBy,
+		SchoolAPIKeyCreatedAt:  m.SchoolAPIKeyCreatedAt,
+		SchoolAPIKeyUpdatedAt:  m.SchoolAPIKeyUpdatedAt,
+	}
+	if raw != "" {
+		out.SchoolAPIKeyKey = &raw
+	}
+	return out
+}
+
+func FromKeys(rows []model.SchoolAPIKeyModel) []KeyResponse {
+	out := make([]KeyResponse, 0, len(rows))
+	for i := range rows {
+		out = append(out, FromKey(&rows[i], ""))
+	}
+	return out
+}
+
+type KeyDetailResponse struct {
+	KeyResponse
+	Usage []model.SchoolAPIKeyUsageDailyModel `json:"usage"`
+}
+
+func FromKeyDetail(m *model.SchoolAPIKeyModel, usage []model.SchoolAPIKeyUsageDailyModel) KeyDetailResponse {
+	if usage == nil {
+		usage = []model.SchoolAPIKeyUsageDailyModel{}
+	}
+	return KeyDetailResponse{KeyResponse: FromKey(m, ""), Usage: usage}
+}
@@ -0,0 +1,49 @@
+// file: internals/features/lembaga/api_keys/model/api_keys_model.go
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// SchoolAPIKeyModel: kredensial mesin-ke-mesin milik sekolah.
+// Key mentah hanya ditampilkan sekali saat dibuat; yang disimpan prefix + sha256.
+type SchoolAPIKeyModel struct {
+	SchoolAPIKeyID         uuid.UUID      `gorm:"column:school_api_key_id;type:uuid;default:gen_random_uuid();primaryKey" json:"school_api_key_id"`
+	SchoolAPIKeySchoolID   uuid.UUID      `gorm:"column:school_api_key_school_id;type:uuid;not null" json:"school_api_key_school_id"`
+	SchoolAPIKeyName       string         `gorm:"column:school_api_key_name;type:varchar(120);not null" json:"school_api_key_name"`
+	SchoolAPIKeyPrefix     string         `gorm:"column:school_api_key_prefix;type:varchar(16);not null" json:"school_api_key_prefix"`
+	SchoolAPIKeyHash       string         `gorm:"column:school_api_key_hash;type:char(64);not null" json:"-"`
+	SchoolAPIKeyScopes     pq.StringArray `gorm:"column:school_api_key_scopes;type:text[];not null;default:'{}'" json:"school_api_key_scopes"`
+	SchoolAPIKeyAllowedIPs pq.StringArray `gorm:"column:school_api_key_allowed_ips;type:text[];not null;default:'{}'" json:"school_api_key_allowed_ips"`
+	SchoolAPIKeyExpiresAt  time.Time      `gorm:"column:school_api_key_expires_at;not null" json:"school_api_key_expires_at"`
+
+	SchoolAPIKeyLastUsedAt *time.Time `gorm:"column:school_api_key_last_used_at" json:"school_api_key_last_used_at,omitempty"`
+	SchoolAPIKeyLastUsedIP *string    `gorm:"column:school_api_key_last_used_ip;type:varchar(64)" json:"school_api_key_last_used_ip,omitempty"`
+	SchoolAPIKeyUsageCount int64      `gorm:"column:school_api_key_usage_count;not null;default:0" json:"school_api_key_usage_count"`
+
+	SchoolAPIKeyCreatedBy *uuid.UUID `gorm:"column:school_api_key_created_by;type:uuid" json:"school_api_key_created_by,omitempty"`
+	SchoolAPIKeyRevokedAt *time.Time `gorm:"column:school_api_key_revoked_at" json:"school_api_key_revoked_at,omitempty"`
+	SchoolAPIKeyRevokedBy *uuid.UUID `gorm:"column:school_api_key_revoked_by;type:uuid" json:"school_api_key_revoked_by,omitempty"`
+
+	SchoolAPIKeyCreatedAt time.Time `gorm:"column:school_api_key_created_at;autoCreateTime" json:"school_api_key_created_at"`
+	SchoolAPIKeyUpdatedAt time.Time `gorm:"column:school_api_key_updated_at;autoUpdateTime" json:"school_api_key_updated_at"`
+}
+
+func (SchoolAPIKeyModel) TableName() string { return "school_api_keys" }
+
+// Active: belum dicabut & belum kedaluwarsa.
+func (m *SchoolAPIKeyModel) Active(now time.Time) bool {
+	return m.SchoolAPIKeyRevokedAt == nil && now.Before(m.SchoolAPIKeyExpiresAt)
+}
+
+// SchoolAPIKeyUsageDailyModel: rekap jumlah request per key per hari.
+type SchoolAPIKeyUsageDailyModel struct {
+	SchoolAPIKeyUsageKeyID uuid.UUID `gorm:"column:school_api_key_usage_key_id;type:uuid;primaryKey" json:"school_api_key_id"`
+	SchoolAPIKeyUsageDate  time.Time `gorm:"column:school_api_key_usage_date;type:date;primaryKey" json:"date"`
+	SchoolAPIKeyUsageCount int64     `gorm:"column:school_api_key_usage_count;not null;default:0" json:"request_count"`
+}
+
+func (SchoolAPIKeyUsageDailyModel) TableName() string { return "school_api_key_usage_daily" }
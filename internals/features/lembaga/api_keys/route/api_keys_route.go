@@ -0,0 +1,27 @@
+// file: internals/features/lembaga/api_keys/route/api_keys_route.go
+package route
+
+import (
+	apiKeyController "madinahsalam_backend/internals/features/lembaga/api_keys/controller"
+	schoolkuMiddleware "madinahsalam_backend/internals/middlewares/features"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// /api/a/api-keys → kelola API key sekolah (api_keys.* tidak bisa didelegasikan,
+// jadi API key sendiri juga tidak bisa membuat/mencabut key lain)
+func APIKeyAdminRoutes(api fiber.Router, db *gorm.DB) {
+	ctl := apiKeyController.NewAPIKeyController(db)
+
+	read := schoolkuMiddleware.RequirePermission("api_keys.read")
+	write := schoolkuMiddleware.RequirePermission("api_keys.write")
+
+	g := api.Group("/api-keys")
+
+	g.Get("/", read, ctl.List)
+	g.Post("/", write, ctl.Create)
+	g.Get("/:id", read, ctl.Detail)
+	g.Patch("/:id", write, ctl.Update)
+	g.Post("/:id/revoke", write, ctl.Revoke)
+}
@@ -0,0 +1,55 @@
+// file: internals/features/lembaga/api_keys/service/authenticate.go
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	model "madinahsalam_backend/internals/features/lembaga/api_keys/model"
+)
+
+var (
+	ErrKeyInvalid      = errors.New("api key tidak valid")
+	ErrKeyExpired      = errors.New("api key sudah kedaluwarsa")
+	ErrKeyIPNotAllowed = errors.New("IP tidak diizinkan untuk api key ini")
+)
+
+// Authenticate memverifikasi key mentah dari header lalu mencatat pemakaian (batched).
+// Semua kegagalan verifikasi key → ErrKeyInvalid (tidak membocorkan prefix mana yang ada).
+func Authenticate(ctx context.Context, db *gorm.DB, raw, ip string) (*model.SchoolAPIKeyModel, error) {
+	prefix, ok := splitPrefix(raw)
+	if !ok {
+		return nil, ErrKeyInvalid
+	}
+
+	var m model.SchoolAPIKeyModel
+	err := db.WithContext(ctx).
+		Where("school_api_key_prefix = ?", prefix).
+		Take(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrKeyInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(hashKey(raw)), []byte(m.SchoolAPIKeyHash)) != 1 {
+		return nil, ErrKeyInvalid
+	}
+
+	if m.SchoolAPIKeyRevokedAt != nil {
+		return nil, ErrKeyRevoked
+	}
+	if !time.Now().Before(m.SchoolAPIKeyExpiresAt) {
+		return nil, ErrKeyExpired
+	}
+	if !IPAllowed(m.SchoolAPIKeyAllowedIPs, ip) {
+		return nil, ErrKeyIPNotAllowed
+	}
+
+	TrackUsage(m.SchoolAPIKeyID, ip)
+	return &m, nil
+}
@@ -0,0 +1,347 @@
+// file: internals/features/lembaga/api_keys/service/keys.go
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+
+	model "madinahsalam_backend/internals/features/lembaga/api_keys/model"
+	permsvc "madinahsalam_backend/internals/features/lembaga/permissions/service"
+)
+
+var (
+	ErrKeyNotFound    = errors.New("api key tidak ditemukan")
+	ErrKeyRevoked     = errors.New("api key sudah dicabut")
+	ErrNameEmpty      = errors.New("nama api key wajib diisi")
+	ErrScopesEmpty    = errors.New("scopes api key wajib diisi")
+	ErrInvalidScope   = errors.New("scope tidak dikenal atau tidak boleh diberikan ke api key")
+	ErrInvalidIP      = errors.New("allowed_ips harus berisi IP atau CIDR yang valid")
+	ErrInvalidExpiry  = errors.New("expires_at harus di masa depan dan maksimal 2 tahun")
+	ErrTooManyKeys    = errors.New("jumlah api key aktif sekolah sudah mencapai batas")
+	ErrTooManyIPRules = errors.New("allowed_ips terlalu banyak")
+)
+
+const (
+	// KeyPrefix: penanda key sekolah (dipakai middleware untuk membedakan dari JWT).
+	KeyPrefix = "msk_"
+
+	maxActiveKeysPerSchool = 50
+	maxAllowedIPs          = 50
+
+	defaultKeyTTL = 365 * 24 * time.Hour
+	maxKeyTTL     = 2 * 365 * 24 * time.Hour
+)
+
+/* =========================================================
+   Format key: msk_<8 hex lookup>_<64 hex secret>
+   - prefix (msk_<8 hex>) unik → lookup 1 baris
+   - hash = sha256(key utuh), dibandingkan constant-time
+========================================================= */
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func generateKey() (raw, prefix string, err error) {
+	lookup, err := randomHex(4)
+	if err != nil {
+		return "", "", err
+	}
+	secret, err := randomHex(32)
+	if err != nil {
+		return "", "", err
+	}
+	prefix = KeyPrefix + lookup
+	return prefix + "_" + secret, prefix, nil
+}
+
+func hashKey(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+// LooksLikeKey: dipakai middleware untuk memilih jalur API key vs JWT.
+func LooksLikeKey(raw string) bool {
+	return strings.HasPrefix(strings.TrimSpace(raw), KeyPrefix)
+}
+
+func splitPrefix(raw string) (string, bool) {
+	raw = strings.TrimSpace(raw)
+	if !LooksLikeKey(raw) {
+		return "", false
+	}
+	i := strings.Index(raw[len(KeyPrefix):], "_")
+	if i <= 0 {
+		return "", false
+	}
+	return raw[:len(KeyPrefix)+i], true
+}
+
+/* =========================================================
+   Validasi input
+========================================================= */
+
+func normalizeScopes(in []string) ([]string, error) {
+	set := map[string]bool{}
+	for _, p := range in {
+		p = strings.ToLower(strings.TrimSpace(p))
+		if p == "" {
+			continue
+		}
+		if !permsvc.Grantable(p) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidScope, p)
+		}
+		set[p] = true
+	}
+	if len(set) == 0 {
+		return nil, ErrScopesEmpty
+	}
+	out := make([]string, 0, len(set))
+	for p := range set {
+		out = append(out, p)
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// NormalizeIPs: IP tunggal / CIDR → bentuk kanonik (juga dipakai allowlist mesin absen).
+func NormalizeIPs(in []string) ([]string, error) {
+	set := map[string]bool{}
+	for _, s := range in {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		if strings.Contains(s, "/") {
+			_, n, err := net.ParseCIDR(s)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s", ErrInvalidIP, s)
+			}
+			set[n.String()] = true
+			continue
+		}
+		ip := net.ParseIP(s)
+		if ip == nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidIP, s)
+		}
+		set[ip.String()] = true
+	}
+	if len(set) > maxAllowedIPs {
+		return nil, ErrTooManyIPRules
+	}
+	out := make([]string, 0, len(set))
+	for s := range set {
+		out = append(out, s)
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+func validateExpiry(t time.Time, now time.Time) (time.Time, error) {
+	if !t.After(now) || t.Sub(now) > maxKeyTTL {
+		return time.Time{}, ErrInvalidExpiry
+	}
+	return t.UTC(), nil
+}
+
+// IPAllowed: allowlist kosong = semua IP.
+func IPAllowed(allowed []string, ip string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	addr := net.ParseIP(strings.TrimSpace(ip))
+	if addr == nil {
+		return false
+	}
+	for _, rule := range allowed {
+		if strings.Contains(rule, "/") {
+			if _, n, err := net.ParseCIDR(rule); err == nil && n.Contains(addr) {
+				return true
+			}
+			continue
+		}
+		if r := net.ParseIP(rule); r != nil && r.Equal(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+/* =========================================================
+   CRUD
+========================================================= */
+
+type KeyInput struct {
+	Name       *string
+	Scopes     []string
+	SetScopes  bool
+	AllowedIPs []string
+	SetIPs     bool
+	ExpiresAt  *time.Time
+}
+
+func ListKeys(ctx context.Context, db *gorm.DB, schoolID uuid.UUID, includeRevoked bool) ([]model.SchoolAPIKeyModel, error) {
+	q := db.WithContext(ctx).Where("school_api_key_school_id = ?", schoolID)
+	if !includeRevoked {
+		q = q.Where("school_api_key_revoked_at IS NULL")
+	}
+	var rows []model.SchoolAPIKeyModel
+	err := q.Order("school_api_key_created_at DESC").Find(&rows).Error
+	return rows, err
+}
+
+func GetKey(ctx context.Context, db *gorm.DB, schoolID, id uuid.UUID) (*model.SchoolAPIKeyModel, error) {
+	var m model.SchoolAPIKeyModel
+	err := db.WithContext(ctx).
+		Where("school_api_key_id = ? AND school_api_key_school_id = ?", id, schoolID).
+		Take(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// CreateKey mengembalikan model + key mentah (hanya sekali ini).
+func CreateKey(ctx context.Context, db *gorm.DB, schoolID uuid.UUID, in KeyInput, by *uuid.UUID) (*model.SchoolAPIKeyModel, string, error) {
+	now := time.Now().UTC()
+
+	name := ""
+	if in.Name != nil {
+		name = strings.TrimSpace(*in.Name)
+	}
+	if name == "" {
+		return nil, "", ErrNameEmpty
+	}
+	scopes, err := normalizeScopes(in.Scopes)
+	if err != nil {
+		return nil, "", err
+	}
+	ips, err := NormalizeIPs(in.AllowedIPs)
+	if err != nil {
+		return nil, "", err
+	}
+	expires := now.Add(defaultKeyTTL)
+	if in.ExpiresAt != nil {
+		if expires, err = validateExpiry(*in.ExpiresAt, now); err != nil {
+			return nil, "", err
+		}
+	}
+
+	var active int64
+	if err := db.WithContext(ctx).Model(&model.SchoolAPIKeyModel{}).
+		Where("school_api_key_school_id = ? AND school_api_key_revoked_at IS NULL AND school_api_key_expires_at > ?", schoolID, now).
+		Count(&active).Error; err != nil {
+		return nil, "", err
+	}
+	if active >= maxActiveKeysPerSchool {
+		return nil, "", ErrTooManyKeys
+	}
+
+	raw, prefix, err := generateKey()
+	if err != nil {
+		return nil, "", err
+	}
+	m := &model.SchoolAPIKeyModel{
+		SchoolAPIKeySchoolID:   schoolID,
+		SchoolAPIKeyName:       name,
+		SchoolAPIKeyPrefix:     prefix,
+		SchoolAPIKeyHash:       hashKey(raw),
+		SchoolAPIKeyScopes:     pq.StringArray(scopes),
+		SchoolAPIKeyAllowedIPs: pq.StringArray(ips),
+		SchoolAPIKeyExpiresAt:  expires,
+		SchoolAPIKeyCreatedBy:  by,
+	}
+	if err := db.WithContext(ctx).Create(m).Error; err != nil {
+		return nil, "", err
+	}
+	return m, raw, nil
+}
+
+// UpdateKey: nama, scopes, allowlist IP, masa berlaku (key yang dicabut tidak bisa diubah).
+func UpdateKey(ctx context.Context, db *gorm.DB, schoolID, id uuid.UUID, in KeyInput) (*model.SchoolAPIKeyModel, error) {
+	m, err := GetKey(ctx, db, schoolID, id)
+	if err != nil {
+		return nil, err
+	}
+	if m.SchoolAPIKeyRevokedAt != nil {
+		return nil, ErrKeyRevoked
+	}
+
+	upd := map[string]any{}
+	if in.Name != nil {
+		name := strings.TrimSpace(*in.Name)
+		if name == "" {
+			return nil, ErrNameEmpty
+		}
+		upd["school_api_key_name"] = name
+	}
+	if in.SetScopes {
+		scopes, err := normalizeScopes(in.Scopes)
+		if err != nil {
+			return nil, err
+		}
+		upd["school_api_key_scopes"] = pq.StringArray(scopes)
+	}
+	if in.SetIPs {
+		ips, err := NormalizeIPs(in.AllowedIPs)
+		if err != nil {
+			return nil, err
+		}
+		upd["school_api_key_allowed_ips"] = pq.StringArray(ips)
+	}
+	if in.ExpiresAt != nil {
+		exp, err := validateExpiry(*in.ExpiresAt, time.Now().UTC())
+		if err != nil {
+			return nil, err
+		}
+		upd["school_api_key_expires_at"] = exp
+	}
+	if len(upd) == 0 {
+		return m, nil
+	}
+	upd["school_api_key_updated_at"] = time.Now().UTC()
+
+	if err := db.WithContext(ctx).Model(m).Updates(upd).Error; err != nil {
+		return nil, err
+	}
+	return GetKey(ctx, db, schoolID, id)
+}
+
+// RevokeKey: idempoten; key yang dicabut langsung ditolak di request berikutnya.
+func RevokeKey(ctx context.Context, db *gorm.DB, schoolID, id uuid.UUID, by *uuid.UUID) (*model.SchoolAPIKeyModel, error) {
+	m, err := GetKey(ctx, db, schoolID, id)
+	if err != nil {
+		return nil, err
+	}
+	if m.SchoolAPIKeyRevokedAt != nil {
+		return m, nil
+	}
+	now := time.Now().UTC()
+	if err := db.WithContext(ctx).Model(m).Updates(map[string]any{
+		"school_api_key_revoked_at": now,
+		"school_api_key_revoked_by": by,
+		"school_api_key_updated_at": now,
+	}).Error; err != nil {
+		return nil, err
+	}
+	return GetKey(ctx, db, schoolID, id)
+}
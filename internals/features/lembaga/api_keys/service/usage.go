@@ -0,0 +1,168 @@
+// file: internals/features/lembaga/api_keys/service/usage.go
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	model "madinahsalam_backend/internals/features/lembaga/api_keys/model"
+)
+
+/* =========================================================
+   Pelacakan pemakaian
+   - tiap request hanya menambah counter di memori
+   - flusher periodik menulis usage_count/last_used_* + rekap harian
+   (mesin absen bisa kirim ratusan request/menit; jangan UPDATE per request)
+========================================================= */
+
+type usageKey struct {
+	KeyID uuid.UUID
+	Day   string // YYYY-MM-DD (UTC)
+}
+
+type usageAgg struct {
+	Count  int64
+	LastAt time.Time
+	LastIP string
+}
+
+var (
+	usageMu  sync.Mutex
+	usageBuf = map[usageKey]*usageAgg{}
+)
+
+const usageFlushInterval = 30 * time.Second
+
+// TrackUsage: catat 1 request (non-blocking, tanpa DB).
+func TrackUsage(keyID uuid.UUID, ip string) {
+	now := time.Now().UTC()
+	k := usageKey{KeyID: keyID, Day: now.Format("2006-01-02")}
+
+	usageMu.Lock()
+	defer usageMu.Unlock()
+	a := usageBuf[k]
+	if a == nil {
+		a = &usageAgg{}
+		usageBuf[k] = a
+	}
+	a.Count++
+	a.LastAt, a.LastIP = now, ip
+}
+
+func drainUsage() map[usageKey]*usageAgg {
+	usageMu.Lock()
+	defer usageMu.Unlock()
+	out := usageBuf
+	usageBuf = map[usageKey]*usageAgg{}
+	return out
+}
+
+// FlushUsage menulis buffer ke DB. Gagal tulis → counter dikembalikan ke buffer.
+func FlushUsage(ctx context.Context, db *gorm.DB) {
+	buf := drainUsage()
+	if len(buf) == 0 {
+		return
+	}
+	for k, a := range buf {
+		if err := flushOne(ctx, db, k, a); err != nil {
+			log.Printf("[api_keys] flush usage %s: %v", k.KeyID, err)
+			usageMu.Lock()
+			if cur := usageBuf[k]; cur != nil {
+				cur.Count += a.Count
+			} else {
+				usageBuf[k] = a
+			}
+			usageMu.Unlock()
+		}
+	}
+}
+
+func flushOne(ctx context.Context, db *gorm.DB, k usageKey, a *usageAgg) error {
+	day, _ := time.Parse("2006-01-02", k.Day)
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.SchoolAPIKeyModel{}).
+			Where("school_api_key_id = ?", k.KeyID).
+			Updates(map[string]any{
+				"school_api_key_usage_count":  gorm.Expr("school_api_key_usage_count + ?", a.Count),
+				"school_api_key_last_used_at": gorm.Expr("GREATEST(COALESCE(school_api_key_last_used_at, ?), ?)", a.LastAt, a.LastAt),
+				"school_api_key_last_used_ip": a.LastIP,
+			}).Error; err != nil {
+			return err
+		}
+		row := model.SchoolAPIKeyUsageDailyModel{
+			SchoolAPIKeyUsageKeyID: k.KeyID,
+			SchoolAPIKeyUsageDate:  day,
+			SchoolAPIKeyUsageCount: a.Count,
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "school_api_key_usage_key_id"}, {Name: "school_api_key_usage_date"}},
+			DoUpdates: clause.Assignments(map[string]any{
+				"school_api_key_usage_count": gorm.Expr("school_api_key_usage_daily.school_api_key_usage_count + EXCLUDED.school_api_key_usage_count"),
+			}),
+		}).Create(&row).Error
+	})
+}
+
+// RunUsageFlusher: loop flush sampai ctx selesai (flush terakhir saat shutdown).
+func RunUsageFlusher(ctx context.Context, db *gorm.DB) {
+	t := time.NewTicker(usageFlushInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			fctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			FlushUsage(fctx, db)
+			cancel()
+			return
+		case <-t.C:
+			FlushUsage(ctx, db)
+		}
+	}
+}
+
+// DailyUsage: rekap N hari terakhir (termasuk counter yang belum di-flush).
+func DailyUsage(ctx context.Context, db *gorm.DB, keyID uuid.UUID, days int) ([]model.SchoolAPIKeyUsageDailyModel, error) {
+	if days <= 0 {
+		days = 30
+	}
+	since := time.Now().UTC().AddDate(0, 0, -(days - 1)).Format("2006-01-02")
+
+	var rows []model.SchoolAPIKeyUsageDailyModel
+	if err := db.WithContext(ctx).
+		Where("school_api_key_usage_key_id = ? AND school_api_key_usage_date >= ?", keyID, since).
+		Order("school_api_key_usage_date DESC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	usageMu.Lock()
+	defer usageMu.Unlock()
+	for k, a := range usageBuf {
+		if k.KeyID != keyID || k.Day < since {
+			continue
+		}
+		merged := false
+		for i := range rows {
+			if rows[i].SchoolAPIKeyUsageDate.Format("2006-01-02") == k.Day {
+				rows[i].SchoolAPIKeyUsageCount += a.Count
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			day, _ := time.Parse("2006-01-02", k.Day)
+			rows = append([]model.SchoolAPIKeyUsageDailyModel{{
+				SchoolAPIKeyUsageKeyID: keyID,
+				SchoolAPIKeyUsageDate:  day,
+				SchoolAPIKeyUsageCount: a.Count,
+			}}, rows...)
+		}
+	}
+	return rows, nil
+}
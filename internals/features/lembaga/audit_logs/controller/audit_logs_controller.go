@@ -0,0 +1,284 @@
+// file: internals/features/lembaga/audit_logs/controller/audit_logs_controller.go
+package controller
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	dto "madinahsalam_backend/internals/features/lembaga/audit_logs/dto"
+	model "madinahsalam_backend/internals/features/lembaga/audit_logs/model"
+	svc "madinahsalam_backend/internals/features/lembaga/audit_logs/service"
+	helper "madinahsalam_backend/internals/helpers"
+	helperAuth "madinahsalam_backend/internals/helpers/auth"
+)
+
+/*
+Audit trail (read-only; log ditulis callback GORM)
+
+GET /api/a/audit-logs?table=&entity_id=&actor_user_id=&action=&request_id=&from=&to=
+GET /api/a/audit-logs/entity/:table/:entity_id   riwayat 1 entitas
+GET /api/a/audit-logs/actor/:user_id             jejak 1 aktor
+GET /api/a/audit-logs/settings                   retensi sekolah
+PUT /api/a/audit-logs/settings                   {"retention_days": 365 | null}
+GET /api/a/audit-logs/:id
+
+GET /api/o/audit-logs[...]                       owner: lintas tenant (?school_id= opsional)
+*/
+
+type AuditLogController struct {
+	DB  *gorm.DB
+	Cfg svc.Config
+}
+
+func NewAuditLogController(db *gorm.DB) *AuditLogController {
+	return &AuditLogController{DB: db, Cfg: svc.LoadConfig()}
+}
+
+func resolveAdminSchool(c *fiber.Ctx) (uuid.UUID, error) {
+	schoolID, err := helperAuth.ResolveSchoolIDFromContext(c)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if err := helperAuth.EnsureDKMSchool(c, schoolID); err != nil {
+		return uuid.Nil, err
+	}
+	return schoolID, nil
+}
+
+// scope: admin → sekolah aktif (wajib); owner → ?school_id= opsional.
+func (h *AuditLogController) scope(c *fiber.Ctx, owner bool) (*uuid.UUID, error) {
+	if !owner {
+		id, err := resolveAdminSchool(c)
+		if err != nil {
+			return nil, err
+		}
+		return &id, nil
+	}
+	if s := strings.TrimSpace(c.Query("school_id")); s != "" {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return nil, fiber.NewError(fiber.StatusBadRequest, "school_id tidak valid")
+		}
+		return &id, nil
+	}
+	return nil, nil
+}
+
+func writeErr(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, svc.ErrLogNotFound):
+		return helper.JsonError(c, fiber.StatusNotFound, err.Error())
+	case errors.Is(err, svc.ErrInvalidRetention):
+		return helper.JsonError(c, fiber.StatusBadRequest, err.Error())
+	}
+	var fe *fiber.Error
+	if errors.As(err, &fe) {
+		return helper.JsonError(c, fe.Code, fe.Message)
+	}
+	return helper.JsonError(c, fiber.StatusInternalServerError, err.Error())
+}
+
+func parseFilter(c *fiber.Ctx) (svc.Filter, error) {
+	f := svc.Filter{
+		Table:     strings.TrimSpace(c.Query("table")),
+		EntityID:  strings.TrimSpace(c.Query("entity_id")),
+		RequestID: strings.TrimSpace(c.Query("request_id")),
+	}
+	if s := strings.TrimSpace(c.Query("actor_user_id")); s != "" {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return f, fiber.NewError(fiber.StatusBadRequest, "actor_user_id tidak valid")
+		}
+		f.ActorUserID = &id
+	}
+	switch a := model.AuditAction(strings.ToLower(strings.TrimSpace(c.Query("action")))); a {
+	case "":
+	case model.AuditActionCreate, model.AuditActionUpdate, model.AuditActionDelete:
+		f.Action = a
+	default:
+		return f, fiber.NewError(fiber.StatusBadRequest, "action harus create|update|delete")
+	}
+	for key, dst := range map[string]**time.Time{"from": &f.From, "to": &f.To} {
+		t, err := parseTime(c.Query(key))
+		if err != nil {
+			return f, fiber.NewError(fiber.StatusBadRequest, key+" harus RFC3339 atau YYYY-MM-DD")
+		}
+		*dst = t
+	}
+	return f, nil
+}
+
+func parseTime(s string) (*time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return &t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (h *AuditLogController) list(c *fiber.Ctx, owner bool, f svc.Filter) error {
+	schoolID, err := h.scope(c, owner)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	f.SchoolID = schoolID
+
+	p := helper.ResolvePaging(c, 50, 200)
+	rows, total, err := svc.List(c.Context(), h.DB, f, p.Limit, p.Offset)
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusInternalServerError, "Gagal mengambil log audit")
+	}
+	return helper.JsonList(c, "OK", dto.FromModels(rows), helper.BuildPaginationFromPage(total, p.Page, p.PerPage))
+}
+
+/* =========================================================
+   ADMIN
+========================================================= */
+
+// GET /api/a/audit-logs
+func (h *AuditLogController) List(c *fiber.Ctx) error {
+	f, err := parseFilter(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return h.list(c, false, f)
+}
+
+// GET /api/a/audit-logs/entity/:table/:entity_id
+func (h *AuditLogController) ByEntity(c *fiber.Ctx) error {
+	f, err := parseFilter(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	f.Table = strings.TrimSpace(c.Params("table"))
+	f.EntityID = strings.TrimSpace(c.Params("entity_id"))
+	return h.list(c, false, f)
+}
+
+// GET /api/a/audit-logs/actor/:user_id
+func (h *AuditLogController) ByActor(c *fiber.Ctx) error {
+	f, err := parseFilter(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	uid, err := uuid.Parse(strings.TrimSpace(c.Params("user_id")))
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "user_id tidak valid")
+	}
+	f.ActorUserID = &uid
+	return h.list(c, false, f)
+}
+
+// GET /api/a/audit-logs/:id
+func (h *AuditLogController) Detail(c *fiber.Ctx) error {
+	return h.detail(c, false)
+}
+
+func (h *AuditLogController) detail(c *fiber.Ctx, owner bool) error {
+	schoolID, err := h.scope(c, owner)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	id, err := uuid.Parse(strings.TrimSpace(c.Params("id")))
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "id tidak valid")
+	}
+	m, err := svc.Get(c.Context(), h.DB, schoolID, id)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonOK(c, "OK", dto.FromModel(m))
+}
+
+// GET /api/a/audit-logs/settings
+func (h *AuditLogController) GetSettings(c *fiber.Ctx) error {
+	schoolID, err := resolveAdminSchool(c)
+	if err != nil {
+		return err
+	}
+	days, override, err := svc.GetRetention(c.Context(), h.DB, h.Cfg, schoolID)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonOK(c, "OK", dto.RetentionResponse{SchoolID: schoolID, RetentionDays: days, IsDefault: !override})
+}
+
+// PUT /api/a/audit-logs/settings
+func (h *AuditLogController) UpdateSettings(c *fiber.Ctx) error {
+	schoolID, err := resolveAdminSchool(c)
+	if err != nil {
+		return err
+	}
+	var req dto.UpdateRetentionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "Payload tidak valid")
+	}
+	var by *uuid.UUID
+	if uid, err := helperAuth.GetUserIDFromToken(c); err == nil && uid != uuid.Nil {
+		by = &uid
+	}
+	if err := svc.SetRetention(c.Context(), h.DB, schoolID, req.RetentionDays, by); err != nil {
+		return writeErr(c, err)
+	}
+	days, override, err := svc.GetRetention(c.Context(), h.DB, h.Cfg, schoolID)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonUpdated(c, "Retensi audit diperbarui",
+		dto.RetentionResponse{SchoolID: schoolID, RetentionDays: days, IsDefault: !override})
+}
+
+/* =========================================================
+   OWNER (lintas tenant)
+========================================================= */
+
+// GET /api/o/audit-logs
+func (h *AuditLogController) OwnerList(c *fiber.Ctx) error {
+	f, err := parseFilter(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return h.list(c, true, f)
+}
+
+// GET /api/o/audit-logs/entity/:table/:entity_id
+func (h *AuditLogController) OwnerByEntity(c *fiber.Ctx) error {
+	f, err := parseFilter(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	f.Table = strings.TrimSpace(c.Params("table"))
+	f.EntityID = strings.TrimSpace(c.Params("entity_id"))
+	return h.list(c, true, f)
+}
+
+// GET /api/o/audit-logs/actor/:user_id
+func (h *AuditLogController) OwnerByActor(c *fiber.Ctx) error {
+	f, err := parseFilter(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	uid, err := uuid.Parse(strings.TrimSpace(c.Params("user_id")))
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "user_id tidak valid")
+	}
+	f.ActorUserID = &uid
+	return h.list(c, true, f)
+}
+
+// GET /api/o/audit-logs/:id
+func (h *AuditLogController) OwnerDetail(c *fiber.Ctx) error {
+	return h.detail(c, true)
+}
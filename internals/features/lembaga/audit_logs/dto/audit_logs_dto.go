@@ -0,0 +1,86 @@
+// file: internals/features/lembaga/audit_logs/dto/audit_logs_dto.go
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	model "madinahsalam_backend/internals/features/lembaga/audit_logs/model"
+)
+
+/* =========================================================
+   RESPONSE
+========================================================= */
+
+type AuditLogResponse struct {
+	AuditLogID       uuid.UUID  `json:"audit_log_id"`
+	AuditLogSchoolID *uuid.UUID `json:"audit_log_school_id,omitempty"`
+
+	AuditLogActorUserID *uuid.UUID `json:"audit_log_actor_user_id,omitempty"`
+	AuditLogActorRole   *string    `json:"audit_log_actor_role,omitempty"`
+	AuditLogRequestID   *string    `json:"audit_log_request_id,omitempty"`
+	AuditLogIP          *string    `json:"audit_log_ip,omitempty"`
+	AuditLogUserAgent   *string    `json:"audit_log_user_agent,omitempty"`
+	AuditLogMethod      *string    `json:"audit_log_method,omitempty"`
+	AuditLogPath        *string    `json:"audit_log_path,omitempty"`
+
+	AuditLogTable    string            `json:"audit_log_table"`
+	AuditLogEntityID string            `json:"audit_log_entity_id"`
+	AuditLogAction   model.AuditAction `json:"audit_log_action"`
+
+	AuditLogBefore         json.RawMessage `json:"audit_log_before,omitempty"`
+	AuditLogAfter          json.RawMessage `json:"audit_log_after,omitempty"`
+	AuditLogChangedColumns []string        `json:"audit_log_changed_columns"`
+
+	AuditLogCreatedAt time.Time `json:"audit_log_created_at"`
+}
+
+func FromModel(m *model.AuditLogModel) AuditLogResponse {
+	changed := []string(m.AuditLogChangedColumns)
+	if changed == nil {
+		changed = []string{}
+	}
+	return AuditLogResponse{
+		AuditLogID:             m.AuditLogID,
+		AuditLogSchoolID:       m.AuditLogSchoolID,
+		AuditLogActorUserID:    m.AuditLogActorUserID,
+		AuditLogActorRole:      m.AuditLogActorRole,
+		AuditLogRequestID:      m.AuditLogRequestID,
+		AuditLogIP:             m.AuditLogIP,
+		AuditLogUserAgent:      m.AuditLogUserAgent,
+		AuditLogMethod:         m.AuditLogMethod,
+		AuditLogPath:           m.AuditLogPath,
+		AuditLogTable:          m.AuditLogTable,
+		AuditLogEntityID:       m.AuditLogEntityID,
+		AuditLogAction:         m.AuditLogAction,
+		AuditLogBefore:         json.RawMessage(m.AuditLogBefore),
+		AuditLogAfter:          json.RawMessage(m.AuditLogAfter),
+		AuditLogChangedColumns: changed,
+		AuditLogCreatedAt:      m.AuditLogCreatedAt,
+	}
+}
+
+func FromModels(rows []model.AuditLogModel) []AuditLogResponse {
+	out := make([]AuditLogResponse, 0, len(rows))
+	for i := range rows {
+		out = append(out, FromModel(&rows[i]))
+	}
+	return out
+}
+
+/* =========================================================
+   RETENSI
+========================================================= */
+
+// UpdateRetentionRequest: retention_days=null → kembali ke default global.
+type UpdateRetentionRequest struct {
+	RetentionDays *int `json:"retention_days"`
+}
+
+type RetentionResponse struct {
+	SchoolID      uuid.UUID `json:"school_id"`
+	RetentionDays int       `json:"retention_days"`
+	IsDefault     bool      `json:"is_default"`
+}
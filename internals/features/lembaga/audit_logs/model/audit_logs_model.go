@@ -0,0 +1,55 @@
+// file: internals/features/lembaga/audit_logs/model/audit_logs_model.go
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/datatypes"
+)
+
+type AuditAction string
+
+const (
+	AuditActionCreate AuditAction = "create"
+	AuditActionUpdate AuditAction = "update"
+	AuditActionDelete AuditAction = "delete"
+)
+
+// AuditLogModel: append-only (UPDATE/DELETE ditolak trigger, kecuali purge retensi).
+type AuditLogModel struct {
+	AuditLogID       uuid.UUID  `gorm:"column:audit_log_id;type:uuid;default:gen_random_uuid();primaryKey" json:"audit_log_id"`
+	AuditLogSchoolID *uuid.UUID `gorm:"column:audit_log_school_id;type:uuid" json:"audit_log_school_id,omitempty"`
+
+	AuditLogActorUserID *uuid.UUID `gorm:"column:audit_log_actor_user_id;type:uuid" json:"audit_log_actor_user_id,omitempty"`
+	AuditLogActorRole   *string    `gorm:"column:audit_log_actor_role;type:varchar(32)" json:"audit_log_actor_role,omitempty"`
+	AuditLogRequestID   *string    `gorm:"column:audit_log_request_id;type:varchar(64)" json:"audit_log_request_id,omitempty"`
+	AuditLogIP          *string    `gorm:"column:audit_log_ip;type:varchar(64)" json:"audit_log_ip,omitempty"`
+	AuditLogUserAgent   *string    `gorm:"column:audit_log_user_agent;type:text" json:"audit_log_user_agent,omitempty"`
+	AuditLogMethod      *string    `gorm:"column:audit_log_method;type:varchar(10)" json:"audit_log_method,omitempty"`
+	AuditLogPath        *string    `gorm:"column:audit_log_path;type:text" json:"audit_log_path,omitempty"`
+
+	AuditLogTable    string      `gorm:"column:audit_log_table;type:varchar(80);not null" json:"audit_log_table"`
+	AuditLogEntityID string      `gorm:"column:audit_log_entity_id;type:text;not null" json:"audit_log_entity_id"`
+	AuditLogAction   AuditAction `gorm:"column:audit_log_action;type:varchar(10);not null" json:"audit_log_action"`
+
+	AuditLogBefore         datatypes.JSON `gorm:"column:audit_log_before;type:jsonb" json:"audit_log_before,omitempty"`
+	AuditLogAfter          datatypes.JSON `gorm:"column:audit_log_after;type:jsonb" json:"audit_log_after,omitempty"`
+	AuditLogChangedColumns pq.StringArray `gorm:"column:audit_log_changed_columns;type:text[];not null;default:'{}'" json:"audit_log_changed_columns"`
+
+	AuditLogCreatedAt time.Time `gorm:"column:audit_log_created_at;autoCreateTime" json:"audit_log_created_at"`
+}
+
+func (AuditLogModel) TableName() string { return "audit_logs" }
+
+type AuditSettingModel struct {
+	AuditSettingSchoolID        uuid.UUID  `gorm:"column:audit_setting_school_id;type:uuid;primaryKey" json:"audit_setting_school_id"`
+	AuditSettingRetentionDays   int        `gorm:"column:audit_setting_retention_days;not null" json:"audit_setting_retention_days"`
+	AuditSettingUpdatedByUserID *uuid.UUID `gorm:"column:audit_setting_updated_by_user_id;type:uuid" json:"audit_setting_updated_by_user_id,omitempty"`
+
+	AuditSettingCreatedAt time.Time `gorm:"column:audit_setting_created_at;autoCreateTime" json:"audit_setting_created_at"`
+	AuditSettingUpdatedAt time.Time `gorm:"column:audit_setting_updated_at;autoUpdateTime" json:"audit_setting_updated_at"`
+}
+
+func (AuditSettingModel) TableName() string { return "audit_settings" }
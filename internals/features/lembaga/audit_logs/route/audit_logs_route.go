@@ -0,0 +1,49 @@
+// file: internals/features/lembaga/audit_logs/route/audit_logs_route.go
+package route
+
+import (
+	"madinahsalam_backend/internals/constants"
+	auditController "madinahsalam_backend/internals/features/lembaga/audit_logs/controller"
+	authMiddleware "madinahsalam_backend/internals/middlewares/auth"
+	schoolkuMiddleware "madinahsalam_backend/internals/middlewares/features"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// /api/a/audit-logs → DKM + Admin + Owner (school dari token)
+func AuditLogAdminRoutes(api fiber.Router, db *gorm.DB) {
+	ctl := auditController.NewAuditLogController(db)
+
+	g := api.Group("/audit-logs",
+		authMiddleware.OnlyRolesSlice(
+			constants.RoleErrorAdmin("melihat audit trail"),
+			constants.AdminAndAbove,
+		),
+		schoolkuMiddleware.IsSchoolAdmin(),
+	)
+
+	g.Get("/", ctl.List)
+	g.Get("/settings", ctl.GetSettings)
+	g.Put("/settings", ctl.UpdateSettings)
+	g.Get("/entity/:table/:entity_id", ctl.ByEntity)
+	g.Get("/actor/:user_id", ctl.ByActor)
+	g.Get("/:id", ctl.Detail)
+}
+
+// /api/o/audit-logs → owner global, lintas tenant
+func AuditLogOwnerRoutes(api fiber.Router, db *gorm.DB) {
+	ctl := auditController.NewAuditLogController(db)
+
+	g := api.Group("/audit-logs",
+		authMiddleware.OnlyRolesSlice(
+			constants.RoleErrorOwner("melihat audit trail lintas sekolah"),
+			constants.OwnerOnly,
+		),
+	)
+
+	g.Get("/", ctl.OwnerList)
+	g.Get("/entity/:table/:entity_id", ctl.OwnerByEntity)
+	g.Get("/actor/:user_id", ctl.OwnerByActor)
+	g.Get("/:id", ctl.OwnerDetail)
+}
@@ -0,0 +1,44 @@
+// file: internals/features/lembaga/audit_logs/scheduler/audit_retention_scheduler.go
+package scheduler
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"gorm.io/gorm"
+
+	svc "madinahsalam_backend/internals/features/lembaga/audit_logs/service"
+)
+
+// Jalan harian: hapus log audit yang melewati retensi (per sekolah / default global)
+func StartAuditRetentionScheduler(db *gorm.DB) {
+	cfg := svc.LoadConfig()
+	if !cfg.Enabled {
+		return
+	}
+	schedule := os.Getenv("AUDIT_RETENTION_CRON")
+	if schedule == "" {
+		schedule = "15 2 * * *"
+	}
+
+	c := cron.New(cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger)))
+	_, err := c.AddFunc(schedule, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+		defer cancel()
+
+		n, err := svc.Purge(ctx, db, cfg, time.Now())
+		if err != nil {
+			log.Printf("[AUDIT-RETENTION] error setelah %d baris: %v", n, err)
+			return
+		}
+		log.Printf("[AUDIT-RETENTION] purged=%d default_days=%d", n, cfg.RetentionDays)
+	})
+	if err != nil {
+		log.Fatalf("[AUDIT-RETENTION] add cron gagal: %v", err)
+	}
+	log.Printf("[AUDIT-RETENTION] started schedule=%q", schedule)
+	c.Start()
+}
@@ -0,0 +1,491 @@
+// file: internals/features/lembaga/audit_logs/service/audit_callback.go
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	model "madinahsalam_backend/internals/features/lembaga/audit_logs/model"
+)
+
+/* =========================================================
+   Callback GORM: create / update / delete → audit_logs
+
+   - Hanya statement ber-Model (punya schema + PK); Exec/Raw SQL tidak tercatat,
+     jadi perubahan yang perlu jejak (langganan, invoice, plan sekolah) ditulis lewat Model.
+   - Hanya bila ada Actor di context (request user); worker/seeder dilewati.
+   - Update/Delete: snapshot "before" diambil dengan WHERE yang sama,
+     "after" dibaca ulang per PK → diff kolom yang berubah.
+   - Insert log memakai ConnPool statement (ikut transaksi default GORM):
+     gagal menulis log → perubahan ikut di-rollback.
+========================================================= */
+
+const (
+	commitTx      = "gorm:commit_or_rollback_transaction"
+	settingBefore = "audit:before"
+	settingSkip   = "audit:skip"
+)
+
+// Skip: tx.Set(...) untuk menonaktifkan audit pada 1 statement (mis. update counter massal).
+func Skip(db *gorm.DB) *gorm.DB { return db.Set(settingSkip, true) }
+
+type auditor struct {
+	cfg Config
+}
+
+// Register memasang callback audit ke instance GORM (panggil sekali saat init DB).
+func Register(db *gorm.DB, cfg Config) error {
+	if !cfg.Enabled {
+		log.Println("[AUDIT] nonaktif (AUDIT_ENABLED=false)")
+		return nil
+	}
+	if !db.Migrator().HasTable(&model.AuditLogModel{}) {
+		log.Println("[AUDIT] tabel audit_logs belum ada → audit nonaktif (jalankan migrate up)")
+		return nil
+	}
+
+	a := &auditor{cfg: cfg}
+	cb := db.Callback()
+	if err := cb.Create().After("gorm:create").Before(commitTx).Register("audit:after_create", a.afterCreate); err != nil {
+		return err
+	}
+	if err := cb.Update().Before("gorm:update").Register("audit:before_update", a.before); err != nil {
+		return err
+	}
+	if err := cb.Update().After("gorm:update").Before(commitTx).Register("audit:after_update", a.afterUpdate); err != nil {
+		return err
+	}
+	if err := cb.Delete().Before("gorm:delete").Register("audit:before_delete", a.before); err != nil {
+		return err
+	}
+	if err := cb.Delete().After("gorm:delete").Before(commitTx).Register("audit:after_delete", a.afterDelete); err != nil {
+		return err
+	}
+	log.Printf("[AUDIT] aktif (retensi default %d hari, exclude=%d tabel)", cfg.RetentionDays, len(cfg.ExcludeTables))
+	return nil
+}
+
+func (a *auditor) actorOf(db *gorm.DB) *Actor {
+	st := db.Statement
+	if db.Error != nil || st.Schema == nil || len(st.Schema.PrimaryFields) == 0 {
+		return nil
+	}
+	if a.cfg.ExcludeTables[st.Table] {
+		return nil
+	}
+	if v, ok := st.Settings.Load(settingSkip); ok && v == true {
+		return nil
+	}
+	return ActorFrom(st.Context)
+}
+
+/* =========================================================
+   CREATE
+========================================================= */
+
+func (a *auditor) afterCreate(db *gorm.DB) {
+	actor := a.actorOf(db)
+	if actor == nil || db.Error != nil || db.RowsAffected == 0 {
+		return
+	}
+	st := db.Statement
+
+	var entries []*model.AuditLogModel
+	eachStruct(st.ReflectValue, func(rv reflect.Value) {
+		if len(entries) >= a.cfg.MaxRows {
+			return
+		}
+		row := structRow(st, rv)
+		entries = append(entries, a.entry(st, actor, row, model.AuditActionCreate, nil, row, nil))
+	})
+	a.write(db, entries)
+}
+
+/* =========================================================
+   UPDATE / DELETE
+========================================================= */
+
+func (a *auditor) before(db *gorm.DB) {
+	if a.actorOf(db) == nil {
+		return
+	}
+	rows, err := a.snapshot(db, nil)
+	if err != nil {
+		db.AddError(fmt.Errorf("audit snapshot %s: %w", db.Statement.Table, err))
+		return
+	}
+	if len(rows) > 0 {
+		db.Statement.Settings.Store(settingBefore, rows)
+	}
+}
+
+func (a *auditor) afterUpdate(db *gorm.DB) {
+	v, ok := db.Statement.Settings.LoadAndDelete(settingBefore)
+	actor := a.actorOf(db)
+	if !ok || actor == nil || db.RowsAffected == 0 {
+		return
+	}
+	st := db.Statement
+	before := v.([]map[string]any)
+
+	after, err := a.snapshot(db, before)
+	if err != nil {
+		db.AddError(fmt.Errorf("audit reload %s: %w", st.Table, err))
+		return
+	}
+	byPK := make(map[string]map[string]any, len(after))
+	for _, r := range after {
+		byPK[entityID(st.Schema, r)] = r
+	}
+
+	var entries []*model.AuditLogModel
+	for _, b := range before {
+		aft, ok := byPK[entityID(st.Schema, b)]
+		if !ok {
+			continue // tidak lagi cocok (mis. soft-deleted lewat Updates)
+		}
+		changed := diffColumns(st.Schema, b, aft)
+		if len(changed) == 0 {
+			continue
+		}
+		bOnly := make(map[string]any, len(changed))
+		aOnly := make(map[string]any, len(changed))
+		for _, col := range changed {
+			bOnly[col], aOnly[col] = b[col], aft[col]
+		}
+		entries = append(entries, a.entry(st, actor, aft, model.AuditActionUpdate, bOnly, aOnly, changed))
+	}
+	a.write(db, entries)
+}
+
+func (a *auditor) afterDelete(db *gorm.DB) {
+	v, ok := db.Statement.Settings.LoadAndDelete(settingBefore)
+	actor := a.actorOf(db)
+	if !ok || actor == nil || db.RowsAffected == 0 {
+		return
+	}
+	st := db.Statement
+	var entries []*model.AuditLogModel
+	for _, b := range v.([]map[string]any) {
+		entries = append(entries, a.entry(st, actor, b, model.AuditActionDelete, b, nil, nil))
+	}
+	a.write(db, entries)
+}
+
+// snapshot: byPK=nil → pakai WHERE statement (+PK dari model); selain itu baca ulang per PK.
+func (a *auditor) snapshot(db *gorm.DB, byPK []map[string]any) ([]map[string]any, error) {
+	st := db.Statement
+	q := db.Session(&gorm.Session{NewDB: true, SkipHooks: true}).Table(st.Table)
+
+	if byPK != nil {
+		exprs := pkExprsFromRows(st.Schema, byPK)
+		if len(exprs) == 0 {
+			return nil, nil
+		}
+		q = q.Clauses(clause.Where{Exprs: exprs})
+	} else {
+		var exprs []clause.Expression
+		if c, ok := st.Clauses["WHERE"]; ok {
+			if w, ok := c.Expression.(clause.Where); ok {
+				exprs = append(exprs, w.Exprs...)
+			}
+		}
+		exprs = append(exprs, pkExprsFromModel(st)...)
+		if len(exprs) == 0 {
+			return nil, nil // update/delete global → ditolak GORM sendiri
+		}
+		if col := deletedAtColumn(st.Schema); col != "" && !st.Unscoped {
+			exprs = append(exprs, clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: col}, Value: nil})
+		}
+		q = q.Clauses(clause.Where{Exprs: exprs})
+	}
+
+	var rows []map[string]any
+	if err := q.Limit(a.cfg.MaxRows + 1).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	if len(rows) > a.cfg.MaxRows {
+		log.Printf("[AUDIT] %s: >%d baris terdampak, hanya %d pertama yang dicatat", st.Table, a.cfg.MaxRows, a.cfg.MaxRows)
+		rows = rows[:a.cfg.MaxRows]
+	}
+	for _, r := range rows {
+		normalizeRow(st.Schema, r)
+	}
+	return rows, nil
+}
+
+func (a *auditor) write(db *gorm.DB, entries []*model.AuditLogModel) {
+	if len(entries) == 0 {
+		return
+	}
+	err := db.Session(&gorm.Session{NewDB: true, SkipHooks: true}).
+		CreateInBatches(entries, 200).Error
+	if err != nil {
+		db.AddError(fmt.Errorf("audit write %s: %w", db.Statement.Table, err))
+	}
+}
+
+func (a *auditor) entry(st *gorm.Statement, actor *Actor, row map[string]any, action model.AuditAction,
+	before, after map[string]any, changed []string) *model.AuditLogModel {
+
+	e := &model.AuditLogModel{
+		AuditLogSchoolID:       schoolOf(st.Schema, row, actor),
+		AuditLogActorUserID:    actor.UserID,
+		AuditLogActorRole:      nonEmpty(actor.Role),
+		AuditLogRequestID:      nonEmpty(actor.RequestID),
+		AuditLogIP:             nonEmpty(actor.IP),
+		AuditLogUserAgent:      nonEmpty(actor.UserAgent),
+		AuditLogMethod:         nonEmpty(actor.Method),
+		AuditLogPath:           nonEmpty(actor.Path),
+		AuditLogTable:          st.Table,
+		AuditLogEntityID:       entityID(st.Schema, row),
+		AuditLogAction:         action,
+		AuditLogBefore:         a.toJSON(before),
+		AuditLogAfter:          a.toJSON(after),
+		AuditLogChangedColumns: pq.StringArray(changed),
+	}
+	if e.AuditLogChangedColumns == nil {
+		e.AuditLogChangedColumns = pq.StringArray{}
+	}
+	return e
+}
+
+func (a *auditor) toJSON(m map[string]any) datatypes.JSON {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		if a.isMasked(k) {
+			if v != nil {
+				v = "***"
+			}
+		}
+		out[k] = v
+	}
+	b, err := json.Marshal(out)
+	if err != nil {
+		return datatypes.JSON(fmt.Sprintf(`{"_error":%q}`, err.Error()))
+	}
+	return datatypes.JSON(b)
+}
+
+func (a *auditor) isMasked(col string) bool {
+	for _, s := range a.cfg.MaskColumns {
+		if strings.Contains(col, s) {
+			return true
+		}
+	}
+	return false
+}
+
+/* =========================================================
+   Helpers: baris, PK, diff
+========================================================= */
+
+func eachStruct(rv reflect.Value, fn func(reflect.Value)) {
+	rv = reflect.Indirect(rv)
+	switch rv.Kind() {
+	case reflect.Struct:
+		fn(rv)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			if el := reflect.Indirect(rv.Index(i)); el.Kind() == reflect.Struct {
+				fn(el)
+			}
+		}
+	}
+}
+
+func structRow(st *gorm.Statement, rv reflect.Value) map[string]any {
+	row := make(map[string]any, len(st.Schema.DBNames))
+	for _, f := range st.Schema.Fields {
+		if f.DBName == "" {
+			continue
+		}
+		v, _ := f.ValueOf(st.Context, rv)
+		row[f.DBName] = derefValue(v)
+	}
+	return row
+}
+
+func derefValue(v any) any {
+	rv := reflect.ValueOf(v)
+	for rv.IsValid() && rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return nil
+	}
+	return rv.Interface()
+}
+
+// normalizeRow: hasil scan map (pgx) → jsonb datang sebagai string; jadikan RawMessage
+// supaya before/after tersimpan sebagai JSON, bukan string ter-escape.
+func normalizeRow(s *schema.Schema, row map[string]any) {
+	for k, v := range row {
+		f := s.LookUpField(k)
+		if f == nil {
+			continue
+		}
+		typ := strings.ToLower(string(f.DataType) + " " + f.TagSettings["TYPE"])
+		if !strings.Contains(typ, "json") {
+			continue
+		}
+		var raw []byte
+		switch t := v.(type) {
+		case string:
+			raw = []byte(t)
+		case []byte:
+			raw = t
+		default:
+			continue
+		}
+		if json.Valid(raw) {
+			row[k] = json.RawMessage(raw)
+		}
+	}
+}
+
+func pkExprsFromModel(st *gorm.Statement) []clause.Expression {
+	pks := st.Schema.PrimaryFields
+	var values [][]any
+	eachStruct(st.ReflectValue, func(rv reflect.Value) {
+		vals := make([]any, len(pks))
+		for i, f := range pks {
+			v, zero := f.ValueOf(st.Context, rv)
+			if zero {
+				return
+			}
+			vals[i] = v
+		}
+		values = append(values, vals)
+	})
+	return pkIn(pks, values)
+}
+
+func pkExprsFromRows(s *schema.Schema, rows []map[string]any) []clause.Expression {
+	values := make([][]any, 0, len(rows))
+	for _, r := range rows {
+		vals := make([]any, len(s.PrimaryFields))
+		for i, f := range s.PrimaryFields {
+			vals[i] = r[f.DBName]
+		}
+		values = append(values, vals)
+	}
+	return pkIn(s.PrimaryFields, values)
+}
+
+func pkIn(pks []*schema.Field, values [][]any) []clause.Expression {
+	if len(values) == 0 {
+		return nil
+	}
+	if len(pks) == 1 {
+		flat := make([]any, len(values))
+		for i, v := range values {
+			flat[i] = v[0]
+		}
+		return []clause.Expression{clause.IN{
+			Column: clause.Column{Table: clause.CurrentTable, Name: pks[0].DBName},
+			Values: flat,
+		}}
+	}
+	cols := make([]clause.Column, len(pks))
+	for i, f := range pks {
+		cols[i] = clause.Column{Table: clause.CurrentTable, Name: f.DBName}
+	}
+	return []clause.Expression{clause.IN{Column: cols, Values: toAnySlice(values)}}
+}
+
+func toAnySlice(values [][]any) []any {
+	out := make([]any, len(values))
+	for i, v := range values {
+		out[i] = v
+	}
+	return out
+}
+
+func deletedAtColumn(s *schema.Schema) string {
+	deletedAt := reflect.TypeOf(gorm.DeletedAt{})
+	for _, f := range s.Fields {
+		if f.FieldType == deletedAt {
+			return f.DBName
+		}
+	}
+	return ""
+}
+
+func entityID(s *schema.Schema, row map[string]any) string {
+	parts := make([]string, len(s.PrimaryFields))
+	for i, f := range s.PrimaryFields {
+		parts[i] = fmt.Sprint(derefValue(row[f.DBName]))
+	}
+	return strings.Join(parts, ",")
+}
+
+// diffColumns: urut sesuai schema; perubahan *_updated_at saja tidak dihitung.
+func diffColumns(s *schema.Schema, before, after map[string]any) []string {
+	var changed []string
+	meaningful := false
+	for _, col := range s.DBNames {
+		b, okB := before[col]
+		a, okA := after[col]
+		if !okB && !okA {
+			continue
+		}
+		if sameValue(b, a) {
+			continue
+		}
+		changed = append(changed, col)
+		if !strings.HasSuffix(col, "updated_at") {
+			meaningful = true
+		}
+	}
+	if !meaningful {
+		return nil
+	}
+	return changed
+}
+
+func sameValue(a, b any) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	if errA != nil || errB != nil {
+		return reflect.DeepEqual(a, b)
+	}
+	return bytes.Equal(ja, jb)
+}
+
+// schoolOf: kolom school_id / *_school_id pertama (urut schema), fallback sekolah aktif aktor.
+func schoolOf(s *schema.Schema, row map[string]any, actor *Actor) *uuid.UUID {
+	for _, col := range s.DBNames {
+		if col != "school_id" && !strings.HasSuffix(col, "_school_id") {
+			continue
+		}
+		if id, err := uuid.Parse(fmt.Sprint(derefValue(row[col]))); err == nil && id != uuid.Nil {
+			return &id
+		}
+	}
+	return actor.SchoolID
+}
+
+func nonEmpty(s string) *string {
+	if s = strings.TrimSpace(s); s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,46 @@
+// file: internals/features/lembaga/audit_logs/service/audit_context.go
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// LocalsKey: key c.Locals tempat middleware menyimpan *Actor.
+// Fiber Locals = fasthttp UserValue, dan RequestCtx.Value(string) membaca UserValue,
+// jadi query yang memakai WithContext(c.Context()) / c.UserContext() otomatis membawa aktor.
+const LocalsKey = "audit_actor"
+
+// Actor: siapa & dari request mana perubahan berasal.
+type Actor struct {
+	UserID    *uuid.UUID
+	SchoolID  *uuid.UUID // sekolah aktif; fallback bila baris tidak punya kolom *_school_id
+	Role      string
+	RequestID string
+	IP        string
+	UserAgent string
+	Method    string
+	Path      string
+}
+
+type actorCtxKey struct{}
+
+// WithActor: untuk job/worker yang menulis atas nama user (tanpa fiber.Ctx).
+func WithActor(ctx context.Context, a *Actor) context.Context {
+	return context.WithValue(ctx, actorCtxKey{}, a)
+}
+
+// ActorFrom: nil → penulisan sistem (worker, seeder, migrasi) → tidak diaudit.
+func ActorFrom(ctx context.Context) *Actor {
+	if ctx == nil {
+		return nil
+	}
+	if a, ok := ctx.Value(actorCtxKey{}).(*Actor); ok && a != nil {
+		return a
+	}
+	if a, ok := ctx.Value(LocalsKey).(*Actor); ok && a != nil {
+		return a
+	}
+	return nil
+}
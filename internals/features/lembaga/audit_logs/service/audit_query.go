@@ -0,0 +1,85 @@
+// file: internals/features/lembaga/audit_logs/service/audit_query.go
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	model "madinahsalam_backend/internals/features/lembaga/audit_logs/model"
+)
+
+var ErrLogNotFound = errors.New("log audit tidak ditemukan")
+
+// Filter query log; SchoolID nil hanya untuk owner (lintas tenant).
+type Filter struct {
+	SchoolID    *uuid.UUID
+	Table       string
+	EntityID    string
+	ActorUserID *uuid.UUID
+	Action      model.AuditAction
+	RequestID   string
+	From, To    *time.Time
+}
+
+func (f Filter) apply(q *gorm.DB) *gorm.DB {
+	if f.SchoolID != nil {
+		q = q.Where("audit_log_school_id = ?", *f.SchoolID)
+	}
+	if f.Table != "" {
+		q = q.Where("audit_log_table = ?", f.Table)
+	}
+	if f.EntityID != "" {
+		q = q.Where("audit_log_entity_id = ?", f.EntityID)
+	}
+	if f.ActorUserID != nil {
+		q = q.Where("audit_log_actor_user_id = ?", *f.ActorUserID)
+	}
+	if f.Action != "" {
+		q = q.Where("audit_log_action = ?", f.Action)
+	}
+	if f.RequestID != "" {
+		q = q.Where("audit_log_request_id = ?", f.RequestID)
+	}
+	if f.From != nil {
+		q = q.Where("audit_log_created_at >= ?", *f.From)
+	}
+	if f.To != nil {
+		q = q.Where("audit_log_created_at < ?", *f.To)
+	}
+	return q
+}
+
+func List(ctx context.Context, db *gorm.DB, f Filter, limit, offset int) ([]model.AuditLogModel, int64, error) {
+	q := f.apply(db.WithContext(ctx).Model(&model.AuditLogModel{}))
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var rows []model.AuditLogModel
+	if err := q.Order("audit_log_created_at DESC, audit_log_id DESC").
+		Limit(limit).Offset(offset).
+		Find(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+	return rows, total, nil
+}
+
+func Get(ctx context.Context, db *gorm.DB, schoolID *uuid.UUID, id uuid.UUID) (*model.AuditLogModel, error) {
+	q := db.WithContext(ctx).Where("audit_log_id = ?", id)
+	if schoolID != nil {
+		q = q.Where("audit_log_school_id = ?", *schoolID)
+	}
+	var m model.AuditLogModel
+	if err := q.Take(&m).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrLogNotFound
+		}
+		return nil, err
+	}
+	return &m, nil
+}
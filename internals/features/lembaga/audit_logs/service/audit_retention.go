@@ -0,0 +1,153 @@
+// file: internals/features/lembaga/audit_logs/service/audit_retention.go
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	model "madinahsalam_backend/internals/features/lembaga/audit_logs/model"
+)
+
+/* =========================================================
+   Konfigurasi (ENV) + retensi per sekolah
+========================================================= */
+
+const (
+	MinRetentionDays = 30
+	MaxRetentionDays = 3650
+
+	purgeBatch = 5000
+)
+
+var ErrInvalidRetention = errors.New("retention_days harus di antara 30 dan 3650 hari")
+
+type Config struct {
+	Enabled       bool
+	RetentionDays int             // default bila sekolah tidak punya audit_settings
+	MaxRows       int             // batas baris yang dicatat per statement
+	ExcludeTables map[string]bool // tabel berisik / sensitif yang tidak diaudit
+	MaskColumns   []string        // substring nama kolom yang nilainya disamarkan
+}
+
+// Tabel yang tidak pernah diaudit (log sendiri, token, progres job).
+var defaultExclude = []string{
+	"audit_logs",
+	"token_blacklist",
+	"refresh_tokens",
+	"import_jobs",
+}
+
+func LoadConfig() Config {
+	cfg := Config{
+		Enabled:       !strings.EqualFold(strings.TrimSpace(os.Getenv("AUDIT_ENABLED")), "false"),
+		RetentionDays: envInt("AUDIT_RETENTION_DAYS", 365),
+		MaxRows:       envInt("AUDIT_MAX_ROWS_PER_STATEMENT", 200),
+		ExcludeTables: map[string]bool{},
+		MaskColumns:   []string{"password", "secret", "token"},
+	}
+	if cfg.RetentionDays < MinRetentionDays {
+		cfg.RetentionDays = MinRetentionDays
+	}
+	if cfg.MaxRows <= 0 {
+		cfg.MaxRows = 200
+	}
+	for _, t := range defaultExclude {
+		cfg.ExcludeTables[t] = true
+	}
+	for _, t := range strings.Split(os.Getenv("AUDIT_EXCLUDE_TABLES"), ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			cfg.ExcludeTables[t] = true
+		}
+	}
+	return cfg
+}
+
+func envInt(key string, def int) int {
+	if v := strings.TrimSpace(os.Getenv(key)); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	}
+	return def
+}
+
+// GetRetention: (hari, override?) — override=false berarti memakai default global.
+func GetRetention(ctx context.Context, db *gorm.DB, cfg Config, schoolID uuid.UUID) (int, bool, error) {
+	var s model.AuditSettingModel
+	err := db.WithContext(ctx).
+		Where("audit_setting_school_id = ?", schoolID).
+		Take(&s).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return cfg.RetentionDays, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return s.AuditSettingRetentionDays, true, nil
+}
+
+// SetRetention: days=nil → hapus override (kembali ke default global).
+func SetRetention(ctx context.Context, db *gorm.DB, schoolID uuid.UUID, days *int, by *uuid.UUID) error {
+	tx := db.WithContext(ctx)
+	if days == nil {
+		return tx.Where("audit_setting_school_id = ?", schoolID).
+			Delete(&model.AuditSettingModel{}).Error
+	}
+	if *days < MinRetentionDays || *days > MaxRetentionDays {
+		return ErrInvalidRetention
+	}
+	row := model.AuditSettingModel{
+		AuditSettingSchoolID:        schoolID,
+		AuditSettingRetentionDays:   *days,
+		AuditSettingUpdatedByUserID: by,
+	}
+	return tx.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "audit_setting_school_id"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"audit_setting_retention_days":     *days,
+			"audit_setting_updated_by_user_id": by,
+			"audit_setting_updated_at":         time.Now(),
+		}),
+	}).Create(&row).Error
+}
+
+// Purge menghapus log yang melewati retensi (per sekolah, fallback default global).
+// Trigger append-only hanya mengizinkan DELETE bila audit.allow_purge = 'on' (SET LOCAL per batch).
+func Purge(ctx context.Context, db *gorm.DB, cfg Config, now time.Time) (int64, error) {
+	var total int64
+	for {
+		var n int64
+		err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(`SET LOCAL audit.allow_purge = 'on'`).Error; err != nil {
+				return err
+			}
+			res := tx.Exec(`
+				DELETE FROM audit_logs
+				WHERE audit_log_id IN (
+				  SELECT l.audit_log_id
+				  FROM audit_logs l
+				  LEFT JOIN audit_settings s ON s.audit_setting_school_id = l.audit_log_school_id
+				  WHERE l.audit_log_created_at <
+				        ?::timestamptz - make_interval(days => COALESCE(s.audit_setting_retention_days, ?))
+				  LIMIT ?
+				)`, now, cfg.RetentionDays, purgeBatch)
+			n = res.RowsAffected
+			return res.Error
+		})
+		if err != nil {
+			return total, err
+		}
+		total += n
+		if n < purgeBatch || ctx.Err() != nil {
+			return total, ctx.Err()
+		}
+	}
+}
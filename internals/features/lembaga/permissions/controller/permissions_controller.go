@@ -0,0 +1,275 @@
+// file: internals/features/lembaga/permissions/controller/permissions_controller.go
+package controller
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	dto "madinahsalam_backend/internals/features/lembaga/permissions/dto"
+	svc "madinahsalam_backend/internals/features/lembaga/permissions/service"
+	helper "madinahsalam_backend/internals/helpers"
+	helperAuth "madinahsalam_backend/internals/helpers/auth"
+)
+
+/*
+Permission matrix (resource.action)
+
+GET    /api/a/permissions/registry                        daftar resource & action
+GET    /api/a/permissions/matrix                          role bawaan: default, override, efektif
+PUT    /api/a/permissions/roles/:role                     {"allow": [...], "deny": [...]}
+GET    /api/a/permissions/custom-roles
+POST   /api/a/permissions/custom-roles                    {"key","name","description","permissions"}
+PATCH  /api/a/permissions/custom-roles/:id
+DELETE /api/a/permissions/custom-roles/:id
+GET    /api/a/permissions/custom-roles/:id/members
+POST   /api/a/permissions/custom-roles/:id/members        {"user_id"}
+DELETE /api/a/permissions/custom-roles/:id/members/:user_id
+
+GET    /api/u/me/permissions                              permission efektif (untuk frontend)
+*/
+
+type PermissionController struct {
+	DB *gorm.DB
+}
+
+func NewPermissionController(db *gorm.DB) *PermissionController {
+	return &PermissionController{DB: db}
+}
+
+func writeErr(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, svc.ErrCustomRoleNotFound):
+		return helper.JsonError(c, fiber.StatusNotFound, err.Error())
+	case errors.Is(err, svc.ErrCustomRoleKeyTaken):
+		return helper.JsonError(c, fiber.StatusConflict, err.Error())
+	case errors.Is(err, svc.ErrInvalidPermission),
+		errors.Is(err, svc.ErrRoleNotOverridable),
+		errors.Is(err, svc.ErrAllowDenyOverlap),
+		errors.Is(err, svc.ErrInvalidCustomKey),
+		errors.Is(err, svc.ErrCustomRoleNameEmpty),
+		errors.Is(err, svc.ErrUserNotInSchool):
+		return helper.JsonError(c, fiber.StatusBadRequest, err.Error())
+	}
+	var fe *fiber.Error
+	if errors.As(err, &fe) {
+		return helper.JsonError(c, fe.Code, fe.Message)
+	}
+	return helper.JsonError(c, fiber.StatusInternalServerError, err.Error())
+}
+
+func actorID(c *fiber.Ctx) *uuid.UUID {
+	if uid, err := helperAuth.GetUserIDFromToken(c); err == nil && uid != uuid.Nil {
+		return &uid
+	}
+	return nil
+}
+
+func parseID(c *fiber.Ctx, key string) (uuid.UUID, error) {
+	id, err := uuid.Parse(strings.TrimSpace(c.Params(key)))
+	if err != nil {
+		return uuid.Nil, fiber.NewError(fiber.StatusBadRequest, key+" tidak valid")
+	}
+	return id, nil
+}
+
+/* =========================================================
+   REGISTRY & MATRIX
+========================================================= */
+
+// GET /api/a/permissions/registry
+func (h *PermissionController) Registry(c *fiber.Ctx) error {
+	return helper.JsonOK(c, "OK", fiber.Map{
+		"resources":         svc.Registry,
+		"permissions":       svc.AllPermissions(),
+		"overridable_roles": svc.OverridableRoles,
+	})
+}
+
+// GET /api/a/permissions/matrix
+func (h *PermissionController) Matrix(c *fiber.Ctx) error {
+	schoolID, err := svc.SchoolFromRequest(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	rows, err := svc.Matrix(c.Context(), h.DB, schoolID)
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusInternalServerError, "Gagal mengambil matrix hak akses")
+	}
+	return helper.JsonOK(c, "OK", rows)
+}
+
+// PUT /api/a/permissions/roles/:role
+func (h *PermissionController) UpdateRole(c *fiber.Ctx) error {
+	schoolID, err := svc.SchoolFromRequest(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	var req dto.UpdateRoleOverridesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "Payload tidak valid")
+	}
+	role := strings.TrimSpace(c.Params("role"))
+	if err := svc.SetRoleOverrides(c.Context(), h.DB, schoolID, role, req.Allow, req.Deny, actorID(c)); err != nil {
+		return writeErr(c, err)
+	}
+	rows, err := svc.Matrix(c.Context(), h.DB, schoolID)
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusInternalServerError, "Gagal mengambil matrix hak akses")
+	}
+	return helper.JsonUpdated(c, "Hak akses role diperbarui", rows)
+}
+
+/* =========================================================
+   CUSTOM ROLE
+========================================================= */
+
+// GET /api/a/permissions/custom-roles
+func (h *PermissionController) ListCustomRoles(c *fiber.Ctx) error {
+	schoolID, err := svc.SchoolFromRequest(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	rows, err := svc.ListCustomRoles(c.Context(), h.DB, schoolID)
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusInternalServerError, "Gagal mengambil custom role")
+	}
+	return helper.JsonOK(c, "OK", dto.FromCustomRoles(rows))
+}
+
+// POST /api/a/permissions/custom-roles
+func (h *PermissionController) CreateCustomRole(c *fiber.Ctx) error {
+	schoolID, err := svc.SchoolFromRequest(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	var req dto.CreateCustomRoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "Payload tidak valid")
+	}
+	m, err := svc.CreateCustomRole(c.Context(), h.DB, schoolID, req.ToInput())
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonCreated(c, "Custom role dibuat", dto.FromCustomRole(m))
+}
+
+// PATCH /api/a/permissions/custom-roles/:id
+func (h *PermissionController) UpdateCustomRole(c *fiber.Ctx) error {
+	schoolID, err := svc.SchoolFromRequest(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	id, err := parseID(c, "id")
+	if err != nil {
+		return writeErr(c, err)
+	}
+	var req dto.UpdateCustomRoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "Payload tidak valid")
+	}
+	m, err := svc.UpdateCustomRole(c.Context(), h.DB, schoolID, id, req.ToInput())
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonUpdated(c, "Custom role diperbarui", dto.FromCustomRole(m))
+}
+
+// DELETE /api/a/permissions/custom-roles/:id
+func (h *PermissionController) DeleteCustomRole(c *fiber.Ctx) error {
+	schoolID, err := svc.SchoolFromRequest(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	id, err := parseID(c, "id")
+	if err != nil {
+		return writeErr(c, err)
+	}
+	if err := svc.DeleteCustomRole(c.Context(), h.DB, schoolID, id); err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonDeleted(c, "Custom role dihapus", fiber.Map{"school_custom_role_id": id})
+}
+
+// GET /api/a/permissions/custom-roles/:id/members
+func (h *PermissionController) ListMembers(c *fiber.Ctx) error {
+	schoolID, err := svc.SchoolFromRequest(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	id, err := parseID(c, "id")
+	if err != nil {
+		return writeErr(c, err)
+	}
+	rows, err := svc.ListMembers(c.Context(), h.DB, schoolID, id)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonOK(c, "OK", rows)
+}
+
+// POST /api/a/permissions/custom-roles/:id/members
+func (h *PermissionController) AddMember(c *fiber.Ctx) error {
+	schoolID, err := svc.SchoolFromRequest(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	id, err := parseID(c, "id")
+	if err != nil {
+		return writeErr(c, err)
+	}
+	var req dto.AddMemberRequest
+	if err := c.BodyParser(&req); err != nil || req.UserID == uuid.Nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "user_id wajib diisi")
+	}
+	if err := svc.AddMember(c.Context(), h.DB, schoolID, id, req.UserID, actorID(c)); err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonCreated(c, "User ditambahkan ke custom role", fiber.Map{
+		"school_custom_role_id": id,
+		"user_id":               req.UserID,
+	})
+}
+
+// DELETE /api/a/permissions/custom-roles/:id/members/:user_id
+func (h *PermissionController) RemoveMember(c *fiber.Ctx) error {
+	schoolID, err := svc.SchoolFromRequest(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	id, err := parseID(c, "id")
+	if err != nil {
+		return writeErr(c, err)
+	}
+	userID, err := parseID(c, "user_id")
+	if err != nil {
+		return writeErr(c, err)
+	}
+	if err := svc.RemoveMember(c.Context(), h.DB, schoolID, id, userID); err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonDeleted(c, "User dikeluarkan dari custom role", fiber.Map{
+		"school_custom_role_id": id,
+		"user_id":               userID,
+	})
+}
+
+/* =========================================================
+   USER
+========================================================= */
+
+// GET /api/u/me/permissions — dipakai frontend untuk menampilkan/menyembunyikan menu.
+func (h *PermissionController) Me(c *fiber.Ctx) error {
+	schoolID, err := svc.SchoolFromRequest(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	eff, err := svc.ForRequest(c, schoolID)
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusInternalServerError, "Gagal menghitung hak akses")
+	}
+	return helper.JsonOK(c, "OK", eff)
+}
@@ -0,0 +1,102 @@
+// file: internals/features/lembaga/permissions/dto/permissions_dto.go
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	model "madinahsalam_backend/internals/features/lembaga/permissions/model"
+	svc "madinahsalam_backend/internals/features/lembaga/permissions/service"
+)
+
+/* =========================================================
+   REQUEST
+========================================================= */
+
+// PUT /permissions/roles/:role — mengganti seluruh override role tsb.
+type UpdateRoleOverridesRequest struct {
+	Allow []string `json:"allow"`
+	Deny  []string `json:"deny"`
+}
+
+type CreateCustomRoleRequest struct {
+	Key         string   `json:"key"`
+	Name        string   `json:"name"`
+	Description *string  `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+func (r CreateCustomRoleRequest) ToInput() svc.CustomRoleInput {
+	return svc.CustomRoleInput{
+		Key:         &r.Key,
+		Name:        &r.Name,
+		Description: r.Description,
+		Permissions: r.Permissions,
+		SetPerms:    true,
+	}
+}
+
+// PATCH: field nil = tidak diubah; permissions (bila dikirim) mengganti seluruh daftar.
+type UpdateCustomRoleRequest struct {
+	Key         *string   `json:"key"`
+	Name        *string   `json:"name"`
+	Description *string   `json:"description"`
+	Permissions *[]string `json:"permissions"`
+}
+
+func (r UpdateCustomRoleRequest) ToInput() svc.CustomRoleInput {
+	in := svc.CustomRoleInput{Key: r.Key, Name: r.Name, Description: r.Description}
+	if r.Permissions != nil {
+		in.Permissions, in.SetPerms = *r.Permissions, true
+	}
+	return in
+}
+
+type AddMemberRequest struct {
+	UserID uuid.UUID `json:"user_id"`
+}
+
+/* =========================================================
+   RESPONSE
+========================================================= */
+
+type CustomRoleResponse struct {
+	SchoolCustomRoleID          uuid.UUID `json:"school_custom_role_id"`
+	SchoolCustomRoleSchoolID    uuid.UUID `json:"school_custom_role_school_id"`
+	SchoolCustomRoleKey         string    `json:"school_custom_role_key"`
+	SchoolCustomRoleName        string    `json:"school_custom_role_name"`
+	SchoolCustomRoleDescription *string   `json:"school_custom_role_description,omitempty"`
+	SchoolCustomRolePermissions []string  `json:"school_custom_role_permissions"`
+	SchoolCustomRoleMemberCount *int64    `json:"school_custom_role_member_count,omitempty"`
+	SchoolCustomRoleCreatedAt   time.Time `json:"school_custom_role_created_at"`
+	SchoolCustomRoleUpdatedAt   time.Time `json:"school_custom_role_updated_at"`
+}
+
+func FromCustomRole(m *model.SchoolCustomRoleModel) CustomRoleResponse {
+	perms := []string(m.SchoolCustomRolePermissions)
+	if perms == nil {
+		perms = []string{}
+	}
+	return CustomRoleResponse{
+		SchoolCustomRoleID:          m.SchoolCustomRoleID,
+		SchoolCustomRoleSchoolID:    m.SchoolCustomRoleSchoolID,
+		SchoolCustomRoleKey:         m.SchoolCustomRoleKey,
+		SchoolCustomRoleName:        m.SchoolCustomRoleName,
+		SchoolCustomRoleDescription: m.SchoolCustomRoleDescription,
+		SchoolCustomRolePermissions: perms,
+		SchoolCustomRoleCreatedAt:   m.SchoolCustomRoleCreatedAt,
+		SchoolCustomRoleUpdatedAt:   m.SchoolCustomRoleUpdatedAt,
+	}
+}
+
+func FromCustomRoles(rows []svc.CustomRoleWithCount) []CustomRoleResponse {
+	out := make([]CustomRoleResponse, 0, len(rows))
+	for i := range rows {
+		r := FromCustomRole(&rows[i].SchoolCustomRoleModel)
+		n := rows[i].MemberCount
+		r.SchoolCustomRoleMemberCount = &n
+		out = append(out, r)
+	}
+	return out
+}
@@ -0,0 +1,59 @@
+// file: internals/features/lembaga/permissions/model/permissions_model.go
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+type PermissionEffect string
+
+const (
+	EffectAllow PermissionEffect = "allow"
+	EffectDeny  PermissionEffect = "deny"
+)
+
+// SchoolRolePermissionOverrideModel: allow/deny tambahan untuk role bawaan di 1 sekolah.
+type SchoolRolePermissionOverrideModel struct {
+	SchoolRolePermissionSchoolID   uuid.UUID        `gorm:"column:school_role_permission_school_id;type:uuid;primaryKey" json:"school_role_permission_school_id"`
+	SchoolRolePermissionRole       string           `gorm:"column:school_role_permission_role;type:varchar(32);primaryKey" json:"school_role_permission_role"`
+	SchoolRolePermissionPermission string           `gorm:"column:school_role_permission_permission;type:varchar(64);primaryKey" json:"school_role_permission_permission"`
+	SchoolRolePermissionEffect     PermissionEffect `gorm:"column:school_role_permission_effect;type:varchar(8);not null" json:"school_role_permission_effect"`
+	SchoolRolePermissionUpdatedBy  *uuid.UUID       `gorm:"column:school_role_permission_updated_by;type:uuid" json:"school_role_permission_updated_by,omitempty"`
+
+	SchoolRolePermissionCreatedAt time.Time `gorm:"column:school_role_permission_created_at;autoCreateTime" json:"school_role_permission_created_at"`
+}
+
+func (SchoolRolePermissionOverrideModel) TableName() string {
+	return "school_role_permission_overrides"
+}
+
+// SchoolCustomRoleModel: role buatan sekolah, permission-nya eksplisit.
+type SchoolCustomRoleModel struct {
+	SchoolCustomRoleID          uuid.UUID      `gorm:"column:school_custom_role_id;type:uuid;default:gen_random_uuid();primaryKey" json:"school_custom_role_id"`
+	SchoolCustomRoleSchoolID    uuid.UUID      `gorm:"column:school_custom_role_school_id;type:uuid;not null" json:"school_custom_role_school_id"`
+	SchoolCustomRoleKey         string         `gorm:"column:school_custom_role_key;type:varchar(48);not null" json:"school_custom_role_key"`
+	SchoolCustomRoleName        string         `gorm:"column:school_custom_role_name;type:varchar(120);not null" json:"school_custom_role_name"`
+	SchoolCustomRoleDescription *string        `gorm:"column:school_custom_role_description;type:text" json:"school_custom_role_description,omitempty"`
+	SchoolCustomRolePermissions pq.StringArray `gorm:"column:school_custom_role_permissions;type:text[];not null;default:'{}'" json:"school_custom_role_permissions"`
+
+	SchoolCustomRoleCreatedAt time.Time      `gorm:"column:school_custom_role_created_at;autoCreateTime" json:"school_custom_role_created_at"`
+	SchoolCustomRoleUpdatedAt time.Time      `gorm:"column:school_custom_role_updated_at;autoUpdateTime" json:"school_custom_role_updated_at"`
+	SchoolCustomRoleDeletedAt gorm.DeletedAt `gorm:"column:school_custom_role_deleted_at;index" json:"school_custom_role_deleted_at,omitempty"`
+}
+
+func (SchoolCustomRoleModel) TableName() string { return "school_custom_roles" }
+
+type SchoolCustomRoleMemberModel struct {
+	SchoolCustomRoleMemberRoleID     uuid.UUID  `gorm:"column:school_custom_role_member_role_id;type:uuid;primaryKey" json:"school_custom_role_member_role_id"`
+	SchoolCustomRoleMemberUserID     uuid.UUID  `gorm:"column:school_custom_role_member_user_id;type:uuid;primaryKey" json:"school_custom_role_member_user_id"`
+	SchoolCustomRoleMemberSchoolID   uuid.UUID  `gorm:"column:school_custom_role_member_school_id;type:uuid;not null" json:"school_custom_role_member_school_id"`
+	SchoolCustomRoleMemberAssignedBy *uuid.UUID `gorm:"column:school_custom_role_member_assigned_by;type:uuid" json:"school_custom_role_member_assigned_by,omitempty"`
+
+	SchoolCustomRoleMemberCreatedAt time.Time `gorm:"column:school_custom_role_member_created_at;autoCreateTime" json:"school_custom_role_member_created_at"`
+}
+
+func (SchoolCustomRoleMemberModel) TableName() string { return "school_custom_role_members" }
@@ -0,0 +1,39 @@
+// file: internals/features/lembaga/permissions/route/permissions_route.go
+package route
+
+import (
+	permController "madinahsalam_backend/internals/features/lembaga/permissions/controller"
+	schoolkuMiddleware "madinahsalam_backend/internals/middlewares/features"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// /api/a/permissions → kelola matrix & custom role (permissions.* tidak bisa didelegasikan)
+func PermissionAdminRoutes(api fiber.Router, db *gorm.DB) {
+	ctl := permController.NewPermissionController(db)
+
+	read := schoolkuMiddleware.RequirePermission("permissions.read")
+	write := schoolkuMiddleware.RequirePermission("permissions.write")
+
+	g := api.Group("/permissions")
+
+	g.Get("/registry", read, ctl.Registry)
+	g.Get("/matrix", read, ctl.Matrix)
+	g.Put("/roles/:role", write, ctl.UpdateRole)
+
+	g.Get("/custom-roles", read, ctl.ListCustomRoles)
+	g.Post("/custom-roles", write, ctl.CreateCustomRole)
+	g.Patch("/custom-roles/:id", write, ctl.UpdateCustomRole)
+	g.Delete("/custom-roles/:id", write, ctl.DeleteCustomRole)
+
+	g.Get("/custom-roles/:id/members", read, ctl.ListMembers)
+	g.Post("/custom-roles/:id/members", write, ctl.AddMember)
+	g.Delete("/custom-roles/:id/members/:user_id", write, ctl.RemoveMember)
+}
+
+// /api/u/me/permissions → permission efektif user di sekolah aktif
+func PermissionUserRoutes(api fiber.Router, db *gorm.DB) {
+	ctl := permController.NewPermissionController(db)
+	api.Get("/me/permissions", ctl.Me)
+}
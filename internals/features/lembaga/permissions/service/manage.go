@@ -0,0 +1,341 @@
+// file: internals/features/lembaga/permissions/service/manage.go
+package service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+
+	"madinahsalam_backend/internals/constants"
+	model "madinahsalam_backend/internals/features/lembaga/permissions/model"
+)
+
+var (
+	ErrInvalidPermission   = errors.New("permission tidak dikenal atau tidak boleh diberikan")
+	ErrRoleNotOverridable  = errors.New("role ini tidak bisa diubah hak aksesnya")
+	ErrAllowDenyOverlap    = errors.New("permission yang sama tidak boleh ada di allow dan deny")
+	ErrCustomRoleNotFound  = errors.New("custom role tidak ditemukan")
+	ErrCustomRoleKeyTaken  = errors.New("key custom role sudah dipakai di sekolah ini")
+	ErrInvalidCustomKey    = errors.New("key custom role harus huruf kecil/angka/underscore (2-48 karakter)")
+	ErrCustomRoleNameEmpty = errors.New("nama custom role wajib diisi")
+	ErrUserNotInSchool     = errors.New("user tidak terdaftar di sekolah ini")
+)
+
+var reCustomKey = regexp.MustCompile(`^[a-z][a-z0-9_]{1,47}$`)
+
+func normalizePatterns(in []string) ([]string, error) {
+	seen := map[string]bool{}
+	out := make([]string, 0, len(in))
+	for _, p := range in {
+		p = strings.ToLower(strings.TrimSpace(p))
+		if p == "" || seen[p] {
+			continue
+		}
+		if !Grantable(p) {
+			return nil, ErrInvalidPermission
+		}
+		seen[p] = true
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+/* =========================================================
+   Matrix role bawaan
+========================================================= */
+
+type RoleMatrixRow struct {
+	Role        string   `json:"role"`
+	Overridable bool     `json:"overridable"`
+	Defaults    []string `json:"defaults"`
+	Allow       []string `json:"allow"`
+	Deny        []string `json:"deny"`
+	Effective   []string `json:"effective"`
+}
+
+func Matrix(ctx context.Context, db *gorm.DB, schoolID uuid.UUID) ([]RoleMatrixRow, error) {
+	ov, err := loadOverrides(ctx, db, schoolID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]RoleMatrixRow, 0, len(constants.AllowedRoles))
+	for _, role := range constants.AllowedRoles {
+		o := ov[role]
+		row := RoleMatrixRow{
+			Role:        role,
+			Overridable: constants.ContainsRole(OverridableRoles, role),
+			Defaults:    nonNil(DefaultRolePermissions[role]),
+			Allow:       nonNil(o.Allow),
+			Deny:        nonNil(o.Deny),
+			Effective:   rolePermissions(role, ov),
+		}
+		out = append(out, row)
+	}
+	return out, nil
+}
+
+// SetRoleOverrides mengganti seluruh override role bawaan di sekolah.
+func SetRoleOverrides(ctx context.Context, db *gorm.DB, schoolID uuid.UUID, role string, allow, deny []string, by *uuid.UUID) error {
+	role = constants.NormalizeRole(role)
+	if !constants.ContainsRole(OverridableRoles, role) {
+		return ErrRoleNotOverridable
+	}
+	a, err := normalizePatterns(allow)
+	if err != nil {
+		return err
+	}
+	d, err := normalizePatterns(deny)
+	if err != nil {
+		return err
+	}
+	for _, x := range a {
+		for _, y := range d {
+			if x == y {
+				return ErrAllowDenyOverlap
+			}
+		}
+	}
+
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("school_role_permission_school_id = ? AND school_role_permission_role = ?", schoolID, role).
+			Delete(&model.SchoolRolePermissionOverrideModel{}).Error; err != nil {
+			return err
+		}
+		rows := make([]model.SchoolRolePermissionOverrideModel, 0, len(a)+len(d))
+		add := func(ps []string, eff model.PermissionEffect) {
+			for _, p := range ps {
+				rows = append(rows, model.SchoolRolePermissionOverrideModel{
+					SchoolRolePermissionSchoolID:   schoolID,
+					SchoolRolePermissionRole:       role,
+					SchoolRolePermissionPermission: p,
+					SchoolRolePermissionEffect:     eff,
+					SchoolRolePermissionUpdatedBy:  by,
+				})
+			}
+		}
+		add(a, model.EffectAllow)
+		add(d, model.EffectDeny)
+		if len(rows) == 0 {
+			return nil
+		}
+		return tx.Create(&rows).Error
+	})
+	Invalidate(schoolID)
+	return err
+}
+
+/* =========================================================
+   Custom role
+========================================================= */
+
+type CustomRoleInput struct {
+	Key         *string
+	Name        *string
+	Description *string
+	Permissions []string
+	SetPerms    bool
+}
+
+type CustomRoleWithCount struct {
+	model.SchoolCustomRoleModel
+	MemberCount int64 `json:"member_count" gorm:"column:member_count"`
+}
+
+func ListCustomRoles(ctx context.Context, db *gorm.DB, schoolID uuid.UUID) ([]CustomRoleWithCount, error) {
+	var rows []CustomRoleWithCount
+	err := db.WithContext(ctx).
+		Model(&model.SchoolCustomRoleModel{}).
+		Select(`school_custom_roles.*,
+		        (SELECT COUNT(*) FROM school_custom_role_members m
+		          WHERE m.school_custom_role_member_role_id = school_custom_roles.school_custom_role_id) AS member_count`).
+		Where("school_custom_role_school_id = ?", schoolID).
+		Order("school_custom_role_name ASC").
+		Scan(&rows).Error
+	return rows, err
+}
+
+func getCustomRole(ctx context.Context, db *gorm.DB, schoolID, id uuid.UUID) (*model.SchoolCustomRoleModel, error) {
+	var m model.SchoolCustomRoleModel
+	err := db.WithContext(ctx).
+		Where("school_custom_role_id = ? AND school_custom_role_school_id = ?", id, schoolID).
+		Take(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrCustomRoleNotFound
+	}
+	return &m, err
+}
+
+func keyTaken(ctx context.Context, db *gorm.DB, schoolID uuid.UUID, key string, except *uuid.UUID) (bool, error) {
+	q := db.WithContext(ctx).Model(&model.SchoolCustomRoleModel{}).
+		Where("school_custom_role_school_id = ? AND school_custom_role_key = ?", schoolID, key)
+	if except != nil {
+		q = q.Where("school_custom_role_id <> ?", *except)
+	}
+	var n int64
+	err := q.Count(&n).Error
+	return n > 0, err
+}
+
+func CreateCustomRole(ctx context.Context, db *gorm.DB, schoolID uuid.UUID, in CustomRoleInput) (*model.SchoolCustomRoleModel, error) {
+	if in.Key == nil || !reCustomKey.MatchString(strings.TrimSpace(*in.Key)) {
+		return nil, ErrInvalidCustomKey
+	}
+	key := strings.TrimSpace(*in.Key)
+	if in.Name == nil || strings.TrimSpace(*in.Name) == "" {
+		return nil, ErrCustomRoleNameEmpty
+	}
+	perms, err := normalizePatterns(in.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	if taken, err := keyTaken(ctx, db, schoolID, key, nil); err != nil {
+		return nil, err
+	} else if taken {
+		return nil, ErrCustomRoleKeyTaken
+	}
+
+	m := model.SchoolCustomRoleModel{
+		SchoolCustomRoleSchoolID:    schoolID,
+		SchoolCustomRoleKey:         key,
+		SchoolCustomRoleName:        strings.TrimSpace(*in.Name),
+		SchoolCustomRoleDescription: in.Description,
+		SchoolCustomRolePermissions: pq.StringArray(perms),
+	}
+	if err := db.WithContext(ctx).Create(&m).Error; err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func UpdateCustomRole(ctx context.Context, db *gorm.DB, schoolID, id uuid.UUID, in CustomRoleInput) (*model.SchoolCustomRoleModel, error) {
+	m, err := getCustomRole(ctx, db, schoolID, id)
+	if err != nil {
+		return nil, err
+	}
+	upd := map[string]any{"school_custom_role_updated_at": time.Now()}
+	if in.Key != nil {
+		key := strings.TrimSpace(*in.Key)
+		if !reCustomKey.MatchString(key) {
+			return nil, ErrInvalidCustomKey
+		}
+		if taken, err := keyTaken(ctx, db, schoolID, key, &id); err != nil {
+			return nil, err
+		} else if taken {
+			return nil, ErrCustomRoleKeyTaken
+		}
+		upd["school_custom_role_key"] = key
+	}
+	if in.Name != nil {
+		if strings.TrimSpace(*in.Name) == "" {
+			return nil, ErrCustomRoleNameEmpty
+		}
+		upd["school_custom_role_name"] = strings.TrimSpace(*in.Name)
+	}
+	if in.Description != nil {
+		upd["school_custom_role_description"] = in.Description
+	}
+	if in.SetPerms {
+		perms, err := normalizePatterns(in.Permissions)
+		if err != nil {
+			return nil, err
+		}
+		upd["school_custom_role_permissions"] = pq.StringArray(perms)
+	}
+	if err := db.WithContext(ctx).Model(m).Updates(upd).Error; err != nil {
+		return nil, err
+	}
+	return getCustomRole(ctx, db, schoolID, id)
+}
+
+// DeleteCustomRole: soft delete + cabut semua penugasan (akses langsung hilang).
+func DeleteCustomRole(ctx context.Context, db *gorm.DB, schoolID, id uuid.UUID) error {
+	m, err := getCustomRole(ctx, db, schoolID, id)
+	if err != nil {
+		return err
+	}
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("school_custom_role_member_role_id = ?", m.SchoolCustomRoleID).
+			Delete(&model.SchoolCustomRoleMemberModel{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(m).Error
+	})
+}
+
+/* =========================================================
+   Penugasan custom role
+========================================================= */
+
+type CustomRoleMember struct {
+	UserID     uuid.UUID  `json:"user_id"`
+	UserName   string     `json:"user_name"`
+	FullName   *string    `json:"full_name,omitempty"`
+	Email      string     `json:"email"`
+	AssignedBy *uuid.UUID `json:"assigned_by,omitempty"`
+	AssignedAt time.Time  `json:"assigned_at"`
+}
+
+func ListMembers(ctx context.Context, db *gorm.DB, schoolID, roleID uuid.UUID) ([]CustomRoleMember, error) {
+	if _, err := getCustomRole(ctx, db, schoolID, roleID); err != nil {
+		return nil, err
+	}
+	var rows []CustomRoleMember
+	err := db.WithContext(ctx).Raw(`
+		SELECT u.id AS user_id, u.user_name, u.full_name, u.email,
+		       m.school_custom_role_member_assigned_by AS assigned_by,
+		       m.school_custom_role_member_created_at  AS assigned_at
+		FROM school_custom_role_members m
+		JOIN users u ON u.id = m.school_custom_role_member_user_id
+		WHERE m.school_custom_role_member_role_id = ?
+		ORDER BY u.user_name ASC`, roleID).Scan(&rows).Error
+	return rows, err
+}
+
+func AddMember(ctx context.Context, db *gorm.DB, schoolID, roleID, userID uuid.UUID, by *uuid.UUID) error {
+	if _, err := getCustomRole(ctx, db, schoolID, roleID); err != nil {
+		return err
+	}
+	var inSchool bool
+	if err := db.WithContext(ctx).Raw(`
+		SELECT EXISTS (SELECT 1 FROM user_roles
+		               WHERE user_id = ? AND school_id = ? AND deleted_at IS NULL)`,
+		userID, schoolID).Scan(&inSchool).Error; err != nil {
+		return err
+	}
+	if !inSchool {
+		return ErrUserNotInSchool
+	}
+	return db.WithContext(ctx).Exec(`
+		INSERT INTO school_custom_role_members
+		  (school_custom_role_member_role_id, school_custom_role_member_user_id,
+		   school_custom_role_member_school_id, school_custom_role_member_assigned_by)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT DO NOTHING`, roleID, userID, schoolID, by).Error
+}
+
+func RemoveMember(ctx context.Context, db *gorm.DB, schoolID, roleID, userID uuid.UUID) error {
+	res := db.WithContext(ctx).
+		Where("school_custom_role_member_role_id = ? AND school_custom_role_member_user_id = ? AND school_custom_role_member_school_id = ?",
+			roleID, userID, schoolID).
+		Delete(&model.SchoolCustomRoleMemberModel{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrCustomRoleNotFound
+	}
+	return nil
+}
+
+func nonNil(xs []string) []string {
+	if xs == nil {
+		return []string{}
+	}
+	return xs
+}
@@ -0,0 +1,233 @@
+// file: internals/features/lembaga/permissions/service/registry.go
+package service
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"madinahsalam_backend/internals/constants"
+)
+
+/* =========================================================
+   Registry: resource.action
+   - read   → GET/HEAD
+   - write  → POST/PUT/PATCH
+   - delete → DELETE
+   Grantable=false → hanya admin/dkm/owner (tidak bisa lewat override/custom role)
+========================================================= */
+
+const (
+	ActionRead   = "read"
+	ActionWrite  = "write"
+	ActionDelete = "delete"
+
+	Wildcard = "*"
+)
+
+type Resource struct {
+	Key       string   `json:"key"`
+	Label     string   `json:"label"`
+	Actions   []string `json:"actions"`
+	Grantable bool     `json:"grantable"`
+
+	// prefix path di /api/a yang dijaga resource ini
+	AdminPaths []string `json:"-"`
+}
+
+var crud = []string{ActionRead, ActionWrite, ActionDelete}
+
+var Registry = []Resource{
+	{Key: "school_profile", Label: "Profil sekolah", Actions: []string{ActionRead, ActionWrite, ActionDelete}, Grantable: true,
+		AdminPaths: []string{"school-profiles"}},
+	{Key: "teachers", Label: "Data guru", Actions: crud, Grantable: true,
+		AdminPaths: []string{"school-teachers"}},
+	{Key: "students", Label: "Data siswa, mutasi & import", Actions: crud, Grantable: true,
+		AdminPaths: []string{"school-students", "student-transfers", "imports", "class-parents"}},
+	{Key: "classes", Label: "Kelas, rombel, mapel & buku", Actions: crud, Grantable: true,
+		AdminPaths: []string{
+			"classes", "class-sections", "class-rooms", "class-subjects", "subjects", "academic-terms",
+			"class-enrollments", "student-class-sections", "class-section-subject-teachers",
+			"books", "class-subject-books", "book-urls",
+		}},
+	{Key: "schedules", Label: "Jadwal pelajaran", Actions: crud, Grantable: true,
+		AdminPaths: []string{"class-schedules"}},
+	{Key: "attendance", Label: "Pengaturan presensi", Actions: crud, Grantable: true,
+		AdminPaths: []string{"attendance-session-types", "attendance-participant-types", "class_attendance_settings"}},
+	{Key: "attendance_devices", Label: "Mesin absen (fingerprint/RFID)", Actions: crud, Grantable: true,
+		AdminPaths: []string{"attendance-devices"}},
+	{Key: "tahfidz", Label: "Tahfidz (target & rapor hafalan)", Actions: crud, Grantable: true,
+		AdminPaths: []string{"tahfidz"}},
+	{Key: "grades", Label: "Penilaian, tugas & kuis", Actions: crud, Grantable: true,
+		AdminPaths: []string{"assessments", "assessment-types", "submissions", "quizzes", "rubrics", "question-bank"}},
+	{Key: "payments", Label: "Tagihan & pembayaran", Actions: crud, Grantable: true,
+		AdminPaths: []string{"payments", "fee-rules", "bill-batches", "general-billings", "user-general-billings"}},
+	{Key: "events", Label: "Agenda & tema acara", Actions: crud, Grantable: true,
+		AdminPaths: []string{"events", "event-themes"}},
+	{Key: "surveys", Label: "Survei", Actions: crud, Grantable: true,
+		AdminPaths: []string{"survey-questions"}},
+	{Key: "stats", Label: "Statistik lembaga", Actions: []string{ActionRead}, Grantable: true,
+		AdminPaths: []string{"lembaga-stats", "semester-stats"}},
+	{Key: "audit_logs", Label: "Audit trail", Actions: []string{ActionRead, ActionWrite}, Grantable: true,
+		AdminPaths: []string{"audit-logs"}},
+	{Key: "subscription", Label: "Langganan sekolah", Actions: []string{ActionRead, ActionWrite}, Grantable: false,
+		AdminPaths: []string{"subscription"}},
+	{Key: "security", Label: "Sesi user & kebijakan 2FA", Actions: []string{ActionRead, ActionWrite}, Grantable: false,
+		AdminPaths: []string{"user-sessions", "mfa-policy"}},
+	{Key: "permissions", Label: "Hak akses & custom role", Actions: []string{ActionRead, ActionWrite}, Grantable: false,
+		AdminPaths: []string{"permissions"}},
+	{Key: "webhooks", Label: "Webhook integrasi", Actions: crud, Grantable: false,
+		AdminPaths: []string{"webhooks"}},
+	{Key: "api_keys", Label: "API key integrasi", Actions: []string{ActionRead, ActionWrite}, Grantable: false,
+		AdminPaths: []string{"api-keys"}},
+}
+
+/*
+Default role → permission (sama dengan perilaku sebelum matrix).
+owner/admin/dkm penuh (dulu IsSchoolAdmin). Role lain (teacher, treasurer, ...)
+tidak punya akses /api/a secara default: console admin dulu khusus IsSchoolAdmin.
+Akses tambahan diberikan sekolah lewat override role atau custom role.
+*/
+var DefaultRolePermissions = map[string][]string{
+	constants.RoleOwner: {Wildcard},
+	constants.RoleAdmin: {Wildcard},
+	constants.RoleDKM:   {Wildcard},
+}
+
+// Role bawaan yang boleh di-override per sekolah (admin/dkm/owner selalu penuh).
+var OverridableRoles = []string{
+	constants.RoleTeacher, constants.RoleTreasurer, constants.RoleAuthor, constants.RoleStudent, constants.RoleUser,
+}
+
+func IsFullAccessRole(role string) bool {
+	for _, p := range DefaultRolePermissions[constants.NormalizeRole(role)] {
+		if p == Wildcard {
+			return true
+		}
+	}
+	return false
+}
+
+/* =========================================================
+   Lookup & wildcard
+========================================================= */
+
+var (
+	allKeys      []string
+	keySet       = map[string]*Resource{}
+	pathResource = map[string]string{}
+)
+
+func init() {
+	for i := range Registry {
+		r := &Registry[i]
+		for _, a := range r.Actions {
+			k := r.Key + "." + a
+			allKeys = append(allKeys, k)
+			keySet[k] = r
+		}
+		for _, p := range r.AdminPaths {
+			pathResource[p] = r.Key
+		}
+	}
+	sort.Strings(allKeys)
+}
+
+func AllPermissions() []string { return append([]string(nil), allKeys...) }
+
+// ValidPattern: "payments.read", "payments.*" atau "*".
+func ValidPattern(p string) bool {
+	p = strings.TrimSpace(p)
+	if p == Wildcard {
+		return true
+	}
+	if res, ok := strings.CutSuffix(p, ".*"); ok {
+		for _, r := range Registry {
+			if r.Key == res {
+				return true
+			}
+		}
+		return false
+	}
+	_, ok := keySet[p]
+	return ok
+}
+
+// Grantable: pola tidak menyentuh resource non-grantable ("*" pun ditolak).
+func Grantable(p string) bool {
+	p = strings.TrimSpace(p)
+	if p == Wildcard || !ValidPattern(p) {
+		return false
+	}
+	for _, k := range Expand([]string{p}) {
+		if !keySet[k].Grantable {
+			return false
+		}
+	}
+	return true
+}
+
+// Expand pola → daftar permission konkret (urut, unik).
+func Expand(patterns []string) []string {
+	set := map[string]bool{}
+	for _, p := range patterns {
+		p = strings.TrimSpace(p)
+		switch {
+		case p == Wildcard:
+			for _, k := range allKeys {
+				set[k] = true
+			}
+		case strings.HasSuffix(p, ".*"):
+			prefix := strings.TrimSuffix(p, "*")
+			for _, k := range allKeys {
+				if strings.HasPrefix(k, prefix) {
+					set[k] = true
+				}
+			}
+		default:
+			if _, ok := keySet[p]; ok {
+				set[p] = true
+			}
+		}
+	}
+	out := make([]string, 0, len(set))
+	for k := range set {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// PermissionForAdminPath: "/api/a/<uuid?>/payments/..." + method → "payments.read".
+// Resource = segmen non-UUID pertama; sub-path ikut resource induknya
+// (mis. /question-bank/quizzes/:id/draw-rules → grades.*). "" bila path tidak terdaftar (tetap khusus admin).
+func PermissionForAdminPath(method, path string) string {
+	segs := strings.Split(strings.Trim(strings.ToLower(path), "/"), "/")
+	for _, s := range segs {
+		switch s {
+		case "", "api", "a":
+			continue
+		}
+		if _, err := uuid.Parse(s); err == nil {
+			continue
+		}
+		res, ok := pathResource[s]
+		if !ok {
+			return ""
+		}
+		return res + "." + actionForMethod(method)
+	}
+	return ""
+}
+
+func actionForMethod(method string) string {
+	switch strings.ToUpper(method) {
+	case "GET", "HEAD", "OPTIONS":
+		return ActionRead
+	case "DELETE":
+		return ActionDelete
+	default:
+		return ActionWrite
+	}
+}
@@ -0,0 +1,70 @@
+// file: internals/features/lembaga/permissions/service/registry_test.go
+package service
+
+import (
+	"testing"
+
+	"madinahsalam_backend/internals/constants"
+)
+
+// Sub-path ikut resource segmen pertama; path belum terdaftar tetap khusus admin ("").
+func TestPermissionForAdminPath(t *testing.T) {
+	const sid = "3f2504e0-4f89-11d3-9a0c-0305e82c3301"
+	cases := []struct {
+		method, path, want string
+	}{
+		{"GET", "/api/a/payments/list", "payments.read"},
+		{"POST", "/api/a/" + sid + "/payments", "payments.write"},
+		{"GET", "/api/a/question-bank/quizzes/" + sid + "/draw-rules", "grades.read"},
+		{"DELETE", "/api/a/quizzes/questions/" + sid, "grades.delete"},
+		{"POST", "/api/a/class-sections/" + sid + "/join-code/teacher/rotate", "classes.write"},
+		{"GET", "/api/a/student-transfers/" + sid + "/transcript", "students.read"},
+		{"POST", "/api/a/student-transfers/" + sid + "/approve", "students.write"},
+		{"PATCH", "/api/a/tahfidz/targets/" + sid, "tahfidz.write"},
+		{"POST", "/api/a/subscription/invoices/" + sid + "/pay", "subscription.write"},
+		{"DELETE", "/api/a/" + sid + "/event-themes/" + sid, "events.delete"},
+		{"GET", "/api/a/schools/list", ""},
+		{"GET", "/api/a/yayasans", ""},
+	}
+	for _, tc := range cases {
+		if got := PermissionForAdminPath(tc.method, tc.path); got != tc.want {
+			t.Errorf("%s %s = %q, want %q", tc.method, tc.path, got, tc.want)
+		}
+	}
+}
+
+func TestDefaultRolePermissions(t *testing.T) {
+	for role, patterns := range DefaultRolePermissions {
+		for _, p := range patterns {
+			if !ValidPattern(p) {
+				t.Errorf("%s: pola tidak terdaftar %q", role, p)
+			}
+		}
+	}
+
+	has := func(role, perm string) bool {
+		for _, k := range Expand(DefaultRolePermissions[role]) {
+			if k == perm {
+				return true
+			}
+		}
+		return false
+	}
+	// selain owner/admin/dkm tanpa akses /api/a bawaan (harus lewat override / custom role)
+	for _, role := range OverridableRoles {
+		if n := len(Expand(DefaultRolePermissions[role])); n != 0 {
+			t.Errorf("%s punya %d permission bawaan, harus 0", role, n)
+		}
+	}
+	// non-grantable tetap khusus admin/dkm/owner
+	for _, role := range []string{constants.RoleTeacher, constants.RoleTreasurer} {
+		for _, perm := range []string{"subscription.write", "permissions.write", "security.write", "api_keys.write"} {
+			if has(role, perm) {
+				t.Errorf("%s tidak boleh punya %s", role, perm)
+			}
+		}
+		if IsFullAccessRole(role) {
+			t.Errorf("%s bukan full access", role)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+// file: internals/features/lembaga/permissions/service/request.go
+package service
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	helperAuth "madinahsalam_backend/internals/helpers/auth"
+)
+
+const localsEffective = "permissions_effective"
+
+// SchoolFromRequest: active_school_id (UseSchoolScope) → fallback resolver token/path.
+func SchoolFromRequest(c *fiber.Ctx) (uuid.UUID, error) {
+	if s, _ := c.Locals(helperAuth.LocActiveSchoolID).(string); strings.TrimSpace(s) != "" {
+		if id, err := uuid.Parse(strings.TrimSpace(s)); err == nil {
+			return id, nil
+		}
+	}
+	return helperAuth.ResolveSchoolIDFromContext(c)
+}
+
+// ForRequest: permission efektif user untuk school ini (di-memo per request).
+func ForRequest(c *fiber.Ctx, schoolID uuid.UUID) (*Effective, error) {
+	if e, ok := c.Locals(localsEffective).(*Effective); ok && e.SchoolID == schoolID {
+		return e, nil
+	}
+	// API key: hanya scope milik key (tanpa role/override/custom role)
+	if scopes, ok := helperAuth.GetAPIKeyScopes(c); ok {
+		e := ForScopes(schoolID, scopes)
+		c.Locals(localsEffective, e)
+		return e, nil
+	}
+	db, err := DB()
+	if err != nil {
+		return nil, err
+	}
+	userID, _ := helperAuth.GetUserIDFromToken(c)
+	roles := helperAuth.GetRolesInSchool(c, schoolID)
+
+	e, err := Resolve(c.Context(), db, schoolID, userID, roles, helperAuth.IsOwner(c))
+	if err != nil {
+		return nil, err
+	}
+	c.Locals(localsEffective, e)
+	return e, nil
+}
+
+// Has: cek permission di controller (mis. tampilkan kolom nominal hanya bila payments.read).
+func Has(c *fiber.Ctx, perm string) bool {
+	schoolID, err := SchoolFromRequest(c)
+	if err != nil {
+		return false
+	}
+	e, err := ForRequest(c, schoolID)
+	if err != nil {
+		return false
+	}
+	return e.Has(perm)
+}
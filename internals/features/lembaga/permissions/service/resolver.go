@@ -0,0 +1,220 @@
+// file: internals/features/lembaga/permissions/service/resolver.go
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"madinahsalam_backend/internals/constants"
+	model "madinahsalam_backend/internals/features/lembaga/permissions/model"
+)
+
+/* =========================================================
+   Effective permission = (default role bawaan ± override sekolah)
+                          ∪ permission custom role milik user
+   Override per sekolah di-cache singkat; custom role dibaca per request
+   (1 query, hanya untuk non-admin).
+========================================================= */
+
+const overrideCacheTTL = 60 * time.Second
+
+var (
+	ErrNotInitialized = errors.New("permission service belum di-init")
+
+	defaultDB *gorm.DB
+
+	cacheMu sync.RWMutex
+	cache   = map[uuid.UUID]overrideEntry{}
+)
+
+type overrideEntry struct {
+	byRole map[string]roleOverride
+	at     time.Time
+}
+
+type roleOverride struct {
+	Allow []string
+	Deny  []string
+}
+
+// Init: dipanggil sekali saat setup route (middleware RequirePermission butuh DB).
+func Init(db *gorm.DB) { defaultDB = db }
+
+func DB() (*gorm.DB, error) {
+	if defaultDB == nil {
+		return nil, ErrNotInitialized
+	}
+	return defaultDB, nil
+}
+
+// Invalidate cache override sekolah (setelah admin mengubah matrix).
+func Invalidate(schoolID uuid.UUID) {
+	cacheMu.Lock()
+	delete(cache, schoolID)
+	cacheMu.Unlock()
+}
+
+func loadOverrides(ctx context.Context, db *gorm.DB, schoolID uuid.UUID) (map[string]roleOverride, error) {
+	cacheMu.RLock()
+	e, ok := cache[schoolID]
+	cacheMu.RUnlock()
+	if ok && time.Since(e.at) < overrideCacheTTL {
+		return e.byRole, nil
+	}
+
+	var rows []model.SchoolRolePermissionOverrideModel
+	if err := db.WithContext(ctx).
+		Where("school_role_permission_school_id = ?", schoolID).
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	byRole := map[string]roleOverride{}
+	for _, r := range rows {
+		o := byRole[r.SchoolRolePermissionRole]
+		if r.SchoolRolePermissionEffect == model.EffectDeny {
+			o.Deny = append(o.Deny, r.SchoolRolePermissionPermission)
+		} else {
+			o.Allow = append(o.Allow, r.SchoolRolePermissionPermission)
+		}
+		byRole[r.SchoolRolePermissionRole] = o
+	}
+
+	cacheMu.Lock()
+	cache[schoolID] = overrideEntry{byRole: byRole, at: time.Now()}
+	cacheMu.Unlock()
+	return byRole, nil
+}
+
+// rolePermissions: permission konkret untuk 1 role bawaan di sekolah (default ± override).
+func rolePermissions(role string, ov map[string]roleOverride) []string {
+	role = constants.NormalizeRole(role)
+	base := Expand(DefaultRolePermissions[role])
+	if IsFullAccessRole(role) {
+		return base
+	}
+	o := ov[role]
+	set := map[string]bool{}
+	for _, k := range base {
+		set[k] = true
+	}
+	for _, k := range Expand(o.Allow) {
+		set[k] = true
+	}
+	for _, k := range Expand(o.Deny) {
+		delete(set, k)
+	}
+	out := make([]string, 0, len(set))
+	for k := range set {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+type CustomRoleRef struct {
+	ID   uuid.UUID `json:"id"`
+	Key  string    `json:"key"`
+	Name string    `json:"name"`
+}
+
+type Effective struct {
+	SchoolID    uuid.UUID       `json:"school_id"`
+	Roles       []string        `json:"roles"`
+	CustomRoles []CustomRoleRef `json:"custom_roles"`
+	FullAccess  bool            `json:"full_access"`
+	Permissions []string        `json:"permissions"`
+
+	set map[string]bool
+}
+
+func (e *Effective) Has(perm string) bool {
+	if e == nil {
+		return false
+	}
+	return e.FullAccess || e.set[perm]
+}
+
+// Resolve menghitung permission efektif user di sekolah.
+// roles = role bawaan user di sekolah tsb (dari token); isOwner = owner global.
+func Resolve(ctx context.Context, db *gorm.DB, schoolID, userID uuid.UUID, roles []string, isOwner bool) (*Effective, error) {
+	eff := &Effective{SchoolID: schoolID, Roles: roles, CustomRoles: []CustomRoleRef{}, set: map[string]bool{}}
+	if eff.Roles == nil {
+		eff.Roles = []string{}
+	}
+
+	if isOwner {
+		eff.FullAccess = true
+	}
+	for _, r := range roles {
+		if IsFullAccessRole(r) {
+			eff.FullAccess = true
+		}
+	}
+	if eff.FullAccess {
+		eff.Permissions = AllPermissions()
+		return eff, nil
+	}
+
+	ov, err := loadOverrides(ctx, db, schoolID)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range roles {
+		for _, k := range rolePermissions(r, ov) {
+			eff.set[k] = true
+		}
+	}
+
+	if userID != uuid.Nil {
+		var rows []model.SchoolCustomRoleModel
+		if err := db.WithContext(ctx).
+			Joins(`JOIN school_custom_role_members m
+			         ON m.school_custom_role_member_role_id = school_custom_roles.school_custom_role_id`).
+			Where("m.school_custom_role_member_user_id = ? AND school_custom_roles.school_custom_role_school_id = ?", userID, schoolID).
+			Find(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, r := range rows {
+			eff.CustomRoles = append(eff.CustomRoles, CustomRoleRef{
+				ID: r.SchoolCustomRoleID, Key: r.SchoolCustomRoleKey, Name: r.SchoolCustomRoleName,
+			})
+			for _, k := range Expand(r.SchoolCustomRolePermissions) {
+				// jaga-jaga data lama: resource non-grantable tidak pernah lewat custom role
+				if keySet[k].Grantable {
+					eff.set[k] = true
+				}
+			}
+		}
+	}
+
+	eff.Permissions = make([]string, 0, len(eff.set))
+	for k := range eff.set {
+		eff.Permissions = append(eff.Permissions, k)
+	}
+	sort.Strings(eff.Permissions)
+	return eff, nil
+}
+
+// ForScopes: permission efektif principal API key (scope key, resource grantable saja).
+func ForScopes(schoolID uuid.UUID, scopes []string) *Effective {
+	eff := &Effective{
+		SchoolID:    schoolID,
+		Roles:       []string{constants.RoleAPIKey},
+		CustomRoles: []CustomRoleRef{},
+		Permissions: []string{},
+		set:         map[string]bool{},
+	}
+	for _, k := range Expand(scopes) {
+		if keySet[k].Grantable {
+			eff.set[k] = true
+			eff.Permissions = append(eff.Permissions, k)
+		}
+	}
+	return eff
+}
@@ -0,0 +1,222 @@
+// file: internals/features/lembaga/school_yayasans/imports/controller/import_jobs_controller.go
+package controller
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	dto "madinahsalam_backend/internals/features/lembaga/school_yayasans/imports/dto"
+	model "madinahsalam_backend/internals/features/lembaga/school_yayasans/imports/model"
+	svc "madinahsalam_backend/internals/features/lembaga/school_yayasans/imports/service"
+	helper "madinahsalam_backend/internals/helpers"
+	helperAuth "madinahsalam_backend/internals/helpers/auth"
+)
+
+/*
+Bulk import siswa/guru dari XLSX/CSV (termasuk ekspor Dapodik)
+
+POST /api/a/imports              multipart: file, kind=students|teachers, dry_run=true|false (default true),
+                                            default_class_section_id (opsional)
+GET  /api/a/imports?kind=&status=
+GET  /api/a/imports/:id          progres + error per baris
+POST /api/a/imports/:id/commit   hasil dry-run OK → proses sungguhan
+POST /api/a/imports/:id/cancel
+*/
+
+// Batas ukuran upload (bytes)
+const maxImportFileSize = 10 << 20
+
+type ImportJobController struct {
+	DB *gorm.DB
+}
+
+func NewImportJobController(db *gorm.DB) *ImportJobController {
+	return &ImportJobController{DB: db}
+}
+
+func resolveAdminSchool(c *fiber.Ctx) (uuid.UUID, error) {
+	schoolID, err := helperAuth.ResolveSchoolIDFromContext(c)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if err := helperAuth.EnsureDKMSchool(c, schoolID); err != nil {
+		return uuid.Nil, err
+	}
+	return schoolID, nil
+}
+
+func parseID(c *fiber.Ctx) (uuid.UUID, error) {
+	id, err := uuid.Parse(strings.TrimSpace(c.Params("id")))
+	if err != nil {
+		return uuid.Nil, fiber.NewError(fiber.StatusBadRequest, "id tidak valid")
+	}
+	return id, nil
+}
+
+func writeErr(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, svc.ErrJobNotFound):
+		return helper.JsonError(c, fiber.StatusNotFound, err.Error())
+	case errors.Is(err, svc.ErrJobNotValidated), errors.Is(err, svc.ErrJobNotCancelable):
+		return helper.JsonError(c, fiber.StatusConflict, err.Error())
+	case errors.Is(err, svc.ErrInvalidKind), errors.Is(err, svc.ErrSectionNotFound),
+		errors.Is(err, svc.ErrUnsupportedFormat), errors.Is(err, svc.ErrHeaderNotFound),
+		errors.Is(err, svc.ErrEmptyFile):
+		return helper.JsonError(c, fiber.StatusBadRequest, err.Error())
+	case errors.Is(err, svc.ErrTooManyRows):
+		return helper.JsonError(c, fiber.StatusRequestEntityTooLarge,
+			err.Error()+" ("+strconv.Itoa(svc.MaxRows())+")")
+	}
+	var fe *fiber.Error
+	if errors.As(err, &fe) {
+		return helper.JsonError(c, fe.Code, fe.Message)
+	}
+	return helper.JsonError(c, fiber.StatusInternalServerError, err.Error())
+}
+
+// POST /api/a/imports
+func (h *ImportJobController) Create(c *fiber.Ctx) error {
+	schoolID, err := resolveAdminSchool(c)
+	if err != nil {
+		return err
+	}
+
+	fh, err := c.FormFile("file")
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "file wajib diunggah")
+	}
+	if fh.Size > maxImportFileSize {
+		return helper.JsonError(c, fiber.StatusRequestEntityTooLarge, "ukuran file maksimal 10MB")
+	}
+	f, err := fh.Open()
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "file tidak bisa dibaca")
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, maxImportFileSize+1))
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "file tidak bisa dibaca")
+	}
+
+	kind := model.ImportJobKind(strings.ToLower(strings.TrimSpace(c.FormValue("kind", "students"))))
+
+	dryRun := true
+	if v := strings.TrimSpace(c.FormValue("dry_run")); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return helper.JsonError(c, fiber.StatusBadRequest, "dry_run harus true|false")
+		}
+		dryRun = b
+	}
+
+	var opts svc.JobOptions
+	if v := strings.TrimSpace(c.FormValue("default_class_section_id")); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return helper.JsonError(c, fiber.StatusBadRequest, "default_class_section_id tidak valid")
+		}
+		opts.DefaultClassSectionID = &id
+	}
+
+	var createdBy *uuid.UUID
+	if uid, err := helperAuth.GetUserIDFromToken(c); err == nil && uid != uuid.Nil {
+		createdBy = &uid
+	}
+
+	m, err := svc.CreateJob(c.Context(), h.DB, schoolID, kind, fh.Filename, data, dryRun, opts, createdBy)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"message": "Import dijadwalkan",
+		"data":    dto.FromModel(m, false),
+	})
+}
+
+// GET /api/a/imports
+func (h *ImportJobController) List(c *fiber.Ctx) error {
+	schoolID, err := resolveAdminSchool(c)
+	if err != nil {
+		return err
+	}
+	p := helper.ResolvePaging(c, 20, 100)
+
+	q := h.DB.WithContext(c.Context()).Model(&model.ImportJobModel{}).
+		Where("import_job_school_id = ?", schoolID)
+	if k := strings.ToLower(strings.TrimSpace(c.Query("kind"))); k != "" {
+		q = q.Where("import_job_kind = ?", k)
+	}
+	if s := strings.ToLower(strings.TrimSpace(c.Query("status"))); s != "" {
+		q = q.Where("import_job_status = ?", s)
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return helper.JsonError(c, fiber.StatusInternalServerError, "Gagal menghitung data")
+	}
+	var rows []model.ImportJobModel
+	if err := q.Omit("import_job_rows", "import_job_errors").
+		Order("import_job_created_at DESC").
+		Limit(p.Limit).Offset(p.Offset).
+		Find(&rows).Error; err != nil {
+		return helper.JsonError(c, fiber.StatusInternalServerError, "Gagal mengambil data")
+	}
+	return helper.JsonList(c, "OK", dto.FromModels(rows), helper.BuildPaginationFromPage(total, p.Page, p.PerPage))
+}
+
+// GET /api/a/imports/:id
+func (h *ImportJobController) Detail(c *fiber.Ctx) error {
+	schoolID, err := resolveAdminSchool(c)
+	if err != nil {
+		return err
+	}
+	id, err := parseID(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	m, err := svc.GetJob(c.Context(), h.DB, schoolID, id)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonOK(c, "OK", dto.FromModel(m, true))
+}
+
+// POST /api/a/imports/:id/commit
+func (h *ImportJobController) Commit(c *fiber.Ctx) error {
+	schoolID, err := resolveAdminSchool(c)
+	if err != nil {
+		return err
+	}
+	id, err := parseID(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	m, err := svc.Commit(c.Context(), h.DB, schoolID, id)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonUpdated(c, "Import dijalankan", dto.FromModel(m, false))
+}
+
+// POST /api/a/imports/:id/cancel
+func (h *ImportJobController) Cancel(c *fiber.Ctx) error {
+	schoolID, err := resolveAdminSchool(c)
+	if err != nil {
+		return err
+	}
+	id, err := parseID(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	m, err := svc.Cancel(c.Context(), h.DB, schoolID, id)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonUpdated(c, "Import dibatalkan", dto.FromModel(m, false))
+}
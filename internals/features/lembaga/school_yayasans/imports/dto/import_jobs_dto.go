@@ -0,0 +1,83 @@
+// file: internals/features/lembaga/school_yayasans/imports/dto/import_jobs_dto.go
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	model "madinahsalam_backend/internals/features/lembaga/school_yayasans/imports/model"
+	svc "madinahsalam_backend/internals/features/lembaga/school_yayasans/imports/service"
+)
+
+/* =========================================================
+   RESPONSE
+========================================================= */
+
+type ImportJobResponse struct {
+	ImportJobID     uuid.UUID             `json:"import_job_id"`
+	ImportJobKind   model.ImportJobKind   `json:"import_job_kind"`
+	ImportJobStatus model.ImportJobStatus `json:"import_job_status"`
+	ImportJobDryRun bool                  `json:"import_job_dry_run"`
+
+	ImportJobFileName     *string `json:"import_job_file_name,omitempty"`
+	ImportJobSourceFormat string  `json:"import_job_source_format"`
+	ImportJobLayout       string  `json:"import_job_layout"`
+
+	ImportJobOptions svc.JobOptions `json:"import_job_options"`
+
+	ImportJobTotalRows     int     `json:"import_job_total_rows"`
+	ImportJobProcessedRows int     `json:"import_job_processed_rows"`
+	ImportJobProgress      float64 `json:"import_job_progress"` // 0..100
+	ImportJobCreatedCount  int     `json:"import_job_created_count"`
+	ImportJobSkippedCount  int     `json:"import_job_skipped_count"`
+	ImportJobErrorCount    int     `json:"import_job_error_count"`
+
+	ImportJobErrors        []svc.RowError `json:"import_job_errors,omitempty"`
+	ImportJobFailureReason *string        `json:"import_job_failure_reason,omitempty"`
+
+	ImportJobStartedAt  *time.Time `json:"import_job_started_at,omitempty"`
+	ImportJobFinishedAt *time.Time `json:"import_job_finished_at,omitempty"`
+	ImportJobCreatedAt  time.Time  `json:"import_job_created_at"`
+	ImportJobUpdatedAt  time.Time  `json:"import_job_updated_at"`
+}
+
+// FromModel; withErrors=false untuk list (error per baris bisa ribuan).
+func FromModel(m *model.ImportJobModel, withErrors bool) ImportJobResponse {
+	out := ImportJobResponse{
+		ImportJobID:            m.ImportJobID,
+		ImportJobKind:          m.ImportJobKind,
+		ImportJobStatus:        m.ImportJobStatus,
+		ImportJobDryRun:        m.ImportJobDryRun,
+		ImportJobFileName:      m.ImportJobFileName,
+		ImportJobSourceFormat:  m.ImportJobSourceFormat,
+		ImportJobLayout:        m.ImportJobLayout,
+		ImportJobTotalRows:     m.ImportJobTotalRows,
+		ImportJobProcessedRows: m.ImportJobProcessedRows,
+		ImportJobCreatedCount:  m.ImportJobCreatedCount,
+		ImportJobSkippedCount:  m.ImportJobSkippedCount,
+		ImportJobErrorCount:    m.ImportJobErrorCount,
+		ImportJobFailureReason: m.ImportJobFailureReason,
+		ImportJobStartedAt:     m.ImportJobStartedAt,
+		ImportJobFinishedAt:    m.ImportJobFinishedAt,
+		ImportJobCreatedAt:     m.ImportJobCreatedAt,
+		ImportJobUpdatedAt:     m.ImportJobUpdatedAt,
+	}
+	_ = json.Unmarshal(m.ImportJobOptions, &out.ImportJobOptions)
+	if m.ImportJobTotalRows > 0 {
+		out.ImportJobProgress = float64(m.ImportJobProcessedRows) * 100 / float64(m.ImportJobTotalRows)
+	}
+	if withErrors {
+		_ = json.Unmarshal(m.ImportJobErrors, &out.ImportJobErrors)
+	}
+	return out
+}
+
+func FromModels(rows []model.ImportJobModel) []ImportJobResponse {
+	out := make([]ImportJobResponse, 0, len(rows))
+	for i := range rows {
+		out = append(out, FromModel(&rows[i], false))
+	}
+	return out
+}
@@ -0,0 +1,65 @@
+// file: internals/features/lembaga/school_yayasans/imports/model/import_jobs_model.go
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+type ImportJobStatus string
+
+const (
+	ImportJobQueued     ImportJobStatus = "queued"
+	ImportJobValidating ImportJobStatus = "validating"
+	ImportJobValidated  ImportJobStatus = "validated"
+	ImportJobRunning    ImportJobStatus = "running"
+	ImportJobCompleted  ImportJobStatus = "completed"
+	ImportJobFailed     ImportJobStatus = "failed"
+	ImportJobCanceled   ImportJobStatus = "canceled"
+)
+
+type ImportJobKind string
+
+const (
+	ImportKindStudents ImportJobKind = "students"
+	ImportKindTeachers ImportJobKind = "teachers"
+)
+
+type ImportJobModel struct {
+	ImportJobID       uuid.UUID `gorm:"column:import_job_id;type:uuid;default:gen_random_uuid();primaryKey" json:"import_job_id"`
+	ImportJobSchoolID uuid.UUID `gorm:"column:import_job_school_id;type:uuid;not null" json:"import_job_school_id"`
+
+	ImportJobKind   ImportJobKind   `gorm:"column:import_job_kind;type:import_job_kind_enum;not null" json:"import_job_kind"`
+	ImportJobStatus ImportJobStatus `gorm:"column:import_job_status;type:import_job_status_enum;not null;default:'queued'" json:"import_job_status"`
+	ImportJobDryRun bool            `gorm:"column:import_job_dry_run;not null;default:true" json:"import_job_dry_run"`
+
+	ImportJobFileName     *string `gorm:"column:import_job_file_name;type:varchar(255)" json:"import_job_file_name,omitempty"`
+	ImportJobSourceFormat string  `gorm:"column:import_job_source_format;type:varchar(10);not null" json:"import_job_source_format"`
+	ImportJobLayout       string  `gorm:"column:import_job_layout;type:varchar(20);not null;default:'generic'" json:"import_job_layout"`
+
+	ImportJobOptions datatypes.JSON `gorm:"column:import_job_options;type:jsonb;not null;default:'{}'" json:"import_job_options"`
+	ImportJobRows    datatypes.JSON `gorm:"column:import_job_rows;type:jsonb;not null;default:'[]'" json:"-"`
+
+	ImportJobTotalRows     int `gorm:"column:import_job_total_rows;not null;default:0" json:"import_job_total_rows"`
+	ImportJobProcessedRows int `gorm:"column:import_job_processed_rows;not null;default:0" json:"import_job_processed_rows"`
+	ImportJobCreatedCount  int `gorm:"column:import_job_created_count;not null;default:0" json:"import_job_created_count"`
+	ImportJobSkippedCount  int `gorm:"column:import_job_skipped_count;not null;default:0" json:"import_job_skipped_count"`
+	ImportJobErrorCount    int `gorm:"column:import_job_error_count;not null;default:0" json:"import_job_error_count"`
+
+	ImportJobErrors        datatypes.JSON `gorm:"column:import_job_errors;type:jsonb;not null;default:'[]'" json:"import_job_errors"`
+	ImportJobFailureReason *string        `gorm:"column:import_job_failure_reason;type:text" json:"import_job_failure_reason,omitempty"`
+
+	ImportJobCreatedByUserID *uuid.UUID `gorm:"column:import_job_created_by_user_id;type:uuid" json:"import_job_created_by_user_id,omitempty"`
+
+	ImportJobStartedAt  *time.Time `gorm:"column:import_job_started_at" json:"import_job_started_at,omitempty"`
+	ImportJobFinishedAt *time.Time `gorm:"column:import_job_finished_at" json:"import_job_finished_at,omitempty"`
+
+	ImportJobCreatedAt time.Time      `gorm:"column:import_job_created_at;autoCreateTime" json:"import_job_created_at"`
+	ImportJobUpdatedAt time.Time      `gorm:"column:import_job_updated_at;autoUpdateTime" json:"import_job_updated_at"`
+	ImportJobDeletedAt gorm.DeletedAt `gorm:"column:import_job_deleted_at;index" json:"import_job_deleted_at,omitempty"`
+}
+
+func (ImportJobModel) TableName() string { return "import_jobs" }
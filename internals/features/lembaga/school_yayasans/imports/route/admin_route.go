@@ -0,0 +1,30 @@
+package route
+
+import (
+	"madinahsalam_backend/internals/constants"
+	importController "madinahsalam_backend/internals/features/lembaga/school_yayasans/imports/controller"
+	authMiddleware "madinahsalam_backend/internals/middlewares/auth"
+	schoolkuMiddleware "madinahsalam_backend/internals/middlewares/features"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// /api/a/imports → DKM + Admin + Owner (school dari token)
+func ImportJobAdminRoutes(api fiber.Router, db *gorm.DB) {
+	ctl := importController.NewImportJobController(db)
+
+	g := api.Group("/imports",
+		authMiddleware.OnlyRolesSlice(
+			constants.RoleErrorAdmin("mengimpor data siswa/guru"),
+			constants.AdminAndAbove,
+		),
+		schoolkuMiddleware.IsSchoolAdmin(),
+	)
+
+	g.Post("/", ctl.Create)
+	g.Get("/", ctl.List)
+	g.Get("/:id", ctl.Detail)
+	g.Post("/:id/commit", ctl.Commit)
+	g.Post("/:id/cancel", ctl.Cancel)
+}
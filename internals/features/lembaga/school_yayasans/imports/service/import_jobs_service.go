@@ -0,0 +1,891 @@
+// file: internals/features/lembaga/school_yayasans/imports/service/import_jobs_service.go
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/mail"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	model "madinahsalam_backend/internals/features/lembaga/school_yayasans/imports/model"
+	personModel "madinahsalam_backend/internals/features/lembaga/school_yayasans/teachers_students/model"
+	statsSvc "madinahsalam_backend/internals/features/lembaga/stats/lembaga_stats/service"
+	sectionModel "madinahsalam_backend/internals/features/school/classes/class_sections/model"
+	membership "madinahsalam_backend/internals/features/school/classes/classes/service"
+	userTeacherService "madinahsalam_backend/internals/features/users/user_teachers/service"
+	userModel "madinahsalam_backend/internals/features/users/users/model"
+	userProfileService "madinahsalam_backend/internals/features/users/users/service"
+	helper "madinahsalam_backend/internals/helpers"
+)
+
+var (
+	ErrJobNotFound      = errors.New("import job tidak ditemukan")
+	ErrJobNotValidated  = errors.New("import job belum selesai divalidasi (dry-run)")
+	ErrJobNotCancelable = errors.New("import job sudah selesai, tidak bisa dibatalkan")
+	ErrInvalidKind      = errors.New("kind harus students atau teachers")
+	ErrSectionNotFound  = errors.New("default_class_section_id tidak ditemukan di school ini")
+)
+
+/* =========================================================
+   Konfigurasi (ENV)
+========================================================= */
+
+func MaxRows() int   { return envInt("IMPORT_MAX_ROWS", 5000) }
+func batchSize() int { return envInt("IMPORT_BATCH_SIZE", 100) }
+func maxErrors() int { return envInt("IMPORT_MAX_ERRORS", 1000) }
+func placeholderDomain() string {
+	if v := strings.TrimSpace(os.Getenv("IMPORT_PLACEHOLDER_EMAIL_DOMAIN")); v != "" {
+		return strings.TrimPrefix(v, "@")
+	}
+	return "import.madinahsalam.local"
+}
+
+func envInt(key string, def int) int {
+	if v, err := strconv.Atoi(strings.TrimSpace(os.Getenv(key))); err == nil && v > 0 {
+		return v
+	}
+	return def
+}
+
+/* =========================================================
+   Types
+========================================================= */
+
+type JobOptions struct {
+	DefaultClassSectionID *uuid.UUID `json:"default_class_section_id,omitempty"`
+}
+
+type RowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// rowPlan = baris yang sudah lolos validasi + hasil resolve referensi.
+type rowPlan struct {
+	src ImportRow
+
+	email      string
+	nisn       *string
+	gender     *userModel.Gender
+	birthDate  *time.Time
+	waURL      *string
+	section    *sectionModel.ClassSectionModel
+	existingID *uuid.UUID // user sudah ada (email/NISN) → dipakai ulang
+}
+
+/* =========================================================
+   Create / Commit / Cancel
+========================================================= */
+
+func CreateJob(
+	ctx context.Context,
+	db *gorm.DB,
+	schoolID uuid.UUID,
+	kind model.ImportJobKind,
+	fileName string,
+	data []byte,
+	dryRun bool,
+	opts JobOptions,
+	createdBy *uuid.UUID,
+) (*model.ImportJobModel, error) {
+	if kind != model.ImportKindStudents && kind != model.ImportKindTeachers {
+		return nil, ErrInvalidKind
+	}
+	if opts.DefaultClassSectionID != nil {
+		var n int64
+		if err := db.WithContext(ctx).Model(&sectionModel.ClassSectionModel{}).
+			Where("class_section_id = ? AND class_section_school_id = ? AND class_section_deleted_at IS NULL",
+				*opts.DefaultClassSectionID, schoolID).
+			Count(&n).Error; err != nil {
+			return nil, err
+		}
+		if n == 0 {
+			return nil, ErrSectionNotFound
+		}
+	}
+
+	parsed, err := ParseImportFile(fileName, data, MaxRows())
+	if err != nil {
+		return nil, err
+	}
+
+	rowsJSON, err := json.Marshal(parsed.Rows)
+	if err != nil {
+		return nil, err
+	}
+	optsJSON, err := json.Marshal(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(fileName)
+	if len(name) > 255 {
+		name = name[:255]
+	}
+	m := &model.ImportJobModel{
+		ImportJobSchoolID:        schoolID,
+		ImportJobKind:            kind,
+		ImportJobStatus:          model.ImportJobQueued,
+		ImportJobDryRun:          dryRun,
+		ImportJobFileName:        &name,
+		ImportJobSourceFormat:    parsed.Format,
+		ImportJobLayout:          parsed.Layout,
+		ImportJobOptions:         datatypes.JSON(optsJSON),
+		ImportJobRows:            datatypes.JSON(rowsJSON),
+		ImportJobTotalRows:       len(parsed.Rows),
+		ImportJobErrors:          datatypes.JSON([]byte("[]")),
+		ImportJobCreatedByUserID: createdBy,
+	}
+	if err := db.WithContext(ctx).Create(m).Error; err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func GetJob(ctx context.Context, db *gorm.DB, schoolID, jobID uuid.UUID) (*model.ImportJobModel, error) {
+	var m model.ImportJobModel
+	if err := db.WithContext(ctx).
+		Omit("import_job_rows").
+		Where("import_job_id = ? AND import_job_school_id = ?", jobID, schoolID).
+		First(&m).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrJobNotFound
+		}
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Commit: hasil dry-run sudah dicek admin → jalankan sungguhan.
+func Commit(ctx context.Context, db *gorm.DB, schoolID, jobID uuid.UUID) (*model.ImportJobModel, error) {
+	res := db.WithContext(ctx).Model(&model.ImportJobModel{}).
+		Where("import_job_id = ? AND import_job_school_id = ? AND import_job_status = ?",
+			jobID, schoolID, model.ImportJobValidated).
+		Updates(map[string]any{
+			"import_job_status":         model.ImportJobQueued,
+			"import_job_dry_run":        false,
+			"import_job_processed_rows": 0,
+			"import_job_created_count":  0,
+			"import_job_skipped_count":  0,
+			"import_job_error_count":    0,
+			"import_job_errors":         datatypes.JSON([]byte("[]")),
+			"import_job_started_at":     nil,
+			"import_job_finished_at":    nil,
+		})
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		if _, err := GetJob(ctx, db, schoolID, jobID); err != nil {
+			return nil, err
+		}
+		return nil, ErrJobNotValidated
+	}
+	return GetJob(ctx, db, schoolID, jobID)
+}
+
+// Cancel: worker memeriksa status di antara batch, jadi job running ikut berhenti.
+func Cancel(ctx context.Context, db *gorm.DB, schoolID, jobID uuid.UUID) (*model.ImportJobModel, error) {
+	now := time.Now()
+	res := db.WithContext(ctx).Model(&model.ImportJobModel{}).
+		Where("import_job_id = ? AND import_job_school_id = ? AND import_job_status IN ?",
+			jobID, schoolID, []model.ImportJobStatus{
+				model.ImportJobQueued, model.ImportJobValidating, model.ImportJobValidated, model.ImportJobRunning,
+			}).
+		Updates(map[string]any{
+			"import_job_status":      model.ImportJobCanceled,
+			"import_job_finished_at": now,
+		})
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		if _, err := GetJob(ctx, db, schoolID, jobID); err != nil {
+			return nil, err
+		}
+		return nil, ErrJobNotCancelable
+	}
+	return GetJob(ctx, db, schoolID, jobID)
+}
+
+/* =========================================================
+   Worker side
+========================================================= */
+
+// ClaimNext mengambil 1 job antri (aman untuk multi-instance).
+func ClaimNext(ctx context.Context, db *gorm.DB) (*model.ImportJobModel, error) {
+	var out *model.ImportJobModel
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var m model.ImportJobModel
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("import_job_status = ?", model.ImportJobQueued).
+			Order("import_job_created_at ASC").
+			First(&m).Error; err != nil {
+			return err
+		}
+		now := time.Now()
+		if err := tx.Model(&m).Updates(map[string]any{
+			"import_job_status":     model.ImportJobValidating,
+			"import_job_started_at": now,
+		}).Error; err != nil {
+			return err
+		}
+		m.ImportJobStatus = model.ImportJobValidating
+		m.ImportJobStartedAt = &now
+		out = &m
+		return nil
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return out, err
+}
+
+// RequeueStale: job yang tertinggal validating/running (mis. proses mati) diantre ulang.
+// Baris yang sudah dibuat akan terdeteksi sebagai duplikat → skipped.
+func RequeueStale(ctx context.Context, db *gorm.DB, olderThan time.Duration) (int64, error) {
+	res := db.WithContext(ctx).Model(&model.ImportJobModel{}).
+		Where("import_job_status IN ? AND import_job_updated_at < ?",
+			[]model.ImportJobStatus{model.ImportJobValidating, model.ImportJobRunning},
+			time.Now().Add(-olderThan)).
+		Update("import_job_status", model.ImportJobQueued)
+	return res.RowsAffected, res.Error
+}
+
+// RunJob: validasi semua baris; dry-run berhenti di "validated",
+// selain itu proses per batch dengan savepoint per baris.
+func RunJob(ctx context.Context, db *gorm.DB, job *model.ImportJobModel) error {
+	var rows []ImportRow
+	if err := json.Unmarshal(job.ImportJobRows, &rows); err != nil {
+		return failJob(ctx, db, job, "rows rusak: "+err.Error())
+	}
+	var opts JobOptions
+	_ = json.Unmarshal(job.ImportJobOptions, &opts)
+
+	plans, rowErrs, err := validateRows(ctx, db, job, rows, opts)
+	if err != nil {
+		return failJob(ctx, db, job, err.Error())
+	}
+
+	if job.ImportJobDryRun {
+		skipped := 0
+		for _, p := range plans {
+			if p == nil {
+				continue
+			}
+			if p.existingID != nil && alreadyMember(ctx, db, job, *p.existingID) {
+				skipped++
+			}
+		}
+		now := time.Now()
+		return db.WithContext(ctx).Model(job).
+			Where("import_job_status = ?", model.ImportJobValidating).
+			Updates(map[string]any{
+				"import_job_status":         model.ImportJobValidated,
+				"import_job_processed_rows": len(rows),
+				"import_job_skipped_count":  skipped,
+				"import_job_error_count":    len(rowErrs),
+				"import_job_errors":         errorsJSON(rowErrs),
+				"import_job_finished_at":    now,
+			}).Error
+	}
+
+	res := db.WithContext(ctx).Model(job).
+		Where("import_job_status = ?", model.ImportJobValidating).
+		Updates(map[string]any{
+			"import_job_status":      model.ImportJobRunning,
+			"import_job_error_count": len(rowErrs),
+			"import_job_errors":      errorsJSON(rowErrs),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil // dibatalkan saat validasi
+	}
+
+	var (
+		created, skipped int
+		processed        = len(rowErrs)
+		size             = batchSize()
+	)
+	for start := 0; start < len(plans); start += size {
+		end := start + size
+		if end > len(plans) {
+			end = len(plans)
+		}
+
+		// berhenti kalau admin membatalkan
+		var status model.ImportJobStatus
+		if err := db.WithContext(ctx).Model(&model.ImportJobModel{}).
+			Select("import_job_status").
+			Where("import_job_id = ?", job.ImportJobID).
+			Scan(&status).Error; err != nil {
+			return err
+		}
+		if status != model.ImportJobRunning {
+			return nil
+		}
+
+		err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			for _, p := range plans[start:end] {
+				if p == nil {
+					continue
+				}
+				processed++
+
+				sp := fmt.Sprintf("imp_row_%d", p.src.Row)
+				if err := tx.SavePoint(sp).Error; err != nil {
+					return err
+				}
+				ok, err := applyRow(ctx, tx, job, p)
+				if err != nil {
+					if rbErr := tx.RollbackTo(sp).Error; rbErr != nil {
+						return rbErr
+					}
+					rowErrs = append(rowErrs, RowError{Row: p.src.Row, Message: err.Error()})
+					continue
+				}
+				if ok {
+					created++
+				} else {
+					skipped++
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return failJob(ctx, db, job, err.Error())
+		}
+
+		if err := db.WithContext(ctx).Model(job).Updates(map[string]any{
+			"import_job_processed_rows": processed,
+			"import_job_created_count":  created,
+			"import_job_skipped_count":  skipped,
+			"import_job_error_count":    len(rowErrs),
+			"import_job_errors":         errorsJSON(rowErrs),
+		}).Error; err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	return db.WithContext(ctx).Model(job).
+		Where("import_job_status = ?", model.ImportJobRunning).
+		Updates(map[string]any{
+			"import_job_status":         model.ImportJobCompleted,
+			"import_job_processed_rows": len(rows),
+			"import_job_finished_at":    now,
+		}).Error
+}
+
+func failJob(ctx context.Context, db *gorm.DB, job *model.ImportJobModel, reason string) error {
+	now := time.Now()
+	return db.WithContext(ctx).Model(job).Updates(map[string]any{
+		"import_job_status":         model.ImportJobFailed,
+		"import_job_failure_reason": reason,
+		"import_job_finished_at":    now,
+	}).Error
+}
+
+func errorsJSON(errs []RowError) datatypes.JSON {
+	if len(errs) > maxErrors() {
+		errs = errs[:maxErrors()]
+	}
+	b, _ := json.Marshal(errs)
+	if len(errs) == 0 {
+		b = []byte("[]")
+	}
+	return datatypes.JSON(b)
+}
+
+/* =========================================================
+   Validasi
+========================================================= */
+
+// validateRows → plans[i] nil bila baris i gagal (error dicatat di rowErrs).
+func validateRows(
+	ctx context.Context,
+	db *gorm.DB,
+	job *model.ImportJobModel,
+	rows []ImportRow,
+	opts JobOptions,
+) ([]*rowPlan, []RowError, error) {
+	plans := make([]*rowPlan, len(rows))
+	var rowErrs []RowError
+
+	sections, err := loadSections(ctx, db, job.ImportJobSchoolID)
+	if err != nil {
+		return nil, nil, err
+	}
+	var defaultSection *sectionModel.ClassSectionModel
+	if opts.DefaultClassSectionID != nil {
+		for i := range sections {
+			if sections[i].ClassSectionID == *opts.DefaultClassSectionID {
+				defaultSection = &sections[i]
+				break
+			}
+		}
+	}
+
+	seenEmail := map[string]int{}
+	seenNISN := map[string]int{}
+
+	for i, r := range rows {
+		p := &rowPlan{src: r}
+		var errs []RowError
+		add := func(field, msg string) {
+			errs = append(errs, RowError{Row: r.Row, Field: field, Message: msg})
+		}
+
+		name := strings.Join(strings.Fields(r.FullName), " ")
+		p.src.FullName = name
+		switch {
+		case name == "":
+			add("full_name", "nama wajib diisi")
+		case len(name) < 3 || len(name) > 100:
+			add("full_name", "nama harus 3–100 karakter")
+		}
+
+		if r.Email != nil {
+			e := strings.ToLower(strings.TrimSpace(*r.Email))
+			if _, err := mail.ParseAddress(e); err != nil || !strings.Contains(e, "@") {
+				add("email", "format email tidak valid")
+			} else if prev, dup := seenEmail[e]; dup {
+				add("email", fmt.Sprintf("email duplikat dengan baris %d", prev))
+			} else {
+				seenEmail[e] = r.Row
+				p.email = e
+			}
+		}
+
+		if r.NISN != nil {
+			n := normalizeNISN(*r.NISN)
+			if !reNISN.MatchString(n) {
+				add("nisn", "NISN harus 10 digit angka")
+			} else if prev, dup := seenNISN[n]; dup {
+				add("nisn", fmt.Sprintf("NISN duplikat dengan baris %d", prev))
+			} else {
+				seenNISN[n] = r.Row
+				p.nisn = &n
+			}
+		}
+
+		if r.Gender != nil {
+			if g, ok := normalizeGender(*r.Gender); ok {
+				gg := userModel.Gender(g)
+				p.gender = &gg
+			} else {
+				add("gender", "jenis kelamin harus L/P")
+			}
+		}
+
+		if r.DateOfBirth != nil {
+			if t, ok := parseBirthDate(*r.DateOfBirth); ok && t.Before(time.Now()) {
+				p.birthDate = &t
+			} else {
+				add("date_of_birth", "tanggal lahir tidak dikenali")
+			}
+		}
+
+		if r.Phone != nil {
+			if u, ok := phoneToWhatsappURL(*r.Phone); ok {
+				p.waURL = &u
+			}
+		}
+
+		if job.ImportJobKind == model.ImportKindStudents {
+			if r.Section != nil {
+				if s := matchSection(sections, *r.Section); s != nil {
+					p.section = s
+				} else {
+					add("section", "rombel/kelas tidak ditemukan: "+*r.Section)
+				}
+			} else {
+				p.section = defaultSection
+			}
+		}
+
+		if len(errs) > 0 {
+			rowErrs = append(rowErrs, errs...)
+			continue
+		}
+		plans[i] = p
+	}
+
+	if err := resolveExistingUsers(ctx, db, plans); err != nil {
+		return nil, nil, err
+	}
+	return plans, rowErrs, nil
+}
+
+func loadSections(ctx context.Context, db *gorm.DB, schoolID uuid.UUID) ([]sectionModel.ClassSectionModel, error) {
+	var out []sectionModel.ClassSectionModel
+	err := db.WithContext(ctx).
+		Select("class_section_id", "class_section_school_id", "class_section_slug", "class_section_name", "class_section_code").
+		Where("class_section_school_id = ? AND class_section_deleted_at IS NULL AND class_section_status = ?",
+			schoolID, sectionModel.ClassStatusActive).
+		Find(&out).Error
+	return out, err
+}
+
+func matchSection(sections []sectionModel.ClassSectionModel, key string) *sectionModel.ClassSectionModel {
+	k := normalizeHeader(key)
+	if k == "" {
+		return nil
+	}
+	for i := range sections {
+		s := &sections[i]
+		if normalizeHeader(s.ClassSectionName) == k || normalizeHeader(s.ClassSectionSlug) == k {
+			return s
+		}
+		if s.ClassSectionCode != nil && normalizeHeader(*s.ClassSectionCode) == k {
+			return s
+		}
+	}
+	return nil
+}
+
+// resolveExistingUsers: de-dup terhadap users (email) & user_profiles (NISN).
+func resolveExistingUsers(ctx context.Context, db *gorm.DB, plans []*rowPlan) error {
+	var emails, nisns []string
+	for _, p := range plans {
+		if p == nil {
+			continue
+		}
+		if p.email != "" {
+			emails = append(emails, p.email)
+		}
+		if p.nisn != nil {
+			nisns = append(nisns, *p.nisn)
+		}
+	}
+
+	byEmail := map[string]uuid.UUID{}
+	if len(emails) > 0 {
+		var rs []struct {
+			ID    uuid.UUID
+			Email string
+		}
+		if err := db.WithContext(ctx).Table("users").
+			Select("id, lower(email) AS email").
+			Where("lower(email) IN ? AND deleted_at IS NULL", emails).
+			Scan(&rs).Error; err != nil {
+			return err
+		}
+		for _, r := range rs {
+			byEmail[r.Email] = r.ID
+		}
+	}
+
+	byNISN := map[string]uuid.UUID{}
+	if len(nisns) > 0 {
+		var rs []struct {
+			UserID uuid.UUID `gorm:"column:user_id"`
+			NISN   string    `gorm:"column:nisn"`
+		}
+		if err := db.WithContext(ctx).Table("user_profiles").
+			Select("user_profile_user_id AS user_id, user_profile_nisn AS nisn").
+			Where("user_profile_nisn IN ? AND user_profile_deleted_at IS NULL", nisns).
+			Scan(&rs).Error; err != nil {
+			return err
+		}
+		for _, r := range rs {
+			byNISN[r.NISN] = r.UserID
+		}
+	}
+
+	for _, p := range plans {
+		if p == nil {
+			continue
+		}
+		if p.nisn != nil {
+			if id, ok := byNISN[*p.nisn]; ok {
+				p.existingID = &id
+				continue
+			}
+		}
+		if id, ok := byEmail[p.email]; ok && p.email != "" {
+			p.existingID = &id
+		}
+	}
+	return nil
+}
+
+func alreadyMember(ctx context.Context, db *gorm.DB, job *model.ImportJobModel, userID uuid.UUID) bool {
+	var n int64
+	q := db.WithContext(ctx)
+	switch job.ImportJobKind {
+	case model.ImportKindStudents:
+		q.Table("school_students ss").
+			Joins("JOIN user_profiles up ON up.user_profile_id = ss.school_student_user_profile_id").
+			Where("up.user_profile_user_id = ? AND ss.school_student_school_id = ?", userID, job.ImportJobSchoolID).
+			Where("ss.school_student_status = 'active' AND ss.school_student_deleted_at IS NULL").
+			Count(&n)
+	case model.ImportKindTeachers:
+		q.Table("school_teachers st").
+			Joins("JOIN user_teachers ut ON ut.user_teacher_id = st.school_teacher_user_teacher_id").
+			Where("ut.user_teacher_user_id = ? AND st.school_teacher_school_id = ?", userID, job.ImportJobSchoolID).
+			Where("st.school_teacher_deleted_at IS NULL").
+			Count(&n)
+	}
+	return n > 0
+}
+
+/* =========================================================
+   Apply (1 baris, di dalam savepoint)
+========================================================= */
+
+// applyRow → (true, nil) bila dibuat, (false, nil) bila sudah terdaftar (skipped).
+func applyRow(ctx context.Context, tx *gorm.DB, job *model.ImportJobModel, p *rowPlan) (bool, error) {
+	if p.existingID != nil && alreadyMember(ctx, tx, job, *p.existingID) {
+		return false, nil
+	}
+
+	user, err := ensureUser(ctx, tx, p)
+	if err != nil {
+		return false, err
+	}
+	prof, err := ensureProfile(ctx, tx, user, p)
+	if err != nil {
+		return false, err
+	}
+
+	assignedBy := uuid.Nil
+	if job.ImportJobCreatedByUserID != nil {
+		assignedBy = *job.ImportJobCreatedByUserID
+	}
+	schoolID := job.ImportJobSchoolID
+	stats := statsSvc.NewLembagaStatsService()
+	if err := stats.EnsureForSchool(tx, schoolID); err != nil {
+		return false, err
+	}
+
+	switch job.ImportJobKind {
+	case model.ImportKindStudents:
+		if err := createStudent(ctx, tx, schoolID, prof, p); err != nil {
+			return false, err
+		}
+		if err := membership.New().GrantRole(tx, user.ID, "student", &schoolID, assignedBy); err != nil {
+			return false, err
+		}
+		if err := stats.IncActiveStudents(tx, schoolID, 1); err != nil {
+			return false, err
+		}
+	case model.ImportKindTeachers:
+		if err := createTeacher(ctx, tx, schoolID, user, p); err != nil {
+			return false, err
+		}
+		if err := membership.New().GrantRole(tx, user.ID, "teacher", &schoolID, assignedBy); err != nil {
+			return false, err
+		}
+		if err := stats.IncActiveTeachers(tx, schoolID, 1); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func ensureUser(ctx context.Context, tx *gorm.DB, p *rowPlan) (*userModel.UserModel, error) {
+	var u userModel.UserModel
+	if p.existingID != nil {
+		if err := tx.WithContext(ctx).First(&u, "id = ?", *p.existingID).Error; err != nil {
+			return nil, err
+		}
+		return &u, nil
+	}
+
+	email := p.email
+	if email == "" {
+		// akun tanpa email (umum untuk siswa SD) → email placeholder unik
+		local := "u" + strings.ReplaceAll(uuid.NewString(), "-", "")[:16]
+		if p.nisn != nil {
+			local = "nisn" + *p.nisn
+		}
+		email = local + "@" + placeholderDomain()
+	}
+
+	userName := p.src.FullName
+	if len(userName) > 50 {
+		userName = strings.TrimSpace(userName[:50])
+	}
+	fullName := p.src.FullName
+	u = userModel.UserModel{
+		UserName: userName,
+		FullName: &fullName,
+		Email:    email,
+		IsActive: true,
+	}
+	if err := tx.WithContext(ctx).Create(&u).Error; err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+func ensureProfile(ctx context.Context, tx *gorm.DB, u *userModel.UserModel, p *rowPlan) (*userModel.UserProfileModel, error) {
+	if err := userProfileService.EnsureProfileRow(ctx, tx, u.ID, u.FullName); err != nil {
+		return nil, err
+	}
+	var prof userModel.UserProfileModel
+	if err := tx.WithContext(ctx).
+		Where("user_profile_user_id = ? AND user_profile_deleted_at IS NULL", u.ID).
+		First(&prof).Error; err != nil {
+		return nil, err
+	}
+
+	// hanya isi kolom yang masih kosong (jangan timpa data user existing)
+	upd := map[string]any{}
+	if prof.UserProfileNISN == nil && p.nisn != nil {
+		upd["user_profile_nisn"] = *p.nisn
+		prof.UserProfileNISN = p.nisn
+	}
+	if prof.UserProfileGender == nil && p.gender != nil {
+		upd["user_profile_gender"] = *p.gender
+		prof.UserProfileGender = p.gender
+	}
+	if prof.UserProfileDateOfBirth == nil && p.birthDate != nil {
+		upd["user_profile_date_of_birth"] = *p.birthDate
+		prof.UserProfileDateOfBirth = p.birthDate
+	}
+	if prof.UserProfilePlaceOfBirth == nil && p.src.PlaceOfBirth != nil {
+		upd["user_profile_place_of_birth"] = *p.src.PlaceOfBirth
+		prof.UserProfilePlaceOfBirth = p.src.PlaceOfBirth
+	}
+	if prof.UserProfileParentName == nil && p.src.ParentName != nil {
+		upd["user_profile_parent_name"] = *p.src.ParentName
+		prof.UserProfileParentName = p.src.ParentName
+	}
+	if p.waURL != nil {
+		// nomor HP pada ekspor Dapodik siswa = kontak orang tua
+		if p.src.ParentName != nil && prof.UserProfileParentWhatsappURL == nil {
+			upd["user_profile_parent_whatsapp_url"] = *p.waURL
+			prof.UserProfileParentWhatsappURL = p.waURL
+		} else if prof.UserProfileWhatsappURL == nil {
+			upd["user_profile_whatsapp_url"] = *p.waURL
+			prof.UserProfileWhatsappURL = p.waURL
+		}
+	}
+	if len(upd) > 0 {
+		if err := tx.WithContext(ctx).Model(&prof).Updates(upd).Error; err != nil {
+			return nil, err
+		}
+	}
+	return &prof, nil
+}
+
+func genderCache(g *userModel.Gender) *string {
+	if g == nil {
+		return nil
+	}
+	s := string(*g)
+	return &s
+}
+
+func truncPtr(s *string, n int) *string {
+	if s == nil {
+		return nil
+	}
+	v := *s
+	if len(v) > n {
+		v = v[:n]
+	}
+	return &v
+}
+
+func createStudent(ctx context.Context, tx *gorm.DB, schoolID uuid.UUID, prof *userModel.UserProfileModel, p *rowPlan) error {
+	slug, err := helper.EnsureUniqueSlugCI(ctx, tx, "school_students", "school_student_slug",
+		helper.SuggestSlugFromName(p.src.FullName),
+		func(q *gorm.DB) *gorm.DB {
+			return q.Where("school_student_school_id = ? AND school_student_deleted_at IS NULL", schoolID)
+		}, 50)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	name := truncPtr(&p.src.FullName, 80)
+	st := &personModel.SchoolStudentModel{
+		SchoolStudentSchoolID:           schoolID,
+		SchoolStudentUserProfileID:      prof.UserProfileID,
+		SchoolStudentSlug:               slug,
+		SchoolStudentCode:               truncPtr(p.src.Code, 50),
+		SchoolStudentStatus:             personModel.SchoolStudentActive,
+		SchoolStudentJoinedAt:           &now,
+		SchoolStudentNeedsClassSections: p.section == nil,
+
+		SchoolStudentUserProfileNameCache:              name,
+		SchoolStudentUserProfileAvatarURLCache:         truncPtr(prof.UserProfileAvatarURL, 255),
+		SchoolStudentUserProfileWhatsappURLCache:       truncPtr(prof.UserProfileWhatsappURL, 50),
+		SchoolStudentUserProfileParentNameCache:        truncPtr(prof.UserProfileParentName, 80),
+		SchoolStudentUserProfileParentWhatsappURLCache: truncPtr(prof.UserProfileParentWhatsappURL, 50),
+		SchoolStudentUserProfileGenderCache:            genderCache(prof.UserProfileGender),
+	}
+	if err := tx.WithContext(ctx).Create(st).Error; err != nil {
+		return err
+	}
+
+	if p.section == nil {
+		return nil
+	}
+	scs := &sectionModel.StudentClassSection{
+		StudentClassSectionSchoolStudentID:  st.SchoolStudentID,
+		StudentClassSectionSchoolID:         schoolID,
+		StudentClassSectionSectionID:        p.section.ClassSectionID,
+		StudentClassSectionSectionSlugCache: p.section.ClassSectionSlug,
+		StudentClassSectionStatus:           sectionModel.StudentClassSectionActive,
+		StudentClassSectionAssignedAt:       now,
+
+		StudentClassSectionUserProfileNameCache:              name,
+		StudentClassSectionUserProfileAvatarURLCache:         st.SchoolStudentUserProfileAvatarURLCache,
+		StudentClassSectionUserProfileWhatsappURLCache:       st.SchoolStudentUserProfileWhatsappURLCache,
+		StudentClassSectionUserProfileParentNameCache:        st.SchoolStudentUserProfileParentNameCache,
+		StudentClassSectionUserProfileParentWhatsappURLCache: st.SchoolStudentUserProfileParentWhatsappURLCache,
+		StudentClassSectionUserProfileGenderCache:            st.SchoolStudentUserProfileGenderCache,
+		StudentClassSectionStudentCodeCache:                  st.SchoolStudentCode,
+	}
+	return tx.WithContext(ctx).Create(scs).Error
+}
+
+func createTeacher(ctx context.Context, tx *gorm.DB, schoolID uuid.UUID, u *userModel.UserModel, p *rowPlan) error {
+	ut, err := userTeacherService.EnsureUserTeacherFromUser(ctx, tx, u)
+	if err != nil {
+		return err
+	}
+
+	slug, err := helper.EnsureUniqueSlugCI(ctx, tx, "school_teachers", "school_teacher_slug",
+		helper.SuggestSlugFromName(p.src.FullName),
+		func(q *gorm.DB) *gorm.DB {
+			return q.Where("school_teacher_school_id = ? AND school_teacher_deleted_at IS NULL", schoolID)
+		}, 50)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	t := &personModel.SchoolTeacherModel{
+		SchoolTeacherSchoolID:      schoolID,
+		SchoolTeacherUserTeacherID: ut.UserTeacherID,
+		SchoolTeacherCode:          truncPtr(p.src.Code, 50),
+		SchoolTeacherSlug:          &slug,
+		SchoolTeacherIsActive:      true,
+		SchoolTeacherJoinedAt:      &now,
+		SchoolTeacherIsPublic:      true,
+
+		SchoolTeacherUserTeacherFullNameCache:    truncPtr(&p.src.FullName, 80),
+		SchoolTeacherUserTeacherAvatarURLCache:   truncPtr(ut.UserTeacherAvatarURL, 255),
+		SchoolTeacherUserTeacherWhatsappURLCache: truncPtr(ut.UserTeacherWhatsappURL, 50),
+		SchoolTeacherUserTeacherGenderCache:      genderCache(p.gender),
+	}
+	return tx.WithContext(ctx).Create(t).Error
+}
@@ -0,0 +1,533 @@
+// file: internals/features/lembaga/school_yayasans/imports/service/import_parser.go
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"io"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("format file tidak didukung (gunakan .xlsx atau .csv)")
+	ErrHeaderNotFound    = errors.New("baris header tidak ditemukan (minimal kolom nama)")
+	ErrEmptyFile         = errors.New("file tidak memiliki baris data")
+	ErrTooManyRows       = errors.New("jumlah baris melebihi batas import")
+)
+
+const (
+	LayoutGeneric = "generic"
+	LayoutDapodik = "dapodik"
+
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+
+	// Ekspor Dapodik punya beberapa baris judul sebelum header
+	headerScanLimit = 15
+)
+
+// ImportRow = 1 baris ter-normalisasi (hasil parse, sebelum validasi).
+type ImportRow struct {
+	Row          int     `json:"row"` // nomor baris di file (1-based)
+	FullName     string  `json:"full_name"`
+	Email        *string `json:"email,omitempty"`
+	NISN         *string `json:"nisn,omitempty"`
+	Code         *string `json:"code,omitempty"` // NIS/NIPD (siswa) atau NIP/NUPTK (guru)
+	Gender       *string `json:"gender,omitempty"`
+	PlaceOfBirth *string `json:"place_of_birth,omitempty"`
+	DateOfBirth  *string `json:"date_of_birth,omitempty"` // mentah; divalidasi belakangan
+	ParentName   *string `json:"parent_name,omitempty"`
+	Phone        *string `json:"phone,omitempty"`
+	Section      *string `json:"section,omitempty"` // nama/slug/kode rombel
+}
+
+type ParsedFile struct {
+	Format string
+	Layout string
+	Rows   []ImportRow
+}
+
+/* =========================================================
+   Header aliases (generic + Dapodik)
+========================================================= */
+
+const (
+	fieldFullName     = "full_name"
+	fieldEmail        = "email"
+	fieldNISN         = "nisn"
+	fieldCode         = "code"
+	fieldGender       = "gender"
+	fieldPlaceOfBirth = "place_of_birth"
+	fieldDateOfBirth  = "date_of_birth"
+	fieldGuardian     = "guardian_name"
+	fieldFather       = "father_name"
+	fieldMother       = "mother_name"
+	fieldPhone        = "phone"
+	fieldSection      = "section"
+)
+
+// key = header yang sudah dinormalisasi (huruf kecil, tanpa spasi/tanda baca)
+var headerAliases = map[string]string{
+	"nama": fieldFullName, "namalengkap": fieldFullName, "fullname": fieldFullName,
+	"name": fieldFullName, "namapesertadidik": fieldFullName, "namaptk": fieldFullName,
+	"namasiswa": fieldFullName, "namaguru": fieldFullName,
+
+	"email": fieldEmail, "surel": fieldEmail, "emailaddress": fieldEmail,
+
+	"nisn": fieldNISN,
+
+	"nipd": fieldCode, "nis": fieldCode, "nuptk": fieldCode, "nip": fieldCode,
+	"code": fieldCode, "kode": fieldCode, "nomorinduk": fieldCode,
+
+	"jk": fieldGender, "jeniskelamin": fieldGender, "gender": fieldGender, "lp": fieldGender,
+
+	"tempatlahir": fieldPlaceOfBirth, "placeofbirth": fieldPlaceOfBirth,
+	"tanggallahir": fieldDateOfBirth, "tgllahir": fieldDateOfBirth, "dateofbirth": fieldDateOfBirth,
+
+	"namawali": fieldGuardian, "wali": fieldGuardian, "parentname": fieldGuardian, "namaorangtua": fieldGuardian,
+	"namaayah": fieldFather, "ayah": fieldFather,
+	"namaibu": fieldMother, "ibu": fieldMother, "namaibukandung": fieldMother,
+
+	"hp": fieldPhone, "nohp": fieldPhone, "nomorhp": fieldPhone, "telepon": fieldPhone,
+	"phone": fieldPhone, "whatsapp": fieldPhone, "wa": fieldPhone,
+
+	"rombelsaatini": fieldSection, "rombel": fieldSection, "kelas": fieldSection,
+	"section": fieldSection, "classsection": fieldSection,
+}
+
+func normalizeHeader(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(s)) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// mapHeader → field → index kolom. Kolom pertama yang cocok menang
+// (ekspor Dapodik mengulang "Nama" untuk ayah/ibu/wali di bagian belakang).
+func mapHeader(cells []string) (map[string]int, string) {
+	out := map[string]int{}
+	layout := LayoutGeneric
+	for i, h := range cells {
+		n := normalizeHeader(h)
+		if n == "nipd" || n == "rombelsaatini" {
+			layout = LayoutDapodik
+		}
+		f, ok := headerAliases[n]
+		if !ok {
+			continue
+		}
+		if _, dup := out[f]; dup {
+			continue
+		}
+		out[f] = i
+	}
+	return out, layout
+}
+
+/* =========================================================
+   Entry point
+========================================================= */
+
+// ParseImportFile membaca CSV/XLSX → baris ter-normalisasi.
+func ParseImportFile(fileName string, data []byte, maxRows int) (*ParsedFile, error) {
+	ext := strings.ToLower(strings.TrimPrefix(path.Ext(fileName), "."))
+
+	var (
+		grid   [][]string
+		format string
+		err    error
+	)
+	switch ext {
+	case FormatCSV, "txt":
+		format = FormatCSV
+		grid, err = readCSV(data)
+	case FormatXLSX:
+		format = FormatXLSX
+		grid, err = readXLSX(data)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// cari baris header
+	headerAt := -1
+	var cols map[string]int
+	layout := LayoutGeneric
+	for i := 0; i < len(grid) && i < headerScanLimit; i++ {
+		m, l := mapHeader(grid[i])
+		if _, ok := m[fieldFullName]; ok {
+			headerAt, cols, layout = i, m, l
+			break
+		}
+	}
+	if headerAt < 0 {
+		return nil, ErrHeaderNotFound
+	}
+
+	rows := make([]ImportRow, 0, len(grid)-headerAt)
+	for i := headerAt + 1; i < len(grid); i++ {
+		line := grid[i]
+		if isBlankLine(line) {
+			continue
+		}
+		r := ImportRow{Row: i + 1}
+		r.FullName = cell(line, cols, fieldFullName)
+		r.Email = cellPtr(line, cols, fieldEmail)
+		r.NISN = cellPtr(line, cols, fieldNISN)
+		r.Code = cellPtr(line, cols, fieldCode)
+		r.Gender = cellPtr(line, cols, fieldGender)
+		r.PlaceOfBirth = cellPtr(line, cols, fieldPlaceOfBirth)
+		r.DateOfBirth = cellPtr(line, cols, fieldDateOfBirth)
+		r.Phone = cellPtr(line, cols, fieldPhone)
+		r.Section = cellPtr(line, cols, fieldSection)
+
+		// wali > ayah > ibu
+		for _, f := range []string{fieldGuardian, fieldFather, fieldMother} {
+			if v := cellPtr(line, cols, f); v != nil {
+				r.ParentName = v
+				break
+			}
+		}
+
+		rows = append(rows, r)
+		if maxRows > 0 && len(rows) > maxRows {
+			return nil, ErrTooManyRows
+		}
+	}
+	if len(rows) == 0 {
+		return nil, ErrEmptyFile
+	}
+	return &ParsedFile{Format: format, Layout: layout, Rows: rows}, nil
+}
+
+func isBlankLine(line []string) bool {
+	for _, s := range line {
+		if strings.TrimSpace(s) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func cell(line []string, cols map[string]int, field string) string {
+	i, ok := cols[field]
+	if !ok || i >= len(line) {
+		return ""
+	}
+	return strings.TrimSpace(line[i])
+}
+
+func cellPtr(line []string, cols map[string]int, field string) *string {
+	v := cell(line, cols, field)
+	if v == "" || v == "-" {
+		return nil
+	}
+	return &v
+}
+
+/* =========================================================
+   CSV
+========================================================= */
+
+func readCSV(data []byte) ([][]string, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // BOM dari Excel
+
+	// Excel lokal Indonesia sering ekspor CSV pakai ';'
+	// (sampel beberapa baris awal; ekspor Dapodik diawali baris judul)
+	sep := ','
+	sample := data
+	for i, n := 0, 0; i < len(data); i++ {
+		if data[i] == '\n' {
+			if n++; n == headerScanLimit {
+				sample = data[:i]
+				break
+			}
+		}
+	}
+	if bytes.Count(sample, []byte(";")) > bytes.Count(sample, []byte(",")) {
+		sep = ';'
+	}
+
+	r := csv.NewReader(bytes.NewReader(data))
+	r.Comma = sep
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	r.TrimLeadingSpace = true
+
+	var out [][]string
+	for {
+		rec, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, rec)
+	}
+	return out, nil
+}
+
+/* =========================================================
+   XLSX (sheet pertama; cukup untuk ekspor Dapodik/Excel)
+========================================================= */
+
+type xlsxSST struct {
+	Items []xlsxSI `xml:"si"`
+}
+
+type xlsxSI struct {
+	T string   `xml:"t"`
+	R []xlsxRT `xml:"r"`
+}
+
+type xlsxRT struct {
+	T string `xml:"t"`
+}
+
+func (si xlsxSI) text() string {
+	if len(si.R) == 0 {
+		return si.T
+	}
+	var b strings.Builder
+	for _, r := range si.R {
+		b.WriteString(r.T)
+	}
+	return b.String()
+}
+
+type xlsxSheet struct {
+	Rows []struct {
+		Cells []struct {
+			Ref    string  `xml:"r,attr"`
+			Type   string  `xml:"t,attr"`
+			Style  string  `xml:"s,attr"`
+			Value  string  `xml:"v"`
+			Inline *xlsxSI `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		RID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRels struct {
+	Items []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+func readXLSX(data []byte) ([][]string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, ErrUnsupportedFormat
+	}
+	files := map[string]*zip.File{}
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	var sst xlsxSST
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := decodeZipXML(f, &sst); err != nil {
+			return nil, err
+		}
+	}
+
+	sheetPath := firstSheetPath(files)
+	f, ok := files[sheetPath]
+	if !ok {
+		return nil, ErrUnsupportedFormat
+	}
+	var sh xlsxSheet
+	if err := decodeZipXML(f, &sh); err != nil {
+		return nil, err
+	}
+
+	out := make([][]string, 0, len(sh.Rows))
+	for _, row := range sh.Rows {
+		var line []string
+		for i, c := range row.Cells {
+			col := i
+			if c.Ref != "" {
+				col = columnIndex(c.Ref)
+			}
+			for len(line) <= col {
+				line = append(line, "")
+			}
+			switch c.Type {
+			case "s":
+				if idx, err := strconv.Atoi(c.Value); err == nil && idx >= 0 && idx < len(sst.Items) {
+					line[col] = sst.Items[idx].text()
+				}
+			case "inlineStr":
+				if c.Inline != nil {
+					line[col] = c.Inline.text()
+				}
+			default:
+				line[col] = c.Value
+			}
+		}
+		out = append(out, line)
+	}
+	return out, nil
+}
+
+func firstSheetPath(files map[string]*zip.File) string {
+	const fallback = "xl/worksheets/sheet1.xml"
+	wbf, ok := files["xl/workbook.xml"]
+	if !ok {
+		return fallback
+	}
+	relf, ok := files["xl/_rels/workbook.xml.rels"]
+	if !ok {
+		return fallback
+	}
+	var wb xlsxWorkbook
+	var rels xlsxRels
+	if decodeZipXML(wbf, &wb) != nil || decodeZipXML(relf, &rels) != nil || len(wb.Sheets) == 0 {
+		return fallback
+	}
+	for _, r := range rels.Items {
+		if r.ID != wb.Sheets[0].RID {
+			continue
+		}
+		t := strings.TrimPrefix(r.Target, "/")
+		if !strings.HasPrefix(t, "xl/") {
+			t = "xl/" + t
+		}
+		return t
+	}
+	return fallback
+}
+
+func decodeZipXML(f *zip.File, v any) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	return xml.NewDecoder(rc).Decode(v)
+}
+
+// "AB12" → 27
+func columnIndex(ref string) int {
+	n := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		n = n*26 + int(r-'A'+1)
+	}
+	return n - 1
+}
+
+/* =========================================================
+   Normalisasi nilai
+========================================================= */
+
+var (
+	reDigits = regexp.MustCompile(`^[0-9]+$`)
+	reNISN   = regexp.MustCompile(`^[0-9]{10}$`)
+)
+
+// Excel sering menyimpan NISN sebagai angka → "5.012345678E9" / leading zero hilang
+// (NISN kelahiran 2000-an diawali "00").
+func normalizeNISN(s string) string {
+	s = strings.TrimSpace(strings.TrimPrefix(s, "'"))
+	if strings.ContainsAny(s, "eE.") {
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			s = strconv.FormatFloat(f, 'f', 0, 64)
+		}
+	}
+	if reDigits.MatchString(s) && len(s) >= 8 && len(s) < 10 {
+		s = strings.Repeat("0", 10-len(s)) + s
+	}
+	return s
+}
+
+func normalizeGender(s string) (string, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "l", "lk", "laki-laki", "laki laki", "lakilaki", "pria", "male", "m":
+		return "male", true
+	case "p", "pr", "perempuan", "wanita", "female", "f":
+		return "female", true
+	}
+	return "", false
+}
+
+var dateLayouts = []string{
+	"2006-01-02",
+	"02/01/2006",
+	"2/1/2006",
+	"02-01-2006",
+	"2-1-2006",
+	"02.01.2006",
+	"2006/01/02",
+}
+
+var idMonths = strings.NewReplacer(
+	"januari", "January", "februari", "February", "maret", "March", "april", "April",
+	"mei", "May", "juni", "June", "juli", "July", "agustus", "August",
+	"september", "September", "oktober", "October", "november", "November", "desember", "December",
+)
+
+// parseBirthDate menerima ISO, dd/mm/yyyy, "12 Januari 2010", atau serial date Excel.
+func parseBirthDate(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, false
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil && f > 1 && f < 100000 {
+		// Excel epoch (sudah memperhitungkan bug 1900 leap year)
+		base := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+		return base.AddDate(0, 0, int(f)), true
+	}
+	for _, l := range dateLayouts {
+		if t, err := time.Parse(l, s); err == nil {
+			return t, true
+		}
+	}
+	if t, err := time.Parse("2 January 2006", idMonths.Replace(strings.ToLower(s))); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// 08xx / 62xx / +62xx → https://wa.me/62xx
+func phoneToWhatsappURL(s string) (string, bool) {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	d := b.String()
+	switch {
+	case strings.HasPrefix(d, "0"):
+		d = "62" + d[1:]
+	case strings.HasPrefix(d, "8"):
+		d = "62" + d
+	}
+	if len(d) < 9 || len(d) > 15 {
+		return "", false
+	}
+	return "https://wa.me/" + d, true
+}
@@ -0,0 +1,61 @@
+// file: internals/features/lembaga/school_yayasans/imports/worker/import_worker.go
+package worker
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"gorm.io/gorm"
+
+	svc "madinahsalam_backend/internals/features/lembaga/school_yayasans/imports/service"
+)
+
+// RunImportWorker: polling job import antri sampai ctx dibatalkan.
+// Interval via IMPORT_POLL_INTERVAL (default 5s).
+func RunImportWorker(ctx context.Context, db *gorm.DB) {
+	interval := 5 * time.Second
+	if v, err := time.ParseDuration(os.Getenv("IMPORT_POLL_INTERVAL")); err == nil && v > 0 {
+		interval = v
+	}
+
+	// job yatim dari proses sebelumnya (restart saat running)
+	if n, err := svc.RequeueStale(ctx, db, 10*time.Minute); err != nil {
+		log.Printf("[IMPORT] requeue stale error: %v", err)
+	} else if n > 0 {
+		log.Printf("[IMPORT] requeued %d stale job(s)", n)
+	}
+
+	log.Printf("[IMPORT] worker started interval=%s", interval)
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		// habiskan antrean sebelum tidur lagi
+		for {
+			job, err := svc.ClaimNext(ctx, db)
+			if err != nil {
+				log.Printf("[IMPORT] claim error: %v", err)
+				break
+			}
+			if job == nil {
+				break
+			}
+			start := time.Now()
+			if err := svc.RunJob(ctx, db, job); err != nil {
+				log.Printf("[IMPORT] job=%s error: %v", job.ImportJobID, err)
+				continue
+			}
+			log.Printf("[IMPORT] job=%s kind=%s dry_run=%v rows=%d done in %s",
+				job.ImportJobID, job.ImportJobKind, job.ImportJobDryRun, job.ImportJobTotalRows, time.Since(start))
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Printf("[IMPORT] worker stopped")
+			return
+		case <-t.C:
+		}
+	}
+}
@@ -0,0 +1,274 @@
+// file: internals/features/lembaga/school_yayasans/student_transfers/controller/student_transfers_controller.go
+package controller
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	dto "madinahsalam_backend/internals/features/lembaga/school_yayasans/student_transfers/dto"
+	model "madinahsalam_backend/internals/features/lembaga/school_yayasans/student_transfers/model"
+	svc "madinahsalam_backend/internals/features/lembaga/school_yayasans/student_transfers/service"
+	studentDTO "madinahsalam_backend/internals/features/lembaga/school_yayasans/teachers_students/dto"
+	helper "madinahsalam_backend/internals/helpers"
+	helperAuth "madinahsalam_backend/internals/helpers/auth"
+)
+
+/*
+Mutasi siswa antar school (scope: school aktif di token)
+
+POST /api/a/student-transfers                 (asal)   {school_student_id, destination_school_id, reason}
+GET  /api/a/student-transfers?direction=incoming|outgoing&status=pending
+GET  /api/a/student-transfers/:id
+GET  /api/a/student-transfers/:id/transcript  (asal & tujuan; snapshot)
+POST /api/a/student-transfers/:id/approve     (tujuan) {school_student_code, note}
+POST /api/a/student-transfers/:id/reject      (tujuan) {note}
+POST /api/a/student-transfers/:id/cancel      (asal)   {note}
+*/
+
+type StudentTransferController struct {
+	DB       *gorm.DB
+	Validate *validator.Validate
+}
+
+func NewStudentTransferController(db *gorm.DB) *StudentTransferController {
+	return &StudentTransferController{DB: db, Validate: validator.New()}
+}
+
+func resolveAdminSchool(c *fiber.Ctx) (uuid.UUID, error) {
+	schoolID, err := helperAuth.ResolveSchoolIDFromContext(c)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if err := helperAuth.EnsureDKMSchool(c, schoolID); err != nil {
+		return uuid.Nil, err
+	}
+	return schoolID, nil
+}
+
+func parseID(c *fiber.Ctx) (uuid.UUID, error) {
+	id, err := uuid.Parse(strings.TrimSpace(c.Params("id")))
+	if err != nil {
+		return uuid.Nil, fiber.NewError(fiber.StatusBadRequest, "id tidak valid")
+	}
+	return id, nil
+}
+
+func writeErr(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, svc.ErrTransferNotFound), errors.Is(err, svc.ErrStudentNotFound):
+		return helper.JsonError(c, fiber.StatusNotFound, err.Error())
+	case errors.Is(err, svc.ErrTransferNotPending), errors.Is(err, svc.ErrAlreadyActiveInTarget):
+		return helper.JsonError(c, fiber.StatusConflict, err.Error())
+	case errors.Is(err, svc.ErrStudentNotActive), errors.Is(err, svc.ErrDestinationNotFound), errors.Is(err, svc.ErrSameSchool):
+		return helper.JsonError(c, fiber.StatusBadRequest, err.Error())
+	}
+	var fe *fiber.Error
+	if errors.As(err, &fe) {
+		return helper.JsonError(c, fe.Code, fe.Message)
+	}
+	return helper.JsonError(c, fiber.StatusInternalServerError, err.Error())
+}
+
+func userIDPtr(c *fiber.Ctx) *uuid.UUID {
+	if uid, err := helperAuth.GetUserIDFromToken(c); err == nil && uid != uuid.Nil {
+		return &uid
+	}
+	return nil
+}
+
+// load transfer yang melibatkan school ini (asal ATAU tujuan)
+func (h *StudentTransferController) loadVisible(c *fiber.Ctx, schoolID uuid.UUID) (*model.StudentTransferModel, error) {
+	id, err := parseID(c)
+	if err != nil {
+		return nil, err
+	}
+	var m model.StudentTransferModel
+	if err := h.DB.WithContext(c.Context()).
+		Where("student_transfer_id = ?", id).
+		Where("student_transfer_origin_school_id = ? OR student_transfer_destination_school_id = ?", schoolID, schoolID).
+		First(&m).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, svc.ErrTransferNotFound
+		}
+		return nil, err
+	}
+	return &m, nil
+}
+
+// POST /api/a/student-transfers
+func (h *StudentTransferController) Create(c *fiber.Ctx) error {
+	schoolID, err := resolveAdminSchool(c)
+	if err != nil {
+		return err
+	}
+
+	var req dto.CreateStudentTransferRequest
+	if err := c.BodyParser(&req); err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "Payload tidak valid")
+	}
+	req.Normalize()
+	if err := h.Validate.Struct(&req); err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	m, err := svc.CreateTransfer(c.Context(), h.DB, schoolID, req.SchoolStudentID, req.DestinationSchoolID, req.Reason, userIDPtr(c))
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonCreated(c, "Pengajuan mutasi dibuat", dto.FromModel(m, schoolID))
+}
+
+// GET /api/a/student-transfers
+func (h *StudentTransferController) List(c *fiber.Ctx) error {
+	schoolID, err := resolveAdminSchool(c)
+	if err != nil {
+		return err
+	}
+	p := helper.ResolvePaging(c, 20, 100)
+
+	q := h.DB.WithContext(c.Context()).Model(&model.StudentTransferModel{})
+	switch strings.ToLower(strings.TrimSpace(c.Query("direction"))) {
+	case "incoming":
+		q = q.Where("student_transfer_destination_school_id = ?", schoolID)
+	case "outgoing":
+		q = q.Where("student_transfer_origin_school_id = ?", schoolID)
+	case "":
+		q = q.Where("student_transfer_origin_school_id = ? OR student_transfer_destination_school_id = ?", schoolID, schoolID)
+	default:
+		return helper.JsonError(c, fiber.StatusBadRequest, "direction harus incoming|outgoing")
+	}
+	if s := strings.ToLower(strings.TrimSpace(c.Query("status"))); s != "" {
+		q = q.Where("student_transfer_status = ?", s)
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return helper.JsonError(c, fiber.StatusInternalServerError, "Gagal menghitung data")
+	}
+	var rows []model.StudentTransferModel
+	if err := q.Order("student_transfer_created_at DESC").
+		Limit(p.Limit).Offset(p.Offset).
+		Find(&rows).Error; err != nil {
+		return helper.JsonError(c, fiber.StatusInternalServerError, "Gagal mengambil data")
+	}
+	return helper.JsonList(c, "OK", dto.FromModels(rows, schoolID), helper.BuildPaginationFromPage(total, p.Page, p.PerPage))
+}
+
+// GET /api/a/student-transfers/:id
+func (h *StudentTransferController) Detail(c *fiber.Ctx) error {
+	schoolID, err := resolveAdminSchool(c)
+	if err != nil {
+		return err
+	}
+	m, err := h.loadVisible(c, schoolID)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonOK(c, "OK", dto.FromModel(m, schoolID))
+}
+
+// GET /api/a/student-transfers/:id/transcript
+// Isi diambil dari snapshot transfer — bukan query langsung ke data school asal.
+func (h *StudentTransferController) Transcript(c *fiber.Ctx) error {
+	schoolID, err := resolveAdminSchool(c)
+	if err != nil {
+		return err
+	}
+	m, err := h.loadVisible(c, schoolID)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	out, err := dto.FromTranscript(m, schoolID)
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusInternalServerError, "Transcript rusak")
+	}
+	return helper.JsonOK(c, "OK", out)
+}
+
+func (h *StudentTransferController) parseDecision(c *fiber.Ctx) (dto.DecideStudentTransferRequest, error) {
+	var req dto.DecideStudentTransferRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return req, fiber.NewError(fiber.StatusBadRequest, "Payload tidak valid")
+		}
+	}
+	req.Normalize()
+	if err := h.Validate.Struct(&req); err != nil {
+		return req, fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	return req, nil
+}
+
+// POST /api/a/student-transfers/:id/approve
+func (h *StudentTransferController) Approve(c *fiber.Ctx) error {
+	schoolID, err := resolveAdminSchool(c)
+	if err != nil {
+		return err
+	}
+	id, err := parseID(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	req, err := h.parseDecision(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+
+	m, st, err := svc.Approve(c.Context(), h.DB, id, schoolID, userIDPtr(c), req.SchoolStudentCode, req.Note)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonUpdated(c, "Mutasi disetujui", fiber.Map{
+		"transfer":       dto.FromModel(m, schoolID),
+		"school_student": studentDTO.FromModel(c, st),
+	})
+}
+
+// POST /api/a/student-transfers/:id/reject
+func (h *StudentTransferController) Reject(c *fiber.Ctx) error {
+	schoolID, err := resolveAdminSchool(c)
+	if err != nil {
+		return err
+	}
+	id, err := parseID(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	req, err := h.parseDecision(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+
+	m, err := svc.Reject(c.Context(), h.DB, id, schoolID, userIDPtr(c), req.Note)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonUpdated(c, "Mutasi ditolak", dto.FromModel(m, schoolID))
+}
+
+// POST /api/a/student-transfers/:id/cancel
+func (h *StudentTransferController) Cancel(c *fiber.Ctx) error {
+	schoolID, err := resolveAdminSchool(c)
+	if err != nil {
+		return err
+	}
+	id, err := parseID(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	req, err := h.parseDecision(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+
+	m, err := svc.Cancel(c.Context(), h.DB, id, schoolID, userIDPtr(c), req.Note)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonUpdated(c, "Mutasi dibatalkan", dto.FromModel(m, schoolID))
+}
@@ -0,0 +1,119 @@
+// file: internals/features/lembaga/school_yayasans/student_transfers/dto/student_transfers_dto.go
+package dto
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	model "madinahsalam_backend/internals/features/lembaga/school_yayasans/student_transfers/model"
+	svc "madinahsalam_backend/internals/features/lembaga/school_yayasans/student_transfers/service"
+)
+
+/* =========================================================
+   REQUESTS
+========================================================= */
+
+// POST /api/a/student-transfers (school asal)
+type CreateStudentTransferRequest struct {
+	SchoolStudentID     uuid.UUID `json:"school_student_id" validate:"required"`
+	DestinationSchoolID uuid.UUID `json:"destination_school_id" validate:"required"`
+	Reason              *string   `json:"reason" validate:"omitempty,max=1000"`
+}
+
+func (r *CreateStudentTransferRequest) Normalize() {
+	r.Reason = trimPtr(r.Reason)
+}
+
+// POST /api/a/student-transfers/:id/approve|reject|cancel
+type DecideStudentTransferRequest struct {
+	SchoolStudentCode *string `json:"school_student_code" validate:"omitempty,max=50"` // approve saja
+	Note              *string `json:"note" validate:"omitempty,max=1000"`
+}
+
+func (r *DecideStudentTransferRequest) Normalize() {
+	r.SchoolStudentCode = trimPtr(r.SchoolStudentCode)
+	r.Note = trimPtr(r.Note)
+}
+
+func trimPtr(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	v := strings.TrimSpace(*s)
+	if v == "" {
+		return nil
+	}
+	return &v
+}
+
+/* =========================================================
+   RESPONSES
+========================================================= */
+
+type StudentTransferResponse struct {
+	StudentTransferID                         uuid.UUID  `json:"student_transfer_id"`
+	StudentTransferOriginSchoolID             uuid.UUID  `json:"student_transfer_origin_school_id"`
+	StudentTransferDestinationSchoolID        uuid.UUID  `json:"student_transfer_destination_school_id"`
+	StudentTransferDestinationSchoolStudentID *uuid.UUID `json:"student_transfer_destination_school_student_id,omitempty"`
+	StudentTransferUserProfileID              uuid.UUID  `json:"student_transfer_user_profile_id"`
+	StudentTransferStudentNameSnapshot        *string    `json:"student_transfer_student_name_snapshot,omitempty"`
+	StudentTransferStatus                     string     `json:"student_transfer_status"`
+	StudentTransferReason                     *string    `json:"student_transfer_reason,omitempty"`
+	StudentTransferDecisionNote               *string    `json:"student_transfer_decision_note,omitempty"`
+	StudentTransferDecidedAt                  *time.Time `json:"student_transfer_decided_at,omitempty"`
+	StudentTransferCreatedAt                  time.Time  `json:"student_transfer_created_at"`
+
+	// hanya diisi untuk sisi asal (id internal school asal)
+	StudentTransferOriginSchoolStudentID *uuid.UUID `json:"student_transfer_origin_school_student_id,omitempty"`
+}
+
+// FromModel: id siswa di school asal hanya ditampilkan ke school asal.
+func FromModel(m *model.StudentTransferModel, viewerSchoolID uuid.UUID) StudentTransferResponse {
+	out := StudentTransferResponse{
+		StudentTransferID:                         m.StudentTransferID,
+		StudentTransferOriginSchoolID:             m.StudentTransferOriginSchoolID,
+		StudentTransferDestinationSchoolID:        m.StudentTransferDestinationSchoolID,
+		StudentTransferDestinationSchoolStudentID: m.StudentTransferDestinationSchoolStudentID,
+		StudentTransferUserProfileID:              m.StudentTransferUserProfileID,
+		StudentTransferStudentNameSnapshot:        m.StudentTransferStudentNameSnapshot,
+		StudentTransferStatus:                     string(m.StudentTransferStatus),
+		StudentTransferReason:                     m.StudentTransferReason,
+		StudentTransferDecisionNote:               m.StudentTransferDecisionNote,
+		StudentTransferDecidedAt:                  m.StudentTransferDecidedAt,
+		StudentTransferCreatedAt:                  m.StudentTransferCreatedAt,
+	}
+	if viewerSchoolID == m.StudentTransferOriginSchoolID {
+		id := m.StudentTransferOriginSchoolStudentID
+		out.StudentTransferOriginSchoolStudentID = &id
+	}
+	return out
+}
+
+func FromModels(rows []model.StudentTransferModel, viewerSchoolID uuid.UUID) []StudentTransferResponse {
+	out := make([]StudentTransferResponse, 0, len(rows))
+	for i := range rows {
+		out = append(out, FromModel(&rows[i], viewerSchoolID))
+	}
+	return out
+}
+
+type TranscriptResponse struct {
+	Transfer   StudentTransferResponse `json:"transfer"`
+	Transcript *svc.TranscriptBundle   `json:"transcript"`
+}
+
+func FromTranscript(m *model.StudentTransferModel, viewerSchoolID uuid.UUID) (TranscriptResponse, error) {
+	out := TranscriptResponse{Transfer: FromModel(m, viewerSchoolID)}
+	if len(m.StudentTransferTranscript) == 0 {
+		return out, nil
+	}
+	var b svc.TranscriptBundle
+	if err := json.Unmarshal(m.StudentTransferTranscript, &b); err != nil {
+		return out, err
+	}
+	out.Transcript = &b
+	return out, nil
+}
@@ -0,0 +1,52 @@
+// file: internals/features/lembaga/school_yayasans/student_transfers/model/student_transfers_model.go
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+type StudentTransferStatus string
+
+const (
+	StudentTransferPending  StudentTransferStatus = "pending"
+	StudentTransferApproved StudentTransferStatus = "approved"
+	StudentTransferRejected StudentTransferStatus = "rejected"
+	StudentTransferCanceled StudentTransferStatus = "canceled"
+)
+
+type StudentTransferModel struct {
+	StudentTransferID uuid.UUID `gorm:"column:student_transfer_id;type:uuid;default:gen_random_uuid();primaryKey" json:"student_transfer_id"`
+
+	// Asal
+	StudentTransferOriginSchoolID        uuid.UUID `gorm:"column:student_transfer_origin_school_id;type:uuid;not null" json:"student_transfer_origin_school_id"`
+	StudentTransferOriginSchoolStudentID uuid.UUID `gorm:"column:student_transfer_origin_school_student_id;type:uuid;not null" json:"student_transfer_origin_school_student_id"`
+
+	// Tujuan
+	StudentTransferDestinationSchoolID        uuid.UUID  `gorm:"column:student_transfer_destination_school_id;type:uuid;not null" json:"student_transfer_destination_school_id"`
+	StudentTransferDestinationSchoolStudentID *uuid.UUID `gorm:"column:student_transfer_destination_school_student_id;type:uuid" json:"student_transfer_destination_school_student_id,omitempty"`
+
+	// Identitas siswa
+	StudentTransferUserProfileID       uuid.UUID `gorm:"column:student_transfer_user_profile_id;type:uuid;not null" json:"student_transfer_user_profile_id"`
+	StudentTransferStudentNameSnapshot *string   `gorm:"column:student_transfer_student_name_snapshot;type:varchar(80)" json:"student_transfer_student_name_snapshot,omitempty"`
+
+	StudentTransferStatus       StudentTransferStatus `gorm:"column:student_transfer_status;type:student_transfer_status_enum;not null;default:'pending'" json:"student_transfer_status"`
+	StudentTransferReason       *string               `gorm:"column:student_transfer_reason;type:text" json:"student_transfer_reason,omitempty"`
+	StudentTransferDecisionNote *string               `gorm:"column:student_transfer_decision_note;type:text" json:"student_transfer_decision_note,omitempty"`
+
+	// Snapshot transcript bundle (lihat service.TranscriptBundle)
+	StudentTransferTranscript datatypes.JSON `gorm:"column:student_transfer_transcript;type:jsonb;not null;default:'{}'" json:"-"`
+
+	StudentTransferRequestedByUserID *uuid.UUID `gorm:"column:student_transfer_requested_by_user_id;type:uuid" json:"student_transfer_requested_by_user_id,omitempty"`
+	StudentTransferDecidedByUserID   *uuid.UUID `gorm:"column:student_transfer_decided_by_user_id;type:uuid" json:"student_transfer_decided_by_user_id,omitempty"`
+	StudentTransferDecidedAt         *time.Time `gorm:"column:student_transfer_decided_at" json:"student_transfer_decided_at,omitempty"`
+
+	StudentTransferCreatedAt time.Time      `gorm:"column:student_transfer_created_at;autoCreateTime" json:"student_transfer_created_at"`
+	StudentTransferUpdatedAt time.Time      `gorm:"column:student_transfer_updated_at;autoUpdateTime" json:"student_transfer_updated_at"`
+	StudentTransferDeletedAt gorm.DeletedAt `gorm:"column:student_transfer_deleted_at;index" json:"student_transfer_deleted_at,omitempty"`
+}
+
+func (StudentTransferModel) TableName() string { return "student_transfers" }
@@ -0,0 +1,32 @@
+package route
+
+import (
+	"madinahsalam_backend/internals/constants"
+	transferController "madinahsalam_backend/internals/features/lembaga/school_yayasans/student_transfers/controller"
+	authMiddleware "madinahsalam_backend/internals/middlewares/auth"
+	schoolkuMiddleware "madinahsalam_backend/internals/middlewares/features"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// /api/a/student-transfers → DKM + Admin + Owner (school asal / tujuan dari token)
+func StudentTransferAdminRoutes(api fiber.Router, db *gorm.DB) {
+	ctl := transferController.NewStudentTransferController(db)
+
+	g := api.Group("/student-transfers",
+		authMiddleware.OnlyRolesSlice(
+			constants.RoleErrorAdmin("mengelola mutasi siswa"),
+			constants.AdminAndAbove,
+		),
+		schoolkuMiddleware.IsSchoolAdmin(),
+	)
+
+	g.Post("/", ctl.Create)
+	g.Get("/", ctl.List)
+	g.Get("/:id", ctl.Detail)
+	g.Get("/:id/transcript", ctl.Transcript)
+	g.Post("/:id/approve", ctl.Approve)
+	g.Post("/:id/reject", ctl.Reject)
+	g.Post("/:id/cancel", ctl.Cancel)
+}
@@ -0,0 +1,493 @@
+// file: internals/features/lembaga/school_yayasans/student_transfers/service/student_transfers_service.go
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	model "madinahsalam_backend/internals/features/lembaga/school_yayasans/student_transfers/model"
+	studentModel "madinahsalam_backend/internals/features/lembaga/school_yayasans/teachers_students/model"
+	sectionModel "madinahsalam_backend/internals/features/school/classes/class_sections/model"
+	membership "madinahsalam_backend/internals/features/school/classes/classes/service"
+	helper "madinahsalam_backend/internals/helpers"
+)
+
+var (
+	ErrTransferNotFound      = errors.New("transfer tidak ditemukan")
+	ErrTransferNotPending    = errors.New("transfer sudah diproses")
+	ErrStudentNotFound       = errors.New("siswa tidak ditemukan di school asal")
+	ErrStudentNotActive      = errors.New("hanya siswa aktif yang bisa dipindahkan")
+	ErrDestinationNotFound   = errors.New("school tujuan tidak ditemukan / tidak aktif")
+	ErrSameSchool            = errors.New("school tujuan sama dengan school asal")
+	ErrAlreadyActiveInTarget = errors.New("siswa sudah aktif di school tujuan")
+)
+
+/* =========================================================
+   Transcript bundle — HANYA data milik siswa yang dipindah
+========================================================= */
+
+type TranscriptSubject struct {
+	SubjectName    *string        `json:"subject_name,omitempty" gorm:"column:subject_name"`
+	SubjectCode    *string        `json:"subject_code,omitempty" gorm:"column:subject_code"`
+	AcademicYear   *string        `json:"academic_year,omitempty" gorm:"column:academic_year"`
+	TermName       *string        `json:"term_name,omitempty" gorm:"column:term_name"`
+	FinalScore     *float64       `json:"final_score,omitempty" gorm:"column:final_score"`
+	PassThreshold  float64        `json:"pass_threshold" gorm:"column:pass_threshold"`
+	Passed         bool           `json:"passed" gorm:"column:passed"`
+	Breakdown      datatypes.JSON `json:"breakdown,omitempty" gorm:"column:breakdown"`
+	LastAssessedAt *time.Time     `json:"last_assessed_at,omitempty" gorm:"column:last_assessed_at"`
+}
+
+type TranscriptAttendance struct {
+	Total    int64   `json:"total" gorm:"column:total"`
+	Present  int64   `json:"present" gorm:"column:present"`
+	Late     int64   `json:"late" gorm:"column:late"`
+	Absent   int64   `json:"absent" gorm:"column:absent"`
+	Excused  int64   `json:"excused" gorm:"column:excused"`
+	Sick     int64   `json:"sick" gorm:"column:sick"`
+	Leave    int64   `json:"leave" gorm:"column:leave"`
+	Unmarked int64   `json:"unmarked" gorm:"column:unmarked"`
+	Rate     float64 `json:"rate" gorm:"-"`
+}
+
+type TranscriptBalanceItem struct {
+	Title     *string   `json:"title,omitempty" gorm:"column:title"`
+	Category  *string   `json:"category,omitempty" gorm:"column:category"`
+	BillCode  *string   `json:"bill_code,omitempty" gorm:"column:bill_code"`
+	AmountIDR int64     `json:"amount_idr" gorm:"column:amount_idr"`
+	CreatedAt time.Time `json:"created_at" gorm:"column:created_at"`
+}
+
+type TranscriptBundle struct {
+	OriginSchoolName string                  `json:"origin_school_name"`
+	StudentName      *string                 `json:"student_name,omitempty"`
+	JoinedAt         *time.Time              `json:"joined_at,omitempty"`
+	LeftAt           *time.Time              `json:"left_at,omitempty"`
+	Subjects         []TranscriptSubject     `json:"subjects"`
+	Attendance       TranscriptAttendance    `json:"attendance"`
+	Outstanding      []TranscriptBalanceItem `json:"outstanding"`
+	OutstandingIDR   int64                   `json:"outstanding_idr"`
+	GeneratedAt      time.Time               `json:"generated_at"`
+}
+
+// BuildTranscriptBundle merangkum data siswa di school asal. Semua query dikunci
+// ke (school asal, school_student asal) supaya tidak ada data lain yang ikut terbawa.
+// Isi sengaja terbatas: nilai mapel, agregat presensi, tagihan belum lunas. Data modul
+// lain (mis. setoran tahfidz) tidak dibawa; sekolah tujuan mencatat ulang dari awal.
+func BuildTranscriptBundle(tx *gorm.DB, st *studentModel.SchoolStudentModel, now time.Time) (*TranscriptBundle, error) {
+	b := &TranscriptBundle{
+		StudentName: st.SchoolStudentUserProfileNameCache,
+		JoinedAt:    st.SchoolStudentJoinedAt,
+		LeftAt:      st.SchoolStudentLeftAt,
+		Subjects:    make([]TranscriptSubject, 0),
+		Outstanding: make([]TranscriptBalanceItem, 0),
+		GeneratedAt: now,
+	}
+
+	if err := tx.Raw(`SELECT school_name FROM schools WHERE school_id = ?`, st.SchoolStudentSchoolID).
+		Scan(&b.OriginSchoolName).Error; err != nil {
+		return nil, err
+	}
+
+	if err := tx.Raw(`
+		SELECT cs.class_subject_subject_name_cache AS subject_name,
+		       cs.class_subject_subject_code_cache AS subject_code,
+		       t.academic_term_academic_year       AS academic_year,
+		       t.academic_term_name                AS term_name,
+		       s.user_subject_summary_final_score     AS final_score,
+		       s.user_subject_summary_pass_threshold  AS pass_threshold,
+		       s.user_subject_summary_passed          AS passed,
+		       s.user_subject_summary_breakdown       AS breakdown,
+		       s.user_subject_summary_last_assessed_at AS last_assessed_at
+		  FROM user_subject_summary s
+		  LEFT JOIN class_subjects cs
+		    ON cs.class_subject_id = s.user_subject_summary_class_subjects_id
+		   AND cs.class_subject_school_id = s.user_subject_summary_school_id
+		  LEFT JOIN academic_terms t
+		    ON t.academic_term_id = s.user_subject_summary_term_id
+		   AND t.academic_term_school_id = s.user_subject_summary_school_id
+		 WHERE s.user_subject_summary_school_id = ?
+		   AND s.user_subject_summary_school_student_id = ?
+		   AND s.user_subject_summary_deleted_at IS NULL
+		 ORDER BY t.academic_term_academic_year NULLS LAST, t.academic_term_name, cs.class_subject_order_index
+	`, st.SchoolStudentSchoolID, st.SchoolStudentID).Scan(&b.Subjects).Error; err != nil {
+		return nil, err
+	}
+
+	if err := tx.Raw(`
+		SELECT COUNT(*) AS total,
+		       COUNT(*) FILTER (WHERE class_attendance_session_participant_state = 'present')  AS present,
+		       COUNT(*) FILTER (WHERE class_attendance_session_participant_state = 'late')     AS late,
+		       COUNT(*) FILTER (WHERE class_attendance_session_participant_state = 'absent')   AS absent,
+		       COUNT(*) FILTER (WHERE class_attendance_session_participant_state = 'excused')  AS excused,
+		       COUNT(*) FILTER (WHERE class_attendance_session_participant_state = 'sick')     AS sick,
+		       COUNT(*) FILTER (WHERE class_attendance_session_participant_state = 'leave')    AS leave,
+		       COUNT(*) FILTER (WHERE class_attendance_session_participant_state = 'unmarked') AS unmarked
+		  FROM class_attendance_session_participants
+		 WHERE class_attendance_session_participant_school_id = ?
+		   AND class_attendance_session_participant_school_student_id = ?
+		   AND class_attendance_session_participant_deleted_at IS NULL
+	`, st.SchoolStudentSchoolID, st.SchoolStudentID).Scan(&b.Attendance).Error; err != nil {
+		return nil, err
+	}
+	if marked := b.Attendance.Total - b.Attendance.Unmarked; marked > 0 {
+		b.Attendance.Rate = float64(b.Attendance.Present+b.Attendance.Late) / float64(marked)
+	}
+
+	if err := tx.Raw(`
+		SELECT user_general_billing_title_snapshot        AS title,
+		       user_general_billing_category_snapshot::text AS category,
+		       user_general_billing_bill_code_snapshot    AS bill_code,
+		       user_general_billing_amount_idr            AS amount_idr,
+		       user_general_billing_created_at            AS created_at
+		  FROM user_general_billings
+		 WHERE user_general_billing_school_id = ?
+		   AND user_general_billing_school_student_id = ?
+		   AND user_general_billing_status = 'unpaid'
+		   AND user_general_billing_deleted_at IS NULL
+		 ORDER BY user_general_billing_created_at ASC
+	`, st.SchoolStudentSchoolID, st.SchoolStudentID).Scan(&b.Outstanding).Error; err != nil {
+		return nil, err
+	}
+	for _, it := range b.Outstanding {
+		b.OutstandingIDR += it.AmountIDR
+	}
+
+	return b, nil
+}
+
+func marshalBundle(b *TranscriptBundle) (datatypes.JSON, error) {
+	raw, err := json.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+	return datatypes.JSON(raw), nil
+}
+
+/* =========================================================
+   Request (school asal)
+========================================================= */
+
+func CreateTransfer(
+	ctx context.Context,
+	db *gorm.DB,
+	originSchoolID, schoolStudentID, destinationSchoolID uuid.UUID,
+	reason *string,
+	requestedBy *uuid.UUID,
+) (*model.StudentTransferModel, error) {
+	if originSchoolID == destinationSchoolID {
+		return nil, ErrSameSchool
+	}
+
+	var out *model.StudentTransferModel
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var st studentModel.SchoolStudentModel
+		if err := tx.Where("school_student_id = ? AND school_student_school_id = ?", schoolStudentID, originSchoolID).
+			First(&st).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrStudentNotFound
+			}
+			return err
+		}
+		if st.SchoolStudentStatus != studentModel.SchoolStudentActive {
+			return ErrStudentNotActive
+		}
+
+		var n int64
+		if err := tx.Table("schools").
+			Where("school_id = ? AND school_is_active = TRUE AND school_deleted_at IS NULL", destinationSchoolID).
+			Count(&n).Error; err != nil {
+			return err
+		}
+		if n == 0 {
+			return ErrDestinationNotFound
+		}
+
+		now := time.Now()
+		bundle, err := BuildTranscriptBundle(tx, &st, now)
+		if err != nil {
+			return err
+		}
+		raw, err := marshalBundle(bundle)
+		if err != nil {
+			return err
+		}
+
+		m := &model.StudentTransferModel{
+			StudentTransferOriginSchoolID:        originSchoolID,
+			StudentTransferOriginSchoolStudentID: st.SchoolStudentID,
+			StudentTransferDestinationSchoolID:   destinationSchoolID,
+			StudentTransferUserProfileID:         st.SchoolStudentUserProfileID,
+			StudentTransferStudentNameSnapshot:   st.SchoolStudentUserProfileNameCache,
+			StudentTransferStatus:                model.StudentTransferPending,
+			StudentTransferReason:                reason,
+			StudentTransferTranscript:            raw,
+			StudentTransferRequestedByUserID:     requestedBy,
+		}
+		if err := tx.Create(m).Error; err != nil {
+			if strings.Contains(strings.ToLower(err.Error()), "ux_st_origin_student_pending") {
+				return ErrTransferNotPending
+			}
+			return err
+		}
+		out = m
+		return nil
+	})
+	return out, err
+}
+
+/* =========================================================
+   Decide (school tujuan) / cancel (school asal)
+========================================================= */
+
+func lockTransfer(tx *gorm.DB, id uuid.UUID, where string, schoolID uuid.UUID) (*model.StudentTransferModel, error) {
+	var m model.StudentTransferModel
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("student_transfer_id = ?", id).
+		Where(where, schoolID).
+		First(&m).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTransferNotFound
+		}
+		return nil, err
+	}
+	if m.StudentTransferStatus != model.StudentTransferPending {
+		return nil, ErrTransferNotPending
+	}
+	return &m, nil
+}
+
+func userIDOfProfile(tx *gorm.DB, profileID uuid.UUID) (uuid.UUID, error) {
+	var uid uuid.UUID
+	err := tx.Raw(`SELECT user_profile_user_id FROM user_profiles WHERE user_profile_id = ?`, profileID).
+		Scan(&uid).Error
+	return uid, err
+}
+
+// Approve: tutup siswa (+ enrolment rombel) di asal, buat/aktifkan di tujuan, bekukan ulang transcript.
+func Approve(
+	ctx context.Context,
+	db *gorm.DB,
+	transferID, destinationSchoolID uuid.UUID,
+	decidedBy *uuid.UUID,
+	studentCode, note *string,
+) (*model.StudentTransferModel, *studentModel.SchoolStudentModel, error) {
+	var (
+		out  *model.StudentTransferModel
+		dest *studentModel.SchoolStudentModel
+	)
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		m, err := lockTransfer(tx, transferID, "student_transfer_destination_school_id = ?", destinationSchoolID)
+		if err != nil {
+			return err
+		}
+
+		var origin studentModel.SchoolStudentModel
+		if err := tx.Where("school_student_id = ? AND school_student_school_id = ?",
+			m.StudentTransferOriginSchoolStudentID, m.StudentTransferOriginSchoolID).
+			First(&origin).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrStudentNotFound
+			}
+			return err
+		}
+
+		now := time.Now()
+
+		// 1) Tutup di asal
+		reasonNote := "Mutasi keluar"
+		if m.StudentTransferReason != nil {
+			reasonNote += ": " + *m.StudentTransferReason
+		}
+		if origin.SchoolStudentNote != nil && strings.TrimSpace(*origin.SchoolStudentNote) != "" {
+			reasonNote = strings.TrimSpace(*origin.SchoolStudentNote) + "\n" + reasonNote
+		}
+		origin.SchoolStudentStatus = studentModel.SchoolStudentInactive
+		origin.SchoolStudentLeftAt = &now
+		origin.SchoolStudentNote = &reasonNote
+		if err := tx.Model(&origin).Updates(map[string]any{
+			"school_student_status":  origin.SchoolStudentStatus,
+			"school_student_left_at": now,
+			"school_student_note":    reasonNote,
+		}).Error; err != nil {
+			return err
+		}
+		// Enrolment rombel aktif di asal ikut ditutup (riwayat & nilai tetap utuh)
+		if err := tx.Model(&sectionModel.StudentClassSection{}).
+			Where("student_class_section_school_id = ? AND student_class_section_school_student_id = ? AND student_class_section_status = ?",
+				origin.SchoolStudentSchoolID, origin.SchoolStudentID, sectionModel.StudentClassSectionActive).
+			Updates(map[string]any{
+				"student_class_section_status":        sectionModel.StudentClassSectionInactive,
+				"student_class_section_unassigned_at": now,
+			}).Error; err != nil {
+			return err
+		}
+
+		// Snapshot final (sesudah left_at terisi)
+		bundle, err := BuildTranscriptBundle(tx, &origin, now)
+		if err != nil {
+			return err
+		}
+		raw, err := marshalBundle(bundle)
+		if err != nil {
+			return err
+		}
+
+		// 2) Buat / aktifkan di tujuan
+		var existing studentModel.SchoolStudentModel
+		err = tx.Where("school_student_school_id = ? AND school_student_user_profile_id = ?",
+			destinationSchoolID, m.StudentTransferUserProfileID).
+			Order("school_student_created_at DESC").
+			First(&existing).Error
+		switch {
+		case err == nil:
+			if existing.SchoolStudentStatus == studentModel.SchoolStudentActive {
+				return ErrAlreadyActiveInTarget
+			}
+			upd := map[string]any{
+				"school_student_status":               studentModel.SchoolStudentActive,
+				"school_student_joined_at":            now,
+				"school_student_left_at":              nil,
+				"school_student_needs_class_sections": true,
+			}
+			if studentCode != nil {
+				upd["school_student_code"] = *studentCode
+			}
+			if err := tx.Model(&existing).Updates(upd).Error; err != nil {
+				return err
+			}
+			if err := tx.First(&existing, "school_student_id = ?", existing.SchoolStudentID).Error; err != nil {
+				return err
+			}
+			dest = &existing
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			base := origin.SchoolStudentSlug
+			if base == "" && origin.SchoolStudentUserProfileNameCache != nil {
+				base = helper.SuggestSlugFromName(*origin.SchoolStudentUserProfileNameCache)
+			}
+			slug, err := helper.EnsureUniqueSlugCI(ctx, tx, "school_students", "school_student_slug", base,
+				func(q *gorm.DB) *gorm.DB {
+					return q.Where("school_student_school_id = ? AND school_student_deleted_at IS NULL", destinationSchoolID)
+				}, 50)
+			if err != nil {
+				return err
+			}
+			joined := now
+			dest = &studentModel.SchoolStudentModel{
+				SchoolStudentSchoolID:           destinationSchoolID,
+				SchoolStudentUserProfileID:      m.StudentTransferUserProfileID,
+				SchoolStudentSlug:               slug,
+				SchoolStudentCode:               studentCode,
+				SchoolStudentStatus:             studentModel.SchoolStudentActive,
+				SchoolStudentJoinedAt:           &joined,
+				SchoolStudentNeedsClassSections: true,
+
+				SchoolStudentUserProfileNameCache:              origin.SchoolStudentUserProfileNameCache,
+				SchoolStudentUserProfileAvatarURLCache:         origin.SchoolStudentUserProfileAvatarURLCache,
+				SchoolStudentUserProfileWhatsappURLCache:       origin.SchoolStudentUserProfileWhatsappURLCache,
+				SchoolStudentUserProfileParentNameCache:        origin.SchoolStudentUserProfileParentNameCache,
+				SchoolStudentUserProfileParentWhatsappURLCache: origin.SchoolStudentUserProfileParentWhatsappURLCache,
+				SchoolStudentUserProfileGenderCache:            origin.SchoolStudentUserProfileGenderCache,
+			}
+			if err := tx.Create(dest).Error; err != nil {
+				return err
+			}
+		default:
+			return err
+		}
+
+		// 3) Role student pindah scope
+		if uid, err := userIDOfProfile(tx, m.StudentTransferUserProfileID); err == nil && uid != uuid.Nil {
+			svc := membership.New()
+			assignedBy := uuid.Nil
+			if decidedBy != nil {
+				assignedBy = *decidedBy
+			}
+			originID := m.StudentTransferOriginSchoolID
+			if err := svc.RevokeRole(tx, uid, "student", &originID); err != nil {
+				return err
+			}
+			if err := svc.GrantRole(tx, uid, "student", &destinationSchoolID, assignedBy); err != nil {
+				return err
+			}
+		}
+
+		// 4) Finalisasi transfer
+		destID := dest.SchoolStudentID
+		if err := tx.Model(m).Updates(map[string]any{
+			"student_transfer_status":                        model.StudentTransferApproved,
+			"student_transfer_destination_school_student_id": destID,
+			"student_transfer_decision_note":                 note,
+			"student_transfer_decided_by_user_id":            decidedBy,
+			"student_transfer_decided_at":                    now,
+			"student_transfer_transcript":                    raw,
+		}).Error; err != nil {
+			return err
+		}
+		m.StudentTransferStatus = model.StudentTransferApproved
+		m.StudentTransferDestinationSchoolStudentID = &destID
+		m.StudentTransferDecisionNote = note
+		m.StudentTransferDecidedByUserID = decidedBy
+		m.StudentTransferDecidedAt = &now
+		m.StudentTransferTranscript = raw
+		out = m
+		return nil
+	})
+	return out, dest, err
+}
+
+// Reject (school tujuan)
+func Reject(ctx context.Context, db *gorm.DB, transferID, destinationSchoolID uuid.UUID, decidedBy *uuid.UUID, note *string) (*model.StudentTransferModel, error) {
+	return finish(ctx, db, transferID, "student_transfer_destination_school_id = ?", destinationSchoolID,
+		model.StudentTransferRejected, decidedBy, note)
+}
+
+// Cancel (school asal, selama masih pending)
+func Cancel(ctx context.Context, db *gorm.DB, transferID, originSchoolID uuid.UUID, decidedBy *uuid.UUID, note *string) (*model.StudentTransferModel, error) {
+	return finish(ctx, db, transferID, "student_transfer_origin_school_id = ?", originSchoolID,
+		model.StudentTransferCanceled, decidedBy, note)
+}
+
+func finish(
+	ctx context.Context,
+	db *gorm.DB,
+	transferID uuid.UUID,
+	where string,
+	schoolID uuid.UUID,
+	status model.StudentTransferStatus,
+	decidedBy *uuid.UUID,
+	note *string,
+) (*model.StudentTransferModel, error) {
+	var out *model.StudentTransferModel
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		m, err := lockTransfer(tx, transferID, where, schoolID)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		if err := tx.Model(m).Updates(map[string]any{
+			"student_transfer_status":             status,
+			"student_transfer_decision_note":      note,
+			"student_transfer_decided_by_user_id": decidedBy,
+			"student_transfer_decided_at":         now,
+		}).Error; err != nil {
+			return err
+		}
+		m.StudentTransferStatus = status
+		m.StudentTransferDecisionNote = note
+		m.StudentTransferDecidedByUserID = decidedBy
+		m.StudentTransferDecidedAt = &now
+		out = m
+		return nil
+	})
+	return out, err
+}
@@ -0,0 +1,224 @@
+// file: internals/features/lembaga/school_yayasans/yayasans/controller/yayasan_dashboard_controller.go
+package controller
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	yDTO "madinahsalam_backend/internals/features/lembaga/school_yayasans/yayasans/dto"
+	yModel "madinahsalam_backend/internals/features/lembaga/school_yayasans/yayasans/model"
+	ySvc "madinahsalam_backend/internals/features/lembaga/school_yayasans/yayasans/service"
+	helper "madinahsalam_backend/internals/helpers"
+	helperAuth "madinahsalam_backend/internals/helpers/auth"
+)
+
+/*
+Dashboard konsolidasi yayasan (scope yayasan_admin):
+
+GET    /api/u/yayasans/mine
+GET    /api/u/yayasans/:yayasan_id/dashboard?from=YYYY-MM-DD&to=YYYY-MM-DD
+GET    /api/u/yayasans/:yayasan_id/teacher-assignments?unassigned=1&page=&per_page=
+
+Owner:
+GET    /api/o/yayasans/:yayasan_id/admins
+POST   /api/o/yayasans/:yayasan_id/admins          {user_id}
+DELETE /api/o/yayasans/:yayasan_id/admins/:user_id
+*/
+
+type YayasanDashboardController struct {
+	DB       *gorm.DB
+	Validate *validator.Validate
+}
+
+func NewYayasanDashboardController(db *gorm.DB) *YayasanDashboardController {
+	return &YayasanDashboardController{DB: db, Validate: validator.New()}
+}
+
+func parseYayasanParam(c *fiber.Ctx) (uuid.UUID, error) {
+	id, err := uuid.Parse(strings.TrimSpace(c.Params("yayasan_id")))
+	if err != nil {
+		return uuid.Nil, fiber.NewError(fiber.StatusBadRequest, "yayasan_id tidak valid")
+	}
+	return id, nil
+}
+
+// Periode default: 30 hari terakhir; `to` inklusif (tanggal).
+func parseDateRange(c *fiber.Ctx) (time.Time, time.Time, error) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	to := today.AddDate(0, 0, 1)
+	from := today.AddDate(0, 0, -29)
+
+	if s := strings.TrimSpace(c.Query("from")); s != "" {
+		t, err := time.ParseInLocation("2006-01-02", s, now.Location())
+		if err != nil {
+			return from, to, fiber.NewError(fiber.StatusBadRequest, "from harus YYYY-MM-DD")
+		}
+		from = t
+	}
+	if s := strings.TrimSpace(c.Query("to")); s != "" {
+		t, err := time.ParseInLocation("2006-01-02", s, now.Location())
+		if err != nil {
+			return from, to, fiber.NewError(fiber.StatusBadRequest, "to harus YYYY-MM-DD")
+		}
+		to = t.AddDate(0, 0, 1)
+	}
+	if !to.After(from) {
+		return from, to, fiber.NewError(fiber.StatusBadRequest, "Rentang tanggal tidak valid")
+	}
+	return from, to, nil
+}
+
+// GET /api/u/yayasans/mine — yayasan yang dipegang user (scope yayasan_admin)
+func (h *YayasanDashboardController) ListMine(c *fiber.Ctx) error {
+	uid, err := helperAuth.GetUserIDFromToken(c)
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusUnauthorized, "User tidak terautentik")
+	}
+
+	var rows []yModel.YayasanModel
+	if err := h.DB.WithContext(c.Context()).
+		Where(`yayasan_id IN (
+			SELECT yayasan_admin_yayasan_id FROM yayasan_admins
+			 WHERE yayasan_admin_user_id = ? AND yayasan_admin_deleted_at IS NULL
+		)`, uid).
+		Order("yayasan_name ASC").
+		Find(&rows).Error; err != nil {
+		return helper.JsonError(c, fiber.StatusInternalServerError, "Gagal mengambil yayasan")
+	}
+	return helper.JsonOK(c, "OK", rows)
+}
+
+// GET /api/u/yayasans/:yayasan_id/dashboard
+func (h *YayasanDashboardController) Dashboard(c *fiber.Ctx) error {
+	yid, err := parseYayasanParam(c)
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, err.Error())
+	}
+	from, to, err := parseDateRange(c)
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	out, err := ySvc.BuildDashboard(c.Context(), h.DB, yid, from, to)
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusInternalServerError, "Gagal menyusun dashboard yayasan")
+	}
+	return helper.JsonOK(c, "OK", out)
+}
+
+// GET /api/u/yayasans/:yayasan_id/teacher-assignments
+func (h *YayasanDashboardController) TeacherAssignments(c *fiber.Ctx) error {
+	yid, err := parseYayasanParam(c)
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, err.Error())
+	}
+	p := helper.ResolvePaging(c, 50, 200)
+	unassigned := c.QueryBool("unassigned", false)
+
+	rows, total, err := ySvc.ListTeacherAssignments(c.Context(), h.DB, yid, unassigned, p.Limit, p.Offset)
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusInternalServerError, "Gagal mengambil penugasan guru")
+	}
+	return helper.JsonList(c, "OK", rows, helper.BuildPaginationFromPage(total, p.Page, p.PerPage))
+}
+
+/* =========================
+   OWNER: kelola yayasan_admin
+========================= */
+
+// GET /api/o/yayasans/:yayasan_id/admins
+func (h *YayasanDashboardController) ListAdmins(c *fiber.Ctx) error {
+	yid, err := parseYayasanParam(c)
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, err.Error())
+	}
+	var rows []yModel.YayasanAdminModel
+	if err := h.DB.WithContext(c.Context()).
+		Where("yayasan_admin_yayasan_id = ?", yid).
+		Order("yayasan_admin_created_at ASC").
+		Find(&rows).Error; err != nil {
+		return helper.JsonError(c, fiber.StatusInternalServerError, "Gagal mengambil admin yayasan")
+	}
+	return helper.JsonOK(c, "OK", yDTO.FromYayasanAdminModels(rows))
+}
+
+// POST /api/o/yayasans/:yayasan_id/admins
+func (h *YayasanDashboardController) AssignAdmin(c *fiber.Ctx) error {
+	yid, err := parseYayasanParam(c)
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, err.Error())
+	}
+	var req yDTO.AssignYayasanAdminRequest
+	if err := c.BodyParser(&req); err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "Payload tidak valid")
+	}
+	if err := h.Validate.Struct(&req); err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	var y yModel.YayasanModel
+	if err := h.DB.WithContext(c.Context()).First(&y, "yayasan_id = ?", yid).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return helper.JsonError(c, fiber.StatusNotFound, "Yayasan tidak ditemukan")
+		}
+		return helper.JsonError(c, fiber.StatusInternalServerError, "Gagal mengambil yayasan")
+	}
+
+	// idempotent: kalau sudah ada baris hidup, kembalikan saja
+	var existing yModel.YayasanAdminModel
+	err = h.DB.WithContext(c.Context()).
+		Where("yayasan_admin_yayasan_id = ? AND yayasan_admin_user_id = ?", yid, req.UserID).
+		First(&existing).Error
+	if err == nil {
+		return helper.JsonOK(c, "Sudah terdaftar sebagai admin yayasan", yDTO.FromYayasanAdminModel(existing))
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return helper.JsonError(c, fiber.StatusInternalServerError, "Gagal memeriksa admin yayasan")
+	}
+
+	m := yModel.YayasanAdminModel{
+		YayasanAdminYayasanID: yid,
+		YayasanAdminUserID:    req.UserID,
+	}
+	if by, err := helperAuth.GetUserIDFromToken(c); err == nil {
+		m.YayasanAdminAssignedBy = &by
+	}
+	if err := h.DB.WithContext(c.Context()).Create(&m).Error; err != nil {
+		msg := strings.ToLower(err.Error())
+		if strings.Contains(msg, "foreign key") {
+			return helper.JsonError(c, fiber.StatusBadRequest, "User tidak ditemukan")
+		}
+		return helper.JsonError(c, fiber.StatusInternalServerError, "Gagal menambahkan admin yayasan")
+	}
+	return helper.JsonCreated(c, "Admin yayasan ditambahkan", yDTO.FromYayasanAdminModel(m))
+}
+
+// DELETE /api/o/yayasans/:yayasan_id/admins/:user_id
+func (h *YayasanDashboardController) RevokeAdmin(c *fiber.Ctx) error {
+	yid, err := parseYayasanParam(c)
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, err.Error())
+	}
+	uid, err := uuid.Parse(strings.TrimSpace(c.Params("user_id")))
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "user_id tidak valid")
+	}
+
+	res := h.DB.WithContext(c.Context()).
+		Where("yayasan_admin_yayasan_id = ? AND yayasan_admin_user_id = ?", yid, uid).
+		Delete(&yModel.YayasanAdminModel{})
+	if res.Error != nil {
+		return helper.JsonError(c, fiber.StatusInternalServerError, "Gagal mencabut admin yayasan")
+	}
+	if res.RowsAffected == 0 {
+		return helper.JsonError(c, fiber.StatusNotFound, "Admin yayasan tidak ditemukan")
+	}
+	return helper.JsonDeleted(c, "Admin yayasan dicabut", fiber.Map{"yayasan_id": yid, "user_id": uid})
+}
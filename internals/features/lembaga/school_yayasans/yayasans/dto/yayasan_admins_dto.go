@@ -0,0 +1,41 @@
+// internals/features/lembaga/school_yayasans/yayasans/dto/yayasan_admins_dto.go
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	model "madinahsalam_backend/internals/features/lembaga/school_yayasans/yayasans/model"
+)
+
+// POST /api/o/yayasans/:yayasan_id/admins
+type AssignYayasanAdminRequest struct {
+	UserID uuid.UUID `json:"user_id" validate:"required"`
+}
+
+type YayasanAdminResponse struct {
+	YayasanAdminID         uuid.UUID  `json:"yayasan_admin_id"`
+	YayasanAdminYayasanID  uuid.UUID  `json:"yayasan_admin_yayasan_id"`
+	YayasanAdminUserID     uuid.UUID  `json:"yayasan_admin_user_id"`
+	YayasanAdminAssignedBy *uuid.UUID `json:"yayasan_admin_assigned_by,omitempty"`
+	YayasanAdminCreatedAt  time.Time  `json:"yayasan_admin_created_at"`
+}
+
+func FromYayasanAdminModel(m model.YayasanAdminModel) YayasanAdminResponse {
+	return YayasanAdminResponse{
+		YayasanAdminID:         m.YayasanAdminID,
+		YayasanAdminYayasanID:  m.YayasanAdminYayasanID,
+		YayasanAdminUserID:     m.YayasanAdminUserID,
+		YayasanAdminAssignedBy: m.YayasanAdminAssignedBy,
+		YayasanAdminCreatedAt:  m.YayasanAdminCreatedAt,
+	}
+}
+
+func FromYayasanAdminModels(rows []model.YayasanAdminModel) []YayasanAdminResponse {
+	out := make([]YayasanAdminResponse, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, FromYayasanAdminModel(r))
+	}
+	return out
+}
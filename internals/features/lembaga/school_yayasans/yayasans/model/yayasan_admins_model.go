@@ -0,0 +1,23 @@
+// internals/features/lembaga/school_yayasans/yayasans/model/yayasan_admins_model.go
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Scope yayasan_admin: 1 user → semua school dengan school_yayasan_id = yayasan ini
+type YayasanAdminModel struct {
+	YayasanAdminID         uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey;column:yayasan_admin_id" json:"yayasan_admin_id"`
+	YayasanAdminYayasanID  uuid.UUID  `gorm:"type:uuid;not null;column:yayasan_admin_yayasan_id" json:"yayasan_admin_yayasan_id"`
+	YayasanAdminUserID     uuid.UUID  `gorm:"type:uuid;not null;column:yayasan_admin_user_id" json:"yayasan_admin_user_id"`
+	YayasanAdminAssignedBy *uuid.UUID `gorm:"type:uuid;column:yayasan_admin_assigned_by" json:"yayasan_admin_assigned_by,omitempty"`
+
+	YayasanAdminCreatedAt time.Time      `gorm:"column:yayasan_admin_created_at;autoCreateTime" json:"yayasan_admin_created_at"`
+	YayasanAdminUpdatedAt time.Time      `gorm:"column:yayasan_admin_updated_at;autoUpdateTime" json:"yayasan_admin_updated_at"`
+	YayasanAdminDeletedAt gorm.DeletedAt `gorm:"column:yayasan_admin_deleted_at;index" json:"yayasan_admin_deleted_at,omitempty"`
+}
+
+func (YayasanAdminModel) TableName() string { return "yayasan_admins" }
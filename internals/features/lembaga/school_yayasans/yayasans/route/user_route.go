@@ -0,0 +1,32 @@
+package route
+
+import (
+	ycontroller "madinahsalam_backend/internals/features/lembaga/school_yayasans/yayasans/controller"
+	featuresMiddleware "madinahsalam_backend/internals/middlewares/features"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// Scope yayasan_admin → /api/u/yayasans/...
+func YayasanUserRoutes(r fiber.Router, db *gorm.DB) {
+	ctl := ycontroller.NewYayasanDashboardController(db)
+	guard := featuresMiddleware.IsYayasanAdmin(db)
+
+	g := r.Group("/yayasans")
+
+	g.Get("/mine", ctl.ListMine)
+	g.Get("/:yayasan_id/dashboard", guard, ctl.Dashboard)
+	g.Get("/:yayasan_id/teacher-assignments", guard, ctl.TeacherAssignments)
+}
+
+// Owner: kelola admin yayasan → /api/o/yayasans/...
+func YayasanOwnerRoutes(r fiber.Router, db *gorm.DB) {
+	ctl := ycontroller.NewYayasanDashboardController(db)
+
+	g := r.Group("/yayasans/:yayasan_id/admins")
+
+	g.Get("/", ctl.ListAdmins)
+	g.Post("/", ctl.AssignAdmin)
+	g.Delete("/:user_id", ctl.RevokeAdmin)
+}
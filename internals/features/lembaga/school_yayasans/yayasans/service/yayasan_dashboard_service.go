@@ -0,0 +1,285 @@
+// internals/features/lembaga/school_yayasans/yayasans/service/yayasan_dashboard_service.go
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+/* =========================================================
+   Scope yayasan_admin
+========================================================= */
+
+// IsYayasanAdmin: user punya baris hidup di yayasan_admins untuk yayasan ini.
+func IsYayasanAdmin(ctx context.Context, db *gorm.DB, userID, yayasanID uuid.UUID) (bool, error) {
+	var n int64
+	err := db.WithContext(ctx).
+		Table("yayasan_admins").
+		Where("yayasan_admin_user_id = ? AND yayasan_admin_yayasan_id = ? AND yayasan_admin_deleted_at IS NULL", userID, yayasanID).
+		Count(&n).Error
+	return n > 0, err
+}
+
+// IsYayasanAdminOfSchool: user admin yayasan pemilik school ini (schools.school_yayasan_id).
+func IsYayasanAdminOfSchool(ctx context.Context, db *gorm.DB, userID, schoolID uuid.UUID) (bool, error) {
+	var n int64
+	err := db.WithContext(ctx).
+		Table("yayasan_admins ya").
+		Joins("JOIN schools s ON s.school_yayasan_id = ya.yayasan_admin_yayasan_id AND s.school_deleted_at IS NULL").
+		Where("ya.yayasan_admin_user_id = ? AND s.school_id = ? AND ya.yayasan_admin_deleted_at IS NULL", userID, schoolID).
+		Count(&n).Error
+	return n > 0, err
+}
+
+// SchoolIDsOfYayasan: semua school hidup di bawah yayasan.
+func SchoolIDsOfYayasan(ctx context.Context, db *gorm.DB, yayasanID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := db.WithContext(ctx).
+		Table("schools").
+		Where("school_yayasan_id = ? AND school_deleted_at IS NULL", yayasanID).
+		Order("school_name ASC").
+		Pluck("school_id", &ids).Error
+	return ids, err
+}
+
+/* =========================================================
+   Dashboard (konsolidasi per school + total)
+========================================================= */
+
+type SchoolSummary struct {
+	SchoolID       uuid.UUID `json:"school_id" gorm:"column:school_id"`
+	SchoolName     string    `json:"school_name" gorm:"column:school_name"`
+	SchoolSlug     string    `json:"school_slug" gorm:"column:school_slug"`
+	SchoolIsActive bool      `json:"school_is_active" gorm:"column:school_is_active"`
+
+	// lembaga_stats
+	ActiveClasses  int `json:"active_classes" gorm:"column:active_classes"`
+	ActiveSections int `json:"active_sections" gorm:"column:active_sections"`
+	ActiveStudents int `json:"active_students" gorm:"column:active_students"`
+	ActiveTeachers int `json:"active_teachers" gorm:"column:active_teachers"`
+
+	// kehadiran (periode)
+	AttendanceMarked  int64   `json:"attendance_marked" gorm:"column:attendance_marked"`
+	AttendancePresent int64   `json:"attendance_present" gorm:"column:attendance_present"`
+	AttendanceRate    float64 `json:"attendance_rate" gorm:"-"`
+
+	// keuangan
+	CollectedIDR    int64 `json:"collected_idr" gorm:"column:collected_idr"`
+	CollectedCount  int64 `json:"collected_count" gorm:"column:collected_count"`
+	ArrearsIDR      int64 `json:"arrears_idr" gorm:"column:arrears_idr"`
+	ArrearsCount    int64 `json:"arrears_count" gorm:"column:arrears_count"`
+	AssignmentCount int64 `json:"assignment_count" gorm:"column:assignment_count"`
+	AssignedTeacher int64 `json:"assigned_teachers" gorm:"column:assigned_teachers"`
+}
+
+type DashboardTotals struct {
+	Schools           int     `json:"schools"`
+	ActiveClasses     int     `json:"active_classes"`
+	ActiveSections    int     `json:"active_sections"`
+	ActiveStudents    int     `json:"active_students"`
+	ActiveTeachers    int     `json:"active_teachers"`
+	AttendanceMarked  int64   `json:"attendance_marked"`
+	AttendancePresent int64   `json:"attendance_present"`
+	AttendanceRate    float64 `json:"attendance_rate"`
+	CollectedIDR      int64   `json:"collected_idr"`
+	CollectedCount    int64   `json:"collected_count"`
+	ArrearsIDR        int64   `json:"arrears_idr"`
+	ArrearsCount      int64   `json:"arrears_count"`
+	AssignmentCount   int64   `json:"assignment_count"`
+	AssignedTeacher   int64   `json:"assigned_teachers"`
+}
+
+type Dashboard struct {
+	YayasanID uuid.UUID       `json:"yayasan_id"`
+	From      time.Time       `json:"from"`
+	To        time.Time       `json:"to"`
+	Totals    DashboardTotals `json:"totals"`
+	Schools   []SchoolSummary `json:"schools"`
+}
+
+func rate(num, den int64) float64 {
+	if den <= 0 {
+		return 0
+	}
+	return float64(num) / float64(den)
+}
+
+// BuildDashboard: 1 query agregat (CTE per domain) untuk semua school di yayasan.
+// Periode [from, to) berlaku untuk kehadiran & pemasukan; tunggakan = posisi saat ini.
+func BuildDashboard(ctx context.Context, db *gorm.DB, yayasanID uuid.UUID, from, to time.Time) (*Dashboard, error) {
+	rows := make([]SchoolSummary, 0)
+	err := db.WithContext(ctx).Raw(`
+		WITH s AS (
+			SELECT school_id, school_name, school_slug, school_is_active
+			  FROM schools
+			 WHERE school_yayasan_id = ?
+			   AND school_deleted_at IS NULL
+		),
+		att AS (
+			SELECT p.class_attendance_session_participant_school_id AS school_id,
+			       COUNT(*) FILTER (WHERE p.class_attendance_session_participant_state <> 'unmarked') AS marked,
+			       COUNT(*) FILTER (WHERE p.class_attendance_session_participant_state IN ('present','late')) AS present
+			  FROM class_attendance_session_participants p
+			  JOIN class_attendance_sessions cs
+			    ON cs.class_attendance_session_id = p.class_attendance_session_participant_session_id
+			   AND cs.class_attendance_session_deleted_at IS NULL
+			 WHERE p.class_attendance_session_participant_school_id IN (SELECT school_id FROM s)
+			   AND p.class_attendance_session_participant_deleted_at IS NULL
+			   AND cs.class_attendance_session_date >= ?::date
+			   AND cs.class_attendance_session_date <  ?::date
+			 GROUP BY 1
+		),
+		pay AS (
+			SELECT payment_school_id AS school_id,
+			       COALESCE(SUM(payment_amount_idr), 0) AS collected_idr,
+			       COUNT(*) AS collected_count
+			  FROM payments
+			 WHERE payment_school_id IN (SELECT school_id FROM s)
+			   AND payment_status = 'paid'
+			   AND payment_deleted_at IS NULL
+			   AND payment_paid_at >= ? AND payment_paid_at < ?
+			 GROUP BY 1
+		),
+		arr AS (
+			SELECT user_general_billing_school_id AS school_id,
+			       COALESCE(SUM(user_general_billing_amount_idr), 0) AS arrears_idr,
+			       COUNT(*) AS arrears_count
+			  FROM user_general_billings
+			 WHERE user_general_billing_school_id IN (SELECT school_id FROM s)
+			   AND user_general_billing_status = 'unpaid'
+			   AND user_general_billing_deleted_at IS NULL
+			 GROUP BY 1
+		),
+		asg AS (
+			SELECT csst_school_id AS school_id,
+			       COUNT(*) AS assignment_count,
+			       COUNT(DISTINCT csst_school_teacher_id) AS assigned_teachers
+			  FROM class_section_subject_teachers
+			 WHERE csst_school_id IN (SELECT school_id FROM s)
+			   AND csst_status = 'active'
+			   AND csst_deleted_at IS NULL
+			 GROUP BY 1
+		)
+		SELECT s.school_id, s.school_name, s.school_slug, s.school_is_active,
+		       COALESCE(ls.lembaga_stats_active_classes, 0)  AS active_classes,
+		       COALESCE(ls.lembaga_stats_active_sections, 0) AS active_sections,
+		       COALESCE(ls.lembaga_stats_active_students, 0) AS active_students,
+		       COALESCE(ls.lembaga_stats_active_teachers, 0) AS active_teachers,
+		       COALESCE(att.marked, 0)            AS attendance_marked,
+		       COALESCE(att.present, 0)           AS attendance_present,
+		       COALESCE(pay.collected_idr, 0)     AS collected_idr,
+		       COALESCE(pay.collected_count, 0)   AS collected_count,
+		       COALESCE(arr.arrears_idr, 0)       AS arrears_idr,
+		       COALESCE(arr.arrears_count, 0)     AS arrears_count,
+		       COALESCE(asg.assignment_count, 0)  AS assignment_count,
+		       COALESCE(asg.assigned_teachers, 0) AS assigned_teachers
+		  FROM s
+		  LEFT JOIN lembaga_stats ls ON ls.lembaga_stats_school_id = s.school_id
+		  LEFT JOIN att ON att.school_id = s.school_id
+		  LEFT JOIN pay ON pay.school_id = s.school_id
+		  LEFT JOIN arr ON arr.school_id = s.school_id
+		  LEFT JOIN asg ON asg.school_id = s.school_id
+		 ORDER BY s.school_name ASC
+	`, yayasanID, from, to, from, to).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	out := &Dashboard{YayasanID: yayasanID, From: from, To: to, Schools: rows}
+	t := &out.Totals
+	for i := range rows {
+		r := &rows[i]
+		r.AttendanceRate = rate(r.AttendancePresent, r.AttendanceMarked)
+
+		t.Schools++
+		t.ActiveClasses += r.ActiveClasses
+		t.ActiveSections += r.ActiveSections
+		t.ActiveStudents += r.ActiveStudents
+		t.ActiveTeachers += r.ActiveTeachers
+		t.AttendanceMarked += r.AttendanceMarked
+		t.AttendancePresent += r.AttendancePresent
+		t.CollectedIDR += r.CollectedIDR
+		t.CollectedCount += r.CollectedCount
+		t.ArrearsIDR += r.ArrearsIDR
+		t.ArrearsCount += r.ArrearsCount
+		t.AssignmentCount += r.AssignmentCount
+		t.AssignedTeacher += r.AssignedTeacher
+	}
+	t.AttendanceRate = rate(t.AttendancePresent, t.AttendanceMarked)
+	return out, nil
+}
+
+/* =========================================================
+   Penugasan guru lintas school
+========================================================= */
+
+type TeacherAssignment struct {
+	SchoolID          uuid.UUID  `json:"school_id" gorm:"column:school_id"`
+	SchoolName        string     `json:"school_name" gorm:"column:school_name"`
+	SchoolTeacherID   uuid.UUID  `json:"school_teacher_id" gorm:"column:school_teacher_id"`
+	UserTeacherID     *uuid.UUID `json:"user_teacher_id,omitempty" gorm:"column:user_teacher_id"`
+	TeacherName       *string    `json:"teacher_name,omitempty" gorm:"column:teacher_name"`
+	AssignmentCount   int64      `json:"assignment_count" gorm:"column:assignment_count"`
+	SectionCount      int64      `json:"section_count" gorm:"column:section_count"`
+	SchoolsForTeacher int64      `json:"schools_for_teacher" gorm:"column:schools_for_teacher"`
+}
+
+// ListTeacherAssignments: guru aktif per school + jumlah mapel-kelas yang dipegang.
+// schools_for_teacher > 1 → guru yang sama mengajar di beberapa school yayasan.
+func ListTeacherAssignments(ctx context.Context, db *gorm.DB, yayasanID uuid.UUID, onlyUnassigned bool, limit, offset int) ([]TeacherAssignment, int64, error) {
+	base := `
+		WITH s AS (
+			SELECT school_id, school_name
+			  FROM schools
+			 WHERE school_yayasan_id = ?
+			   AND school_deleted_at IS NULL
+		),
+		t AS (
+			SELECT st.school_teacher_id,
+			       st.school_teacher_school_id AS school_id,
+			       st.school_teacher_user_teacher_id AS user_teacher_id,
+			       st.school_teacher_user_teacher_full_name_cache AS teacher_name
+			  FROM school_teachers st
+			 WHERE st.school_teacher_school_id IN (SELECT school_id FROM s)
+			   AND st.school_teacher_is_active = TRUE
+			   AND st.school_teacher_deleted_at IS NULL
+		),
+		a AS (
+			SELECT csst_school_teacher_id AS school_teacher_id,
+			       COUNT(*) AS assignment_count,
+			       COUNT(DISTINCT csst_class_section_id) AS section_count
+			  FROM class_section_subject_teachers
+			 WHERE csst_school_id IN (SELECT school_id FROM s)
+			   AND csst_status = 'active'
+			   AND csst_deleted_at IS NULL
+			 GROUP BY 1
+		),
+		x AS (
+			SELECT t.school_id, s.school_name, t.school_teacher_id, t.user_teacher_id, t.teacher_name,
+			       COALESCE(a.assignment_count, 0) AS assignment_count,
+			       COALESCE(a.section_count, 0)    AS section_count,
+			       COUNT(*) OVER (PARTITION BY COALESCE(t.user_teacher_id, t.school_teacher_id)) AS schools_for_teacher
+			  FROM t
+			  JOIN s ON s.school_id = t.school_id
+			  LEFT JOIN a ON a.school_teacher_id = t.school_teacher_id
+		)
+	`
+	where := ""
+	if onlyUnassigned {
+		where = " WHERE assignment_count = 0"
+	}
+
+	var total int64
+	if err := db.WithContext(ctx).Raw(base+`SELECT COUNT(*) FROM x`+where, yayasanID).Scan(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	rows := make([]TeacherAssignment, 0)
+	err := db.WithContext(ctx).Raw(base+`SELECT * FROM x`+where+`
+		 ORDER BY teacher_name ASC NULLS LAST, school_name ASC
+		 LIMIT ? OFFSET ?`, yayasanID, limit, offset).Scan(&rows).Error
+	return rows, total, err
+}
@@ -0,0 +1,256 @@
+// file: internals/features/lembaga/webhooks/controller/webhooks_controller.go
+package controller
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	permsvc "madinahsalam_backend/internals/features/lembaga/permissions/service"
+	dto "madinahsalam_backend/internals/features/lembaga/webhooks/dto"
+	model "madinahsalam_backend/internals/features/lembaga/webhooks/model"
+	svc "madinahsalam_backend/internals/features/lembaga/webhooks/service"
+	helper "madinahsalam_backend/internals/helpers"
+	helperAuth "madinahsalam_backend/internals/helpers/auth"
+)
+
+/*
+Outbound webhook sekolah
+
+GET    /api/a/webhooks/events                       daftar event yang bisa dilanggan
+GET    /api/a/webhooks
+POST   /api/a/webhooks                              {"name","url","events":[...],"is_active"} → secret (sekali)
+GET    /api/a/webhooks/:id
+PATCH  /api/a/webhooks/:id
+DELETE /api/a/webhooks/:id
+POST   /api/a/webhooks/:id/rotate-secret            → secret baru (sekali)
+POST   /api/a/webhooks/:id/ping                     antre event webhook.ping
+
+GET    /api/a/webhooks/deliveries?subscription_id=&event=&status=
+GET    /api/a/webhooks/deliveries/:id               + log percobaan
+POST   /api/a/webhooks/deliveries/:id/redeliver
+*/
+
+type WebhookController struct {
+	DB *gorm.DB
+}
+
+func NewWebhookController(db *gorm.DB) *WebhookController {
+	return &WebhookController{DB: db}
+}
+
+func writeErr(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, svc.ErrSubscriptionNotFound), errors.Is(err, svc.ErrDeliveryNotFound):
+		return helper.JsonError(c, fiber.StatusNotFound, err.Error())
+	case errors.Is(err, svc.ErrDeliveryInFlight), errors.Is(err, svc.ErrTooManySubscriptions):
+		return helper.JsonError(c, fiber.StatusConflict, err.Error())
+	case errors.Is(err, svc.ErrInvalidURL), errors.Is(err, svc.ErrInvalidEvent), errors.Is(err, svc.ErrNameEmpty):
+		return helper.JsonError(c, fiber.StatusBadRequest, err.Error())
+	}
+	var fe *fiber.Error
+	if errors.As(err, &fe) {
+		return helper.JsonError(c, fe.Code, fe.Message)
+	}
+	return helper.JsonError(c, fiber.StatusInternalServerError, err.Error())
+}
+
+func parseID(c *fiber.Ctx) (uuid.UUID, error) {
+	id, err := uuid.Parse(strings.TrimSpace(c.Params("id")))
+	if err != nil {
+		return uuid.Nil, fiber.NewError(fiber.StatusBadRequest, "id tidak valid")
+	}
+	return id, nil
+}
+
+// scope: school aktif + :id (bila ada di route).
+func scope(c *fiber.Ctx) (uuid.UUID, uuid.UUID, error) {
+	schoolID, err := permsvc.SchoolFromRequest(c)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	if c.Params("id") == "" {
+		return schoolID, uuid.Nil, nil
+	}
+	id, err := parseID(c)
+	return schoolID, id, err
+}
+
+/* =========================================================
+   SUBSCRIPTION
+========================================================= */
+
+// GET /api/a/webhooks/events
+func (h *WebhookController) Events(c *fiber.Ctx) error {
+	return helper.JsonOK(c, "OK", fiber.Map{
+		"events":           model.SubscribableEvents,
+		"signature_header": "X-Webhook-Signature",
+		"signature_scheme": "sha256=hex(HMAC-SHA256(secret, X-Webhook-Timestamp + \".\" + body))",
+	})
+}
+
+// GET /api/a/webhooks
+func (h *WebhookController) List(c *fiber.Ctx) error {
+	schoolID, _, err := scope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	rows, err := svc.ListSubscriptions(c.Context(), h.DB, schoolID)
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusInternalServerError, "Gagal mengambil webhook")
+	}
+	return helper.JsonOK(c, "OK", dto.FromSubscriptions(rows))
+}
+
+// GET /api/a/webhooks/:id
+func (h *WebhookController) Detail(c *fiber.Ctx) error {
+	schoolID, id, err := scope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	m, err := svc.GetSubscription(c.Context(), h.DB, schoolID, id)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonOK(c, "OK", dto.FromSubscription(m, false))
+}
+
+// POST /api/a/webhooks
+func (h *WebhookController) Create(c *fiber.Ctx) error {
+	schoolID, _, err := scope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	var req dto.CreateSubscriptionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "Payload tidak valid")
+	}
+	var by *uuid.UUID
+	if uid, err := helperAuth.GetUserIDFromToken(c); err == nil && uid != uuid.Nil {
+		by = &uid
+	}
+	m, err := svc.CreateSubscription(c.Context(), h.DB, schoolID, req.ToInput(), by)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonCreated(c, "Webhook dibuat (simpan secret, tidak ditampilkan lagi)", dto.FromSubscription(m, true))
+}
+
+// PATCH /api/a/webhooks/:id
+func (h *WebhookController) Update(c *fiber.Ctx) error {
+	schoolID, id, err := scope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	var req dto.UpdateSubscriptionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "Payload tidak valid")
+	}
+	m, err := svc.UpdateSubscription(c.Context(), h.DB, schoolID, id, req.ToInput())
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonUpdated(c, "Webhook diperbarui", dto.FromSubscription(m, false))
+}
+
+// DELETE /api/a/webhooks/:id
+func (h *WebhookController) Delete(c *fiber.Ctx) error {
+	schoolID, id, err := scope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	if err := svc.DeleteSubscription(c.Context(), h.DB, schoolID, id); err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonDeleted(c, "Webhook dihapus", fiber.Map{"webhook_subscription_id": id})
+}
+
+// POST /api/a/webhooks/:id/rotate-secret
+func (h *WebhookController) RotateSecret(c *fiber.Ctx) error {
+	schoolID, id, err := scope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	m, err := svc.RotateSecret(c.Context(), h.DB, schoolID, id)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonUpdated(c, "Secret webhook diganti (simpan secret, tidak ditampilkan lagi)", dto.FromSubscription(m, true))
+}
+
+// POST /api/a/webhooks/:id/ping
+func (h *WebhookController) Ping(c *fiber.Ctx) error {
+	schoolID, id, err := scope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	d, err := svc.Ping(c.Context(), h.DB, schoolID, id)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"message": "Ping dijadwalkan",
+		"data":    d,
+	})
+}
+
+/* =========================================================
+   DELIVERY LOG
+========================================================= */
+
+// GET /api/a/webhooks/deliveries
+func (h *WebhookController) ListDeliveries(c *fiber.Ctx) error {
+	schoolID, _, err := scope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	f := svc.DeliveryFilter{
+		Event:  c.Query("event"),
+		Status: c.Query("status"),
+	}
+	if v := strings.TrimSpace(c.Query("subscription_id")); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return helper.JsonError(c, fiber.StatusBadRequest, "subscription_id tidak valid")
+		}
+		f.SubscriptionID = &id
+	}
+	p := helper.ResolvePaging(c, 50, 200)
+	rows, total, err := svc.ListDeliveries(c.Context(), h.DB, schoolID, f, p.Limit, p.Offset)
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusInternalServerError, "Gagal mengambil log webhook")
+	}
+	return helper.JsonList(c, "OK", rows, helper.BuildPaginationFromPage(total, p.Page, p.PerPage))
+}
+
+// GET /api/a/webhooks/deliveries/:id
+func (h *WebhookController) DeliveryDetail(c *fiber.Ctx) error {
+	schoolID, id, err := scope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	d, attempts, err := svc.GetDelivery(c.Context(), h.DB, schoolID, id)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonOK(c, "OK", dto.FromDeliveryDetail(d, attempts))
+}
+
+// POST /api/a/webhooks/deliveries/:id/redeliver
+func (h *WebhookController) Redeliver(c *fiber.Ctx) error {
+	schoolID, id, err := scope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	d, err := svc.Redeliver(c.Context(), h.DB, schoolID, id)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"message": "Redelivery dijadwalkan",
+		"data":    d,
+	})
+}
@@ -0,0 +1,109 @@
+// file: internals/features/lembaga/webhooks/dto/webhooks_dto.go
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	model "madinahsalam_backend/internals/features/lembaga/webhooks/model"
+	svc "madinahsalam_backend/internals/features/lembaga/webhooks/service"
+)
+
+/* =========================================================
+   REQUEST
+========================================================= */
+
+type CreateSubscriptionRequest struct {
+	Name     string   `json:"name"`
+	URL      string   `json:"url"`
+	Events   []string `json:"events"`
+	IsActive *bool    `json:"is_active"`
+}
+
+func (r CreateSubscriptionRequest) ToInput() svc.SubscriptionInput {
+	return svc.SubscriptionInput{
+		Name:      &r.Name,
+		URL:       &r.URL,
+		Events:    r.Events,
+		SetEvents: true,
+		IsActive:  r.IsActive,
+	}
+}
+
+// PATCH: field nil = tidak diubah; events (bila dikirim) mengganti seluruh filter.
+type UpdateSubscriptionRequest struct {
+	Name     *string   `json:"name"`
+	URL      *string   `json:"url"`
+	Events   *[]string `json:"events"`
+	IsActive *bool     `json:"is_active"`
+}
+
+func (r UpdateSubscriptionRequest) ToInput() svc.SubscriptionInput {
+	in := svc.SubscriptionInput{Name: r.Name, URL: r.URL, IsActive: r.IsActive}
+	if r.Events != nil {
+		in.Events, in.SetEvents = *r.Events, true
+	}
+	return in
+}
+
+/* =========================================================
+   RESPONSE
+========================================================= */
+
+type SubscriptionResponse struct {
+	WebhookSubscriptionID       uuid.UUID `json:"webhook_subscription_id"`
+	WebhookSubscriptionSchoolID uuid.UUID `json:"webhook_subscription_school_id"`
+	WebhookSubscriptionName     string    `json:"webhook_subscription_name"`
+	WebhookSubscriptionURL      string    `json:"webhook_subscription_url"`
+	WebhookSubscriptionEvents   []string  `json:"webhook_subscription_events"`
+	WebhookSubscriptionIsActive bool      `json:"webhook_subscription_is_active"`
+
+	// hanya terisi saat create / rotate-secret
+	WebhookSubscriptionSecret *string `json:"webhook_subscription_secret,omitempty"`
+
+	WebhookSubscriptionCreatedAt time.Time `json:"webhook_subscription_created_at"`
+	WebhookSubscriptionUpdatedAt time.Time `json:"webhook_subscription_updated_at"`
+}
+
+func FromSubscription(m *model.WebhookSubscriptionModel, withSecret bool) SubscriptionResponse {
+	events := []string(m.WebhookSubscriptionEvents)
+	if events == nil {
+		events = []string{}
+	}
+	out := SubscriptionResponse{
+		WebhookSubscriptionID:        m.WebhookSubscriptionID,
+		WebhookSubscriptionSchoolID:  m.WebhookSubscriptionSchoolID,
+		WebhookSubscriptionName:      m.WebhookSubscriptionName,
+		WebhookSubscriptionURL:       m.WebhookSubscriptionURL,
+		WebhookSubscriptionEvents:    events,
+		WebhookSubscriptionIsActive:  m.WebhookSubscriptionIsActive,
+		WebhookSubscriptionCreatedAt: m.WebhookSubscriptionCreatedAt,
+		WebhookSubscriptionUpdatedAt: m.WebhookSubscriptionUpdatedAt,
+	}
+	if withSecret {
+		s := m.WebhookSubscriptionSecret
+		out.WebhookSubscriptionSecret = &s
+	}
+	return out
+}
+
+func FromSubscriptions(rows []model.WebhookSubscriptionModel) []SubscriptionResponse {
+	out := make([]SubscriptionResponse, 0, len(rows))
+	for i := range rows {
+		out = append(out, FromSubscription(&rows[i], false))
+	}
+	return out
+}
+
+type DeliveryDetailResponse struct {
+	model.WebhookDeliveryModel
+	Attempts []model.WebhookDeliveryAttemptModel `json:"attempts"`
+}
+
+func FromDeliveryDetail(d *model.WebhookDeliveryModel, attempts []model.WebhookDeliveryAttemptModel) DeliveryDetailResponse {
+	if attempts == nil {
+		attempts = []model.WebhookDeliveryAttemptModel{}
+	}
+	return DeliveryDetailResponse{WebhookDeliveryModel: *d, Attempts: attempts}
+}
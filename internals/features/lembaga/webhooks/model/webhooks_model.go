@@ -0,0 +1,110 @@
+// file: internals/features/lembaga/webhooks/model/webhooks_model.go
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// Event yang bisa dilanggan.
+const (
+	EventPaymentPaid      = "payment.paid"
+	EventStudentEnrolled  = "student.enrolled"
+	EventAttendanceAbsent = "attendance.absent"
+	EventAssessmentGraded = "assessment.graded"
+
+	// hanya dari tombol "test" (selalu terkirim, tidak ikut filter)
+	EventPing = "webhook.ping"
+)
+
+var SubscribableEvents = []string{
+	EventPaymentPaid,
+	EventStudentEnrolled,
+	EventAttendanceAbsent,
+	EventAssessmentGraded,
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending    DeliveryStatus = "pending"
+	DeliveryDelivering DeliveryStatus = "delivering"
+	DeliverySucceeded  DeliveryStatus = "succeeded"
+	DeliveryDead       DeliveryStatus = "dead"
+)
+
+// WebhookSubscriptionModel: endpoint integrasi milik sekolah.
+type WebhookSubscriptionModel struct {
+	WebhookSubscriptionID       uuid.UUID      `gorm:"column:webhook_subscription_id;type:uuid;default:gen_random_uuid();primaryKey" json:"webhook_subscription_id"`
+	WebhookSubscriptionSchoolID uuid.UUID      `gorm:"column:webhook_subscription_school_id;type:uuid;not null" json:"webhook_subscription_school_id"`
+	WebhookSubscriptionName     string         `gorm:"column:webhook_subscription_name;type:varchar(120);not null" json:"webhook_subscription_name"`
+	WebhookSubscriptionURL      string         `gorm:"column:webhook_subscription_url;type:text;not null" json:"webhook_subscription_url"`
+	WebhookSubscriptionSecret   string         `gorm:"column:webhook_subscription_secret;type:varchar(128);not null" json:"-"`
+	WebhookSubscriptionEvents   pq.StringArray `gorm:"column:webhook_subscription_events;type:text[];not null;default:'{}'" json:"webhook_subscription_events"`
+	WebhookSubscriptionIsActive bool           `gorm:"column:webhook_subscription_is_active;not null;default:true" json:"webhook_subscription_is_active"`
+
+	WebhookSubscriptionCreatedBy *uuid.UUID `gorm:"column:webhook_subscription_created_by;type:uuid" json:"webhook_subscription_created_by,omitempty"`
+
+	WebhookSubscriptionCreatedAt time.Time      `gorm:"column:webhook_subscription_created_at;autoCreateTime" json:"webhook_subscription_created_at"`
+	WebhookSubscriptionUpdatedAt time.Time      `gorm:"column:webhook_subscription_updated_at;autoUpdateTime" json:"webhook_subscription_updated_at"`
+	WebhookSubscriptionDeletedAt gorm.DeletedAt `gorm:"column:webhook_subscription_deleted_at;index" json:"-"`
+}
+
+func (WebhookSubscriptionModel) TableName() string { return "webhook_subscriptions" }
+
+// Wants: filter kosong = semua event.
+func (m *WebhookSubscriptionModel) Wants(event string) bool {
+	if event == EventPing || len(m.WebhookSubscriptionEvents) == 0 {
+		return true
+	}
+	for _, e := range m.WebhookSubscriptionEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDeliveryModel: 1 event untuk 1 subscription (antrean + status terakhir).
+type WebhookDeliveryModel struct {
+	WebhookDeliveryID             uuid.UUID      `gorm:"column:webhook_delivery_id;type:uuid;default:gen_random_uuid();primaryKey" json:"webhook_delivery_id"`
+	WebhookDeliverySchoolID       uuid.UUID      `gorm:"column:webhook_delivery_school_id;type:uuid;not null" json:"webhook_delivery_school_id"`
+	WebhookDeliverySubscriptionID uuid.UUID      `gorm:"column:webhook_delivery_subscription_id;type:uuid;not null" json:"webhook_delivery_subscription_id"`
+	WebhookDeliveryEventID        uuid.UUID      `gorm:"column:webhook_delivery_event_id;type:uuid;not null" json:"webhook_delivery_event_id"`
+	WebhookDeliveryEvent          string         `gorm:"column:webhook_delivery_event;type:varchar(64);not null" json:"webhook_delivery_event"`
+	WebhookDeliveryDedupeKey      *string        `gorm:"column:webhook_delivery_dedupe_key;type:varchar(160)" json:"-"`
+	WebhookDeliveryRedeliveryOf   *uuid.UUID     `gorm:"column:webhook_delivery_redelivery_of;type:uuid" json:"webhook_delivery_redelivery_of,omitempty"`
+	WebhookDeliveryPayload        datatypes.JSON `gorm:"column:webhook_delivery_payload;type:jsonb;not null" json:"webhook_delivery_payload"`
+
+	WebhookDeliveryStatus         DeliveryStatus `gorm:"column:webhook_delivery_status;type:varchar(16);not null;default:'pending'" json:"webhook_delivery_status"`
+	WebhookDeliveryAttempts       int            `gorm:"column:webhook_delivery_attempts;not null;default:0" json:"webhook_delivery_attempts"`
+	WebhookDeliveryMaxAttempts    int            `gorm:"column:webhook_delivery_max_attempts;not null;default:8" json:"webhook_delivery_max_attempts"`
+	WebhookDeliveryNextAttemptAt  time.Time      `gorm:"column:webhook_delivery_next_attempt_at;not null" json:"webhook_delivery_next_attempt_at"`
+	WebhookDeliveryLastStatusCode *int           `gorm:"column:webhook_delivery_last_status_code" json:"webhook_delivery_last_status_code,omitempty"`
+	WebhookDeliveryLastError      *string        `gorm:"column:webhook_delivery_last_error;type:text" json:"webhook_delivery_last_error,omitempty"`
+	WebhookDeliveryDeliveredAt    *time.Time     `gorm:"column:webhook_delivery_delivered_at" json:"webhook_delivery_delivered_at,omitempty"`
+
+	WebhookDeliveryCreatedAt time.Time `gorm:"column:webhook_delivery_created_at;autoCreateTime" json:"webhook_delivery_created_at"`
+	WebhookDeliveryUpdatedAt time.Time `gorm:"column:webhook_delivery_updated_at;autoUpdateTime" json:"webhook_delivery_updated_at"`
+}
+
+func (WebhookDeliveryModel) TableName() string { return "webhook_deliveries" }
+
+// WebhookDeliveryAttemptModel: log 1 percobaan HTTP.
+type WebhookDeliveryAttemptModel struct {
+	WebhookDeliveryAttemptID           uuid.UUID `gorm:"column:webhook_delivery_attempt_id;type:uuid;default:gen_random_uuid();primaryKey" json:"webhook_delivery_attempt_id"`
+	WebhookDeliveryAttemptDeliveryID   uuid.UUID `gorm:"column:webhook_delivery_attempt_delivery_id;type:uuid;not null" json:"webhook_delivery_attempt_delivery_id"`
+	WebhookDeliveryAttemptNo           int       `gorm:"column:webhook_delivery_attempt_no;not null" json:"webhook_delivery_attempt_no"`
+	WebhookDeliveryAttemptStatusCode   *int      `gorm:"column:webhook_delivery_attempt_status_code" json:"webhook_delivery_attempt_status_code,omitempty"`
+	WebhookDeliveryAttemptResponseBody *string   `gorm:"column:webhook_delivery_attempt_response_body;type:text" json:"webhook_delivery_attempt_response_body,omitempty"`
+	WebhookDeliveryAttemptError        *string   `gorm:"column:webhook_delivery_attempt_error;type:text" json:"webhook_delivery_attempt_error,omitempty"`
+	WebhookDeliveryAttemptDurationMs   int       `gorm:"column:webhook_delivery_attempt_duration_ms;not null;default:0" json:"webhook_delivery_attempt_duration_ms"`
+
+	WebhookDeliveryAttemptCreatedAt time.Time `gorm:"column:webhook_delivery_attempt_created_at;autoCreateTime" json:"webhook_delivery_attempt_created_at"`
+}
+
+func (WebhookDeliveryAttemptModel) TableName() string { return "webhook_delivery_attempts" }
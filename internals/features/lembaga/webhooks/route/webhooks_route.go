@@ -0,0 +1,35 @@
+// file: internals/features/lembaga/webhooks/route/webhooks_route.go
+package route
+
+import (
+	webhookController "madinahsalam_backend/internals/features/lembaga/webhooks/controller"
+	schoolkuMiddleware "madinahsalam_backend/internals/middlewares/features"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// /api/a/webhooks → endpoint integrasi sekolah (webhooks.* tidak bisa didelegasikan)
+func WebhookAdminRoutes(api fiber.Router, db *gorm.DB) {
+	ctl := webhookController.NewWebhookController(db)
+
+	read := schoolkuMiddleware.RequirePermission("webhooks.read")
+	write := schoolkuMiddleware.RequirePermission("webhooks.write")
+	del := schoolkuMiddleware.RequirePermission("webhooks.delete")
+
+	g := api.Group("/webhooks")
+
+	g.Get("/events", read, ctl.Events)
+
+	g.Get("/deliveries", read, ctl.ListDeliveries)
+	g.Get("/deliveries/:id", read, ctl.DeliveryDetail)
+	g.Post("/deliveries/:id/redeliver", write, ctl.Redeliver)
+
+	g.Get("/", read, ctl.List)
+	g.Post("/", write, ctl.Create)
+	g.Get("/:id", read, ctl.Detail)
+	g.Patch("/:id", write, ctl.Update)
+	g.Delete("/:id", del, ctl.Delete)
+	g.Post("/:id/rotate-secret", write, ctl.RotateSecret)
+	g.Post("/:id/ping", write, ctl.Ping)
+}
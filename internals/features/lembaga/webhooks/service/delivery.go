@@ -0,0 +1,370 @@
+// file: internals/features/lembaga/webhooks/service/delivery.go
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	model "madinahsalam_backend/internals/features/lembaga/webhooks/model"
+	"madinahsalam_backend/internals/helpers/telemetry"
+)
+
+/* =========================================================
+   Pengiriman
+   Header ke endpoint sekolah:
+     X-Webhook-Id         event id (sama untuk redelivery → dedupe di penerima)
+     X-Webhook-Event      nama event
+     X-Webhook-Delivery   delivery id
+     X-Webhook-Timestamp  unix detik
+     X-Webhook-Signature  sha256=hex(HMAC-SHA256(secret, timestamp + "." + body))
+   2xx = sukses; selain itu retry dengan backoff eksponensial
+   (30s, 1m, 2m, 4m, ... maks 6 jam) sampai max_attempts → dead.
+========================================================= */
+
+var (
+	ErrDeliveryNotFound  = errors.New("delivery webhook tidak ditemukan")
+	ErrDeliveryInFlight  = errors.New("delivery masih dalam antrean")
+	ErrBlockedAddress    = errors.New("alamat tujuan webhook tidak diizinkan")
+	errSubscriptionGone  = "webhook sudah dihapus"
+	errSubscriptionPause = "webhook nonaktif"
+)
+
+const (
+	backoffBase     = 30 * time.Second
+	backoffMax      = 6 * time.Hour
+	deliveryTimeout = 10 * time.Second
+	maxResponseLog  = 2048
+)
+
+func maxAttempts() int {
+	if n, err := strconv.Atoi(strings.TrimSpace(os.Getenv("WEBHOOK_MAX_ATTEMPTS"))); err == nil && n > 0 {
+		return n
+	}
+	return 8
+}
+
+// Sign: signature yang diverifikasi penerima.
+func Sign(secret string, ts int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(ts, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Backoff untuk percobaan ke-n (n mulai 1) + jitter ±10%.
+func Backoff(n int) time.Duration {
+	d := backoffBase
+	for i := 1; i < n && d < backoffMax; i++ {
+		d *= 2
+	}
+	if d > backoffMax {
+		d = backoffMax
+	}
+	jitter := time.Duration(rand.Int64N(int64(d)/5+1)) - d/10
+	return d + jitter
+}
+
+/* =========================================================
+   HTTP client (tolak alamat internal kecuali WEBHOOK_ALLOW_PRIVATE=true)
+   Dicek saat dial → aman dari DNS rebinding.
+========================================================= */
+
+func allowPrivate() bool {
+	return strings.EqualFold(strings.TrimSpace(os.Getenv("WEBHOOK_ALLOW_PRIVATE")), "true")
+}
+
+func blockedIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast()
+}
+
+var httpClient = &http.Client{
+	Timeout: deliveryTimeout,
+	Transport: &http.Transport{
+		Proxy: nil,
+		DialContext: (&net.Dialer{
+			Timeout: 5 * time.Second,
+			Control: func(_, address string, _ syscall.RawConn) error {
+				if allowPrivate() {
+					return nil
+				}
+				host, _, err := net.SplitHostPort(address)
+				if err != nil {
+					return err
+				}
+				if ip := net.ParseIP(host); ip == nil || blockedIP(ip) {
+					return ErrBlockedAddress
+				}
+				return nil
+			},
+		}).DialContext,
+		TLSHandshakeTimeout:   5 * time.Second,
+		ResponseHeaderTimeout: deliveryTimeout,
+		MaxIdleConnsPerHost:   2,
+	},
+	// redirect tidak diikuti: penerima wajib membalas langsung
+	CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+}
+
+/* =========================================================
+   Antrean (dipakai worker)
+========================================================= */
+
+// ClaimDue mengambil delivery jatuh tempo (aman untuk multi-instance).
+func ClaimDue(ctx context.Context, db *gorm.DB, limit int) ([]model.WebhookDeliveryModel, error) {
+	var rows []model.WebhookDeliveryModel
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("webhook_delivery_status = ? AND webhook_delivery_next_attempt_at <= ?", model.DeliveryPending, time.Now()).
+			Order("webhook_delivery_next_attempt_at ASC").
+			Limit(limit).
+			Find(&rows).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		ids := make([]uuid.UUID, 0, len(rows))
+		for i := range rows {
+			ids = append(ids, rows[i].WebhookDeliveryID)
+			rows[i].WebhookDeliveryStatus = model.DeliveryDelivering
+		}
+		return tx.Model(&model.WebhookDeliveryModel{}).
+			Where("webhook_delivery_id IN ?", ids).
+			Updates(map[string]any{
+				"webhook_delivery_status":     model.DeliveryDelivering,
+				"webhook_delivery_updated_at": time.Now(),
+			}).Error
+	})
+	return rows, err
+}
+
+// RequeueStale: delivery tertinggal "delivering" (proses mati saat kirim) diantre ulang.
+func RequeueStale(ctx context.Context, db *gorm.DB, olderThan time.Duration) (int64, error) {
+	res := db.WithContext(ctx).Model(&model.WebhookDeliveryModel{}).
+		Where("webhook_delivery_status = ? AND webhook_delivery_updated_at < ?", model.DeliveryDelivering, time.Now().Add(-olderThan)).
+		Updates(map[string]any{
+			"webhook_delivery_status":          model.DeliveryPending,
+			"webhook_delivery_next_attempt_at": time.Now(),
+		})
+	return res.RowsAffected, res.Error
+}
+
+// Deliver: 1 percobaan kirim + catat attempt + jadwalkan ulang / tandai selesai.
+func Deliver(ctx context.Context, db *gorm.DB, d *model.WebhookDeliveryModel) error {
+	var sub model.WebhookSubscriptionModel
+	if err := db.WithContext(ctx).Unscoped().
+		First(&sub, "webhook_subscription_id = ?", d.WebhookDeliverySubscriptionID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return markDead(ctx, db, d, errSubscriptionGone)
+		}
+		return err
+	}
+	if sub.WebhookSubscriptionDeletedAt.Valid {
+		return markDead(ctx, db, d, errSubscriptionGone)
+	}
+	if !sub.WebhookSubscriptionIsActive && d.WebhookDeliveryEvent != model.EventPing {
+		return markDead(ctx, db, d, errSubscriptionPause)
+	}
+
+	attemptNo := d.WebhookDeliveryAttempts + 1
+	body := []byte(d.WebhookDeliveryPayload)
+	ts := time.Now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.WebhookSubscriptionURL, bytes.NewReader(body))
+	if err != nil {
+		return record(ctx, db, d, attemptNo, nil, "", err.Error(), 0)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "MadinahSalam-Webhook/1.0")
+	req.Header.Set("X-Webhook-Id", d.WebhookDeliveryEventID.String())
+	req.Header.Set("X-Webhook-Event", d.WebhookDeliveryEvent)
+	req.Header.Set("X-Webhook-Delivery", d.WebhookDeliveryID.String())
+	req.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(ts, 10))
+	req.Header.Set("X-Webhook-Signature", Sign(sub.WebhookSubscriptionSecret, ts, body))
+
+	start := time.Now()
+	resp, err := httpClient.Do(req)
+	dur := time.Since(start)
+	if err != nil {
+		msg := err.Error()
+		if errors.Is(err, ErrBlockedAddress) {
+			msg = ErrBlockedAddress.Error()
+		}
+		return record(ctx, db, d, attemptNo, nil, "", msg, dur)
+	}
+	defer resp.Body.Close()
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseLog))
+
+	code := resp.StatusCode
+	if code >= 200 && code < 300 {
+		return record(ctx, db, d, attemptNo, &code, string(snippet), "", dur)
+	}
+	return record(ctx, db, d, attemptNo, &code, string(snippet), fmt.Sprintf("HTTP %d", code), dur)
+}
+
+func record(ctx context.Context, db *gorm.DB, d *model.WebhookDeliveryModel, attemptNo int, code *int, respBody, errMsg string, dur time.Duration) error {
+	a := model.WebhookDeliveryAttemptModel{
+		WebhookDeliveryAttemptDeliveryID: d.WebhookDeliveryID,
+		WebhookDeliveryAttemptNo:         attemptNo,
+		WebhookDeliveryAttemptStatusCode: code,
+		WebhookDeliveryAttemptDurationMs: int(dur.Milliseconds()),
+	}
+	// kolom text: buang NUL & byte non-UTF8 dari body penerima
+	if respBody = strings.ToValidUTF8(strings.ReplaceAll(respBody, "\x00", ""), ""); respBody != "" {
+		a.WebhookDeliveryAttemptResponseBody = &respBody
+	}
+	if errMsg != "" {
+		a.WebhookDeliveryAttemptError = &errMsg
+	}
+
+	result := "retry"
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&a).Error; err != nil {
+			return err
+		}
+		now := time.Now()
+		upd := map[string]any{
+			"webhook_delivery_attempts":         attemptNo,
+			"webhook_delivery_last_status_code": code,
+			"webhook_delivery_updated_at":       now,
+		}
+		switch {
+		case errMsg == "":
+			result = string(model.DeliverySucceeded)
+			upd["webhook_delivery_status"] = model.DeliverySucceeded
+			upd["webhook_delivery_delivered_at"] = now
+			upd["webhook_delivery_last_error"] = nil
+		case attemptNo >= d.WebhookDeliveryMaxAttempts:
+			result = string(model.DeliveryDead)
+			upd["webhook_delivery_status"] = model.DeliveryDead
+			upd["webhook_delivery_last_error"] = errMsg
+		default:
+			upd["webhook_delivery_status"] = model.DeliveryPending
+			upd["webhook_delivery_last_error"] = errMsg
+			upd["webhook_delivery_next_attempt_at"] = now.Add(Backoff(attemptNo))
+		}
+		return tx.Model(&model.WebhookDeliveryModel{}).
+			Where("webhook_delivery_id = ?", d.WebhookDeliveryID).
+			Updates(upd).Error
+	})
+	if err == nil {
+		telemetry.ObserveWebhookDelivery(d.WebhookDeliveryEvent, result, dur)
+	}
+	return err
+}
+
+// markDead: tutup delivery tanpa request HTTP (subscription hilang/nonaktif).
+func markDead(ctx context.Context, db *gorm.DB, d *model.WebhookDeliveryModel, reason string) error {
+	err := db.WithContext(ctx).Model(&model.WebhookDeliveryModel{}).
+		Where("webhook_delivery_id = ?", d.WebhookDeliveryID).
+		Updates(map[string]any{
+			"webhook_delivery_status":     model.DeliveryDead,
+			"webhook_delivery_last_error": reason,
+			"webhook_delivery_updated_at": time.Now(),
+		}).Error
+	if err == nil {
+		telemetry.ObserveWebhookDelivery(d.WebhookDeliveryEvent, string(model.DeliveryDead), 0)
+	}
+	return err
+}
+
+/* =========================================================
+   Log & redelivery (admin)
+========================================================= */
+
+type DeliveryFilter struct {
+	SubscriptionID *uuid.UUID
+	Event          string
+	Status         string
+}
+
+func ListDeliveries(ctx context.Context, db *gorm.DB, schoolID uuid.UUID, f DeliveryFilter, limit, offset int) ([]model.WebhookDeliveryModel, int64, error) {
+	q := db.WithContext(ctx).Model(&model.WebhookDeliveryModel{}).
+		Where("webhook_delivery_school_id = ?", schoolID)
+	if f.SubscriptionID != nil {
+		q = q.Where("webhook_delivery_subscription_id = ?", *f.SubscriptionID)
+	}
+	if e := strings.TrimSpace(f.Event); e != "" {
+		q = q.Where("webhook_delivery_event = ?", e)
+	}
+	if s := strings.TrimSpace(f.Status); s != "" {
+		q = q.Where("webhook_delivery_status = ?", s)
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var rows []model.WebhookDeliveryModel
+	err := q.Order("webhook_delivery_created_at DESC").Limit(limit).Offset(offset).Find(&rows).Error
+	return rows, total, err
+}
+
+func GetDelivery(ctx context.Context, db *gorm.DB, schoolID, id uuid.UUID) (*model.WebhookDeliveryModel, []model.WebhookDeliveryAttemptModel, error) {
+	var d model.WebhookDeliveryModel
+	if err := db.WithContext(ctx).
+		Where("webhook_delivery_id = ? AND webhook_delivery_school_id = ?", id, schoolID).
+		First(&d).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrDeliveryNotFound
+		}
+		return nil, nil, err
+	}
+	var attempts []model.WebhookDeliveryAttemptModel
+	if err := db.WithContext(ctx).
+		Where("webhook_delivery_attempt_delivery_id = ?", id).
+		Order("webhook_delivery_attempt_no ASC").
+		Find(&attempts).Error; err != nil {
+		return nil, nil, err
+	}
+	return &d, attempts, nil
+}
+
+// Redeliver: antre ulang payload yang sama (event id tetap) sebagai delivery baru.
+func Redeliver(ctx context.Context, db *gorm.DB, schoolID, id uuid.UUID) (*model.WebhookDeliveryModel, error) {
+	src, _, err := GetDelivery(ctx, db, schoolID, id)
+	if err != nil {
+		return nil, err
+	}
+	if src.WebhookDeliveryStatus == model.DeliveryPending || src.WebhookDeliveryStatus == model.DeliveryDelivering {
+		return nil, ErrDeliveryInFlight
+	}
+	if _, err := GetSubscription(ctx, db, schoolID, src.WebhookDeliverySubscriptionID); err != nil {
+		return nil, err
+	}
+	d := &model.WebhookDeliveryModel{
+		WebhookDeliverySchoolID:       schoolID,
+		WebhookDeliverySubscriptionID: src.WebhookDeliverySubscriptionID,
+		WebhookDeliveryEventID:        src.WebhookDeliveryEventID,
+		WebhookDeliveryEvent:          src.WebhookDeliveryEvent,
+		WebhookDeliveryRedeliveryOf:   &src.WebhookDeliveryID,
+		WebhookDeliveryPayload:        src.WebhookDeliveryPayload,
+		WebhookDeliveryStatus:         model.DeliveryPending,
+		WebhookDeliveryMaxAttempts:    maxAttempts(),
+		WebhookDeliveryNextAttemptAt:  time.Now(),
+	}
+	if err := db.WithContext(ctx).Create(d).Error; err != nil {
+		return nil, err
+	}
+	return d, nil
+}
@@ -0,0 +1,98 @@
+// file: internals/features/lembaga/webhooks/service/emit.go
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	model "madinahsalam_backend/internals/features/lembaga/webhooks/model"
+)
+
+/* =========================================================
+   Emit: dipanggil dari titik bisnis (payment, enrollment, presensi, kuis).
+   - hanya INSERT ke antrean; pengiriman HTTP oleh worker
+   - dijalankan dalam (sub)transaksi → bila db adalah tx pemanggil,
+     kegagalan di sini cukup rollback ke savepoint, tx pemanggil aman
+   - dedupeKey (opsional) mencegah event sama diantre 2x saat side-effect
+     dipanggil berulang (mis. callback gateway dikirim ulang)
+========================================================= */
+
+// Envelope: body JSON yang diterima endpoint sekolah.
+type Envelope struct {
+	ID         uuid.UUID `json:"id"`
+	Event      string    `json:"event"`
+	SchoolID   uuid.UUID `json:"school_id"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       any       `json:"data"`
+}
+
+func buildPayload(eventID, schoolID uuid.UUID, event string, data any) (datatypes.JSON, error) {
+	b, err := json.Marshal(Envelope{
+		ID:         eventID,
+		Event:      event,
+		SchoolID:   schoolID,
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	})
+	return datatypes.JSON(b), err
+}
+
+// Emit mengantre event untuk semua subscription aktif sekolah yang memfilter event tsb.
+// Error hanya di-log (webhook tidak boleh menggagalkan alur utama) dan dikembalikan
+// bila pemanggil ingin tahu.
+func Emit(ctx context.Context, db *gorm.DB, schoolID uuid.UUID, event, dedupeKey string, data any) error {
+	if db == nil || schoolID == uuid.Nil {
+		return nil
+	}
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var subs []model.WebhookSubscriptionModel
+		if err := tx.
+			Where("webhook_subscription_school_id = ? AND webhook_subscription_is_active", schoolID).
+			Where("(cardinality(webhook_subscription_events) = 0 OR ? = ANY(webhook_subscription_events))", event).
+			Find(&subs).Error; err != nil {
+			return err
+		}
+		if len(subs) == 0 {
+			return nil
+		}
+
+		eventID := uuid.New()
+		payload, err := buildPayload(eventID, schoolID, event, data)
+		if err != nil {
+			return err
+		}
+		var dk *string
+		if k := strings.TrimSpace(dedupeKey); k != "" {
+			dk = &k
+		}
+
+		now := time.Now()
+		rows := make([]model.WebhookDeliveryModel, 0, len(subs))
+		for _, s := range subs {
+			rows = append(rows, model.WebhookDeliveryModel{
+				WebhookDeliverySchoolID:       schoolID,
+				WebhookDeliverySubscriptionID: s.WebhookSubscriptionID,
+				WebhookDeliveryEventID:        eventID,
+				WebhookDeliveryEvent:          event,
+				WebhookDeliveryDedupeKey:      dk,
+				WebhookDeliveryPayload:        payload,
+				WebhookDeliveryStatus:         model.DeliveryPending,
+				WebhookDeliveryMaxAttempts:    maxAttempts(),
+				WebhookDeliveryNextAttemptAt:  now,
+			})
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+	})
+	if err != nil {
+		log.Printf("[WEBHOOK] emit event=%s school=%s error: %v", event, schoolID, err)
+	}
+	return err
+}
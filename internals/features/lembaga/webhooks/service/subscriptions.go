@@ -0,0 +1,280 @@
+// file: internals/features/lembaga/webhooks/service/subscriptions.go
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+
+	model "madinahsalam_backend/internals/features/lembaga/webhooks/model"
+)
+
+var (
+	ErrSubscriptionNotFound = errors.New("webhook tidak ditemukan")
+	ErrInvalidURL           = errors.New("url webhook harus https:// dengan host yang valid")
+	ErrInvalidEvent         = errors.New("event tidak dikenal")
+	ErrNameEmpty            = errors.New("nama webhook wajib diisi")
+	ErrTooManySubscriptions = errors.New("jumlah webhook sekolah sudah mencapai batas")
+)
+
+// Batas endpoint per sekolah (biar satu event tidak meledak jadi ratusan request).
+const maxSubscriptionsPerSchool = 20
+
+func allowHTTP() bool {
+	return strings.EqualFold(strings.TrimSpace(os.Getenv("WEBHOOK_ALLOW_HTTP")), "true")
+}
+
+func validateURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || u.User != nil {
+		return "", ErrInvalidURL
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "https":
+	case "http":
+		if !allowHTTP() {
+			return "", ErrInvalidURL
+		}
+	default:
+		return "", ErrInvalidURL
+	}
+	return u.String(), nil
+}
+
+func normalizeEvents(in []string) ([]string, error) {
+	valid := map[string]bool{}
+	for _, e := range model.SubscribableEvents {
+		valid[e] = true
+	}
+	set := map[string]bool{}
+	for _, e := range in {
+		e = strings.ToLower(strings.TrimSpace(e))
+		if e == "" {
+			continue
+		}
+		if !valid[e] {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidEvent, e)
+		}
+		set[e] = true
+	}
+	out := make([]string, 0, len(set))
+	for e := range set {
+		out = append(out, e)
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+/* =========================================================
+   CRUD
+========================================================= */
+
+type SubscriptionInput struct {
+	Name      *string
+	URL       *string
+	Events    []string
+	SetEvents bool
+	IsActive  *bool
+}
+
+func ListSubscriptions(ctx context.Context, db *gorm.DB, schoolID uuid.UUID) ([]model.WebhookSubscriptionModel, error) {
+	var rows []model.WebhookSubscriptionModel
+	err := db.WithContext(ctx).
+		Where("webhook_subscription_school_id = ?", schoolID).
+		Order("webhook_subscription_created_at ASC").
+		Find(&rows).Error
+	return rows, err
+}
+
+func GetSubscription(ctx context.Context, db *gorm.DB, schoolID, id uuid.UUID) (*model.WebhookSubscriptionModel, error) {
+	var m model.WebhookSubscriptionModel
+	if err := db.WithContext(ctx).
+		Where("webhook_subscription_id = ? AND webhook_subscription_school_id = ?", id, schoolID).
+		First(&m).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSubscriptionNotFound
+		}
+		return nil, err
+	}
+	return &m, nil
+}
+
+// CreateSubscription: secret dibuat server & hanya dikembalikan sekali di response create/rotate.
+func CreateSubscription(ctx context.Context, db *gorm.DB, schoolID uuid.UUID, in SubscriptionInput, by *uuid.UUID) (*model.WebhookSubscriptionModel, error) {
+	name := ""
+	if in.Name != nil {
+		name = strings.TrimSpace(*in.Name)
+	}
+	if name == "" {
+		return nil, ErrNameEmpty
+	}
+	rawURL := ""
+	if in.URL != nil {
+		rawURL = *in.URL
+	}
+	u, err := validateURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	events, err := normalizeEvents(in.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	var n int64
+	if err := db.WithContext(ctx).Model(&model.WebhookSubscriptionModel{}).
+		Where("webhook_subscription_school_id = ?", schoolID).
+		Count(&n).Error; err != nil {
+		return nil, err
+	}
+	if n >= maxSubscriptionsPerSchool {
+		return nil, ErrTooManySubscriptions
+	}
+
+	secret, err := newSecret()
+	if err != nil {
+		return nil, err
+	}
+	m := &model.WebhookSubscriptionModel{
+		WebhookSubscriptionSchoolID:  schoolID,
+		WebhookSubscriptionName:      name,
+		WebhookSubscriptionURL:       u,
+		WebhookSubscriptionSecret:    secret,
+		WebhookSubscriptionEvents:    pq.StringArray(events),
+		WebhookSubscriptionIsActive:  true,
+		WebhookSubscriptionCreatedBy: by,
+	}
+	if in.IsActive != nil {
+		m.WebhookSubscriptionIsActive = *in.IsActive
+	}
+	if err := db.WithContext(ctx).Create(m).Error; err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func UpdateSubscription(ctx context.Context, db *gorm.DB, schoolID, id uuid.UUID, in SubscriptionInput) (*model.WebhookSubscriptionModel, error) {
+	m, err := GetSubscription(ctx, db, schoolID, id)
+	if err != nil {
+		return nil, err
+	}
+	upd := map[string]any{"webhook_subscription_updated_at": time.Now()}
+	if in.Name != nil {
+		name := strings.TrimSpace(*in.Name)
+		if name == "" {
+			return nil, ErrNameEmpty
+		}
+		upd["webhook_subscription_name"] = name
+	}
+	if in.URL != nil {
+		u, err := validateURL(*in.URL)
+		if err != nil {
+			return nil, err
+		}
+		upd["webhook_subscription_url"] = u
+	}
+	if in.SetEvents {
+		events, err := normalizeEvents(in.Events)
+		if err != nil {
+			return nil, err
+		}
+		upd["webhook_subscription_events"] = pq.StringArray(events)
+	}
+	if in.IsActive != nil {
+		upd["webhook_subscription_is_active"] = *in.IsActive
+	}
+	if err := db.WithContext(ctx).Model(m).Updates(upd).Error; err != nil {
+		return nil, err
+	}
+	return GetSubscription(ctx, db, schoolID, id)
+}
+
+// DeleteSubscription: soft delete + antrean yang belum terkirim dimatikan.
+func DeleteSubscription(ctx context.Context, db *gorm.DB, schoolID, id uuid.UUID) error {
+	m, err := GetSubscription(ctx, db, schoolID, id)
+	if err != nil {
+		return err
+	}
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(m).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.WebhookDeliveryModel{}).
+			Where("webhook_delivery_subscription_id = ? AND webhook_delivery_status = ?", id, model.DeliveryPending).
+			Updates(map[string]any{
+				"webhook_delivery_status":     model.DeliveryDead,
+				"webhook_delivery_last_error": "webhook dihapus",
+				"webhook_delivery_updated_at": time.Now(),
+			}).Error
+	})
+}
+
+func RotateSecret(ctx context.Context, db *gorm.DB, schoolID, id uuid.UUID) (*model.WebhookSubscriptionModel, error) {
+	m, err := GetSubscription(ctx, db, schoolID, id)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := newSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := db.WithContext(ctx).Model(m).Updates(map[string]any{
+		"webhook_subscription_secret":     secret,
+		"webhook_subscription_updated_at": time.Now(),
+	}).Error; err != nil {
+		return nil, err
+	}
+	m.WebhookSubscriptionSecret = secret
+	return m, nil
+}
+
+// Ping: antre event webhook.ping hanya untuk 1 subscription (abaikan filter & status aktif).
+func Ping(ctx context.Context, db *gorm.DB, schoolID, id uuid.UUID) (*model.WebhookDeliveryModel, error) {
+	m, err := GetSubscription(ctx, db, schoolID, id)
+	if err != nil {
+		return nil, err
+	}
+	eventID := uuid.New()
+	payload, err := buildPayload(eventID, schoolID, model.EventPing, map[string]any{
+		"webhook_subscription_id": m.WebhookSubscriptionID,
+		"message":                 "ping",
+	})
+	if err != nil {
+		return nil, err
+	}
+	d := &model.WebhookDeliveryModel{
+		WebhookDeliverySchoolID:       schoolID,
+		WebhookDeliverySubscriptionID: m.WebhookSubscriptionID,
+		WebhookDeliveryEventID:        eventID,
+		WebhookDeliveryEvent:          model.EventPing,
+		WebhookDeliveryPayload:        payload,
+		WebhookDeliveryStatus:         model.DeliveryPending,
+		// ping cukup sekali, hasilnya langsung terlihat di log
+		WebhookDeliveryMaxAttempts:   1,
+		WebhookDeliveryNextAttemptAt: time.Now(),
+	}
+	if err := db.WithContext(ctx).Create(d).Error; err != nil {
+		return nil, err
+	}
+	return d, nil
+}
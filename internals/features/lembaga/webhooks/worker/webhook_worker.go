@@ -0,0 +1,61 @@
+// file: internals/features/lembaga/webhooks/worker/webhook_worker.go
+package worker
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"gorm.io/gorm"
+
+	svc "madinahsalam_backend/internals/features/lembaga/webhooks/service"
+)
+
+const claimBatch = 20
+
+// RunWebhookWorker: polling antrean webhook_deliveries sampai ctx dibatalkan.
+// Interval via WEBHOOK_POLL_INTERVAL (default 5s).
+func RunWebhookWorker(ctx context.Context, db *gorm.DB) {
+	interval := 5 * time.Second
+	if v, err := time.ParseDuration(os.Getenv("WEBHOOK_POLL_INTERVAL")); err == nil && v > 0 {
+		interval = v
+	}
+
+	log.Printf("[WEBHOOK] worker started interval=%s", interval)
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		// delivery yatim (proses mati saat kirim) → antre ulang
+		if n, err := svc.RequeueStale(ctx, db, 5*time.Minute); err != nil {
+			log.Printf("[WEBHOOK] requeue stale error: %v", err)
+		} else if n > 0 {
+			log.Printf("[WEBHOOK] requeued %d stale delivery(s)", n)
+		}
+
+		// habiskan yang jatuh tempo sebelum tidur lagi
+		for {
+			rows, err := svc.ClaimDue(ctx, db, claimBatch)
+			if err != nil {
+				log.Printf("[WEBHOOK] claim error: %v", err)
+				break
+			}
+			for i := range rows {
+				if err := svc.Deliver(ctx, db, &rows[i]); err != nil {
+					log.Printf("[WEBHOOK] delivery=%s error: %v", rows[i].WebhookDeliveryID, err)
+				}
+			}
+			if len(rows) < claimBatch {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Printf("[WEBHOOK] worker stopped")
+			return
+		case <-t.C:
+		}
+	}
+}
@@ -0,0 +1,131 @@
+// file: internals/features/school/class_others/attendance_devices/controller/adms_controller.go
+package controller
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	model "madinahsalam_backend/internals/features/school/class_others/attendance_devices/model"
+	svc "madinahsalam_backend/internals/features/school/class_others/attendance_devices/service"
+)
+
+/*
+ADMS / iclock (protokol push mesin ZKTeco & kompatibel)
+
+GET  /iclock/cdata?SN=...                          handshake → opsi + ATTLOGStamp
+POST /iclock/cdata?SN=...&table=ATTLOG&Stamp=...   upload log absen (text, tab-separated)
+GET  /iclock/getrequest?SN=...                     polling perintah → selalu "OK"
+POST /iclock/devicecmd?SN=...                      hasil perintah → "OK"
+
+Tanpa JWT: mesin dikenali dari serial number, lalu diautentikasi dengan token push
+(?token= pada URL server mesin atau header X-Device-Token) + allowlist IP bila diisi.
+Respons wajib text/plain; mesin tidak paham JSON.
+*/
+
+type ADMSController struct {
+	DB *gorm.DB
+}
+
+func NewADMSController(db *gorm.DB) *ADMSController {
+	return &ADMSController{DB: db}
+}
+
+func (h *ADMSController) device(c *fiber.Ctx) (*model.AttendanceDeviceModel, error) {
+	sn := strings.TrimSpace(c.Query("SN"))
+	if sn == "" {
+		return nil, c.Status(fiber.StatusBadRequest).SendString("SN required")
+	}
+	dev, err := svc.FindDeviceBySerial(c.Context(), h.DB, sn)
+	if err != nil {
+		log.Printf("[ATT-DEVICE] ADMS rejected SN=%s ip=%s: %v", sn, c.IP(), err)
+		return nil, c.Status(fiber.StatusForbidden).SendString("device not registered")
+	}
+	token := strings.TrimSpace(c.Query("token"))
+	if token == "" {
+		token = strings.TrimSpace(c.Get("X-Device-Token"))
+	}
+	if err := svc.AuthenticateDevice(dev, token, c.IP()); err != nil {
+		log.Printf("[ATT-DEVICE] ADMS rejected SN=%s ip=%s: %v", sn, c.IP(), err)
+		return nil, c.Status(fiber.StatusUnauthorized).SendString("unauthorized")
+	}
+	return dev, nil
+}
+
+// GET /iclock/cdata
+func (h *ADMSController) Handshake(c *fiber.Ctx) error {
+	dev, err := h.device(c)
+	if dev == nil {
+		return err
+	}
+	svc.Touch(c.Context(), h.DB, dev, c.IP(), c.Query("pushver"), nil)
+
+	_, offset := time.Now().In(svc.SchoolLocation(c.Context(), h.DB, dev.AttendanceDeviceSchoolID)).Zone()
+	lines := []string{
+		"GET OPTION FROM: " + dev.AttendanceDeviceSerialNumber,
+		fmt.Sprintf("ATTLOGStamp=%d", dev.AttendanceDeviceAttlogStamp),
+		"OPERLOGStamp=9999",
+		"ATTPHOTOStamp=None",
+		"ErrorDelay=60",
+		"Delay=30",
+		"TransTimes=00:00;14:05",
+		"TransInterval=1",
+		"TransFlag=TransData AttLog",
+		fmt.Sprintf("TimeZone=%d", offset/3600),
+		"Realtime=1",
+		"Encrypt=None",
+	}
+	c.Set(fiber.HeaderContentType, fiber.MIMETextPlainCharsetUTF8)
+	return c.SendString(strings.Join(lines, "\r\n"))
+}
+
+// POST /iclock/cdata
+func (h *ADMSController) Upload(c *fiber.Ctx) error {
+	dev, err := h.device(c)
+	if dev == nil {
+		return err
+	}
+	c.Set(fiber.HeaderContentType, fiber.MIMETextPlainCharsetUTF8)
+
+	// OPERLOG/ATTPHOTO dll. tidak dipakai → tetap di-ACK supaya mesin tidak kirim ulang
+	if !strings.EqualFold(c.Query("table"), "ATTLOG") {
+		svc.Touch(c.Context(), h.DB, dev, c.IP(), "", nil)
+		return c.SendString("OK")
+	}
+
+	loc := svc.SchoolLocation(c.Context(), h.DB, dev.AttendanceDeviceSchoolID)
+	inputs, invalid := svc.ParseATTLOG(string(c.Body()), loc)
+	res, err := svc.Ingest(c.Context(), h.DB, dev, inputs, model.PunchSourceADMS)
+	if err != nil {
+		// tanpa ACK → mesin mengulang upload dengan stamp yang sama
+		log.Printf("[ATT-DEVICE] ADMS ingest SN=%s: %v", dev.AttendanceDeviceSerialNumber, err)
+		return c.Status(fiber.StatusInternalServerError).SendString("ERROR")
+	}
+
+	var stamp *int64
+	if v, err := strconv.ParseInt(strings.TrimSpace(c.Query("Stamp")), 10, 64); err == nil {
+		stamp = &v
+	}
+	svc.Touch(c.Context(), h.DB, dev, c.IP(), "", stamp)
+
+	if invalid > 0 || res.Rejected > 0 {
+		log.Printf("[ATT-DEVICE] ADMS SN=%s invalid=%d rejected=%d", dev.AttendanceDeviceSerialNumber, invalid, res.Rejected)
+	}
+	return c.SendString(fmt.Sprintf("OK: %d", len(inputs)+invalid))
+}
+
+// GET /iclock/getrequest, POST /iclock/devicecmd
+func (h *ADMSController) Ack(c *fiber.Ctx) error {
+	dev, err := h.device(c)
+	if dev == nil {
+		return err
+	}
+	svc.Touch(c.Context(), h.DB, dev, c.IP(), "", nil)
+	c.Set(fiber.HeaderContentType, fiber.MIMETextPlainCharsetUTF8)
+	return c.SendString("OK")
+}
@@ -0,0 +1,348 @@
+// file: internals/features/school/class_others/attendance_devices/controller/attendance_devices_controller.go
+package controller
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	apikeysvc "madinahsalam_backend/internals/features/lembaga/api_keys/service"
+	permsvc "madinahsalam_backend/internals/features/lembaga/permissions/service"
+	dto "madinahsalam_backend/internals/features/school/class_others/attendance_devices/dto"
+	model "madinahsalam_backend/internals/features/school/class_others/attendance_devices/model"
+	svc "madinahsalam_backend/internals/features/school/class_others/attendance_devices/service"
+	helper "madinahsalam_backend/internals/helpers"
+)
+
+/*
+Mesin absen fingerprint/RFID (admin; bisa juga lewat API key scope attendance_devices.*)
+
+GET    /api/a/attendance-devices
+POST   /api/a/attendance-devices                    {"name","serial_number","kind","location","is_active","allowed_ips"}
+GET    /api/a/attendance-devices/:id
+PATCH  /api/a/attendance-devices/:id
+DELETE /api/a/attendance-devices/:id
+POST   /api/a/attendance-devices/:id/resync         minta mesin kirim ulang seluruh log (ADMS)
+POST   /api/a/attendance-devices/:id/token          rotate token push ADMS (tampil sekali)
+POST   /api/a/attendance-devices/:id/punches        {"punches":[{"pin","punched_at","state","verify"}]}
+
+GET    /api/a/attendance-devices/users?q=           pemetaan PIN → siswa/guru
+POST   /api/a/attendance-devices/users              {"pin","school_student_id"|"school_teacher_id","card_number"}
+DELETE /api/a/attendance-devices/users/:id
+
+GET    /api/a/attendance-devices/punches?device_id=&status=&pin=&from=&to=
+POST   /api/a/attendance-devices/punches/reprocess  {"device_id","status","pin","from","to"}
+*/
+
+type AttendanceDeviceController struct {
+	DB *gorm.DB
+}
+
+func NewAttendanceDeviceController(db *gorm.DB) *AttendanceDeviceController {
+	return &AttendanceDeviceController{DB: db}
+}
+
+func writeErr(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, svc.ErrDeviceNotFound), errors.Is(err, svc.ErrMappingNotFound):
+		return helper.JsonError(c, fiber.StatusNotFound, err.Error())
+	case errors.Is(err, svc.ErrSerialTaken), errors.Is(err, svc.ErrPINTaken), errors.Is(err, svc.ErrPersonAlreadyHasPIN),
+		errors.Is(err, svc.ErrDeviceInactive):
+		return helper.JsonError(c, fiber.StatusConflict, err.Error())
+	case errors.Is(err, svc.ErrSerialEmpty), errors.Is(err, svc.ErrNameEmpty), errors.Is(err, svc.ErrInvalidKind),
+		errors.Is(err, svc.ErrPINEmpty), errors.Is(err, svc.ErrMappingTarget), errors.Is(err, svc.ErrPersonNotInSchool),
+		errors.Is(err, apikeysvc.ErrInvalidIP), errors.Is(err, apikeysvc.ErrTooManyIPRules):
+		return helper.JsonError(c, fiber.StatusBadRequest, err.Error())
+	}
+	var fe *fiber.Error
+	if errors.As(err, &fe) {
+		return helper.JsonError(c, fe.Code, fe.Message)
+	}
+	return helper.JsonError(c, fiber.StatusInternalServerError, err.Error())
+}
+
+// scope: school aktif + :id (bila ada di route).
+func scope(c *fiber.Ctx) (uuid.UUID, uuid.UUID, error) {
+	schoolID, err := permsvc.SchoolFromRequest(c)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	if c.Params("id") == "" {
+		return schoolID, uuid.Nil, nil
+	}
+	id, err := uuid.Parse(strings.TrimSpace(c.Params("id")))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fiber.NewError(fiber.StatusBadRequest, "id tidak valid")
+	}
+	return schoolID, id, nil
+}
+
+func parseTimeQuery(c *fiber.Ctx, key string) (*time.Time, error) {
+	v := strings.TrimSpace(c.Query(key))
+	if v == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, key+" harus RFC3339")
+	}
+	return &t, nil
+}
+
+/* =========================================================
+   DEVICE
+========================================================= */
+
+// GET /api/a/attendance-devices
+func (h *AttendanceDeviceController) List(c *fiber.Ctx) error {
+	schoolID, _, err := scope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	rows, err := svc.ListDevices(c.Context(), h.DB, schoolID)
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusInternalServerError, "Gagal mengambil mesin absen")
+	}
+	return helper.JsonOK(c, "OK", rows)
+}
+
+// GET /api/a/attendance-devices/:id
+func (h *AttendanceDeviceController) Detail(c *fiber.Ctx) error {
+	schoolID, id, err := scope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	m, err := svc.GetDevice(c.Context(), h.DB, schoolID, id)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonOK(c, "OK", m)
+}
+
+// POST /api/a/attendance-devices
+func (h *AttendanceDeviceController) Create(c *fiber.Ctx) error {
+	schoolID, _, err := scope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	var req dto.CreateDeviceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "Payload tidak valid")
+	}
+	m, token, err := svc.CreateDevice(c.Context(), h.DB, schoolID, req.ToInput())
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonCreated(c, "Mesin absen didaftarkan (simpan token push, tidak ditampilkan lagi)",
+		dto.DeviceWithToken{AttendanceDeviceModel: m, AttendanceDevicePushToken: token})
+}
+
+// PATCH /api/a/attendance-devices/:id
+func (h *AttendanceDeviceController) Update(c *fiber.Ctx) error {
+	schoolID, id, err := scope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	var req dto.UpdateDeviceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "Payload tidak valid")
+	}
+	m, err := svc.UpdateDevice(c.Context(), h.DB, schoolID, id, req.ToInput())
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonUpdated(c, "Mesin absen diperbarui", m)
+}
+
+// DELETE /api/a/attendance-devices/:id
+func (h *AttendanceDeviceController) Delete(c *fiber.Ctx) error {
+	schoolID, id, err := scope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	if err := svc.DeleteDevice(c.Context(), h.DB, schoolID, id); err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonDeleted(c, "Mesin absen dihapus", fiber.Map{"attendance_device_id": id})
+}
+
+// POST /api/a/attendance-devices/:id/resync
+func (h *AttendanceDeviceController) Resync(c *fiber.Ctx) error {
+	schoolID, id, err := scope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	m, err := svc.ResetStamp(c.Context(), h.DB, schoolID, id)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonUpdated(c, "Mesin akan mengirim ulang seluruh log pada koneksi berikutnya", m)
+}
+
+// POST /api/a/attendance-devices/:id/token
+func (h *AttendanceDeviceController) RotateToken(c *fiber.Ctx) error {
+	schoolID, id, err := scope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	m, token, err := svc.RotateDeviceToken(c.Context(), h.DB, schoolID, id)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonUpdated(c, "Token push diganti; perbarui URL server di mesin",
+		dto.DeviceWithToken{AttendanceDeviceModel: m, AttendanceDevicePushToken: token})
+}
+
+// POST /api/a/attendance-devices/:id/punches
+func (h *AttendanceDeviceController) Ingest(c *fiber.Ctx) error {
+	schoolID, id, err := scope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	dev, err := svc.GetDevice(c.Context(), h.DB, schoolID, id)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	if !dev.AttendanceDeviceIsActive {
+		return writeErr(c, svc.ErrDeviceInactive)
+	}
+
+	var req dto.IngestRequest
+	if err := c.BodyParser(&req); err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "Payload tidak valid")
+	}
+	if len(req.Punches) == 0 {
+		return helper.JsonError(c, fiber.StatusBadRequest, "punches wajib diisi")
+	}
+	if len(req.Punches) > dto.MaxIngestPunches {
+		return helper.JsonError(c, fiber.StatusRequestEntityTooLarge, "punches maksimal 5000 per request")
+	}
+
+	inputs, invalid := req.ToInputs(svc.SchoolLocation(c.Context(), h.DB, schoolID))
+	res, err := svc.Ingest(c.Context(), h.DB, dev, inputs, model.PunchSourceAPI)
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusInternalServerError, "Gagal menyimpan log absen")
+	}
+	res.Received += invalid
+	res.Rejected += invalid
+	svc.Touch(c.Context(), h.DB, dev, c.IP(), "", nil)
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"message": "Log absen diterima, diproses di background",
+		"data":    res,
+	})
+}
+
+/* =========================================================
+   PIN MAPPING
+========================================================= */
+
+// GET /api/a/attendance-devices/users
+func (h *AttendanceDeviceController) ListMappings(c *fiber.Ctx) error {
+	schoolID, _, err := scope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	p := helper.ResolvePaging(c, 50, 200)
+	rows, total, err := svc.ListMappings(c.Context(), h.DB, schoolID, c.Query("q"), p.Limit, p.Offset)
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusInternalServerError, "Gagal mengambil pemetaan PIN")
+	}
+	return helper.JsonList(c, "OK", rows, helper.BuildPaginationFromPage(total, p.Page, p.PerPage))
+}
+
+// POST /api/a/attendance-devices/users
+func (h *AttendanceDeviceController) UpsertMapping(c *fiber.Ctx) error {
+	schoolID, _, err := scope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	var req dto.MappingRequest
+	if err := c.BodyParser(&req); err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "Payload tidak valid")
+	}
+	m, err := svc.UpsertMapping(c.Context(), h.DB, schoolID, req.ToInput())
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonOK(c, "Pemetaan PIN disimpan", m)
+}
+
+// DELETE /api/a/attendance-devices/users/:id
+func (h *AttendanceDeviceController) DeleteMapping(c *fiber.Ctx) error {
+	schoolID, id, err := scope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	if err := svc.DeleteMapping(c.Context(), h.DB, schoolID, id); err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonDeleted(c, "Pemetaan PIN dihapus", fiber.Map{"attendance_device_user_id": id})
+}
+
+/* =========================================================
+   PUNCH LOG
+========================================================= */
+
+func punchFilterFromQuery(c *fiber.Ctx) (svc.PunchFilter, error) {
+	f := svc.PunchFilter{Status: c.Query("status"), PIN: c.Query("pin")}
+	if v := strings.TrimSpace(c.Query("device_id")); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return f, fiber.NewError(fiber.StatusBadRequest, "device_id tidak valid")
+		}
+		f.DeviceID = &id
+	}
+	var err error
+	if f.From, err = parseTimeQuery(c, "from"); err != nil {
+		return f, err
+	}
+	if f.To, err = parseTimeQuery(c, "to"); err != nil {
+		return f, err
+	}
+	return f, nil
+}
+
+// GET /api/a/attendance-devices/punches
+func (h *AttendanceDeviceController) ListPunches(c *fiber.Ctx) error {
+	schoolID, _, err := scope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	f, err := punchFilterFromQuery(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	p := helper.ResolvePaging(c, 50, 200)
+	rows, total, err := svc.ListPunches(c.Context(), h.DB, schoolID, f, p.Limit, p.Offset)
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusInternalServerError, "Gagal mengambil log absen")
+	}
+	return helper.JsonList(c, "OK", rows, helper.BuildPaginationFromPage(total, p.Page, p.PerPage))
+}
+
+// POST /api/a/attendance-devices/punches/reprocess
+func (h *AttendanceDeviceController) Reprocess(c *fiber.Ctx) error {
+	schoolID, _, err := scope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	var req dto.ReprocessRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return helper.JsonError(c, fiber.StatusBadRequest, "Payload tidak valid")
+		}
+	}
+	n, err := svc.Reprocess(c.Context(), h.DB, schoolID, req.ToFilter())
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusInternalServerError, "Gagal menjadwalkan proses ulang")
+	}
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"message": "Log absen dijadwalkan untuk diproses ulang",
+		"data":    fiber.Map{"queued": n},
+	})
+}
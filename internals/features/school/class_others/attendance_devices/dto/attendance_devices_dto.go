@@ -0,0 +1,127 @@
+// file: internals/features/school/class_others/attendance_devices/dto/attendance_devices_dto.go
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	model "madinahsalam_backend/internals/features/school/class_others/attendance_devices/model"
+	svc "madinahsalam_backend/internals/features/school/class_others/attendance_devices/service"
+)
+
+/* =========================================================
+   DEVICE
+========================================================= */
+
+type CreateDeviceRequest struct {
+	Name         string    `json:"name"`
+	SerialNumber string    `json:"serial_number"`
+	Kind         *string   `json:"kind"`
+	Location     *string   `json:"location"`
+	IsActive     *bool     `json:"is_active"`
+	AllowedIPs   *[]string `json:"allowed_ips"`
+}
+
+func (r CreateDeviceRequest) ToInput() svc.DeviceInput {
+	return svc.DeviceInput{
+		Name:         &r.Name,
+		SerialNumber: &r.SerialNumber,
+		Kind:         r.Kind,
+		Location:     r.Location,
+		IsActive:     r.IsActive,
+		AllowedIPs:   r.AllowedIPs,
+	}
+}
+
+// PATCH: field nil = tidak diubah.
+type UpdateDeviceRequest struct {
+	Name         *string   `json:"name"`
+	SerialNumber *string   `json:"serial_number"`
+	Kind         *string   `json:"kind"`
+	Location     *string   `json:"location"`
+	IsActive     *bool     `json:"is_active"`
+	AllowedIPs   *[]string `json:"allowed_ips"`
+}
+
+func (r UpdateDeviceRequest) ToInput() svc.DeviceInput {
+	return svc.DeviceInput{
+		Name:         r.Name,
+		SerialNumber: r.SerialNumber,
+		Kind:         r.Kind,
+		Location:     r.Location,
+		IsActive:     r.IsActive,
+		AllowedIPs:   r.AllowedIPs,
+	}
+}
+
+// DeviceWithToken: respons daftar / rotate; token push hanya tampil di sini.
+type DeviceWithToken struct {
+	*model.AttendanceDeviceModel
+	AttendanceDevicePushToken string `json:"attendance_device_push_token"`
+}
+
+/* =========================================================
+   PIN MAPPING
+========================================================= */
+
+type MappingRequest struct {
+	PIN             string     `json:"pin"`
+	CardNumber      *string    `json:"card_number"`
+	SchoolStudentID *uuid.UUID `json:"school_student_id"`
+	SchoolTeacherID *uuid.UUID `json:"school_teacher_id"`
+}
+
+func (r MappingRequest) ToInput() svc.MappingInput {
+	return svc.MappingInput{
+		PIN:             r.PIN,
+		CardNumber:      r.CardNumber,
+		SchoolStudentID: r.SchoolStudentID,
+		SchoolTeacherID: r.SchoolTeacherID,
+	}
+}
+
+/* =========================================================
+   INGEST (JSON; untuk bridge/middleware yang menarik log dari mesin)
+========================================================= */
+
+type PunchRequest struct {
+	PIN string `json:"pin"`
+	// RFC3339, atau "YYYY-MM-DD HH:MM:SS" = jam lokal sekolah
+	PunchedAt string `json:"punched_at"`
+	State     *int16 `json:"state"`
+	Verify    *int16 `json:"verify"`
+}
+
+type IngestRequest struct {
+	Punches []PunchRequest `json:"punches"`
+}
+
+// MaxIngestPunches: batas per request (backfill besar → pecah beberapa request).
+const MaxIngestPunches = 5000
+
+// ToInputs: punched_at yang tidak bisa diparse dihitung invalid.
+func (r IngestRequest) ToInputs(loc *time.Location) (out []svc.PunchInput, invalid int) {
+	out = make([]svc.PunchInput, 0, len(r.Punches))
+	for _, p := range r.Punches {
+		at, ok := svc.ParsePunchTime(p.PunchedAt, loc)
+		if !ok {
+			invalid++
+			continue
+		}
+		out = append(out, svc.PunchInput{PIN: p.PIN, PunchedAt: at, State: p.State, Verify: p.Verify})
+	}
+	return out, invalid
+}
+
+type ReprocessRequest struct {
+	DeviceID *uuid.UUID `json:"device_id"`
+	Status   string     `json:"status"`
+	PIN      string     `json:"pin"`
+	From     *time.Time `json:"from"`
+	To       *time.Time `json:"to"`
+}
+
+func (r ReprocessRequest) ToFilter() svc.PunchFilter {
+	return svc.PunchFilter{DeviceID: r.DeviceID, Status: r.Status, PIN: r.PIN, From: r.From, To: r.To}
+}
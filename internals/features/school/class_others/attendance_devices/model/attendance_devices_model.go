@@ -0,0 +1,100 @@
+// file: internals/features/school/class_others/attendance_devices/model/attendance_devices_model.go
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+const (
+	DeviceKindFingerprint = "fingerprint"
+	DeviceKindRFID        = "rfid"
+	DeviceKindFace        = "face"
+	DeviceKindOther       = "other"
+)
+
+var DeviceKinds = []string{DeviceKindFingerprint, DeviceKindRFID, DeviceKindFace, DeviceKindOther}
+
+const (
+	PunchSourceADMS = "adms"
+	PunchSourceAPI  = "api"
+)
+
+type PunchStatus string
+
+const (
+	PunchPending   PunchStatus = "pending"
+	PunchApplied   PunchStatus = "applied"    // minimal 1 participant terisi
+	PunchUnmapped  PunchStatus = "unmapped"   // PIN belum dipetakan ke siswa/guru
+	PunchNoSession PunchStatus = "no_session" // tidak ada sesi hari itu
+	PunchSkipped   PunchStatus = "skipped"    // sesi terkunci/ditutup/sudah lewat
+	PunchFailed    PunchStatus = "failed"
+)
+
+// AttendanceDeviceModel: mesin absen milik sekolah (dikenali dari serial number).
+type AttendanceDeviceModel struct {
+	AttendanceDeviceID           uuid.UUID `gorm:"column:attendance_device_id;type:uuid;default:gen_random_uuid();primaryKey" json:"attendance_device_id"`
+	AttendanceDeviceSchoolID     uuid.UUID `gorm:"column:attendance_device_school_id;type:uuid;not null" json:"attendance_device_school_id"`
+	AttendanceDeviceName         string    `gorm:"column:attendance_device_name;type:varchar(120);not null" json:"attendance_device_name"`
+	AttendanceDeviceSerialNumber string    `gorm:"column:attendance_device_serial_number;type:varchar(64);not null" json:"attendance_device_serial_number"`
+	AttendanceDeviceKind         string    `gorm:"column:attendance_device_kind;type:varchar(16);not null;default:'fingerprint'" json:"attendance_device_kind"`
+	AttendanceDeviceLocation     *string   `gorm:"column:attendance_device_location;type:varchar(160)" json:"attendance_device_location,omitempty"`
+	AttendanceDeviceIsActive     bool      `gorm:"column:attendance_device_is_active;not null;default:true" json:"attendance_device_is_active"`
+
+	// Autentikasi push ADMS: token (hash saja) + allowlist IP sumber (kosong = bebas)
+	AttendanceDeviceTokenHash  *string        `gorm:"column:attendance_device_token_hash;type:char(64)" json:"-"`
+	AttendanceDeviceAllowedIPs pq.StringArray `gorm:"column:attendance_device_allowed_ips;type:text[];not null;default:'{}'" json:"attendance_device_allowed_ips"`
+
+	AttendanceDeviceAttlogStamp int64      `gorm:"column:attendance_device_attlog_stamp;not null;default:0" json:"attendance_device_attlog_stamp"`
+	AttendanceDeviceLastSeenAt  *time.Time `gorm:"column:attendance_device_last_seen_at" json:"attendance_device_last_seen_at,omitempty"`
+	AttendanceDeviceLastIP      *string    `gorm:"column:attendance_device_last_ip;type:varchar(64)" json:"attendance_device_last_ip,omitempty"`
+	AttendanceDeviceFirmware    *string    `gorm:"column:attendance_device_firmware;type:varchar(80)" json:"attendance_device_firmware,omitempty"`
+
+	AttendanceDeviceCreatedAt time.Time      `gorm:"column:attendance_device_created_at;autoCreateTime" json:"attendance_device_created_at"`
+	AttendanceDeviceUpdatedAt time.Time      `gorm:"column:attendance_device_updated_at;autoUpdateTime" json:"attendance_device_updated_at"`
+	AttendanceDeviceDeletedAt gorm.DeletedAt `gorm:"column:attendance_device_deleted_at;index" json:"-"`
+}
+
+func (AttendanceDeviceModel) TableName() string { return "attendance_devices" }
+
+// AttendanceDeviceUserModel: PIN mesin → siswa XOR guru (berlaku di semua mesin sekolah).
+type AttendanceDeviceUserModel struct {
+	AttendanceDeviceUserID              uuid.UUID  `gorm:"column:attendance_device_user_id;type:uuid;default:gen_random_uuid();primaryKey" json:"attendance_device_user_id"`
+	AttendanceDeviceUserSchoolID        uuid.UUID  `gorm:"column:attendance_device_user_school_id;type:uuid;not null" json:"attendance_device_user_school_id"`
+	AttendanceDeviceUserPIN             string     `gorm:"column:attendance_device_user_pin;type:varchar(32);not null" json:"attendance_device_user_pin"`
+	AttendanceDeviceUserCardNumber      *string    `gorm:"column:attendance_device_user_card_number;type:varchar(64)" json:"attendance_device_user_card_number,omitempty"`
+	AttendanceDeviceUserSchoolStudentID *uuid.UUID `gorm:"column:attendance_device_user_school_student_id;type:uuid" json:"attendance_device_user_school_student_id,omitempty"`
+	AttendanceDeviceUserSchoolTeacherID *uuid.UUID `gorm:"column:attendance_device_user_school_teacher_id;type:uuid" json:"attendance_device_user_school_teacher_id,omitempty"`
+
+	AttendanceDeviceUserCreatedAt time.Time      `gorm:"column:attendance_device_user_created_at;autoCreateTime" json:"attendance_device_user_created_at"`
+	AttendanceDeviceUserUpdatedAt time.Time      `gorm:"column:attendance_device_user_updated_at;autoUpdateTime" json:"attendance_device_user_updated_at"`
+	AttendanceDeviceUserDeletedAt gorm.DeletedAt `gorm:"column:attendance_device_user_deleted_at;index" json:"-"`
+}
+
+func (AttendanceDeviceUserModel) TableName() string { return "attendance_device_users" }
+
+// AttendanceDevicePunchModel: 1 tap mentah dari mesin + hasil prosesnya.
+type AttendanceDevicePunchModel struct {
+	AttendanceDevicePunchID        uuid.UUID `gorm:"column:attendance_device_punch_id;type:uuid;default:gen_random_uuid();primaryKey" json:"attendance_device_punch_id"`
+	AttendanceDevicePunchSchoolID  uuid.UUID `gorm:"column:attendance_device_punch_school_id;type:uuid;not null" json:"attendance_device_punch_school_id"`
+	AttendanceDevicePunchDeviceID  uuid.UUID `gorm:"column:attendance_device_punch_device_id;type:uuid;not null" json:"attendance_device_punch_device_id"`
+	AttendanceDevicePunchPIN       string    `gorm:"column:attendance_device_punch_pin;type:varchar(32);not null" json:"attendance_device_punch_pin"`
+	AttendanceDevicePunchPunchedAt time.Time `gorm:"column:attendance_device_punch_punched_at;not null" json:"attendance_device_punch_punched_at"`
+	AttendanceDevicePunchState     *int16    `gorm:"column:attendance_device_punch_state" json:"attendance_device_punch_state,omitempty"`
+	AttendanceDevicePunchVerify    *int16    `gorm:"column:attendance_device_punch_verify" json:"attendance_device_punch_verify,omitempty"`
+	AttendanceDevicePunchSource    string    `gorm:"column:attendance_device_punch_source;type:varchar(8);not null;default:'adms'" json:"attendance_device_punch_source"`
+
+	AttendanceDevicePunchStatus          PunchStatus `gorm:"column:attendance_device_punch_status;type:varchar(16);not null;default:'pending'" json:"attendance_device_punch_status"`
+	AttendanceDevicePunchPersonKind      *string     `gorm:"column:attendance_device_punch_person_kind;type:varchar(16)" json:"attendance_device_punch_person_kind,omitempty"`
+	AttendanceDevicePunchSchoolStudentID *uuid.UUID  `gorm:"column:attendance_device_punch_school_student_id;type:uuid" json:"attendance_device_punch_school_student_id,omitempty"`
+	AttendanceDevicePunchSchoolTeacherID *uuid.UUID  `gorm:"column:attendance_device_punch_school_teacher_id;type:uuid" json:"attendance_device_punch_school_teacher_id,omitempty"`
+	AttendanceDevicePunchAppliedCount    int         `gorm:"column:attendance_device_punch_applied_count;not null;default:0" json:"attendance_device_punch_applied_count"`
+	AttendanceDevicePunchError           *string     `gorm:"column:attendance_device_punch_error" json:"attendance_device_punch_error,omitempty"`
+	AttendanceDevicePunchProcessedAt     *time.Time  `gorm:"column:attendance_device_punch_processed_at" json:"attendance_device_punch_processed_at,omitempty"`
+	AttendanceDevicePunchCreatedAt       time.Time   `gorm:"column:attendance_device_punch_created_at;autoCreateTime" json:"attendance_device_punch_created_at"`
+}
+
+func (AttendanceDevicePunchModel) TableName() string { return "attendance_device_punches" }
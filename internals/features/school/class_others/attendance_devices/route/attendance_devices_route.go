@@ -0,0 +1,48 @@
+// file: internals/features/school/class_others/attendance_devices/route/attendance_devices_route.go
+package route
+
+import (
+	deviceController "madinahsalam_backend/internals/features/school/class_others/attendance_devices/controller"
+	schoolkuMiddleware "madinahsalam_backend/internals/middlewares/features"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// /api/a/attendance-devices → kelola mesin absen, pemetaan PIN & log tap
+func AttendanceDeviceAdminRoutes(api fiber.Router, db *gorm.DB) {
+	ctl := deviceController.NewAttendanceDeviceController(db)
+
+	read := schoolkuMiddleware.RequirePermission("attendance_devices.read")
+	write := schoolkuMiddleware.RequirePermission("attendance_devices.write")
+	del := schoolkuMiddleware.RequirePermission("attendance_devices.delete")
+
+	g := api.Group("/attendance-devices")
+
+	g.Get("/users", read, ctl.ListMappings)
+	g.Post("/users", write, ctl.UpsertMapping)
+	g.Delete("/users/:id", del, ctl.DeleteMapping)
+
+	g.Get("/punches", read, ctl.ListPunches)
+	g.Post("/punches/reprocess", write, ctl.Reprocess)
+
+	g.Get("/", read, ctl.List)
+	g.Post("/", write, ctl.Create)
+	g.Get("/:id", read, ctl.Detail)
+	g.Patch("/:id", write, ctl.Update)
+	g.Delete("/:id", del, ctl.Delete)
+	g.Post("/:id/resync", write, ctl.Resync)
+	g.Post("/:id/token", write, ctl.RotateToken)
+	g.Post("/:id/punches", write, ctl.Ingest)
+}
+
+// /iclock → endpoint push mesin (ADMS); tanpa JWT, dikenali dari SN + token push (+ allowlist IP)
+func AttendanceDeviceADMSRoutes(app fiber.Router, db *gorm.DB) {
+	ctl := deviceController.NewADMSController(db)
+
+	g := app.Group("/iclock")
+	g.Get("/cdata", ctl.Handshake)
+	g.Post("/cdata", ctl.Upload)
+	g.Get("/getrequest", ctl.Ack)
+	g.Post("/devicecmd", ctl.Ack)
+}
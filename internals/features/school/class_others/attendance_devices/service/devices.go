@@ -0,0 +1,326 @@
+// file: internals/features/school/class_others/attendance_devices/service/devices.go
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+
+	apikeysvc "madinahsalam_backend/internals/features/lembaga/api_keys/service"
+	model "madinahsalam_backend/internals/features/school/class_others/attendance_devices/model"
+)
+
+var (
+	ErrDeviceNotFound      = errors.New("mesin absen tidak ditemukan")
+	ErrDeviceInactive      = errors.New("mesin absen tidak aktif")
+	ErrSerialTaken         = errors.New("serial number mesin sudah terdaftar")
+	ErrSerialEmpty         = errors.New("serial number mesin wajib diisi")
+	ErrNameEmpty           = errors.New("nama mesin wajib diisi")
+	ErrInvalidKind         = errors.New("jenis mesin harus fingerprint/rfid/face/other")
+	ErrMappingNotFound     = errors.New("pemetaan PIN tidak ditemukan")
+	ErrPINEmpty            = errors.New("PIN mesin wajib diisi")
+	ErrMappingTarget       = errors.New("isi salah satu: school_student_id atau school_teacher_id")
+	ErrPINTaken            = errors.New("PIN sudah dipakai orang lain di sekolah ini")
+	ErrPersonAlreadyHasPIN = errors.New("siswa/guru ini sudah punya PIN lain")
+	ErrPersonNotInSchool   = errors.New("siswa/guru tidak terdaftar di sekolah ini")
+)
+
+func isUniqueViolation(err error) bool {
+	if err == nil {
+		return false
+	}
+	s := strings.ToLower(err.Error())
+	return strings.Contains(s, "duplicate key") || strings.Contains(s, "sqlstate 23505")
+}
+
+func normSerial(s string) string { return strings.ToUpper(strings.TrimSpace(s)) }
+
+func validKind(k string) bool {
+	for _, v := range model.DeviceKinds {
+		if v == k {
+			return true
+		}
+	}
+	return false
+}
+
+/* =========================================================
+   DEVICE
+========================================================= */
+
+type DeviceInput struct {
+	Name         *string
+	SerialNumber *string
+	Kind         *string
+	Location     *string
+	IsActive     *bool
+	AllowedIPs   *[]string // nil = tidak diubah; [] = bebas
+}
+
+func ListDevices(ctx context.Context, db *gorm.DB, schoolID uuid.UUID) ([]model.AttendanceDeviceModel, error) {
+	var rows []model.AttendanceDeviceModel
+	err := db.WithContext(ctx).
+		Where("attendance_device_school_id = ?", schoolID).
+		Order("attendance_device_created_at ASC").
+		Find(&rows).Error
+	return rows, err
+}
+
+func GetDevice(ctx context.Context, db *gorm.DB, schoolID, id uuid.UUID) (*model.AttendanceDeviceModel, error) {
+	var m model.AttendanceDeviceModel
+	err := db.WithContext(ctx).
+		Where("attendance_device_id = ? AND attendance_device_school_id = ?", id, schoolID).
+		Take(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrDeviceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// FindDeviceBySerial: dipakai listener ADMS (mesin hanya mengirim SN).
+func FindDeviceBySerial(ctx context.Context, db *gorm.DB, serial string) (*model.AttendanceDeviceModel, error) {
+	serial = normSerial(serial)
+	if serial == "" {
+		return nil, ErrDeviceNotFound
+	}
+	var m model.AttendanceDeviceModel
+	err := db.WithContext(ctx).
+		Where("upper(attendance_device_serial_number) = ?", serial).
+		Take(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrDeviceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !m.AttendanceDeviceIsActive {
+		return nil, ErrDeviceInactive
+	}
+	return &m, nil
+}
+
+// CreateDevice: token push mentah dikembalikan sekali (hanya hash yang disimpan).
+func CreateDevice(ctx context.Context, db *gorm.DB, schoolID uuid.UUID, in DeviceInput) (*model.AttendanceDeviceModel, string, error) {
+	m := &model.AttendanceDeviceModel{
+		AttendanceDeviceSchoolID:   schoolID,
+		AttendanceDeviceKind:       model.DeviceKindFingerprint,
+		AttendanceDeviceIsActive:   true,
+		AttendanceDeviceAllowedIPs: pq.StringArray{},
+	}
+	if in.Name == nil || strings.TrimSpace(*in.Name) == "" {
+		return nil, "", ErrNameEmpty
+	}
+	if in.SerialNumber == nil || normSerial(*in.SerialNumber) == "" {
+		return nil, "", ErrSerialEmpty
+	}
+	if err := applyDeviceInput(m, in); err != nil {
+		return nil, "", err
+	}
+	raw, hash, err := generateDeviceToken()
+	if err != nil {
+		return nil, "", err
+	}
+	m.AttendanceDeviceTokenHash = &hash
+	if err := db.WithContext(ctx).Create(m).Error; err != nil {
+		if isUniqueViolation(err) {
+			return nil, "", ErrSerialTaken
+		}
+		return nil, "", err
+	}
+	return m, raw, nil
+}
+
+func UpdateDevice(ctx context.Context, db *gorm.DB, schoolID, id uuid.UUID, in DeviceInput) (*model.AttendanceDeviceModel, error) {
+	m, err := GetDevice(ctx, db, schoolID, id)
+	if err != nil {
+		return nil, err
+	}
+	if in.Name != nil && strings.TrimSpace(*in.Name) == "" {
+		return nil, ErrNameEmpty
+	}
+	if in.SerialNumber != nil && normSerial(*in.SerialNumber) == "" {
+		return nil, ErrSerialEmpty
+	}
+	if err := applyDeviceInput(m, in); err != nil {
+		return nil, err
+	}
+	if err := db.WithContext(ctx).Save(m).Error; err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrSerialTaken
+		}
+		return nil, err
+	}
+	return m, nil
+}
+
+func applyDeviceInput(m *model.AttendanceDeviceModel, in DeviceInput) error {
+	if in.Name != nil {
+		m.AttendanceDeviceName = strings.TrimSpace(*in.Name)
+	}
+	if in.SerialNumber != nil {
+		m.AttendanceDeviceSerialNumber = normSerial(*in.SerialNumber)
+	}
+	if in.Kind != nil {
+		k := strings.ToLower(strings.TrimSpace(*in.Kind))
+		if !validKind(k) {
+			return ErrInvalidKind
+		}
+		m.AttendanceDeviceKind = k
+	}
+	if in.Location != nil {
+		if loc := strings.TrimSpace(*in.Location); loc != "" {
+			m.AttendanceDeviceLocation = &loc
+		} else {
+			m.AttendanceDeviceLocation = nil
+		}
+	}
+	if in.IsActive != nil {
+		m.AttendanceDeviceIsActive = *in.IsActive
+	}
+	if in.AllowedIPs != nil {
+		ips, err := apikeysvc.NormalizeIPs(*in.AllowedIPs)
+		if err != nil {
+			return err
+		}
+		m.AttendanceDeviceAllowedIPs = pq.StringArray(ips)
+	}
+	return nil
+}
+
+func DeleteDevice(ctx context.Context, db *gorm.DB, schoolID, id uuid.UUID) error {
+	m, err := GetDevice(ctx, db, schoolID, id)
+	if err != nil {
+		return err
+	}
+	return db.WithContext(ctx).Delete(m).Error
+}
+
+// ResetStamp: ATTLOGStamp=0 → pada handshake berikutnya mesin mengirim ulang seluruh log
+// (backfill setelah mesin offline lama / ganti server). Duplikat dibuang oleh dedupe.
+func ResetStamp(ctx context.Context, db *gorm.DB, schoolID, id uuid.UUID) (*model.AttendanceDeviceModel, error) {
+	m, err := GetDevice(ctx, db, schoolID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.WithContext(ctx).Model(m).Updates(map[string]any{
+		"attendance_device_attlog_stamp": 0,
+		"attendance_device_updated_at":   time.Now(),
+	}).Error; err != nil {
+		return nil, err
+	}
+	m.AttendanceDeviceAttlogStamp = 0
+	return m, nil
+}
+
+/* =========================================================
+   PIN MAPPING
+========================================================= */
+
+type MappingInput struct {
+	PIN             string
+	CardNumber      *string
+	SchoolStudentID *uuid.UUID
+	SchoolTeacherID *uuid.UUID
+}
+
+func ListMappings(ctx context.Context, db *gorm.DB, schoolID uuid.UUID, q string, limit, offset int) ([]model.AttendanceDeviceUserModel, int64, error) {
+	tx := db.WithContext(ctx).Model(&model.AttendanceDeviceUserModel{}).
+		Where("attendance_device_user_school_id = ?", schoolID)
+	if q = strings.TrimSpace(q); q != "" {
+		tx = tx.Where("attendance_device_user_pin ILIKE ? OR attendance_device_user_card_number ILIKE ?", q+"%", q+"%")
+	}
+	var total int64
+	if err := tx.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var rows []model.AttendanceDeviceUserModel
+	err := tx.Order("attendance_device_user_pin ASC").Limit(limit).Offset(offset).Find(&rows).Error
+	return rows, total, err
+}
+
+// UpsertMapping: PIN yang sama → target diganti; orang yang sama pindah PIN → ditolak (hapus dulu).
+func UpsertMapping(ctx context.Context, db *gorm.DB, schoolID uuid.UUID, in MappingInput) (*model.AttendanceDeviceUserModel, error) {
+	pin := strings.TrimSpace(in.PIN)
+	if pin == "" || len(pin) > 32 {
+		return nil, ErrPINEmpty
+	}
+	hasStudent := in.SchoolStudentID != nil && *in.SchoolStudentID != uuid.Nil
+	hasTeacher := in.SchoolTeacherID != nil && *in.SchoolTeacherID != uuid.Nil
+	if hasStudent == hasTeacher {
+		return nil, ErrMappingTarget
+	}
+
+	var cnt int64
+	if hasStudent {
+		db.WithContext(ctx).Table("school_students").
+			Where("school_student_id = ? AND school_student_school_id = ? AND school_student_deleted_at IS NULL", *in.SchoolStudentID, schoolID).
+			Count(&cnt)
+	} else {
+		db.WithContext(ctx).Table("school_teachers").
+			Where("school_teacher_id = ? AND school_teacher_school_id = ? AND school_teacher_deleted_at IS NULL", *in.SchoolTeacherID, schoolID).
+			Count(&cnt)
+	}
+	if cnt == 0 {
+		return nil, ErrPersonNotInSchool
+	}
+
+	var out model.AttendanceDeviceUserModel
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var cur model.AttendanceDeviceUserModel
+		err := tx.Where("attendance_device_user_school_id = ? AND attendance_device_user_pin = ?", schoolID, pin).
+			Take(&cur).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			cur = model.AttendanceDeviceUserModel{AttendanceDeviceUserSchoolID: schoolID, AttendanceDeviceUserPIN: pin}
+		case err != nil:
+			return err
+		}
+		cur.AttendanceDeviceUserCardNumber = in.CardNumber
+		cur.AttendanceDeviceUserSchoolStudentID, cur.AttendanceDeviceUserSchoolTeacherID = nil, nil
+		if hasStudent {
+			cur.AttendanceDeviceUserSchoolStudentID = in.SchoolStudentID
+		} else {
+			cur.AttendanceDeviceUserSchoolTeacherID = in.SchoolTeacherID
+		}
+		if err := tx.Save(&cur).Error; err != nil {
+			if isUniqueViolation(err) {
+				return ErrPersonAlreadyHasPIN
+			}
+			return err
+		}
+		out = cur
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// tap yang tadinya belum terpetakan untuk PIN ini → proses ulang
+	_ = db.WithContext(ctx).Model(&model.AttendanceDevicePunchModel{}).
+		Where("attendance_device_punch_school_id = ? AND attendance_device_punch_pin = ? AND attendance_device_punch_status = ?",
+			schoolID, pin, model.PunchUnmapped).
+		Update("attendance_device_punch_status", model.PunchPending).Error
+
+	return &out, nil
+}
+
+func DeleteMapping(ctx context.Context, db *gorm.DB, schoolID, id uuid.UUID) error {
+	res := db.WithContext(ctx).
+		Where("attendance_device_user_id = ? AND attendance_device_user_school_id = ?", id, schoolID).
+		Delete(&model.AttendanceDeviceUserModel{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrMappingNotFound
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+// file: internals/features/school/class_others/attendance_devices/service/ingest.go
+package service
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	model "madinahsalam_backend/internals/features/school/class_others/attendance_devices/model"
+)
+
+/* =========================================================
+   Ingest tap mentah
+   - simpan dulu (status pending), worker yang menerapkan ke sesi
+   - dedupe (device, pin, punched_at) → kirim ulang / backfill aman
+========================================================= */
+
+const (
+	ingestChunk = 500
+
+	// batas umur log backfill & toleransi jam mesin yang kecepetan
+	maxBackfillAge = 366 * 24 * time.Hour
+	maxClockSkew   = 10 * time.Minute
+)
+
+type PunchInput struct {
+	PIN       string
+	PunchedAt time.Time
+	State     *int16
+	Verify    *int16
+}
+
+type IngestResult struct {
+	Received   int `json:"received"`
+	Accepted   int `json:"accepted"`
+	Duplicates int `json:"duplicates"`
+	Rejected   int `json:"rejected"`
+}
+
+// SchoolLocation: timezone sekolah (schools.school_timezone) → fallback Asia/Jakarta.
+func SchoolLocation(ctx context.Context, db *gorm.DB, schoolID uuid.UUID) *time.Location {
+	var tz *string
+	_ = db.WithContext(ctx).Table("schools").
+		Select("school_timezone").
+		Where("school_id = ?", schoolID).
+		Scan(&tz).Error
+	if tz != nil && strings.TrimSpace(*tz) != "" {
+		if loc, err := time.LoadLocation(strings.TrimSpace(*tz)); err == nil {
+			return loc
+		}
+	}
+	if loc, err := time.LoadLocation("Asia/Jakarta"); err == nil {
+		return loc
+	}
+	return time.FixedZone("WIB", 7*3600)
+}
+
+// Ingest menyimpan tap untuk satu mesin. Tap tidak valid (PIN kosong, waktu di luar batas) dihitung rejected.
+func Ingest(ctx context.Context, db *gorm.DB, dev *model.AttendanceDeviceModel, in []PunchInput, source string) (IngestResult, error) {
+	res := IngestResult{Received: len(in)}
+	now := time.Now().UTC()
+
+	rows := make([]model.AttendanceDevicePunchModel, 0, len(in))
+	for _, p := range in {
+		pin := strings.TrimSpace(p.PIN)
+		at := p.PunchedAt.UTC()
+		if pin == "" || len(pin) > 32 || at.IsZero() || at.After(now.Add(maxClockSkew)) || now.Sub(at) > maxBackfillAge {
+			res.Rejected++
+			continue
+		}
+		rows = append(rows, model.AttendanceDevicePunchModel{
+			AttendanceDevicePunchSchoolID:  dev.AttendanceDeviceSchoolID,
+			AttendanceDevicePunchDeviceID:  dev.AttendanceDeviceID,
+			AttendanceDevicePunchPIN:       pin,
+			AttendanceDevicePunchPunchedAt: at.Truncate(time.Second),
+			AttendanceDevicePunchState:     p.State,
+			AttendanceDevicePunchVerify:    p.Verify,
+			AttendanceDevicePunchSource:    source,
+			AttendanceDevicePunchStatus:    model.PunchPending,
+		})
+	}
+
+	for start := 0; start < len(rows); start += ingestChunk {
+		end := min(start+ingestChunk, len(rows))
+		chunk := rows[start:end]
+		r := db.WithContext(ctx).
+			Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&chunk)
+		if r.Error != nil {
+			return res, r.Error
+		}
+		res.Accepted += int(r.RowsAffected)
+		res.Duplicates += len(chunk) - int(r.RowsAffected)
+	}
+	return res, nil
+}
+
+// Touch: catat mesin terakhir terlihat (+ stamp ATTLOG bila dikirim mesin).
+func Touch(ctx context.Context, db *gorm.DB, dev *model.AttendanceDeviceModel, ip, firmware string, stamp *int64) {
+	upd := map[string]any{
+		"attendance_device_last_seen_at": time.Now().UTC(),
+		"attendance_device_last_ip":      ip,
+	}
+	if firmware = strings.TrimSpace(firmware); firmware != "" {
+		if len(firmware) > 80 {
+			firmware = firmware[:80]
+		}
+		upd["attendance_device_firmware"] = firmware
+	}
+	if stamp != nil && *stamp > dev.AttendanceDeviceAttlogStamp {
+		upd["attendance_device_attlog_stamp"] = *stamp
+	}
+	_ = db.WithContext(ctx).Model(&model.AttendanceDeviceModel{}).
+		Where("attendance_device_id = ?", dev.AttendanceDeviceID).
+		Updates(upd).Error
+}
+
+/* =========================================================
+   ADMS (ZKTeco push) — tabel ATTLOG
+   baris: PIN \t YYYY-MM-DD HH:MM:SS \t state \t verify \t workcode ...
+   waktu mesin = jam lokal sekolah (tanpa zona)
+========================================================= */
+
+func ParseATTLOG(body string, loc *time.Location) (out []PunchInput, invalid int) {
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		f := strings.Split(line, "\t")
+		if len(f) < 2 {
+			invalid++
+			continue
+		}
+		at, err := time.ParseInLocation("2006-01-02 15:04:05", strings.TrimSpace(f[1]), loc)
+		if err != nil {
+			invalid++
+			continue
+		}
+		p := PunchInput{PIN: strings.TrimSpace(f[0]), PunchedAt: at}
+		if len(f) > 2 {
+			p.State = parseSmall(f[2])
+		}
+		if len(f) > 3 {
+			p.Verify = parseSmall(f[3])
+		}
+		out = append(out, p)
+	}
+	return out, invalid
+}
+
+func parseSmall(s string) *int16 {
+	n, err := strconv.ParseInt(strings.TrimSpace(s), 10, 16)
+	if err != nil {
+		return nil
+	}
+	v := int16(n)
+	return &v
+}
+
+// ParsePunchTime: RFC3339 (dengan zona) atau "YYYY-MM-DD HH:MM:SS" (jam lokal sekolah).
+func ParsePunchTime(s string, loc *time.Location) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, true
+	}
+	if t, err := time.ParseInLocation("2006-01-02 15:04:05", s, loc); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,395 @@
+// file: internals/features/school/class_others/attendance_devices/service/process.go
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	model "madinahsalam_backend/internals/features/school/class_others/attendance_devices/model"
+	attendanceModel "madinahsalam_backend/internals/features/school/class_others/class_attendance_sessions/model"
+	snapSvc "madinahsalam_backend/internals/features/users/users/service"
+)
+
+/* =========================================================
+   Terapkan tap ke class_attendance_session_participants
+   - PIN → siswa/guru (attendance_device_users, fallback kode siswa/guru)
+   - tanggal lokal tap → sesi hari itu milik orang tsb
+     (siswa: lewat student_csst; guru: guru sesi / guru CSST)
+   - tap paling awal = checkin, paling akhir = checkout (urutan tap bebas → aman untuk backfill)
+   - state present/late dihitung dari checkin vs jam mulai sesi;
+     excused/sick/leave & penandaan manual tidak ditimpa
+========================================================= */
+
+// tap beruntun (dobel tempel jari) tidak dianggap checkout
+const minCheckoutGap = 2 * time.Minute
+
+func lateGrace() time.Duration {
+	if v, err := strconv.Atoi(strings.TrimSpace(os.Getenv("ATTENDANCE_DEVICE_LATE_GRACE_MINUTES"))); err == nil && v >= 0 {
+		return time.Duration(v) * time.Minute
+	}
+	return 5 * time.Minute
+}
+
+type person struct {
+	Kind      attendanceModel.ParticipantKind
+	StudentID *uuid.UUID
+	TeacherID *uuid.UUID
+}
+
+type sessionRow struct {
+	ID               uuid.UUID  `gorm:"column:class_attendance_session_id"`
+	StartsAt         *time.Time `gorm:"column:class_attendance_session_starts_at"`
+	EndsAt           *time.Time `gorm:"column:class_attendance_session_ends_at"`
+	Locked           bool       `gorm:"column:class_attendance_session_locked"`
+	AttendanceStatus string     `gorm:"column:class_attendance_session_attendance_status"`
+}
+
+func resolvePerson(ctx context.Context, db *gorm.DB, schoolID uuid.UUID, pin string) (*person, error) {
+	var mp model.AttendanceDeviceUserModel
+	err := db.WithContext(ctx).
+		Where("attendance_device_user_school_id = ? AND attendance_device_user_pin = ?", schoolID, pin).
+		Take(&mp).Error
+	switch {
+	case err == nil:
+		if mp.AttendanceDeviceUserSchoolStudentID != nil {
+			return &person{Kind: attendanceModel.ParticipantKindStudent, StudentID: mp.AttendanceDeviceUserSchoolStudentID}, nil
+		}
+		return &person{Kind: attendanceModel.ParticipantKindTeacher, TeacherID: mp.AttendanceDeviceUserSchoolTeacherID}, nil
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, err
+	}
+
+	// fallback: PIN mesin = kode siswa / kode guru
+	var ids []uuid.UUID
+	if err := db.WithContext(ctx).Table("school_students").
+		Where("school_student_school_id = ? AND lower(school_student_code) = lower(?) AND school_student_deleted_at IS NULL", schoolID, pin).
+		Limit(2).Pluck("school_student_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	if len(ids) == 1 {
+		return &person{Kind: attendanceModel.ParticipantKindStudent, StudentID: &ids[0]}, nil
+	}
+	ids = ids[:0]
+	if err := db.WithContext(ctx).Table("school_teachers").
+		Where("school_teacher_school_id = ? AND lower(school_teacher_code) = lower(?) AND school_teacher_deleted_at IS NULL", schoolID, pin).
+		Limit(2).Pluck("school_teacher_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	if len(ids) == 1 {
+		return &person{Kind: attendanceModel.ParticipantKindTeacher, TeacherID: &ids[0]}, nil
+	}
+	return nil, nil
+}
+
+func sessionsFor(ctx context.Context, db *gorm.DB, schoolID uuid.UUID, p *person, day time.Time) ([]sessionRow, error) {
+	q := db.WithContext(ctx).
+		Table("class_attendance_sessions").
+		Select(`class_attendance_session_id, class_attendance_session_starts_at, class_attendance_session_ends_at,
+		        class_attendance_session_locked, class_attendance_session_attendance_status`).
+		Where(`class_attendance_session_school_id = ?
+		   AND class_attendance_session_date = ?
+		   AND class_attendance_session_deleted_at IS NULL
+		   AND NOT class_attendance_session_is_canceled`, schoolID, day.Format("2006-01-02"))
+
+	if p.StudentID != nil {
+		q = q.Where(`class_attendance_session_csst_id IN (
+			SELECT student_csst_csst_id FROM student_class_section_subject_teachers
+			 WHERE student_csst_school_id = ?
+			   AND student_csst_student_id = ?
+			   AND student_csst_is_active = TRUE
+			   AND student_csst_deleted_at IS NULL
+			   AND (student_csst_from IS NULL OR student_csst_from <= ?)
+			   AND (student_csst_to   IS NULL OR student_csst_to   >= ?))`,
+			schoolID, *p.StudentID, day, day)
+	} else {
+		q = q.Where(`(class_attendance_session_teacher_id = ?
+			OR (class_attendance_session_teacher_id IS NULL AND class_attendance_session_csst_id IN (
+				SELECT csst_id FROM class_section_subject_teachers
+				 WHERE csst_school_id = ? AND csst_school_teacher_id = ? AND csst_deleted_at IS NULL)))`,
+			*p.TeacherID, schoolID, *p.TeacherID)
+	}
+
+	var rows []sessionRow
+	err := q.Order("class_attendance_session_starts_at ASC NULLS LAST").Scan(&rows).Error
+	return rows, err
+}
+
+// stateFor: present / late (+ detik terlambat) dari checkin vs jam mulai sesi.
+func stateFor(s sessionRow, checkin time.Time) (attendanceModel.AttendanceState, *int) {
+	if s.StartsAt == nil || !checkin.After(s.StartsAt.Add(lateGrace())) {
+		return attendanceModel.AttendanceStatePresent, nil
+	}
+	sec := int(checkin.Sub(*s.StartsAt).Seconds())
+	return attendanceModel.AttendanceStateLate, &sec
+}
+
+// device boleh mengubah state bila belum ditandai manual
+func deviceOwnsState(m *attendanceModel.ClassAttendanceSessionParticipantModel) bool {
+	switch m.ClassAttendanceSessionParticipantState {
+	case attendanceModel.AttendanceStateUnmarked, attendanceModel.AttendanceStateAbsent:
+		return true
+	case attendanceModel.AttendanceStatePresent, attendanceModel.AttendanceStateLate:
+		return m.ClassAttendanceSessionParticipantMethod != nil &&
+			*m.ClassAttendanceSessionParticipantMethod == attendanceModel.ParticipantMethodDevice
+	}
+	return false
+}
+
+// applyToSession: true bila participant dibuat/diubah.
+func applyToSession(ctx context.Context, tx *gorm.DB, schoolID uuid.UUID, p *person, s sessionRow, at time.Time) (bool, error) {
+	q := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("class_attendance_session_participant_school_id = ? AND class_attendance_session_participant_session_id = ?", schoolID, s.ID)
+	if p.StudentID != nil {
+		q = q.Where("class_attendance_session_participant_school_student_id = ?", *p.StudentID)
+	} else {
+		q = q.Where("class_attendance_session_participant_school_teacher_id = ?", *p.TeacherID)
+	}
+
+	var m attendanceModel.ClassAttendanceSessionParticipantModel
+	err := q.Take(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// tap setelah sesi selesai tidak membuat kehadiran baru
+		if s.EndsAt != nil && at.After(*s.EndsAt) {
+			return false, nil
+		}
+		method := attendanceModel.ParticipantMethodDevice
+		now := time.Now().UTC()
+		checkin := at
+		state, late := stateFor(s, checkin)
+		m = attendanceModel.ClassAttendanceSessionParticipantModel{
+			ClassAttendanceSessionParticipantSchoolID:        schoolID,
+			ClassAttendanceSessionParticipantSessionID:       s.ID,
+			ClassAttendanceSessionParticipantKind:            p.Kind,
+			ClassAttendanceSessionParticipantSchoolStudentID: p.StudentID,
+			ClassAttendanceSessionParticipantSchoolTeacherID: p.TeacherID,
+			ClassAttendanceSessionParticipantState:           state,
+			ClassAttendanceSessionParticipantLateSeconds:     late,
+			ClassAttendanceSessionParticipantCheckinAt:       &checkin,
+			ClassAttendanceSessionParticipantMarkedAt:        &now,
+			ClassAttendanceSessionParticipantMethod:          &method,
+		}
+		if p.StudentID != nil {
+			hydrateStudentSnapshot(ctx, tx, &m)
+		}
+		return true, tx.Create(&m).Error
+	}
+	if err != nil {
+		return false, err
+	}
+	if m.ClassAttendanceSessionParticipantLockedAt != nil {
+		return false, nil
+	}
+
+	checkin, checkout := m.ClassAttendanceSessionParticipantCheckinAt, m.ClassAttendanceSessionParticipantCheckoutAt
+	switch {
+	case checkin == nil:
+		checkin = &at
+	case at.Before(*checkin):
+		// backfill tap yang lebih awal: checkin lama bisa jadi checkout
+		old := *checkin
+		checkin = &at
+		if old.Sub(at) >= minCheckoutGap && (checkout == nil || old.After(*checkout)) {
+			checkout = &old
+		}
+	case at.Sub(*checkin) >= minCheckoutGap && (checkout == nil || at.After(*checkout)):
+		checkout = &at
+	default:
+		return false, nil
+	}
+
+	upd := map[string]any{
+		"class_attendance_session_participant_checkin_at":  checkin,
+		"class_attendance_session_participant_checkout_at": checkout,
+		"class_attendance_session_participant_updated_at":  time.Now().UTC(),
+	}
+	if deviceOwnsState(&m) {
+		state, late := stateFor(s, *checkin)
+		upd["class_attendance_session_participant_state"] = state
+		upd["class_attendance_session_participant_late_seconds"] = late
+		upd["class_attendance_session_participant_method"] = attendanceModel.ParticipantMethodDevice
+		if m.ClassAttendanceSessionParticipantMarkedAt == nil {
+			upd["class_attendance_session_participant_marked_at"] = time.Now().UTC()
+		}
+	}
+	return true, tx.Model(&m).Updates(upd).Error
+}
+
+func hydrateStudentSnapshot(ctx context.Context, db *gorm.DB, m *attendanceModel.ClassAttendanceSessionParticipantModel) {
+	var profileID uuid.UUID
+	if err := db.WithContext(ctx).Table("school_students").
+		Select("school_student_user_profile_id").
+		Where("school_student_id = ?", *m.ClassAttendanceSessionParticipantSchoolStudentID).
+		Scan(&profileID).Error; err != nil || profileID == uuid.Nil {
+		return
+	}
+	up, err := snapSvc.BuildUserProfileCacheByProfileID(ctx, db, profileID)
+	if err != nil {
+		return
+	}
+	if strings.TrimSpace(up.Name) != "" {
+		m.ClassAttendanceSessionParticipantUserProfileNameSnapshot = &up.Name
+	}
+	m.ClassAttendanceSessionParticipantUserProfileAvatarURLSnapshot = up.AvatarURL
+	m.ClassAttendanceSessionParticipantUserProfileWhatsappURLSnapshot = up.WhatsappURL
+	m.ClassAttendanceSessionParticipantUserProfileParentNameSnapshot = up.ParentName
+	m.ClassAttendanceSessionParticipantUserProfileParentWhatsappURLSnapshot = up.ParentWhatsappURL
+	m.ClassAttendanceSessionParticipantUserProfileGenderSnapshot = up.Gender
+}
+
+// processPunch menerapkan 1 tap; hasil ditulis ke baris punch.
+func processPunch(ctx context.Context, tx *gorm.DB, loc *time.Location, pu *model.AttendanceDevicePunchModel) error {
+	finish := func(status model.PunchStatus, applied int, p *person, errMsg *string) error {
+		upd := map[string]any{
+			"attendance_device_punch_status":        status,
+			"attendance_device_punch_applied_count": applied,
+			"attendance_device_punch_error":         errMsg,
+			"attendance_device_punch_processed_at":  time.Now().UTC(),
+		}
+		if p != nil {
+			upd["attendance_device_punch_person_kind"] = string(p.Kind)
+			upd["attendance_device_punch_school_student_id"] = p.StudentID
+			upd["attendance_device_punch_school_teacher_id"] = p.TeacherID
+		}
+		return tx.Model(pu).Updates(upd).Error
+	}
+
+	p, err := resolvePerson(ctx, tx, pu.AttendanceDevicePunchSchoolID, pu.AttendanceDevicePunchPIN)
+	if err != nil {
+		return err
+	}
+	if p == nil {
+		return finish(model.PunchUnmapped, 0, nil, nil)
+	}
+
+	local := pu.AttendanceDevicePunchPunchedAt.In(loc)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+	sessions, err := sessionsFor(ctx, tx, pu.AttendanceDevicePunchSchoolID, p, day)
+	if err != nil {
+		return err
+	}
+	if len(sessions) == 0 {
+		return finish(model.PunchNoSession, 0, p, nil)
+	}
+
+	applied := 0
+	for _, s := range sessions {
+		if s.Locked || strings.EqualFold(s.AttendanceStatus, string(attendanceModel.AttendanceStatusClosed)) {
+			continue
+		}
+		var changed bool
+		if err := tx.Transaction(func(stx *gorm.DB) error {
+			var e error
+			changed, e = applyToSession(ctx, stx, pu.AttendanceDevicePunchSchoolID, p, s, pu.AttendanceDevicePunchPunchedAt.UTC())
+			return e
+		}); err != nil {
+			msg := err.Error()
+			return finish(model.PunchFailed, applied, p, &msg)
+		}
+		if changed {
+			applied++
+		}
+	}
+	if applied == 0 {
+		return finish(model.PunchSkipped, 0, p, nil)
+	}
+	return finish(model.PunchApplied, applied, p, nil)
+}
+
+// ProcessPending memproses antrean tap (urut waktu tap). Mengembalikan jumlah tap yang diproses.
+func ProcessPending(ctx context.Context, db *gorm.DB, limit int) (int, error) {
+	n := 0
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var rows []model.AttendanceDevicePunchModel
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("attendance_device_punch_status = ?", model.PunchPending).
+			Order("attendance_device_punch_punched_at ASC").
+			Limit(limit).
+			Find(&rows).Error; err != nil {
+			return err
+		}
+		locs := map[uuid.UUID]*time.Location{}
+		for i := range rows {
+			pu := &rows[i]
+			loc := locs[pu.AttendanceDevicePunchSchoolID]
+			if loc == nil {
+				loc = SchoolLocation(ctx, tx, pu.AttendanceDevicePunchSchoolID)
+				locs[pu.AttendanceDevicePunchSchoolID] = loc
+			}
+			if err := processPunch(ctx, tx, loc, pu); err != nil {
+				return err
+			}
+			n++
+		}
+		return nil
+	})
+	return n, err
+}
+
+/* =========================================================
+   Log & proses ulang (admin)
+========================================================= */
+
+type PunchFilter struct {
+	DeviceID *uuid.UUID
+	Status   string
+	PIN      string
+	From     *time.Time
+	To       *time.Time
+}
+
+func (f PunchFilter) apply(q *gorm.DB) *gorm.DB {
+	if f.DeviceID != nil {
+		q = q.Where("attendance_device_punch_device_id = ?", *f.DeviceID)
+	}
+	if s := strings.TrimSpace(f.Status); s != "" {
+		q = q.Where("attendance_device_punch_status = ?", s)
+	}
+	if pin := strings.TrimSpace(f.PIN); pin != "" {
+		q = q.Where("attendance_device_punch_pin = ?", pin)
+	}
+	if f.From != nil {
+		q = q.Where("attendance_device_punch_punched_at >= ?", *f.From)
+	}
+	if f.To != nil {
+		q = q.Where("attendance_device_punch_punched_at < ?", *f.To)
+	}
+	return q
+}
+
+func ListPunches(ctx context.Context, db *gorm.DB, schoolID uuid.UUID, f PunchFilter, limit, offset int) ([]model.AttendanceDevicePunchModel, int64, error) {
+	q := f.apply(db.WithContext(ctx).Model(&model.AttendanceDevicePunchModel{}).
+		Where("attendance_device_punch_school_id = ?", schoolID))
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var rows []model.AttendanceDevicePunchModel
+	err := q.Order("attendance_device_punch_punched_at DESC").Limit(limit).Offset(offset).Find(&rows).Error
+	return rows, total, err
+}
+
+// Reprocess: tap non-applied (unmapped/no_session/skipped/failed) → pending lagi,
+// mis. setelah PIN dipetakan atau sesi hari itu baru dibuat.
+func Reprocess(ctx context.Context, db *gorm.DB, schoolID uuid.UUID, f PunchFilter) (int64, error) {
+	q := db.WithContext(ctx).Model(&model.AttendanceDevicePunchModel{}).
+		Where("attendance_device_punch_school_id = ?", schoolID)
+	if strings.TrimSpace(f.Status) == "" {
+		q = q.Where("attendance_device_punch_status IN ?", []model.PunchStatus{
+			model.PunchUnmapped, model.PunchNoSession, model.PunchSkipped, model.PunchFailed,
+		})
+	}
+	res := f.apply(q).Updates(map[string]any{
+		"attendance_device_punch_status": model.PunchPending,
+		"attendance_device_punch_error":  nil,
+	})
+	return res.RowsAffected, res.Error
+}
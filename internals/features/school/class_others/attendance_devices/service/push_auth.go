@@ -0,0 +1,85 @@
+// file: internals/features/school/class_others/attendance_devices/service/push_auth.go
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	apikeysvc "madinahsalam_backend/internals/features/lembaga/api_keys/service"
+	model "madinahsalam_backend/internals/features/school/class_others/attendance_devices/model"
+)
+
+/* =========================================================
+   Autentikasi push ADMS (/iclock)
+
+   SN tercetak di mesin & ikut tercatat di access log, jadi bukan kredensial.
+   Setiap mesin punya token push (msd_<48 hex>), ditampilkan sekali saat
+   daftar / rotate; yang disimpan hanya sha256-nya, dibandingkan constant-time.
+   Token dikirim lewat ?token= pada URL server mesin atau header X-Device-Token.
+   Allowlist IP (opsional) dicek sesudahnya.
+========================================================= */
+
+var (
+	ErrDeviceNoToken      = errors.New("mesin absen belum punya token push; rotate token dulu")
+	ErrDeviceTokenInvalid = errors.New("token push mesin absen tidak valid")
+	ErrDeviceIPNotAllowed = errors.New("IP sumber tidak ada di allowlist mesin absen")
+)
+
+// DeviceTokenPrefix: penanda token push mesin absen.
+const DeviceTokenPrefix = "msd_"
+
+func generateDeviceToken() (raw, hash string, err error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	raw = DeviceTokenPrefix + hex.EncodeToString(b)
+	return raw, hashDeviceToken(raw), nil
+}
+
+func hashDeviceToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+// AuthenticateDevice: dipanggil listener ADMS sebelum handshake / ingest.
+func AuthenticateDevice(dev *model.AttendanceDeviceModel, token, ip string) error {
+	if dev.AttendanceDeviceTokenHash == nil || *dev.AttendanceDeviceTokenHash == "" {
+		return ErrDeviceNoToken
+	}
+	if subtle.ConstantTimeCompare([]byte(hashDeviceToken(token)), []byte(*dev.AttendanceDeviceTokenHash)) != 1 {
+		return ErrDeviceTokenInvalid
+	}
+	if !apikeysvc.IPAllowed(dev.AttendanceDeviceAllowedIPs, ip) {
+		return ErrDeviceIPNotAllowed
+	}
+	return nil
+}
+
+// RotateDeviceToken: token lama langsung tidak berlaku; token baru dikembalikan sekali.
+func RotateDeviceToken(ctx context.Context, db *gorm.DB, schoolID, id uuid.UUID) (*model.AttendanceDeviceModel, string, error) {
+	m, err := GetDevice(ctx, db, schoolID, id)
+	if err != nil {
+		return nil, "", err
+	}
+	raw, hash, err := generateDeviceToken()
+	if err != nil {
+		return nil, "", err
+	}
+	if err := db.WithContext(ctx).Model(m).Updates(map[string]any{
+		"attendance_device_token_hash": hash,
+		"attendance_device_updated_at": time.Now(),
+	}).Error; err != nil {
+		return nil, "", err
+	}
+	m.AttendanceDeviceTokenHash = &hash
+	return m, raw, nil
+}
@@ -0,0 +1,49 @@
+// file: internals/features/school/class_others/attendance_devices/worker/punch_worker.go
+package worker
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"gorm.io/gorm"
+
+	svc "madinahsalam_backend/internals/features/school/class_others/attendance_devices/service"
+)
+
+const processBatch = 200
+
+// RunPunchWorker: terapkan tap mesin absen (status pending) ke sesi sampai ctx dibatalkan.
+// Interval via ATTENDANCE_DEVICE_POLL_INTERVAL (default 5s).
+func RunPunchWorker(ctx context.Context, db *gorm.DB) {
+	interval := 5 * time.Second
+	if v, err := time.ParseDuration(os.Getenv("ATTENDANCE_DEVICE_POLL_INTERVAL")); err == nil && v > 0 {
+		interval = v
+	}
+
+	log.Printf("[ATT-DEVICE] worker started interval=%s", interval)
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		// backfill bisa ribuan tap → habiskan antrean dulu
+		for {
+			n, err := svc.ProcessPending(ctx, db, processBatch)
+			if err != nil {
+				log.Printf("[ATT-DEVICE] process error: %v", err)
+				break
+			}
+			if n < processBatch {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Printf("[ATT-DEVICE] worker stopped")
+			return
+		case <-t.C:
+		}
+	}
+}
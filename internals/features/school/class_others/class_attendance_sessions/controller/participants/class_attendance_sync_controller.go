@@ -0,0 +1,121 @@
+// file: internals/features/school/class_others/class_attendance_sessions/controller/participants/class_attendance_sync_controller.go
+package controller
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	attendanceService "madinahsalam_backend/internals/features/school/class_others/class_attendance_sessions/service"
+	helper "madinahsalam_backend/internals/helpers"
+	helperAuth "madinahsalam_backend/internals/helpers/auth"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+/*
+Sinkronisasi absensi offline (guru)
+
+GET  /api/t/attendance-sync/bundle?date=YYYY-MM-DD[&teacher_id=]   sesi hari itu + roster (version per baris)
+POST /api/t/attendance-sync/batch                                  {"client_id","ops":[{"client_op_id","class_attendance_session_id","school_student_id","state","teacher_note","checkin_at","marked_at","base_version"}]}
+
+Guru hanya melihat & menandai sesi miliknya; DKM/admin boleh semua sesi (opsional ?teacher_id=).
+Kirim ulang batch yang sama aman: client_op_id yang sudah diproses mengembalikan hasil lama (duplicate=true).
+*/
+
+func syncError(c *fiber.Ctx, err error) error {
+	var fe *fiber.Error
+	switch {
+	case errors.As(err, &fe):
+		return helper.JsonError(c, fe.Code, fe.Message)
+	case errors.Is(err, attendanceService.ErrSyncEmpty), errors.Is(err, attendanceService.ErrSyncTooManyOps):
+		return helper.JsonError(c, fiber.StatusBadRequest, err.Error())
+	}
+	return helper.JsonError(c, fiber.StatusInternalServerError, err.Error())
+}
+
+// syncActor: guru → teacher_id dari token; DKM/admin → nil (semua sesi) atau ?teacher_id=.
+func (ctl *ClassAttendanceSessionParticipantController) syncActor(c *fiber.Ctx, schoolID uuid.UUID) (*uuid.UUID, error) {
+	if helperAuth.IsOwner(c) || helperAuth.IsDKMInSchool(c, schoolID) {
+		if s := strings.TrimSpace(c.Query("teacher_id")); s != "" {
+			id, err := uuid.Parse(s)
+			if err != nil {
+				return nil, fiber.NewError(fiber.StatusBadRequest, "teacher_id tidak valid")
+			}
+			return &id, nil
+		}
+		return nil, nil
+	}
+	teacherID, err := helperAuth.GetSchoolTeacherIDForSchool(c, schoolID)
+	if err != nil || teacherID == uuid.Nil {
+		return nil, fiber.NewError(fiber.StatusForbidden, "Hanya guru/DKM yang dapat sinkronisasi absensi")
+	}
+	return &teacherID, nil
+}
+
+// GET /attendance-sync/bundle
+func (ctl *ClassAttendanceSessionParticipantController) SyncBundle(c *fiber.Ctx) error {
+	schoolID, err := ctl.resolveSchoolIDFromToken(c)
+	if err != nil {
+		return err
+	}
+	teacherID, err := ctl.syncActor(c, schoolID)
+	if err != nil {
+		return syncError(c, err)
+	}
+
+	var day time.Time
+	if s := strings.TrimSpace(c.Query("date")); s != "" {
+		if day, err = time.Parse("2006-01-02", s); err != nil {
+			return helper.JsonError(c, fiber.StatusBadRequest, "date harus format YYYY-MM-DD")
+		}
+	}
+
+	bundle, err := attendanceService.NewAttendanceSyncService(ctl.DB).Bundle(c.Context(), schoolID, teacherID, day)
+	if err != nil {
+		return syncError(c, err)
+	}
+	return helper.JsonOK(c, "OK", bundle)
+}
+
+// POST /attendance-sync/batch
+func (ctl *ClassAttendanceSessionParticipantController) SyncBatch(c *fiber.Ctx) error {
+	schoolID, err := ctl.resolveSchoolIDFromToken(c)
+	if err != nil {
+		return err
+	}
+	teacherID, err := ctl.syncActor(c, schoolID)
+	if err != nil {
+		return syncError(c, err)
+	}
+
+	var body struct {
+		ClientID *string                         `json:"client_id"`
+		Ops      []attendanceService.SyncOpInput `json:"ops"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "Payload tidak valid")
+	}
+	if body.ClientID != nil {
+		v := strings.TrimSpace(*body.ClientID)
+		if len(v) > 80 {
+			v = v[:80]
+		}
+		body.ClientID = &v
+		if v == "" {
+			body.ClientID = nil
+		}
+	}
+
+	res, err := attendanceService.NewAttendanceSyncService(ctl.DB).ApplyBatch(c.Context(), attendanceService.SyncBatchInput{
+		SchoolID:  schoolID,
+		TeacherID: teacherID,
+		ClientID:  body.ClientID,
+		Ops:       body.Ops,
+	})
+	if err != nil {
+		return syncError(c, err)
+	}
+	return helper.JsonOK(c, "Sinkronisasi absensi diproses", res)
+}
@@ -546,6 +546,9 @@ func (ctl *ClassAttendanceSessionParticipantController) CreateAttendanceParticip
 		return helper.JsonError(c, fiber.StatusInternalServerError, err.Error())
 	}
 
+	// webhook attendance.absent (di luar tx)
+	attendanceService.EmitAbsentWebhook(c.Context(), ctl.DB, &created)
+
 	// Ambil URLs (live) untuk response
 	var urls []attendanceModel.ClassAttendanceSessionParticipantURLModel
 	_ = ctl.DB.
@@ -642,6 +645,10 @@ func (ctl *ClassAttendanceSessionParticipantController) Patch(c *fiber.Ctx) erro
 	}
 
 	// ── Transaksi ──
+	var (
+		updated   attendanceModel.ClassAttendanceSessionParticipantModel
+		prevState attendanceModel.AttendanceState
+	)
 	if err := ctl.DB.WithContext(c.Context()).Transaction(func(tx *gorm.DB) error {
 		// load + FOR UPDATE (tenant guard)
 		var m attendanceModel.ClassAttendanceSessionParticipantModel
@@ -658,6 +665,8 @@ func (ctl *ClassAttendanceSessionParticipantController) Patch(c *fiber.Ctx) erro
 			return err
 		}
 
+		prevState = m.ClassAttendanceSessionParticipantState
+
 		// apply tri-state patch
 		if err := req.ApplyPatch(&m); err != nil {
 			return err
@@ -715,6 +724,7 @@ func (ctl *ClassAttendanceSessionParticipantController) Patch(c *fiber.Ctx) erro
 		if err := ensurePrimaryUnique(tx, m.ClassAttendanceSessionParticipantID); err != nil {
 			return err
 		}
+		updated = m
 		return nil
 	}); err != nil {
 		if fe, ok := err.(*fiber.Error); ok {
@@ -723,6 +733,11 @@ func (ctl *ClassAttendanceSessionParticipantController) Patch(c *fiber.Ctx) erro
 		return helper.JsonError(c, fiber.StatusInternalServerError, err.Error())
 	}
 
+	// webhook attendance.absent hanya saat transisi ke absent
+	if prevState != attendanceModel.AttendanceStateAbsent {
+		attendanceService.EmitAbsentWebhook(c.Context(), ctl.DB, &updated)
+	}
+
 	// Balikan state terbaru
 	var urls []attendanceModel.ClassAttendanceSessionParticipantURLModel
 	_ = ctl.DB.
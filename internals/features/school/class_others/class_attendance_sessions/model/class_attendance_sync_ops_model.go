@@ -0,0 +1,44 @@
+// file: internals/features/school/class_others/class_attendance_sessions/model/class_attendance_sync_ops_model.go
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+/* =========================================================
+   class_attendance_sync_ops — log operasi sinkronisasi offline
+   (1 baris per client_op_id → dasar idempotensi batch)
+   ========================================================= */
+
+// status hasil per baris
+const (
+	SyncOpStatusApplied   = "applied"   // ditulis ke participant
+	SyncOpStatusConflict  = "conflict"  // server punya penandaan lebih baru → data server dipertahankan
+	SyncOpStatusLocked    = "locked"    // participant / sesi sudah dikunci
+	SyncOpStatusRejected  = "rejected"  // sesi/siswa tidak valid atau guru tidak berhak
+	SyncOpStatusDuplicate = "duplicate" // client_op_id sudah pernah diproses (hanya di summary, tidak disimpan)
+)
+
+type ClassAttendanceSyncOpModel struct {
+	ClassAttendanceSyncOpID            uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey;column:class_attendance_sync_op_id" json:"class_attendance_sync_op_id"`
+	ClassAttendanceSyncOpSchoolID      uuid.UUID  `gorm:"type:uuid;not null;column:class_attendance_sync_op_school_id" json:"class_attendance_sync_op_school_id"`
+	ClassAttendanceSyncOpClientOpID    uuid.UUID  `gorm:"type:uuid;not null;column:class_attendance_sync_op_client_op_id" json:"class_attendance_sync_op_client_op_id"`
+	ClassAttendanceSyncOpClientID      *string    `gorm:"type:varchar(80);column:class_attendance_sync_op_client_id" json:"class_attendance_sync_op_client_id,omitempty"`
+	ClassAttendanceSyncOpTeacherID     *uuid.UUID `gorm:"type:uuid;column:class_attendance_sync_op_teacher_id" json:"class_attendance_sync_op_teacher_id,omitempty"`
+	ClassAttendanceSyncOpSessionID     uuid.UUID  `gorm:"type:uuid;not null;column:class_attendance_sync_op_session_id" json:"class_attendance_sync_op_session_id"`
+	ClassAttendanceSyncOpStudentID     uuid.UUID  `gorm:"type:uuid;not null;column:class_attendance_sync_op_student_id" json:"class_attendance_sync_op_student_id"`
+	ClassAttendanceSyncOpParticipantID *uuid.UUID `gorm:"type:uuid;column:class_attendance_sync_op_participant_id" json:"class_attendance_sync_op_participant_id,omitempty"`
+
+	ClassAttendanceSyncOpStatus         string            `gorm:"type:varchar(16);not null;column:class_attendance_sync_op_status" json:"class_attendance_sync_op_status"`
+	ClassAttendanceSyncOpClientMarkedAt time.Time         `gorm:"type:timestamptz;not null;column:class_attendance_sync_op_client_marked_at" json:"class_attendance_sync_op_client_marked_at"`
+	ClassAttendanceSyncOpResult         datatypes.JSONMap `gorm:"type:jsonb;not null;default:'{}'::jsonb;column:class_attendance_sync_op_result" json:"class_attendance_sync_op_result"`
+
+	ClassAttendanceSyncOpCreatedAt time.Time `gorm:"type:timestamptz;not null;default:now();autoCreateTime;column:class_attendance_sync_op_created_at" json:"class_attendance_sync_op_created_at"`
+}
+
+func (ClassAttendanceSyncOpModel) TableName() string {
+	return "class_attendance_sync_ops"
+}
@@ -43,6 +43,12 @@ const (
 	AttendanceStateUnmarked AttendanceState = "unmarked" // default
 )
 
+// metode absen (selaras CHECK chk_cas_participant_method)
+const (
+	ParticipantMethodManual = "manual"
+	ParticipantMethodDevice = "device" // mesin fingerprint/RFID
+)
+
 /* =========================================
    MODEL: class_attendance_session_participants
    ========================================= */
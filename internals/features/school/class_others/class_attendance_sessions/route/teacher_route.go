@@ -45,4 +45,12 @@ func AttendanceSessionsTeacherRoutes(r fiber.Router, db *gorm.DB) {
 	uatt.Patch("/:id", uattCtl.Patch)
 	uatt.Delete("/:id", uattCtl.Delete)
 	uatt.Post("/:id/restore", uattCtl.Restore)
+
+	// =====================
+	// Sinkronisasi absensi offline
+	// =====================
+	syncCtl := attendanceParticipantController.NewClassAttendanceSessionParticipantController(db)
+	sync := base.Group("/attendance-sync")
+	sync.Get("/bundle", syncCtl.SyncBundle)
+	sync.Post("/batch", syncCtl.SyncBatch)
 }
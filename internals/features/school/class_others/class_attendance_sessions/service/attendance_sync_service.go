@@ -0,0 +1,621 @@
+// file: internals/features/school/class_others/class_attendance_sessions/service/attendance_sync_service.go
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	devsvc "madinahsalam_backend/internals/features/school/class_others/attendance_devices/service"
+	attendanceModel "madinahsalam_backend/internals/features/school/class_others/class_attendance_sessions/model"
+)
+
+/* =========================================================
+   Sinkronisasi absensi offline (guru dengan sinyal buruk)
+
+   1) Bundle  : sesi hari itu milik guru + roster siswa.
+                Tiap baris roster membawa version = updated_at participant (unix mikrodetik, 0 = belum ada).
+   2) Batch   : penandaan offline dikirim bersama client_op_id (UUID klien) & marked_at (jam klien).
+                Aturan per baris:
+                - client_op_id sudah pernah diproses → hasil lama dikembalikan (duplicate=true), tanpa menulis
+                - sesi dikunci / absensi ditutup / participant locked_at terisi → locked
+                - marked_at server lebih baru dari marked_at klien → conflict (data server dipertahankan)
+                - selain itu ditulis (last-writer-wins berdasarkan marked_at);
+                  base_version beda tapi klien lebih baru → applied + overwrote=true
+   ========================================================= */
+
+const (
+	SyncMaxOps = 500
+	// toleransi jam klien yang kebablasan maju
+	syncMaxClockSkew = 5 * time.Minute
+)
+
+var (
+	ErrSyncTooManyOps = errors.New("maksimal 500 operasi per batch")
+	ErrSyncEmpty      = errors.New("ops wajib diisi")
+
+	errSyncOpRace = errors.New("client_op_id sedang diproses")
+)
+
+type AttendanceSyncService struct {
+	DB *gorm.DB
+}
+
+func NewAttendanceSyncService(db *gorm.DB) *AttendanceSyncService {
+	return &AttendanceSyncService{DB: db}
+}
+
+/* =========================
+   Bundle
+   ========================= */
+
+type SyncRosterRow struct {
+	SchoolStudentID uuid.UUID  `json:"school_student_id"`
+	Name            *string    `json:"name,omitempty"`
+	Code            *string    `json:"code,omitempty"`
+	AvatarURL       *string    `json:"avatar_url,omitempty"`
+	ParticipantID   *uuid.UUID `json:"class_attendance_session_participant_id,omitempty"`
+	State           string     `json:"state"`
+	CheckinAt       *time.Time `json:"checkin_at,omitempty"`
+	MarkedAt        *time.Time `json:"marked_at,omitempty"`
+	LockedAt        *time.Time `json:"locked_at,omitempty"`
+	TeacherNote     *string    `json:"teacher_note,omitempty"`
+	Version         int64      `json:"version"`
+}
+
+type SyncBundleSession struct {
+	SessionID        uuid.UUID       `json:"class_attendance_session_id"`
+	Date             string          `json:"date"`
+	StartsAt         *time.Time      `json:"starts_at,omitempty"`
+	EndsAt           *time.Time      `json:"ends_at,omitempty"`
+	Title            *string         `json:"title,omitempty"`
+	CSSTID           *uuid.UUID      `json:"csst_id,omitempty"`
+	TeacherID        *uuid.UUID      `json:"teacher_id,omitempty"`
+	Locked           bool            `json:"locked"`
+	AttendanceStatus string          `json:"attendance_status"`
+	Version          int64           `json:"version"`
+	Roster           []SyncRosterRow `json:"roster"`
+}
+
+type SyncBundle struct {
+	Date       string              `json:"date"`
+	ServerTime time.Time           `json:"server_time"`
+	Sessions   []SyncBundleSession `json:"sessions"`
+}
+
+type syncSessionRow struct {
+	ID               uuid.UUID  `gorm:"column:class_attendance_session_id"`
+	Date             time.Time  `gorm:"column:class_attendance_session_date"`
+	StartsAt         *time.Time `gorm:"column:class_attendance_session_starts_at"`
+	EndsAt           *time.Time `gorm:"column:class_attendance_session_ends_at"`
+	Title            *string    `gorm:"column:class_attendance_session_title"`
+	CSSTID           *uuid.UUID `gorm:"column:class_attendance_session_csst_id"`
+	TeacherID        *uuid.UUID `gorm:"column:class_attendance_session_teacher_id"`
+	Locked           bool       `gorm:"column:class_attendance_session_locked"`
+	AttendanceStatus string     `gorm:"column:class_attendance_session_attendance_status"`
+	IsCanceled       bool       `gorm:"column:class_attendance_session_is_canceled"`
+	UpdatedAt        time.Time  `gorm:"column:class_attendance_session_updated_at"`
+}
+
+const syncSessionCols = `class_attendance_session_id, class_attendance_session_date,
+	class_attendance_session_starts_at, class_attendance_session_ends_at, class_attendance_session_title,
+	class_attendance_session_csst_id, class_attendance_session_teacher_id, class_attendance_session_locked,
+	class_attendance_session_attendance_status, class_attendance_session_is_canceled, class_attendance_session_updated_at`
+
+type syncRosterStudent struct {
+	CSSTID            uuid.UUID `gorm:"column:student_csst_csst_id"`
+	StudentID         uuid.UUID `gorm:"column:student_csst_student_id"`
+	Name              *string   `gorm:"column:student_csst_user_profile_name_cache"`
+	AvatarURL         *string   `gorm:"column:student_csst_user_profile_avatar_url_cache"`
+	WhatsappURL       *string   `gorm:"column:student_csst_user_profile_whatsapp_url_cache"`
+	ParentName        *string   `gorm:"column:student_csst_user_profile_parent_name_cache"`
+	ParentWhatsappURL *string   `gorm:"column:student_csst_user_profile_parent_wa_url_cache"`
+	Gender            *string   `gorm:"column:student_csst_user_profile_gender_cache"`
+	Code              *string   `gorm:"column:student_csst_school_student_code_cache"`
+}
+
+// teacherSessionScope: sesi milik guru (guru sesi, atau guru CSST bila sesi tidak override guru).
+func teacherSessionScope(q *gorm.DB, schoolID, teacherID uuid.UUID) *gorm.DB {
+	return q.Where(`(class_attendance_session_teacher_id = ?
+		OR (class_attendance_session_teacher_id IS NULL AND class_attendance_session_csst_id IN (
+			SELECT csst_id FROM class_section_subject_teachers
+			 WHERE csst_school_id = ? AND csst_school_teacher_id = ? AND csst_deleted_at IS NULL)))`,
+		teacherID, schoolID, teacherID)
+}
+
+func rosterStudents(ctx context.Context, db *gorm.DB, schoolID uuid.UUID, csstIDs []uuid.UUID, day time.Time) ([]syncRosterStudent, error) {
+	var rows []syncRosterStudent
+	if len(csstIDs) == 0 {
+		return rows, nil
+	}
+	err := db.WithContext(ctx).
+		Table("student_class_section_subject_teachers").
+		Select(`student_csst_csst_id, student_csst_student_id,
+		        student_csst_user_profile_name_cache, student_csst_user_profile_avatar_url_cache,
+		        student_csst_user_profile_whatsapp_url_cache, student_csst_user_profile_parent_name_cache,
+		        student_csst_user_profile_parent_wa_url_cache, student_csst_user_profile_gender_cache,
+		        student_csst_school_student_code_cache`).
+		Where(`student_csst_school_id = ?
+		   AND student_csst_csst_id IN ?
+		   AND student_csst_is_active = TRUE
+		   AND student_csst_deleted_at IS NULL
+		   AND (student_csst_from IS NULL OR student_csst_from <= ?)
+		   AND (student_csst_to   IS NULL OR student_csst_to   >= ?)`,
+			schoolID, csstIDs, day.Format("2006-01-02"), day.Format("2006-01-02")).
+		Order("student_csst_user_profile_name_cache ASC NULLS LAST").
+		Scan(&rows).Error
+	return rows, err
+}
+
+func participantVersion(m *attendanceModel.ClassAttendanceSessionParticipantModel) int64 {
+	if m == nil {
+		return 0
+	}
+	return m.ClassAttendanceSessionParticipantUpdatedAt.UnixMicro()
+}
+
+func rosterFromParticipant(r *SyncRosterRow, m *attendanceModel.ClassAttendanceSessionParticipantModel) {
+	id := m.ClassAttendanceSessionParticipantID
+	r.ParticipantID = &id
+	r.State = string(m.ClassAttendanceSessionParticipantState)
+	r.CheckinAt = m.ClassAttendanceSessionParticipantCheckinAt
+	r.MarkedAt = m.ClassAttendanceSessionParticipantMarkedAt
+	r.LockedAt = m.ClassAttendanceSessionParticipantLockedAt
+	r.TeacherNote = m.ClassAttendanceSessionParticipantTeacherNote
+	r.Version = participantVersion(m)
+	if r.Name == nil {
+		r.Name = m.ClassAttendanceSessionParticipantUserProfileNameSnapshot
+	}
+	if r.AvatarURL == nil {
+		r.AvatarURL = m.ClassAttendanceSessionParticipantUserProfileAvatarURLSnapshot
+	}
+}
+
+// Bundle: sesi tanggal `day` (zona sekolah; zero = hari ini) + roster.
+// teacherID nil → semua sesi sekolah (DKM/admin).
+func (s *AttendanceSyncService) Bundle(ctx context.Context, schoolID uuid.UUID, teacherID *uuid.UUID, day time.Time) (*SyncBundle, error) {
+	if day.IsZero() {
+		day = time.Now().In(devsvc.SchoolLocation(ctx, s.DB, schoolID))
+	}
+	dayStr := day.Format("2006-01-02")
+
+	q := s.DB.WithContext(ctx).
+		Table("class_attendance_sessions").
+		Select(syncSessionCols).
+		Where(`class_attendance_session_school_id = ?
+		   AND class_attendance_session_date = ?
+		   AND class_attendance_session_deleted_at IS NULL
+		   AND NOT class_attendance_session_is_canceled`, schoolID, dayStr)
+	if teacherID != nil {
+		q = teacherSessionScope(q, schoolID, *teacherID)
+	}
+	var sessions []syncSessionRow
+	if err := q.Order("class_attendance_session_starts_at ASC NULLS LAST").Scan(&sessions).Error; err != nil {
+		return nil, err
+	}
+
+	out := &SyncBundle{Date: dayStr, ServerTime: time.Now().UTC(), Sessions: []SyncBundleSession{}}
+	if len(sessions) == 0 {
+		return out, nil
+	}
+
+	sessionIDs := make([]uuid.UUID, 0, len(sessions))
+	csstIDs := make([]uuid.UUID, 0, len(sessions))
+	for _, ss := range sessions {
+		sessionIDs = append(sessionIDs, ss.ID)
+		if ss.CSSTID != nil {
+			csstIDs = append(csstIDs, *ss.CSSTID)
+		}
+	}
+
+	students, err := rosterStudents(ctx, s.DB, schoolID, csstIDs, day)
+	if err != nil {
+		return nil, err
+	}
+	byCSST := map[uuid.UUID][]syncRosterStudent{}
+	for _, st := range students {
+		byCSST[st.CSSTID] = append(byCSST[st.CSSTID], st)
+	}
+
+	var parts []attendanceModel.ClassAttendanceSessionParticipantModel
+	if err := s.DB.WithContext(ctx).
+		Where(`class_attendance_session_participant_school_id = ?
+		   AND class_attendance_session_participant_session_id IN ?
+		   AND class_attendance_session_participant_school_student_id IS NOT NULL
+		   AND class_attendance_session_participant_deleted_at IS NULL`, schoolID, sessionIDs).
+		Find(&parts).Error; err != nil {
+		return nil, err
+	}
+	type key struct{ session, student uuid.UUID }
+	partBy := make(map[key]*attendanceModel.ClassAttendanceSessionParticipantModel, len(parts))
+	for i := range parts {
+		p := &parts[i]
+		partBy[key{p.ClassAttendanceSessionParticipantSessionID, *p.ClassAttendanceSessionParticipantSchoolStudentID}] = p
+	}
+
+	for _, ss := range sessions {
+		bs := SyncBundleSession{
+			SessionID:        ss.ID,
+			Date:             ss.Date.Format("2006-01-02"),
+			StartsAt:         ss.StartsAt,
+			EndsAt:           ss.EndsAt,
+			Title:            ss.Title,
+			CSSTID:           ss.CSSTID,
+			TeacherID:        ss.TeacherID,
+			Locked:           ss.Locked,
+			AttendanceStatus: ss.AttendanceStatus,
+			Version:          ss.UpdatedAt.UnixMicro(),
+			Roster:           []SyncRosterRow{},
+		}
+		seen := map[uuid.UUID]bool{}
+		if ss.CSSTID != nil {
+			for _, st := range byCSST[*ss.CSSTID] {
+				if seen[st.StudentID] {
+					continue
+				}
+				seen[st.StudentID] = true
+				r := SyncRosterRow{
+					SchoolStudentID: st.StudentID,
+					Name:            st.Name,
+					Code:            st.Code,
+					AvatarURL:       st.AvatarURL,
+					State:           string(attendanceModel.AttendanceStateUnmarked),
+				}
+				if p := partBy[key{ss.ID, st.StudentID}]; p != nil {
+					rosterFromParticipant(&r, p)
+				}
+				bs.Roster = append(bs.Roster, r)
+			}
+		}
+		// participant di luar roster CSST (mis. sesi tanpa CSST / ditambah manual)
+		for i := range parts {
+			p := &parts[i]
+			if p.ClassAttendanceSessionParticipantSessionID != ss.ID || seen[*p.ClassAttendanceSessionParticipantSchoolStudentID] {
+				continue
+			}
+			seen[*p.ClassAttendanceSessionParticipantSchoolStudentID] = true
+			r := SyncRosterRow{SchoolStudentID: *p.ClassAttendanceSessionParticipantSchoolStudentID}
+			rosterFromParticipant(&r, p)
+			bs.Roster = append(bs.Roster, r)
+		}
+		out.Sessions = append(out.Sessions, bs)
+	}
+	return out, nil
+}
+
+/* =========================
+   Batch upload
+   ========================= */
+
+type SyncOpInput struct {
+	ClientOpID      uuid.UUID  `json:"client_op_id"`
+	SessionID       uuid.UUID  `json:"class_attendance_session_id"`
+	SchoolStudentID uuid.UUID  `json:"school_student_id"`
+	State           string     `json:"state"`
+	TeacherNote     *string    `json:"teacher_note"`
+	CheckinAt       *time.Time `json:"checkin_at"`
+	MarkedAt        time.Time  `json:"marked_at"`
+	BaseVersion     *int64     `json:"base_version"`
+}
+
+type SyncBatchInput struct {
+	SchoolID  uuid.UUID
+	TeacherID *uuid.UUID
+	ClientID  *string
+	Ops       []SyncOpInput
+}
+
+type SyncOpResult struct {
+	ClientOpID uuid.UUID      `json:"client_op_id"`
+	Status     string         `json:"status"`
+	Reason     string         `json:"reason,omitempty"`
+	Duplicate  bool           `json:"duplicate,omitempty"`
+	Overwrote  bool           `json:"overwrote,omitempty"`
+	Server     *SyncRosterRow `json:"server,omitempty"`
+}
+
+type SyncBatchResult struct {
+	ServerTime time.Time      `json:"server_time"`
+	Summary    map[string]int `json:"summary"`
+	Results    []SyncOpResult `json:"results"`
+}
+
+func validSyncState(s string) bool {
+	switch attendanceModel.AttendanceState(s) {
+	case attendanceModel.AttendanceStatePresent, attendanceModel.AttendanceStateAbsent,
+		attendanceModel.AttendanceStateLate, attendanceModel.AttendanceStateExcused,
+		attendanceModel.AttendanceStateSick, attendanceModel.AttendanceStateLeave,
+		attendanceModel.AttendanceStateUnmarked:
+		return true
+	}
+	return false
+}
+
+// ApplyBatch memproses tiap operasi dalam transaksi sendiri (1 baris gagal tidak membatalkan yang lain).
+// Operasi diproses urut marked_at klien sehingga penandaan offline terakhir untuk siswa yang sama menang.
+func (s *AttendanceSyncService) ApplyBatch(ctx context.Context, in SyncBatchInput) (*SyncBatchResult, error) {
+	if len(in.Ops) == 0 {
+		return nil, ErrSyncEmpty
+	}
+	if len(in.Ops) > SyncMaxOps {
+		return nil, ErrSyncTooManyOps
+	}
+
+	now := time.Now().UTC()
+	order := make([]int, len(in.Ops))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return in.Ops[order[a]].MarkedAt.Before(in.Ops[order[b]].MarkedAt)
+	})
+
+	results := make([]SyncOpResult, len(in.Ops))
+	for _, i := range order {
+		res, err := s.applyOp(ctx, in, in.Ops[i], now)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = res
+	}
+
+	summary := map[string]int{}
+	for _, r := range results {
+		if r.Duplicate {
+			summary[attendanceModel.SyncOpStatusDuplicate]++
+			continue
+		}
+		summary[r.Status]++
+	}
+	return &SyncBatchResult{ServerTime: now, Summary: summary, Results: results}, nil
+}
+
+func (s *AttendanceSyncService) findOp(ctx context.Context, db *gorm.DB, schoolID, clientOpID uuid.UUID) (*SyncOpResult, error) {
+	var prev attendanceModel.ClassAttendanceSyncOpModel
+	err := db.WithContext(ctx).
+		Where("class_attendance_sync_op_school_id = ? AND class_attendance_sync_op_client_op_id = ?", schoolID, clientOpID).
+		Take(&prev).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var res SyncOpResult
+	if b, e := json.Marshal(prev.ClassAttendanceSyncOpResult); e == nil {
+		_ = json.Unmarshal(b, &res)
+	}
+	res.ClientOpID = clientOpID
+	res.Status = prev.ClassAttendanceSyncOpStatus
+	res.Duplicate = true
+	return &res, nil
+}
+
+func (s *AttendanceSyncService) applyOp(ctx context.Context, in SyncBatchInput, op SyncOpInput, now time.Time) (SyncOpResult, error) {
+	res := SyncOpResult{ClientOpID: op.ClientOpID}
+	op.State = strings.ToLower(strings.TrimSpace(op.State))
+
+	// validasi payload (tidak dicatat; tanpa client_op_id tidak bisa idempotent)
+	switch {
+	case op.ClientOpID == uuid.Nil:
+		res.Status, res.Reason = attendanceModel.SyncOpStatusRejected, "client_op_id wajib diisi"
+	case op.SessionID == uuid.Nil || op.SchoolStudentID == uuid.Nil:
+		res.Status, res.Reason = attendanceModel.SyncOpStatusRejected, "class_attendance_session_id & school_student_id wajib diisi"
+	case !validSyncState(op.State):
+		res.Status, res.Reason = attendanceModel.SyncOpStatusRejected, "state tidak valid"
+	case op.MarkedAt.IsZero():
+		res.Status, res.Reason = attendanceModel.SyncOpStatusRejected, "marked_at wajib diisi"
+	}
+	if res.Status != "" {
+		return res, nil
+	}
+
+	if prev, err := s.findOp(ctx, s.DB, in.SchoolID, op.ClientOpID); err != nil || prev != nil {
+		if prev != nil {
+			return *prev, nil
+		}
+		return res, err
+	}
+
+	clientMarked := op.MarkedAt.UTC().Truncate(time.Microsecond)
+	if clientMarked.After(now.Add(syncMaxClockSkew)) {
+		clientMarked = now.Truncate(time.Microsecond)
+	}
+
+	var (
+		applied   *attendanceModel.ClassAttendanceSessionParticipantModel
+		prevState attendanceModel.AttendanceState
+	)
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res = SyncOpResult{ClientOpID: op.ClientOpID}
+		var participantID *uuid.UUID
+
+		record := func() error {
+			b, _ := json.Marshal(res)
+			var m map[string]any
+			_ = json.Unmarshal(b, &m)
+			row := attendanceModel.ClassAttendanceSyncOpModel{
+				ClassAttendanceSyncOpSchoolID:       in.SchoolID,
+				ClassAttendanceSyncOpClientOpID:     op.ClientOpID,
+				ClassAttendanceSyncOpClientID:       in.ClientID,
+				ClassAttendanceSyncOpTeacherID:      in.TeacherID,
+				ClassAttendanceSyncOpSessionID:      op.SessionID,
+				ClassAttendanceSyncOpStudentID:      op.SchoolStudentID,
+				ClassAttendanceSyncOpParticipantID:  participantID,
+				ClassAttendanceSyncOpStatus:         res.Status,
+				ClassAttendanceSyncOpClientMarkedAt: clientMarked,
+				ClassAttendanceSyncOpResult:         m,
+			}
+			r := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&row)
+			if r.Error != nil {
+				return r.Error
+			}
+			if r.RowsAffected == 0 {
+				return errSyncOpRace
+			}
+			return nil
+		}
+		reject := func(status, reason string) error {
+			res.Status, res.Reason = status, reason
+			return record()
+		}
+
+		// sesi (tenant guard)
+		var ss syncSessionRow
+		sq := tx.Table("class_attendance_sessions").
+			Select(syncSessionCols).
+			Where(`class_attendance_session_id = ? AND class_attendance_session_school_id = ?
+			   AND class_attendance_session_deleted_at IS NULL`, op.SessionID, in.SchoolID)
+		if err := sq.Take(&ss).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return reject(attendanceModel.SyncOpStatusRejected, "sesi tidak ditemukan")
+			}
+			return err
+		}
+		if ss.IsCanceled {
+			return reject(attendanceModel.SyncOpStatusRejected, "sesi dibatalkan")
+		}
+		if in.TeacherID != nil {
+			var n int64
+			if err := teacherSessionScope(
+				tx.Table("class_attendance_sessions").Where("class_attendance_session_id = ?", ss.ID),
+				in.SchoolID, *in.TeacherID,
+			).Count(&n).Error; err != nil {
+				return err
+			}
+			if n == 0 {
+				return reject(attendanceModel.SyncOpStatusRejected, "bukan sesi guru ini")
+			}
+		}
+		if ss.Locked || ss.AttendanceStatus == string(attendanceModel.AttendanceStatusClosed) {
+			return reject(attendanceModel.SyncOpStatusLocked, "absensi sesi sudah dikunci/ditutup")
+		}
+
+		// participant (FOR UPDATE)
+		var m attendanceModel.ClassAttendanceSessionParticipantModel
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where(`class_attendance_session_participant_school_id = ?
+			   AND class_attendance_session_participant_session_id = ?
+			   AND class_attendance_session_participant_school_student_id = ?
+			   AND class_attendance_session_participant_deleted_at IS NULL`, in.SchoolID, ss.ID, op.SchoolStudentID).
+			Take(&m).Error
+		exists := err == nil
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		var st *syncRosterStudent
+		if !exists {
+			// siswa baru boleh dibuatkan participant hanya bila ada di roster CSST sesi
+			if ss.CSSTID != nil {
+				rows, err := rosterStudents(ctx, tx, in.SchoolID, []uuid.UUID{*ss.CSSTID}, ss.Date)
+				if err != nil {
+					return err
+				}
+				for i := range rows {
+					if rows[i].StudentID == op.SchoolStudentID {
+						st = &rows[i]
+						break
+					}
+				}
+			}
+			if st == nil {
+				return reject(attendanceModel.SyncOpStatusRejected, "siswa bukan peserta sesi ini")
+			}
+		} else {
+			id := m.ClassAttendanceSessionParticipantID
+			participantID = &id
+			server := SyncRosterRow{SchoolStudentID: op.SchoolStudentID}
+			rosterFromParticipant(&server, &m)
+
+			if m.ClassAttendanceSessionParticipantLockedAt != nil {
+				res.Server = &server
+				return reject(attendanceModel.SyncOpStatusLocked, "kehadiran sudah dikunci")
+			}
+			if sm := m.ClassAttendanceSessionParticipantMarkedAt; sm != nil && sm.After(clientMarked) {
+				res.Server = &server
+				return reject(attendanceModel.SyncOpStatusConflict, "server punya penandaan lebih baru")
+			}
+			if op.BaseVersion != nil && *op.BaseVersion != participantVersion(&m) {
+				res.Overwrote = true
+			}
+		}
+
+		prevState = m.ClassAttendanceSessionParticipantState
+		method := attendanceModel.ParticipantMethodManual
+		stamp := now.Truncate(time.Microsecond)
+
+		m.ClassAttendanceSessionParticipantState = attendanceModel.AttendanceState(op.State)
+		m.ClassAttendanceSessionParticipantMarkedAt = &clientMarked
+		if in.TeacherID != nil {
+			m.ClassAttendanceSessionParticipantMarkedByTeacherID = in.TeacherID
+		}
+		m.ClassAttendanceSessionParticipantMethod = &method
+		if op.TeacherNote != nil {
+			m.ClassAttendanceSessionParticipantTeacherNote = op.TeacherNote
+		}
+		if op.CheckinAt != nil {
+			t := op.CheckinAt.UTC()
+			m.ClassAttendanceSessionParticipantCheckinAt = &t
+		}
+		m.ClassAttendanceSessionParticipantUpdatedAt = stamp
+
+		if exists {
+			if err := tx.Save(&m).Error; err != nil {
+				return err
+			}
+		} else {
+			sid := op.SchoolStudentID
+			m.ClassAttendanceSessionParticipantSchoolID = in.SchoolID
+			m.ClassAttendanceSessionParticipantSessionID = ss.ID
+			m.ClassAttendanceSessionParticipantKind = attendanceModel.ParticipantKindStudent
+			m.ClassAttendanceSessionParticipantSchoolStudentID = &sid
+			m.ClassAttendanceSessionParticipantCreatedAt = stamp
+			m.ClassAttendanceSessionParticipantUserProfileNameSnapshot = st.Name
+			m.ClassAttendanceSessionParticipantUserProfileAvatarURLSnapshot = st.AvatarURL
+			m.ClassAttendanceSessionParticipantUserProfileWhatsappURLSnapshot = st.WhatsappURL
+			m.ClassAttendanceSessionParticipantUserProfileParentNameSnapshot = st.ParentName
+			m.ClassAttendanceSessionParticipantUserProfileParentWhatsappURLSnapshot = st.ParentWhatsappURL
+			m.ClassAttendanceSessionParticipantUserProfileGenderSnapshot = st.Gender
+			if err := tx.Create(&m).Error; err != nil {
+				return err
+			}
+			id := m.ClassAttendanceSessionParticipantID
+			participantID = &id
+		}
+
+		server := SyncRosterRow{SchoolStudentID: op.SchoolStudentID}
+		rosterFromParticipant(&server, &m)
+		res.Server = &server
+		res.Status = attendanceModel.SyncOpStatusApplied
+		if err := record(); err != nil {
+			return err
+		}
+		applied = &m
+		return nil
+	})
+	if errors.Is(err, errSyncOpRace) {
+		// batch yang sama dikirim paralel → pakai hasil yang sudah tersimpan
+		prev, ferr := s.findOp(ctx, s.DB, in.SchoolID, op.ClientOpID)
+		if ferr != nil || prev == nil {
+			return res, ferr
+		}
+		return *prev, nil
+	}
+	if err != nil {
+		return res, err
+	}
+
+	// webhook attendance.absent hanya saat transisi ke absent (di luar tx)
+	if applied != nil && prevState != attendanceModel.AttendanceStateAbsent {
+		EmitAbsentWebhook(ctx, s.DB, applied)
+	}
+	return res, nil
+}
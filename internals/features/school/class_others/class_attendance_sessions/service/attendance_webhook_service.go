@@ -0,0 +1,50 @@
+// file: internals/features/school/class_others/class_attendance_sessions/service/attendance_webhook_service.go
+package service
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	webhookmodel "madinahsalam_backend/internals/features/lembaga/webhooks/model"
+	webhooksvc "madinahsalam_backend/internals/features/lembaga/webhooks/service"
+	attendanceModel "madinahsalam_backend/internals/features/school/class_others/class_attendance_sessions/model"
+)
+
+// EmitAbsentWebhook: antre attendance.absent untuk peserta siswa.
+// Dipanggil saat state BERUBAH menjadi absent (create langsung absent / patch → absent);
+// snapshot nama & WA orang tua ikut dikirim (kebutuhan bot WhatsApp sekolah).
+func EmitAbsentWebhook(ctx context.Context, db *gorm.DB, m *attendanceModel.ClassAttendanceSessionParticipantModel) {
+	if m == nil ||
+		m.ClassAttendanceSessionParticipantKind != attendanceModel.ParticipantKindStudent ||
+		m.ClassAttendanceSessionParticipantState != attendanceModel.AttendanceStateAbsent {
+		return
+	}
+
+	var sess struct {
+		Date   time.Time  `gorm:"column:class_attendance_session_date"`
+		Starts *time.Time `gorm:"column:class_attendance_session_starts_at"`
+		Title  *string    `gorm:"column:class_attendance_session_title"`
+	}
+	_ = db.WithContext(ctx).
+		Table("class_attendance_sessions").
+		Select("class_attendance_session_date, class_attendance_session_starts_at, class_attendance_session_title").
+		Where("class_attendance_session_id = ?", m.ClassAttendanceSessionParticipantSessionID).
+		Take(&sess).Error
+
+	_ = webhooksvc.Emit(ctx, db, m.ClassAttendanceSessionParticipantSchoolID, webhookmodel.EventAttendanceAbsent, "",
+		map[string]any{
+			"class_attendance_session_participant_id": m.ClassAttendanceSessionParticipantID,
+			"class_attendance_session_id":             m.ClassAttendanceSessionParticipantSessionID,
+			"session_date":                            sess.Date.Format("2006-01-02"),
+			"session_starts_at":                       sess.Starts,
+			"session_title":                           sess.Title,
+			"school_student_id":                       m.ClassAttendanceSessionParticipantSchoolStudentID,
+			"student_name":                            m.ClassAttendanceSessionParticipantUserProfileNameSnapshot,
+			"parent_name":                             m.ClassAttendanceSessionParticipantUserProfileParentNameSnapshot,
+			"parent_whatsapp_url":                     m.ClassAttendanceSessionParticipantUserProfileParentWhatsappURLSnapshot,
+			"marked_at":                               m.ClassAttendanceSessionParticipantMarkedAt,
+			"teacher_note":                            m.ClassAttendanceSessionParticipantTeacherNote,
+		})
+}
@@ -0,0 +1,154 @@
+// file: internals/features/school/class_others/class_materials/controller/classes/class_material_learning_path_controller.go
+package controller
+
+import (
+	"errors"
+	"log"
+
+	"madinahsalam_backend/internals/features/school/class_others/class_materials/dto"
+	"madinahsalam_backend/internals/features/school/class_others/class_materials/service"
+	helper "madinahsalam_backend/internals/helpers"
+	helperAuth "madinahsalam_backend/internals/helpers/auth"
+
+	dbtime "madinahsalam_backend/internals/helpers/dbtime"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+/* =========================================================
+   Learning path (guru/DKM)
+
+   GET  /api/t/csst/:csst_id/materials/:material_id/prerequisites
+   PUT  /api/t/csst/:csst_id/materials/:material_id/prerequisites  {"prerequisites":[{"kind":"material|quiz","required_material_id","required_quiz_id","min_percent"}]}
+   GET  /api/t/csst/:csst_id/materials/completion-report
+   POST /api/t/csst/:csst_id/materials/sync-rapor
+========================================================= */
+
+func learningPathError(c *fiber.Ctx, err error) error {
+	var fe *fiber.Error
+	switch {
+	case errors.As(err, &fe):
+		return helper.JsonError(c, fe.Code, fe.Message)
+	case errors.Is(err, service.ErrMaterialNotFound):
+		return helper.JsonError(c, fiber.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrNotEnrolled):
+		return helper.JsonError(c, fiber.StatusForbidden, err.Error())
+	case errors.Is(err, service.ErrPrereqInvalid), errors.Is(err, service.ErrPrereqSelf),
+		errors.Is(err, service.ErrPrereqMaterialSide), errors.Is(err, service.ErrPrereqQuizNotFound):
+		return helper.JsonError(c, fiber.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrPrereqCycle):
+		return helper.JsonError(c, fiber.StatusConflict, err.Error())
+	}
+	log.Printf("[LearningPath] error: %v", err)
+	return helper.JsonError(c, fiber.StatusInternalServerError, "failed to process learning path")
+}
+
+func parseCSSTAndMaterial(c *fiber.Ctx) (uuid.UUID, uuid.UUID, error) {
+	csstID, err := uuid.Parse(c.Params("csst_id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fiber.NewError(fiber.StatusBadRequest, "invalid csst_id")
+	}
+	materialID, err := uuid.Parse(c.Params("material_id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fiber.NewError(fiber.StatusBadRequest, "invalid material_id")
+	}
+	return csstID, materialID, nil
+}
+
+// GET /api/t/csst/:csst_id/materials/:material_id/prerequisites
+func (h *ClassMaterialsController) ListPrerequisites(c *fiber.Ctx) error {
+	schoolID, err := helperAuth.ResolveSchoolForDKMOrTeacher(c)
+	if err != nil {
+		return err
+	}
+	csstID, materialID, err := parseCSSTAndMaterial(c)
+	if err != nil {
+		return learningPathError(c, err)
+	}
+
+	rows, err := service.ListPrerequisites(c.Context(), h.DB, schoolID, csstID, materialID)
+	if err != nil {
+		return learningPathError(c, err)
+	}
+	return helper.JsonOK(c, "ok", rows)
+}
+
+// PUT /api/t/csst/:csst_id/materials/:material_id/prerequisites
+// 🔐 khusus DKM/Admin sekolah (sama dengan create/update materi)
+func (h *ClassMaterialsController) PutPrerequisites(c *fiber.Ctx) error {
+	schoolID, err := helperAuth.ResolveSchoolIDFromContext(c)
+	if err != nil {
+		return err
+	}
+	if err := helperAuth.EnsureDKMSchool(c, schoolID); err != nil {
+		return err
+	}
+	csstID, materialID, err := parseCSSTAndMaterial(c)
+	if err != nil {
+		return learningPathError(c, err)
+	}
+
+	var req dto.ClassMaterialPrerequisitesPutRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "invalid body")
+	}
+
+	rows, err := service.SetPrerequisites(c.Context(), h.DB, schoolID, csstID, materialID, req.ToInputs())
+	if err != nil {
+		return learningPathError(c, err)
+	}
+	return helper.JsonUpdated(c, "updated", rows)
+}
+
+// GET /api/t/csst/:csst_id/materials/completion-report
+func (h *ClassMaterialsController) CompletionReport(c *fiber.Ctx) error {
+	schoolID, err := helperAuth.ResolveSchoolForDKMOrTeacher(c)
+	if err != nil {
+		return err
+	}
+	csstID, err := uuid.Parse(c.Params("csst_id"))
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "invalid csst_id")
+	}
+
+	now, err := dbtime.GetDBTime(c)
+	if err != nil {
+		log.Printf("[CompletionReport] get db time error: %v", err)
+		return helper.JsonError(c, fiber.StatusInternalServerError, "failed to get server time")
+	}
+
+	rep, err := service.Report(c.Context(), h.DB, schoolID, csstID, now)
+	if err != nil {
+		return learningPathError(c, err)
+	}
+	return helper.JsonOK(c, "ok", rep)
+}
+
+// POST /api/t/csst/:csst_id/materials/sync-rapor
+// 🔐 khusus DKM/Admin sekolah
+func (h *ClassMaterialsController) SyncRapor(c *fiber.Ctx) error {
+	schoolID, err := helperAuth.ResolveSchoolIDFromContext(c)
+	if err != nil {
+		return err
+	}
+	if err := helperAuth.EnsureDKMSchool(c, schoolID); err != nil {
+		return err
+	}
+	csstID, err := uuid.Parse(c.Params("csst_id"))
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "invalid csst_id")
+	}
+
+	now, err := dbtime.GetDBTime(c)
+	if err != nil {
+		log.Printf("[SyncRapor] get db time error: %v", err)
+		return helper.JsonError(c, fiber.StatusInternalServerError, "failed to get server time")
+	}
+
+	res, err := service.SyncRapor(c.Context(), h.DB, schoolID, csstID, now)
+	if err != nil {
+		return learningPathError(c, err)
+	}
+	return helper.JsonOK(c, "rapor synced", res)
+}
@@ -0,0 +1,310 @@
+// file: internals/features/school/class_others/class_materials/controller/libraries/material_libraries_controller.go
+package controller
+
+import (
+	"errors"
+	"log"
+	"strings"
+
+	"madinahsalam_backend/internals/features/school/class_others/class_materials/dto"
+	"madinahsalam_backend/internals/features/school/class_others/class_materials/service"
+	helper "madinahsalam_backend/internals/helpers"
+	helperAuth "madinahsalam_backend/internals/helpers/auth"
+
+	dbtime "madinahsalam_backend/internals/helpers/dbtime"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+/* =========================================================
+   Material library (berbagi materi lintas sekolah)
+
+   Penerbit (DKM/Admin):
+   GET    /material-libraries/mine
+   POST   /material-libraries                 {"material_library_title","material_library_visibility":"yayasan|public","material_library_license","material_library_attribution","items":[{"kind":"material|quiz","school_material_id","quiz_id"}]}
+   PATCH  /material-libraries/:id             metadata (judul, visibility, lisensi, atribusi, is_active)
+   POST   /material-libraries/:id/versions    {"changelog","items"?} → snapshot ulang → versi baru
+   DELETE /material-libraries/:id
+
+   Pengimpor (guru/DKM):
+   GET    /material-libraries/catalog?q=      paket publik + yayasan yang sama
+   GET    /material-libraries/:id             detail + snapshot versi terbaru
+   GET    /material-libraries/:id/versions
+   POST   /material-libraries/:id/import      salin ke school_materials / quizzes sekolah ini
+   GET    /material-libraries/imports         + update_available
+   POST   /material-libraries/imports/:id/sync
+========================================================= */
+
+type MaterialLibraryController struct {
+	DB *gorm.DB
+}
+
+func NewMaterialLibraryController(db *gorm.DB) *MaterialLibraryController {
+	return &MaterialLibraryController{DB: db}
+}
+
+func libraryError(c *fiber.Ctx, err error) error {
+	var fe *fiber.Error
+	switch {
+	case errors.As(err, &fe):
+		return helper.JsonError(c, fe.Code, fe.Message)
+	case errors.Is(err, service.ErrLibraryNotFound), errors.Is(err, service.ErrImportNotFound):
+		return helper.JsonError(c, fiber.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrLibraryInvalid), errors.Is(err, service.ErrLibraryNoYayasan),
+		errors.Is(err, service.ErrLibraryNoItems), errors.Is(err, service.ErrLibraryItemInvalid),
+		errors.Is(err, service.ErrLibraryOwnImport):
+		return helper.JsonError(c, fiber.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrLibraryImported):
+		return helper.JsonError(c, fiber.StatusConflict, err.Error())
+	}
+	log.Printf("[MaterialLibrary] error: %v", err)
+	return helper.JsonError(c, fiber.StatusInternalServerError, "failed to process material library")
+}
+
+func parseLibraryID(c *fiber.Ctx) (uuid.UUID, error) {
+	id, err := uuid.Parse(strings.TrimSpace(c.Params("id")))
+	if err != nil {
+		return uuid.Nil, fiber.NewError(fiber.StatusBadRequest, "invalid id")
+	}
+	return id, nil
+}
+
+func currentUserID(c *fiber.Ctx) *uuid.UUID {
+	uid, err := helperAuth.GetUserIDFromToken(c)
+	if err != nil || uid == uuid.Nil {
+		return nil
+	}
+	return &uid
+}
+
+// dkmSchool: penerbitan paket = keputusan sekolah → khusus DKM/Admin.
+func dkmSchool(c *fiber.Ctx) (uuid.UUID, error) {
+	schoolID, err := helperAuth.ResolveSchoolIDFromContext(c)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if err := helperAuth.EnsureDKMSchool(c, schoolID); err != nil {
+		return uuid.Nil, err
+	}
+	return schoolID, nil
+}
+
+/* =========================
+   Penerbit
+   ========================= */
+
+// GET /material-libraries/mine
+func (h *MaterialLibraryController) ListMine(c *fiber.Ctx) error {
+	schoolID, err := helperAuth.ResolveSchoolForDKMOrTeacher(c)
+	if err != nil {
+		return err
+	}
+	rows, err := service.ListMyLibraries(c.Context(), h.DB, schoolID)
+	if err != nil {
+		return libraryError(c, err)
+	}
+	return helper.JsonOK(c, "ok", rows)
+}
+
+// POST /material-libraries
+func (h *MaterialLibraryController) Publish(c *fiber.Ctx) error {
+	schoolID, err := dkmSchool(c)
+	if err != nil {
+		return err
+	}
+	var req dto.MaterialLibraryPublishRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "invalid body")
+	}
+
+	out, err := service.PublishLibrary(c.Context(), h.DB, schoolID, currentUserID(c), req.ToInput())
+	if err != nil {
+		return libraryError(c, err)
+	}
+	return helper.JsonCreated(c, "published", out)
+}
+
+// PATCH /material-libraries/:id
+func (h *MaterialLibraryController) Update(c *fiber.Ctx) error {
+	schoolID, err := dkmSchool(c)
+	if err != nil {
+		return err
+	}
+	id, err := parseLibraryID(c)
+	if err != nil {
+		return libraryError(c, err)
+	}
+	var req dto.MaterialLibraryPatchRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "invalid body")
+	}
+
+	lib, err := service.UpdateLibrary(c.Context(), h.DB, schoolID, id, req.ToInput())
+	if err != nil {
+		return libraryError(c, err)
+	}
+	return helper.JsonUpdated(c, "updated", lib)
+}
+
+// POST /material-libraries/:id/versions
+func (h *MaterialLibraryController) PublishVersion(c *fiber.Ctx) error {
+	schoolID, err := dkmSchool(c)
+	if err != nil {
+		return err
+	}
+	id, err := parseLibraryID(c)
+	if err != nil {
+		return libraryError(c, err)
+	}
+	var req dto.MaterialLibraryVersionRequestDTO
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return helper.JsonError(c, fiber.StatusBadRequest, "invalid body")
+		}
+	}
+
+	v, err := service.PublishVersion(c.Context(), h.DB, schoolID, id, currentUserID(c), req.ItemInputs(), req.Changelog)
+	if err != nil {
+		return libraryError(c, err)
+	}
+	return helper.JsonCreated(c, "version published", v)
+}
+
+// DELETE /material-libraries/:id
+func (h *MaterialLibraryController) Delete(c *fiber.Ctx) error {
+	schoolID, err := dkmSchool(c)
+	if err != nil {
+		return err
+	}
+	id, err := parseLibraryID(c)
+	if err != nil {
+		return libraryError(c, err)
+	}
+	now, err := dbtime.GetDBTime(c)
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusInternalServerError, "failed to get server time")
+	}
+
+	if err := service.DeleteLibrary(c.Context(), h.DB, schoolID, id, now); err != nil {
+		return libraryError(c, err)
+	}
+	return helper.JsonDeleted(c, "deleted", nil)
+}
+
+/* =========================
+   Katalog & pengimpor
+   ========================= */
+
+// GET /material-libraries/catalog?q=&page=&per_page=
+func (h *MaterialLibraryController) Catalog(c *fiber.Ctx) error {
+	schoolID, err := helperAuth.ResolveSchoolForDKMOrTeacher(c)
+	if err != nil {
+		return err
+	}
+	p := helper.ResolvePaging(c, 20, 100)
+
+	rows, total, err := service.Catalog(c.Context(), h.DB, schoolID, c.Query("q"), p.Offset, p.Limit)
+	if err != nil {
+		return libraryError(c, err)
+	}
+	return helper.JsonList(c, "ok", rows, helper.BuildPaginationFromOffset(total, p.Offset, p.Limit))
+}
+
+// GET /material-libraries/:id
+func (h *MaterialLibraryController) Get(c *fiber.Ctx) error {
+	schoolID, err := helperAuth.ResolveSchoolForDKMOrTeacher(c)
+	if err != nil {
+		return err
+	}
+	id, err := parseLibraryID(c)
+	if err != nil {
+		return libraryError(c, err)
+	}
+
+	out, err := service.GetLibrary(c.Context(), h.DB, schoolID, id)
+	if err != nil {
+		return libraryError(c, err)
+	}
+	return helper.JsonOK(c, "ok", out)
+}
+
+// GET /material-libraries/:id/versions
+func (h *MaterialLibraryController) ListVersions(c *fiber.Ctx) error {
+	schoolID, err := helperAuth.ResolveSchoolForDKMOrTeacher(c)
+	if err != nil {
+		return err
+	}
+	id, err := parseLibraryID(c)
+	if err != nil {
+		return libraryError(c, err)
+	}
+
+	rows, err := service.ListVersions(c.Context(), h.DB, schoolID, id)
+	if err != nil {
+		return libraryError(c, err)
+	}
+	return helper.JsonOK(c, "ok", rows)
+}
+
+// POST /material-libraries/:id/import
+func (h *MaterialLibraryController) Import(c *fiber.Ctx) error {
+	schoolID, err := helperAuth.ResolveSchoolForDKMOrTeacher(c)
+	if err != nil {
+		return err
+	}
+	id, err := parseLibraryID(c)
+	if err != nil {
+		return libraryError(c, err)
+	}
+	now, err := dbtime.GetDBTime(c)
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusInternalServerError, "failed to get server time")
+	}
+
+	res, err := service.ImportLibrary(c.Context(), h.DB, schoolID, id, currentUserID(c), now)
+	if err != nil {
+		return libraryError(c, err)
+	}
+	return helper.JsonCreated(c, "imported", res)
+}
+
+// GET /material-libraries/imports
+func (h *MaterialLibraryController) ListImports(c *fiber.Ctx) error {
+	schoolID, err := helperAuth.ResolveSchoolForDKMOrTeacher(c)
+	if err != nil {
+		return err
+	}
+	rows, err := service.ListImports(c.Context(), h.DB, schoolID)
+	if err != nil {
+		return libraryError(c, err)
+	}
+	return helper.JsonOK(c, "ok", rows)
+}
+
+// POST /material-libraries/imports/:id/sync
+func (h *MaterialLibraryController) SyncImport(c *fiber.Ctx) error {
+	schoolID, err := helperAuth.ResolveSchoolForDKMOrTeacher(c)
+	if err != nil {
+		return err
+	}
+	id, err := parseLibraryID(c)
+	if err != nil {
+		return libraryError(c, err)
+	}
+	now, err := dbtime.GetDBTime(c)
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusInternalServerError, "failed to get server time")
+	}
+
+	res, err := service.SyncImport(c.Context(), h.DB, schoolID, id, currentUserID(c), now)
+	if err != nil {
+		return libraryError(c, err)
+	}
+	msg := "synced"
+	if res.UpToDate {
+		msg = "already up to date"
+	}
+	return helper.JsonOK(c, msg, res)
+}
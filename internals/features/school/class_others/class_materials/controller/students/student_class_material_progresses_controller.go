@@ -2,6 +2,8 @@
 package controller
 
 import (
+	"errors"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -12,6 +14,7 @@ import (
 
 	dto "madinahsalam_backend/internals/features/school/class_others/class_materials/dto"
 	model "madinahsalam_backend/internals/features/school/class_others/class_materials/model"
+	"madinahsalam_backend/internals/features/school/class_others/class_materials/service"
 )
 
 /* =======================================================
@@ -62,6 +65,19 @@ func (ctl *StudentClassMaterialProgressController) PingMyClassMaterialProgress(c
 	// 🔹 waktu "sekarang" pakai dbtime (timezone sekolah)
 	now, _ := dbtime.GetDBTime(c)
 
+	// Learning path: materi terkunci (belum rilis / prasyarat belum terpenuhi) tidak boleh dicatat
+	if locks, err := service.EnsureMaterialUnlocked(c.Context(), ctl.DB, schoolID, body.StudentClassMaterialProgressClassMaterialID, studentID, now); err != nil {
+		switch {
+		case errors.Is(err, service.ErrMaterialLocked):
+			return helper.JsonError(c, fiber.StatusForbidden, service.DescribeLocks(locks))
+		case errors.Is(err, service.ErrMaterialNotFound):
+			return helper.JsonError(c, fiber.StatusNotFound, "material not found")
+		case errors.Is(err, service.ErrNotEnrolled):
+			return helper.JsonError(c, fiber.StatusForbidden, err.Error())
+		}
+		return helper.JsonError(c, fiber.StatusInternalServerError, "failed to check material access")
+	}
+
 	// Upsert berdasarkan (school_id, scsst_id, class_material_id)
 	var progress model.StudentClassMaterialProgressModel
 	tx := ctl.DB.WithContext(c.Context())
@@ -0,0 +1,46 @@
+// file: internals/features/school/class_others/class_materials/controller/students/student_learning_path_controller.go
+package controller
+
+import (
+	"errors"
+
+	"madinahsalam_backend/internals/features/school/class_others/class_materials/service"
+	helper "madinahsalam_backend/internals/helpers"
+	helperAuth "madinahsalam_backend/internals/helpers/auth"
+	"madinahsalam_backend/internals/helpers/dbtime"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+/* =======================================================
+   Learning path murid yang login
+   GET /.../student/class-material-progress/learning-path/:csst_id
+   - materi + status terbuka/terkunci (alasan: rilis, materi syarat, quiz syarat)
+======================================================= */
+
+func (ctl *StudentClassMaterialProgressController) MyLearningPath(c *fiber.Ctx) error {
+	csstID, err := uuid.Parse(c.Params("csst_id"))
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "invalid csst_id")
+	}
+
+	schoolID, err := helperAuth.ResolveSchoolIDFromContext(c)
+	if err != nil {
+		return err
+	}
+	studentID, err := helperAuth.ResolveStudentIDFromContext(c, schoolID)
+	if err != nil {
+		return err
+	}
+
+	now, _ := dbtime.GetDBTime(c)
+	path, err := service.StudentLearningPath(c.Context(), ctl.DB, schoolID, csstID, studentID, now)
+	if err != nil {
+		if errors.Is(err, service.ErrNotEnrolled) {
+			return helper.JsonError(c, fiber.StatusForbidden, err.Error())
+		}
+		return helper.JsonError(c, fiber.StatusInternalServerError, "failed to get learning path")
+	}
+	return helper.JsonOK(c, "ok", path)
+}
@@ -0,0 +1,39 @@
+// file: internals/features/school/class_others/class_materials/dto/class_material_prerequisites_dto.go
+package dto
+
+import (
+	"madinahsalam_backend/internals/features/school/class_others/class_materials/service"
+
+	"github.com/google/uuid"
+)
+
+/* =========================================================
+   Prasyarat materi (learning path)
+========================================================= */
+
+type ClassMaterialPrerequisiteItemDTO struct {
+	// material | quiz
+	Kind               string     `json:"kind"`
+	RequiredMaterialID *uuid.UUID `json:"required_material_id,omitempty"`
+	RequiredQuizID     *uuid.UUID `json:"required_quiz_id,omitempty"`
+	// khusus quiz: nilai minimal (0..100)
+	MinPercent *float64 `json:"min_percent,omitempty"`
+}
+
+// PUT /csst/:csst_id/materials/:material_id/prerequisites — mengganti seluruh prasyarat
+type ClassMaterialPrerequisitesPutRequestDTO struct {
+	Prerequisites []ClassMaterialPrerequisiteItemDTO `json:"prerequisites"`
+}
+
+func (r ClassMaterialPrerequisitesPutRequestDTO) ToInputs() []service.PrereqInput {
+	out := make([]service.PrereqInput, 0, len(r.Prerequisites))
+	for _, p := range r.Prerequisites {
+		out = append(out, service.PrereqInput{
+			Kind:               p.Kind,
+			RequiredMaterialID: p.RequiredMaterialID,
+			RequiredQuizID:     p.RequiredQuizID,
+			MinPercent:         p.MinPercent,
+		})
+	}
+	return out
+}
@@ -51,6 +51,11 @@ type ClassMaterialCreateRequestDTO struct {
 	ClassMaterialMeetingNumber *int `json:"class_material_meeting_number,omitempty"`
 	ClassMaterialOrder         *int `json:"class_material_order,omitempty"`
 
+	// rilis bertahap: tanggal tertentu / ikut jam mulai sesi absensi
+	ClassMaterialSessionID          *uuid.UUID `json:"class_material_session_id,omitempty"`
+	ClassMaterialReleaseAt          *time.Time `json:"class_material_release_at,omitempty"`
+	ClassMaterialReleaseWithSession *bool      `json:"class_material_release_with_session,omitempty"`
+
 	// info sumber
 	// "school" kalau dari template, "teacher" kalau manual (boleh backend yang isi)
 	ClassMaterialSourceKind             *string    `json:"class_material_source_kind,omitempty"`
@@ -86,6 +91,10 @@ type ClassMaterialUpdateRequestDTO struct {
 	ClassMaterialMeetingNumber *int `json:"class_material_meeting_number,omitempty"`
 	ClassMaterialOrder         *int `json:"class_material_order,omitempty"`
 
+	ClassMaterialSessionID          *uuid.UUID `json:"class_material_session_id,omitempty"`
+	ClassMaterialReleaseAt          *time.Time `json:"class_material_release_at,omitempty"`
+	ClassMaterialReleaseWithSession *bool      `json:"class_material_release_with_session,omitempty"`
+
 	ClassMaterialIsActive    *bool `json:"class_material_is_active,omitempty"`
 	ClassMaterialIsPublished *bool `json:"class_material_is_published,omitempty"`
 }
@@ -124,6 +133,9 @@ type ClassMaterialResponseDTO struct {
 	ClassMaterialMeetingNumber *int       `json:"class_material_meeting_number,omitempty"`
 	ClassMaterialSessionID     *uuid.UUID `json:"class_material_session_id,omitempty"`
 
+	ClassMaterialReleaseAt          *time.Time `json:"class_material_release_at,omitempty"`
+	ClassMaterialReleaseWithSession bool       `json:"class_material_release_with_session"`
+
 	ClassMaterialSourceKind             *string    `json:"class_material_source_kind,omitempty"`
 	ClassMaterialSourceSchoolMaterialID *uuid.UUID `json:"class_material_source_school_material_id,omitempty"`
 
@@ -175,6 +187,9 @@ func FromModel(m *model.ClassMaterialsModel) *ClassMaterialResponseDTO {
 		ClassMaterialMeetingNumber: m.ClassMaterialMeetingNumber,
 		ClassMaterialSessionID:     m.ClassMaterialSessionID,
 
+		ClassMaterialReleaseAt:          m.ClassMaterialReleaseAt,
+		ClassMaterialReleaseWithSession: m.ClassMaterialReleaseWithSession,
+
 		ClassMaterialSourceKind:             m.ClassMaterialSourceKind,
 		ClassMaterialSourceSchoolMaterialID: m.ClassMaterialSourceSchoolMaterialID,
 
@@ -242,6 +257,13 @@ func ApplyCreateDTOToModel(
 	m.ClassMaterialMeetingNumber = req.ClassMaterialMeetingNumber
 	m.ClassMaterialOrder = req.ClassMaterialOrder
 
+	// rilis
+	m.ClassMaterialSessionID = req.ClassMaterialSessionID
+	m.ClassMaterialReleaseAt = req.ClassMaterialReleaseAt
+	if req.ClassMaterialReleaseWithSession != nil {
+		m.ClassMaterialReleaseWithSession = *req.ClassMaterialReleaseWithSession
+	}
+
 	// source
 	m.ClassMaterialSourceKind = req.ClassMaterialSourceKind
 	m.ClassMaterialSourceSchoolMaterialID = req.ClassMaterialSourceSchoolMaterialID
@@ -320,6 +342,16 @@ func ApplyUpdateDTOToModel(
 		m.ClassMaterialOrder = req.ClassMaterialOrder
 	}
 
+	if req.ClassMaterialSessionID != nil {
+		m.ClassMaterialSessionID = req.ClassMaterialSessionID
+	}
+	if req.ClassMaterialReleaseAt != nil {
+		m.ClassMaterialReleaseAt = req.ClassMaterialReleaseAt
+	}
+	if req.ClassMaterialReleaseWithSession != nil {
+		m.ClassMaterialReleaseWithSession = *req.ClassMaterialReleaseWithSession
+	}
+
 	if req.ClassMaterialIsActive != nil {
 		m.ClassMaterialIsActive = *req.ClassMaterialIsActive
 	}
@@ -0,0 +1,92 @@
+// file: internals/features/school/class_others/class_materials/dto/material_libraries_dto.go
+package dto
+
+import (
+	"madinahsalam_backend/internals/features/school/class_others/class_materials/service"
+
+	"github.com/google/uuid"
+)
+
+/* =========================================================
+   Material library (berbagi materi lintas sekolah)
+========================================================= */
+
+type MaterialLibraryItemDTO struct {
+	// material | quiz
+	Kind             string     `json:"kind"`
+	SchoolMaterialID *uuid.UUID `json:"school_material_id,omitempty"`
+	QuizID           *uuid.UUID `json:"quiz_id,omitempty"`
+}
+
+func toLibraryItemInputs(items []MaterialLibraryItemDTO) []service.LibraryItemInput {
+	out := make([]service.LibraryItemInput, 0, len(items))
+	for _, it := range items {
+		out = append(out, service.LibraryItemInput{
+			Kind:             it.Kind,
+			SchoolMaterialID: it.SchoolMaterialID,
+			QuizID:           it.QuizID,
+		})
+	}
+	return out
+}
+
+// POST /material-libraries — terbitkan paket (versi 1)
+type MaterialLibraryPublishRequestDTO struct {
+	Title       string  `json:"material_library_title"`
+	Description *string `json:"material_library_description"`
+	// yayasan | public
+	Visibility string `json:"material_library_visibility"`
+	// contoh: CC-BY-4.0, CC-BY-NC-SA-4.0, all-rights-reserved
+	License string `json:"material_library_license"`
+	// kosong → nama sekolah penerbit
+	Attribution *string                  `json:"material_library_attribution"`
+	Changelog   *string                  `json:"changelog"`
+	Items       []MaterialLibraryItemDTO `json:"items"`
+}
+
+func (r MaterialLibraryPublishRequestDTO) ToInput() service.LibraryPublishInput {
+	return service.LibraryPublishInput{
+		Title:       r.Title,
+		Description: r.Description,
+		Visibility:  r.Visibility,
+		License:     r.License,
+		Attribution: r.Attribution,
+		Changelog:   r.Changelog,
+		Items:       toLibraryItemInputs(r.Items),
+	}
+}
+
+// PATCH /material-libraries/:id — metadata saja
+type MaterialLibraryPatchRequestDTO struct {
+	Title       *string `json:"material_library_title"`
+	Description *string `json:"material_library_description"`
+	Visibility  *string `json:"material_library_visibility"`
+	License     *string `json:"material_library_license"`
+	Attribution *string `json:"material_library_attribution"`
+	IsActive    *bool   `json:"material_library_is_active"`
+}
+
+func (r MaterialLibraryPatchRequestDTO) ToInput() service.LibraryPatchInput {
+	return service.LibraryPatchInput{
+		Title:       r.Title,
+		Description: r.Description,
+		Visibility:  r.Visibility,
+		License:     r.License,
+		Attribution: r.Attribution,
+		IsActive:    r.IsActive,
+	}
+}
+
+// POST /material-libraries/:id/versions — snapshot ulang isi sumber
+// items kosong/tidak dikirim → daftar item lama dipakai
+type MaterialLibraryVersionRequestDTO struct {
+	Changelog *string                  `json:"changelog"`
+	Items     []MaterialLibraryItemDTO `json:"items"`
+}
+
+func (r MaterialLibraryVersionRequestDTO) ItemInputs() []service.LibraryItemInput {
+	if len(r.Items) == 0 {
+		return nil
+	}
+	return toLibraryItemInputs(r.Items)
+}
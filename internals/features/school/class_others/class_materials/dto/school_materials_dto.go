@@ -50,6 +50,11 @@ type SchoolMaterialResponse struct {
 
 	SchoolMaterialScopeTag *string `json:"school_material_scope_tag"`
 
+	// asal salinan (import material library)
+	SchoolMaterialOriginLibraryID *uuid.UUID `json:"school_material_origin_library_id,omitempty"`
+	SchoolMaterialOriginSourceID  *uuid.UUID `json:"school_material_origin_source_id,omitempty"`
+	SchoolMaterialOriginVersion   *int       `json:"school_material_origin_version,omitempty"`
+
 	SchoolMaterialIsActive    bool       `json:"school_material_is_active"`
 	SchoolMaterialIsPublished bool       `json:"school_material_is_published"`
 	SchoolMaterialPublishedAt *time.Time `json:"school_material_published_at"`
@@ -202,6 +207,9 @@ func NewSchoolMaterialResponse(m *materialModel.SchoolMaterialModel) *SchoolMate
 		SchoolMaterialMeetingNumber:     m.SchoolMaterialMeetingNumber,
 		SchoolMaterialDefaultOrder:      m.SchoolMaterialDefaultOrder,
 		SchoolMaterialScopeTag:          m.SchoolMaterialScopeTag,
+		SchoolMaterialOriginLibraryID:   m.SchoolMaterialOriginLibraryID,
+		SchoolMaterialOriginSourceID:    m.SchoolMaterialOriginSourceID,
+		SchoolMaterialOriginVersion:     m.SchoolMaterialOriginVersion,
 		SchoolMaterialIsActive:          m.SchoolMaterialIsActive,
 		SchoolMaterialIsPublished:       m.SchoolMaterialIsPublished,
 		SchoolMaterialPublishedAt:       m.SchoolMaterialPublishedAt,
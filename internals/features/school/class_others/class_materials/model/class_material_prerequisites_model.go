@@ -0,0 +1,34 @@
+// file: internals/features/school/class_others/class_materials/model/class_material_prerequisites_model.go
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/* =========================================================
+   class_material_prerequisites — syarat buka materi
+========================================================= */
+
+const (
+	PrerequisiteKindMaterial = "material" // materi lain sudah selesai
+	PrerequisiteKindQuiz     = "quiz"     // quiz dikerjakan (nilai terbaik ≥ min_percent)
+)
+
+type ClassMaterialPrerequisiteModel struct {
+	ClassMaterialPrerequisiteID         uuid.UUID `gorm:"column:class_material_prerequisite_id;type:uuid;default:gen_random_uuid();primaryKey" json:"class_material_prerequisite_id"`
+	ClassMaterialPrerequisiteSchoolID   uuid.UUID `gorm:"column:class_material_prerequisite_school_id;type:uuid;not null" json:"class_material_prerequisite_school_id"`
+	ClassMaterialPrerequisiteMaterialID uuid.UUID `gorm:"column:class_material_prerequisite_class_material_id;type:uuid;not null" json:"class_material_prerequisite_class_material_id"`
+	ClassMaterialPrerequisiteKind       string    `gorm:"column:class_material_prerequisite_kind;type:varchar(16);not null" json:"class_material_prerequisite_kind"`
+
+	ClassMaterialPrerequisiteRequiredMaterialID *uuid.UUID `gorm:"column:class_material_prerequisite_required_material_id;type:uuid" json:"class_material_prerequisite_required_material_id,omitempty"`
+	ClassMaterialPrerequisiteRequiredQuizID     *uuid.UUID `gorm:"column:class_material_prerequisite_required_quiz_id;type:uuid" json:"class_material_prerequisite_required_quiz_id,omitempty"`
+	ClassMaterialPrerequisiteMinPercent         *float64   `gorm:"column:class_material_prerequisite_min_percent;type:numeric(5,2)" json:"class_material_prerequisite_min_percent,omitempty"`
+
+	ClassMaterialPrerequisiteCreatedAt time.Time `gorm:"column:class_material_prerequisite_created_at;not null;default:now()" json:"class_material_prerequisite_created_at"`
+}
+
+func (ClassMaterialPrerequisiteModel) TableName() string {
+	return "class_material_prerequisites"
+}
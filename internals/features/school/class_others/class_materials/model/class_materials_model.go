@@ -72,6 +72,10 @@ type ClassMaterialsModel struct {
 	ClassMaterialMeetingNumber *int       `gorm:"column:class_material_meeting_number" json:"class_material_meeting_number"`
 	ClassMaterialSessionID     *uuid.UUID `gorm:"column:class_material_session_id;type:uuid" json:"class_material_session_id"`
 
+	// rilis bertahap (learning path)
+	ClassMaterialReleaseAt          *time.Time `gorm:"column:class_material_release_at" json:"class_material_release_at"`
+	ClassMaterialReleaseWithSession bool       `gorm:"column:class_material_release_with_session;not null;default:false" json:"class_material_release_with_session"`
+
 	// source info
 	// contoh nilai: "school" | "teacher"
 	ClassMaterialSourceKind             *string    `gorm:"column:class_material_source_kind;type:text" json:"class_material_source_kind"`
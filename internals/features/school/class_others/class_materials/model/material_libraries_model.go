@@ -0,0 +1,82 @@
+// file: internals/features/school/class_others/class_materials/model/material_libraries_model.go
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+/* =========================================================
+   material_libraries — paket materi lintas sekolah
+   (diterbitkan ke yayasan atau publik, diimpor dengan atribusi)
+========================================================= */
+
+const (
+	LibraryVisibilityYayasan = "yayasan"
+	LibraryVisibilityPublic  = "public"
+
+	LibraryItemKindMaterial = "material"
+	LibraryItemKindQuiz     = "quiz"
+)
+
+type MaterialLibraryModel struct {
+	MaterialLibraryID         uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey;column:material_library_id" json:"material_library_id"`
+	MaterialLibrarySchoolID   uuid.UUID  `gorm:"type:uuid;not null;column:material_library_school_id" json:"material_library_school_id"`
+	MaterialLibraryYayasanID  *uuid.UUID `gorm:"type:uuid;column:material_library_yayasan_id" json:"material_library_yayasan_id,omitempty"`
+	MaterialLibraryVisibility string     `gorm:"type:varchar(10);not null;column:material_library_visibility" json:"material_library_visibility"`
+
+	MaterialLibraryTitle       string  `gorm:"type:varchar(180);not null;column:material_library_title" json:"material_library_title"`
+	MaterialLibraryDescription *string `gorm:"type:text;column:material_library_description" json:"material_library_description,omitempty"`
+	MaterialLibraryLicense     string  `gorm:"type:varchar(40);not null;column:material_library_license" json:"material_library_license"`
+	MaterialLibraryAttribution string  `gorm:"type:text;not null;column:material_library_attribution" json:"material_library_attribution"`
+
+	MaterialLibraryCurrentVersion  int        `gorm:"type:int;not null;default:1;column:material_library_current_version" json:"material_library_current_version"`
+	MaterialLibraryIsActive        bool       `gorm:"not null;default:true;column:material_library_is_active" json:"material_library_is_active"`
+	MaterialLibraryCreatedByUserID *uuid.UUID `gorm:"type:uuid;column:material_library_created_by_user_id" json:"material_library_created_by_user_id,omitempty"`
+
+	MaterialLibraryCreatedAt time.Time  `gorm:"type:timestamptz;not null;default:now();autoCreateTime;column:material_library_created_at" json:"material_library_created_at"`
+	MaterialLibraryUpdatedAt time.Time  `gorm:"type:timestamptz;not null;default:now();autoUpdateTime;column:material_library_updated_at" json:"material_library_updated_at"`
+	MaterialLibraryDeletedAt *time.Time `gorm:"type:timestamptz;column:material_library_deleted_at" json:"material_library_deleted_at,omitempty"`
+}
+
+func (MaterialLibraryModel) TableName() string { return "material_libraries" }
+
+// sumber paket: school_material / quiz milik sekolah penerbit
+type MaterialLibraryItemModel struct {
+	MaterialLibraryItemID               uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey;column:material_library_item_id" json:"material_library_item_id"`
+	MaterialLibraryItemLibraryID        uuid.UUID  `gorm:"type:uuid;not null;column:material_library_item_library_id" json:"material_library_item_library_id"`
+	MaterialLibraryItemKind             string     `gorm:"type:varchar(16);not null;column:material_library_item_kind" json:"material_library_item_kind"`
+	MaterialLibraryItemSchoolMaterialID *uuid.UUID `gorm:"type:uuid;column:material_library_item_school_material_id" json:"material_library_item_school_material_id,omitempty"`
+	MaterialLibraryItemQuizID           *uuid.UUID `gorm:"type:uuid;column:material_library_item_quiz_id" json:"material_library_item_quiz_id,omitempty"`
+	MaterialLibraryItemOrder            int        `gorm:"type:int;not null;default:0;column:material_library_item_order" json:"material_library_item_order"`
+}
+
+func (MaterialLibraryItemModel) TableName() string { return "material_library_items" }
+
+// snapshot isi paket per versi (lihat service.LibrarySnapshot)
+type MaterialLibraryVersionModel struct {
+	MaterialLibraryVersionID              uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primaryKey;column:material_library_version_id" json:"material_library_version_id"`
+	MaterialLibraryVersionLibraryID       uuid.UUID      `gorm:"type:uuid;not null;column:material_library_version_library_id" json:"material_library_version_library_id"`
+	MaterialLibraryVersionNumber          int            `gorm:"type:int;not null;column:material_library_version_number" json:"material_library_version_number"`
+	MaterialLibraryVersionChangelog       *string        `gorm:"type:text;column:material_library_version_changelog" json:"material_library_version_changelog,omitempty"`
+	MaterialLibraryVersionSnapshot        datatypes.JSON `gorm:"type:jsonb;not null;column:material_library_version_snapshot" json:"material_library_version_snapshot,omitempty"`
+	MaterialLibraryVersionCreatedByUserID *uuid.UUID     `gorm:"type:uuid;column:material_library_version_created_by_user_id" json:"material_library_version_created_by_user_id,omitempty"`
+	MaterialLibraryVersionCreatedAt       time.Time      `gorm:"type:timestamptz;not null;default:now();autoCreateTime;column:material_library_version_created_at" json:"material_library_version_created_at"`
+}
+
+func (MaterialLibraryVersionModel) TableName() string { return "material_library_versions" }
+
+// sekolah pengimpor + versi yang sedang dipakai
+type MaterialLibraryImportModel struct {
+	MaterialLibraryImportID               uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey;column:material_library_import_id" json:"material_library_import_id"`
+	MaterialLibraryImportLibraryID        uuid.UUID  `gorm:"type:uuid;not null;column:material_library_import_library_id" json:"material_library_import_library_id"`
+	MaterialLibraryImportSchoolID         uuid.UUID  `gorm:"type:uuid;not null;column:material_library_import_school_id" json:"material_library_import_school_id"`
+	MaterialLibraryImportVersion          int        `gorm:"type:int;not null;column:material_library_import_version" json:"material_library_import_version"`
+	MaterialLibraryImportImportedByUserID *uuid.UUID `gorm:"type:uuid;column:material_library_import_imported_by_user_id" json:"material_library_import_imported_by_user_id,omitempty"`
+	MaterialLibraryImportCreatedAt        time.Time  `gorm:"type:timestamptz;not null;default:now();autoCreateTime;column:material_library_import_created_at" json:"material_library_import_created_at"`
+	MaterialLibraryImportSyncedAt         time.Time  `gorm:"type:timestamptz;not null;default:now();column:material_library_import_synced_at" json:"material_library_import_synced_at"`
+}
+
+func (MaterialLibraryImportModel) TableName() string { return "material_library_imports" }
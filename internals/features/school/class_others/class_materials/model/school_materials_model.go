@@ -46,6 +46,11 @@ type SchoolMaterialModel struct {
 
 	SchoolMaterialScopeTag *string `json:"school_material_scope_tag" gorm:"column:school_material_scope_tag;type:text"`
 
+	// asal salinan (import dari material_libraries) → atribusi & lisensi
+	SchoolMaterialOriginLibraryID *uuid.UUID `json:"school_material_origin_library_id,omitempty" gorm:"column:school_material_origin_library_id;type:uuid"`
+	SchoolMaterialOriginSourceID  *uuid.UUID `json:"school_material_origin_source_id,omitempty" gorm:"column:school_material_origin_source_id;type:uuid"`
+	SchoolMaterialOriginVersion   *int       `json:"school_material_origin_version,omitempty" gorm:"column:school_material_origin_version"`
+
 	SchoolMaterialIsActive    bool       `json:"school_material_is_active" gorm:"column:school_material_is_active;not null;default:true"`
 	SchoolMaterialIsPublished bool       `json:"school_material_is_published" gorm:"column:school_material_is_published;not null;default:false"`
 	SchoolMaterialPublishedAt *time.Time `json:"school_material_published_at" gorm:"column:school_material_published_at"`
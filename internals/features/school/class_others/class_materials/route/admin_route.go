@@ -7,6 +7,7 @@ import (
 
 
 	classMatController "madinahsalam_backend/internals/features/school/class_others/class_materials/controller/classes"
+	libraryController "madinahsalam_backend/internals/features/school/class_others/class_materials/controller/libraries"
 	schoolMatController "madinahsalam_backend/internals/features/school/class_others/class_materials/controller/schools"
 
 
@@ -35,6 +36,12 @@ import (
 //	POST   /api/a/school-materials
 //	PATCH  /api/a/school-materials/:id
 //	DELETE /api/a/school-materials/:id
+//
+//	// MATERIAL LIBRARIES — admin area (endpoint sama dengan /api/t/material-libraries)
+//	GET|POST       /api/a/material-libraries[/mine|/catalog|/imports]
+//	GET|PATCH|DEL  /api/a/material-libraries/:id
+//	POST           /api/a/material-libraries/:id/{versions,import}
+//	POST           /api/a/material-libraries/imports/:id/sync
 func MaterialsAdminRoutes(admin fiber.Router, db *gorm.DB) {
 	classMaterialsCtrl := classMatController.NewClassMaterialsController(db)
 	schoolMaterialsCtrl := schoolMatController.NewSchoolMaterialController(db)
@@ -56,6 +63,10 @@ func MaterialsAdminRoutes(admin fiber.Router, db *gorm.DB) {
 	adminCSSTMaterials.Post("/", classMaterialsCtrl.TeacherCreate)
 	adminCSSTMaterials.Patch("/:material_id", classMaterialsCtrl.TeacherUpdate)
 	adminCSSTMaterials.Delete("/:material_id", classMaterialsCtrl.TeacherSoftDelete)
+	adminCSSTMaterials.Get("/completion-report", classMaterialsCtrl.CompletionReport)
+	adminCSSTMaterials.Post("/sync-rapor", classMaterialsCtrl.SyncRapor)
+	adminCSSTMaterials.Get("/:material_id/prerequisites", classMaterialsCtrl.ListPrerequisites)
+	adminCSSTMaterials.Put("/:material_id/prerequisites", classMaterialsCtrl.PutPrerequisites)
 
 	/* =====================================================
 	   SCHOOL MATERIALS (template per school) - admin area
@@ -67,4 +78,23 @@ func MaterialsAdminRoutes(admin fiber.Router, db *gorm.DB) {
 	adminSchoolMaterials.Post("/", schoolMaterialsCtrl.CreateSchoolMaterial)
 	adminSchoolMaterials.Patch("/:id", schoolMaterialsCtrl.UpdateSchoolMaterial)
 	adminSchoolMaterials.Delete("/:id", schoolMaterialsCtrl.DeleteSchoolMaterial)
+
+	/* =====================================================
+	   MATERIAL LIBRARIES (berbagi lintas sekolah) - admin area
+	===================================================== */
+
+	libraryCtrl := libraryController.NewMaterialLibraryController(db)
+	adminLibraries := admin.Group("/material-libraries", guard)
+
+	adminLibraries.Get("/mine", libraryCtrl.ListMine)
+	adminLibraries.Get("/catalog", libraryCtrl.Catalog)
+	adminLibraries.Get("/imports", libraryCtrl.ListImports)
+	adminLibraries.Post("/imports/:id/sync", libraryCtrl.SyncImport)
+	adminLibraries.Post("/", libraryCtrl.Publish)
+	adminLibraries.Get("/:id", libraryCtrl.Get)
+	adminLibraries.Patch("/:id", libraryCtrl.Update)
+	adminLibraries.Delete("/:id", libraryCtrl.Delete)
+	adminLibraries.Get("/:id/versions", libraryCtrl.ListVersions)
+	adminLibraries.Post("/:id/versions", libraryCtrl.PublishVersion)
+	adminLibraries.Post("/:id/import", libraryCtrl.Import)
 }
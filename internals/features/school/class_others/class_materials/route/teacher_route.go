@@ -6,6 +6,7 @@ import (
 	"madinahsalam_backend/internals/middlewares/auth"
 
 	classMatController "madinahsalam_backend/internals/features/school/class_others/class_materials/controller/classes"
+	libraryController "madinahsalam_backend/internals/features/school/class_others/class_materials/controller/libraries"
 	schoolMatController "madinahsalam_backend/internals/features/school/class_others/class_materials/controller/schools"
 
 	"github.com/gofiber/fiber/v2"
@@ -27,12 +28,29 @@ import (
 //	POST   /api/t/csst/:csst_id/materials
 //	PATCH  /api/t/csst/:csst_id/materials/:material_id
 //	DELETE /api/t/csst/:csst_id/materials/:material_id
+//	GET    /api/t/csst/:csst_id/materials/completion-report
+//	POST   /api/t/csst/:csst_id/materials/sync-rapor
+//	GET    /api/t/csst/:csst_id/materials/:material_id/prerequisites
+//	PUT    /api/t/csst/:csst_id/materials/:material_id/prerequisites
 //
 //	// SCHOOL MATERIALS (template per school)
 //	GET    /api/t/school-materials
 //	POST   /api/t/school-materials
 //	PATCH  /api/t/school-materials/:id
 //	DELETE /api/t/school-materials/:id
+//
+//	// MATERIAL LIBRARIES (berbagi lintas sekolah: yayasan / publik)
+//	GET    /api/t/material-libraries/mine
+//	GET    /api/t/material-libraries/catalog
+//	GET    /api/t/material-libraries/imports
+//	POST   /api/t/material-libraries/imports/:id/sync
+//	GET    /api/t/material-libraries/:id
+//	GET    /api/t/material-libraries/:id/versions
+//	POST   /api/t/material-libraries/:id/import
+//	POST   /api/t/material-libraries
+//	PATCH  /api/t/material-libraries/:id
+//	POST   /api/t/material-libraries/:id/versions
+//	DELETE /api/t/material-libraries/:id
 func MaterialsTeacherRoutes(teacher fiber.Router, db *gorm.DB) {
 	classMatCtrl := classMatController.NewClassMaterialsController(db)
 	schoolMatCtrl := schoolMatController.NewSchoolMaterialController(db)
@@ -59,11 +77,17 @@ func MaterialsTeacherRoutes(teacher fiber.Router, db *gorm.DB) {
 	// List materi di 1 CSST
 	csstMaterials.Get("/", classMatCtrl.List)
 
+	// Learning path: laporan penyelesaian per murid & prasyarat materi
+	csstMaterials.Get("/completion-report", classMatCtrl.CompletionReport)
+	csstMaterials.Get("/:material_id/prerequisites", classMatCtrl.ListPrerequisites)
+
 	// Mutating endpoints → wajib Admin/DKM
 	protectedClass := csstMaterials.Group("/", adminDkmGuard)
 	protectedClass.Post("/", classMatCtrl.TeacherCreate)
 	protectedClass.Patch("/:material_id", classMatCtrl.TeacherUpdate)
 	protectedClass.Delete("/:material_id", classMatCtrl.TeacherSoftDelete)
+	protectedClass.Put("/:material_id/prerequisites", classMatCtrl.PutPrerequisites)
+	protectedClass.Post("/sync-rapor", classMatCtrl.SyncRapor)
 
 	/* =====================================================
 	   SCHOOL MATERIALS (template per school)
@@ -80,4 +104,26 @@ func MaterialsTeacherRoutes(teacher fiber.Router, db *gorm.DB) {
 	schoolMaterials.Post("/", schoolMatCtrl.CreateSchoolMaterial)
 	schoolMaterials.Patch("/:id", schoolMatCtrl.UpdateSchoolMaterial)
 	schoolMaterials.Delete("/:id", schoolMatCtrl.DeleteSchoolMaterial)
+
+	/* =====================================================
+	   MATERIAL LIBRARIES (berbagi materi lintas sekolah)
+	   - katalog / detail / import / sinkron: guru & DKM (cek di controller)
+	   - terbitkan / ubah / versi baru / hapus: hanya Admin/DKM
+	===================================================== */
+	libraryCtrl := libraryController.NewMaterialLibraryController(db)
+
+	libraries := teacher.Group("/material-libraries")
+	libraries.Get("/mine", libraryCtrl.ListMine)
+	libraries.Get("/catalog", libraryCtrl.Catalog)
+	libraries.Get("/imports", libraryCtrl.ListImports)
+	libraries.Post("/imports/:id/sync", libraryCtrl.SyncImport)
+	libraries.Get("/:id", libraryCtrl.Get)
+	libraries.Get("/:id/versions", libraryCtrl.ListVersions)
+	libraries.Post("/:id/import", libraryCtrl.Import)
+
+	protectedLibraries := libraries.Group("/", adminDkmGuard)
+	protectedLibraries.Post("/", libraryCtrl.Publish)
+	protectedLibraries.Patch("/:id", libraryCtrl.Update)
+	protectedLibraries.Post("/:id/versions", libraryCtrl.PublishVersion)
+	protectedLibraries.Delete("/:id", libraryCtrl.Delete)
 }
@@ -27,6 +27,7 @@ import (
 //	GET  /api/u/student/class-material-progress
 //	GET  /api/u/student/class-material-progress/by-material/:class_material_id
 //	POST /api/u/student/class-material-progress/ping
+//	GET  /api/u/student/class-material-progress/learning-path/:csst_id
 func MaterialsUserRoutes(user fiber.Router, db *gorm.DB) {
 	// Controller materi (class_materials)
 	classMatCtrl := classMatController.NewClassMaterialsController(db)
@@ -63,4 +64,8 @@ func MaterialsUserRoutes(user fiber.Router, db *gorm.DB) {
 	// Ping / upsert progress materi (article/video/pdf/dll)
 	// POST /api/u/student/class-material-progress/ping
 	studentProgress.Post("/ping", progressCtrl.PingMyClassMaterialProgress)
+
+	// Learning path murid di 1 CSST (status kunci + alasan per materi)
+	// GET /api/u/student/class-material-progress/learning-path/:csst_id
+	studentProgress.Get("/learning-path/:csst_id", progressCtrl.MyLearningPath)
 }
@@ -0,0 +1,594 @@
+// file: internals/features/school/class_others/class_materials/service/learning_path_service.go
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	devsvc "madinahsalam_backend/internals/features/school/class_others/attendance_devices/service"
+	model "madinahsalam_backend/internals/features/school/class_others/class_materials/model"
+)
+
+/* =========================================================
+   Learning path materi kelas (per CSST)
+   - materi terbuka bila: sudah rilis (release_at / jam mulai sesi) DAN semua prasyarat terpenuhi
+   - prasyarat 'material' : materi lain sudah completed (progress murid)
+   - prasyarat 'quiz'     : nilai terbaik quiz ≥ min_percent (NULL = cukup sudah dinilai)
+   - materi wajib (is_required_for_pass) yang belum selesai → rapor tidak lulus (SyncRapor)
+========================================================= */
+
+var (
+	ErrMaterialNotFound   = errors.New("material not found")
+	ErrMaterialLocked     = errors.New("material is locked")
+	ErrNotEnrolled        = errors.New("student is not enrolled in this class subject")
+	ErrPrereqInvalid      = errors.New("invalid prerequisite")
+	ErrPrereqSelf         = errors.New("material cannot require itself")
+	ErrPrereqCycle        = errors.New("prerequisites would create a cycle")
+	ErrPrereqMaterialSide = errors.New("required material must belong to the same class subject")
+	ErrPrereqQuizNotFound = errors.New("required quiz not found")
+)
+
+// Alasan materi masih terkunci
+const (
+	LockReasonRelease  = "release"
+	LockReasonMaterial = model.PrerequisiteKindMaterial
+	LockReasonQuiz     = model.PrerequisiteKindQuiz
+)
+
+type LockReason struct {
+	Kind           string     `json:"kind"`
+	MaterialID     *uuid.UUID `json:"class_material_id,omitempty"`
+	QuizID         *uuid.UUID `json:"quiz_id,omitempty"`
+	Title          *string    `json:"title,omitempty"`
+	MinPercent     *float64   `json:"min_percent,omitempty"`
+	CurrentPercent *float64   `json:"current_percent,omitempty"`
+	ReleaseAt      *time.Time `json:"release_at,omitempty"`
+}
+
+type PathItem struct {
+	MaterialID        uuid.UUID    `json:"class_material_id"`
+	Title             string       `json:"class_material_title"`
+	Type              string       `json:"class_material_type"`
+	MeetingNumber     *int         `json:"class_material_meeting_number,omitempty"`
+	Order             *int         `json:"class_material_order,omitempty"`
+	IsRequiredForPass bool         `json:"class_material_is_required_for_pass"`
+	ReleaseAt         *time.Time   `json:"release_at,omitempty"`
+	Unlocked          bool         `json:"unlocked"`
+	Progress          string       `json:"progress"`
+	Completed         bool         `json:"completed"`
+	Locks             []LockReason `json:"locks,omitempty"`
+}
+
+type StudentPath struct {
+	CSSTID            uuid.UUID  `json:"csst_id"`
+	SchoolStudentID   uuid.UUID  `json:"school_student_id"`
+	Total             int        `json:"total"`
+	Completed         int        `json:"completed"`
+	RequiredTotal     int        `json:"required_total"`
+	RequiredCompleted int        `json:"required_completed"`
+	RequiredComplete  bool       `json:"required_complete"`
+	Items             []PathItem `json:"items"`
+}
+
+/* =========================
+   Loader
+   ========================= */
+
+type pathData struct {
+	csstID     uuid.UUID
+	materials  []model.ClassMaterialsModel
+	titles     map[uuid.UUID]string
+	prereqs    map[uuid.UUID][]model.ClassMaterialPrerequisiteModel
+	release    map[uuid.UUID]*time.Time
+	quizTitles map[uuid.UUID]string
+	quizIDs    []uuid.UUID
+}
+
+// loadPath: materi published + aktif dalam CSST beserta prasyarat & waktu rilis efektif.
+func loadPath(ctx context.Context, db *gorm.DB, schoolID, csstID uuid.UUID) (*pathData, error) {
+	p := &pathData{
+		csstID:     csstID,
+		titles:     map[uuid.UUID]string{},
+		prereqs:    map[uuid.UUID][]model.ClassMaterialPrerequisiteModel{},
+		release:    map[uuid.UUID]*time.Time{},
+		quizTitles: map[uuid.UUID]string{},
+	}
+	if err := db.WithContext(ctx).
+		Where(`class_material_school_id = ? AND class_material_csst_id = ?
+		   AND NOT class_material_deleted AND class_material_is_active AND class_material_is_published`, schoolID, csstID).
+		Order("class_material_meeting_number NULLS LAST, class_material_order NULLS LAST, class_material_created_at ASC").
+		Find(&p.materials).Error; err != nil {
+		return nil, err
+	}
+	if len(p.materials) == 0 {
+		return p, nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(p.materials))
+	sessionIDs := []uuid.UUID{}
+	for _, m := range p.materials {
+		ids = append(ids, m.ClassMaterialID)
+		p.titles[m.ClassMaterialID] = m.ClassMaterialTitle
+		if m.ClassMaterialReleaseWithSession && m.ClassMaterialSessionID != nil {
+			sessionIDs = append(sessionIDs, *m.ClassMaterialSessionID)
+		}
+	}
+
+	var rows []model.ClassMaterialPrerequisiteModel
+	if err := db.WithContext(ctx).
+		Where("class_material_prerequisite_school_id = ? AND class_material_prerequisite_class_material_id IN ?", schoolID, ids).
+		Order("class_material_prerequisite_created_at ASC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		// materi syarat yang sudah dihapus/unpublish tidak mengunci selamanya
+		if r.ClassMaterialPrerequisiteKind == model.PrerequisiteKindMaterial {
+			if _, ok := p.titles[*r.ClassMaterialPrerequisiteRequiredMaterialID]; !ok {
+				continue
+			}
+		}
+		if r.ClassMaterialPrerequisiteRequiredQuizID != nil {
+			p.quizIDs = append(p.quizIDs, *r.ClassMaterialPrerequisiteRequiredQuizID)
+		}
+		p.prereqs[r.ClassMaterialPrerequisiteMaterialID] = append(p.prereqs[r.ClassMaterialPrerequisiteMaterialID], r)
+	}
+
+	if len(p.quizIDs) > 0 {
+		var qs []struct {
+			ID    uuid.UUID `gorm:"column:quiz_id"`
+			Title string    `gorm:"column:quiz_title"`
+		}
+		if err := db.WithContext(ctx).Table("quizzes").
+			Select("quiz_id, quiz_title").
+			Where("quiz_id IN ?", p.quizIDs).
+			Scan(&qs).Error; err != nil {
+			return nil, err
+		}
+		for _, q := range qs {
+			p.quizTitles[q.ID] = q.Title
+		}
+	}
+
+	// jam mulai sesi (fallback: awal hari tanggal sesi di zona sekolah)
+	sessionStart := map[uuid.UUID]time.Time{}
+	if len(sessionIDs) > 0 {
+		var ss []struct {
+			ID       uuid.UUID  `gorm:"column:class_attendance_session_id"`
+			Date     time.Time  `gorm:"column:class_attendance_session_date"`
+			StartsAt *time.Time `gorm:"column:class_attendance_session_starts_at"`
+		}
+		if err := db.WithContext(ctx).Table("class_attendance_sessions").
+			Select("class_attendance_session_id, class_attendance_session_date, class_attendance_session_starts_at").
+			Where("class_attendance_session_id IN ? AND class_attendance_session_deleted_at IS NULL", sessionIDs).
+			Scan(&ss).Error; err != nil {
+			return nil, err
+		}
+		loc := devsvc.SchoolLocation(ctx, db, schoolID)
+		for _, s := range ss {
+			if s.StartsAt != nil {
+				sessionStart[s.ID] = *s.StartsAt
+				continue
+			}
+			d := s.Date
+			sessionStart[s.ID] = time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, loc)
+		}
+	}
+
+	for _, m := range p.materials {
+		var at *time.Time
+		if m.ClassMaterialReleaseAt != nil {
+			t := *m.ClassMaterialReleaseAt
+			at = &t
+		}
+		if m.ClassMaterialReleaseWithSession && m.ClassMaterialSessionID != nil {
+			if st, ok := sessionStart[*m.ClassMaterialSessionID]; ok && (at == nil || st.After(*at)) {
+				at = &st
+			}
+		}
+		p.release[m.ClassMaterialID] = at
+	}
+	return p, nil
+}
+
+// progressFor: status progress per (murid, materi); completed = is_completed / status completed.
+func progressFor(ctx context.Context, db *gorm.DB, schoolID uuid.UUID, materialIDs, studentIDs []uuid.UUID) (map[uuid.UUID]map[uuid.UUID]string, error) {
+	out := map[uuid.UUID]map[uuid.UUID]string{}
+	if len(materialIDs) == 0 || len(studentIDs) == 0 {
+		return out, nil
+	}
+	var rows []model.StudentClassMaterialProgressModel
+	if err := db.WithContext(ctx).
+		Where(`student_class_material_progress_school_id = ?
+		   AND student_class_material_progress_class_material_id IN ?
+		   AND student_class_material_progress_student_id IN ?`, schoolID, materialIDs, studentIDs).
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		sid := r.StudentClassMaterialProgressStudentID
+		if out[sid] == nil {
+			out[sid] = map[uuid.UUID]string{}
+		}
+		st := string(r.StudentClassMaterialProgressStatus)
+		if r.StudentClassMaterialProgressIsCompleted {
+			st = string(model.MaterialProgressStatusCompleted)
+		}
+		out[sid][r.StudentClassMaterialProgressClassMaterialID] = st
+	}
+	return out, nil
+}
+
+// quizBestFor: nilai terbaik (persen) per (murid, quiz).
+func quizBestFor(ctx context.Context, db *gorm.DB, schoolID uuid.UUID, quizIDs, studentIDs []uuid.UUID) (map[uuid.UUID]map[uuid.UUID]float64, error) {
+	out := map[uuid.UUID]map[uuid.UUID]float64{}
+	if len(quizIDs) == 0 || len(studentIDs) == 0 {
+		return out, nil
+	}
+	var rows []struct {
+		QuizID    uuid.UUID `gorm:"column:student_quiz_attempt_quiz_id"`
+		StudentID uuid.UUID `gorm:"column:student_quiz_attempt_student_id"`
+		Best      float64   `gorm:"column:best"`
+	}
+	if err := db.WithContext(ctx).Table("student_quiz_attempts").
+		Select("student_quiz_attempt_quiz_id, student_quiz_attempt_student_id, MAX(student_quiz_attempt_best_percent) AS best").
+		Where(`student_quiz_attempt_school_id = ?
+		   AND student_quiz_attempt_quiz_id IN ?
+		   AND student_quiz_attempt_student_id IN ?
+		   AND student_quiz_attempt_best_percent IS NOT NULL`, schoolID, quizIDs, studentIDs).
+		Group("student_quiz_attempt_quiz_id, student_quiz_attempt_student_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		if out[r.StudentID] == nil {
+			out[r.StudentID] = map[uuid.UUID]float64{}
+		}
+		out[r.StudentID][r.QuizID] = r.Best
+	}
+	return out, nil
+}
+
+// evaluate: learning path 1 murid.
+func (p *pathData) evaluate(studentID uuid.UUID, progress map[uuid.UUID]string, quizBest map[uuid.UUID]float64, now time.Time) *StudentPath {
+	out := &StudentPath{CSSTID: p.csstID, SchoolStudentID: studentID, Items: make([]PathItem, 0, len(p.materials))}
+	completed := func(id uuid.UUID) bool {
+		return progress[id] == string(model.MaterialProgressStatusCompleted)
+	}
+
+	for _, m := range p.materials {
+		it := PathItem{
+			MaterialID:        m.ClassMaterialID,
+			Title:             m.ClassMaterialTitle,
+			Type:              string(m.ClassMaterialType),
+			MeetingNumber:     m.ClassMaterialMeetingNumber,
+			Order:             m.ClassMaterialOrder,
+			IsRequiredForPass: m.ClassMaterialIsRequiredForPass,
+			ReleaseAt:         p.release[m.ClassMaterialID],
+			Progress:          string(model.MaterialProgressStatusNotStarted),
+			Completed:         completed(m.ClassMaterialID),
+		}
+		if st, ok := progress[m.ClassMaterialID]; ok {
+			it.Progress = st
+		}
+
+		if it.ReleaseAt != nil && now.Before(*it.ReleaseAt) {
+			it.Locks = append(it.Locks, LockReason{Kind: LockReasonRelease, ReleaseAt: it.ReleaseAt})
+		}
+		for _, pr := range p.prereqs[m.ClassMaterialID] {
+			switch pr.ClassMaterialPrerequisiteKind {
+			case model.PrerequisiteKindMaterial:
+				rid := *pr.ClassMaterialPrerequisiteRequiredMaterialID
+				if !completed(rid) {
+					title := p.titles[rid]
+					it.Locks = append(it.Locks, LockReason{Kind: LockReasonMaterial, MaterialID: &rid, Title: &title})
+				}
+			case model.PrerequisiteKindQuiz:
+				qid := *pr.ClassMaterialPrerequisiteRequiredQuizID
+				best, ok := quizBest[qid]
+				minPct := pr.ClassMaterialPrerequisiteMinPercent
+				if ok && (minPct == nil || best >= *minPct) {
+					continue
+				}
+				lr := LockReason{Kind: LockReasonQuiz, QuizID: &qid, MinPercent: minPct}
+				if t, ok := p.quizTitles[qid]; ok {
+					lr.Title = &t
+				}
+				if ok {
+					b := best
+					lr.CurrentPercent = &b
+				}
+				it.Locks = append(it.Locks, lr)
+			}
+		}
+		it.Unlocked = len(it.Locks) == 0
+
+		out.Total++
+		if it.Completed {
+			out.Completed++
+		}
+		if it.IsRequiredForPass {
+			out.RequiredTotal++
+			if it.Completed {
+				out.RequiredCompleted++
+			}
+		}
+		out.Items = append(out.Items, it)
+	}
+	out.RequiredComplete = out.RequiredCompleted == out.RequiredTotal
+	return out
+}
+
+func (p *pathData) materialIDs() []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(p.materials))
+	for _, m := range p.materials {
+		ids = append(ids, m.ClassMaterialID)
+	}
+	return ids
+}
+
+// DescribeLocks: pesan singkat alasan materi terkunci (untuk response 403).
+func DescribeLocks(locks []LockReason) string {
+	parts := make([]string, 0, len(locks))
+	for _, l := range locks {
+		title := ""
+		if l.Title != nil {
+			title = " \"" + *l.Title + "\""
+		}
+		switch l.Kind {
+		case LockReasonRelease:
+			parts = append(parts, "available from "+l.ReleaseAt.UTC().Format(time.RFC3339))
+		case LockReasonMaterial:
+			parts = append(parts, "complete material"+title+" first")
+		case LockReasonQuiz:
+			if l.MinPercent != nil {
+				parts = append(parts, fmt.Sprintf("score at least %.0f on quiz%s", *l.MinPercent, title))
+			} else {
+				parts = append(parts, "finish quiz"+title+" first")
+			}
+		}
+	}
+	if len(parts) == 0 {
+		return ErrMaterialLocked.Error()
+	}
+	return ErrMaterialLocked.Error() + ": " + strings.Join(parts, "; ")
+}
+
+/* =========================
+   Murid
+   ========================= */
+
+// enrolled: murid aktif di CSST (student_csst).
+func enrolled(ctx context.Context, db *gorm.DB, schoolID, csstID, studentID uuid.UUID) (bool, error) {
+	var n int64
+	err := db.WithContext(ctx).Table("student_class_section_subject_teachers").
+		Where(`student_csst_school_id = ? AND student_csst_csst_id = ? AND student_csst_student_id = ?
+		   AND student_csst_is_active = TRUE AND student_csst_deleted_at IS NULL`, schoolID, csstID, studentID).
+		Count(&n).Error
+	return n > 0, err
+}
+
+// StudentLearningPath: learning path murid di 1 CSST.
+func StudentLearningPath(ctx context.Context, db *gorm.DB, schoolID, csstID, studentID uuid.UUID, now time.Time) (*StudentPath, error) {
+	ok, err := enrolled(ctx, db, schoolID, csstID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrNotEnrolled
+	}
+	p, err := loadPath(ctx, db, schoolID, csstID)
+	if err != nil {
+		return nil, err
+	}
+	prog, err := progressFor(ctx, db, schoolID, p.materialIDs(), []uuid.UUID{studentID})
+	if err != nil {
+		return nil, err
+	}
+	best, err := quizBestFor(ctx, db, schoolID, p.quizIDs, []uuid.UUID{studentID})
+	if err != nil {
+		return nil, err
+	}
+	return p.evaluate(studentID, prog[studentID], best[studentID], now), nil
+}
+
+// EnsureMaterialUnlocked: dipanggil sebelum progress materi dicatat.
+// Materi terkunci → ErrMaterialLocked + daftar alasan.
+func EnsureMaterialUnlocked(ctx context.Context, db *gorm.DB, schoolID, materialID, studentID uuid.UUID, now time.Time) ([]LockReason, error) {
+	var m model.ClassMaterialsModel
+	if err := db.WithContext(ctx).
+		Where("class_material_id = ? AND class_material_school_id = ? AND NOT class_material_deleted", materialID, schoolID).
+		Take(&m).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMaterialNotFound
+		}
+		return nil, err
+	}
+	path, err := StudentLearningPath(ctx, db, schoolID, m.ClassMaterialCSSTID, studentID, now)
+	if err != nil {
+		return nil, err
+	}
+	for _, it := range path.Items {
+		if it.MaterialID == materialID {
+			if !it.Unlocked {
+				return it.Locks, ErrMaterialLocked
+			}
+			return nil, nil
+		}
+	}
+	// materi belum dipublish / nonaktif → tidak bisa diakses murid
+	return nil, ErrMaterialNotFound
+}
+
+/* =========================
+   Laporan guru
+   ========================= */
+
+type CompletionRow struct {
+	SchoolStudentID   uuid.UUID            `json:"school_student_id"`
+	Name              *string              `json:"name,omitempty"`
+	Code              *string              `json:"code,omitempty"`
+	Total             int                  `json:"total"`
+	Completed         int                  `json:"completed"`
+	Unlocked          int                  `json:"unlocked"`
+	RequiredTotal     int                  `json:"required_total"`
+	RequiredCompleted int                  `json:"required_completed"`
+	RequiredComplete  bool                 `json:"required_complete"`
+	Materials         map[uuid.UUID]string `json:"materials"`
+}
+
+type CompletionReport struct {
+	CSSTID    uuid.UUID       `json:"csst_id"`
+	Materials []PathItem      `json:"materials"`
+	Students  []CompletionRow `json:"students"`
+}
+
+type rosterRow struct {
+	StudentID uuid.UUID `gorm:"column:student_csst_student_id"`
+	Name      *string   `gorm:"column:student_csst_user_profile_name_cache"`
+	Code      *string   `gorm:"column:student_csst_school_student_code_cache"`
+}
+
+func roster(ctx context.Context, db *gorm.DB, schoolID, csstID uuid.UUID) ([]rosterRow, error) {
+	var rows []rosterRow
+	err := db.WithContext(ctx).Table("student_class_section_subject_teachers").
+		Select("DISTINCT ON (student_csst_student_id) student_csst_student_id, student_csst_user_profile_name_cache, student_csst_school_student_code_cache").
+		Where(`student_csst_school_id = ? AND student_csst_csst_id = ?
+		   AND student_csst_is_active = TRUE AND student_csst_deleted_at IS NULL`, schoolID, csstID).
+		Order("student_csst_student_id").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// Report: rekap penyelesaian materi per murid (materials: class_material_id → status, 'locked' bila belum terbuka).
+func Report(ctx context.Context, db *gorm.DB, schoolID, csstID uuid.UUID, now time.Time) (*CompletionReport, error) {
+	p, err := loadPath(ctx, db, schoolID, csstID)
+	if err != nil {
+		return nil, err
+	}
+	students, err := roster(ctx, db, schoolID, csstID)
+	if err != nil {
+		return nil, err
+	}
+	studentIDs := make([]uuid.UUID, 0, len(students))
+	for _, s := range students {
+		studentIDs = append(studentIDs, s.StudentID)
+	}
+	prog, err := progressFor(ctx, db, schoolID, p.materialIDs(), studentIDs)
+	if err != nil {
+		return nil, err
+	}
+	best, err := quizBestFor(ctx, db, schoolID, p.quizIDs, studentIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &CompletionReport{CSSTID: csstID, Materials: []PathItem{}, Students: make([]CompletionRow, 0, len(students))}
+	// header materi (tanpa data murid)
+	for _, it := range p.evaluate(uuid.Nil, nil, nil, now).Items {
+		it.Progress, it.Completed, it.Unlocked, it.Locks = "", false, false, nil
+		out.Materials = append(out.Materials, it)
+	}
+	for _, s := range students {
+		sp := p.evaluate(s.StudentID, prog[s.StudentID], best[s.StudentID], now)
+		row := CompletionRow{
+			SchoolStudentID:   s.StudentID,
+			Name:              s.Name,
+			Code:              s.Code,
+			Total:             sp.Total,
+			Completed:         sp.Completed,
+			RequiredTotal:     sp.RequiredTotal,
+			RequiredCompleted: sp.RequiredCompleted,
+			RequiredComplete:  sp.RequiredComplete,
+			Materials:         make(map[uuid.UUID]string, len(sp.Items)),
+		}
+		for _, it := range sp.Items {
+			st := it.Progress
+			if !it.Unlocked && !it.Completed {
+				st = "locked"
+			}
+			if it.Unlocked {
+				row.Unlocked++
+			}
+			row.Materials[it.MaterialID] = st
+		}
+		out.Students = append(out.Students, row)
+	}
+	return out, nil
+}
+
+/* =========================
+   Rapor
+   ========================= */
+
+type SyncResult struct {
+	CSSTID  uuid.UUID `json:"csst_id"`
+	Updated int       `json:"updated"`
+}
+
+// SyncRapor: tulis rekap materi ke user_subject_summary (breakdown.materials) untuk baris CSST ini.
+// Materi wajib belum selesai → passed = false; bila sudah lengkap passed dihitung ulang dari final_score.
+// Hanya baris yang sudah ada (tidak membuat baris baru).
+func SyncRapor(ctx context.Context, db *gorm.DB, schoolID, csstID uuid.UUID, now time.Time) (*SyncResult, error) {
+	var students []uuid.UUID
+	if err := db.WithContext(ctx).Table("user_subject_summary").
+		Where("user_subject_summary_school_id = ? AND user_subject_summary_csst_id = ? AND user_subject_summary_deleted_at IS NULL", schoolID, csstID).
+		Distinct().Pluck("user_subject_summary_school_student_id", &students).Error; err != nil {
+		return nil, err
+	}
+	res := &SyncResult{CSSTID: csstID}
+	if len(students) == 0 {
+		return res, nil
+	}
+
+	p, err := loadPath(ctx, db, schoolID, csstID)
+	if err != nil {
+		return nil, err
+	}
+	prog, err := progressFor(ctx, db, schoolID, p.materialIDs(), students)
+	if err != nil {
+		return nil, err
+	}
+	best, err := quizBestFor(ctx, db, schoolID, p.quizIDs, students)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sid := range students {
+		sp := p.evaluate(sid, prog[sid], best[sid], now)
+		raw, err := json.Marshal(map[string]any{"materials": map[string]any{
+			"total":              sp.Total,
+			"completed":          sp.Completed,
+			"required_total":     sp.RequiredTotal,
+			"required_completed": sp.RequiredCompleted,
+			"required_complete":  sp.RequiredComplete,
+		}})
+		if err != nil {
+			return nil, err
+		}
+		upd := db.WithContext(ctx).Exec(`
+			UPDATE user_subject_summary
+			   SET user_subject_summary_breakdown = COALESCE(user_subject_summary_breakdown, '{}'::jsonb) || ?::jsonb,
+			       user_subject_summary_passed = CASE
+			         WHEN ? THEN COALESCE(user_subject_summary_final_score >= user_subject_summary_pass_threshold, user_subject_summary_passed)
+			         ELSE FALSE END,
+			       user_subject_summary_updated_at = ?
+			 WHERE user_subject_summary_school_id = ?
+			   AND user_subject_summary_school_student_id = ?
+			   AND user_subject_summary_csst_id = ?
+			   AND user_subject_summary_deleted_at IS NULL
+		`, string(raw), sp.RequiredComplete, now, schoolID, sid, csstID)
+		if upd.Error != nil {
+			return nil, upd.Error
+		}
+		res.Updated += int(upd.RowsAffected)
+	}
+	return res, nil
+}
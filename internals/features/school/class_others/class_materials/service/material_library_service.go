@@ -0,0 +1,1173 @@
+// file: internals/features/school/class_others/class_materials/service/material_library_service.go
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	model "madinahsalam_backend/internals/features/school/class_others/class_materials/model"
+	qmodel "madinahsalam_backend/internals/features/school/submissions_assesments/quizzes/model"
+)
+
+/* =========================================================
+   Material library (berbagi materi lintas sekolah)
+   - sekolah menerbitkan paket school_materials + quiz ke yayasan / publik
+   - tiap terbit ulang = versi baru (snapshot isi beku)
+   - sekolah lain impor → salinan lokal dengan kolom *_origin_* (atribusi & lisensi)
+   - sinkron: terapkan versi terbaru ke salinan; salinan yang sudah dihapus /
+     quiz yang sudah dikerjakan murid tidak diubah
+   - file lampiran ikut lewat URL (objek tetap milik penerbit)
+========================================================= */
+
+var (
+	ErrLibraryNotFound    = errors.New("material library not found")
+	ErrLibraryInvalid     = errors.New("title, license and visibility (yayasan/public) are required")
+	ErrLibraryNoYayasan   = errors.New("school is not part of a yayasan")
+	ErrLibraryNoItems     = errors.New("material library needs at least one material or quiz")
+	ErrLibraryItemInvalid = errors.New("library item not found in this school")
+	ErrLibraryOwnImport   = errors.New("cannot import your own material library")
+	ErrLibraryImported    = errors.New("material library already imported, use sync")
+	ErrImportNotFound     = errors.New("library import not found")
+)
+
+// status per item hasil import/sinkron
+const (
+	LibrarySyncAdded           = "added"
+	LibrarySyncUpdated         = "updated"
+	LibrarySyncSkippedDeleted  = "skipped_deleted"      // salinan sudah dihapus sekolah pengimpor
+	LibrarySyncSkippedAttempts = "skipped_has_attempts" // quiz sudah dikerjakan → soal tidak diganti
+	LibrarySyncRemovedUpstream = "removed_upstream"     // tidak ada lagi di versi terbaru (salinan dibiarkan)
+)
+
+type LibraryItemInput struct {
+	Kind             string
+	SchoolMaterialID *uuid.UUID
+	QuizID           *uuid.UUID
+}
+
+type LibraryPublishInput struct {
+	Title       string
+	Description *string
+	Visibility  string
+	License     string
+	Attribution *string // kosong → nama sekolah penerbit
+	Changelog   *string
+	Items       []LibraryItemInput
+}
+
+type LibraryPatchInput struct {
+	Title       *string
+	Description *string
+	Visibility  *string
+	License     *string
+	Attribution *string
+	IsActive    *bool
+}
+
+/* =========================
+   Snapshot (isi per versi)
+   ========================= */
+
+type SnapshotMaterial struct {
+	SourceID          uuid.UUID                `json:"source_id"`
+	Title             string                   `json:"title"`
+	Description       *string                  `json:"description,omitempty"`
+	Type              model.MaterialType       `json:"type"`
+	ContentHTML       *string                  `json:"content_html,omitempty"`
+	FileURL           *string                  `json:"file_url,omitempty"`
+	FileName          *string                  `json:"file_name,omitempty"`
+	FileMimeType      *string                  `json:"file_mime_type,omitempty"`
+	FileSizeBytes     *int64                   `json:"file_size_bytes,omitempty"`
+	ExternalURL       *string                  `json:"external_url,omitempty"`
+	YouTubeID         *string                  `json:"youtube_id,omitempty"`
+	DurationSec       *int32                   `json:"duration_sec,omitempty"`
+	Importance        model.MaterialImportance `json:"importance"`
+	IsRequiredForPass bool                     `json:"is_required_for_pass"`
+	AffectsScoring    bool                     `json:"affects_scoring"`
+	MeetingNumber     *int32                   `json:"meeting_number,omitempty"`
+	DefaultOrder      *int32                   `json:"default_order,omitempty"`
+	ScopeTag          *string                  `json:"scope_tag,omitempty"`
+}
+
+type SnapshotQuestion struct {
+	Type        qmodel.QuizQuestionType `json:"type"`
+	Text        string                  `json:"text"`
+	Points      float64                 `json:"points"`
+	Answers     json.RawMessage         `json:"answers,omitempty"`
+	Correct     *string                 `json:"correct,omitempty"`
+	Explanation *string                 `json:"explanation,omitempty"`
+}
+
+type SnapshotQuiz struct {
+	SourceID     uuid.UUID          `json:"source_id"`
+	Title        string             `json:"title"`
+	Description  *string            `json:"description,omitempty"`
+	TimeLimitSec *int               `json:"time_limit_sec,omitempty"`
+	Questions    []SnapshotQuestion `json:"questions"`
+}
+
+type LibrarySnapshot struct {
+	PublisherSchoolID uuid.UUID          `json:"publisher_school_id"`
+	License           string             `json:"license"`
+	Attribution       string             `json:"attribution"`
+	Materials         []SnapshotMaterial `json:"materials"`
+	Quizzes           []SnapshotQuiz     `json:"quizzes"`
+}
+
+/* =========================
+   Response
+   ========================= */
+
+type LibraryDetail struct {
+	Library         model.MaterialLibraryModel         `json:"library"`
+	PublisherName   string                             `json:"publisher_school_name"`
+	Items           []model.MaterialLibraryItemModel   `json:"items,omitempty"` // hanya untuk penerbit
+	Version         *model.MaterialLibraryVersionModel `json:"version,omitempty"`
+	ImportedVersion *int                               `json:"imported_version,omitempty"`
+}
+
+type CatalogItem struct {
+	model.MaterialLibraryModel
+	PublisherName   string `json:"publisher_school_name"`
+	ImportedVersion *int   `json:"imported_version,omitempty"`
+}
+
+type ImportRow struct {
+	model.MaterialLibraryImportModel
+	LibraryTitle    string `json:"material_library_title"`
+	License         string `json:"material_library_license"`
+	Attribution     string `json:"material_library_attribution"`
+	PublisherName   string `json:"publisher_school_name"`
+	CurrentVersion  int    `json:"material_library_current_version"`
+	Available       bool   `json:"available"` // masih aktif & terlihat untuk sekolah ini
+	UpdateAvailable bool   `json:"update_available"`
+}
+
+type LibrarySyncItem struct {
+	Kind     string     `json:"kind"`
+	SourceID uuid.UUID  `json:"source_id"`
+	CopyID   *uuid.UUID `json:"copy_id,omitempty"`
+	Title    string     `json:"title"`
+	Status   string     `json:"status"`
+}
+
+type LibrarySyncResult struct {
+	ImportID    uuid.UUID         `json:"material_library_import_id"`
+	LibraryID   uuid.UUID         `json:"material_library_id"`
+	FromVersion *int              `json:"from_version,omitempty"`
+	ToVersion   int               `json:"to_version"`
+	UpToDate    bool              `json:"up_to_date"`
+	Items       []LibrarySyncItem `json:"items"`
+}
+
+/* =========================
+   Helper
+   ========================= */
+
+func trimOrNil(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	v := strings.TrimSpace(*s)
+	if v == "" {
+		return nil
+	}
+	return &v
+}
+
+func schoolInfo(ctx context.Context, db *gorm.DB, schoolID uuid.UUID) (name string, yayasanID *uuid.UUID, err error) {
+	var row struct {
+		SchoolName      string
+		SchoolYayasanID *uuid.UUID
+	}
+	err = db.WithContext(ctx).Table("schools").
+		Select("school_name, school_yayasan_id").
+		Where("school_id = ?", schoolID).
+		Take(&row).Error
+	return row.SchoolName, row.SchoolYayasanID, err
+}
+
+func schoolNames(ctx context.Context, db *gorm.DB, ids []uuid.UUID) (map[uuid.UUID]string, error) {
+	out := map[uuid.UUID]string{}
+	if len(ids) == 0 {
+		return out, nil
+	}
+	var rows []struct {
+		SchoolID   uuid.UUID
+		SchoolName string
+	}
+	if err := db.WithContext(ctx).Table("schools").
+		Select("school_id, school_name").
+		Where("school_id IN ?", ids).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		out[r.SchoolID] = r.SchoolName
+	}
+	return out, nil
+}
+
+// applyVisibility: set visibility + yayasan penerbit (yayasan wajib untuk visibility 'yayasan').
+func applyVisibility(lib *model.MaterialLibraryModel, visibility string, yayasanID *uuid.UUID) error {
+	switch strings.ToLower(strings.TrimSpace(visibility)) {
+	case model.LibraryVisibilityYayasan:
+		if yayasanID == nil {
+			return ErrLibraryNoYayasan
+		}
+		lib.MaterialLibraryVisibility = model.LibraryVisibilityYayasan
+	case model.LibraryVisibilityPublic:
+		lib.MaterialLibraryVisibility = model.LibraryVisibilityPublic
+	default:
+		return ErrLibraryInvalid
+	}
+	lib.MaterialLibraryYayasanID = yayasanID
+	return nil
+}
+
+func normalizeLicense(s string) (string, error) {
+	v := strings.TrimSpace(s)
+	if v == "" || len(v) > 40 {
+		return "", ErrLibraryInvalid
+	}
+	return v, nil
+}
+
+// visibleTo: paket milik sendiri selalu terlihat; paket lain harus aktif & sesuai visibility.
+func visibleTo(lib *model.MaterialLibraryModel, schoolID uuid.UUID, yayasanID *uuid.UUID) bool {
+	if lib.MaterialLibraryDeletedAt != nil {
+		return false
+	}
+	if lib.MaterialLibrarySchoolID == schoolID {
+		return true
+	}
+	if !lib.MaterialLibraryIsActive {
+		return false
+	}
+	switch lib.MaterialLibraryVisibility {
+	case model.LibraryVisibilityPublic:
+		return true
+	case model.LibraryVisibilityYayasan:
+		return yayasanID != nil && lib.MaterialLibraryYayasanID != nil && *lib.MaterialLibraryYayasanID == *yayasanID
+	}
+	return false
+}
+
+func loadVisibleLibrary(ctx context.Context, db *gorm.DB, schoolID, libraryID uuid.UUID) (*model.MaterialLibraryModel, error) {
+	var lib model.MaterialLibraryModel
+	err := db.WithContext(ctx).
+		Where("material_library_id = ? AND material_library_deleted_at IS NULL", libraryID).
+		Take(&lib).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrLibraryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	_, yayasanID, err := schoolInfo(ctx, db, schoolID)
+	if err != nil {
+		return nil, err
+	}
+	if !visibleTo(&lib, schoolID, yayasanID) {
+		return nil, ErrLibraryNotFound
+	}
+	return &lib, nil
+}
+
+func loadOwnLibrary(ctx context.Context, db *gorm.DB, schoolID, libraryID uuid.UUID, lock bool) (*model.MaterialLibraryModel, error) {
+	q := db.WithContext(ctx)
+	if lock {
+		q = q.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+	var lib model.MaterialLibraryModel
+	err := q.Where("material_library_id = ? AND material_library_school_id = ? AND material_library_deleted_at IS NULL", libraryID, schoolID).
+		Take(&lib).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrLibraryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &lib, nil
+}
+
+func loadVersion(ctx context.Context, db *gorm.DB, libraryID uuid.UUID, number int) (*model.MaterialLibraryVersionModel, *LibrarySnapshot, error) {
+	var v model.MaterialLibraryVersionModel
+	if err := db.WithContext(ctx).
+		Where("material_library_version_library_id = ? AND material_library_version_number = ?", libraryID, number).
+		Take(&v).Error; err != nil {
+		return nil, nil, err
+	}
+	var snap LibrarySnapshot
+	if err := json.Unmarshal(v.MaterialLibraryVersionSnapshot, &snap); err != nil {
+		return nil, nil, err
+	}
+	return &v, &snap, nil
+}
+
+/* =========================
+   Item & snapshot (penerbit)
+   ========================= */
+
+// resolveItems: validasi sumber milik sekolah penerbit (duplikat diabaikan).
+func resolveItems(ctx context.Context, db *gorm.DB, schoolID, libraryID uuid.UUID, in []LibraryItemInput) ([]model.MaterialLibraryItemModel, error) {
+	out := make([]model.MaterialLibraryItemModel, 0, len(in))
+	seen := map[uuid.UUID]bool{}
+	for _, it := range in {
+		row := model.MaterialLibraryItemModel{
+			MaterialLibraryItemLibraryID: libraryID,
+			MaterialLibraryItemKind:      strings.ToLower(strings.TrimSpace(it.Kind)),
+			MaterialLibraryItemOrder:     len(out),
+		}
+		var n int64
+		switch row.MaterialLibraryItemKind {
+		case model.LibraryItemKindMaterial:
+			if it.SchoolMaterialID == nil {
+				return nil, ErrLibraryItemInvalid
+			}
+			if seen[*it.SchoolMaterialID] {
+				continue
+			}
+			if err := db.WithContext(ctx).Model(&model.SchoolMaterialModel{}).
+				Where("school_material_id = ? AND school_material_school_id = ? AND school_material_deleted = FALSE", *it.SchoolMaterialID, schoolID).
+				Count(&n).Error; err != nil {
+				return nil, err
+			}
+			seen[*it.SchoolMaterialID] = true
+			row.MaterialLibraryItemSchoolMaterialID = it.SchoolMaterialID
+		case model.LibraryItemKindQuiz:
+			if it.QuizID == nil {
+				return nil, ErrLibraryItemInvalid
+			}
+			if seen[*it.QuizID] {
+				continue
+			}
+			if err := db.WithContext(ctx).Model(&qmodel.QuizModel{}).
+				Where("quiz_id = ? AND quiz_school_id = ?", *it.QuizID, schoolID).
+				Count(&n).Error; err != nil {
+				return nil, err
+			}
+			seen[*it.QuizID] = true
+			row.MaterialLibraryItemQuizID = it.QuizID
+		default:
+			return nil, ErrLibraryItemInvalid
+		}
+		if n == 0 {
+			return nil, ErrLibraryItemInvalid
+		}
+		out = append(out, row)
+	}
+	if len(out) == 0 {
+		return nil, ErrLibraryNoItems
+	}
+	return out, nil
+}
+
+// buildSnapshot: bekukan isi sumber saat ini (urut sesuai item).
+func buildSnapshot(ctx context.Context, db *gorm.DB, lib *model.MaterialLibraryModel, items []model.MaterialLibraryItemModel) (datatypes.JSON, error) {
+	snap := LibrarySnapshot{
+		PublisherSchoolID: lib.MaterialLibrarySchoolID,
+		License:           lib.MaterialLibraryLicense,
+		Attribution:       lib.MaterialLibraryAttribution,
+		Materials:         []SnapshotMaterial{},
+		Quizzes:           []SnapshotQuiz{},
+	}
+
+	for _, it := range items {
+		switch it.MaterialLibraryItemKind {
+		case model.LibraryItemKindMaterial:
+			var m model.SchoolMaterialModel
+			err := db.WithContext(ctx).
+				Where("school_material_id = ? AND school_material_school_id = ? AND school_material_deleted = FALSE",
+					*it.MaterialLibraryItemSchoolMaterialID, lib.MaterialLibrarySchoolID).
+				Take(&m).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrLibraryItemInvalid
+			}
+			if err != nil {
+				return nil, err
+			}
+			snap.Materials = append(snap.Materials, SnapshotMaterial{
+				SourceID:          m.SchoolMaterialID,
+				Title:             m.SchoolMaterialTitle,
+				Description:       m.SchoolMaterialDescription,
+				Type:              m.SchoolMaterialType,
+				ContentHTML:       m.SchoolMaterialContentHTML,
+				FileURL:           m.SchoolMaterialFileURL,
+				FileName:          m.SchoolMaterialFileName,
+				FileMimeType:      m.SchoolMaterialFileMimeType,
+				FileSizeBytes:     m.SchoolMaterialFileSizeBytes,
+				ExternalURL:       m.SchoolMaterialExternalURL,
+				YouTubeID:         m.SchoolMaterialYouTubeID,
+				DurationSec:       m.SchoolMaterialDurationSec,
+				Importance:        m.SchoolMaterialImportance,
+				IsRequiredForPass: m.SchoolMaterialIsRequiredForPass,
+				AffectsScoring:    m.SchoolMaterialAffectsScoring,
+				MeetingNumber:     m.SchoolMaterialMeetingNumber,
+				DefaultOrder:      m.SchoolMaterialDefaultOrder,
+				ScopeTag:          m.SchoolMaterialScopeTag,
+			})
+
+		case model.LibraryItemKindQuiz:
+			var q qmodel.QuizModel
+			err := db.WithContext(ctx).
+				Where("quiz_id = ? AND quiz_school_id = ?", *it.MaterialLibraryItemQuizID, lib.MaterialLibrarySchoolID).
+				Take(&q).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrLibraryItemInvalid
+			}
+			if err != nil {
+				return nil, err
+			}
+			// hanya set soal tetap (soal tarikan acak / pool tidak ikut)
+			var qs []qmodel.QuizQuestionModel
+			if err := db.WithContext(ctx).
+				Where("quiz_question_quiz_id = ? AND quiz_question_is_pool = FALSE", q.QuizID).
+				Order("quiz_question_created_at ASC").
+				Find(&qs).Error; err != nil {
+				return nil, err
+			}
+			sq := SnapshotQuiz{
+				SourceID:     q.QuizID,
+				Title:        q.QuizTitle,
+				Description:  q.QuizDescription,
+				TimeLimitSec: q.QuizTimeLimitSec,
+				Questions:    make([]SnapshotQuestion, 0, len(qs)),
+			}
+			for _, qq := range qs {
+				sq.Questions = append(sq.Questions, SnapshotQuestion{
+					Type:        qq.QuizQuestionType,
+					Text:        qq.QuizQuestionText,
+					Points:      qq.QuizQuestionPoints,
+					Answers:     json.RawMessage(qq.QuizQuestionAnswers),
+					Correct:     qq.QuizQuestionCorrect,
+					Explanation: qq.QuizQuestionExplanation,
+				})
+			}
+			snap.Quizzes = append(snap.Quizzes, sq)
+		}
+	}
+
+	buf, err := json.Marshal(snap)
+	if err != nil {
+		return nil, err
+	}
+	return datatypes.JSON(buf), nil
+}
+
+/* =========================
+   Penerbit
+   ========================= */
+
+// PublishLibrary: buat paket + versi 1.
+func PublishLibrary(ctx context.Context, db *gorm.DB, schoolID uuid.UUID, userID *uuid.UUID, in LibraryPublishInput) (*LibraryDetail, error) {
+	title := strings.TrimSpace(in.Title)
+	if title == "" || len(title) > 180 {
+		return nil, ErrLibraryInvalid
+	}
+	license, err := normalizeLicense(in.License)
+	if err != nil {
+		return nil, err
+	}
+	name, yayasanID, err := schoolInfo(ctx, db, schoolID)
+	if err != nil {
+		return nil, err
+	}
+
+	lib := model.MaterialLibraryModel{
+		MaterialLibraryID:              uuid.New(),
+		MaterialLibrarySchoolID:        schoolID,
+		MaterialLibraryTitle:           title,
+		MaterialLibraryDescription:     trimOrNil(in.Description),
+		MaterialLibraryLicense:         license,
+		MaterialLibraryAttribution:     name,
+		MaterialLibraryCurrentVersion:  1,
+		MaterialLibraryIsActive:        true,
+		MaterialLibraryCreatedByUserID: userID,
+	}
+	if a := trimOrNil(in.Attribution); a != nil {
+		lib.MaterialLibraryAttribution = *a
+	}
+	if err := applyVisibility(&lib, in.Visibility, yayasanID); err != nil {
+		return nil, err
+	}
+
+	items, err := resolveItems(ctx, db, schoolID, lib.MaterialLibraryID, in.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	var version model.MaterialLibraryVersionModel
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&lib).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&items).Error; err != nil {
+			return err
+		}
+		snap, err := buildSnapshot(ctx, tx, &lib, items)
+		if err != nil {
+			return err
+		}
+		version = model.MaterialLibraryVersionModel{
+			MaterialLibraryVersionLibraryID:       lib.MaterialLibraryID,
+			MaterialLibraryVersionNumber:          1,
+			MaterialLibraryVersionChangelog:       trimOrNil(in.Changelog),
+			MaterialLibraryVersionSnapshot:        snap,
+			MaterialLibraryVersionCreatedByUserID: userID,
+		}
+		return tx.Create(&version).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &LibraryDetail{Library: lib, PublisherName: name, Items: items, Version: &version}, nil
+}
+
+// UpdateLibrary: ubah metadata (isi paket baru berubah lewat PublishVersion).
+func UpdateLibrary(ctx context.Context, db *gorm.DB, schoolID, libraryID uuid.UUID, in LibraryPatchInput) (*model.MaterialLibraryModel, error) {
+	lib, err := loadOwnLibrary(ctx, db, schoolID, libraryID, false)
+	if err != nil {
+		return nil, err
+	}
+	if in.Title != nil {
+		t := strings.TrimSpace(*in.Title)
+		if t == "" || len(t) > 180 {
+			return nil, ErrLibraryInvalid
+		}
+		lib.MaterialLibraryTitle = t
+	}
+	if in.Description != nil {
+		lib.MaterialLibraryDescription = trimOrNil(in.Description)
+	}
+	if in.License != nil {
+		if lib.MaterialLibraryLicense, err = normalizeLicense(*in.License); err != nil {
+			return nil, err
+		}
+	}
+	if a := trimOrNil(in.Attribution); a != nil {
+		lib.MaterialLibraryAttribution = *a
+	}
+	if in.IsActive != nil {
+		lib.MaterialLibraryIsActive = *in.IsActive
+	}
+	if in.Visibility != nil {
+		_, yayasanID, err := schoolInfo(ctx, db, schoolID)
+		if err != nil {
+			return nil, err
+		}
+		if err := applyVisibility(lib, *in.Visibility, yayasanID); err != nil {
+			return nil, err
+		}
+	}
+	if err := db.WithContext(ctx).Save(lib).Error; err != nil {
+		return nil, err
+	}
+	return lib, nil
+}
+
+// PublishVersion: snapshot ulang isi sumber → versi baru.
+// items nil = pakai daftar item yang sudah ada.
+func PublishVersion(ctx context.Context, db *gorm.DB, schoolID, libraryID uuid.UUID, userID *uuid.UUID, items []LibraryItemInput, changelog *string) (*model.MaterialLibraryVersionModel, error) {
+	var version model.MaterialLibraryVersionModel
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		lib, err := loadOwnLibrary(ctx, tx, schoolID, libraryID, true)
+		if err != nil {
+			return err
+		}
+
+		var rows []model.MaterialLibraryItemModel
+		if items != nil {
+			if rows, err = resolveItems(ctx, tx, schoolID, libraryID, items); err != nil {
+				return err
+			}
+			if err := tx.Where("material_library_item_library_id = ?", libraryID).
+				Delete(&model.MaterialLibraryItemModel{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Create(&rows).Error; err != nil {
+				return err
+			}
+		} else {
+			if err := tx.Where("material_library_item_library_id = ?", libraryID).
+				Order("material_library_item_order ASC").
+				Find(&rows).Error; err != nil {
+				return err
+			}
+			if len(rows) == 0 {
+				return ErrLibraryNoItems
+			}
+		}
+
+		snap, err := buildSnapshot(ctx, tx, lib, rows)
+		if err != nil {
+			return err
+		}
+		version = model.MaterialLibraryVersionModel{
+			MaterialLibraryVersionLibraryID:       libraryID,
+			MaterialLibraryVersionNumber:          lib.MaterialLibraryCurrentVersion + 1,
+			MaterialLibraryVersionChangelog:       trimOrNil(changelog),
+			MaterialLibraryVersionSnapshot:        snap,
+			MaterialLibraryVersionCreatedByUserID: userID,
+		}
+		if err := tx.Create(&version).Error; err != nil {
+			return err
+		}
+		return tx.Model(lib).Updates(map[string]any{
+			"material_library_current_version": version.MaterialLibraryVersionNumber,
+			"material_library_updated_at":      gorm.Expr("NOW()"),
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+// DeleteLibrary: soft delete; salinan di sekolah pengimpor tetap ada (origin tetap tercatat).
+func DeleteLibrary(ctx context.Context, db *gorm.DB, schoolID, libraryID uuid.UUID, now time.Time) error {
+	res := db.WithContext(ctx).Model(&model.MaterialLibraryModel{}).
+		Where("material_library_id = ? AND material_library_school_id = ? AND material_library_deleted_at IS NULL", libraryID, schoolID).
+		Updates(map[string]any{
+			"material_library_deleted_at": now,
+			"material_library_is_active":  false,
+			"material_library_updated_at": now,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrLibraryNotFound
+	}
+	return nil
+}
+
+// ListMyLibraries: paket yang diterbitkan sekolah ini.
+func ListMyLibraries(ctx context.Context, db *gorm.DB, schoolID uuid.UUID) ([]model.MaterialLibraryModel, error) {
+	rows := []model.MaterialLibraryModel{}
+	err := db.WithContext(ctx).
+		Where("material_library_school_id = ? AND material_library_deleted_at IS NULL", schoolID).
+		Order("material_library_updated_at DESC").
+		Find(&rows).Error
+	return rows, err
+}
+
+/* =========================
+   Katalog & detail
+   ========================= */
+
+// Catalog: paket sekolah lain yang terlihat (publik + yayasan yang sama).
+func Catalog(ctx context.Context, db *gorm.DB, schoolID uuid.UUID, q string, offset, limit int) ([]CatalogItem, int64, error) {
+	_, yayasanID, err := schoolInfo(ctx, db, schoolID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	base := db.WithContext(ctx).Model(&model.MaterialLibraryModel{}).
+		Where("material_library_deleted_at IS NULL AND material_library_is_active = TRUE AND material_library_school_id <> ?", schoolID)
+	if yayasanID != nil {
+		base = base.Where("(material_library_visibility = ? OR (material_library_visibility = ? AND material_library_yayasan_id = ?))",
+			model.LibraryVisibilityPublic, model.LibraryVisibilityYayasan, *yayasanID)
+	} else {
+		base = base.Where("material_library_visibility = ?", model.LibraryVisibilityPublic)
+	}
+	if s := strings.TrimSpace(q); s != "" {
+		like := "%" + s + "%"
+		base = base.Where("(material_library_title ILIKE ? OR material_library_description ILIKE ?)", like, like)
+	}
+
+	var total int64
+	if err := base.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var libs []model.MaterialLibraryModel
+	if err := base.Order("material_library_updated_at DESC").Offset(offset).Limit(limit).Find(&libs).Error; err != nil {
+		return nil, 0, err
+	}
+
+	publisherIDs := make([]uuid.UUID, 0, len(libs))
+	libIDs := make([]uuid.UUID, 0, len(libs))
+	for _, l := range libs {
+		publisherIDs = append(publisherIDs, l.MaterialLibrarySchoolID)
+		libIDs = append(libIDs, l.MaterialLibraryID)
+	}
+	names, err := schoolNames(ctx, db, publisherIDs)
+	if err != nil {
+		return nil, 0, err
+	}
+	imported := map[uuid.UUID]int{}
+	if len(libIDs) > 0 {
+		var imps []model.MaterialLibraryImportModel
+		if err := db.WithContext(ctx).
+			Where("material_library_import_school_id = ? AND material_library_import_library_id IN ?", schoolID, libIDs).
+			Find(&imps).Error; err != nil {
+			return nil, 0, err
+		}
+		for _, im := range imps {
+			imported[im.MaterialLibraryImportLibraryID] = im.MaterialLibraryImportVersion
+		}
+	}
+
+	out := make([]CatalogItem, 0, len(libs))
+	for _, l := range libs {
+		it := CatalogItem{MaterialLibraryModel: l, PublisherName: names[l.MaterialLibrarySchoolID]}
+		if v, ok := imported[l.MaterialLibraryID]; ok {
+			v := v
+			it.ImportedVersion = &v
+		}
+		out = append(out, it)
+	}
+	return out, total, nil
+}
+
+// GetLibrary: detail + snapshot versi terbaru (item sumber hanya untuk penerbit).
+func GetLibrary(ctx context.Context, db *gorm.DB, schoolID, libraryID uuid.UUID) (*LibraryDetail, error) {
+	lib, err := loadVisibleLibrary(ctx, db, schoolID, libraryID)
+	if err != nil {
+		return nil, err
+	}
+	names, err := schoolNames(ctx, db, []uuid.UUID{lib.MaterialLibrarySchoolID})
+	if err != nil {
+		return nil, err
+	}
+	out := &LibraryDetail{Library: *lib, PublisherName: names[lib.MaterialLibrarySchoolID]}
+
+	v, _, err := loadVersion(ctx, db, libraryID, lib.MaterialLibraryCurrentVersion)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	out.Version = v
+
+	if lib.MaterialLibrarySchoolID == schoolID {
+		if err := db.WithContext(ctx).
+			Where("material_library_item_library_id = ?", libraryID).
+			Order("material_library_item_order ASC").
+			Find(&out.Items).Error; err != nil {
+			return nil, err
+		}
+		return out, nil
+	}
+
+	var imp model.MaterialLibraryImportModel
+	err = db.WithContext(ctx).
+		Where("material_library_import_library_id = ? AND material_library_import_school_id = ?", libraryID, schoolID).
+		Take(&imp).Error
+	if err == nil {
+		out.ImportedVersion = &imp.MaterialLibraryImportVersion
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListVersions: riwayat versi (tanpa snapshot).
+func ListVersions(ctx context.Context, db *gorm.DB, schoolID, libraryID uuid.UUID) ([]model.MaterialLibraryVersionModel, error) {
+	if _, err := loadVisibleLibrary(ctx, db, schoolID, libraryID); err != nil {
+		return nil, err
+	}
+	rows := []model.MaterialLibraryVersionModel{}
+	err := db.WithContext(ctx).
+		Select("material_library_version_id", "material_library_version_library_id", "material_library_version_number",
+			"material_library_version_changelog", "material_library_version_created_by_user_id", "material_library_version_created_at").
+		Where("material_library_version_library_id = ?", libraryID).
+		Order("material_library_version_number DESC").
+		Find(&rows).Error
+	return rows, err
+}
+
+/* =========================
+   Pengimpor
+   ========================= */
+
+// ImportLibrary: salin versi terbaru ke sekolah ini.
+func ImportLibrary(ctx context.Context, db *gorm.DB, schoolID, libraryID uuid.UUID, userID *uuid.UUID, now time.Time) (*LibrarySyncResult, error) {
+	lib, err := loadVisibleLibrary(ctx, db, schoolID, libraryID)
+	if err != nil {
+		return nil, err
+	}
+	if lib.MaterialLibrarySchoolID == schoolID {
+		return nil, ErrLibraryOwnImport
+	}
+	if !lib.MaterialLibraryIsActive {
+		return nil, ErrLibraryNotFound
+	}
+	_, snap, err := loadVersion(ctx, db, libraryID, lib.MaterialLibraryCurrentVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	imp := model.MaterialLibraryImportModel{
+		MaterialLibraryImportLibraryID:        libraryID,
+		MaterialLibraryImportSchoolID:         schoolID,
+		MaterialLibraryImportVersion:          lib.MaterialLibraryCurrentVersion,
+		MaterialLibraryImportImportedByUserID: userID,
+		MaterialLibraryImportSyncedAt:         now,
+	}
+	res := &LibrarySyncResult{LibraryID: libraryID, ToVersion: lib.MaterialLibraryCurrentVersion}
+
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ins := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&imp)
+		if ins.Error != nil {
+			return ins.Error
+		}
+		if ins.RowsAffected == 0 {
+			return ErrLibraryImported
+		}
+		items, err := applySnapshot(ctx, tx, schoolID, libraryID, lib.MaterialLibraryCurrentVersion, snap, userID, now)
+		if err != nil {
+			return err
+		}
+		res.Items = items
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	res.ImportID = imp.MaterialLibraryImportID
+	return res, nil
+}
+
+// ListImports: paket yang diimpor sekolah ini + penanda versi baru.
+func ListImports(ctx context.Context, db *gorm.DB, schoolID uuid.UUID) ([]ImportRow, error) {
+	var imps []model.MaterialLibraryImportModel
+	if err := db.WithContext(ctx).
+		Where("material_library_import_school_id = ?", schoolID).
+		Order("material_library_import_synced_at DESC").
+		Find(&imps).Error; err != nil {
+		return nil, err
+	}
+	if len(imps) == 0 {
+		return []ImportRow{}, nil
+	}
+
+	libIDs := make([]uuid.UUID, 0, len(imps))
+	for _, im := range imps {
+		libIDs = append(libIDs, im.MaterialLibraryImportLibraryID)
+	}
+	var libs []model.MaterialLibraryModel
+	if err := db.WithContext(ctx).Where("material_library_id IN ?", libIDs).Find(&libs).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]model.MaterialLibraryModel, len(libs))
+	publisherIDs := make([]uuid.UUID, 0, len(libs))
+	for _, l := range libs {
+		byID[l.MaterialLibraryID] = l
+		publisherIDs = append(publisherIDs, l.MaterialLibrarySchoolID)
+	}
+	names, err := schoolNames(ctx, db, publisherIDs)
+	if err != nil {
+		return nil, err
+	}
+	_, yayasanID, err := schoolInfo(ctx, db, schoolID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]ImportRow, 0, len(imps))
+	for _, im := range imps {
+		l := byID[im.MaterialLibraryImportLibraryID]
+		row := ImportRow{
+			MaterialLibraryImportModel: im,
+			LibraryTitle:               l.MaterialLibraryTitle,
+			License:                    l.MaterialLibraryLicense,
+			Attribution:                l.MaterialLibraryAttribution,
+			PublisherName:              names[l.MaterialLibrarySchoolID],
+			CurrentVersion:             l.MaterialLibraryCurrentVersion,
+			Available:                  visibleTo(&l, schoolID, yayasanID),
+		}
+		row.UpdateAvailable = row.Available && l.MaterialLibraryCurrentVersion > im.MaterialLibraryImportVersion
+		out = append(out, row)
+	}
+	return out, nil
+}
+
+// SyncImport: terapkan versi terbaru ke salinan sekolah ini.
+func SyncImport(ctx context.Context, db *gorm.DB, schoolID, importID uuid.UUID, userID *uuid.UUID, now time.Time) (*LibrarySyncResult, error) {
+	var res *LibrarySyncResult
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var imp model.MaterialLibraryImportModel
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("material_library_import_id = ? AND material_library_import_school_id = ?", importID, schoolID).
+			Take(&imp).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrImportNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		lib, err := loadVisibleLibrary(ctx, tx, schoolID, imp.MaterialLibraryImportLibraryID)
+		if err != nil {
+			return err
+		}
+		from := imp.MaterialLibraryImportVersion
+		res = &LibrarySyncResult{
+			ImportID:    imp.MaterialLibraryImportID,
+			LibraryID:   lib.MaterialLibraryID,
+			FromVersion: &from,
+			ToVersion:   lib.MaterialLibraryCurrentVersion,
+			Items:       []LibrarySyncItem{},
+		}
+		if lib.MaterialLibraryCurrentVersion <= from {
+			res.UpToDate = true
+			return nil
+		}
+
+		_, snap, err := loadVersion(ctx, tx, lib.MaterialLibraryID, lib.MaterialLibraryCurrentVersion)
+		if err != nil {
+			return err
+		}
+		items, err := applySnapshot(ctx, tx, schoolID, lib.MaterialLibraryID, lib.MaterialLibraryCurrentVersion, snap, userID, now)
+		if err != nil {
+			return err
+		}
+		res.Items = items
+
+		return tx.Model(&imp).Updates(map[string]any{
+			"material_library_import_version":   lib.MaterialLibraryCurrentVersion,
+			"material_library_import_synced_at": now,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+/* =========================
+   Terapkan snapshot ke sekolah pengimpor
+   ========================= */
+
+func applySnapshot(ctx context.Context, tx *gorm.DB, schoolID, libraryID uuid.UUID, version int, snap *LibrarySnapshot, userID *uuid.UUID, now time.Time) ([]LibrarySyncItem, error) {
+	out := []LibrarySyncItem{}
+	keepMaterials := make([]uuid.UUID, 0, len(snap.Materials))
+	keepQuizzes := make([]uuid.UUID, 0, len(snap.Quizzes))
+
+	for i := range snap.Materials {
+		sm := &snap.Materials[i]
+		keepMaterials = append(keepMaterials, sm.SourceID)
+		item, err := syncMaterialCopy(ctx, tx, schoolID, libraryID, version, sm, userID, now)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, item)
+	}
+	for i := range snap.Quizzes {
+		sq := &snap.Quizzes[i]
+		keepQuizzes = append(keepQuizzes, sq.SourceID)
+		item, err := syncQuizCopy(ctx, tx, schoolID, libraryID, version, sq)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, item)
+	}
+
+	// salinan yang sumbernya tidak ada lagi di versi ini → dilaporkan saja
+	var goneMaterials []model.SchoolMaterialModel
+	qm := tx.WithContext(ctx).
+		Where("school_material_school_id = ? AND school_material_origin_library_id = ? AND school_material_deleted = FALSE", schoolID, libraryID)
+	if len(keepMaterials) > 0 {
+		qm = qm.Where("school_material_origin_source_id NOT IN ?", keepMaterials)
+	}
+	if err := qm.Find(&goneMaterials).Error; err != nil {
+		return nil, err
+	}
+	for _, m := range goneMaterials {
+		id := m.SchoolMaterialID
+		out = append(out, LibrarySyncItem{Kind: model.LibraryItemKindMaterial, SourceID: *m.SchoolMaterialOriginSourceID, CopyID: &id, Title: m.SchoolMaterialTitle, Status: LibrarySyncRemovedUpstream})
+	}
+
+	var goneQuizzes []qmodel.QuizModel
+	qq := tx.WithContext(ctx).
+		Where("quiz_school_id = ? AND quiz_origin_library_id = ?", schoolID, libraryID)
+	if len(keepQuizzes) > 0 {
+		qq = qq.Where("quiz_origin_source_id NOT IN ?", keepQuizzes)
+	}
+	if err := qq.Find(&goneQuizzes).Error; err != nil {
+		return nil, err
+	}
+	for _, q := range goneQuizzes {
+		id := q.QuizID
+		out = append(out, LibrarySyncItem{Kind: model.LibraryItemKindQuiz, SourceID: *q.QuizOriginSourceID, CopyID: &id, Title: q.QuizTitle, Status: LibrarySyncRemovedUpstream})
+	}
+	return out, nil
+}
+
+func syncMaterialCopy(ctx context.Context, tx *gorm.DB, schoolID, libraryID uuid.UUID, version int, sm *SnapshotMaterial, userID *uuid.UUID, now time.Time) (LibrarySyncItem, error) {
+	item := LibrarySyncItem{Kind: model.LibraryItemKindMaterial, SourceID: sm.SourceID, Title: sm.Title}
+	v := version
+
+	var cp model.SchoolMaterialModel
+	err := tx.WithContext(ctx).
+		Where("school_material_school_id = ? AND school_material_origin_library_id = ? AND school_material_origin_source_id = ?",
+			schoolID, libraryID, sm.SourceID).
+		Order("school_material_deleted ASC, school_material_created_at DESC").
+		Take(&cp).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		src := sm.SourceID
+		cp = model.SchoolMaterialModel{
+			SchoolMaterialSchoolID:        schoolID,
+			SchoolMaterialCreatedByUserID: userID,
+			SchoolMaterialOriginLibraryID: &libraryID,
+			SchoolMaterialOriginSourceID:  &src,
+			// salinan baru belum terbit di sekolah pengimpor
+			SchoolMaterialIsActive:    true,
+			SchoolMaterialIsPublished: false,
+			SchoolMaterialCreatedAt:   now,
+		}
+		item.Status = LibrarySyncAdded
+	case err != nil:
+		return item, err
+	case cp.SchoolMaterialDeleted:
+		id := cp.SchoolMaterialID
+		item.CopyID = &id
+		item.Status = LibrarySyncSkippedDeleted
+		return item, nil
+	default:
+		item.Status = LibrarySyncUpdated
+	}
+
+	cp.SchoolMaterialTitle = sm.Title
+	cp.SchoolMaterialDescription = sm.Description
+	cp.SchoolMaterialType = sm.Type
+	cp.SchoolMaterialContentHTML = sm.ContentHTML
+	cp.SchoolMaterialFileURL = sm.FileURL
+	cp.SchoolMaterialFileName = sm.FileName
+	cp.SchoolMaterialFileMimeType = sm.FileMimeType
+	cp.SchoolMaterialFileSizeBytes = sm.FileSizeBytes
+	cp.SchoolMaterialExternalURL = sm.ExternalURL
+	cp.SchoolMaterialYouTubeID = sm.YouTubeID
+	cp.SchoolMaterialDurationSec = sm.DurationSec
+	cp.SchoolMaterialImportance = sm.Importance
+	cp.SchoolMaterialIsRequiredForPass = sm.IsRequiredForPass
+	cp.SchoolMaterialAffectsScoring = sm.AffectsScoring
+	cp.SchoolMaterialMeetingNumber = sm.MeetingNumber
+	cp.SchoolMaterialDefaultOrder = sm.DefaultOrder
+	cp.SchoolMaterialScopeTag = sm.ScopeTag
+	cp.SchoolMaterialOriginVersion = &v
+	cp.SchoolMaterialUpdatedAt = now
+
+	if item.Status == LibrarySyncAdded {
+		err = tx.WithContext(ctx).Create(&cp).Error
+	} else {
+		err = tx.WithContext(ctx).Save(&cp).Error
+	}
+	if err != nil {
+		return item, err
+	}
+	id := cp.SchoolMaterialID
+	item.CopyID = &id
+	return item, nil
+}
+
+func syncQuizCopy(ctx context.Context, tx *gorm.DB, schoolID, libraryID uuid.UUID, version int, sq *SnapshotQuiz) (LibrarySyncItem, error) {
+	item := LibrarySyncItem{Kind: model.LibraryItemKindQuiz, SourceID: sq.SourceID, Title: sq.Title}
+	v := version
+
+	var cp qmodel.QuizModel
+	err := tx.WithContext(ctx).Unscoped().
+		Where("quiz_school_id = ? AND quiz_origin_library_id = ? AND quiz_origin_source_id = ?", schoolID, libraryID, sq.SourceID).
+		Order("quiz_deleted_at DESC NULLS FIRST, quiz_created_at DESC").
+		Take(&cp).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		src := sq.SourceID
+		cp = qmodel.QuizModel{
+			QuizSchoolID:        schoolID,
+			QuizTitle:           sq.Title,
+			QuizDescription:     sq.Description,
+			QuizTimeLimitSec:    sq.TimeLimitSec,
+			QuizIsPublished:     false,
+			QuizTotalQuestions:  len(sq.Questions),
+			QuizOriginLibraryID: &libraryID,
+			QuizOriginSourceID:  &src,
+			QuizOriginVersion:   &v,
+		}
+		if err := tx.WithContext(ctx).Create(&cp).Error; err != nil {
+			return item, err
+		}
+		if err := insertSnapshotQuestions(ctx, tx, &cp, sq.Questions); err != nil {
+			return item, err
+		}
+		id := cp.QuizID
+		item.CopyID = &id
+		item.Status = LibrarySyncAdded
+		return item, nil
+	case err != nil:
+		return item, err
+	}
+
+	id := cp.QuizID
+	item.CopyID = &id
+	if cp.QuizDeletedAt.Valid {
+		item.Status = LibrarySyncSkippedDeleted
+		return item, nil
+	}
+
+	// sudah dikerjakan murid → soal tidak diganti (nilai & analisis tetap konsisten)
+	var attempts int64
+	if err := tx.WithContext(ctx).Table("student_quiz_attempts").
+		Where("student_quiz_attempt_quiz_id = ?", cp.QuizID).
+		Count(&attempts).Error; err != nil {
+		return item, err
+	}
+	if attempts > 0 {
+		item.Status = LibrarySyncSkippedAttempts
+		return item, nil
+	}
+
+	if err := tx.WithContext(ctx).
+		Where("quiz_question_quiz_id = ? AND quiz_question_is_pool = FALSE", cp.QuizID).
+		Delete(&qmodel.QuizQuestionModel{}).Error; err != nil {
+		return item, err
+	}
+	if err := insertSnapshotQuestions(ctx, tx, &cp, sq.Questions); err != nil {
+		return item, err
+	}
+	if err := tx.WithContext(ctx).Model(&cp).Updates(map[string]any{
+		"quiz_title":           sq.Title,
+		"quiz_description":     sq.Description,
+		"quiz_time_limit_sec":  sq.TimeLimitSec,
+		"quiz_total_questions": len(sq.Questions),
+		"quiz_origin_version":  v,
+		"quiz_updated_at":      gorm.Expr("NOW()"),
+	}).Error; err != nil {
+		return item, err
+	}
+	item.Status = LibrarySyncUpdated
+	return item, nil
+}
+
+func insertSnapshotQuestions(ctx context.Context, tx *gorm.DB, quiz *qmodel.QuizModel, qs []SnapshotQuestion) error {
+	if len(qs) == 0 {
+		return nil
+	}
+	rows := make([]qmodel.QuizQuestionModel, 0, len(qs))
+	for _, q := range qs {
+		row := qmodel.QuizQuestionModel{
+			QuizQuestionQuizID:      quiz.QuizID,
+			QuizQuestionSchoolID:    quiz.QuizSchoolID,
+			QuizQuestionType:        q.Type,
+			QuizQuestionText:        q.Text,
+			QuizQuestionPoints:      q.Points,
+			QuizQuestionCorrect:     q.Correct,
+			QuizQuestionExplanation: q.Explanation,
+			QuizQuestionVersion:     1,
+			QuizQuestionHistory:     datatypes.JSON([]byte("[]")),
+		}
+		if len(q.Answers) > 0 && string(q.Answers) != "null" {
+			row.QuizQuestionAnswers = datatypes.JSON(q.Answers)
+		}
+		rows = append(rows, row)
+	}
+	return tx.WithContext(ctx).Create(&rows).Error
+}
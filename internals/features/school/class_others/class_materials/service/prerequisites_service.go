@@ -0,0 +1,178 @@
+// file: internals/features/school/class_others/class_materials/service/prerequisites_service.go
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	model "madinahsalam_backend/internals/features/school/class_others/class_materials/model"
+)
+
+/* =========================================================
+   Prasyarat materi (guru/DKM)
+   - PUT mengganti seluruh prasyarat 1 materi
+   - materi syarat wajib dari CSST yang sama; rantai materi tidak boleh melingkar
+========================================================= */
+
+type PrereqInput struct {
+	Kind               string
+	RequiredMaterialID *uuid.UUID
+	RequiredQuizID     *uuid.UUID
+	MinPercent         *float64
+}
+
+func loadMaterial(ctx context.Context, db *gorm.DB, schoolID, csstID, materialID uuid.UUID) (*model.ClassMaterialsModel, error) {
+	var m model.ClassMaterialsModel
+	err := db.WithContext(ctx).
+		Where("class_material_id = ? AND class_material_school_id = ? AND class_material_csst_id = ? AND NOT class_material_deleted",
+			materialID, schoolID, csstID).
+		Take(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrMaterialNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// ListPrerequisites: prasyarat 1 materi.
+func ListPrerequisites(ctx context.Context, db *gorm.DB, schoolID, csstID, materialID uuid.UUID) ([]model.ClassMaterialPrerequisiteModel, error) {
+	if _, err := loadMaterial(ctx, db, schoolID, csstID, materialID); err != nil {
+		return nil, err
+	}
+	rows := []model.ClassMaterialPrerequisiteModel{}
+	err := db.WithContext(ctx).
+		Where("class_material_prerequisite_school_id = ? AND class_material_prerequisite_class_material_id = ?", schoolID, materialID).
+		Order("class_material_prerequisite_created_at ASC").
+		Find(&rows).Error
+	return rows, err
+}
+
+// SetPrerequisites: ganti seluruh prasyarat materi (list kosong = hapus semua).
+func SetPrerequisites(ctx context.Context, db *gorm.DB, schoolID, csstID, materialID uuid.UUID, in []PrereqInput) ([]model.ClassMaterialPrerequisiteModel, error) {
+	if _, err := loadMaterial(ctx, db, schoolID, csstID, materialID); err != nil {
+		return nil, err
+	}
+
+	rows := make([]model.ClassMaterialPrerequisiteModel, 0, len(in))
+	seen := map[uuid.UUID]bool{}
+	requiredMaterials := []uuid.UUID{}
+	for _, p := range in {
+		row := model.ClassMaterialPrerequisiteModel{
+			ClassMaterialPrerequisiteSchoolID:   schoolID,
+			ClassMaterialPrerequisiteMaterialID: materialID,
+			ClassMaterialPrerequisiteKind:       strings.ToLower(strings.TrimSpace(p.Kind)),
+		}
+		switch row.ClassMaterialPrerequisiteKind {
+		case model.PrerequisiteKindMaterial:
+			if p.RequiredMaterialID == nil || *p.RequiredMaterialID == uuid.Nil {
+				return nil, ErrPrereqInvalid
+			}
+			if *p.RequiredMaterialID == materialID {
+				return nil, ErrPrereqSelf
+			}
+			if _, err := loadMaterial(ctx, db, schoolID, csstID, *p.RequiredMaterialID); err != nil {
+				if errors.Is(err, ErrMaterialNotFound) {
+					return nil, ErrPrereqMaterialSide
+				}
+				return nil, err
+			}
+			if seen[*p.RequiredMaterialID] {
+				continue
+			}
+			seen[*p.RequiredMaterialID] = true
+			requiredMaterials = append(requiredMaterials, *p.RequiredMaterialID)
+			row.ClassMaterialPrerequisiteRequiredMaterialID = p.RequiredMaterialID
+		case model.PrerequisiteKindQuiz:
+			if p.RequiredQuizID == nil || *p.RequiredQuizID == uuid.Nil {
+				return nil, ErrPrereqInvalid
+			}
+			if p.MinPercent != nil && (*p.MinPercent < 0 || *p.MinPercent > 100) {
+				return nil, ErrPrereqInvalid
+			}
+			var n int64
+			if err := db.WithContext(ctx).Table("quizzes").
+				Where("quiz_id = ? AND quiz_school_id = ? AND quiz_deleted_at IS NULL", *p.RequiredQuizID, schoolID).
+				Count(&n).Error; err != nil {
+				return nil, err
+			}
+			if n == 0 {
+				return nil, ErrPrereqQuizNotFound
+			}
+			if seen[*p.RequiredQuizID] {
+				continue
+			}
+			seen[*p.RequiredQuizID] = true
+			row.ClassMaterialPrerequisiteRequiredQuizID = p.RequiredQuizID
+			row.ClassMaterialPrerequisiteMinPercent = p.MinPercent
+		default:
+			return nil, ErrPrereqInvalid
+		}
+		rows = append(rows, row)
+	}
+
+	if len(requiredMaterials) > 0 {
+		cyclic, err := createsCycle(ctx, db, schoolID, csstID, materialID, requiredMaterials)
+		if err != nil {
+			return nil, err
+		}
+		if cyclic {
+			return nil, ErrPrereqCycle
+		}
+	}
+
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("class_material_prerequisite_school_id = ? AND class_material_prerequisite_class_material_id = ?", schoolID, materialID).
+			Delete(&model.ClassMaterialPrerequisiteModel{}).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		return tx.Create(&rows).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// createsCycle: apakah salah satu materi syarat (langsung/tidak langsung) mensyaratkan materialID.
+func createsCycle(ctx context.Context, db *gorm.DB, schoolID, csstID, materialID uuid.UUID, required []uuid.UUID) (bool, error) {
+	var edges []model.ClassMaterialPrerequisiteModel
+	if err := db.WithContext(ctx).
+		Where(`class_material_prerequisite_school_id = ?
+		   AND class_material_prerequisite_kind = ?
+		   AND class_material_prerequisite_class_material_id <> ?
+		   AND class_material_prerequisite_class_material_id IN (
+		     SELECT class_material_id FROM class_materials WHERE class_material_csst_id = ?)`,
+			schoolID, model.PrerequisiteKindMaterial, materialID, csstID).
+		Find(&edges).Error; err != nil {
+		return false, err
+	}
+	graph := map[uuid.UUID][]uuid.UUID{}
+	for _, e := range edges {
+		graph[e.ClassMaterialPrerequisiteMaterialID] = append(graph[e.ClassMaterialPrerequisiteMaterialID], *e.ClassMaterialPrerequisiteRequiredMaterialID)
+	}
+
+	visited := map[uuid.UUID]bool{}
+	stack := append([]uuid.UUID{}, required...)
+	for len(stack) > 0 {
+		cur := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if cur == materialID {
+			return true, nil
+		}
+		if visited[cur] {
+			continue
+		}
+		visited[cur] = true
+		stack = append(stack, graph[cur]...)
+	}
+	return false, nil
+}
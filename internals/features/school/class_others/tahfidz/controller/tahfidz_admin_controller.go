@@ -0,0 +1,127 @@
+// file: internals/features/school/class_others/tahfidz/controller/tahfidz_admin_controller.go
+package controller
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	dto "madinahsalam_backend/internals/features/school/class_others/tahfidz/dto"
+	svc "madinahsalam_backend/internals/features/school/class_others/tahfidz/service"
+	helper "madinahsalam_backend/internals/helpers"
+)
+
+/*
+Tahfidz admin (target semester & rapor)
+
+GET    /api/a/tahfidz/targets?term_id=
+POST   /api/a/tahfidz/targets                 {"term_id","class_section_id"|"school_student_id"|-, "pages","juz_total","note"}
+PATCH  /api/a/tahfidz/targets/:id             {"pages","juz_total","note"}
+DELETE /api/a/tahfidz/targets/:id
+
+GET    /api/a/tahfidz/students/:id/rapor      ringkasan per semester
+POST   /api/a/tahfidz/rapor/sync              {"term_id","class_subject_id"} → breakdown["tahfidz"] di user_subject_summary
+*/
+
+/* =========================================================
+   TARGET
+========================================================= */
+
+// GET /api/a/tahfidz/targets
+func (h *TahfidzController) ListTargets(c *fiber.Ctx) error {
+	schoolID, _, err := scope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	termID, err := uuidQuery(c, "term_id")
+	if err != nil {
+		return writeErr(c, err)
+	}
+	rows, err := svc.ListTargets(c.Context(), h.DB, schoolID, termID)
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusInternalServerError, "Gagal mengambil target hafalan")
+	}
+	return helper.JsonOK(c, "OK", rows)
+}
+
+// POST /api/a/tahfidz/targets
+func (h *TahfidzController) CreateTarget(c *fiber.Ctx) error {
+	schoolID, _, err := scope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	var req dto.CreateTargetRequest
+	if err := c.BodyParser(&req); err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "Payload tidak valid")
+	}
+	m, err := svc.CreateTarget(c.Context(), h.DB, schoolID, req.ToInput())
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonCreated(c, "Target hafalan dibuat", m)
+}
+
+// PATCH /api/a/tahfidz/targets/:id
+func (h *TahfidzController) UpdateTarget(c *fiber.Ctx) error {
+	schoolID, id, err := scope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	var req dto.UpdateTargetRequest
+	if err := c.BodyParser(&req); err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "Payload tidak valid")
+	}
+	m, err := svc.UpdateTarget(c.Context(), h.DB, schoolID, id, req.ToInput())
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonUpdated(c, "Target hafalan diperbarui", m)
+}
+
+// DELETE /api/a/tahfidz/targets/:id
+func (h *TahfidzController) DeleteTarget(c *fiber.Ctx) error {
+	schoolID, id, err := scope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	if err := svc.DeleteTarget(c.Context(), h.DB, schoolID, id); err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonDeleted(c, "Target hafalan dihapus", fiber.Map{"tahfidz_target_id": id})
+}
+
+/* =========================================================
+   RAPOR
+========================================================= */
+
+// GET /api/a/tahfidz/students/:id/rapor
+func (h *TahfidzController) StudentRapor(c *fiber.Ctx) error {
+	schoolID, studentID, err := scope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	r, err := svc.StudentRapor(c.Context(), h.DB, schoolID, studentID)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonOK(c, "OK", r)
+}
+
+// POST /api/a/tahfidz/rapor/sync
+func (h *TahfidzController) SyncRapor(c *fiber.Ctx) error {
+	schoolID, _, err := scope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	var req dto.SyncRaporRequest
+	if err := c.BodyParser(&req); err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "Payload tidak valid")
+	}
+	if req.TermID == uuid.Nil || req.ClassSubjectID == uuid.Nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "term_id dan class_subject_id wajib diisi")
+	}
+	res, err := svc.SyncRapor(c.Context(), h.DB, schoolID, req.TermID, req.ClassSubjectID)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonUpdated(c, "Rapor tahfidz disinkronkan", res)
+}
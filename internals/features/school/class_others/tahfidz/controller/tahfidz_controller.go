@@ -0,0 +1,361 @@
+// file: internals/features/school/class_others/tahfidz/controller/tahfidz_controller.go
+package controller
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	permsvc "madinahsalam_backend/internals/features/lembaga/permissions/service"
+	dto "madinahsalam_backend/internals/features/school/class_others/tahfidz/dto"
+	"madinahsalam_backend/internals/features/school/class_others/tahfidz/quran"
+	svc "madinahsalam_backend/internals/features/school/class_others/tahfidz/service"
+	helper "madinahsalam_backend/internals/helpers"
+	helperAuth "madinahsalam_backend/internals/helpers/auth"
+	"madinahsalam_backend/internals/helpers/dbtime"
+)
+
+/*
+Tahfidz (guru/DKM mencatat; siswa hanya membaca miliknya)
+
+GET    /api/u/tahfidz/quran/surahs
+GET    /api/u/tahfidz/quran/juz
+
+GET    /api/u/tahfidz/records?school_student_id=&teacher_id=&session_id=&term_id=&type=&from=&to=
+POST   /api/u/tahfidz/records       {"school_student_id","session_id","type","juz"|"surah_from","ayah_from","surah_to","ayah_to",
+                                     "score_tajwid","score_fluency","score_makhraj","is_passed","recorded_on","note"}
+GET    /api/u/tahfidz/records/:id
+PATCH  /api/u/tahfidz/records/:id
+DELETE /api/u/tahfidz/records/:id
+
+GET    /api/u/tahfidz/students/:id/progress?term_id=     (:id boleh "me" untuk siswa)
+GET    /api/u/tahfidz/class-sections/:id/leaderboard?term_id=
+GET    /api/u/tahfidz/sessions/:id/pending              siswa hadir yang belum setor
+*/
+
+type TahfidzController struct {
+	DB *gorm.DB
+}
+
+func NewTahfidzController(db *gorm.DB) *TahfidzController {
+	return &TahfidzController{DB: db}
+}
+
+func writeErr(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, svc.ErrRecordNotFound), errors.Is(err, svc.ErrTargetNotFound), errors.Is(err, svc.ErrSessionNotFound),
+		errors.Is(err, svc.ErrTermNotFound):
+		return helper.JsonError(c, fiber.StatusNotFound, err.Error())
+	case errors.Is(err, svc.ErrTargetExists), errors.Is(err, svc.ErrMemorizationDisabled):
+		return helper.JsonError(c, fiber.StatusConflict, err.Error())
+	case errors.Is(err, svc.ErrInvalidType), errors.Is(err, svc.ErrInvalidRange), errors.Is(err, svc.ErrRangeReversed),
+		errors.Is(err, svc.ErrRangeRequired), errors.Is(err, svc.ErrInvalidJuz), errors.Is(err, svc.ErrInvalidScore),
+		errors.Is(err, svc.ErrStudentNotInSchool), errors.Is(err, svc.ErrTeacherNotInSchool),
+		errors.Is(err, svc.ErrTargetSubject), errors.Is(err, svc.ErrTargetPages), errors.Is(err, svc.ErrTargetJuz),
+		errors.Is(err, svc.ErrSectionInvalid):
+		return helper.JsonError(c, fiber.StatusBadRequest, err.Error())
+	}
+	var fe *fiber.Error
+	if errors.As(err, &fe) {
+		return helper.JsonError(c, fe.Code, fe.Message)
+	}
+	return helper.JsonError(c, fiber.StatusInternalServerError, err.Error())
+}
+
+// scope: school aktif + :id (bila ada di route).
+func scope(c *fiber.Ctx) (uuid.UUID, uuid.UUID, error) {
+	schoolID, err := permsvc.SchoolFromRequest(c)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	if c.Params("id") == "" {
+		return schoolID, uuid.Nil, nil
+	}
+	id, err := uuid.Parse(strings.TrimSpace(c.Params("id")))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fiber.NewError(fiber.StatusBadRequest, "id tidak valid")
+	}
+	return schoolID, id, nil
+}
+
+// isStaff: guru penyimak / DKM / owner boleh mencatat & melihat semua siswa.
+func isStaff(c *fiber.Ctx, schoolID uuid.UUID) bool {
+	return helperAuth.IsOwner(c) || helperAuth.IsDKMInSchool(c, schoolID) || helperAuth.IsTeacherInSchool(c, schoolID)
+}
+
+func requireStaff(c *fiber.Ctx, schoolID uuid.UUID) error {
+	if !isStaff(c, schoolID) {
+		return fiber.NewError(fiber.StatusForbidden, "Hanya guru/DKM yang boleh mencatat setoran")
+	}
+	return nil
+}
+
+// ownStudentID: school_student_id siswa yang login (non-staff).
+func ownStudentID(c *fiber.Ctx, schoolID uuid.UUID) (uuid.UUID, error) {
+	if !helperAuth.IsStudentInSchool(c, schoolID) {
+		return uuid.Nil, fiber.NewError(fiber.StatusForbidden, "Akses ditolak")
+	}
+	id, err := helperAuth.GetSchoolStudentIDForSchool(c, schoolID)
+	if err != nil || id == uuid.Nil {
+		return uuid.Nil, fiber.NewError(fiber.StatusForbidden, "Akses ditolak")
+	}
+	return id, nil
+}
+
+func actorOf(c *fiber.Ctx, schoolID uuid.UUID) svc.Actor {
+	a := svc.Actor{Today: dbtime.NowInSchool(c)}
+	if uid, err := helperAuth.GetUserIDFromToken(c); err == nil && uid != uuid.Nil {
+		a.UserID = &uid
+	}
+	if helperAuth.IsTeacherInSchool(c, schoolID) {
+		if tid, err := helperAuth.GetSchoolTeacherIDForSchool(c, schoolID); err == nil && tid != uuid.Nil {
+			a.TeacherID = &tid
+		}
+	}
+	return a
+}
+
+func uuidQuery(c *fiber.Ctx, key string) (*uuid.UUID, error) {
+	v := strings.TrimSpace(c.Query(key))
+	if v == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(v)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, key+" tidak valid")
+	}
+	return &id, nil
+}
+
+func dateQuery(c *fiber.Ctx, key string) (*time.Time, error) {
+	v := strings.TrimSpace(c.Query(key))
+	if v == "" {
+		return nil, nil
+	}
+	t, err := time.Parse("2006-01-02", v)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, key+" harus YYYY-MM-DD")
+	}
+	return &t, nil
+}
+
+/* =========================================================
+   QURAN
+========================================================= */
+
+// GET /api/u/tahfidz/quran/surahs
+func (h *TahfidzController) Surahs(c *fiber.Ctx) error {
+	return helper.JsonOK(c, "OK", quran.Surahs())
+}
+
+// GET /api/u/tahfidz/quran/juz
+func (h *TahfidzController) JuzList(c *fiber.Ctx) error {
+	return helper.JsonOK(c, "OK", quran.JuzList())
+}
+
+/* =========================================================
+   SETORAN
+========================================================= */
+
+// GET /api/u/tahfidz/records
+func (h *TahfidzController) ListRecords(c *fiber.Ctx) error {
+	schoolID, _, err := scope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	f := svc.RecordFilter{Type: strings.ToLower(strings.TrimSpace(c.Query("type")))}
+	for key, dst := range map[string]**uuid.UUID{
+		"school_student_id": &f.SchoolStudentID,
+		"teacher_id":        &f.SchoolTeacherID,
+		"session_id":        &f.SessionID,
+		"term_id":           &f.TermID,
+	} {
+		if *dst, err = uuidQuery(c, key); err != nil {
+			return writeErr(c, err)
+		}
+	}
+	if f.From, err = dateQuery(c, "from"); err != nil {
+		return writeErr(c, err)
+	}
+	if f.To, err = dateQuery(c, "to"); err != nil {
+		return writeErr(c, err)
+	}
+	if !isStaff(c, schoolID) {
+		own, err := ownStudentID(c, schoolID)
+		if err != nil {
+			return writeErr(c, err)
+		}
+		f.SchoolStudentID = &own
+	}
+
+	p := helper.ResolvePaging(c, 50, 200)
+	f.Offset, f.Limit = p.Offset, p.Limit
+	rows, total, err := svc.ListRecords(c.Context(), h.DB, schoolID, f)
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusInternalServerError, "Gagal mengambil setoran")
+	}
+	return helper.JsonList(c, "OK", rows, helper.BuildPaginationFromPage(total, p.Page, p.PerPage))
+}
+
+// GET /api/u/tahfidz/records/:id
+func (h *TahfidzController) GetRecord(c *fiber.Ctx) error {
+	schoolID, id, err := scope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	m, err := svc.GetRecord(c.Context(), h.DB, schoolID, id)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	if !isStaff(c, schoolID) {
+		own, err := ownStudentID(c, schoolID)
+		if err != nil {
+			return writeErr(c, err)
+		}
+		if own != m.TahfidzRecordSchoolStudentID {
+			return writeErr(c, svc.ErrRecordNotFound)
+		}
+	}
+	return helper.JsonOK(c, "OK", m)
+}
+
+// POST /api/u/tahfidz/records
+func (h *TahfidzController) CreateRecord(c *fiber.Ctx) error {
+	schoolID, _, err := scope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	if err := requireStaff(c, schoolID); err != nil {
+		return writeErr(c, err)
+	}
+	var req dto.RecordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "Payload tidak valid")
+	}
+	in, err := req.ToInput()
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "recorded_on harus YYYY-MM-DD")
+	}
+	m, err := svc.CreateRecord(c.Context(), h.DB, schoolID, in, actorOf(c, schoolID))
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonCreated(c, "Setoran dicatat", m)
+}
+
+// PATCH /api/u/tahfidz/records/:id
+func (h *TahfidzController) UpdateRecord(c *fiber.Ctx) error {
+	schoolID, id, err := scope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	if err := requireStaff(c, schoolID); err != nil {
+		return writeErr(c, err)
+	}
+	var req dto.RecordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "Payload tidak valid")
+	}
+	in, err := req.ToInput()
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "recorded_on harus YYYY-MM-DD")
+	}
+	m, err := svc.UpdateRecord(c.Context(), h.DB, schoolID, id, in)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonUpdated(c, "Setoran diperbarui", m)
+}
+
+// DELETE /api/u/tahfidz/records/:id
+func (h *TahfidzController) DeleteRecord(c *fiber.Ctx) error {
+	schoolID, id, err := scope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	if err := requireStaff(c, schoolID); err != nil {
+		return writeErr(c, err)
+	}
+	if err := svc.DeleteRecord(c.Context(), h.DB, schoolID, id); err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonDeleted(c, "Setoran dihapus", fiber.Map{"tahfidz_record_id": id})
+}
+
+/* =========================================================
+   PROGRESS & LEADERBOARD
+========================================================= */
+
+// GET /api/u/tahfidz/students/:id/progress
+func (h *TahfidzController) Progress(c *fiber.Ctx) error {
+	schoolID, err := permsvc.SchoolFromRequest(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	var studentID uuid.UUID
+	raw := strings.TrimSpace(c.Params("id"))
+	switch {
+	case isStaff(c, schoolID) && raw != "me":
+		if studentID, err = uuid.Parse(raw); err != nil {
+			return helper.JsonError(c, fiber.StatusBadRequest, "id tidak valid")
+		}
+	default:
+		own, err := ownStudentID(c, schoolID)
+		if err != nil {
+			return writeErr(c, err)
+		}
+		if raw != "me" && raw != own.String() {
+			return helper.JsonError(c, fiber.StatusForbidden, "Akses ditolak")
+		}
+		studentID = own
+	}
+	termID, err := uuidQuery(c, "term_id")
+	if err != nil {
+		return writeErr(c, err)
+	}
+	p, err := svc.StudentProgress(c.Context(), h.DB, schoolID, studentID, termID, dbtime.NowInSchool(c))
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonOK(c, "OK", p)
+}
+
+// GET /api/u/tahfidz/class-sections/:id/leaderboard
+func (h *TahfidzController) Leaderboard(c *fiber.Ctx) error {
+	schoolID, sectionID, err := scope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	if !isStaff(c, schoolID) && !helperAuth.IsStudentInSchool(c, schoolID) {
+		return helper.JsonError(c, fiber.StatusForbidden, "Akses ditolak")
+	}
+	termID, err := uuidQuery(c, "term_id")
+	if err != nil {
+		return writeErr(c, err)
+	}
+	rows, err := svc.Leaderboard(c.Context(), h.DB, schoolID, sectionID, termID, dbtime.NowInSchool(c))
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonOK(c, "OK", rows)
+}
+
+// GET /api/u/tahfidz/sessions/:id/pending
+func (h *TahfidzController) SessionPending(c *fiber.Ctx) error {
+	schoolID, sessionID, err := scope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	if err := requireStaff(c, schoolID); err != nil {
+		return writeErr(c, err)
+	}
+	out, err := svc.PendingForSession(c.Context(), h.DB, schoolID, sessionID)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonOK(c, "OK", out)
+}
@@ -0,0 +1,105 @@
+// file: internals/features/school/class_others/tahfidz/dto/tahfidz_dto.go
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	svc "madinahsalam_backend/internals/features/school/class_others/tahfidz/service"
+)
+
+/* =========================================================
+   SETORAN
+========================================================= */
+
+// Rentang: isi "juz" ATAU "surah_from" (+ayah_from, surah_to, ayah_to opsional).
+type RecordRequest struct {
+	SchoolStudentID *uuid.UUID `json:"school_student_id"`
+	SchoolTeacherID *uuid.UUID `json:"school_teacher_id"`
+	SessionID       *uuid.UUID `json:"session_id"`
+	Type            *string    `json:"type"` // ziyadah (default) | murojaah
+
+	Juz       *int `json:"juz"`
+	SurahFrom *int `json:"surah_from"`
+	AyahFrom  *int `json:"ayah_from"`
+	SurahTo   *int `json:"surah_to"`
+	AyahTo    *int `json:"ayah_to"`
+
+	ScoreTajwid  *float64 `json:"score_tajwid"`
+	ScoreFluency *float64 `json:"score_fluency"`
+	ScoreMakhraj *float64 `json:"score_makhraj"`
+	IsPassed     *bool    `json:"is_passed"`
+
+	RecordedOn *string `json:"recorded_on"` // YYYY-MM-DD
+	Note       *string `json:"note"`
+}
+
+func (r RecordRequest) ToInput() (svc.RecordInput, error) {
+	in := svc.RecordInput{
+		SchoolStudentID: r.SchoolStudentID,
+		SchoolTeacherID: r.SchoolTeacherID,
+		SessionID:       r.SessionID,
+		Type:            r.Type,
+		Range: svc.RangeInput{
+			Juz: r.Juz, SurahFrom: r.SurahFrom, AyahFrom: r.AyahFrom, SurahTo: r.SurahTo, AyahTo: r.AyahTo,
+		},
+		ScoreTajwid:  r.ScoreTajwid,
+		ScoreFluency: r.ScoreFluency,
+		ScoreMakhraj: r.ScoreMakhraj,
+		IsPassed:     r.IsPassed,
+		Note:         r.Note,
+	}
+	if r.RecordedOn != nil && *r.RecordedOn != "" {
+		t, err := time.Parse("2006-01-02", *r.RecordedOn)
+		if err != nil {
+			return in, err
+		}
+		in.RecordedOn = &t
+	}
+	return in, nil
+}
+
+/* =========================================================
+   TARGET
+========================================================= */
+
+type CreateTargetRequest struct {
+	TermID          uuid.UUID  `json:"term_id"`
+	ClassSectionID  *uuid.UUID `json:"class_section_id"`
+	SchoolStudentID *uuid.UUID `json:"school_student_id"`
+	Pages           *float64   `json:"pages"`
+	JuzTotal        *float64   `json:"juz_total"`
+	Note            *string    `json:"note"`
+}
+
+func (r CreateTargetRequest) ToInput() svc.TargetInput {
+	return svc.TargetInput{
+		TermID:          &r.TermID,
+		ClassSectionID:  r.ClassSectionID,
+		SchoolStudentID: r.SchoolStudentID,
+		Pages:           r.Pages,
+		JuzTotal:        r.JuzTotal,
+		Note:            r.Note,
+	}
+}
+
+// PATCH: field nil = tidak diubah.
+type UpdateTargetRequest struct {
+	Pages    *float64 `json:"pages"`
+	JuzTotal *float64 `json:"juz_total"`
+	Note     *string  `json:"note"`
+}
+
+func (r UpdateTargetRequest) ToInput() svc.TargetInput {
+	return svc.TargetInput{Pages: r.Pages, JuzTotal: r.JuzTotal, Note: r.Note}
+}
+
+/* =========================================================
+   RAPOR
+========================================================= */
+
+type SyncRaporRequest struct {
+	TermID         uuid.UUID `json:"term_id"`
+	ClassSubjectID uuid.UUID `json:"class_subject_id"`
+}
@@ -0,0 +1,82 @@
+// file: internals/features/school/class_others/tahfidz/model/tahfidz_model.go
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	RecordTypeZiyadah  = "ziyadah"  // hafalan baru
+	RecordTypeMurojaah = "murojaah" // mengulang hafalan lama
+)
+
+const (
+	RecordScopeSurah = "surah"
+	RecordScopeJuz   = "juz"
+)
+
+/* =========================================================
+   tahfidz_records — 1 setoran
+========================================================= */
+
+type TahfidzRecordModel struct {
+	TahfidzRecordID              uuid.UUID  `gorm:"column:tahfidz_record_id;type:uuid;default:gen_random_uuid();primaryKey" json:"tahfidz_record_id"`
+	TahfidzRecordSchoolID        uuid.UUID  `gorm:"column:tahfidz_record_school_id;type:uuid;not null" json:"tahfidz_record_school_id"`
+	TahfidzRecordSchoolStudentID uuid.UUID  `gorm:"column:tahfidz_record_school_student_id;type:uuid;not null" json:"tahfidz_record_school_student_id"`
+	TahfidzRecordSchoolTeacherID *uuid.UUID `gorm:"column:tahfidz_record_school_teacher_id;type:uuid" json:"tahfidz_record_school_teacher_id,omitempty"`
+	TahfidzRecordSessionID       *uuid.UUID `gorm:"column:tahfidz_record_session_id;type:uuid" json:"tahfidz_record_session_id,omitempty"`
+	TahfidzRecordParticipantID   *uuid.UUID `gorm:"column:tahfidz_record_participant_id;type:uuid" json:"tahfidz_record_participant_id,omitempty"`
+	TahfidzRecordTermID          *uuid.UUID `gorm:"column:tahfidz_record_term_id;type:uuid" json:"tahfidz_record_term_id,omitempty"`
+
+	TahfidzRecordType  string `gorm:"column:tahfidz_record_type;type:varchar(10);not null" json:"tahfidz_record_type"`
+	TahfidzRecordScope string `gorm:"column:tahfidz_record_scope;type:varchar(8);not null;default:'surah'" json:"tahfidz_record_scope"`
+	TahfidzRecordJuz   *int16 `gorm:"column:tahfidz_record_juz" json:"tahfidz_record_juz,omitempty"`
+
+	TahfidzRecordSurahFrom    int16    `gorm:"column:tahfidz_record_surah_from;not null" json:"tahfidz_record_surah_from"`
+	TahfidzRecordAyahFrom     int16    `gorm:"column:tahfidz_record_ayah_from;not null" json:"tahfidz_record_ayah_from"`
+	TahfidzRecordSurahTo      int16    `gorm:"column:tahfidz_record_surah_to;not null" json:"tahfidz_record_surah_to"`
+	TahfidzRecordAyahTo       int16    `gorm:"column:tahfidz_record_ayah_to;not null" json:"tahfidz_record_ayah_to"`
+	TahfidzRecordIndexFrom    int16    `gorm:"column:tahfidz_record_index_from;not null" json:"-"`
+	TahfidzRecordIndexTo      int16    `gorm:"column:tahfidz_record_index_to;not null" json:"-"`
+	TahfidzRecordAyahCount    int      `gorm:"column:tahfidz_record_ayah_count;not null" json:"tahfidz_record_ayah_count"`
+	TahfidzRecordPages        float64  `gorm:"column:tahfidz_record_pages;type:numeric(6,2);not null" json:"tahfidz_record_pages"`
+	TahfidzRecordScoreTajwid  *float64 `gorm:"column:tahfidz_record_score_tajwid;type:numeric(5,2)" json:"tahfidz_record_score_tajwid,omitempty"`
+	TahfidzRecordScoreFluency *float64 `gorm:"column:tahfidz_record_score_fluency;type:numeric(5,2)" json:"tahfidz_record_score_fluency,omitempty"`
+	TahfidzRecordScoreMakhraj *float64 `gorm:"column:tahfidz_record_score_makhraj;type:numeric(5,2)" json:"tahfidz_record_score_makhraj,omitempty"`
+	TahfidzRecordScoreAvg     *float64 `gorm:"column:tahfidz_record_score_avg;type:numeric(5,2)" json:"tahfidz_record_score_avg,omitempty"`
+	TahfidzRecordIsPassed     bool     `gorm:"column:tahfidz_record_is_passed;not null;default:true" json:"tahfidz_record_is_passed"`
+
+	TahfidzRecordRecordedOn time.Time  `gorm:"column:tahfidz_record_recorded_on;type:date;not null" json:"tahfidz_record_recorded_on"`
+	TahfidzRecordNote       *string    `gorm:"column:tahfidz_record_note;type:text" json:"tahfidz_record_note,omitempty"`
+	TahfidzRecordCreatedBy  *uuid.UUID `gorm:"column:tahfidz_record_created_by;type:uuid" json:"tahfidz_record_created_by,omitempty"`
+
+	TahfidzRecordCreatedAt time.Time      `gorm:"column:tahfidz_record_created_at;autoCreateTime" json:"tahfidz_record_created_at"`
+	TahfidzRecordUpdatedAt time.Time      `gorm:"column:tahfidz_record_updated_at;autoUpdateTime" json:"tahfidz_record_updated_at"`
+	TahfidzRecordDeletedAt gorm.DeletedAt `gorm:"column:tahfidz_record_deleted_at;index" json:"-"`
+}
+
+func (TahfidzRecordModel) TableName() string { return "tahfidz_records" }
+
+/* =========================================================
+   tahfidz_targets — target per semester
+========================================================= */
+
+type TahfidzTargetModel struct {
+	TahfidzTargetID              uuid.UUID  `gorm:"column:tahfidz_target_id;type:uuid;default:gen_random_uuid();primaryKey" json:"tahfidz_target_id"`
+	TahfidzTargetSchoolID        uuid.UUID  `gorm:"column:tahfidz_target_school_id;type:uuid;not null" json:"tahfidz_target_school_id"`
+	TahfidzTargetTermID          uuid.UUID  `gorm:"column:tahfidz_target_term_id;type:uuid;not null" json:"tahfidz_target_term_id"`
+	TahfidzTargetClassSectionID  *uuid.UUID `gorm:"column:tahfidz_target_class_section_id;type:uuid" json:"tahfidz_target_class_section_id,omitempty"`
+	TahfidzTargetSchoolStudentID *uuid.UUID `gorm:"column:tahfidz_target_school_student_id;type:uuid" json:"tahfidz_target_school_student_id,omitempty"`
+	TahfidzTargetPages           float64    `gorm:"column:tahfidz_target_pages;type:numeric(6,2);not null" json:"tahfidz_target_pages"`
+	TahfidzTargetJuzTotal        *float64   `gorm:"column:tahfidz_target_juz_total;type:numeric(4,1)" json:"tahfidz_target_juz_total,omitempty"`
+	TahfidzTargetNote            *string    `gorm:"column:tahfidz_target_note;type:text" json:"tahfidz_target_note,omitempty"`
+
+	TahfidzTargetCreatedAt time.Time      `gorm:"column:tahfidz_target_created_at;autoCreateTime" json:"tahfidz_target_created_at"`
+	TahfidzTargetUpdatedAt time.Time      `gorm:"column:tahfidz_target_updated_at;autoUpdateTime" json:"tahfidz_target_updated_at"`
+	TahfidzTargetDeletedAt gorm.DeletedAt `gorm:"column:tahfidz_target_deleted_at;index" json:"-"`
+}
+
+func (TahfidzTargetModel) TableName() string { return "tahfidz_targets" }
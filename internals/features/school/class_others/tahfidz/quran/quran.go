@@ -0,0 +1,182 @@
+// file: internals/features/school/class_others/tahfidz/quran/quran.go
+package quran
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+)
+
+/* =========================================================
+   Struktur mushaf (offline, di-embed ke binary)
+   - 114 surah: jumlah ayat + halaman awal mushaf Madinah (604 hlm)
+   - 30 juz: posisi awal (surah:ayat)
+   - indeks global ayat 0..6235 → dipakai untuk coverage hafalan
+
+   Halaman per ayat = estimasi: interpolasi linear di antara titik
+   yang pasti (awal surah & awal juz; juz ≥2 mulai di hlm 20j-18),
+   total tepat 604.
+========================================================= */
+
+const (
+	SurahCount = 114
+	JuzCount   = 30
+	TotalAyahs = 6236
+	TotalPages = 604
+)
+
+type Surah struct {
+	Number    int    `json:"number"`
+	Name      string `json:"name"`
+	Ayahs     int    `json:"ayahs"`
+	PageStart int    `json:"page_start"`
+}
+
+// Pos: posisi ayat (surah 1..114, ayat 1..n).
+type Pos struct {
+	Surah int `json:"surah"`
+	Ayah  int `json:"ayah"`
+}
+
+func (p Pos) String() string { return fmt.Sprintf("%d:%d", p.Surah, p.Ayah) }
+
+type Juz struct {
+	Number int     `json:"number"`
+	Start  Pos     `json:"start"`
+	End    Pos     `json:"end"`
+	Ayahs  int     `json:"ayahs"`
+	Pages  float64 `json:"pages"`
+}
+
+//go:embed surahs.json
+var surahsJSON []byte
+
+// awal tiap juz (standar mushaf Madinah)
+var juzStarts = [JuzCount]Pos{
+	{1, 1}, {2, 142}, {2, 253}, {3, 93}, {4, 24}, {4, 148}, {5, 82}, {6, 111}, {7, 88}, {8, 41},
+	{9, 93}, {11, 6}, {12, 53}, {15, 1}, {17, 1}, {18, 75}, {21, 1}, {23, 1}, {25, 21}, {27, 56},
+	{29, 46}, {33, 31}, {36, 28}, {39, 32}, {41, 47}, {46, 1}, {51, 31}, {58, 1}, {67, 1}, {78, 1},
+}
+
+var (
+	surahs  []Surah
+	offsets [SurahCount + 1]int     // offsets[s-1] = indeks global ayat pertama surah s
+	juzIdx  [JuzCount + 1]int       // juzIdx[j-1] = indeks global awal juz j; juzIdx[30] = TotalAyahs
+	pagePos [TotalAyahs + 1]float64 // posisi halaman (0-based, pecahan) di awal tiap ayat
+	juzList []Juz
+)
+
+func init() {
+	if err := json.Unmarshal(surahsJSON, &surahs); err != nil {
+		panic("quran: surahs.json rusak: " + err.Error())
+	}
+	if len(surahs) != SurahCount {
+		panic("quran: jumlah surah harus 114")
+	}
+	for i, s := range surahs {
+		offsets[i+1] = offsets[i] + s.Ayahs
+	}
+	if offsets[SurahCount] != TotalAyahs {
+		panic("quran: total ayat harus 6236")
+	}
+	for j, p := range juzStarts {
+		juzIdx[j] = offsets[p.Surah-1] + p.Ayah - 1
+	}
+	juzIdx[JuzCount] = TotalAyahs
+	buildPagePos()
+	for j := 0; j < JuzCount; j++ {
+		from, to := juzIdx[j], juzIdx[j+1]-1
+		juzList = append(juzList, Juz{
+			Number: j + 1,
+			Start:  PosAt(from),
+			End:    PosAt(to),
+			Ayahs:  to - from + 1,
+			Pages:  Pages(from, to),
+		})
+	}
+}
+
+func Surahs() []Surah { return surahs }
+func JuzList() []Juz  { return juzList }
+
+func SurahByNumber(n int) (Surah, bool) {
+	if n < 1 || n > SurahCount {
+		return Surah{}, false
+	}
+	return surahs[n-1], true
+}
+
+func Valid(p Pos) bool {
+	s, ok := SurahByNumber(p.Surah)
+	return ok && p.Ayah >= 1 && p.Ayah <= s.Ayahs
+}
+
+// Index: posisi → indeks global 0..6235 (p harus Valid).
+func Index(p Pos) int { return offsets[p.Surah-1] + p.Ayah - 1 }
+
+// PosAt: indeks global → posisi.
+func PosAt(idx int) Pos {
+	lo, hi := 0, SurahCount-1
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if offsets[mid] <= idx {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	return Pos{Surah: lo + 1, Ayah: idx - offsets[lo] + 1}
+}
+
+// JuzRange: indeks global awal & akhir juz j (inklusif).
+func JuzRange(j int) (from, to int, ok bool) {
+	if j < 1 || j > JuzCount {
+		return 0, 0, false
+	}
+	return juzIdx[j-1], juzIdx[j] - 1, true
+}
+
+// JuzOf: juz tempat indeks global berada.
+func JuzOf(idx int) int {
+	for j := JuzCount; j >= 1; j-- {
+		if idx >= juzIdx[j-1] {
+			return j
+		}
+	}
+	return 1
+}
+
+// buildPagePos: titik pasti (indeks → halaman awal) lalu interpolasi linear di antaranya.
+func buildPagePos() {
+	anchor := map[int]int{TotalAyahs: TotalPages}
+	for i, s := range surahs {
+		anchor[offsets[i]] = s.PageStart - 1
+	}
+	for j := 2; j <= JuzCount; j++ {
+		anchor[juzIdx[j-1]] = 20*j - 19
+	}
+	prev := 0
+	for idx := 1; idx <= TotalAyahs; idx++ {
+		page, ok := anchor[idx]
+		if !ok {
+			continue
+		}
+		lo := float64(anchor[prev])
+		step := (float64(page) - lo) / float64(idx-prev)
+		for k := prev; k <= idx; k++ {
+			pagePos[k] = lo + step*float64(k-prev)
+		}
+		prev = idx
+	}
+}
+
+// AyahPages: estimasi halaman untuk 1 ayat (indeks global).
+func AyahPages(idx int) float64 { return pagePos[idx+1] - pagePos[idx] }
+
+// Pages: estimasi jumlah halaman rentang indeks [from, to].
+func Pages(from, to int) float64 {
+	if from > to {
+		return 0
+	}
+	return pagePos[to+1] - pagePos[from]
+}
@@ -0,0 +1,49 @@
+// file: internals/features/school/class_others/tahfidz/route/tahfidz_route.go
+package route
+
+import (
+	tahfidzController "madinahsalam_backend/internals/features/school/class_others/tahfidz/controller"
+	schoolkuMiddleware "madinahsalam_backend/internals/middlewares/features"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// /api/u/tahfidz → setoran (guru/DKM), progress & leaderboard (guru/siswa)
+func TahfidzUserRoutes(api fiber.Router, db *gorm.DB) {
+	ctl := tahfidzController.NewTahfidzController(db)
+
+	g := api.Group("/tahfidz")
+
+	g.Get("/quran/surahs", ctl.Surahs)
+	g.Get("/quran/juz", ctl.JuzList)
+
+	g.Get("/records", ctl.ListRecords)
+	g.Post("/records", ctl.CreateRecord)
+	g.Get("/records/:id", ctl.GetRecord)
+	g.Patch("/records/:id", ctl.UpdateRecord)
+	g.Delete("/records/:id", ctl.DeleteRecord)
+
+	g.Get("/students/:id/progress", ctl.Progress)
+	g.Get("/class-sections/:id/leaderboard", ctl.Leaderboard)
+	g.Get("/sessions/:id/pending", ctl.SessionPending)
+}
+
+// /api/a/tahfidz → target semester & rapor
+func TahfidzAdminRoutes(api fiber.Router, db *gorm.DB) {
+	ctl := tahfidzController.NewTahfidzController(db)
+
+	read := schoolkuMiddleware.RequirePermission("tahfidz.read")
+	write := schoolkuMiddleware.RequirePermission("tahfidz.write")
+	del := schoolkuMiddleware.RequirePermission("tahfidz.delete")
+
+	g := api.Group("/tahfidz")
+
+	g.Get("/targets", read, ctl.ListTargets)
+	g.Post("/targets", write, ctl.CreateTarget)
+	g.Patch("/targets/:id", write, ctl.UpdateTarget)
+	g.Delete("/targets/:id", del, ctl.DeleteTarget)
+
+	g.Get("/students/:id/rapor", read, ctl.StudentRapor)
+	g.Post("/rapor/sync", write, ctl.SyncRapor)
+}
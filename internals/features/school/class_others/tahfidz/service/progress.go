@@ -0,0 +1,444 @@
+// file: internals/features/school/class_others/tahfidz/service/progress.go
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	model "madinahsalam_backend/internals/features/school/class_others/tahfidz/model"
+	"madinahsalam_backend/internals/features/school/class_others/tahfidz/quran"
+)
+
+/* =========================================================
+   Coverage hafalan
+   - dihitung dari setoran ziyadah yang LULUS (bitmap 6236 ayat),
+     jadi setoran yang tumpang tindih / diulang tidak dihitung dua kali
+   - murojaah tidak menambah hafalan, hanya dihitung per semester
+========================================================= */
+
+type coverage [quran.TotalAyahs]bool
+
+func (cv *coverage) mark(from, to int) {
+	for i := from; i <= to && i < quran.TotalAyahs; i++ {
+		cv[i] = true
+	}
+}
+
+func (cv *coverage) stats() (ayahs int, pages float64) {
+	for i, ok := range cv {
+		if ok {
+			ayahs++
+			pages += quran.AyahPages(i)
+		}
+	}
+	return ayahs, round2(pages)
+}
+
+type JuzProgress struct {
+	Juz       int     `json:"juz"`
+	Ayahs     int     `json:"ayahs"`
+	Memorized int     `json:"memorized"`
+	Percent   float64 `json:"percent"`
+}
+
+type Coverage struct {
+	Ayahs        int           `json:"ayahs"`
+	Pages        float64       `json:"pages"`
+	JuzCompleted int           `json:"juz_completed"`
+	JuzEquiv     float64       `json:"juz_equivalent"` // pages / 20
+	Juz          []JuzProgress `json:"juz"`
+}
+
+func (cv *coverage) summary() Coverage {
+	out := Coverage{Juz: make([]JuzProgress, 0, quran.JuzCount)}
+	out.Ayahs, out.Pages = cv.stats()
+	out.JuzEquiv = round2(out.Pages / 20)
+	for _, j := range quran.JuzList() {
+		from, to, _ := quran.JuzRange(j.Number)
+		n := 0
+		for i := from; i <= to; i++ {
+			if cv[i] {
+				n++
+			}
+		}
+		if n == j.Ayahs {
+			out.JuzCompleted++
+		}
+		out.Juz = append(out.Juz, JuzProgress{
+			Juz: j.Number, Ayahs: j.Ayahs, Memorized: n,
+			Percent: round2(float64(n) * 100 / float64(j.Ayahs)),
+		})
+	}
+	return out
+}
+
+func countsAsMemorized(r *model.TahfidzRecordModel) bool {
+	return r.TahfidzRecordType == model.RecordTypeZiyadah && r.TahfidzRecordIsPassed
+}
+
+func studentRecords(ctx context.Context, db *gorm.DB, schoolID, studentID uuid.UUID) ([]model.TahfidzRecordModel, error) {
+	var rows []model.TahfidzRecordModel
+	err := db.WithContext(ctx).
+		Where("tahfidz_record_school_id = ? AND tahfidz_record_school_student_id = ?", schoolID, studentID).
+		Order("tahfidz_record_recorded_on ASC, tahfidz_record_created_at ASC").
+		Find(&rows).Error
+	return rows, err
+}
+
+/* =========================================================
+   Semester
+========================================================= */
+
+type termInfo struct {
+	ID        uuid.UUID `gorm:"column:academic_term_id"`
+	Name      string    `gorm:"column:academic_term_name"`
+	Year      string    `gorm:"column:academic_term_academic_year"`
+	StartDate time.Time `gorm:"column:academic_term_start_date"`
+	EndDate   time.Time `gorm:"column:academic_term_end_date"`
+}
+
+func loadTerms(ctx context.Context, db *gorm.DB, schoolID uuid.UUID, ids []uuid.UUID) (map[uuid.UUID]termInfo, error) {
+	out := map[uuid.UUID]termInfo{}
+	if len(ids) == 0 {
+		return out, nil
+	}
+	var rows []termInfo
+	if err := db.WithContext(ctx).Table("academic_terms").
+		Select("academic_term_id, academic_term_name, academic_term_academic_year, academic_term_start_date, academic_term_end_date").
+		Where("academic_term_school_id = ? AND academic_term_id IN ?", schoolID, ids).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, t := range rows {
+		out[t.ID] = t
+	}
+	return out, nil
+}
+
+type ScoreAverages struct {
+	Tajwid  *float64 `json:"tajwid,omitempty"`
+	Fluency *float64 `json:"fluency,omitempty"`
+	Makhraj *float64 `json:"makhraj,omitempty"`
+	Overall *float64 `json:"overall,omitempty"`
+}
+
+type avgAcc struct {
+	sum float64
+	n   int
+}
+
+func (a *avgAcc) add(p *float64) {
+	if p != nil {
+		a.sum += *p
+		a.n++
+	}
+}
+
+func (a avgAcc) value() *float64 {
+	if a.n == 0 {
+		return nil
+	}
+	v := round2(a.sum / float64(a.n))
+	return &v
+}
+
+type TermProgress struct {
+	TermID        uuid.UUID      `json:"term_id"`
+	TermName      string         `json:"term_name,omitempty"`
+	AcademicYear  string         `json:"academic_year,omitempty"`
+	Records       int            `json:"records"`
+	NewAyahs      int            `json:"new_ayahs"`
+	NewPages      float64        `json:"new_pages"`
+	MurojaahPages float64        `json:"murojaah_pages"`
+	Failed        int            `json:"failed"`
+	Scores        ScoreAverages  `json:"scores"`
+	TotalPages    float64        `json:"total_pages"` // kumulatif s.d. akhir semester
+	Target        *TargetSummary `json:"target,omitempty"`
+	Achievement   *float64       `json:"achievement_percent,omitempty"`
+}
+
+type TargetSummary struct {
+	Source   string   `json:"source"` // student|class_section|school
+	Pages    float64  `json:"pages"`
+	JuzTotal *float64 `json:"juz_total,omitempty"`
+}
+
+// termProgress: hafalan baru = coverage (sebelum + dalam semester) − coverage sebelum semester.
+func termProgress(rows []model.TahfidzRecordModel, t termInfo) TermProgress {
+	var before, upto coverage
+	var tj, fl, mk, all avgAcc
+	tp := TermProgress{TermID: t.ID, TermName: t.Name, AcademicYear: t.Year}
+	start := dateOnly(t.StartDate)
+	end := dateOnly(t.EndDate)
+	inTerm := func(r *model.TahfidzRecordModel) bool {
+		if r.TahfidzRecordTermID != nil {
+			return *r.TahfidzRecordTermID == t.ID
+		}
+		d := dateOnly(r.TahfidzRecordRecordedOn)
+		return !d.Before(start) && !d.After(end)
+	}
+	for i := range rows {
+		r := &rows[i]
+		switch {
+		case inTerm(r):
+			tp.Records++
+			if !r.TahfidzRecordIsPassed {
+				tp.Failed++
+			}
+			if r.TahfidzRecordType == model.RecordTypeMurojaah {
+				tp.MurojaahPages += r.TahfidzRecordPages
+			}
+			tj.add(r.TahfidzRecordScoreTajwid)
+			fl.add(r.TahfidzRecordScoreFluency)
+			mk.add(r.TahfidzRecordScoreMakhraj)
+			all.add(r.TahfidzRecordScoreAvg)
+			if countsAsMemorized(r) {
+				upto.mark(int(r.TahfidzRecordIndexFrom), int(r.TahfidzRecordIndexTo))
+			}
+		case dateOnly(r.TahfidzRecordRecordedOn).Before(start) && countsAsMemorized(r):
+			before.mark(int(r.TahfidzRecordIndexFrom), int(r.TahfidzRecordIndexTo))
+			upto.mark(int(r.TahfidzRecordIndexFrom), int(r.TahfidzRecordIndexTo))
+		}
+	}
+	ba, bp := before.stats()
+	ua, up := upto.stats()
+	tp.NewAyahs = ua - ba
+	tp.NewPages = round2(up - bp)
+	tp.TotalPages = up
+	tp.MurojaahPages = round2(tp.MurojaahPages)
+	tp.Scores = ScoreAverages{Tajwid: tj.value(), Fluency: fl.value(), Makhraj: mk.value(), Overall: all.value()}
+	return tp
+}
+
+func withTarget(tp *TermProgress, t *TargetSummary) {
+	tp.Target = t
+	if t == nil || t.Pages <= 0 {
+		return
+	}
+	pct := round2(tp.NewPages * 100 / t.Pages)
+	tp.Achievement = &pct
+}
+
+/* =========================================================
+   Streak: hari berturut-turut dengan setoran (jenis apa pun)
+========================================================= */
+
+type Streak struct {
+	Current        int        `json:"current"`
+	Longest        int        `json:"longest"`
+	LastRecordedOn *time.Time `json:"last_recorded_on,omitempty"`
+}
+
+// computeStreak: rows urut naik; streak berjalan bila setoran terakhir hari ini/kemarin.
+func computeStreak(rows []model.TahfidzRecordModel, today time.Time) Streak {
+	var s Streak
+	var prev time.Time
+	run := 0
+	for i := range rows {
+		d := dateOnly(rows[i].TahfidzRecordRecordedOn)
+		switch {
+		case run > 0 && d.Equal(prev):
+			continue
+		case run > 0 && d.Equal(prev.AddDate(0, 0, 1)):
+			run++
+		default:
+			run = 1
+		}
+		prev = d
+		if run > s.Longest {
+			s.Longest = run
+		}
+	}
+	if run == 0 {
+		return s
+	}
+	last := prev
+	s.LastRecordedOn = &last
+	if t := dateOnly(today); !last.Before(t.AddDate(0, 0, -1)) {
+		s.Current = run
+	}
+	return s
+}
+
+/* =========================================================
+   Progress siswa
+========================================================= */
+
+type Progress struct {
+	SchoolStudentID uuid.UUID                 `json:"school_student_id"`
+	Coverage        Coverage                  `json:"coverage"`
+	Term            *TermProgress             `json:"term,omitempty"`
+	Streak          Streak                    `json:"streak"`
+	Last            *model.TahfidzRecordModel `json:"last_record,omitempty"`
+}
+
+// StudentProgress: termID nil → semester yang mencakup hari ini.
+func StudentProgress(ctx context.Context, db *gorm.DB, schoolID, studentID uuid.UUID, termID *uuid.UUID, today time.Time) (*Progress, error) {
+	if ok, err := studentInSchool(ctx, db, schoolID, studentID); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, ErrStudentNotInSchool
+	}
+	rows, err := studentRecords(ctx, db, schoolID, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	var cv coverage
+	for i := range rows {
+		if countsAsMemorized(&rows[i]) {
+			cv.mark(int(rows[i].TahfidzRecordIndexFrom), int(rows[i].TahfidzRecordIndexTo))
+		}
+	}
+	p := &Progress{
+		SchoolStudentID: studentID,
+		Coverage:        cv.summary(),
+		Streak:          computeStreak(rows, today),
+	}
+	if n := len(rows); n > 0 {
+		p.Last = &rows[n-1]
+	}
+
+	if termID == nil {
+		if termID, err = TermForDate(ctx, db, schoolID, today); err != nil {
+			return nil, err
+		}
+	}
+	if termID != nil {
+		terms, err := loadTerms(ctx, db, schoolID, []uuid.UUID{*termID})
+		if err != nil {
+			return nil, err
+		}
+		t, ok := terms[*termID]
+		if !ok {
+			return nil, ErrTermNotFound
+		}
+		tp := termProgress(rows, t)
+		target, err := ResolveTarget(ctx, db, schoolID, *termID, studentID)
+		if err != nil {
+			return nil, err
+		}
+		withTarget(&tp, target)
+		p.Term = &tp
+	}
+	return p, nil
+}
+
+/* =========================================================
+   Leaderboard rombel
+========================================================= */
+
+type LeaderboardRow struct {
+	Rank            int       `json:"rank"`
+	SchoolStudentID uuid.UUID `json:"school_student_id"`
+	Name            *string   `json:"name,omitempty"`
+	TotalPages      float64   `json:"total_pages"`
+	TotalAyahs      int       `json:"total_ayahs"`
+	JuzCompleted    int       `json:"juz_completed"`
+	TermPages       float64   `json:"term_pages"`
+	Achievement     *float64  `json:"achievement_percent,omitempty"`
+	CurrentStreak   int       `json:"current_streak"`
+}
+
+type sectionMember struct {
+	StudentID uuid.UUID `gorm:"column:student_class_section_school_student_id"`
+	Name      *string   `gorm:"column:student_class_section_user_profile_name_cache"`
+}
+
+// Leaderboard: siswa aktif di rombel, urut total halaman lalu halaman semester ini.
+func Leaderboard(ctx context.Context, db *gorm.DB, schoolID, sectionID uuid.UUID, termID *uuid.UUID, today time.Time) ([]LeaderboardRow, error) {
+	var members []sectionMember
+	if err := db.WithContext(ctx).Table("student_class_sections").
+		Select("student_class_section_school_student_id, student_class_section_user_profile_name_cache").
+		Where("student_class_section_school_id = ? AND student_class_section_section_id = ?", schoolID, sectionID).
+		Where("student_class_section_status = 'active' AND student_class_section_deleted_at IS NULL").
+		Scan(&members).Error; err != nil {
+		return nil, err
+	}
+	out := make([]LeaderboardRow, 0, len(members))
+	if len(members) == 0 {
+		return out, nil
+	}
+	ids := make([]uuid.UUID, len(members))
+	for i, m := range members {
+		ids[i] = m.StudentID
+	}
+
+	var rows []model.TahfidzRecordModel
+	if err := db.WithContext(ctx).
+		Where("tahfidz_record_school_id = ? AND tahfidz_record_school_student_id IN ?", schoolID, ids).
+		Order("tahfidz_record_recorded_on ASC, tahfidz_record_created_at ASC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	byStudent := map[uuid.UUID][]model.TahfidzRecordModel{}
+	for _, r := range rows {
+		byStudent[r.TahfidzRecordSchoolStudentID] = append(byStudent[r.TahfidzRecordSchoolStudentID], r)
+	}
+
+	if termID == nil {
+		var err error
+		if termID, err = TermForDate(ctx, db, schoolID, today); err != nil {
+			return nil, err
+		}
+	}
+	var term *termInfo
+	var targets *targetSet
+	if termID != nil {
+		terms, err := loadTerms(ctx, db, schoolID, []uuid.UUID{*termID})
+		if err != nil {
+			return nil, err
+		}
+		t, ok := terms[*termID]
+		if !ok {
+			return nil, ErrTermNotFound
+		}
+		term = &t
+		if targets, err = loadTargetSet(ctx, db, schoolID, t.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, m := range members {
+		recs := byStudent[m.StudentID]
+		var cv coverage
+		for i := range recs {
+			if countsAsMemorized(&recs[i]) {
+				cv.mark(int(recs[i].TahfidzRecordIndexFrom), int(recs[i].TahfidzRecordIndexTo))
+			}
+		}
+		c := cv.summary()
+		row := LeaderboardRow{
+			SchoolStudentID: m.StudentID,
+			Name:            m.Name,
+			TotalPages:      c.Pages,
+			TotalAyahs:      c.Ayahs,
+			JuzCompleted:    c.JuzCompleted,
+			CurrentStreak:   computeStreak(recs, today).Current,
+		}
+		if term != nil {
+			tp := termProgress(recs, *term)
+			withTarget(&tp, targets.resolve(m.StudentID, &sectionID))
+			row.TermPages, row.Achievement = tp.NewPages, tp.Achievement
+		}
+		out = append(out, row)
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].TotalPages != out[j].TotalPages {
+			return out[i].TotalPages > out[j].TotalPages
+		}
+		return out[i].TermPages > out[j].TermPages
+	})
+	for i := range out {
+		out[i].Rank = i + 1
+		if i > 0 && out[i].TotalPages == out[i-1].TotalPages && out[i].TermPages == out[i-1].TermPages {
+			out[i].Rank = out[i-1].Rank // nilai sama → peringkat sama
+		}
+	}
+	return out, nil
+}
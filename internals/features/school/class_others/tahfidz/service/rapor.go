@@ -0,0 +1,192 @@
+// file: internals/features/school/class_others/tahfidz/service/rapor.go
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+/* =========================================================
+   Rapor tahfidz
+   - per siswa: ringkasan per semester (hafalan baru, murojaah, nilai, target)
+   - sync: tulis ringkasan ke user_subject_summary_breakdown["tahfidz"]
+     untuk mapel tahfidz di semester tsb (rapor membaca breakdown apa adanya)
+========================================================= */
+
+type Rapor struct {
+	SchoolStudentID uuid.UUID      `json:"school_student_id"`
+	Coverage        Coverage       `json:"coverage"`
+	Terms           []TermProgress `json:"terms"`
+}
+
+func StudentRapor(ctx context.Context, db *gorm.DB, schoolID, studentID uuid.UUID) (*Rapor, error) {
+	if ok, err := studentInSchool(ctx, db, schoolID, studentID); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, ErrStudentNotInSchool
+	}
+	rows, err := studentRecords(ctx, db, schoolID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	var cv coverage
+	seen := map[uuid.UUID]bool{}
+	var termIDs []uuid.UUID
+	for i := range rows {
+		r := &rows[i]
+		if countsAsMemorized(r) {
+			cv.mark(int(r.TahfidzRecordIndexFrom), int(r.TahfidzRecordIndexTo))
+		}
+		if r.TahfidzRecordTermID != nil && !seen[*r.TahfidzRecordTermID] {
+			seen[*r.TahfidzRecordTermID] = true
+			termIDs = append(termIDs, *r.TahfidzRecordTermID)
+		}
+	}
+	terms, err := loadTerms(ctx, db, schoolID, termIDs)
+	if err != nil {
+		return nil, err
+	}
+	sections, err := activeSections(ctx, db, schoolID, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &Rapor{SchoolStudentID: studentID, Coverage: cv.summary(), Terms: make([]TermProgress, 0, len(terms))}
+	for _, id := range termIDs {
+		t, ok := terms[id]
+		if !ok {
+			continue
+		}
+		tp := termProgress(rows, t)
+		ts, err := loadTargetSet(ctx, db, schoolID, id)
+		if err != nil {
+			return nil, err
+		}
+		withTarget(&tp, ts.resolve(studentID, sections...))
+		out.Terms = append(out.Terms, tp)
+	}
+	sort.SliceStable(out.Terms, func(i, j int) bool {
+		return terms[out.Terms[i].TermID].StartDate.Before(terms[out.Terms[j].TermID].StartDate)
+	})
+	return out, nil
+}
+
+type SyncResult struct {
+	TermID         uuid.UUID `json:"term_id"`
+	ClassSubjectID uuid.UUID `json:"class_subject_id"`
+	Updated        int       `json:"updated"`
+}
+
+// SyncRapor: hanya baris user_subject_summary yang sudah ada (tidak membuat baris baru).
+func SyncRapor(ctx context.Context, db *gorm.DB, schoolID, termID, classSubjectID uuid.UUID) (*SyncResult, error) {
+	terms, err := loadTerms(ctx, db, schoolID, []uuid.UUID{termID})
+	if err != nil {
+		return nil, err
+	}
+	t, ok := terms[termID]
+	if !ok {
+		return nil, ErrTermNotFound
+	}
+	ts, err := loadTargetSet(ctx, db, schoolID, termID)
+	if err != nil {
+		return nil, err
+	}
+
+	var students []uuid.UUID
+	if err := db.WithContext(ctx).Table("user_subject_summary").
+		Where("user_subject_summary_school_id = ? AND user_subject_summary_term_id = ? AND user_subject_summary_class_subjects_id = ?", schoolID, termID, classSubjectID).
+		Where("user_subject_summary_deleted_at IS NULL").
+		Distinct().Pluck("user_subject_summary_school_student_id", &students).Error; err != nil {
+		return nil, err
+	}
+
+	res := &SyncResult{TermID: termID, ClassSubjectID: classSubjectID}
+	now := time.Now()
+	for _, sid := range students {
+		rows, err := studentRecords(ctx, db, schoolID, sid)
+		if err != nil {
+			return nil, err
+		}
+		sections, err := activeSections(ctx, db, schoolID, sid)
+		if err != nil {
+			return nil, err
+		}
+		tp := termProgress(rows, t)
+		withTarget(&tp, ts.resolve(sid, sections...))
+		raw, err := json.Marshal(map[string]any{"tahfidz": tp})
+		if err != nil {
+			return nil, err
+		}
+		upd := db.WithContext(ctx).Exec(`
+			UPDATE user_subject_summary
+			   SET user_subject_summary_breakdown = COALESCE(user_subject_summary_breakdown, '{}'::jsonb) || ?::jsonb,
+			       user_subject_summary_updated_at = ?
+			 WHERE user_subject_summary_school_id = ?
+			   AND user_subject_summary_school_student_id = ?
+			   AND user_subject_summary_term_id = ?
+			   AND user_subject_summary_class_subjects_id = ?
+			   AND user_subject_summary_deleted_at IS NULL
+		`, string(raw), now, schoolID, sid, termID, classSubjectID)
+		if upd.Error != nil {
+			return nil, upd.Error
+		}
+		res.Updated += int(upd.RowsAffected)
+	}
+	return res, nil
+}
+
+/* =========================================================
+   Sesi: siswa hadir yang belum setor (require_memorization)
+========================================================= */
+
+type PendingStudent struct {
+	ParticipantID   uuid.UUID `json:"participant_id" gorm:"column:class_attendance_session_participant_id"`
+	SchoolStudentID uuid.UUID `json:"school_student_id" gorm:"column:class_attendance_session_participant_school_student_id"`
+	Name            *string   `json:"name,omitempty" gorm:"column:class_attendance_session_participant_user_profile_name_snapshot"`
+	State           string    `json:"state" gorm:"column:class_attendance_session_participant_state"`
+}
+
+type SessionPending struct {
+	SessionID           uuid.UUID        `json:"session_id"`
+	RequireMemorization bool             `json:"require_memorization"`
+	Pending             []PendingStudent `json:"pending"`
+}
+
+func PendingForSession(ctx context.Context, db *gorm.DB, schoolID, sessionID uuid.UUID) (*SessionPending, error) {
+	if _, err := loadSession(ctx, db, schoolID, sessionID); err != nil {
+		return nil, err
+	}
+	_, required, err := MemorizationSettings(ctx, db, schoolID)
+	if err != nil {
+		return nil, err
+	}
+	out := &SessionPending{SessionID: sessionID, RequireMemorization: required, Pending: make([]PendingStudent, 0)}
+	err = db.WithContext(ctx).Raw(`
+		SELECT p.class_attendance_session_participant_id,
+		       p.class_attendance_session_participant_school_student_id,
+		       p.class_attendance_session_participant_user_profile_name_snapshot,
+		       p.class_attendance_session_participant_state
+		  FROM class_attendance_session_participants p
+		 WHERE p.class_attendance_session_participant_school_id = ?
+		   AND p.class_attendance_session_participant_session_id = ?
+		   AND p.class_attendance_session_participant_school_student_id IS NOT NULL
+		   AND p.class_attendance_session_participant_state IN ('present','late')
+		   AND p.class_attendance_session_participant_deleted_at IS NULL
+		   AND NOT EXISTS (
+		     SELECT 1 FROM tahfidz_records r
+		      WHERE r.tahfidz_record_session_id = p.class_attendance_session_participant_session_id
+		        AND r.tahfidz_record_school_student_id = p.class_attendance_session_participant_school_student_id
+		        AND r.tahfidz_record_deleted_at IS NULL
+		   )
+		 ORDER BY p.class_attendance_session_participant_user_profile_name_snapshot NULLS LAST
+	`, schoolID, sessionID).Scan(&out.Pending).Error
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
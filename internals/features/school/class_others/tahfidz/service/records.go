@@ -0,0 +1,537 @@
+// file: internals/features/school/class_others/tahfidz/service/records.go
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	model "madinahsalam_backend/internals/features/school/class_others/tahfidz/model"
+	"madinahsalam_backend/internals/features/school/class_others/tahfidz/quran"
+)
+
+// PassScore: rata-rata nilai minimal agar setoran dianggap lulus (bila tidak di-set manual).
+const PassScore = 70.0
+
+var (
+	ErrRecordNotFound       = errors.New("setoran tidak ditemukan")
+	ErrInvalidType          = errors.New("jenis setoran harus ziyadah/murojaah")
+	ErrInvalidRange         = errors.New("rentang ayat tidak valid")
+	ErrRangeReversed        = errors.New("ayat awal harus sebelum ayat akhir")
+	ErrRangeRequired        = errors.New("isi juz atau surah_from")
+	ErrInvalidJuz           = errors.New("juz harus 1..30")
+	ErrInvalidScore         = errors.New("nilai harus 0..100")
+	ErrStudentNotInSchool   = errors.New("siswa tidak terdaftar di sekolah ini")
+	ErrTeacherNotInSchool   = errors.New("guru tidak terdaftar di sekolah ini")
+	ErrSessionNotFound      = errors.New("sesi kehadiran tidak ditemukan")
+	ErrMemorizationDisabled = errors.New("hafalan belum diaktifkan di pengaturan presensi sekolah")
+	ErrTermNotFound         = errors.New("semester tidak ditemukan")
+)
+
+func round2(v float64) float64 { return math.Round(v*100) / 100 }
+
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+/* =========================================================
+   Rentang ayat
+========================================================= */
+
+type RangeInput struct {
+	Juz       *int
+	SurahFrom *int
+	AyahFrom  *int
+	SurahTo   *int
+	AyahTo    *int
+}
+
+func (r RangeInput) empty() bool {
+	return r.Juz == nil && r.SurahFrom == nil && r.AyahFrom == nil && r.SurahTo == nil && r.AyahTo == nil
+}
+
+// Range: hasil validasi rentang (indeks global inklusif).
+type Range struct {
+	Scope     string
+	Juz       *int16
+	From, To  quran.Pos
+	IndexFrom int
+	IndexTo   int
+	Ayahs     int
+	Pages     float64
+}
+
+/*
+ResolveRange:
+  - juz=n                       → satu juz penuh
+  - surah_from saja             → satu surah penuh
+  - surah_from+ayah_from        → s.d. akhir surah_to (default surah_from)
+  - lengkap                     → persis rentang tsb
+*/
+func ResolveRange(in RangeInput) (*Range, error) {
+	if in.Juz != nil {
+		from, to, ok := quran.JuzRange(*in.Juz)
+		if !ok {
+			return nil, ErrInvalidJuz
+		}
+		j := int16(*in.Juz)
+		return newRange(model.RecordScopeJuz, &j, from, to), nil
+	}
+	if in.SurahFrom == nil {
+		return nil, ErrRangeRequired
+	}
+	from := quran.Pos{Surah: *in.SurahFrom, Ayah: 1}
+	if in.AyahFrom != nil {
+		from.Ayah = *in.AyahFrom
+	}
+	to := quran.Pos{Surah: from.Surah}
+	if in.SurahTo != nil {
+		to.Surah = *in.SurahTo
+	}
+	if in.AyahTo != nil {
+		to.Ayah = *in.AyahTo
+	} else if s, ok := quran.SurahByNumber(to.Surah); ok {
+		to.Ayah = s.Ayahs
+	}
+	if !quran.Valid(from) || !quran.Valid(to) {
+		return nil, ErrInvalidRange
+	}
+	fi, ti := quran.Index(from), quran.Index(to)
+	if fi > ti {
+		return nil, ErrRangeReversed
+	}
+	return newRange(model.RecordScopeSurah, nil, fi, ti), nil
+}
+
+func newRange(scope string, juz *int16, from, to int) *Range {
+	return &Range{
+		Scope:     scope,
+		Juz:       juz,
+		From:      quran.PosAt(from),
+		To:        quran.PosAt(to),
+		IndexFrom: from,
+		IndexTo:   to,
+		Ayahs:     to - from + 1,
+		Pages:     round2(quran.Pages(from, to)),
+	}
+}
+
+func applyRange(m *model.TahfidzRecordModel, r *Range) {
+	m.TahfidzRecordScope = r.Scope
+	m.TahfidzRecordJuz = r.Juz
+	m.TahfidzRecordSurahFrom = int16(r.From.Surah)
+	m.TahfidzRecordAyahFrom = int16(r.From.Ayah)
+	m.TahfidzRecordSurahTo = int16(r.To.Surah)
+	m.TahfidzRecordAyahTo = int16(r.To.Ayah)
+	m.TahfidzRecordIndexFrom = int16(r.IndexFrom)
+	m.TahfidzRecordIndexTo = int16(r.IndexTo)
+	m.TahfidzRecordAyahCount = r.Ayahs
+	m.TahfidzRecordPages = r.Pages
+}
+
+// rangeInputOf: rentang tersimpan → input (dasar merge saat PATCH sebagian).
+func rangeInputOf(m *model.TahfidzRecordModel) RangeInput {
+	if m.TahfidzRecordScope == model.RecordScopeJuz && m.TahfidzRecordJuz != nil {
+		j := int(*m.TahfidzRecordJuz)
+		return RangeInput{Juz: &j}
+	}
+	sf, af := int(m.TahfidzRecordSurahFrom), int(m.TahfidzRecordAyahFrom)
+	st, at := int(m.TahfidzRecordSurahTo), int(m.TahfidzRecordAyahTo)
+	return RangeInput{SurahFrom: &sf, AyahFrom: &af, SurahTo: &st, AyahTo: &at}
+}
+
+/* =========================================================
+   Nilai
+========================================================= */
+
+func validScore(p *float64) bool { return p == nil || (*p >= 0 && *p <= 100) }
+
+// applyScores: rata-rata dari nilai yang diisi; lulus otomatis bila tidak di-override.
+func applyScores(m *model.TahfidzRecordModel, override *bool) {
+	sum, n := 0.0, 0
+	for _, p := range []*float64{m.TahfidzRecordScoreTajwid, m.TahfidzRecordScoreFluency, m.TahfidzRecordScoreMakhraj} {
+		if p != nil {
+			sum += *p
+			n++
+		}
+	}
+	m.TahfidzRecordScoreAvg = nil
+	if n > 0 {
+		avg := round2(sum / float64(n))
+		m.TahfidzRecordScoreAvg = &avg
+	}
+	switch {
+	case override != nil:
+		m.TahfidzRecordIsPassed = *override
+	case m.TahfidzRecordScoreAvg != nil:
+		m.TahfidzRecordIsPassed = *m.TahfidzRecordScoreAvg >= PassScore
+	default:
+		m.TahfidzRecordIsPassed = true
+	}
+}
+
+/* =========================================================
+   Lookup konteks
+========================================================= */
+
+func studentInSchool(ctx context.Context, db *gorm.DB, schoolID, studentID uuid.UUID) (bool, error) {
+	var n int64
+	err := db.WithContext(ctx).Table("school_students").
+		Where("school_student_id = ? AND school_student_school_id = ? AND school_student_deleted_at IS NULL", studentID, schoolID).
+		Count(&n).Error
+	return n > 0, err
+}
+
+func teacherInSchool(ctx context.Context, db *gorm.DB, schoolID, teacherID uuid.UUID) (bool, error) {
+	var n int64
+	err := db.WithContext(ctx).Table("school_teachers").
+		Where("school_teacher_id = ? AND school_teacher_school_id = ? AND school_teacher_deleted_at IS NULL", teacherID, schoolID).
+		Count(&n).Error
+	return n > 0, err
+}
+
+// MemorizationSettings: switch hafalan di class_attendance_settings (tanpa baris = nonaktif).
+func MemorizationSettings(ctx context.Context, db *gorm.DB, schoolID uuid.UUID) (enabled, required bool, err error) {
+	var row struct {
+		Enable  bool `gorm:"column:class_attendance_setting_enable_memorization"`
+		Require bool `gorm:"column:class_attendance_setting_require_memorization"`
+	}
+	res := db.WithContext(ctx).Table("class_attendance_settings").
+		Select("class_attendance_setting_enable_memorization, class_attendance_setting_require_memorization").
+		Where("class_attendance_setting_school_id = ?", schoolID).
+		Limit(1).Scan(&row)
+	if res.Error != nil {
+		return false, false, res.Error
+	}
+	return row.Enable, row.Require, nil
+}
+
+type sessionInfo struct {
+	Date      time.Time  `gorm:"column:class_attendance_session_date"`
+	TeacherID *uuid.UUID `gorm:"column:class_attendance_session_teacher_id"`
+}
+
+func loadSession(ctx context.Context, db *gorm.DB, schoolID, sessionID uuid.UUID) (*sessionInfo, error) {
+	var s sessionInfo
+	res := db.WithContext(ctx).Table("class_attendance_sessions").
+		Select("class_attendance_session_date, class_attendance_session_teacher_id").
+		Where("class_attendance_session_id = ? AND class_attendance_session_school_id = ? AND class_attendance_session_deleted_at IS NULL", sessionID, schoolID).
+		Limit(1).Scan(&s)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, ErrSessionNotFound
+	}
+	return &s, nil
+}
+
+func participantOf(ctx context.Context, db *gorm.DB, sessionID, studentID uuid.UUID) (*uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := db.WithContext(ctx).Table("class_attendance_session_participants").
+		Where("class_attendance_session_participant_session_id = ? AND class_attendance_session_participant_school_student_id = ? AND class_attendance_session_participant_deleted_at IS NULL", sessionID, studentID).
+		Limit(1).Pluck("class_attendance_session_participant_id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+	return &ids[0], nil
+}
+
+// TermForDate: semester sekolah yang mencakup tanggal (prioritas yang aktif).
+func TermForDate(ctx context.Context, db *gorm.DB, schoolID uuid.UUID, on time.Time) (*uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := db.WithContext(ctx).Table("academic_terms").
+		Where("academic_term_school_id = ? AND academic_term_deleted_at IS NULL", schoolID).
+		Where("academic_term_start_date::date <= ? AND academic_term_end_date::date >= ?", on.Format("2006-01-02"), on.Format("2006-01-02")).
+		Order("academic_term_is_active DESC, academic_term_start_date DESC").
+		Limit(1).Pluck("academic_term_id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+	return &ids[0], nil
+}
+
+/* =========================================================
+   CRUD setoran
+========================================================= */
+
+type RecordInput struct {
+	SchoolStudentID *uuid.UUID
+	SchoolTeacherID *uuid.UUID
+	SessionID       *uuid.UUID
+	Type            *string
+	Range           RangeInput
+	ScoreTajwid     *float64
+	ScoreFluency    *float64
+	ScoreMakhraj    *float64
+	IsPassed        *bool
+	RecordedOn      *time.Time
+	Note            *string
+}
+
+// Actor: pencatat dari token (guru penyimak default = guru yang login).
+type Actor struct {
+	UserID    *uuid.UUID
+	TeacherID *uuid.UUID
+	Today     time.Time
+}
+
+func normType(s *string) (string, error) {
+	if s == nil {
+		return model.RecordTypeZiyadah, nil
+	}
+	t := strings.ToLower(strings.TrimSpace(*s))
+	if t != model.RecordTypeZiyadah && t != model.RecordTypeMurojaah {
+		return "", ErrInvalidType
+	}
+	return t, nil
+}
+
+func trimNote(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	v := strings.TrimSpace(*s)
+	if v == "" {
+		return nil
+	}
+	return &v
+}
+
+func CreateRecord(ctx context.Context, db *gorm.DB, schoolID uuid.UUID, in RecordInput, actor Actor) (*model.TahfidzRecordModel, error) {
+	if in.SchoolStudentID == nil || *in.SchoolStudentID == uuid.Nil {
+		return nil, ErrStudentNotInSchool
+	}
+	if ok, err := studentInSchool(ctx, db, schoolID, *in.SchoolStudentID); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, ErrStudentNotInSchool
+	}
+	typ, err := normType(in.Type)
+	if err != nil {
+		return nil, err
+	}
+	rg, err := ResolveRange(in.Range)
+	if err != nil {
+		return nil, err
+	}
+	if !validScore(in.ScoreTajwid) || !validScore(in.ScoreFluency) || !validScore(in.ScoreMakhraj) {
+		return nil, ErrInvalidScore
+	}
+
+	m := &model.TahfidzRecordModel{
+		TahfidzRecordSchoolID:        schoolID,
+		TahfidzRecordSchoolStudentID: *in.SchoolStudentID,
+		TahfidzRecordType:            typ,
+		TahfidzRecordScoreTajwid:     in.ScoreTajwid,
+		TahfidzRecordScoreFluency:    in.ScoreFluency,
+		TahfidzRecordScoreMakhraj:    in.ScoreMakhraj,
+		TahfidzRecordNote:            trimNote(in.Note),
+		TahfidzRecordCreatedBy:       actor.UserID,
+		TahfidzRecordRecordedOn:      dateOnly(actor.Today),
+	}
+	applyRange(m, rg)
+	applyScores(m, in.IsPassed)
+
+	teacherID := in.SchoolTeacherID
+	if teacherID == nil {
+		teacherID = actor.TeacherID
+	}
+
+	if in.SessionID != nil && *in.SessionID != uuid.Nil {
+		enabled, _, err := MemorizationSettings(ctx, db, schoolID)
+		if err != nil {
+			return nil, err
+		}
+		if !enabled {
+			return nil, ErrMemorizationDisabled
+		}
+		sess, err := loadSession(ctx, db, schoolID, *in.SessionID)
+		if err != nil {
+			return nil, err
+		}
+		m.TahfidzRecordSessionID = in.SessionID
+		m.TahfidzRecordRecordedOn = dateOnly(sess.Date)
+		if teacherID == nil {
+			teacherID = sess.TeacherID
+		}
+		if m.TahfidzRecordParticipantID, err = participantOf(ctx, db, *in.SessionID, *in.SchoolStudentID); err != nil {
+			return nil, err
+		}
+	}
+	if in.RecordedOn != nil {
+		m.TahfidzRecordRecordedOn = dateOnly(*in.RecordedOn)
+	}
+
+	if teacherID != nil && *teacherID != uuid.Nil {
+		if ok, err := teacherInSchool(ctx, db, schoolID, *teacherID); err != nil {
+			return nil, err
+		} else if !ok {
+			return nil, ErrTeacherNotInSchool
+		}
+		m.TahfidzRecordSchoolTeacherID = teacherID
+	}
+
+	if m.TahfidzRecordTermID, err = TermForDate(ctx, db, schoolID, m.TahfidzRecordRecordedOn); err != nil {
+		return nil, err
+	}
+
+	if err := db.WithContext(ctx).Create(m).Error; err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func GetRecord(ctx context.Context, db *gorm.DB, schoolID, id uuid.UUID) (*model.TahfidzRecordModel, error) {
+	var m model.TahfidzRecordModel
+	err := db.WithContext(ctx).
+		Where("tahfidz_record_id = ? AND tahfidz_record_school_id = ?", id, schoolID).
+		Take(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRecordNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// UpdateRecord: siswa & sesi tetap; rentang dihitung ulang bila salah satu field rentang diisi.
+func UpdateRecord(ctx context.Context, db *gorm.DB, schoolID, id uuid.UUID, in RecordInput) (*model.TahfidzRecordModel, error) {
+	m, err := GetRecord(ctx, db, schoolID, id)
+	if err != nil {
+		return nil, err
+	}
+	if in.Type != nil {
+		if m.TahfidzRecordType, err = normType(in.Type); err != nil {
+			return nil, err
+		}
+	}
+	if !in.Range.empty() {
+		merged := in.Range
+		if merged.Juz == nil && merged.SurahFrom == nil {
+			// hanya ayat/surah akhir yang diubah → awal diambil dari data lama
+			old := rangeInputOf(m)
+			if old.Juz != nil {
+				return nil, ErrRangeRequired
+			}
+			merged.SurahFrom = old.SurahFrom
+			if merged.AyahFrom == nil {
+				merged.AyahFrom = old.AyahFrom
+			}
+			if merged.SurahTo == nil && merged.AyahTo != nil {
+				merged.SurahTo = old.SurahTo
+			}
+		}
+		rg, err := ResolveRange(merged)
+		if err != nil {
+			return nil, err
+		}
+		applyRange(m, rg)
+	}
+	if !validScore(in.ScoreTajwid) || !validScore(in.ScoreFluency) || !validScore(in.ScoreMakhraj) {
+		return nil, ErrInvalidScore
+	}
+	if in.ScoreTajwid != nil {
+		m.TahfidzRecordScoreTajwid = in.ScoreTajwid
+	}
+	if in.ScoreFluency != nil {
+		m.TahfidzRecordScoreFluency = in.ScoreFluency
+	}
+	if in.ScoreMakhraj != nil {
+		m.TahfidzRecordScoreMakhraj = in.ScoreMakhraj
+	}
+	override := in.IsPassed
+	if override == nil && in.ScoreTajwid == nil && in.ScoreFluency == nil && in.ScoreMakhraj == nil {
+		passed := m.TahfidzRecordIsPassed // nilai tidak berubah → status lulus dipertahankan
+		override = &passed
+	}
+	applyScores(m, override)
+
+	if in.SchoolTeacherID != nil {
+		if ok, err := teacherInSchool(ctx, db, schoolID, *in.SchoolTeacherID); err != nil {
+			return nil, err
+		} else if !ok {
+			return nil, ErrTeacherNotInSchool
+		}
+		m.TahfidzRecordSchoolTeacherID = in.SchoolTeacherID
+	}
+	if in.RecordedOn != nil {
+		m.TahfidzRecordRecordedOn = dateOnly(*in.RecordedOn)
+		if m.TahfidzRecordTermID, err = TermForDate(ctx, db, schoolID, m.TahfidzRecordRecordedOn); err != nil {
+			return nil, err
+		}
+	}
+	if in.Note != nil {
+		m.TahfidzRecordNote = trimNote(in.Note)
+	}
+
+	if err := db.WithContext(ctx).Save(m).Error; err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func DeleteRecord(ctx context.Context, db *gorm.DB, schoolID, id uuid.UUID) error {
+	res := db.WithContext(ctx).
+		Where("tahfidz_record_id = ? AND tahfidz_record_school_id = ?", id, schoolID).
+		Delete(&model.TahfidzRecordModel{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+type RecordFilter struct {
+	SchoolStudentID *uuid.UUID
+	SchoolTeacherID *uuid.UUID
+	SessionID       *uuid.UUID
+	TermID          *uuid.UUID
+	Type            string
+	From, To        *time.Time
+	Offset, Limit   int
+}
+
+func ListRecords(ctx context.Context, db *gorm.DB, schoolID uuid.UUID, f RecordFilter) ([]model.TahfidzRecordModel, int64, error) {
+	q := db.WithContext(ctx).Model(&model.TahfidzRecordModel{}).
+		Where("tahfidz_record_school_id = ?", schoolID)
+	if f.SchoolStudentID != nil {
+		q = q.Where("tahfidz_record_school_student_id = ?", *f.SchoolStudentID)
+	}
+	if f.SchoolTeacherID != nil {
+		q = q.Where("tahfidz_record_school_teacher_id = ?", *f.SchoolTeacherID)
+	}
+	if f.SessionID != nil {
+		q = q.Where("tahfidz_record_session_id = ?", *f.SessionID)
+	}
+	if f.TermID != nil {
+		q = q.Where("tahfidz_record_term_id = ?", *f.TermID)
+	}
+	if f.Type != "" {
+		q = q.Where("tahfidz_record_type = ?", f.Type)
+	}
+	if f.From != nil {
+		q = q.Where("tahfidz_record_recorded_on >= ?", f.From.Format("2006-01-02"))
+	}
+	if f.To != nil {
+		q = q.Where("tahfidz_record_recorded_on <= ?", f.To.Format("2006-01-02"))
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var rows []model.TahfidzRecordModel
+	err := q.Order("tahfidz_record_recorded_on DESC, tahfidz_record_created_at DESC").
+		Offset(f.Offset).Limit(f.Limit).
+		Find(&rows).Error
+	return rows, total, err
+}
@@ -0,0 +1,261 @@
+// file: internals/features/school/class_others/tahfidz/service/targets.go
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	model "madinahsalam_backend/internals/features/school/class_others/tahfidz/model"
+)
+
+var (
+	ErrTargetNotFound = errors.New("target hafalan tidak ditemukan")
+	ErrTargetExists   = errors.New("target untuk semester & sasaran ini sudah ada")
+	ErrTargetSubject  = errors.New("pilih salah satu: class_section_id atau school_student_id (atau kosong untuk default sekolah)")
+	ErrTargetPages    = errors.New("target halaman harus > 0")
+	ErrTargetJuz      = errors.New("target total juz harus 0..30")
+	ErrSectionInvalid = errors.New("rombel tidak ditemukan di sekolah ini")
+)
+
+const (
+	TargetSourceStudent = "student"
+	TargetSourceSection = "class_section"
+	TargetSourceSchool  = "school"
+)
+
+func isUniqueViolation(err error) bool {
+	if err == nil {
+		return false
+	}
+	s := strings.ToLower(err.Error())
+	return strings.Contains(s, "duplicate key") || strings.Contains(s, "sqlstate 23505")
+}
+
+type TargetInput struct {
+	TermID          *uuid.UUID
+	ClassSectionID  *uuid.UUID
+	SchoolStudentID *uuid.UUID
+	Pages           *float64
+	JuzTotal        *float64
+	Note            *string
+}
+
+func sectionInSchool(ctx context.Context, db *gorm.DB, schoolID, sectionID uuid.UUID) (bool, error) {
+	var n int64
+	err := db.WithContext(ctx).Table("class_sections").
+		Where("class_section_id = ? AND class_section_school_id = ? AND class_section_deleted_at IS NULL", sectionID, schoolID).
+		Count(&n).Error
+	return n > 0, err
+}
+
+func ListTargets(ctx context.Context, db *gorm.DB, schoolID uuid.UUID, termID *uuid.UUID) ([]model.TahfidzTargetModel, error) {
+	q := db.WithContext(ctx).Where("tahfidz_target_school_id = ?", schoolID)
+	if termID != nil {
+		q = q.Where("tahfidz_target_term_id = ?", *termID)
+	}
+	var rows []model.TahfidzTargetModel
+	err := q.Order("tahfidz_target_created_at ASC").Find(&rows).Error
+	return rows, err
+}
+
+func GetTarget(ctx context.Context, db *gorm.DB, schoolID, id uuid.UUID) (*model.TahfidzTargetModel, error) {
+	var m model.TahfidzTargetModel
+	err := db.WithContext(ctx).
+		Where("tahfidz_target_id = ? AND tahfidz_target_school_id = ?", id, schoolID).
+		Take(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrTargetNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func CreateTarget(ctx context.Context, db *gorm.DB, schoolID uuid.UUID, in TargetInput) (*model.TahfidzTargetModel, error) {
+	if in.TermID == nil || *in.TermID == uuid.Nil {
+		return nil, ErrTermNotFound
+	}
+	terms, err := loadTerms(ctx, db, schoolID, []uuid.UUID{*in.TermID})
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := terms[*in.TermID]; !ok {
+		return nil, ErrTermNotFound
+	}
+	if in.ClassSectionID != nil && in.SchoolStudentID != nil {
+		return nil, ErrTargetSubject
+	}
+	if in.ClassSectionID != nil {
+		if ok, err := sectionInSchool(ctx, db, schoolID, *in.ClassSectionID); err != nil {
+			return nil, err
+		} else if !ok {
+			return nil, ErrSectionInvalid
+		}
+	}
+	if in.SchoolStudentID != nil {
+		if ok, err := studentInSchool(ctx, db, schoolID, *in.SchoolStudentID); err != nil {
+			return nil, err
+		} else if !ok {
+			return nil, ErrStudentNotInSchool
+		}
+	}
+	if in.Pages == nil {
+		return nil, ErrTargetPages
+	}
+	m := &model.TahfidzTargetModel{
+		TahfidzTargetSchoolID:        schoolID,
+		TahfidzTargetTermID:          *in.TermID,
+		TahfidzTargetClassSectionID:  in.ClassSectionID,
+		TahfidzTargetSchoolStudentID: in.SchoolStudentID,
+	}
+	if err := applyTargetInput(m, in); err != nil {
+		return nil, err
+	}
+	if err := db.WithContext(ctx).Create(m).Error; err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrTargetExists
+		}
+		return nil, err
+	}
+	return m, nil
+}
+
+// UpdateTarget: sasaran (semester/rombel/siswa) tetap; hanya nilai target & catatan.
+func UpdateTarget(ctx context.Context, db *gorm.DB, schoolID, id uuid.UUID, in TargetInput) (*model.TahfidzTargetModel, error) {
+	m, err := GetTarget(ctx, db, schoolID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyTargetInput(m, in); err != nil {
+		return nil, err
+	}
+	if err := db.WithContext(ctx).Save(m).Error; err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func applyTargetInput(m *model.TahfidzTargetModel, in TargetInput) error {
+	if in.Pages != nil {
+		if *in.Pages <= 0 || *in.Pages > 604 {
+			return ErrTargetPages
+		}
+		m.TahfidzTargetPages = round2(*in.Pages)
+	}
+	if in.JuzTotal != nil {
+		if *in.JuzTotal < 0 || *in.JuzTotal > 30 {
+			return ErrTargetJuz
+		}
+		m.TahfidzTargetJuzTotal = in.JuzTotal
+	}
+	if in.Note != nil {
+		m.TahfidzTargetNote = trimNote(in.Note)
+	}
+	return nil
+}
+
+func DeleteTarget(ctx context.Context, db *gorm.DB, schoolID, id uuid.UUID) error {
+	res := db.WithContext(ctx).
+		Where("tahfidz_target_id = ? AND tahfidz_target_school_id = ?", id, schoolID).
+		Delete(&model.TahfidzTargetModel{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrTargetNotFound
+	}
+	return nil
+}
+
+/* =========================================================
+   Resolusi target: siswa > rombel > sekolah
+========================================================= */
+
+type targetSet struct {
+	school    *model.TahfidzTargetModel
+	bySection map[uuid.UUID]*model.TahfidzTargetModel
+	byStudent map[uuid.UUID]*model.TahfidzTargetModel
+}
+
+func loadTargetSet(ctx context.Context, db *gorm.DB, schoolID, termID uuid.UUID) (*targetSet, error) {
+	rows, err := ListTargets(ctx, db, schoolID, &termID)
+	if err != nil {
+		return nil, err
+	}
+	ts := &targetSet{
+		bySection: map[uuid.UUID]*model.TahfidzTargetModel{},
+		byStudent: map[uuid.UUID]*model.TahfidzTargetModel{},
+	}
+	for i := range rows {
+		t := &rows[i]
+		switch {
+		case t.TahfidzTargetSchoolStudentID != nil:
+			ts.byStudent[*t.TahfidzTargetSchoolStudentID] = t
+		case t.TahfidzTargetClassSectionID != nil:
+			ts.bySection[*t.TahfidzTargetClassSectionID] = t
+		default:
+			ts.school = t
+		}
+	}
+	return ts, nil
+}
+
+func summarize(t *model.TahfidzTargetModel, source string) *TargetSummary {
+	return &TargetSummary{Source: source, Pages: t.TahfidzTargetPages, JuzTotal: t.TahfidzTargetJuzTotal}
+}
+
+// resolve: sections = rombel aktif siswa (boleh lebih dari satu; yang pertama punya target dipakai).
+func (ts *targetSet) resolve(studentID uuid.UUID, sections ...*uuid.UUID) *TargetSummary {
+	if ts == nil {
+		return nil
+	}
+	if t, ok := ts.byStudent[studentID]; ok {
+		return summarize(t, TargetSourceStudent)
+	}
+	for _, sid := range sections {
+		if sid == nil {
+			continue
+		}
+		if t, ok := ts.bySection[*sid]; ok {
+			return summarize(t, TargetSourceSection)
+		}
+	}
+	if ts.school != nil {
+		return summarize(ts.school, TargetSourceSchool)
+	}
+	return nil
+}
+
+func activeSections(ctx context.Context, db *gorm.DB, schoolID, studentID uuid.UUID) ([]*uuid.UUID, error) {
+	var ids []uuid.UUID
+	if err := db.WithContext(ctx).Table("student_class_sections").
+		Where("student_class_section_school_id = ? AND student_class_section_school_student_id = ?", schoolID, studentID).
+		Where("student_class_section_status = 'active' AND student_class_section_deleted_at IS NULL").
+		Order("student_class_section_created_at DESC").
+		Pluck("student_class_section_section_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	out := make([]*uuid.UUID, len(ids))
+	for i := range ids {
+		out[i] = &ids[i]
+	}
+	return out, nil
+}
+
+// ResolveTarget: target efektif siswa untuk semester (nil bila belum diatur).
+func ResolveTarget(ctx context.Context, db *gorm.DB, schoolID, termID, studentID uuid.UUID) (*TargetSummary, error) {
+	ts, err := loadTargetSet(ctx, db, schoolID, termID)
+	if err != nil {
+		return nil, err
+	}
+	sections, err := activeSections(ctx, db, schoolID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	return ts.resolve(studentID, sections...), nil
+}
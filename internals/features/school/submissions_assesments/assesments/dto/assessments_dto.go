@@ -285,6 +285,8 @@ type AssessmentResponse struct {
 	AssessmentAnnounceSessionID *uuid.UUID `json:"assessment_announce_session_id,omitempty"`
 	AssessmentCollectSessionID  *uuid.UUID `json:"assessment_collect_session_id,omitempty"`
 
+	AssessmentRubricID *uuid.UUID `json:"assessment_rubric_id,omitempty"`
+
 	AssessmentCreatedAt time.Time `json:"assessment_created_at"`
 	AssessmentUpdatedAt time.Time `json:"assessment_updated_at"`
 
@@ -327,6 +329,8 @@ func buildAssessmentResponse(m assessModel.AssessmentModel, isOpen bool) Assessm
 		AssessmentAnnounceSessionID: m.AssessmentAnnounceSessionID,
 		AssessmentCollectSessionID:  m.AssessmentCollectSessionID,
 
+		AssessmentRubricID: m.AssessmentRubricID,
+
 		AssessmentCreatedAt: m.AssessmentCreatedAt,
 		AssessmentUpdatedAt: m.AssessmentUpdatedAt,
 
@@ -108,6 +108,9 @@ type AssessmentModel struct {
 	AssessmentAnnounceSessionID *uuid.UUID               `gorm:"type:uuid;column:assessment_announce_session_id"`
 	AssessmentCollectSessionID  *uuid.UUID               `gorm:"type:uuid;column:assessment_collect_session_id"`
 
+	// Rubrik penilaian (assignment_upload)
+	AssessmentRubricID *uuid.UUID `gorm:"type:uuid;column:assessment_rubric_id"`
+
 	// Timestamps
 	AssessmentCreatedAt time.Time      `gorm:"type:timestamptz;not null;default:now();column:assessment_created_at"`
 	AssessmentUpdatedAt time.Time      `gorm:"type:timestamptz;not null;default:now();column:assessment_updated_at"`
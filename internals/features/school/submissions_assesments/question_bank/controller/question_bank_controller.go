@@ -0,0 +1,205 @@
+// file: internals/features/school/submissions_assesments/question_bank/controller/question_bank_controller.go
+package controller
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	permsvc "madinahsalam_backend/internals/features/lembaga/permissions/service"
+	dto "madinahsalam_backend/internals/features/school/submissions_assesments/question_bank/dto"
+	svc "madinahsalam_backend/internals/features/school/submissions_assesments/question_bank/service"
+	helper "madinahsalam_backend/internals/helpers"
+	helperAuth "madinahsalam_backend/internals/helpers/auth"
+)
+
+/*
+Bank soal sekolah (ditandai mapel / class parent / topik / kesulitan)
+
+GET    /api/u/question-bank?subject_id=&class_parent_id=&topic=&difficulty=&type=&q=&active=
+POST   /api/u/question-bank                       {"subject_id","class_parent_id","topic","difficulty","type","text","points","answers","correct","explanation"}
+GET    /api/u/question-bank/:id
+PATCH  /api/u/question-bank/:id                   ubah isi → versi naik, soal quiz mode reference ikut disinkron
+DELETE /api/u/question-bank/:id
+
+/api/a/question-bank → sama, dijaga permission grades.*
+*/
+
+type QuestionBankController struct {
+	DB    *gorm.DB
+	Admin bool // route /api/a: akses sudah dijaga permission grades.*
+}
+
+func NewQuestionBankController(db *gorm.DB) *QuestionBankController {
+	return &QuestionBankController{DB: db}
+}
+
+func NewQuestionBankAdminController(db *gorm.DB) *QuestionBankController {
+	return &QuestionBankController{DB: db, Admin: true}
+}
+
+func writeErr(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, svc.ErrItemNotFound), errors.Is(err, svc.ErrQuizNotFound), errors.Is(err, svc.ErrRuleNotFound):
+		return helper.JsonError(c, fiber.StatusNotFound, err.Error())
+	case errors.Is(err, svc.ErrShapeInvalid), errors.Is(err, svc.ErrDifficultyInvalid), errors.Is(err, svc.ErrSubjectInvalid),
+		errors.Is(err, svc.ErrClassParentInvalid), errors.Is(err, svc.ErrItemInactive), errors.Is(err, svc.ErrModeInvalid),
+		errors.Is(err, svc.ErrNoItems), errors.Is(err, svc.ErrRuleCountInvalid):
+		return helper.JsonError(c, fiber.StatusBadRequest, err.Error())
+	}
+	var fe *fiber.Error
+	if errors.As(err, &fe) {
+		return helper.JsonError(c, fe.Code, fe.Message)
+	}
+	return helper.JsonError(c, fiber.StatusInternalServerError, err.Error())
+}
+
+// scope: school aktif + :id (bila ada di route).
+func scope(c *fiber.Ctx) (uuid.UUID, uuid.UUID, error) {
+	schoolID, err := permsvc.SchoolFromRequest(c)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	if c.Params("id") == "" {
+		return schoolID, uuid.Nil, nil
+	}
+	id, err := uuid.Parse(strings.TrimSpace(c.Params("id")))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fiber.NewError(fiber.StatusBadRequest, "id tidak valid")
+	}
+	return schoolID, id, nil
+}
+
+// staffScope: scope + guard guru/DKM/owner (dilewati untuk controller admin).
+func (h *QuestionBankController) staffScope(c *fiber.Ctx) (uuid.UUID, uuid.UUID, error) {
+	schoolID, id, err := scope(c)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	if h.Admin || helperAuth.IsOwner(c) || helperAuth.IsDKMInSchool(c, schoolID) || helperAuth.IsTeacherInSchool(c, schoolID) {
+		return schoolID, id, nil
+	}
+	return uuid.Nil, uuid.Nil, fiber.NewError(fiber.StatusForbidden, "Hanya guru/DKM yang boleh mengelola bank soal")
+}
+
+func teacherOf(c *fiber.Ctx, schoolID uuid.UUID) *uuid.UUID {
+	if !helperAuth.IsTeacherInSchool(c, schoolID) {
+		return nil
+	}
+	if tid, err := helperAuth.GetSchoolTeacherIDForSchool(c, schoolID); err == nil && tid != uuid.Nil {
+		return &tid
+	}
+	return nil
+}
+
+func optUUID(c *fiber.Ctx, key string) (*uuid.UUID, error) {
+	v := strings.TrimSpace(c.Query(key))
+	if v == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(v)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, key+" tidak valid")
+	}
+	return &id, nil
+}
+
+/* =========================================================
+   CRUD
+========================================================= */
+
+// GET /question-bank
+func (h *QuestionBankController) List(c *fiber.Ctx) error {
+	schoolID, _, err := h.staffScope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	f := svc.ListFilter{
+		Topic:      c.Query("topic"),
+		Difficulty: c.Query("difficulty"),
+		Type:       c.Query("type"),
+		Q:          c.Query("q"),
+	}
+	if f.SubjectID, err = optUUID(c, "subject_id"); err != nil {
+		return writeErr(c, err)
+	}
+	if f.ClassParentID, err = optUUID(c, "class_parent_id"); err != nil {
+		return writeErr(c, err)
+	}
+	switch strings.ToLower(strings.TrimSpace(c.Query("active"))) {
+	case "1", "true", "yes":
+		f.OnlyActive = true
+	}
+	p := helper.ResolvePaging(c, 20, 200)
+	f.Offset, f.Limit = p.Offset, p.Limit
+	rows, total, err := svc.ListItems(c.Context(), h.DB, schoolID, f)
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusInternalServerError, "Gagal mengambil bank soal")
+	}
+	return helper.JsonList(c, "OK", rows, helper.BuildPaginationFromPage(total, p.Page, p.PerPage))
+}
+
+// GET /question-bank/:id
+func (h *QuestionBankController) Get(c *fiber.Ctx) error {
+	schoolID, id, err := h.staffScope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	m, err := svc.GetItem(c.Context(), h.DB, schoolID, id)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonOK(c, "OK", m)
+}
+
+// POST /question-bank
+func (h *QuestionBankController) Create(c *fiber.Ctx) error {
+	schoolID, _, err := h.staffScope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	var req dto.ItemRequest
+	if err := c.BodyParser(&req); err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "Payload tidak valid")
+	}
+	m, err := svc.CreateItem(c.Context(), h.DB, schoolID, req.ToInput(), teacherOf(c, schoolID))
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonCreated(c, "Soal bank dibuat", m)
+}
+
+// PATCH /question-bank/:id
+func (h *QuestionBankController) Update(c *fiber.Ctx) error {
+	schoolID, id, err := h.staffScope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	var req dto.ItemRequest
+	if err := c.BodyParser(&req); err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "Payload tidak valid")
+	}
+	m, synced, err := svc.UpdateItem(c.Context(), h.DB, schoolID, id, req.ToInput())
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonUpdated(c, "Soal bank diperbarui", fiber.Map{
+		"item":             m,
+		"synced_questions": synced,
+	})
+}
+
+// DELETE /question-bank/:id
+func (h *QuestionBankController) Delete(c *fiber.Ctx) error {
+	schoolID, id, err := h.staffScope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	if err := svc.DeleteItem(c.Context(), h.DB, schoolID, id); err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonDeleted(c, "Soal bank dihapus", fiber.Map{"question_bank_item_id": id})
+}
@@ -0,0 +1,117 @@
+// file: internals/features/school/submissions_assesments/question_bank/controller/quiz_draw_controller.go
+package controller
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	dto "madinahsalam_backend/internals/features/school/submissions_assesments/question_bank/dto"
+	svc "madinahsalam_backend/internals/features/school/submissions_assesments/question_bank/service"
+	helper "madinahsalam_backend/internals/helpers"
+)
+
+/*
+Bank soal → quiz
+
+POST   /api/u/question-bank/quizzes/:id/import                  {"item_ids":[...],"mode":"copy|reference"}
+GET    /api/u/question-bank/quizzes/:id/draw-rules              (+ jumlah soal bank yang cocok)
+POST   /api/u/question-bank/quizzes/:id/draw-rules              {"subject_id","class_parent_id","topic","difficulty","count","order"}
+PUT    /api/u/question-bank/quizzes/:id/draw-rules/:rule_id
+DELETE /api/u/question-bank/quizzes/:id/draw-rules/:rule_id
+
+Aturan ditarik per siswa saat attempt dimulai (lihat quizzes attempts/:id/questions).
+*/
+
+func ruleParam(c *fiber.Ctx) (uuid.UUID, error) {
+	id, err := uuid.Parse(strings.TrimSpace(c.Params("rule_id")))
+	if err != nil {
+		return uuid.Nil, fiber.NewError(fiber.StatusBadRequest, "rule_id tidak valid")
+	}
+	return id, nil
+}
+
+// POST /question-bank/quizzes/:id/import
+func (h *QuestionBankController) ImportToQuiz(c *fiber.Ctx) error {
+	schoolID, quizID, err := h.staffScope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	var req dto.ImportRequest
+	if err := c.BodyParser(&req); err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "Payload tidak valid")
+	}
+	res, err := svc.ImportToQuiz(c.Context(), h.DB, schoolID, quizID, req.ItemIDs, req.Mode)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonCreated(c, "Soal bank diimpor ke quiz", res)
+}
+
+// GET /question-bank/quizzes/:id/draw-rules
+func (h *QuestionBankController) ListDrawRules(c *fiber.Ctx) error {
+	schoolID, quizID, err := h.staffScope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	rows, err := svc.ListRules(c.Context(), h.DB, schoolID, quizID)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonOK(c, "OK", rows)
+}
+
+// POST /question-bank/quizzes/:id/draw-rules
+func (h *QuestionBankController) CreateDrawRule(c *fiber.Ctx) error {
+	schoolID, quizID, err := h.staffScope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	var req dto.DrawRuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "Payload tidak valid")
+	}
+	r, err := svc.CreateRule(c.Context(), h.DB, schoolID, quizID, req.ToInput())
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonCreated(c, "Aturan tarik soal dibuat", r)
+}
+
+// PUT /question-bank/quizzes/:id/draw-rules/:rule_id
+func (h *QuestionBankController) UpdateDrawRule(c *fiber.Ctx) error {
+	schoolID, quizID, err := h.staffScope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	ruleID, err := ruleParam(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	var req dto.DrawRuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "Payload tidak valid")
+	}
+	r, err := svc.UpdateRule(c.Context(), h.DB, schoolID, quizID, ruleID, req.ToInput())
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonUpdated(c, "Aturan tarik soal diperbarui", r)
+}
+
+// DELETE /question-bank/quizzes/:id/draw-rules/:rule_id
+func (h *QuestionBankController) DeleteDrawRule(c *fiber.Ctx) error {
+	schoolID, quizID, err := h.staffScope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	ruleID, err := ruleParam(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	if err := svc.DeleteRule(c.Context(), h.DB, schoolID, quizID, ruleID); err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonDeleted(c, "Aturan tarik soal dihapus", fiber.Map{"quiz_draw_rule_id": ruleID})
+}
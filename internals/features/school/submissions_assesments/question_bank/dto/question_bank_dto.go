@@ -0,0 +1,73 @@
+// file: internals/features/school/submissions_assesments/question_bank/dto/question_bank_dto.go
+package dto
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+
+	svc "madinahsalam_backend/internals/features/school/submissions_assesments/question_bank/service"
+)
+
+/* =========================================================
+   SOAL BANK
+========================================================= */
+
+type ItemRequest struct {
+	SubjectID     *uuid.UUID      `json:"subject_id"`      // uuid nol (PATCH) = lepas tag
+	ClassParentID *uuid.UUID      `json:"class_parent_id"` // uuid nol (PATCH) = lepas tag
+	Topic         *string         `json:"topic"`
+	Difficulty    *string         `json:"difficulty"` // easy | medium | hard
+	Type          *string         `json:"type"`       // single | essay
+	Text          *string         `json:"text"`
+	Points        *float64        `json:"points"`
+	Answers       json.RawMessage `json:"answers"` // {"A":"...","B":"..."}; null = hapus
+	Correct       *string         `json:"correct"`
+	Explanation   *string         `json:"explanation"`
+	IsActive      *bool           `json:"is_active"`
+}
+
+func (r ItemRequest) ToInput() svc.ItemInput {
+	return svc.ItemInput{
+		SubjectID:     r.SubjectID,
+		ClassParentID: r.ClassParentID,
+		Topic:         r.Topic,
+		Difficulty:    r.Difficulty,
+		Type:          r.Type,
+		Text:          r.Text,
+		Points:        r.Points,
+		Answers:       r.Answers,
+		Correct:       r.Correct,
+		Explanation:   r.Explanation,
+		IsActive:      r.IsActive,
+	}
+}
+
+/* =========================================================
+   QUIZ
+========================================================= */
+
+type ImportRequest struct {
+	ItemIDs []uuid.UUID `json:"item_ids"`
+	Mode    string      `json:"mode"` // copy | reference (default reference)
+}
+
+type DrawRuleRequest struct {
+	SubjectID     *uuid.UUID `json:"subject_id"`
+	ClassParentID *uuid.UUID `json:"class_parent_id"`
+	Topic         *string    `json:"topic"`
+	Difficulty    *string    `json:"difficulty"`
+	Count         int        `json:"count"`
+	Order         int        `json:"order"`
+}
+
+func (r DrawRuleRequest) ToInput() svc.RuleInput {
+	return svc.RuleInput{
+		SubjectID:     r.SubjectID,
+		ClassParentID: r.ClassParentID,
+		Topic:         r.Topic,
+		Difficulty:    r.Difficulty,
+		Count:         r.Count,
+		Order:         r.Order,
+	}
+}
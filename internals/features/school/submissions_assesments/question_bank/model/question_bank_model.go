@@ -0,0 +1,133 @@
+// file: internals/features/school/submissions_assesments/question_bank/model/question_bank_model.go
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+
+	qmodel "madinahsalam_backend/internals/features/school/submissions_assesments/quizzes/model"
+)
+
+/* =========================================================
+   ENUM
+   ========================================================= */
+
+const (
+	DifficultyEasy   = "easy"
+	DifficultyMedium = "medium"
+	DifficultyHard   = "hard"
+)
+
+func ValidDifficulty(s string) bool {
+	switch s {
+	case DifficultyEasy, DifficultyMedium, DifficultyHard:
+		return true
+	}
+	return false
+}
+
+/* =========================================================
+   question_bank_items — soal reusable tingkat sekolah
+   ========================================================= */
+
+type QuestionBankItemModel struct {
+	QuestionBankItemID       uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey;column:question_bank_item_id" json:"question_bank_item_id"`
+	QuestionBankItemSchoolID uuid.UUID `gorm:"type:uuid;not null;column:question_bank_item_school_id" json:"question_bank_item_school_id"`
+
+	// Tag
+	QuestionBankItemSubjectID     *uuid.UUID `gorm:"type:uuid;column:question_bank_item_subject_id" json:"question_bank_item_subject_id,omitempty"`
+	QuestionBankItemClassParentID *uuid.UUID `gorm:"type:uuid;column:question_bank_item_class_parent_id" json:"question_bank_item_class_parent_id,omitempty"`
+	QuestionBankItemTopic         *string    `gorm:"type:varchar(120);column:question_bank_item_topic" json:"question_bank_item_topic,omitempty"`
+	QuestionBankItemDifficulty    string     `gorm:"type:varchar(8);not null;default:'medium';column:question_bank_item_difficulty" json:"question_bank_item_difficulty"`
+
+	// Isi (bentuk sama dengan quiz_questions)
+	QuestionBankItemType        qmodel.QuizQuestionType `gorm:"type:varchar(8);not null;column:question_bank_item_type" json:"question_bank_item_type"`
+	QuestionBankItemText        string                  `gorm:"type:text;not null;column:question_bank_item_text" json:"question_bank_item_text"`
+	QuestionBankItemPoints      float64                 `gorm:"type:numeric(6,2);not null;default:1;column:question_bank_item_points" json:"question_bank_item_points"`
+	QuestionBankItemAnswers     datatypes.JSON          `gorm:"type:jsonb;column:question_bank_item_answers" json:"question_bank_item_answers,omitempty"`
+	QuestionBankItemCorrect     *string                 `gorm:"type:text;column:question_bank_item_correct" json:"question_bank_item_correct,omitempty"`
+	QuestionBankItemExplanation *string                 `gorm:"type:text;column:question_bank_item_explanation" json:"question_bank_item_explanation,omitempty"`
+
+	QuestionBankItemVersion            int            `gorm:"type:int;not null;default:1;column:question_bank_item_version" json:"question_bank_item_version"`
+	QuestionBankItemHistory            datatypes.JSON `gorm:"type:jsonb;not null;default:'[]'::jsonb;column:question_bank_item_history" json:"question_bank_item_history"`
+	QuestionBankItemIsActive           bool           `gorm:"not null;default:true;column:question_bank_item_is_active" json:"question_bank_item_is_active"`
+	QuestionBankItemCreatedByTeacherID *uuid.UUID     `gorm:"type:uuid;column:question_bank_item_created_by_teacher_id" json:"question_bank_item_created_by_teacher_id,omitempty"`
+
+	QuestionBankItemCreatedAt time.Time      `gorm:"type:timestamptz;not null;default:now();autoCreateTime;column:question_bank_item_created_at" json:"question_bank_item_created_at"`
+	QuestionBankItemUpdatedAt time.Time      `gorm:"type:timestamptz;not null;default:now();autoUpdateTime;column:question_bank_item_updated_at" json:"question_bank_item_updated_at"`
+	QuestionBankItemDeletedAt gorm.DeletedAt `gorm:"column:question_bank_item_deleted_at;index" json:"question_bank_item_deleted_at,omitempty"`
+}
+
+func (QuestionBankItemModel) TableName() string { return "question_bank_items" }
+
+// AsQuizQuestion: bentuk quiz_question dari soal bank (dipakai untuk validasi shape & import).
+func (m *QuestionBankItemModel) AsQuizQuestion(quizID uuid.UUID, mode string, isPool bool) *qmodel.QuizQuestionModel {
+	id := m.QuestionBankItemID
+	md := mode
+	q := &qmodel.QuizQuestionModel{
+		QuizQuestionQuizID:      quizID,
+		QuizQuestionSchoolID:    m.QuestionBankItemSchoolID,
+		QuizQuestionType:        m.QuestionBankItemType,
+		QuizQuestionText:        m.QuestionBankItemText,
+		QuizQuestionPoints:      m.QuestionBankItemPoints,
+		QuizQuestionAnswers:     m.QuestionBankItemAnswers,
+		QuizQuestionCorrect:     m.QuestionBankItemCorrect,
+		QuizQuestionExplanation: m.QuestionBankItemExplanation,
+		QuizQuestionHistory:     datatypes.JSON([]byte("[]")),
+		QuizQuestionVersion:     1,
+		QuizQuestionBankItemID:  &id,
+		QuizQuestionBankMode:    &md,
+		QuizQuestionIsPool:      isPool,
+	}
+	if len(q.QuizQuestionAnswers) == 0 {
+		q.QuizQuestionAnswers = nil
+	}
+	return q
+}
+
+// SameContent: apakah isi penilaian quiz_question masih sama dengan soal bank.
+func (m *QuestionBankItemModel) SameContent(q *qmodel.QuizQuestionModel) bool {
+	eqStr := func(a, b *string) bool {
+		if a == nil || b == nil {
+			return a == nil && b == nil
+		}
+		return strings.TrimSpace(*a) == strings.TrimSpace(*b)
+	}
+	return q.QuizQuestionType == m.QuestionBankItemType &&
+		q.QuizQuestionText == m.QuestionBankItemText &&
+		q.QuizQuestionPoints == m.QuestionBankItemPoints &&
+		string(q.QuizQuestionAnswers) == string(m.QuestionBankItemAnswers) &&
+		eqStr(q.QuizQuestionCorrect, m.QuestionBankItemCorrect) &&
+		eqStr(q.QuizQuestionExplanation, m.QuestionBankItemExplanation)
+}
+
+// AppendHistorySnapshot: simpan state lama ke history + naikkan version (pola sama dengan quiz_questions).
+func (m *QuestionBankItemModel) AppendHistorySnapshot() error {
+	var items []qmodel.QuizQuestionHistoryItem
+	if len(m.QuestionBankItemHistory) > 0 {
+		_ = json.Unmarshal(m.QuestionBankItemHistory, &items)
+	}
+	items = append(items, qmodel.QuizQuestionHistoryItem{
+		Version:     m.QuestionBankItemVersion,
+		SavedAt:     time.Now().UTC(),
+		ChangeKind:  "major",
+		Text:        m.QuestionBankItemText,
+		Answers:     json.RawMessage(m.QuestionBankItemAnswers),
+		Correct:     m.QuestionBankItemCorrect,
+		Explanation: m.QuestionBankItemExplanation,
+		Points:      m.QuestionBankItemPoints,
+	})
+	buf, err := json.Marshal(items)
+	if err != nil {
+		return fmt.Errorf("failed to marshal question_bank_item_history: %w", err)
+	}
+	m.QuestionBankItemHistory = datatypes.JSON(buf)
+	m.QuestionBankItemVersion++
+	return nil
+}
@@ -0,0 +1,50 @@
+// file: internals/features/school/submissions_assesments/question_bank/route/question_bank_route.go
+package route
+
+import (
+	qbController "madinahsalam_backend/internals/features/school/submissions_assesments/question_bank/controller"
+	schoolkuMiddleware "madinahsalam_backend/internals/middlewares/features"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// /api/u → bank soal sekolah + import / aturan tarik acak ke quiz (guru/DKM)
+func QuestionBankUserRoutes(api fiber.Router, db *gorm.DB) {
+	ctl := qbController.NewQuestionBankController(db)
+
+	r := api.Group("/question-bank")
+	r.Post("/quizzes/:id/import", ctl.ImportToQuiz)
+	r.Get("/quizzes/:id/draw-rules", ctl.ListDrawRules)
+	r.Post("/quizzes/:id/draw-rules", ctl.CreateDrawRule)
+	r.Put("/quizzes/:id/draw-rules/:rule_id", ctl.UpdateDrawRule)
+	r.Delete("/quizzes/:id/draw-rules/:rule_id", ctl.DeleteDrawRule)
+
+	r.Get("/", ctl.List)
+	r.Post("/", ctl.Create)
+	r.Get("/:id", ctl.Get)
+	r.Patch("/:id", ctl.Update)
+	r.Delete("/:id", ctl.Delete)
+}
+
+// /api/a/question-bank → kelola bank soal sekolah (permission grades.*)
+func QuestionBankAdminRoutes(api fiber.Router, db *gorm.DB) {
+	ctl := qbController.NewQuestionBankAdminController(db)
+
+	read := schoolkuMiddleware.RequirePermission("grades.read")
+	write := schoolkuMiddleware.RequirePermission("grades.write")
+	del := schoolkuMiddleware.RequirePermission("grades.delete")
+
+	r := api.Group("/question-bank")
+	r.Post("/quizzes/:id/import", write, ctl.ImportToQuiz)
+	r.Get("/quizzes/:id/draw-rules", read, ctl.ListDrawRules)
+	r.Post("/quizzes/:id/draw-rules", write, ctl.CreateDrawRule)
+	r.Put("/quizzes/:id/draw-rules/:rule_id", write, ctl.UpdateDrawRule)
+	r.Delete("/quizzes/:id/draw-rules/:rule_id", del, ctl.DeleteDrawRule)
+
+	r.Get("/", read, ctl.List)
+	r.Post("/", write, ctl.Create)
+	r.Get("/:id", read, ctl.Get)
+	r.Patch("/:id", write, ctl.Update)
+	r.Delete("/:id", del, ctl.Delete)
+}
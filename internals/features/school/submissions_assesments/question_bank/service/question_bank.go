@@ -0,0 +1,349 @@
+// file: internals/features/school/submissions_assesments/question_bank/service/question_bank.go
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	model "madinahsalam_backend/internals/features/school/submissions_assesments/question_bank/model"
+	qmodel "madinahsalam_backend/internals/features/school/submissions_assesments/quizzes/model"
+)
+
+var (
+	ErrItemNotFound       = errors.New("soal bank tidak ditemukan")
+	ErrItemInactive       = errors.New("soal bank tidak aktif")
+	ErrDifficultyInvalid  = errors.New("difficulty harus easy/medium/hard")
+	ErrSubjectInvalid     = errors.New("mapel tidak ditemukan di sekolah ini")
+	ErrClassParentInvalid = errors.New("class parent tidak ditemukan di sekolah ini")
+	ErrShapeInvalid       = errors.New("bentuk soal tidak valid")
+)
+
+func trimPtr(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	v := strings.TrimSpace(*s)
+	if v == "" {
+		return nil
+	}
+	return &v
+}
+
+type ItemInput struct {
+	SubjectID     *uuid.UUID // PATCH: uuid nol = lepas tag
+	ClassParentID *uuid.UUID // PATCH: uuid nol = lepas tag
+	Topic         *string
+	Difficulty    *string
+	Type          *string
+	Text          *string
+	Points        *float64
+	Answers       json.RawMessage // PATCH: "null" = hapus (essay)
+	Correct       *string         // PATCH: "" = hapus
+	Explanation   *string
+	IsActive      *bool
+}
+
+type ListFilter struct {
+	SubjectID     *uuid.UUID
+	ClassParentID *uuid.UUID
+	Topic         string
+	Difficulty    string
+	Type          string
+	Q             string
+	OnlyActive    bool
+	Offset        int
+	Limit         int
+}
+
+/* =========================================================
+   Validasi tag
+   ========================================================= */
+
+func checkTags(ctx context.Context, db *gorm.DB, schoolID uuid.UUID, subjectID, classParentID *uuid.UUID) error {
+	if subjectID != nil {
+		var n int64
+		if err := db.WithContext(ctx).Table("subjects").
+			Where("subject_id = ? AND subject_school_id = ? AND subject_deleted_at IS NULL", *subjectID, schoolID).
+			Count(&n).Error; err != nil {
+			return err
+		}
+		if n == 0 {
+			return ErrSubjectInvalid
+		}
+	}
+	if classParentID != nil {
+		var n int64
+		if err := db.WithContext(ctx).Table("class_parents").
+			Where("class_parent_id = ? AND class_parent_school_id = ? AND class_parent_deleted_at IS NULL", *classParentID, schoolID).
+			Count(&n).Error; err != nil {
+			return err
+		}
+		if n == 0 {
+			return ErrClassParentInvalid
+		}
+	}
+	return nil
+}
+
+// validateShape: pakai aturan yang sama dengan quiz_questions.
+func validateShape(m *model.QuestionBankItemModel) error {
+	if err := m.AsQuizQuestion(uuid.Nil, qmodel.QuizQuestionBankModeCopy, false).ValidateShape(); err != nil {
+		return errors.Join(ErrShapeInvalid, err)
+	}
+	return nil
+}
+
+/* =========================================================
+   CRUD
+   ========================================================= */
+
+func ListItems(ctx context.Context, db *gorm.DB, schoolID uuid.UUID, f ListFilter) ([]model.QuestionBankItemModel, int64, error) {
+	q := db.WithContext(ctx).Model(&model.QuestionBankItemModel{}).Where("question_bank_item_school_id = ?", schoolID)
+	if f.SubjectID != nil {
+		q = q.Where("question_bank_item_subject_id = ?", *f.SubjectID)
+	}
+	if f.ClassParentID != nil {
+		q = q.Where("question_bank_item_class_parent_id = ?", *f.ClassParentID)
+	}
+	if s := strings.TrimSpace(f.Topic); s != "" {
+		q = q.Where("LOWER(question_bank_item_topic) = LOWER(?)", s)
+	}
+	if s := strings.ToLower(strings.TrimSpace(f.Difficulty)); s != "" {
+		q = q.Where("question_bank_item_difficulty = ?", s)
+	}
+	if s := strings.ToLower(strings.TrimSpace(f.Type)); s != "" {
+		q = q.Where("question_bank_item_type = ?", s)
+	}
+	if s := strings.TrimSpace(f.Q); s != "" {
+		q = q.Where("question_bank_item_text ILIKE ?", "%"+s+"%")
+	}
+	if f.OnlyActive {
+		q = q.Where("question_bank_item_is_active = TRUE")
+	}
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var rows []model.QuestionBankItemModel
+	err := q.Order("question_bank_item_created_at DESC").Offset(f.Offset).Limit(f.Limit).Find(&rows).Error
+	return rows, total, err
+}
+
+func GetItem(ctx context.Context, db *gorm.DB, schoolID, id uuid.UUID) (*model.QuestionBankItemModel, error) {
+	var m model.QuestionBankItemModel
+	err := db.WithContext(ctx).
+		Where("question_bank_item_id = ? AND question_bank_item_school_id = ?", id, schoolID).
+		Take(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrItemNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func CreateItem(ctx context.Context, db *gorm.DB, schoolID uuid.UUID, in ItemInput, teacherID *uuid.UUID) (*model.QuestionBankItemModel, error) {
+	if in.SubjectID != nil && *in.SubjectID == uuid.Nil {
+		in.SubjectID = nil
+	}
+	if in.ClassParentID != nil && *in.ClassParentID == uuid.Nil {
+		in.ClassParentID = nil
+	}
+	if err := checkTags(ctx, db, schoolID, in.SubjectID, in.ClassParentID); err != nil {
+		return nil, err
+	}
+	m := &model.QuestionBankItemModel{
+		QuestionBankItemSchoolID:           schoolID,
+		QuestionBankItemSubjectID:          in.SubjectID,
+		QuestionBankItemClassParentID:      in.ClassParentID,
+		QuestionBankItemTopic:              trimPtr(in.Topic),
+		QuestionBankItemDifficulty:         model.DifficultyMedium,
+		QuestionBankItemPoints:             1,
+		QuestionBankItemVersion:            1,
+		QuestionBankItemHistory:            datatypes.JSON([]byte("[]")),
+		QuestionBankItemIsActive:           true,
+		QuestionBankItemCreatedByTeacherID: teacherID,
+	}
+	if err := applyContent(m, in); err != nil {
+		return nil, err
+	}
+	if in.IsActive != nil {
+		m.QuestionBankItemIsActive = *in.IsActive
+	}
+	if err := validateShape(m); err != nil {
+		return nil, err
+	}
+	if err := db.WithContext(ctx).Create(m).Error; err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// applyContent: isi field difficulty + konten dari input (hanya yang dikirim).
+func applyContent(m *model.QuestionBankItemModel, in ItemInput) error {
+	if in.Difficulty != nil {
+		d := strings.ToLower(strings.TrimSpace(*in.Difficulty))
+		if !model.ValidDifficulty(d) {
+			return ErrDifficultyInvalid
+		}
+		m.QuestionBankItemDifficulty = d
+	}
+	if in.Type != nil {
+		m.QuestionBankItemType = qmodel.QuizQuestionType(strings.ToLower(strings.TrimSpace(*in.Type)))
+	}
+	if in.Text != nil {
+		m.QuestionBankItemText = strings.TrimSpace(*in.Text)
+	}
+	if in.Points != nil {
+		m.QuestionBankItemPoints = *in.Points
+	}
+	if in.Answers != nil {
+		if s := strings.TrimSpace(string(in.Answers)); s == "" || s == "null" {
+			m.QuestionBankItemAnswers = nil
+		} else {
+			m.QuestionBankItemAnswers = datatypes.JSON(in.Answers)
+		}
+	}
+	if in.Correct != nil {
+		m.QuestionBankItemCorrect = trimPtr(in.Correct)
+	}
+	if in.Explanation != nil {
+		m.QuestionBankItemExplanation = trimPtr(in.Explanation)
+	}
+	return nil
+}
+
+// UpdateItem: perubahan isi menyimpan snapshot ke history + naik versi,
+// lalu soal quiz yang terhubung (mode reference / pool) ikut disinkron.
+func UpdateItem(ctx context.Context, db *gorm.DB, schoolID, id uuid.UUID, in ItemInput) (*model.QuestionBankItemModel, int, error) {
+	var out *model.QuestionBankItemModel
+	synced := 0
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var m model.QuestionBankItemModel
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("question_bank_item_id = ? AND question_bank_item_school_id = ?", id, schoolID).
+			Take(&m).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrItemNotFound
+			}
+			return err
+		}
+		before := m
+
+		if in.SubjectID != nil {
+			if *in.SubjectID == uuid.Nil {
+				m.QuestionBankItemSubjectID = nil
+			} else {
+				if err := checkTags(ctx, tx, schoolID, in.SubjectID, nil); err != nil {
+					return err
+				}
+				m.QuestionBankItemSubjectID = in.SubjectID
+			}
+		}
+		if in.ClassParentID != nil {
+			if *in.ClassParentID == uuid.Nil {
+				m.QuestionBankItemClassParentID = nil
+			} else {
+				if err := checkTags(ctx, tx, schoolID, nil, in.ClassParentID); err != nil {
+					return err
+				}
+				m.QuestionBankItemClassParentID = in.ClassParentID
+			}
+		}
+		if in.Topic != nil {
+			m.QuestionBankItemTopic = trimPtr(in.Topic)
+		}
+		if in.IsActive != nil {
+			m.QuestionBankItemIsActive = *in.IsActive
+		}
+		if err := applyContent(&m, in); err != nil {
+			return err
+		}
+		if err := validateShape(&m); err != nil {
+			return err
+		}
+
+		contentChanged := !before.SameContent(m.AsQuizQuestion(uuid.Nil, "", false))
+		if contentChanged {
+			// snapshot diambil dari state lama
+			snap := before
+			if err := snap.AppendHistorySnapshot(); err != nil {
+				return err
+			}
+			m.QuestionBankItemHistory = snap.QuestionBankItemHistory
+			m.QuestionBankItemVersion = snap.QuestionBankItemVersion
+		}
+
+		if err := tx.Save(&m).Error; err != nil {
+			return err
+		}
+		if contentChanged {
+			n, err := syncLinkedQuestions(tx, &m)
+			if err != nil {
+				return err
+			}
+			synced = n
+		}
+		out = &m
+		return nil
+	})
+	return out, synced, err
+}
+
+// syncLinkedQuestions: salin isi terbaru ke quiz_questions mode reference & pool.
+// Versi soal quiz ikut naik sehingga attempt lama tetap bisa dinilai ulang per versi.
+func syncLinkedQuestions(tx *gorm.DB, m *model.QuestionBankItemModel) (int, error) {
+	var rows []qmodel.QuizQuestionModel
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where(`quiz_question_school_id = ?
+			AND quiz_question_bank_item_id = ?
+			AND (quiz_question_bank_mode = ? OR quiz_question_is_pool = TRUE)
+			AND quiz_question_deleted_at IS NULL`,
+			m.QuestionBankItemSchoolID, m.QuestionBankItemID, qmodel.QuizQuestionBankModeReference).
+		Find(&rows).Error; err != nil {
+		return 0, err
+	}
+	n := 0
+	for i := range rows {
+		q := &rows[i]
+		if m.SameContent(q) {
+			continue
+		}
+		if err := q.AppendHistorySnapshot("major"); err != nil {
+			return n, err
+		}
+		q.QuizQuestionType = m.QuestionBankItemType
+		q.QuizQuestionText = m.QuestionBankItemText
+		q.QuizQuestionPoints = m.QuestionBankItemPoints
+		q.QuizQuestionAnswers = m.QuestionBankItemAnswers
+		q.QuizQuestionCorrect = m.QuestionBankItemCorrect
+		q.QuizQuestionExplanation = m.QuestionBankItemExplanation
+		if err := tx.Save(q).Error; err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// DeleteItem: soft delete. Soal quiz yang sudah di-import tetap ada (jadi salinan lepas).
+func DeleteItem(ctx context.Context, db *gorm.DB, schoolID, id uuid.UUID) error {
+	res := db.WithContext(ctx).
+		Where("question_bank_item_id = ? AND question_bank_item_school_id = ?", id, schoolID).
+		Delete(&model.QuestionBankItemModel{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrItemNotFound
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+// file: internals/features/school/submissions_assesments/question_bank/service/quiz_import.go
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	model "madinahsalam_backend/internals/features/school/submissions_assesments/question_bank/model"
+	qmodel "madinahsalam_backend/internals/features/school/submissions_assesments/quizzes/model"
+)
+
+var (
+	ErrQuizNotFound     = errors.New("quiz tidak ditemukan")
+	ErrModeInvalid      = errors.New("mode harus copy/reference")
+	ErrNoItems          = errors.New("item_ids wajib diisi")
+	ErrRuleNotFound     = errors.New("aturan tarik soal tidak ditemukan")
+	ErrRuleCountInvalid = errors.New("count harus > 0")
+)
+
+func loadQuiz(ctx context.Context, db *gorm.DB, schoolID, quizID uuid.UUID) (*qmodel.QuizModel, error) {
+	var q qmodel.QuizModel
+	err := db.WithContext(ctx).
+		Where("quiz_id = ? AND quiz_school_id = ?", quizID, schoolID).
+		Take(&q).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrQuizNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &q, nil
+}
+
+/* =========================================================
+   Import soal bank → quiz (set soal tetap)
+   ========================================================= */
+
+type ImportResult struct {
+	Imported []qmodel.QuizQuestionModel `json:"imported"`
+	Skipped  []uuid.UUID                `json:"skipped"` // sudah ada di quiz / tidak aktif
+}
+
+func ImportToQuiz(ctx context.Context, db *gorm.DB, schoolID, quizID uuid.UUID, itemIDs []uuid.UUID, mode string) (*ImportResult, error) {
+	mode = strings.ToLower(strings.TrimSpace(mode))
+	if mode == "" {
+		mode = qmodel.QuizQuestionBankModeReference
+	}
+	if mode != qmodel.QuizQuestionBankModeCopy && mode != qmodel.QuizQuestionBankModeReference {
+		return nil, ErrModeInvalid
+	}
+	if len(itemIDs) == 0 {
+		return nil, ErrNoItems
+	}
+
+	res := &ImportResult{Imported: []qmodel.QuizQuestionModel{}, Skipped: []uuid.UUID{}}
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := loadQuiz(ctx, tx, schoolID, quizID); err != nil {
+			return err
+		}
+
+		var items []model.QuestionBankItemModel
+		if err := tx.
+			Where("question_bank_item_school_id = ? AND question_bank_item_id IN ?", schoolID, itemIDs).
+			Find(&items).Error; err != nil {
+			return err
+		}
+		byID := make(map[uuid.UUID]*model.QuestionBankItemModel, len(items))
+		for i := range items {
+			byID[items[i].QuestionBankItemID] = &items[i]
+		}
+
+		// soal bank yang sudah jadi soal tetap di quiz ini
+		var existing []uuid.UUID
+		if err := tx.Model(&qmodel.QuizQuestionModel{}).
+			Where(`quiz_question_quiz_id = ?
+				AND quiz_question_bank_item_id IS NOT NULL
+				AND quiz_question_is_pool = FALSE
+				AND quiz_question_deleted_at IS NULL`, quizID).
+			Pluck("quiz_question_bank_item_id", &existing).Error; err != nil {
+			return err
+		}
+		seen := make(map[uuid.UUID]bool, len(existing)+len(itemIDs))
+		for _, id := range existing {
+			seen[id] = true
+		}
+
+		for _, id := range itemIDs {
+			it, ok := byID[id]
+			if !ok {
+				return ErrItemNotFound
+			}
+			if seen[id] || !it.QuestionBankItemIsActive {
+				res.Skipped = append(res.Skipped, id)
+				continue
+			}
+			seen[id] = true
+			q := it.AsQuizQuestion(quizID, mode, false)
+			if err := tx.Create(q).Error; err != nil {
+				return err
+			}
+			res.Imported = append(res.Imported, *q)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+/* =========================================================
+   Aturan tarik acak (quiz_draw_rules)
+   ========================================================= */
+
+type RuleInput struct {
+	SubjectID     *uuid.UUID
+	ClassParentID *uuid.UUID
+	Topic         *string
+	Difficulty    *string
+	Count         int
+	Order         int
+}
+
+// RuleWithAvailable: aturan + jumlah soal bank aktif yang cocok.
+type RuleWithAvailable struct {
+	qmodel.QuizDrawRuleModel
+	Available int64 `json:"available"`
+}
+
+// MatchRule: filter soal bank aktif sesuai tag aturan.
+func MatchRule(q *gorm.DB, r *qmodel.QuizDrawRuleModel) *gorm.DB {
+	q = q.Where("question_bank_item_school_id = ? AND question_bank_item_is_active = TRUE", r.QuizDrawRuleSchoolID)
+	if r.QuizDrawRuleSubjectID != nil {
+		q = q.Where("question_bank_item_subject_id = ?", *r.QuizDrawRuleSubjectID)
+	}
+	if r.QuizDrawRuleClassParentID != nil {
+		q = q.Where("question_bank_item_class_parent_id = ?", *r.QuizDrawRuleClassParentID)
+	}
+	if r.QuizDrawRuleTopic != nil {
+		q = q.Where("LOWER(question_bank_item_topic) = LOWER(?)", *r.QuizDrawRuleTopic)
+	}
+	if r.QuizDrawRuleDifficulty != nil {
+		q = q.Where("question_bank_item_difficulty = ?", *r.QuizDrawRuleDifficulty)
+	}
+	return q
+}
+
+func ListRules(ctx context.Context, db *gorm.DB, schoolID, quizID uuid.UUID) ([]RuleWithAvailable, error) {
+	if _, err := loadQuiz(ctx, db, schoolID, quizID); err != nil {
+		return nil, err
+	}
+	var rules []qmodel.QuizDrawRuleModel
+	if err := db.WithContext(ctx).
+		Where("quiz_draw_rule_quiz_id = ? AND quiz_draw_rule_school_id = ?", quizID, schoolID).
+		Order("quiz_draw_rule_order ASC, quiz_draw_rule_created_at ASC").
+		Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	out := make([]RuleWithAvailable, 0, len(rules))
+	for i := range rules {
+		var n int64
+		if err := MatchRule(db.WithContext(ctx).Model(&model.QuestionBankItemModel{}), &rules[i]).
+			Count(&n).Error; err != nil {
+			return nil, err
+		}
+		out = append(out, RuleWithAvailable{QuizDrawRuleModel: rules[i], Available: n})
+	}
+	return out, nil
+}
+
+func buildRule(ctx context.Context, db *gorm.DB, r *qmodel.QuizDrawRuleModel, in RuleInput) error {
+	if in.Count <= 0 {
+		return ErrRuleCountInvalid
+	}
+	if in.SubjectID != nil && *in.SubjectID == uuid.Nil {
+		in.SubjectID = nil
+	}
+	if in.ClassParentID != nil && *in.ClassParentID == uuid.Nil {
+		in.ClassParentID = nil
+	}
+	if err := checkTags(ctx, db, r.QuizDrawRuleSchoolID, in.SubjectID, in.ClassParentID); err != nil {
+		return err
+	}
+	var diff *string
+	if d := trimPtr(in.Difficulty); d != nil {
+		v := strings.ToLower(*d)
+		if !model.ValidDifficulty(v) {
+			return ErrDifficultyInvalid
+		}
+		diff = &v
+	}
+	r.QuizDrawRuleSubjectID = in.SubjectID
+	r.QuizDrawRuleClassParentID = in.ClassParentID
+	r.QuizDrawRuleTopic = trimPtr(in.Topic)
+	r.QuizDrawRuleDifficulty = diff
+	r.QuizDrawRuleCount = in.Count
+	r.QuizDrawRuleOrder = in.Order
+	return nil
+}
+
+func CreateRule(ctx context.Context, db *gorm.DB, schoolID, quizID uuid.UUID, in RuleInput) (*qmodel.QuizDrawRuleModel, error) {
+	if _, err := loadQuiz(ctx, db, schoolID, quizID); err != nil {
+		return nil, err
+	}
+	r := &qmodel.QuizDrawRuleModel{QuizDrawRuleSchoolID: schoolID, QuizDrawRuleQuizID: quizID}
+	if err := buildRule(ctx, db, r, in); err != nil {
+		return nil, err
+	}
+	if err := db.WithContext(ctx).Create(r).Error; err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// UpdateRule: aturan dikirim lengkap (PUT). Attempt yang sudah berjalan tetap memakai set soalnya.
+func UpdateRule(ctx context.Context, db *gorm.DB, schoolID, quizID, ruleID uuid.UUID, in RuleInput) (*qmodel.QuizDrawRuleModel, error) {
+	var r qmodel.QuizDrawRuleModel
+	err := db.WithContext(ctx).
+		Where("quiz_draw_rule_id = ? AND quiz_draw_rule_quiz_id = ? AND quiz_draw_rule_school_id = ?", ruleID, quizID, schoolID).
+		Take(&r).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRuleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := buildRule(ctx, db, &r, in); err != nil {
+		return nil, err
+	}
+	if err := db.WithContext(ctx).Save(&r).Error; err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func DeleteRule(ctx context.Context, db *gorm.DB, schoolID, quizID, ruleID uuid.UUID) error {
+	res := db.WithContext(ctx).
+		Where("quiz_draw_rule_id = ? AND quiz_draw_rule_quiz_id = ? AND quiz_draw_rule_school_id = ?", ruleID, quizID, schoolID).
+		Delete(&qmodel.QuizDrawRuleModel{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrRuleNotFound
+	}
+	return nil
+}
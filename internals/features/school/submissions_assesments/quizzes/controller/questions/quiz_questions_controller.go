@@ -42,6 +42,8 @@ func NewQuizQuestionsController(db *gorm.DB) *QuizQuestionsController {
 
 func (ctl *QuizQuestionsController) applyFilters(db *gorm.DB, schoolID uuid.UUID, quizID *uuid.UUID, qType string, q string) *gorm.DB {
 	db = db.Where("quiz_question_school_id = ? AND quiz_question_deleted_at IS NULL", schoolID)
+	// soal pool (tarikan acak bank soal) bukan bagian set soal tetap quiz
+	db = db.Where("quiz_question_is_pool = FALSE")
 	if quizID != nil && *quizID != uuid.Nil {
 		db = db.Where("quiz_question_quiz_id = ?", *quizID)
 	}
@@ -289,6 +291,12 @@ func (ctl *QuizQuestionsController) Patch(c *fiber.Ctx) error {
 		return helper.JsonError(c, fiber.StatusBadRequest, err.Error())
 	}
 
+	// Soal bank mode reference yang diedit lokal → jadi salinan lepas (tidak ditimpa sinkron bank soal)
+	if !m.QuizQuestionIsPool && m.QuizQuestionBankMode != nil && *m.QuizQuestionBankMode == qmodel.QuizQuestionBankModeReference {
+		mode := qmodel.QuizQuestionBankModeCopy
+		m.QuizQuestionBankMode = &mode
+	}
+
 	// Jika quiz_id berubah, validasi quiz baru milik tenant
 	if req.QuizQuestionQuizID.ShouldUpdate() && !req.QuizQuestionQuizID.IsNull() {
 		newQID := req.QuizQuestionQuizID.Val()
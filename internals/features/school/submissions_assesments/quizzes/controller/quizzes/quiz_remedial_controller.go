@@ -0,0 +1,88 @@
+// file: internals/features/school/submissions_assesments/quizzes/controller/quizzes/quiz_remedial_controller.go
+package controller
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	qbsvc "madinahsalam_backend/internals/features/school/submissions_assesments/question_bank/service"
+	dto "madinahsalam_backend/internals/features/school/submissions_assesments/quizzes/dto"
+	qservice "madinahsalam_backend/internals/features/school/submissions_assesments/quizzes/service"
+	helper "madinahsalam_backend/internals/helpers"
+)
+
+/*
+Remedial otomatis (guru/DKM)
+
+POST /api/t/quizzes-teacher/:id/remedials              {"mode":"clone|assemble","policy":"cap_kkm|max|average","passing_percent","student_ids","question_ids","bank_item_ids","title","time_limit_sec","publish","dry_run"}
+GET  /api/t/quizzes-teacher/:id/remedial-participants  peserta quiz remedial + nilai asal / remedial / akhir
+
+:id pada POST = quiz induk (quiz utama atau remedial ronde sebelumnya).
+*/
+
+func remedialError(c *fiber.Ctx, err error) error {
+	var fe *fiber.Error
+	switch {
+	case errors.As(err, &fe):
+		return helper.JsonError(c, fe.Code, fe.Message)
+	case errors.Is(err, qservice.ErrRemedialQuizNotFound):
+		return helper.JsonError(c, fiber.StatusNotFound, err.Error())
+	case errors.Is(err, qservice.ErrRemedialNoStudents):
+		return helper.JsonError(c, fiber.StatusConflict, err.Error())
+	case errors.Is(err, qservice.ErrRemedialModeInvalid), errors.Is(err, qservice.ErrRemedialPolicyInvalid),
+		errors.Is(err, qservice.ErrRemedialNoPassing), errors.Is(err, qservice.ErrRemedialPassingRange),
+		errors.Is(err, qservice.ErrRemedialStudentInvalid), errors.Is(err, qservice.ErrRemedialNoQuestions),
+		errors.Is(err, qservice.ErrRemedialQuestionInvalid), errors.Is(err, qservice.ErrQuestionSetEmpty),
+		errors.Is(err, qbsvc.ErrItemNotFound):
+		return helper.JsonError(c, fiber.StatusBadRequest, err.Error())
+	}
+	return helper.JsonError(c, fiber.StatusInternalServerError, err.Error())
+}
+
+// POST /quizzes/:id/remedials
+func (ctrl *QuizController) CreateRemedial(c *fiber.Ctx) error {
+	id, err := uuid.Parse(strings.TrimSpace(c.Params("id")))
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "ID tidak valid")
+	}
+	mid, err := resolveSchoolForDKMOrTeacher(c, ctrl.DB)
+	if err != nil {
+		return remedialError(c, err)
+	}
+
+	var req dto.CreateRemedialRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return helper.JsonError(c, fiber.StatusBadRequest, "Payload tidak valid")
+		}
+	}
+
+	res, err := qservice.NewQuizRemedialService(ctrl.DB).CreateRemedial(c.Context(), mid, id, req.ToInput())
+	if err != nil {
+		return remedialError(c, err)
+	}
+	if res.DryRun {
+		return helper.JsonOK(c, "Kandidat remedial (tidak disimpan)", res)
+	}
+	return helper.JsonCreated(c, "Quiz remedial dibuat", res)
+}
+
+// GET /quizzes/:id/remedial-participants
+func (ctrl *QuizController) RemedialParticipants(c *fiber.Ctx) error {
+	id, err := uuid.Parse(strings.TrimSpace(c.Params("id")))
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "ID tidak valid")
+	}
+	mid, err := resolveSchoolForDKMOrTeacher(c, ctrl.DB)
+	if err != nil {
+		return remedialError(c, err)
+	}
+	rows, err := qservice.NewQuizRemedialService(ctrl.DB).ListParticipants(c.Context(), mid, id)
+	if err != nil {
+		return remedialError(c, err)
+	}
+	return helper.JsonOK(c, "OK", rows)
+}
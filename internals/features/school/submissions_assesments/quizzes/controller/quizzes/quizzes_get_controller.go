@@ -147,6 +147,18 @@ func (ctrl *QuizController) List(c *fiber.Ctx) error {
 	dbq := ctrl.DB.WithContext(c.Context()).Model(&model.QuizModel{})
 	dbq = applyFiltersQuizzes(dbq, &q)
 
+	// Siswa: quiz remedial berpeserta hanya terlihat oleh pesertanya
+	if helperAuth.IsStudent(c) && !helperAuth.IsTeacherInSchool(c, schoolID) && !helperAuth.IsDKMInSchool(c, schoolID) {
+		sid, _ := helperAuth.GetSchoolStudentIDForSchool(c, schoolID)
+		dbq = dbq.Where(`(
+			quiz_is_remedial = FALSE
+			OR NOT EXISTS (SELECT 1 FROM quiz_remedial_participants p WHERE p.quiz_remedial_participant_quiz_id = quizzes.quiz_id)
+			OR EXISTS (SELECT 1 FROM quiz_remedial_participants p
+			           WHERE p.quiz_remedial_participant_quiz_id = quizzes.quiz_id
+			             AND p.quiz_remedial_participant_student_id = ?)
+		)`, sid)
+	}
+
 	// 7) Count
 	var total int64
 	if err := dbq.Count(&total).Error; err != nil {
@@ -181,6 +193,7 @@ func (ctrl *QuizController) List(c *fiber.Ctx) error {
 			Where("quiz_question_school_id = ?", schoolID).
 			Where("quiz_question_quiz_id IN ?", quizIDs).
 			Where("quiz_question_deleted_at IS NULL").
+			Where("quiz_question_is_pool = FALSE").
 			Order("quiz_question_created_at ASC").
 			Find(&allQuestions).Error; err != nil {
 
@@ -0,0 +1,157 @@
+// file: internals/features/school/submissions_assesments/quizzes/controller/student_attempts/quiz_attempt_session_controller.go
+package controller
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	qdto "madinahsalam_backend/internals/features/school/submissions_assesments/quizzes/dto"
+	qservice "madinahsalam_backend/internals/features/school/submissions_assesments/quizzes/service"
+	helper "madinahsalam_backend/internals/helpers"
+)
+
+/*
+Sesi attempt (waktu dari server) & sinyal integritas
+
+POST /api/u/quizzes/attempts                                  tanpa items → mulai attempt; respons berisi
+                                                              student_quiz_attempt_session {token, started_at, deadline_at, server_now}
+POST /api/u/quizzes/attempts/:id/questions/:question_id/open  {"session_token"}          strict mode: timer soal mulai
+PUT  /api/u/quizzes/attempts/:id/answers/:question_id         {"session_token","answer"} jawaban draft
+POST /api/u/quizzes/attempts/:id/events                       {"session_token","events":[{"kind","quiz_question_id","client_at","meta"}]}
+POST /api/u/quizzes/attempts                                  dengan items + "session_token" → submit
+
+Submit lewat deadline (+30 detik toleransi) → jawaban kiriman ditolak, dinilai dari draft.
+Attempt yang tidak disubmit di-auto-submit worker.
+
+GET  /api/u/quizzes-teacher/attempts-teacher/:id/events?attempt_no=   ringkasan per attempt (guru/DKM)
+*/
+
+func sessionError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, qservice.ErrSessionNotFound), errors.Is(err, qservice.ErrGradeAttemptNotFound):
+		return helper.JsonError(c, fiber.StatusNotFound, err.Error())
+	case errors.Is(err, qservice.ErrSessionTokenInvalid):
+		return helper.JsonError(c, fiber.StatusForbidden, err.Error())
+	case errors.Is(err, qservice.ErrAttemptExpired), errors.Is(err, qservice.ErrQuestionTimeUp),
+		errors.Is(err, qservice.ErrAttemptConflict):
+		return helper.JsonError(c, fiber.StatusConflict, err.Error())
+	case errors.Is(err, qservice.ErrSessionRequired), errors.Is(err, qservice.ErrQuestionNotOpened),
+		errors.Is(err, qservice.ErrQuestionNotInSet), errors.Is(err, qservice.ErrEventKindInvalid),
+		errors.Is(err, qservice.ErrTooManyEvents):
+		return helper.JsonError(c, fiber.StatusBadRequest, err.Error())
+	}
+	return gradingError(c, err)
+}
+
+func questionParam(c *fiber.Ctx) (uuid.UUID, error) {
+	id, err := uuid.Parse(strings.TrimSpace(c.Params("question_id")))
+	if err != nil {
+		return uuid.Nil, fiber.NewError(fiber.StatusBadRequest, "question_id tidak valid")
+	}
+	return id, nil
+}
+
+// POST /attempts/:id/questions/:question_id/open
+func (ctl *StudentQuizAttemptsController) OpenQuestion(c *fiber.Ctx) error {
+	m, err := ctl.loadOwnAttempt(c)
+	if err != nil {
+		return sessionError(c, err)
+	}
+	qid, err := questionParam(c)
+	if err != nil {
+		return sessionError(c, err)
+	}
+	var req qdto.AttemptSessionTokenRequest
+	if err := c.BodyParser(&req); err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "Payload tidak valid")
+	}
+	now := time.Now().UTC()
+	q, err := qservice.NewQuizAttemptSessionService(ctl.DB).
+		OpenQuestion(c.Context(), m.StudentQuizAttemptID, strings.TrimSpace(req.SessionToken), qid, now)
+	if err != nil {
+		return sessionError(c, err)
+	}
+	return helper.JsonOK(c, "OK", fiber.Map{
+		"quiz_question_id": qid,
+		"question":         q,
+		"server_now":       now,
+	})
+}
+
+// PUT /attempts/:id/answers/:question_id
+func (ctl *StudentQuizAttemptsController) SaveAnswer(c *fiber.Ctx) error {
+	m, err := ctl.loadOwnAttempt(c)
+	if err != nil {
+		return sessionError(c, err)
+	}
+	qid, err := questionParam(c)
+	if err != nil {
+		return sessionError(c, err)
+	}
+	var req qdto.SaveAttemptAnswerRequest
+	if err := c.BodyParser(&req); err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "Payload tidak valid")
+	}
+	now := time.Now().UTC()
+	q, err := qservice.NewQuizAttemptSessionService(ctl.DB).
+		SaveAnswer(c.Context(), m.StudentQuizAttemptID, strings.TrimSpace(req.SessionToken), qid, strings.TrimSpace(req.Answer), now)
+	if err != nil {
+		return sessionError(c, err)
+	}
+	return helper.JsonUpdated(c, "Jawaban disimpan", fiber.Map{
+		"quiz_question_id": qid,
+		"question":         q,
+		"server_now":       now,
+	})
+}
+
+// POST /attempts/:id/events
+func (ctl *StudentQuizAttemptsController) RecordEvents(c *fiber.Ctx) error {
+	m, err := ctl.loadOwnAttempt(c)
+	if err != nil {
+		return sessionError(c, err)
+	}
+	var req qdto.RecordAttemptEventsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "Payload tidak valid")
+	}
+	n, err := qservice.NewQuizAttemptSessionService(ctl.DB).
+		RecordEvents(c.Context(), m.StudentQuizAttemptID, strings.TrimSpace(req.SessionToken), req.ToInput(), time.Now().UTC())
+	if err != nil {
+		return sessionError(c, err)
+	}
+	return helper.JsonCreated(c, "Event dicatat", fiber.Map{"recorded": n})
+}
+
+// GET /quizzes-teacher/attempts-teacher/:id/events
+func (ctl *StudentQuizAttemptsController) EventLog(c *fiber.Ctx) error {
+	mid, err := ctl.resolveGraderSchool(c)
+	if err != nil {
+		return sessionError(c, err)
+	}
+	id, err := uuid.Parse(strings.TrimSpace(c.Params("id")))
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "id tidak valid")
+	}
+	var attemptNo *int
+	if v := strings.TrimSpace(c.Query("attempt_no")); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return helper.JsonError(c, fiber.StatusBadRequest, "attempt_no tidak valid")
+		}
+		attemptNo = &n
+	}
+	rows, err := qservice.AttemptEventLog(c.Context(), ctl.DB, mid, id, attemptNo)
+	if err != nil {
+		return sessionError(c, err)
+	}
+	return helper.JsonOK(c, "OK", fiber.Map{
+		"student_quiz_attempt_id": id,
+		"attempts":                rows,
+	})
+}
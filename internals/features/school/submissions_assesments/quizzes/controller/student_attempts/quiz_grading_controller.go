@@ -0,0 +1,154 @@
+// file: internals/features/school/submissions_assesments/quizzes/controller/student_attempts/quiz_grading_controller.go
+package controller
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	qdto "madinahsalam_backend/internals/features/school/submissions_assesments/quizzes/dto"
+	qservice "madinahsalam_backend/internals/features/school/submissions_assesments/quizzes/service"
+	helper "madinahsalam_backend/internals/helpers"
+	helperAuth "madinahsalam_backend/internals/helpers/auth"
+)
+
+/*
+Grading essay & regrade (guru/DKM/owner)
+
+GET  /api/u/quizzes-teacher/attempts-teacher/essay-queue?quiz_id=&assessment_id=&student_id=&page=&per_page=
+POST /api/u/quizzes-teacher/attempts-teacher/essay-grades   {"grades":[{"student_quiz_attempt_id","attempt_no","quiz_question_id","points_earned","feedback"}]}
+POST /api/u/quizzes-teacher/:id/regrade                      {"quiz_question_id"?, "dry_run"}
+*/
+
+// resolveGraderSchool: school dari token (prefer teacher) + wajib guru/DKM/owner.
+func (ctl *StudentQuizAttemptsController) resolveGraderSchool(c *fiber.Ctx) (uuid.UUID, error) {
+	mid, err := helperAuth.GetSchoolIDFromTokenPreferTeacher(c)
+	if err != nil {
+		return uuid.Nil, fiber.NewError(fiber.StatusUnauthorized, err.Error())
+	}
+	if !helperAuth.IsOwner(c) && !helperAuth.IsDKMInSchool(c, mid) && !helperAuth.IsTeacherInSchool(c, mid) {
+		return uuid.Nil, fiber.NewError(fiber.StatusForbidden, "Hanya guru/DKM yang boleh menilai")
+	}
+	return mid, nil
+}
+
+func gradingError(c *fiber.Ctx, err error) error {
+	var fe *fiber.Error
+	switch {
+	case errors.As(err, &fe):
+		return helper.JsonError(c, fe.Code, fe.Message)
+	case errors.Is(err, qservice.ErrGradeAttemptNotFound), errors.Is(err, qservice.ErrRegradeQuizNotFound),
+		errors.Is(err, qservice.ErrAnalysisQuizNotFound):
+		return helper.JsonError(c, fiber.StatusNotFound, err.Error())
+	case errors.Is(err, qservice.ErrGradeAttemptNoMissing), errors.Is(err, qservice.ErrGradeItemNotFound),
+		errors.Is(err, qservice.ErrGradeNotEssay), errors.Is(err, qservice.ErrGradePointsRange),
+		errors.Is(err, qservice.ErrGradeEmpty), errors.Is(err, qservice.ErrAnalysisBasisInvalid):
+		return helper.JsonError(c, fiber.StatusBadRequest, err.Error())
+	}
+	return helper.JsonError(c, fiber.StatusInternalServerError, err.Error())
+}
+
+func optUUIDQuery(c *fiber.Ctx, key string) (*uuid.UUID, error) {
+	v := strings.TrimSpace(c.Query(key))
+	if v == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(v)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, key+" tidak valid")
+	}
+	return &id, nil
+}
+
+// GET /quizzes-teacher/attempts-teacher/essay-queue
+func (ctl *StudentQuizAttemptsController) EssayQueue(c *fiber.Ctx) error {
+	mid, err := ctl.resolveGraderSchool(c)
+	if err != nil {
+		return gradingError(c, err)
+	}
+
+	var f qservice.EssayQueueFilter
+	for key, dst := range map[string]**uuid.UUID{
+		"quiz_id":       &f.QuizID,
+		"assessment_id": &f.AssessmentID,
+		"student_id":    &f.StudentID,
+	} {
+		if *dst, err = optUUIDQuery(c, key); err != nil {
+			return gradingError(c, err)
+		}
+	}
+
+	pg := helper.ResolvePaging(c, 20, 100)
+	f.Offset, f.Limit = pg.Offset, pg.Limit
+
+	rows, total, err := qservice.NewQuizGradingService(ctl.DB).EssayQueue(c.Context(), mid, f)
+	if err != nil {
+		return gradingError(c, err)
+	}
+	return helper.JsonList(c, "OK", rows, helper.BuildPaginationFromPage(int64(total), pg.Page, pg.PerPage))
+}
+
+// POST /quizzes-teacher/attempts-teacher/essay-grades
+func (ctl *StudentQuizAttemptsController) GradeEssays(c *fiber.Ctx) error {
+	ctl.ensureValidator()
+
+	mid, err := ctl.resolveGraderSchool(c)
+	if err != nil {
+		return gradingError(c, err)
+	}
+
+	var req qdto.GradeEssaysRequest
+	if err := c.BodyParser(&req); err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "Payload tidak valid")
+	}
+	if err := ctl.validator.Struct(&req); err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "Validasi gagal: "+err.Error())
+	}
+
+	var teacherID *uuid.UUID
+	if tid, err := helperAuth.GetSchoolTeacherIDForSchool(c, mid); err == nil && tid != uuid.Nil {
+		teacherID = &tid
+	}
+
+	res, err := qservice.NewQuizGradingService(ctl.DB).GradeEssays(c.Context(), mid, req.ToInput(), teacherID, time.Now().UTC())
+	if err != nil {
+		return gradingError(c, err)
+	}
+	return helper.JsonUpdated(c, "Nilai essay disimpan", res)
+}
+
+// POST /quizzes-teacher/:id/regrade
+func (ctl *StudentQuizAttemptsController) Regrade(c *fiber.Ctx) error {
+	mid, err := ctl.resolveGraderSchool(c)
+	if err != nil {
+		return gradingError(c, err)
+	}
+	quizID, err := uuid.Parse(strings.TrimSpace(c.Params("id")))
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "quiz_id tidak valid")
+	}
+
+	var req qdto.RegradeQuizRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return helper.JsonError(c, fiber.StatusBadRequest, "Payload tidak valid")
+		}
+	}
+
+	rep, err := qservice.NewQuizGradingService(ctl.DB).Regrade(c.Context(), mid, qservice.RegradeInput{
+		QuizID:         quizID,
+		QuizQuestionID: req.QuizQuestionID,
+		DryRun:         req.DryRun,
+	}, time.Now().UTC())
+	if err != nil {
+		return gradingError(c, err)
+	}
+	msg := "Regrade selesai"
+	if rep.DryRun {
+		msg = "Simulasi regrade (tidak disimpan)"
+	}
+	return helper.JsonOK(c, msg, rep)
+}
@@ -0,0 +1,119 @@
+// file: internals/features/school/submissions_assesments/quizzes/controller/student_attempts/quiz_item_analysis_controller.go
+package controller
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	qservice "madinahsalam_backend/internals/features/school/submissions_assesments/quizzes/service"
+	helper "madinahsalam_backend/internals/helpers"
+)
+
+/*
+Analisis butir soal (guru/DKM/owner)
+
+GET /api/t/quizzes-teacher/:id/item-analysis?basis=first|last|best
+GET /api/t/quizzes-teacher/:id/item-analysis?basis=&format=csv    → unduh CSV (1 baris = 1 soal + ringkasan)
+*/
+
+// GET /quizzes-teacher/:id/item-analysis
+func (ctl *StudentQuizAttemptsController) ItemAnalysis(c *fiber.Ctx) error {
+	mid, err := ctl.resolveGraderSchool(c)
+	if err != nil {
+		return gradingError(c, err)
+	}
+	quizID, err := uuid.Parse(strings.TrimSpace(c.Params("id")))
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "quiz_id tidak valid")
+	}
+
+	rep, err := qservice.NewQuizGradingService(ctl.DB).ItemAnalysis(c.Context(), mid, quizID, c.Query("basis"), time.Now().UTC())
+	if err != nil {
+		return gradingError(c, err)
+	}
+
+	if strings.EqualFold(strings.TrimSpace(c.Query("format")), "csv") {
+		buf, err := itemAnalysisCSV(rep)
+		if err != nil {
+			return helper.JsonError(c, fiber.StatusInternalServerError, "Gagal membuat CSV")
+		}
+		c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+		c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="item-analysis-%s-%s.csv"`, quizID, rep.Basis))
+		return c.Send(buf)
+	}
+	return helper.JsonOK(c, "OK", rep)
+}
+
+func fmtOptFloat(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', 3, 64)
+}
+
+func itemAnalysisCSV(rep *qservice.ItemAnalysisReport) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("\xEF\xBB\xBF") // BOM supaya Excel membaca UTF-8
+	w := csv.NewWriter(&buf)
+
+	_ = w.Write([]string{
+		"no", "quiz_question_id", "type", "text", "points", "correct",
+		"respondents", "ungraded", "p_value", "difficulty",
+		"upper_p", "lower_p", "discrimination", "discrimination_label",
+		"options", "blank", "flags",
+	})
+	for _, it := range rep.Items {
+		correct := ""
+		if it.Correct != nil {
+			correct = *it.Correct
+		}
+		opts := make([]string, 0, len(it.Options))
+		for _, o := range it.Options {
+			opts = append(opts, fmt.Sprintf("%s=%d (atas %d, bawah %d)", o.Key, o.Count, o.Upper, o.Lower))
+		}
+		_ = w.Write([]string{
+			strconv.Itoa(it.No),
+			it.QuizQuestionID.String(),
+			string(it.Type),
+			it.Text,
+			strconv.FormatFloat(it.Points, 'f', -1, 64),
+			correct,
+			strconv.Itoa(it.Respondents),
+			strconv.Itoa(it.Ungraded),
+			fmtOptFloat(it.PValue),
+			it.DifficultyLabel,
+			fmtOptFloat(it.UpperP),
+			fmtOptFloat(it.LowerP),
+			fmtOptFloat(it.Discrimination),
+			it.DiscriminationLabel,
+			strings.Join(opts, "; "),
+			strconv.Itoa(it.Blank),
+			strings.Join(it.Flags, "|"),
+		})
+	}
+
+	// Ringkasan quiz
+	_ = w.Write([]string{})
+	for _, kv := range [][2]string{
+		{"quiz_id", rep.QuizID.String()},
+		{"basis", rep.Basis},
+		{"students", strconv.Itoa(rep.Students)},
+		{"group_size", strconv.Itoa(rep.GroupSize)},
+		{"mean_percent", strconv.FormatFloat(rep.MeanPercent, 'f', 3, 64)},
+		{"stddev_percent", strconv.FormatFloat(rep.StdDevPercent, 'f', 3, 64)},
+		{"kr20", fmtOptFloat(rep.KR20)},
+		{"kr20_items", strconv.Itoa(rep.KR20Items)},
+		{"generated_at", rep.GeneratedAt.Format(time.RFC3339)},
+	} {
+		_ = w.Write([]string{kv[0], kv[1]})
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
@@ -0,0 +1,132 @@
+// file: internals/features/school/submissions_assesments/quizzes/controller/student_attempts/quiz_question_set_controller.go
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	qmodel "madinahsalam_backend/internals/features/school/submissions_assesments/quizzes/model"
+	qservice "madinahsalam_backend/internals/features/school/submissions_assesments/quizzes/service"
+	helper "madinahsalam_backend/internals/helpers"
+	helperAuth "madinahsalam_backend/internals/helpers/auth"
+)
+
+/*
+Set soal attempt yang sedang berjalan
+
+GET /api/u/quizzes/attempts/:id/questions
+
+- Set dibuat saat attempt dimulai (POST attempts tanpa items); kalau belum ada, dibuat di sini.
+- Urutan soal & opsi mengikuti set (acak sesuai assessment type).
+- Kunci jawaban & pembahasan tidak dikirim.
+*/
+
+type attemptOption struct {
+	Key  string `json:"key"`
+	Text any    `json:"text"`
+}
+
+type attemptQuestion struct {
+	No                  int                     `json:"no"`
+	QuizQuestionID      uuid.UUID               `json:"quiz_question_id"`
+	QuizQuestionVersion int                     `json:"quiz_question_version"`
+	QuizQuestionType    qmodel.QuizQuestionType `json:"quiz_question_type"`
+	QuizQuestionText    string                  `json:"quiz_question_text"`
+	QuizQuestionPoints  float64                 `json:"quiz_question_points"`
+	Options             []attemptOption         `json:"options,omitempty"`
+}
+
+// loadOwnAttempt: attempt :id milik siswa yang login (guru/DKM/owner boleh semua di sekolahnya).
+func (ctl *StudentQuizAttemptsController) loadOwnAttempt(c *fiber.Ctx) (*qmodel.StudentQuizAttemptModel, error) {
+	id, err := uuid.Parse(strings.TrimSpace(c.Params("id")))
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "id tidak valid")
+	}
+
+	var m qmodel.StudentQuizAttemptModel
+	if err := ctl.DB.WithContext(c.Context()).First(&m, "student_quiz_attempt_id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fiber.NewError(fiber.StatusNotFound, "Attempt tidak ditemukan")
+		}
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Gagal mengambil data")
+	}
+
+	schoolID := m.StudentQuizAttemptSchoolID
+	if helperAuth.IsOwner(c) || helperAuth.IsDKMInSchool(c, schoolID) || helperAuth.IsTeacherInSchool(c, schoolID) {
+		return &m, nil
+	}
+	sid, err := helperAuth.GetSchoolStudentIDForSchool(c, schoolID)
+	if err != nil || sid == uuid.Nil || sid != m.StudentQuizAttemptStudentID {
+		return nil, fiber.NewError(fiber.StatusForbidden, "Akses ditolak")
+	}
+	return &m, nil
+}
+
+// GET /attempts/:id/questions
+func (ctl *StudentQuizAttemptsController) Questions(c *fiber.Ctx) error {
+	m, err := ctl.loadOwnAttempt(c)
+	if err != nil {
+		return gradingError(c, err)
+	}
+	schoolID := m.StudentQuizAttemptSchoolID
+
+	set, err := qservice.NewQuizQuestionSetService(ctl.DB).PrepareQuestionSet(c.Context(), m.StudentQuizAttemptID)
+	if err != nil {
+		if errors.Is(err, qservice.ErrQuestionSetEmpty) {
+			return helper.JsonError(c, fiber.StatusNotFound, err.Error())
+		}
+		return helper.JsonError(c, fiber.StatusInternalServerError, "Gagal menyiapkan soal")
+	}
+	questions, err := qservice.LoadSetQuestions(c.Context(), ctl.DB, schoolID, set)
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusInternalServerError, "Gagal mengambil soal")
+	}
+
+	orderOf := make(map[uuid.UUID][]string, len(set.Questions))
+	for _, it := range set.Questions {
+		orderOf[it.QuizQuestionID] = it.OptionOrder
+	}
+
+	out := make([]attemptQuestion, 0, len(questions))
+	for i := range questions {
+		q := &questions[i]
+		aq := attemptQuestion{
+			No:                  i + 1,
+			QuizQuestionID:      q.QuizQuestionID,
+			QuizQuestionVersion: q.QuizQuestionVersion,
+			QuizQuestionType:    q.QuizQuestionType,
+			QuizQuestionText:    q.QuizQuestionText,
+			QuizQuestionPoints:  q.QuizQuestionPoints,
+		}
+		if q.QuizQuestionType == qmodel.QuizQuestionTypeSingle {
+			var obj map[string]any
+			_ = json.Unmarshal(q.QuizQuestionAnswers, &obj)
+			keys := orderOf[q.QuizQuestionID]
+			if len(keys) == 0 {
+				for k := range obj {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+			}
+			for _, k := range keys {
+				if v, ok := obj[k]; ok {
+					aq.Options = append(aq.Options, attemptOption{Key: k, Text: v})
+				}
+			}
+		}
+		out = append(out, aq)
+	}
+
+	return helper.JsonOK(c, "OK", fiber.Map{
+		"student_quiz_attempt_id": m.StudentQuizAttemptID,
+		"attempt_no":              set.AttemptNo,
+		"drawn_at":                set.DrawnAt,
+		"questions":               out,
+	})
+}
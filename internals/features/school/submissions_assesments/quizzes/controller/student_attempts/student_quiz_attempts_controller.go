@@ -215,7 +215,7 @@ func (ctl *StudentQuizAttemptsController) Create(c *fiber.Ctx) error {
 	}
 
 	// Resolve scope (school + student) dari quiz & token
-	mid, sid, _, err := ctl.resolveScopeForCreate(c, &req)
+	mid, sid, isStudent, err := ctl.resolveScopeForCreate(c, &req)
 	if err != nil {
 		log.Printf("[StudentQuizAttemptsController] resolveScopeForCreate error: %v", err)
 		if fe, ok := err.(*fiber.Error); ok {
@@ -229,6 +229,14 @@ func (ctl *StudentQuizAttemptsController) Create(c *fiber.Ctx) error {
 	req.StudentQuizAttemptSchoolID = &mid
 	req.StudentQuizAttemptStudentID = &sid
 
+	// Quiz remedial berpeserta: hanya siswa yang ditetapkan
+	if err := qservice.EnsureRemedialAccess(c.Context(), ctl.DB, req.StudentQuizAttemptQuizID, sid); err != nil {
+		if errors.Is(err, qservice.ErrRemedialNotParticipant) {
+			return helper.JsonError(c, fiber.StatusForbidden, err.Error())
+		}
+		return helper.JsonError(c, fiber.StatusInternalServerError, "Gagal mengecek peserta remedial")
+	}
+
 	// =========================================
 	// 1) Cek dulu: sudah ada summary row atau belum?
 	//    (1 row = 1 student × 1 quiz)
@@ -313,6 +321,22 @@ func (ctl *StudentQuizAttemptsController) Create(c *fiber.Ctx) error {
 		}
 		log.Printf("[StudentQuizAttemptsController] No items submitted. Returning summary only. isNew=%v attempt_id=%s",
 			isNew, m.StudentQuizAttemptID)
+
+		// Siapkan set soal attempt berikutnya (tarikan bank soal + acak urutan); dipakai ulang saat reload
+		if _, err := qservice.NewQuizQuestionSetService(ctl.DB).PrepareQuestionSet(c.Context(), m.StudentQuizAttemptID); err != nil &&
+			!errors.Is(err, qservice.ErrQuestionSetEmpty) {
+			return helper.JsonError(c, fiber.StatusInternalServerError, "Gagal menyiapkan soal: "+err.Error())
+		}
+
+		// Siswa: sesi server (token + deadline); dipanggil ulang → sesi yang sama
+		if isStudent {
+			if _, err := qservice.NewQuizAttemptSessionService(ctl.DB).StartSession(c.Context(), m.StudentQuizAttemptID, time.Now().UTC()); err != nil {
+				return helper.JsonError(c, fiber.StatusInternalServerError, "Gagal memulai sesi attempt: "+err.Error())
+			}
+			if err := ctl.DB.WithContext(c.Context()).First(m, "student_quiz_attempt_id = ?", m.StudentQuizAttemptID).Error; err != nil {
+				return helper.JsonError(c, fiber.StatusInternalServerError, "Gagal mengambil data")
+			}
+		}
 		return helper.JsonCreated(c, msg, qdto.FromModelStudentQuizAttemptWithCtx(c, m))
 	}
 
@@ -354,11 +378,19 @@ func (ctl *StudentQuizAttemptsController) Create(c *fiber.Ctx) error {
 		AttemptID:  m.StudentQuizAttemptID,
 		FinishedAt: req.AttemptFinishedAt, // boleh nil → service pakai now
 		Answers:    answers,
+		Staff:      !isStudent,
+	}
+	if req.SessionToken != nil {
+		submitIn.SessionToken = strings.TrimSpace(*req.SessionToken)
 	}
 
 	finalAttempt, err := svc.SubmitAttempt(c.Context(), submitIn)
 	if err != nil {
 		log.Printf("[StudentQuizAttemptsController] SubmitAttempt error: %v", err)
+		if errors.Is(err, qservice.ErrSessionRequired) || errors.Is(err, qservice.ErrSessionTokenInvalid) ||
+			errors.Is(err, qservice.ErrAttemptConflict) || errors.Is(err, qservice.ErrSessionNotFound) {
+			return sessionError(c, err)
+		}
 		return helper.JsonError(c, fiber.StatusInternalServerError, "Gagal memproses submit attempt")
 	}
 
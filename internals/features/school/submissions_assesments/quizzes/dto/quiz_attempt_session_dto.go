@@ -0,0 +1,49 @@
+// file: internals/features/school/submissions_assesments/quizzes/dto/quiz_attempt_session_dto.go
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	qservice "madinahsalam_backend/internals/features/school/submissions_assesments/quizzes/service"
+)
+
+/* =========================================================
+   SESI ATTEMPT (siswa)
+========================================================= */
+
+type AttemptSessionTokenRequest struct {
+	SessionToken string `json:"session_token"`
+}
+
+type SaveAttemptAnswerRequest struct {
+	SessionToken string `json:"session_token"`
+	Answer       string `json:"answer"` // key opsi (single) / teks (essay)
+}
+
+type AttemptEventRequest struct {
+	Kind           string          `json:"kind"` // tab_switch | focus_lost | reconnect | fullscreen_exit | copy_paste
+	QuizQuestionID *uuid.UUID      `json:"quiz_question_id"`
+	ClientAt       *time.Time      `json:"client_at"`
+	Meta           json.RawMessage `json:"meta"`
+}
+
+type RecordAttemptEventsRequest struct {
+	SessionToken string                `json:"session_token"`
+	Events       []AttemptEventRequest `json:"events"`
+}
+
+func (r RecordAttemptEventsRequest) ToInput() []qservice.AttemptEventInput {
+	out := make([]qservice.AttemptEventInput, 0, len(r.Events))
+	for _, e := range r.Events {
+		out = append(out, qservice.AttemptEventInput{
+			Kind:           e.Kind,
+			QuizQuestionID: e.QuizQuestionID,
+			ClientAt:       e.ClientAt,
+			Meta:           e.Meta,
+		})
+	}
+	return out
+}
@@ -0,0 +1,51 @@
+// file: internals/features/school/submissions_assesments/quizzes/dto/quiz_grading_dto.go
+package dto
+
+import (
+	"github.com/google/uuid"
+
+	qservice "madinahsalam_backend/internals/features/school/submissions_assesments/quizzes/service"
+)
+
+/* ==========================================================================================
+   REQUEST — GRADING ESSAY
+   1 item = 1 soal essay di 1 attempt (attempt_no dari history)
+========================================================================================== */
+
+type EssayGradeItem struct {
+	StudentQuizAttemptID uuid.UUID `json:"student_quiz_attempt_id" validate:"required"`
+	AttemptNo            int       `json:"attempt_no" validate:"required,min=1"`
+	QuizQuestionID       uuid.UUID `json:"quiz_question_id" validate:"required"`
+	PointsEarned         *float64  `json:"points_earned" validate:"required"`
+	Feedback             *string   `json:"feedback,omitempty"`
+}
+
+type GradeEssaysRequest struct {
+	Grades []EssayGradeItem `json:"grades" validate:"required,min=1,dive"`
+}
+
+func (r *GradeEssaysRequest) ToInput() []qservice.EssayGradeInput {
+	out := make([]qservice.EssayGradeInput, 0, len(r.Grades))
+	for _, g := range r.Grades {
+		in := qservice.EssayGradeInput{
+			AttemptID:      g.StudentQuizAttemptID,
+			AttemptNo:      g.AttemptNo,
+			QuizQuestionID: g.QuizQuestionID,
+			Feedback:       g.Feedback,
+		}
+		if g.PointsEarned != nil {
+			in.PointsEarned = *g.PointsEarned
+		}
+		out = append(out, in)
+	}
+	return out
+}
+
+/* ==========================================================================================
+   REQUEST — REGRADE (setelah kunci jawaban / bobot soal berubah)
+========================================================================================== */
+
+type RegradeQuizRequest struct {
+	QuizQuestionID *uuid.UUID `json:"quiz_question_id,omitempty"` // kosong = semua soal
+	DryRun         bool       `json:"dry_run"`
+}
@@ -0,0 +1,39 @@
+// file: internals/features/school/submissions_assesments/quizzes/dto/quiz_remedial_dto.go
+package dto
+
+import (
+	"github.com/google/uuid"
+
+	qservice "madinahsalam_backend/internals/features/school/submissions_assesments/quizzes/service"
+)
+
+// POST /quizzes-teacher/:id/remedials
+// mode   : clone (salin semua soal + aturan tarik) | assemble (question_ids dari quiz induk + bank_item_ids)
+// policy : cap_kkm (default) | max | average
+type CreateRemedialRequest struct {
+	Mode           string      `json:"mode"`
+	Policy         string      `json:"policy"`
+	PassingPercent *float64    `json:"passing_percent"` // kosong = KKM dari assessment type / mapel
+	StudentIDs     []uuid.UUID `json:"student_ids"`     // kosong = semua siswa di bawah KKM
+	QuestionIDs    []uuid.UUID `json:"question_ids"`
+	BankItemIDs    []uuid.UUID `json:"bank_item_ids"`
+	Title          *string     `json:"title"`
+	TimeLimitSec   *int        `json:"time_limit_sec"`
+	Publish        bool        `json:"publish"`
+	DryRun         bool        `json:"dry_run"` // hanya lihat kandidat
+}
+
+func (r CreateRemedialRequest) ToInput() qservice.RemedialInput {
+	return qservice.RemedialInput{
+		Mode:           r.Mode,
+		Policy:         r.Policy,
+		PassingPercent: r.PassingPercent,
+		StudentIDs:     r.StudentIDs,
+		QuestionIDs:    r.QuestionIDs,
+		BankItemIDs:    r.BankItemIDs,
+		Title:          r.Title,
+		TimeLimitSec:   r.TimeLimitSec,
+		Publish:        r.Publish,
+		DryRun:         r.DryRun,
+	}
+}
@@ -308,6 +308,14 @@ type QuizResponse struct {
 	QuizParentQuizID  *uuid.UUID `json:"quiz_parent_quiz_id,omitempty"`
 	QuizRemedialRound *int       `json:"quiz_remedial_round,omitempty"`
 
+	QuizRemedialPolicy         *string  `json:"quiz_remedial_policy,omitempty"`
+	QuizRemedialPassingPercent *float64 `json:"quiz_remedial_passing_percent,omitempty"`
+
+	// asal salinan (import material library)
+	QuizOriginLibraryID *uuid.UUID `json:"quiz_origin_library_id,omitempty"`
+	QuizOriginSourceID  *uuid.UUID `json:"quiz_origin_source_id,omitempty"`
+	QuizOriginVersion   *int       `json:"quiz_origin_version,omitempty"`
+
 	QuizCreatedAt time.Time  `json:"quiz_created_at"`
 	QuizUpdatedAt time.Time  `json:"quiz_updated_at"`
 	QuizDeletedAt *time.Time `json:"quiz_deleted_at,omitempty"`
@@ -363,6 +371,13 @@ func FromModel(m *model.QuizModel) QuizResponse {
 		QuizParentQuizID:  m.QuizParentQuizID,
 		QuizRemedialRound: m.QuizRemedialRound,
 
+		QuizRemedialPolicy:         m.QuizRemedialPolicy,
+		QuizRemedialPassingPercent: m.QuizRemedialPassingPercent,
+
+		QuizOriginLibraryID: m.QuizOriginLibraryID,
+		QuizOriginSourceID:  m.QuizOriginSourceID,
+		QuizOriginVersion:   m.QuizOriginVersion,
+
 		QuizCreatedAt: m.QuizCreatedAt,
 		QuizUpdatedAt: m.QuizUpdatedAt,
 		QuizDeletedAt: deletedAt,
@@ -58,6 +58,9 @@ type CreateStudentQuizAttemptRequest struct {
 	AttemptStartedAt  *time.Time `json:"attempt_started_at,omitempty" validate:"omitempty"`
 	AttemptFinishedAt *time.Time `json:"attempt_finished_at,omitempty" validate:"omitempty"`
 
+	// Token dari respons "mulai attempt" (student_quiz_attempt_session.token)
+	SessionToken *string `json:"session_token,omitempty"`
+
 	// Kalau diisi → berarti langsung sekalian submit jawaban (1 request)
 	// Kalau dikosongkan → berarti hanya "mulai attempt" (tanpa jawaban dulu)
 	Items []CreateStudentQuizAttemptItem `json:"items" validate:"omitempty,dive"`
@@ -275,9 +278,29 @@ type StudentQuizAttemptResponse struct {
 
 	StudentQuizAttemptCreatedAt time.Time `json:"student_quiz_attempt_created_at"`
 	StudentQuizAttemptUpdatedAt time.Time `json:"student_quiz_attempt_updated_at"`
+
+	// Sesi attempt berjalan (token + deadline server); hanya ada selama attempt dikerjakan
+	StudentQuizAttemptSession *StudentQuizAttemptSessionResponse `json:"student_quiz_attempt_session,omitempty"`
+}
+
+// Timer klien dihitung dari deadline_at - server_now (bukan jam perangkat).
+type StudentQuizAttemptSessionResponse struct {
+	*qmodel.StudentQuizAttemptSession
+	ServerNow time.Time `json:"server_now"`
 }
 
 func FromModelStudentQuizAttempt(m *qmodel.StudentQuizAttemptModel) *StudentQuizAttemptResponse {
+	resp := fromModelStudentQuizAttempt(m)
+	if sess := m.ActiveSession(); sess != nil {
+		resp.StudentQuizAttemptSession = &StudentQuizAttemptSessionResponse{
+			StudentQuizAttemptSession: sess,
+			ServerNow:                 time.Now().UTC(),
+		}
+	}
+	return resp
+}
+
+func fromModelStudentQuizAttempt(m *qmodel.StudentQuizAttemptModel) *StudentQuizAttemptResponse {
 	return &StudentQuizAttemptResponse{
 		StudentQuizAttemptID:        m.StudentQuizAttemptID,
 		StudentQuizAttemptSchoolID:  m.StudentQuizAttemptSchoolID,
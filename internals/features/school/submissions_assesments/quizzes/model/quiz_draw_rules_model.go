@@ -0,0 +1,32 @@
+// file: internals/features/school/submissions_assesments/quizzes/model/quiz_draw_rules_model.go
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/* =========================================================
+   quiz_draw_rules — tarik N soal acak dari bank soal per siswa
+   ========================================================= */
+
+type QuizDrawRuleModel struct {
+	QuizDrawRuleID       uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey;column:quiz_draw_rule_id" json:"quiz_draw_rule_id"`
+	QuizDrawRuleSchoolID uuid.UUID `gorm:"type:uuid;not null;column:quiz_draw_rule_school_id" json:"quiz_draw_rule_school_id"`
+	QuizDrawRuleQuizID   uuid.UUID `gorm:"type:uuid;not null;column:quiz_draw_rule_quiz_id" json:"quiz_draw_rule_quiz_id"`
+
+	// Filter tag (nil = semua)
+	QuizDrawRuleSubjectID     *uuid.UUID `gorm:"type:uuid;column:quiz_draw_rule_subject_id" json:"quiz_draw_rule_subject_id,omitempty"`
+	QuizDrawRuleClassParentID *uuid.UUID `gorm:"type:uuid;column:quiz_draw_rule_class_parent_id" json:"quiz_draw_rule_class_parent_id,omitempty"`
+	QuizDrawRuleTopic         *string    `gorm:"type:varchar(120);column:quiz_draw_rule_topic" json:"quiz_draw_rule_topic,omitempty"`
+	QuizDrawRuleDifficulty    *string    `gorm:"type:varchar(8);column:quiz_draw_rule_difficulty" json:"quiz_draw_rule_difficulty,omitempty"`
+
+	QuizDrawRuleCount int `gorm:"type:int;not null;column:quiz_draw_rule_count" json:"quiz_draw_rule_count"`
+	QuizDrawRuleOrder int `gorm:"type:int;not null;default:0;column:quiz_draw_rule_order" json:"quiz_draw_rule_order"`
+
+	QuizDrawRuleCreatedAt time.Time `gorm:"type:timestamptz;not null;default:now();autoCreateTime;column:quiz_draw_rule_created_at" json:"quiz_draw_rule_created_at"`
+	QuizDrawRuleUpdatedAt time.Time `gorm:"type:timestamptz;not null;default:now();autoUpdateTime;column:quiz_draw_rule_updated_at" json:"quiz_draw_rule_updated_at"`
+}
+
+func (QuizDrawRuleModel) TableName() string { return "quiz_draw_rules" }
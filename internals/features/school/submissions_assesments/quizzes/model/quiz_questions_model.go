@@ -23,6 +23,11 @@ const (
 	QuizQuestionTypeEssay  QuizQuestionType = "essay"
 )
 
+const (
+	QuizQuestionBankModeCopy      = "copy"
+	QuizQuestionBankModeReference = "reference"
+)
+
 /* =========================================================
    History item
    ========================================================= */
@@ -69,6 +74,14 @@ type QuizQuestionModel struct {
 	QuizQuestionVersion int            `gorm:"type:int;not null;default:1;column:quiz_question_version" json:"quiz_question_version"`
 	QuizQuestionHistory datatypes.JSON `gorm:"type:jsonb;not null;default:'[]'::jsonb;column:quiz_question_history" json:"quiz_question_history"`
 
+	// Asal bank soal (opsional)
+	// - mode copy      : salinan lepas, tidak ikut berubah
+	// - mode reference : disinkron ulang saat soal bank diubah
+	// - is_pool        : soal hasil tarikan acak (quiz_draw_rules), bukan set soal tetap
+	QuizQuestionBankItemID *uuid.UUID `gorm:"type:uuid;column:quiz_question_bank_item_id" json:"quiz_question_bank_item_id,omitempty"`
+	QuizQuestionBankMode   *string    `gorm:"type:varchar(10);column:quiz_question_bank_mode" json:"quiz_question_bank_mode,omitempty"`
+	QuizQuestionIsPool     bool       `gorm:"not null;default:false;column:quiz_question_is_pool" json:"quiz_question_is_pool"`
+
 	// Unique pair (quiz_question_id, quiz_question_quiz_id)
 	_ struct{} `gorm:"uniqueIndex:uq_quiz_question_id_quiz"`
 
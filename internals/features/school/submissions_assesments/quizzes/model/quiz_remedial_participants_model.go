@@ -0,0 +1,62 @@
+// file: internals/features/school/submissions_assesments/quizzes/model/quiz_remedial_participants_model.go
+package model
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/* =========================================================
+   quiz_remedial_participants — siswa peserta quiz remedial
+   ========================================================= */
+
+// Kebijakan gabung nilai remedial dengan nilai asal
+const (
+	QuizRemedialPolicyCapKKM  = "cap_kkm" // nilai remedial dibatasi maksimal KKM
+	QuizRemedialPolicyMax     = "max"     // ambil yang lebih tinggi
+	QuizRemedialPolicyAverage = "average" // rata-rata nilai asal & remedial
+)
+
+func ValidRemedialPolicy(p string) bool {
+	switch p {
+	case QuizRemedialPolicyCapKKM, QuizRemedialPolicyMax, QuizRemedialPolicyAverage:
+		return true
+	}
+	return false
+}
+
+// CombineRemedialScore: nilai akhir (persen) dari nilai asal & nilai remedial.
+// cap_kkm : max(asal, min(remedial, KKM))
+// max     : max(asal, remedial)
+// average : (asal + remedial) / 2
+func CombineRemedialScore(policy string, original, remedial, passing float64) float64 {
+	switch policy {
+	case QuizRemedialPolicyMax:
+		return math.Max(original, remedial)
+	case QuizRemedialPolicyAverage:
+		return (original + remedial) / 2
+	}
+	return math.Max(original, math.Min(remedial, passing))
+}
+
+type QuizRemedialParticipantModel struct {
+	QuizRemedialParticipantID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey;column:quiz_remedial_participant_id" json:"quiz_remedial_participant_id"`
+	QuizRemedialParticipantSchoolID   uuid.UUID `gorm:"type:uuid;not null;column:quiz_remedial_participant_school_id" json:"quiz_remedial_participant_school_id"`
+	QuizRemedialParticipantQuizID     uuid.UUID `gorm:"type:uuid;not null;column:quiz_remedial_participant_quiz_id" json:"quiz_remedial_participant_quiz_id"`
+	QuizRemedialParticipantRootQuizID uuid.UUID `gorm:"type:uuid;not null;column:quiz_remedial_participant_root_quiz_id" json:"quiz_remedial_participant_root_quiz_id"`
+	QuizRemedialParticipantStudentID  uuid.UUID `gorm:"type:uuid;not null;column:quiz_remedial_participant_student_id" json:"quiz_remedial_participant_student_id"`
+	QuizRemedialParticipantRound      int       `gorm:"type:int;not null;column:quiz_remedial_participant_round" json:"quiz_remedial_participant_round"`
+
+	// Nilai asal = nilai akhir ronde sebelumnya (ronde 1: attempt terakhir quiz utama)
+	QuizRemedialParticipantOriginalPercent float64    `gorm:"type:numeric(6,3);not null;column:quiz_remedial_participant_original_percent" json:"quiz_remedial_participant_original_percent"`
+	QuizRemedialParticipantRemedialPercent *float64   `gorm:"type:numeric(6,3);column:quiz_remedial_participant_remedial_percent" json:"quiz_remedial_participant_remedial_percent,omitempty"`
+	QuizRemedialParticipantFinalPercent    *float64   `gorm:"type:numeric(6,3);column:quiz_remedial_participant_final_percent" json:"quiz_remedial_participant_final_percent,omitempty"`
+	QuizRemedialParticipantCompletedAt     *time.Time `gorm:"type:timestamptz;column:quiz_remedial_participant_completed_at" json:"quiz_remedial_participant_completed_at,omitempty"`
+
+	QuizRemedialParticipantCreatedAt time.Time `gorm:"type:timestamptz;not null;default:now();autoCreateTime;column:quiz_remedial_participant_created_at" json:"quiz_remedial_participant_created_at"`
+	QuizRemedialParticipantUpdatedAt time.Time `gorm:"type:timestamptz;not null;default:now();autoUpdateTime;column:quiz_remedial_participant_updated_at" json:"quiz_remedial_participant_updated_at"`
+}
+
+func (QuizRemedialParticipantModel) TableName() string { return "quiz_remedial_participants" }
@@ -46,6 +46,15 @@ type QuizModel struct {
 	// Remedial ke berapa (1 = remedial pertama, 2 = remedial kedua, dst)
 	QuizRemedialRound *int `gorm:"type:int;column:quiz_remedial_round" json:"quiz_remedial_round,omitempty"`
 
+	// Kebijakan gabung nilai remedial + KKM saat remedial dibuat (lihat CombineRemedialScore)
+	QuizRemedialPolicy         *string  `gorm:"type:varchar(10);column:quiz_remedial_policy" json:"quiz_remedial_policy,omitempty"`
+	QuizRemedialPassingPercent *float64 `gorm:"type:numeric(5,2);column:quiz_remedial_passing_percent" json:"quiz_remedial_passing_percent,omitempty"`
+
+	// Asal salinan (import dari material_libraries) → atribusi & lisensi
+	QuizOriginLibraryID *uuid.UUID `gorm:"type:uuid;column:quiz_origin_library_id" json:"quiz_origin_library_id,omitempty"`
+	QuizOriginSourceID  *uuid.UUID `gorm:"type:uuid;column:quiz_origin_source_id" json:"quiz_origin_source_id,omitempty"`
+	QuizOriginVersion   *int       `gorm:"type:int;column:quiz_origin_version" json:"quiz_origin_version,omitempty"`
+
 	// Timestamps & soft delete
 	QuizCreatedAt time.Time      `gorm:"type:timestamptz;not null;default:now();column:quiz_created_at" json:"quiz_created_at"`
 	QuizUpdatedAt time.Time      `gorm:"type:timestamptz;not null;default:now();column:quiz_updated_at" json:"quiz_updated_at"`
@@ -0,0 +1,57 @@
+// file: internals/features/school/submissions_assesments/quizzes/model/student_quiz_attempt_events_model.go
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+/* =========================================================
+   student_quiz_attempt_events — log sinyal integritas per attempt
+   ========================================================= */
+
+// Dilaporkan klien
+const (
+	AttemptEventTabSwitch      = "tab_switch"
+	AttemptEventFocusLost      = "focus_lost"
+	AttemptEventReconnect      = "reconnect"
+	AttemptEventFullscreenExit = "fullscreen_exit"
+	AttemptEventCopyPaste      = "copy_paste"
+)
+
+// Dicatat server
+const (
+	AttemptEventResume          = "resume"
+	AttemptEventQuestionTimeout = "question_timeout"
+	AttemptEventLateSubmit      = "late_submit"
+	AttemptEventAutoSubmit      = "auto_submit"
+)
+
+// ClientAttemptEventKind: jenis event yang boleh dikirim klien.
+func ClientAttemptEventKind(k string) bool {
+	switch k {
+	case AttemptEventTabSwitch, AttemptEventFocusLost, AttemptEventReconnect,
+		AttemptEventFullscreenExit, AttemptEventCopyPaste:
+		return true
+	}
+	return false
+}
+
+type StudentQuizAttemptEventModel struct {
+	StudentQuizAttemptEventID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey;column:student_quiz_attempt_event_id" json:"student_quiz_attempt_event_id"`
+	StudentQuizAttemptEventSchoolID  uuid.UUID `gorm:"type:uuid;not null;column:student_quiz_attempt_event_school_id" json:"student_quiz_attempt_event_school_id"`
+	StudentQuizAttemptEventAttemptID uuid.UUID `gorm:"type:uuid;not null;column:student_quiz_attempt_event_attempt_id" json:"student_quiz_attempt_event_attempt_id"`
+	StudentQuizAttemptEventAttemptNo int       `gorm:"type:int;not null;column:student_quiz_attempt_event_attempt_no" json:"student_quiz_attempt_event_attempt_no"`
+
+	StudentQuizAttemptEventKind           string     `gorm:"type:varchar(24);not null;column:student_quiz_attempt_event_kind" json:"student_quiz_attempt_event_kind"`
+	StudentQuizAttemptEventQuizQuestionID *uuid.UUID `gorm:"type:uuid;column:student_quiz_attempt_event_quiz_question_id" json:"student_quiz_attempt_event_quiz_question_id,omitempty"`
+
+	StudentQuizAttemptEventClientAt *time.Time     `gorm:"type:timestamptz;column:student_quiz_attempt_event_client_at" json:"student_quiz_attempt_event_client_at,omitempty"`
+	StudentQuizAttemptEventMeta     datatypes.JSON `gorm:"type:jsonb;column:student_quiz_attempt_event_meta" json:"student_quiz_attempt_event_meta,omitempty"`
+
+	StudentQuizAttemptEventCreatedAt time.Time `gorm:"type:timestamptz;not null;default:now();autoCreateTime;column:student_quiz_attempt_event_created_at" json:"student_quiz_attempt_event_created_at"`
+}
+
+func (StudentQuizAttemptEventModel) TableName() string { return "student_quiz_attempt_events" }
@@ -50,10 +50,25 @@ type StudentQuizAttemptQuestionItem struct {
 	AnswerSingle *string `json:"answer_single,omitempty"` // untuk single choice (A/B/C/...)
 	AnswerEssay  *string `json:"answer_essay,omitempty"`  // untuk essay (teks bebas)
 
+	// Urutan key opsi yang ditampilkan (kalau opsi diacak)
+	OptionOrder []string `json:"option_order,omitempty"`
+
 	// Penilaian
 	IsCorrect    *bool   `json:"is_correct,omitempty"` // boleh null (misal essay belum dinilai)
 	Points       float64 `json:"points"`               // bobot soal
 	PointsEarned float64 `json:"points_earned"`        // 0 / points / parsial
+
+	// Penilaian manual (essay) & regrade
+	GradedByTeacherID   *uuid.UUID `json:"graded_by_teacher_id,omitempty"`
+	GradedAt            *time.Time `json:"graded_at,omitempty"`
+	GradeFeedback       *string    `json:"grade_feedback,omitempty"`
+	RegradedFromVersion *int       `json:"regraded_from_version,omitempty"` // versi soal saat dijawab (sebelum regrade)
+	RegradedAt          *time.Time `json:"regraded_at,omitempty"`
+}
+
+// NeedsGrading: essay yang belum dinilai guru.
+func (it StudentQuizAttemptQuestionItem) NeedsGrading() bool {
+	return it.QuizQuestionType == QuizQuestionTypeEssay && it.IsCorrect == nil
 }
 
 // Satu attempt lengkap (1 kali pengerjaan quiz)
@@ -66,6 +81,57 @@ type StudentQuizAttemptHistoryItem struct {
 	AttemptPercent  float64 `json:"attempt_percent"`   // 0–100
 
 	Items []StudentQuizAttemptQuestionItem `json:"items"`
+
+	// Ringkasan waktu & sinyal integritas (attempt yang dimulai lewat sesi server)
+	Integrity *StudentQuizAttemptIntegrity `json:"integrity,omitempty"`
+}
+
+type StudentQuizAttemptIntegrity struct {
+	ServerStartedAt   time.Time      `json:"server_started_at"`
+	DeadlineAt        *time.Time     `json:"deadline_at,omitempty"`
+	DurationSec       int            `json:"duration_sec"`
+	Late              bool           `json:"late,omitempty"`           // submit lewat deadline → jawaban akhir ditolak
+	AutoSubmitted     bool           `json:"auto_submitted,omitempty"` // dinilai dari jawaban draft
+	TimedOutQuestions int            `json:"timed_out_questions,omitempty"`
+	EventCounts       map[string]int `json:"event_counts,omitempty"`
+}
+
+// Set soal untuk attempt yang sedang berjalan (dibuat saat attempt dimulai).
+// Dipakai SubmitAttempt supaya soal yang dinilai = soal yang ditampilkan.
+type StudentQuizAttemptQuestionSet struct {
+	AttemptNo int                                 `json:"attempt_no"`
+	DrawnAt   time.Time                           `json:"drawn_at"`
+	Questions []StudentQuizAttemptQuestionSetItem `json:"questions"`
+}
+
+type StudentQuizAttemptQuestionSetItem struct {
+	QuizQuestionID      uuid.UUID  `json:"quiz_question_id"`
+	QuizQuestionVersion int        `json:"quiz_question_version"`
+	BankItemID          *uuid.UUID `json:"bank_item_id,omitempty"` // isi kalau hasil tarikan bank soal
+	DrawRuleID          *uuid.UUID `json:"draw_rule_id,omitempty"`
+	OptionOrder         []string   `json:"option_order,omitempty"`
+}
+
+// Sesi attempt berjalan; dikeluarkan server saat attempt dimulai.
+// Waktu mulai & deadline hanya dari server, jawaban draft dipakai saat auto-submit.
+type StudentQuizAttemptSession struct {
+	AttemptNo          int        `json:"attempt_no"`
+	Token              string     `json:"token"`
+	StartedAt          time.Time  `json:"started_at"`
+	DeadlineAt         *time.Time `json:"deadline_at,omitempty"`
+	StrictMode         bool       `json:"strict_mode"`
+	TimePerQuestionSec *int       `json:"time_per_question_sec,omitempty"`
+
+	// key = quiz_question_id
+	Questions map[string]*StudentQuizAttemptSessionQuestion `json:"questions,omitempty"`
+}
+
+type StudentQuizAttemptSessionQuestion struct {
+	OpenedAt   time.Time  `json:"opened_at"`
+	DeadlineAt *time.Time `json:"deadline_at,omitempty"` // strict mode: opened_at + time_per_question_sec
+	Answer     *string    `json:"answer,omitempty"`
+	AnsweredAt *time.Time `json:"answered_at,omitempty"`
+	TimedOut   bool       `json:"timed_out,omitempty"`
 }
 
 /*
@@ -101,6 +167,13 @@ type StudentQuizAttemptModel struct {
 	// Riwayat attempt lengkap (termasuk jawaban) dalam JSONB
 	StudentQuizAttemptHistory datatypes.JSON `gorm:"type:jsonb;not null;default:'[]'::jsonb;column:student_quiz_attempt_history" json:"student_quiz_attempt_history"`
 
+	// Set soal attempt berjalan (urutan soal + opsi); null = pakai semua soal tetap quiz
+	StudentQuizAttemptQuestionSet datatypes.JSON `gorm:"type:jsonb;column:student_quiz_attempt_question_set" json:"student_quiz_attempt_question_set,omitempty"`
+
+	// Sesi attempt berjalan (token + waktu server); deadline disalin ke kolom untuk worker auto-submit
+	StudentQuizAttemptSession    datatypes.JSON `gorm:"type:jsonb;column:student_quiz_attempt_session" json:"-"`
+	StudentQuizAttemptDeadlineAt *time.Time     `gorm:"type:timestamptz;column:student_quiz_attempt_deadline_at" json:"student_quiz_attempt_deadline_at,omitempty"`
+
 	// Total attempt yang pernah dilakukan
 	StudentQuizAttemptCount int `gorm:"type:int;not null;default:0;column:student_quiz_attempt_count" json:"student_quiz_attempt_count"`
 
@@ -226,6 +299,147 @@ func (m *StudentQuizAttemptModel) AppendAttemptHistory(
 	return nil
 }
 
+// ParseAttemptHistory: decode JSON history (kosong → slice kosong).
+func (m *StudentQuizAttemptModel) ParseAttemptHistory() ([]StudentQuizAttemptHistoryItem, error) {
+	var history []StudentQuizAttemptHistoryItem
+	if len(m.StudentQuizAttemptHistory) > 0 {
+		if err := json.Unmarshal(m.StudentQuizAttemptHistory, &history); err != nil {
+			return nil, fmt.Errorf("invalid student_quiz_attempt_history json: %w", err)
+		}
+	}
+	return history, nil
+}
+
+// ReplaceAttemptHistory dipakai setelah item dinilai ulang (grading essay / regrade):
+// - hitung ulang raw/percent tiap attempt dari items
+// - tulis ulang JSON history
+// - hitung ulang ringkasan best/last/first/avg dari nol
+func (m *StudentQuizAttemptModel) ReplaceAttemptHistory(history []StudentQuizAttemptHistoryItem) error {
+	for i := range history {
+		var totalPoints, totalEarned float64
+		for _, it := range history[i].Items {
+			totalPoints += it.Points
+			totalEarned += it.PointsEarned
+		}
+		history[i].AttemptRawScore = totalEarned
+		history[i].AttemptPercent = 0
+		if totalPoints > 0 {
+			history[i].AttemptPercent = (totalEarned / totalPoints) * 100.0
+		}
+	}
+
+	buf, err := json.Marshal(history)
+	if err != nil {
+		return fmt.Errorf("failed to marshal student_quiz_attempt_history: %w", err)
+	}
+	m.StudentQuizAttemptHistory = datatypes.JSON(buf)
+	m.StudentQuizAttemptCount = len(history)
+
+	m.StudentQuizAttemptBestRaw, m.StudentQuizAttemptBestPercent = nil, nil
+	m.StudentQuizAttemptBestStartedAt, m.StudentQuizAttemptBestFinishedAt = nil, nil
+	m.StudentQuizAttemptLastRaw, m.StudentQuizAttemptLastPercent = nil, nil
+	m.StudentQuizAttemptLastStartedAt, m.StudentQuizAttemptLastFinishedAt = nil, nil
+	m.StudentQuizAttemptFirstRaw, m.StudentQuizAttemptFirstPercent = nil, nil
+	m.StudentQuizAttemptFirstStartedAt, m.StudentQuizAttemptFirstFinishedAt = nil, nil
+	m.StudentQuizAttemptAvgRaw, m.StudentQuizAttemptAvgPercent = nil, nil
+	if len(history) == 0 {
+		return nil
+	}
+
+	// BEST: attempt pertama dengan percent tertinggi (sama dengan aturan AppendAttemptHistory)
+	var sumRaw, sumPercent float64
+	for i := range history {
+		h := &history[i]
+		if m.StudentQuizAttemptBestPercent == nil || h.AttemptPercent > *m.StudentQuizAttemptBestPercent {
+			m.StudentQuizAttemptBestRaw = &h.AttemptRawScore
+			m.StudentQuizAttemptBestPercent = &h.AttemptPercent
+			m.StudentQuizAttemptBestStartedAt = &h.AttemptStartedAt
+			m.StudentQuizAttemptBestFinishedAt = &h.AttemptFinishedAt
+		}
+		sumRaw += h.AttemptRawScore
+		sumPercent += h.AttemptPercent
+	}
+
+	last := &history[len(history)-1]
+	m.StudentQuizAttemptLastRaw = &last.AttemptRawScore
+	m.StudentQuizAttemptLastPercent = &last.AttemptPercent
+	m.StudentQuizAttemptLastStartedAt = &last.AttemptStartedAt
+	m.StudentQuizAttemptLastFinishedAt = &last.AttemptFinishedAt
+
+	first := &history[0]
+	m.StudentQuizAttemptFirstRaw = &first.AttemptRawScore
+	m.StudentQuizAttemptFirstPercent = &first.AttemptPercent
+	m.StudentQuizAttemptFirstStartedAt = &first.AttemptStartedAt
+	m.StudentQuizAttemptFirstFinishedAt = &first.AttemptFinishedAt
+
+	n := float64(len(history))
+	avgRaw, avgPercent := sumRaw/n, sumPercent/n
+	m.StudentQuizAttemptAvgRaw = &avgRaw
+	m.StudentQuizAttemptAvgPercent = &avgPercent
+	return nil
+}
+
+// PendingQuestionSet: set soal untuk attempt berikutnya (count+1), nil kalau belum/tidak ada.
+func (m *StudentQuizAttemptModel) PendingQuestionSet() *StudentQuizAttemptQuestionSet {
+	if len(m.StudentQuizAttemptQuestionSet) == 0 || string(m.StudentQuizAttemptQuestionSet) == "null" {
+		return nil
+	}
+	var set StudentQuizAttemptQuestionSet
+	if err := json.Unmarshal(m.StudentQuizAttemptQuestionSet, &set); err != nil {
+		return nil
+	}
+	if set.AttemptNo != m.StudentQuizAttemptCount+1 {
+		return nil
+	}
+	return &set
+}
+
+// ActiveSession: sesi untuk attempt berikutnya (count+1), nil kalau belum dimulai lewat server.
+func (m *StudentQuizAttemptModel) ActiveSession() *StudentQuizAttemptSession {
+	if len(m.StudentQuizAttemptSession) == 0 || string(m.StudentQuizAttemptSession) == "null" {
+		return nil
+	}
+	var s StudentQuizAttemptSession
+	if err := json.Unmarshal(m.StudentQuizAttemptSession, &s); err != nil {
+		return nil
+	}
+	if s.AttemptNo != m.StudentQuizAttemptCount+1 {
+		return nil
+	}
+	return &s
+}
+
+// SetSession: simpan sesi (nil = hapus) + sinkron kolom deadline.
+func (m *StudentQuizAttemptModel) SetSession(s *StudentQuizAttemptSession) error {
+	if s == nil {
+		m.StudentQuizAttemptSession = nil
+		m.StudentQuizAttemptDeadlineAt = nil
+		return nil
+	}
+	buf, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to marshal student_quiz_attempt_session: %w", err)
+	}
+	m.StudentQuizAttemptSession = datatypes.JSON(buf)
+	m.StudentQuizAttemptDeadlineAt = s.DeadlineAt
+	return nil
+}
+
+// SetLastIntegrity: tempel ringkasan integritas ke attempt terakhir di history.
+func (m *StudentQuizAttemptModel) SetLastIntegrity(in *StudentQuizAttemptIntegrity) error {
+	history, err := m.ParseAttemptHistory()
+	if err != nil || len(history) == 0 {
+		return err
+	}
+	history[len(history)-1].Integrity = in
+	buf, err := json.Marshal(history)
+	if err != nil {
+		return fmt.Errorf("failed to marshal student_quiz_attempt_history: %w", err)
+	}
+	m.StudentQuizAttemptHistory = datatypes.JSON(buf)
+	return nil
+}
+
 // TableName override default GORM → pakai nama tabel nyata di DB
 func (StudentQuizAttemptModel) TableName() string {
 	return "student_quiz_attempts"
@@ -34,6 +34,10 @@ func mountQuizRoutes(g fiber.Router, db *gorm.DB) {
 	g.Patch("/:id", ctrl.Patch)   // PATCH /api/a/quizzes/:id
 	g.Delete("/:id", ctrl.Delete) // DELETE /api/a/quizzes/:id
 
+	// Remedial otomatis
+	g.Post("/:id/remedials", ctrl.CreateRemedial)                  // POST /api/a/quizzes/:id/remedials
+	g.Get("/:id/remedial-participants", ctrl.RemedialParticipants) // GET  /api/a/quizzes/:id/remedial-participants
+
 	// QUIZ QUESTIONS (soal & opsi dalam satu baris)
 	qqCtrl := quizQuestionsController.NewQuizQuestionsController(db)
 	qs := g.Group("/questions") // -> /api/a/quizzes/questions
@@ -52,4 +56,7 @@ func mountQuizRoutes(g fiber.Router, db *gorm.DB) {
 	attempts.Post("/", uqAttemptCtrl.Create)      // POST   /api/a/quizzes/attempts
 	attempts.Patch("/:id", uqAttemptCtrl.Patch)   // PATCH  /api/a/quizzes/attempts/:id
 	attempts.Delete("/:id", uqAttemptCtrl.Delete) // DELETE /api/a/quizzes/attempts/:id
+
+	// Analisis butir soal per quiz
+	g.Get("/:id/item-analysis", uqAttemptCtrl.ItemAnalysis) // GET /api/a/quizzes/:id/item-analysis?basis=&format=csv
 }
@@ -37,6 +37,10 @@ func mountQuizTeacherRoutes(base fiber.Router, db *gorm.DB) {
 	quizzes.Patch("/:id", quizCtrl.Patch)   // PATCH  /api/t/quizzes-teacher/:id
 	quizzes.Delete("/:id", quizCtrl.Delete) // DELETE /api/t/quizzes-teacher/:id
 
+	// Remedial otomatis (siswa di bawah KKM) + peserta & nilai gabungan
+	quizzes.Post("/:id/remedials", quizCtrl.CreateRemedial)                  // POST   /api/t/quizzes-teacher/:id/remedials
+	quizzes.Get("/:id/remedial-participants", quizCtrl.RemedialParticipants) // GET    /api/t/quizzes-teacher/:id/remedial-participants
+
 	// ============================
 	// QUIZ QUESTIONS (soal & opsi JSONB)
 	// -> /api/t/quiz-questions-teacher  (+ alias /api/t/quiz-items-teacher)
@@ -60,6 +64,19 @@ func mountQuizTeacherRoutes(base fiber.Router, db *gorm.DB) {
 	attempts.Post("/", uqAttemptCtrl.Create)      // POST   /api/t/quizzes-teacher/attempts-teacher
 	attempts.Patch("/:id", uqAttemptCtrl.Patch)   // PATCH  /api/t/quizzes-teacher/attempts-teacher/:id
 	attempts.Delete("/:id", uqAttemptCtrl.Delete) // DELETE /api/t/quizzes-teacher/attempts-teacher/:id
+
+	// Grading essay (antrean lintas attempt)
+	attempts.Get("/essay-queue", uqAttemptCtrl.EssayQueue)    // GET    /api/t/quizzes-teacher/attempts-teacher/essay-queue?quiz_id=&assessment_id=&student_id=
+	attempts.Post("/essay-grades", uqAttemptCtrl.GradeEssays) // POST   /api/t/quizzes-teacher/attempts-teacher/essay-grades
+
+	// Log integritas attempt (event + ringkasan per attempt_no)
+	attempts.Get("/:id/events", uqAttemptCtrl.EventLog) // GET    /api/t/quizzes-teacher/attempts-teacher/:id/events?attempt_no=
+
+	// Regrade setelah kunci jawaban / bobot soal berubah (pakai quiz_question_version + history)
+	quizzes.Post("/:id/regrade", uqAttemptCtrl.Regrade) // POST /api/t/quizzes-teacher/:id/regrade
+
+	// Analisis butir soal (p-value, daya beda, distraktor, KR-20) + ekspor CSV
+	quizzes.Get("/:id/item-analysis", uqAttemptCtrl.ItemAnalysis) // GET /api/t/quizzes-teacher/:id/item-analysis?basis=first|last|best&format=csv
 }
 
 // Hindari duplikasi handler antara quiz-questions-teacher dan alias quiz-items-teacher
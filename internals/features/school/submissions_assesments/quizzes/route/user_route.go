@@ -71,4 +71,11 @@ func mountUserAttemptsGroup(g fiber.Router, ctrl *studentAttemptsController.Stud
 	g.Post("/", ctrl.Create)      // POST create attempt
 	g.Patch("/:id", ctrl.Patch)   // PATCH attempt by id
 	g.Delete("/:id", ctrl.Delete) // DELETE attempt by id
+
+	g.Get("/:id/questions", ctrl.Questions) // GET set soal attempt (urutan & opsi sesuai set)
+
+	// Sesi pengerjaan (timer server, autosave, log integritas)
+	g.Post("/:id/questions/:question_id/open", ctrl.OpenQuestion) // POST buka soal (mulai timer per-soal)
+	g.Put("/:id/answers/:question_id", ctrl.SaveAnswer)           // PUT autosave jawaban
+	g.Post("/:id/events", ctrl.RecordEvents)                      // POST batch event (tab_switch, focus_lost, ...)
 }
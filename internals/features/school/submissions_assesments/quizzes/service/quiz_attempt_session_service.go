@@ -0,0 +1,516 @@
+// file: internals/features/school/submissions_assesments/quizzes/service/quiz_attempt_session_service.go
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	qmodel "madinahsalam_backend/internals/features/school/submissions_assesments/quizzes/model"
+)
+
+/* =========================================================
+   SESI ATTEMPT (waktu versi server + sinyal integritas)
+   - mulai attempt  → token + started_at + deadline dari server
+   - strict mode    → timer per soal (buka soal → jawab sebelum habis)
+   - jawaban draft  → dipakai saat submit telat / auto-submit worker
+   - event klien    → pindah tab, hilang fokus, reconnect, ...
+========================================================= */
+
+var (
+	ErrSessionRequired     = errors.New("quiz ini berbatas waktu: mulai attempt terlebih dahulu")
+	ErrSessionNotFound     = errors.New("sesi attempt tidak aktif")
+	ErrSessionTokenInvalid = errors.New("token sesi attempt tidak valid")
+	ErrAttemptExpired      = errors.New("waktu pengerjaan sudah habis")
+	ErrAttemptConflict     = errors.New("attempt sudah disubmit")
+	ErrQuestionTimeUp      = errors.New("waktu soal ini sudah habis")
+	ErrQuestionNotOpened   = errors.New("soal belum dibuka")
+	ErrQuestionNotInSet    = errors.New("soal tidak termasuk attempt ini")
+	ErrEventKindInvalid    = errors.New("jenis event tidak dikenal")
+	ErrTooManyEvents       = errors.New("maksimal 100 event per kiriman")
+)
+
+// AttemptGrace: toleransi latensi jaringan terhadap deadline.
+const AttemptGrace = 30 * time.Second
+
+const maxEventsPerBatch = 100
+
+type QuizAttemptSessionService struct {
+	DB *gorm.DB
+}
+
+func NewQuizAttemptSessionService(db *gorm.DB) *QuizAttemptSessionService {
+	return &QuizAttemptSessionService{DB: db}
+}
+
+func newSessionToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// requiresSession: quiz berbatas waktu / strict wajib lewat sesi server.
+func requiresSession(quiz *qmodel.QuizModel, st quizTypeSettings) bool {
+	return (quiz.QuizTimeLimitSec != nil && *quiz.QuizTimeLimitSec > 0) || st.StrictMode
+}
+
+func recordEvent(tx *gorm.DB, a *qmodel.StudentQuizAttemptModel, attemptNo int, kind string, questionID *uuid.UUID) error {
+	return tx.Create(&qmodel.StudentQuizAttemptEventModel{
+		StudentQuizAttemptEventSchoolID:       a.StudentQuizAttemptSchoolID,
+		StudentQuizAttemptEventAttemptID:      a.StudentQuizAttemptID,
+		StudentQuizAttemptEventAttemptNo:      attemptNo,
+		StudentQuizAttemptEventKind:           kind,
+		StudentQuizAttemptEventQuizQuestionID: questionID,
+	}).Error
+}
+
+/* =========================================================
+   START
+========================================================= */
+
+// StartSession: keluarkan sesi untuk attempt berikutnya (count+1).
+// Dipanggil ulang (reload halaman) → sesi yang sama + event "resume".
+func (s *QuizAttemptSessionService) StartSession(ctx context.Context, attemptID uuid.UUID, now time.Time) (*qmodel.StudentQuizAttemptSession, error) {
+	var out *qmodel.StudentQuizAttemptSession
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var a qmodel.StudentQuizAttemptModel
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("student_quiz_attempt_id = ?", attemptID).
+			Take(&a).Error; err != nil {
+			return err
+		}
+		if cur := a.ActiveSession(); cur != nil {
+			out = cur
+			return recordEvent(tx, &a, cur.AttemptNo, qmodel.AttemptEventResume, nil)
+		}
+
+		var quiz qmodel.QuizModel
+		if err := tx.Where("quiz_id = ? AND quiz_school_id = ?", a.StudentQuizAttemptQuizID, a.StudentQuizAttemptSchoolID).
+			Take(&quiz).Error; err != nil {
+			return err
+		}
+		st, err := resolveTypeSettings(tx, &quiz)
+		if err != nil {
+			return err
+		}
+
+		// jumlah soal attempt ini (untuk deadline strict tanpa batas waktu quiz)
+		n := 0
+		if set := a.PendingQuestionSet(); set != nil {
+			n = len(set.Questions)
+		} else {
+			var cnt int64
+			if err := tx.Model(&qmodel.QuizQuestionModel{}).
+				Where("quiz_question_quiz_id = ? AND quiz_question_is_pool = FALSE", quiz.QuizID).
+				Count(&cnt).Error; err != nil {
+				return err
+			}
+			n = int(cnt)
+		}
+
+		token, err := newSessionToken()
+		if err != nil {
+			return err
+		}
+		sess := &qmodel.StudentQuizAttemptSession{
+			AttemptNo:  a.StudentQuizAttemptCount + 1,
+			Token:      token,
+			StartedAt:  now,
+			StrictMode: st.StrictMode,
+		}
+		if st.StrictMode && st.TimePerQuestionSec != nil && *st.TimePerQuestionSec > 0 {
+			sec := *st.TimePerQuestionSec
+			sess.TimePerQuestionSec = &sec
+		}
+		switch {
+		case quiz.QuizTimeLimitSec != nil && *quiz.QuizTimeLimitSec > 0:
+			dl := now.Add(time.Duration(*quiz.QuizTimeLimitSec) * time.Second)
+			sess.DeadlineAt = &dl
+		case sess.TimePerQuestionSec != nil && n > 0:
+			dl := now.Add(time.Duration(*sess.TimePerQuestionSec*n) * time.Second)
+			sess.DeadlineAt = &dl
+		}
+
+		if err := a.SetSession(sess); err != nil {
+			return err
+		}
+		if err := tx.Model(&qmodel.StudentQuizAttemptModel{}).
+			Where("student_quiz_attempt_id = ?", a.StudentQuizAttemptID).
+			Updates(map[string]any{
+				"student_quiz_attempt_session":     a.StudentQuizAttemptSession,
+				"student_quiz_attempt_deadline_at": a.StudentQuizAttemptDeadlineAt,
+				"student_quiz_attempt_started_at":  now,
+				"student_quiz_attempt_status":      qmodel.StudentQuizAttemptInProgress,
+			}).Error; err != nil {
+			return err
+		}
+		out = sess
+		return nil
+	})
+	return out, err
+}
+
+/* =========================================================
+   SOAL & JAWABAN DRAFT
+========================================================= */
+
+// lockSession: attempt (FOR UPDATE) + sesi aktif dengan token cocok.
+func lockSession(tx *gorm.DB, attemptID uuid.UUID, token string) (*qmodel.StudentQuizAttemptModel, *qmodel.StudentQuizAttemptSession, error) {
+	var a qmodel.StudentQuizAttemptModel
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("student_quiz_attempt_id = ?", attemptID).
+		Take(&a).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrSessionNotFound
+		}
+		return nil, nil, err
+	}
+	sess := a.ActiveSession()
+	if sess == nil {
+		return nil, nil, ErrSessionNotFound
+	}
+	if token == "" || token != sess.Token {
+		return nil, nil, ErrSessionTokenInvalid
+	}
+	return &a, sess, nil
+}
+
+func saveSession(tx *gorm.DB, a *qmodel.StudentQuizAttemptModel, sess *qmodel.StudentQuizAttemptSession) error {
+	if err := a.SetSession(sess); err != nil {
+		return err
+	}
+	return tx.Model(&qmodel.StudentQuizAttemptModel{}).
+		Where("student_quiz_attempt_id = ?", a.StudentQuizAttemptID).
+		Update("student_quiz_attempt_session", a.StudentQuizAttemptSession).Error
+}
+
+// questionInAttempt: soal harus ada di set attempt (atau soal tetap quiz bila tanpa set).
+func questionInAttempt(tx *gorm.DB, a *qmodel.StudentQuizAttemptModel, questionID uuid.UUID) error {
+	if set := a.PendingQuestionSet(); set != nil {
+		for _, it := range set.Questions {
+			if it.QuizQuestionID == questionID {
+				return nil
+			}
+		}
+		return ErrQuestionNotInSet
+	}
+	var n int64
+	if err := tx.Model(&qmodel.QuizQuestionModel{}).
+		Where("quiz_question_id = ? AND quiz_question_quiz_id = ? AND quiz_question_is_pool = FALSE", questionID, a.StudentQuizAttemptQuizID).
+		Count(&n).Error; err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrQuestionNotInSet
+	}
+	return nil
+}
+
+// OpenQuestion: catat soal mulai dikerjakan; strict mode → timer per soal berjalan.
+func (s *QuizAttemptSessionService) OpenQuestion(ctx context.Context, attemptID uuid.UUID, token string, questionID uuid.UUID, now time.Time) (*qmodel.StudentQuizAttemptSessionQuestion, error) {
+	var out *qmodel.StudentQuizAttemptSessionQuestion
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		a, sess, err := lockSession(tx, attemptID, token)
+		if err != nil {
+			return err
+		}
+		if sess.DeadlineAt != nil && now.After(*sess.DeadlineAt) {
+			return ErrAttemptExpired
+		}
+		if q, ok := sess.Questions[questionID.String()]; ok {
+			out = q
+			return nil
+		}
+		if err := questionInAttempt(tx, a, questionID); err != nil {
+			return err
+		}
+		q := &qmodel.StudentQuizAttemptSessionQuestion{OpenedAt: now}
+		if sess.TimePerQuestionSec != nil {
+			dl := now.Add(time.Duration(*sess.TimePerQuestionSec) * time.Second)
+			if sess.DeadlineAt != nil && dl.After(*sess.DeadlineAt) {
+				dl = *sess.DeadlineAt
+			}
+			q.DeadlineAt = &dl
+		}
+		if sess.Questions == nil {
+			sess.Questions = map[string]*qmodel.StudentQuizAttemptSessionQuestion{}
+		}
+		sess.Questions[questionID.String()] = q
+		out = q
+		return saveSession(tx, a, sess)
+	})
+	return out, err
+}
+
+// SaveAnswer: simpan jawaban draft. Lewat timer soal (strict) → ditolak & soal ditandai timeout.
+func (s *QuizAttemptSessionService) SaveAnswer(ctx context.Context, attemptID uuid.UUID, token string, questionID uuid.UUID, answer string, now time.Time) (*qmodel.StudentQuizAttemptSessionQuestion, error) {
+	var out *qmodel.StudentQuizAttemptSessionQuestion
+	timeUp := false
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		a, sess, err := lockSession(tx, attemptID, token)
+		if err != nil {
+			return err
+		}
+		if sess.DeadlineAt != nil && now.After(sess.DeadlineAt.Add(AttemptGrace)) {
+			return ErrAttemptExpired
+		}
+		key := questionID.String()
+		q, ok := sess.Questions[key]
+		if !ok {
+			if sess.TimePerQuestionSec != nil {
+				return ErrQuestionNotOpened
+			}
+			if err := questionInAttempt(tx, a, questionID); err != nil {
+				return err
+			}
+			q = &qmodel.StudentQuizAttemptSessionQuestion{OpenedAt: now}
+			if sess.Questions == nil {
+				sess.Questions = map[string]*qmodel.StudentQuizAttemptSessionQuestion{}
+			}
+			sess.Questions[key] = q
+		}
+		if q.DeadlineAt != nil && now.After(q.DeadlineAt.Add(AttemptGrace)) {
+			timeUp = true
+			if q.TimedOut {
+				return nil
+			}
+			q.TimedOut = true
+			if err := recordEvent(tx, a, sess.AttemptNo, qmodel.AttemptEventQuestionTimeout, &questionID); err != nil {
+				return err
+			}
+			return saveSession(tx, a, sess)
+		}
+		ans := answer
+		at := now
+		q.Answer, q.AnsweredAt = &ans, &at
+		out = q
+		return saveSession(tx, a, sess)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if timeUp {
+		return nil, ErrQuestionTimeUp
+	}
+	return out, nil
+}
+
+/* =========================================================
+   EVENT KLIEN
+========================================================= */
+
+type AttemptEventInput struct {
+	Kind           string
+	QuizQuestionID *uuid.UUID
+	ClientAt       *time.Time
+	Meta           json.RawMessage
+}
+
+// RecordEvents: terima batch event dari klien selama sesi aktif (termasuk masa toleransi).
+func (s *QuizAttemptSessionService) RecordEvents(ctx context.Context, attemptID uuid.UUID, token string, events []AttemptEventInput, now time.Time) (int, error) {
+	if len(events) > maxEventsPerBatch {
+		return 0, ErrTooManyEvents
+	}
+	for _, e := range events {
+		if !qmodel.ClientAttemptEventKind(e.Kind) {
+			return 0, ErrEventKindInvalid
+		}
+	}
+	if len(events) == 0 {
+		return 0, nil
+	}
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		a, sess, err := lockSession(tx, attemptID, token)
+		if err != nil {
+			return err
+		}
+		if sess.DeadlineAt != nil && now.After(sess.DeadlineAt.Add(AttemptGrace)) {
+			return ErrAttemptExpired
+		}
+		rows := make([]qmodel.StudentQuizAttemptEventModel, 0, len(events))
+		for _, e := range events {
+			r := qmodel.StudentQuizAttemptEventModel{
+				StudentQuizAttemptEventSchoolID:       a.StudentQuizAttemptSchoolID,
+				StudentQuizAttemptEventAttemptID:      a.StudentQuizAttemptID,
+				StudentQuizAttemptEventAttemptNo:      sess.AttemptNo,
+				StudentQuizAttemptEventKind:           e.Kind,
+				StudentQuizAttemptEventQuizQuestionID: e.QuizQuestionID,
+				StudentQuizAttemptEventClientAt:       e.ClientAt,
+			}
+			if len(e.Meta) > 0 && string(e.Meta) != "null" {
+				r.StudentQuizAttemptEventMeta = datatypes.JSON(e.Meta)
+			}
+			rows = append(rows, r)
+		}
+		return tx.Create(&rows).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(events), nil
+}
+
+/* =========================================================
+   FINAL (dipakai SubmitAttempt)
+========================================================= */
+
+// finalAnswers: jawaban draft sebagai dasar; jawaban kiriman terakhir hanya diterima
+// bila belum telat dan (strict) soal sudah dibuka & timernya belum habis.
+func finalAnswers(sess *qmodel.StudentQuizAttemptSession, payload map[uuid.UUID]string, late bool, now time.Time) (map[uuid.UUID]string, int) {
+	out := make(map[uuid.UUID]string, len(payload)+len(sess.Questions))
+	timedOut := 0
+	for k, q := range sess.Questions {
+		if q.TimedOut || (q.DeadlineAt != nil && now.After(q.DeadlineAt.Add(AttemptGrace))) {
+			timedOut++
+		}
+		id, err := uuid.Parse(k)
+		if err != nil || q.Answer == nil || *q.Answer == "" {
+			continue
+		}
+		out[id] = *q.Answer
+	}
+	if late {
+		return out, timedOut
+	}
+	for id, ans := range payload {
+		if sess.TimePerQuestionSec != nil {
+			q, ok := sess.Questions[id.String()]
+			if !ok || (q.DeadlineAt != nil && now.After(q.DeadlineAt.Add(AttemptGrace))) {
+				continue
+			}
+		}
+		out[id] = ans
+	}
+	return out, timedOut
+}
+
+func eventCounts(db *gorm.DB, attemptID uuid.UUID, attemptNo int) (map[string]int, error) {
+	var rows []struct {
+		Kind string `gorm:"column:kind"`
+		N    int    `gorm:"column:n"`
+	}
+	if err := db.Model(&qmodel.StudentQuizAttemptEventModel{}).
+		Select("student_quiz_attempt_event_kind AS kind, COUNT(*) AS n").
+		Where("student_quiz_attempt_event_attempt_id = ? AND student_quiz_attempt_event_attempt_no = ?", attemptID, attemptNo).
+		Group("student_quiz_attempt_event_kind").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make(map[string]int, len(rows))
+	for _, r := range rows {
+		out[r.Kind] = r.N
+	}
+	return out, nil
+}
+
+/* =========================================================
+   AUTO-SUBMIT (worker)
+========================================================= */
+
+// AutoSubmitExpired: submit attempt yang lewat deadline (+toleransi) memakai jawaban draft.
+func AutoSubmitExpired(ctx context.Context, db *gorm.DB, now time.Time, limit int) (int, error) {
+	var ids []uuid.UUID
+	if err := db.WithContext(ctx).Model(&qmodel.StudentQuizAttemptModel{}).
+		Where("student_quiz_attempt_deadline_at IS NOT NULL AND student_quiz_attempt_deadline_at < ?", now.Add(-AttemptGrace)).
+		Order("student_quiz_attempt_deadline_at ASC").
+		Limit(limit).
+		Pluck("student_quiz_attempt_id", &ids).Error; err != nil {
+		return 0, err
+	}
+	svc := NewStudentQuizAttemptService(db)
+	done := 0
+	for _, id := range ids {
+		if _, err := svc.SubmitAttempt(ctx, &SubmitQuizAttemptInput{AttemptID: id, AutoSubmit: true}); err != nil {
+			if errors.Is(err, ErrAttemptConflict) {
+				continue
+			}
+			log.Printf("[QuizAttemptSession] auto-submit attempt=%s error: %v", id, err)
+			continue
+		}
+		done++
+	}
+	return done, nil
+}
+
+/* =========================================================
+   LOG UNTUK GURU
+========================================================= */
+
+type AttemptEventSummary struct {
+	AttemptNo   int                                   `json:"attempt_no"`
+	InProgress  bool                                  `json:"in_progress"`
+	Integrity   *qmodel.StudentQuizAttemptIntegrity   `json:"integrity,omitempty"`
+	EventCounts map[string]int                        `json:"event_counts"`
+	Events      []qmodel.StudentQuizAttemptEventModel `json:"events"`
+}
+
+// AttemptEventLog: ringkasan + daftar event per attempt_no (opsional filter satu attempt_no).
+func AttemptEventLog(ctx context.Context, db *gorm.DB, schoolID, attemptID uuid.UUID, attemptNo *int) ([]AttemptEventSummary, error) {
+	var a qmodel.StudentQuizAttemptModel
+	if err := db.WithContext(ctx).
+		Where("student_quiz_attempt_id = ? AND student_quiz_attempt_school_id = ?", attemptID, schoolID).
+		Take(&a).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGradeAttemptNotFound
+		}
+		return nil, err
+	}
+	history, err := a.ParseAttemptHistory()
+	if err != nil {
+		return nil, err
+	}
+
+	q := db.WithContext(ctx).
+		Where("student_quiz_attempt_event_attempt_id = ?", a.StudentQuizAttemptID).
+		Order("student_quiz_attempt_event_created_at ASC").
+		Limit(2000)
+	if attemptNo != nil {
+		q = q.Where("student_quiz_attempt_event_attempt_no = ?", *attemptNo)
+	}
+	var events []qmodel.StudentQuizAttemptEventModel
+	if err := q.Find(&events).Error; err != nil {
+		return nil, err
+	}
+
+	byNo := map[int]*AttemptEventSummary{}
+	get := func(no int) *AttemptEventSummary {
+		if s, ok := byNo[no]; ok {
+			return s
+		}
+		s := &AttemptEventSummary{AttemptNo: no, EventCounts: map[string]int{}, Events: []qmodel.StudentQuizAttemptEventModel{}}
+		byNo[no] = s
+		return s
+	}
+	for _, h := range history {
+		if attemptNo == nil || h.AttemptNo == *attemptNo {
+			get(h.AttemptNo).Integrity = h.Integrity
+		}
+	}
+	if sess := a.ActiveSession(); sess != nil && (attemptNo == nil || sess.AttemptNo == *attemptNo) {
+		get(sess.AttemptNo).InProgress = true
+	}
+	for _, e := range events {
+		s := get(e.StudentQuizAttemptEventAttemptNo)
+		s.EventCounts[e.StudentQuizAttemptEventKind]++
+		s.Events = append(s.Events, e)
+	}
+
+	out := make([]AttemptEventSummary, 0, len(byNo))
+	for _, s := range byNo {
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].AttemptNo < out[j].AttemptNo })
+	return out, nil
+}
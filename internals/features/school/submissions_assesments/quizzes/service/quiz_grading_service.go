@@ -0,0 +1,522 @@
+// file: internals/features/school/submissions_assesments/quizzes/service/quiz_grading_service.go
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	qmodel "madinahsalam_backend/internals/features/school/submissions_assesments/quizzes/model"
+	subsvc "madinahsalam_backend/internals/features/school/submissions_assesments/submissions/service"
+)
+
+var (
+	ErrGradeAttemptNotFound  = errors.New("attempt tidak ditemukan")
+	ErrGradeAttemptNoMissing = errors.New("attempt_no tidak ditemukan di history")
+	ErrGradeItemNotFound     = errors.New("soal tidak ditemukan di attempt tersebut")
+	ErrGradeNotEssay         = errors.New("hanya soal essay yang dinilai manual")
+	ErrGradePointsRange      = errors.New("points_earned harus di antara 0 dan bobot soal")
+	ErrGradeEmpty            = errors.New("grades wajib diisi")
+	ErrRegradeQuizNotFound   = errors.New("quiz tidak ditemukan")
+)
+
+/* =========================================================
+   SERVICE
+========================================================= */
+
+// QuizGradingService: antrean penilaian essay + regrade setelah kunci jawaban berubah.
+// Semua perubahan nilai menulis ulang JSON history attempt, menghitung ulang
+// best/last/first/avg, lalu sinkron ke submissions.
+type QuizGradingService struct {
+	DB *gorm.DB
+}
+
+func NewQuizGradingService(db *gorm.DB) *QuizGradingService {
+	return &QuizGradingService{DB: db}
+}
+
+func round3(v float64) float64 { return math.Round(v*1000) / 1000 }
+
+/* =========================================================
+   ANTREAN ESSAY
+========================================================= */
+
+type EssayQueueFilter struct {
+	QuizID       *uuid.UUID
+	AssessmentID *uuid.UUID
+	StudentID    *uuid.UUID
+	Offset       int
+	Limit        int
+}
+
+type EssayQueueItem struct {
+	StudentQuizAttemptID uuid.UUID `json:"student_quiz_attempt_id"`
+	QuizID               uuid.UUID `json:"quiz_id"`
+	StudentID            uuid.UUID `json:"student_id"`
+	StudentName          *string   `json:"student_name,omitempty"`
+	StudentCode          *string   `json:"student_code,omitempty"`
+	AttemptNo            int       `json:"attempt_no"`
+	AttemptFinishedAt    time.Time `json:"attempt_finished_at"`
+	QuizQuestionID       uuid.UUID `json:"quiz_question_id"`
+	QuizQuestionVersion  int       `json:"quiz_question_version"`
+	QuizQuestionText     string    `json:"quiz_question_text,omitempty"`
+	Points               float64   `json:"points"`
+	AnswerEssay          *string   `json:"answer_essay,omitempty"`
+	Answered             bool      `json:"answered"`
+}
+
+// EssayQueue: item essay yang belum dinilai (semua attempt), urut attempt selesai paling lama dulu.
+func (s *QuizGradingService) EssayQueue(ctx context.Context, schoolID uuid.UUID, f EssayQueueFilter) ([]EssayQueueItem, int, error) {
+	q := s.DB.WithContext(ctx).Model(&qmodel.StudentQuizAttemptModel{}).
+		Where("student_quiz_attempt_school_id = ?", schoolID).
+		// prefilter di DB: hanya row yang punya item essay tanpa is_correct
+		Where(`EXISTS (
+			SELECT 1
+			FROM jsonb_array_elements(student_quiz_attempt_history) h,
+			     jsonb_array_elements(h->'items') it
+			WHERE it->>'quiz_question_type' = 'essay' AND (it->>'is_correct') IS NULL
+		)`)
+	if f.QuizID != nil {
+		q = q.Where("student_quiz_attempt_quiz_id = ?", *f.QuizID)
+	}
+	if f.AssessmentID != nil {
+		q = q.Where(`student_quiz_attempt_quiz_id IN (
+			SELECT quiz_id FROM quizzes WHERE quiz_assessment_id = ? AND quiz_school_id = ? AND quiz_deleted_at IS NULL
+		)`, *f.AssessmentID, schoolID)
+	}
+	if f.StudentID != nil {
+		q = q.Where("student_quiz_attempt_student_id = ?", *f.StudentID)
+	}
+
+	var attempts []qmodel.StudentQuizAttemptModel
+	if err := q.Find(&attempts).Error; err != nil {
+		return nil, 0, err
+	}
+
+	out := make([]EssayQueueItem, 0)
+	for i := range attempts {
+		a := &attempts[i]
+		history, err := a.ParseAttemptHistory()
+		if err != nil {
+			log.Printf("[QuizGradingService] skip attempt %s: %v", a.StudentQuizAttemptID, err)
+			continue
+		}
+		for _, h := range history {
+			for _, it := range h.Items {
+				if !it.NeedsGrading() {
+					continue
+				}
+				out = append(out, EssayQueueItem{
+					StudentQuizAttemptID: a.StudentQuizAttemptID,
+					QuizID:               a.StudentQuizAttemptQuizID,
+					StudentID:            a.StudentQuizAttemptStudentID,
+					StudentName:          a.StudentQuizAttemptUserProfileNameSnapshot,
+					StudentCode:          a.StudentQuizAttemptSchoolStudentCodeCache,
+					AttemptNo:            h.AttemptNo,
+					AttemptFinishedAt:    h.AttemptFinishedAt,
+					QuizQuestionID:       it.QuizQuestionID,
+					QuizQuestionVersion:  it.QuizQuestionVersion,
+					Points:               it.Points,
+					AnswerEssay:          it.AnswerEssay,
+					Answered:             it.AnswerEssay != nil && strings.TrimSpace(*it.AnswerEssay) != "",
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if !out[i].AttemptFinishedAt.Equal(out[j].AttemptFinishedAt) {
+			return out[i].AttemptFinishedAt.Before(out[j].AttemptFinishedAt)
+		}
+		return out[i].AttemptNo < out[j].AttemptNo
+	})
+	total := len(out)
+
+	if f.Offset >= total {
+		return []EssayQueueItem{}, total, nil
+	}
+	end := total
+	if f.Limit > 0 && f.Offset+f.Limit < end {
+		end = f.Offset + f.Limit
+	}
+	page := out[f.Offset:end]
+
+	// teks soal (hanya untuk halaman ini)
+	qids := map[uuid.UUID]struct{}{}
+	for _, it := range page {
+		qids[it.QuizQuestionID] = struct{}{}
+	}
+	if len(qids) > 0 {
+		ids := make([]uuid.UUID, 0, len(qids))
+		for id := range qids {
+			ids = append(ids, id)
+		}
+		var qs []qmodel.QuizQuestionModel
+		if err := s.DB.WithContext(ctx).Unscoped().
+			Select("quiz_question_id", "quiz_question_text").
+			Where("quiz_question_id IN ? AND quiz_question_school_id = ?", ids, schoolID).
+			Find(&qs).Error; err != nil {
+			return nil, 0, err
+		}
+		text := make(map[uuid.UUID]string, len(qs))
+		for _, qq := range qs {
+			text[qq.QuizQuestionID] = qq.QuizQuestionText
+		}
+		for i := range page {
+			page[i].QuizQuestionText = text[page[i].QuizQuestionID]
+		}
+	}
+	return page, total, nil
+}
+
+/* =========================================================
+   PENILAIAN ESSAY
+========================================================= */
+
+type EssayGradeInput struct {
+	AttemptID      uuid.UUID
+	AttemptNo      int
+	QuizQuestionID uuid.UUID
+	PointsEarned   float64
+	Feedback       *string
+}
+
+type EssayGradeResult struct {
+	StudentQuizAttemptID uuid.UUID `json:"student_quiz_attempt_id"`
+	ItemsGraded          int       `json:"items_graded"`
+	Pending              int       `json:"pending"` // essay yang masih belum dinilai di attempt ini
+	LastPercent          *float64  `json:"last_percent,omitempty"`
+	BestPercent          *float64  `json:"best_percent,omitempty"`
+	AvgPercent           *float64  `json:"avg_percent,omitempty"`
+}
+
+// GradeEssays: nilai satu/lebih item essay (boleh lintas attempt).
+// Row attempt di-lock, lalu tiap attempt yang berubah di-sync ulang ke submission.
+func (s *QuizGradingService) GradeEssays(ctx context.Context, schoolID uuid.UUID, grades []EssayGradeInput, teacherID *uuid.UUID, now time.Time) ([]EssayGradeResult, error) {
+	if len(grades) == 0 {
+		return nil, ErrGradeEmpty
+	}
+
+	byAttempt := map[uuid.UUID][]EssayGradeInput{}
+	order := make([]uuid.UUID, 0)
+	for _, g := range grades {
+		if _, ok := byAttempt[g.AttemptID]; !ok {
+			order = append(order, g.AttemptID)
+		}
+		byAttempt[g.AttemptID] = append(byAttempt[g.AttemptID], g)
+	}
+
+	results := make([]EssayGradeResult, 0, len(order))
+	synced := make([]*qmodel.StudentQuizAttemptModel, 0, len(order))
+
+	// semua attempt dalam satu transaksi: batch dinilai utuh atau tidak sama sekali
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, attemptID := range order {
+			var a qmodel.StudentQuizAttemptModel
+			err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("student_quiz_attempt_id = ? AND student_quiz_attempt_school_id = ?", attemptID, schoolID).
+				Take(&a).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrGradeAttemptNotFound
+			}
+			if err != nil {
+				return err
+			}
+			history, err := a.ParseAttemptHistory()
+			if err != nil {
+				return err
+			}
+
+			for _, g := range byAttempt[attemptID] {
+				it, err := findItem(history, g.AttemptNo, g.QuizQuestionID)
+				if err != nil {
+					return err
+				}
+				if it.QuizQuestionType != qmodel.QuizQuestionTypeEssay {
+					return ErrGradeNotEssay
+				}
+				if g.PointsEarned < 0 || g.PointsEarned > it.Points {
+					return ErrGradePointsRange
+				}
+				earned := round3(g.PointsEarned)
+				full := earned >= it.Points
+				it.PointsEarned = earned
+				it.IsCorrect = &full
+				it.GradedByTeacherID = teacherID
+				it.GradedAt = &now
+				if g.Feedback != nil {
+					fb := strings.TrimSpace(*g.Feedback)
+					it.GradeFeedback = &fb
+					if fb == "" {
+						it.GradeFeedback = nil
+					}
+				}
+			}
+
+			if err := a.ReplaceAttemptHistory(history); err != nil {
+				return err
+			}
+			if err := tx.Save(&a).Error; err != nil {
+				return err
+			}
+
+			pending := 0
+			for _, h := range history {
+				for _, it := range h.Items {
+					if it.NeedsGrading() {
+						pending++
+					}
+				}
+			}
+			results = append(results, EssayGradeResult{
+				StudentQuizAttemptID: a.StudentQuizAttemptID,
+				ItemsGraded:          len(byAttempt[attemptID]),
+				Pending:              pending,
+				LastPercent:          a.StudentQuizAttemptLastPercent,
+				BestPercent:          a.StudentQuizAttemptBestPercent,
+				AvgPercent:           a.StudentQuizAttemptAvgPercent,
+			})
+			aa := a
+			synced = append(synced, &aa)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.syncSubmissions(ctx, synced)
+	return results, nil
+}
+
+func findItem(history []qmodel.StudentQuizAttemptHistoryItem, attemptNo int, questionID uuid.UUID) (*qmodel.StudentQuizAttemptQuestionItem, error) {
+	for i := range history {
+		if history[i].AttemptNo != attemptNo {
+			continue
+		}
+		for j := range history[i].Items {
+			if history[i].Items[j].QuizQuestionID == questionID {
+				return &history[i].Items[j], nil
+			}
+		}
+		return nil, ErrGradeItemNotFound
+	}
+	return nil, ErrGradeAttemptNoMissing
+}
+
+func (s *QuizGradingService) syncSubmissions(ctx context.Context, attempts []*qmodel.StudentQuizAttemptModel) {
+	subService := subsvc.NewSubmissionService(s.DB)
+	for _, a := range attempts {
+		if err := subService.UpsertSubmissionFromQuizAttempt(ctx, a); err != nil {
+			log.Printf("[QuizGradingService] UpsertSubmissionFromQuizAttempt error: attempt_id=%s err=%v", a.StudentQuizAttemptID, err)
+		}
+	}
+}
+
+/* =========================================================
+   REGRADE (setelah kunci jawaban / bobot berubah)
+========================================================= */
+
+type RegradeInput struct {
+	QuizID         uuid.UUID
+	QuizQuestionID *uuid.UUID // kosong = semua soal di quiz
+	DryRun         bool
+}
+
+type RegradeQuestionReport struct {
+	QuizQuestionID uuid.UUID `json:"quiz_question_id"`
+	CurrentVersion int       `json:"current_version"`
+	CurrentCorrect *string   `json:"current_correct,omitempty"`
+	CurrentPoints  float64   `json:"current_points"`
+	// kunci lama per versi yang dijawab siswa (dari quiz_question_history)
+	PreviousCorrect map[int]*string `json:"previous_correct,omitempty"`
+	ItemsScanned    int             `json:"items_scanned"`
+	ItemsChanged    int             `json:"items_changed"`
+}
+
+type RegradeReport struct {
+	QuizID          uuid.UUID               `json:"quiz_id"`
+	DryRun          bool                    `json:"dry_run"`
+	AttemptsScanned int                     `json:"attempts_scanned"`
+	AttemptsChanged int                     `json:"attempts_changed"`
+	Questions       []RegradeQuestionReport `json:"questions"`
+}
+
+// Regrade: nilai ulang semua attempt quiz memakai kunci & bobot soal versi terkini.
+// - single: dicocokkan ulang dengan quiz_question_correct saat ini
+// - essay : bobot diskalakan (nilai guru dipertahankan secara proporsional)
+// Item yang dijawab pada versi lama dicatat regraded_from_version-nya.
+func (s *QuizGradingService) Regrade(ctx context.Context, schoolID uuid.UUID, in RegradeInput, now time.Time) (*RegradeReport, error) {
+	var n int64
+	if err := s.DB.WithContext(ctx).Model(&qmodel.QuizModel{}).
+		Where("quiz_id = ? AND quiz_school_id = ?", in.QuizID, schoolID).
+		Count(&n).Error; err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, ErrRegradeQuizNotFound
+	}
+
+	qq := s.DB.WithContext(ctx).
+		Where("quiz_question_quiz_id = ? AND quiz_question_school_id = ?", in.QuizID, schoolID)
+	if in.QuizQuestionID != nil {
+		qq = qq.Where("quiz_question_id = ?", *in.QuizQuestionID)
+	}
+	var questions []qmodel.QuizQuestionModel
+	if err := qq.Find(&questions).Error; err != nil {
+		return nil, err
+	}
+	if len(questions) == 0 {
+		return nil, ErrGradeItemNotFound
+	}
+
+	qByID := make(map[uuid.UUID]*qmodel.QuizQuestionModel, len(questions))
+	reports := make(map[uuid.UUID]*RegradeQuestionReport, len(questions))
+	snapshots := make(map[uuid.UUID]map[int]qmodel.QuizQuestionHistoryItem, len(questions))
+	for i := range questions {
+		q := &questions[i]
+		qByID[q.QuizQuestionID] = q
+		reports[q.QuizQuestionID] = &RegradeQuestionReport{
+			QuizQuestionID:  q.QuizQuestionID,
+			CurrentVersion:  q.QuizQuestionVersion,
+			CurrentCorrect:  q.QuizQuestionCorrect,
+			CurrentPoints:   q.QuizQuestionPoints,
+			PreviousCorrect: map[int]*string{},
+		}
+		var hist []qmodel.QuizQuestionHistoryItem
+		if len(q.QuizQuestionHistory) > 0 {
+			_ = json.Unmarshal(q.QuizQuestionHistory, &hist)
+		}
+		snapshots[q.QuizQuestionID] = make(map[int]qmodel.QuizQuestionHistoryItem, len(hist))
+		for _, h := range hist {
+			snapshots[q.QuizQuestionID][h.Version] = h
+		}
+	}
+
+	report := &RegradeReport{QuizID: in.QuizID, DryRun: in.DryRun}
+	synced := make([]*qmodel.StudentQuizAttemptModel, 0)
+
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var attempts []qmodel.StudentQuizAttemptModel
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("student_quiz_attempt_quiz_id = ? AND student_quiz_attempt_school_id = ?", in.QuizID, schoolID).
+			Find(&attempts).Error; err != nil {
+			return err
+		}
+		report.AttemptsScanned = len(attempts)
+
+		for i := range attempts {
+			a := &attempts[i]
+			history, err := a.ParseAttemptHistory()
+			if err != nil {
+				log.Printf("[QuizGradingService] regrade skip attempt %s: %v", a.StudentQuizAttemptID, err)
+				continue
+			}
+			changed := false
+			for hi := range history {
+				for ii := range history[hi].Items {
+					it := &history[hi].Items[ii]
+					q, ok := qByID[it.QuizQuestionID]
+					if !ok {
+						continue
+					}
+					rep := reports[q.QuizQuestionID]
+					rep.ItemsScanned++
+					if it.QuizQuestionVersion != q.QuizQuestionVersion {
+						if snap, ok := snapshots[q.QuizQuestionID][it.QuizQuestionVersion]; ok {
+							rep.PreviousCorrect[it.QuizQuestionVersion] = snap.Correct
+						}
+					}
+					if regradeItem(it, q, now) {
+						rep.ItemsChanged++
+						changed = true
+					}
+				}
+			}
+			if !changed {
+				continue
+			}
+			report.AttemptsChanged++
+			if in.DryRun {
+				continue
+			}
+			if err := a.ReplaceAttemptHistory(history); err != nil {
+				return err
+			}
+			if err := tx.Save(a).Error; err != nil {
+				return err
+			}
+			synced = append(synced, a)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, q := range questions {
+		report.Questions = append(report.Questions, *reports[q.QuizQuestionID])
+	}
+	if !in.DryRun {
+		s.syncSubmissions(ctx, synced)
+	}
+	return report, nil
+}
+
+// regradeItem: terapkan kunci & bobot terkini ke satu item; true bila ada perubahan.
+func regradeItem(it *qmodel.StudentQuizAttemptQuestionItem, q *qmodel.QuizQuestionModel, now time.Time) bool {
+	oldEarned, oldPoints, oldVersion := it.PointsEarned, it.Points, it.QuizQuestionVersion
+	var oldCorrect *bool
+	if it.IsCorrect != nil {
+		v := *it.IsCorrect
+		oldCorrect = &v
+	}
+
+	switch q.QuizQuestionType {
+	case qmodel.QuizQuestionTypeSingle:
+		it.Points = q.QuizQuestionPoints
+		it.PointsEarned = 0
+		it.IsCorrect = nil
+		if it.AnswerSingle != nil && strings.TrimSpace(*it.AnswerSingle) != "" && q.QuizQuestionCorrect != nil {
+			ok := strings.EqualFold(strings.TrimSpace(*it.AnswerSingle), strings.TrimSpace(*q.QuizQuestionCorrect))
+			it.IsCorrect = &ok
+			if ok {
+				it.PointsEarned = q.QuizQuestionPoints
+			}
+		}
+	case qmodel.QuizQuestionTypeEssay:
+		if oldPoints != q.QuizQuestionPoints {
+			if it.IsCorrect != nil && oldPoints > 0 {
+				it.PointsEarned = round3(it.PointsEarned / oldPoints * q.QuizQuestionPoints)
+			}
+			it.Points = q.QuizQuestionPoints
+		}
+	default:
+		return false
+	}
+
+	sameCorrect := (oldCorrect == nil && it.IsCorrect == nil) ||
+		(oldCorrect != nil && it.IsCorrect != nil && *oldCorrect == *it.IsCorrect)
+	scoreChanged := oldEarned != it.PointsEarned || oldPoints != it.Points || !sameCorrect
+
+	if oldVersion != q.QuizQuestionVersion {
+		if it.RegradedFromVersion == nil {
+			v := oldVersion
+			it.RegradedFromVersion = &v
+		}
+		it.QuizQuestionVersion = q.QuizQuestionVersion
+	}
+	if scoreChanged {
+		it.RegradedAt = &now
+	}
+	return scoreChanged || oldVersion != q.QuizQuestionVersion
+}
@@ -0,0 +1,461 @@
+// file: internals/features/school/submissions_assesments/quizzes/service/quiz_item_analysis_service.go
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	qmodel "madinahsalam_backend/internals/features/school/submissions_assesments/quizzes/model"
+)
+
+/*
+=========================================================
+
+	ANALISIS BUTIR SOAL (item analysis)
+	Dihitung langsung dari student_quiz_attempt_history, tanpa tabel tambahan.
+
+	- 1 siswa = 1 attempt (basis: first | last | best)
+	- p-value            : proporsi benar (essay: rata-rata points_earned/points)
+	- daya beda (D)      : p kelompok atas 27% − p kelompok bawah 27% (urut attempt_percent)
+	- distraktor         : frekuensi tiap opsi soal single (total, kelompok atas, bawah)
+	- KR-20              : reliabilitas dari soal single yang dijawab semua siswa
+
+=========================================================
+*/
+
+var (
+	ErrAnalysisQuizNotFound = errors.New("quiz tidak ditemukan")
+	ErrAnalysisBasisInvalid = errors.New("basis harus first, last, atau best")
+)
+
+const (
+	AnalysisBasisFirst = "first"
+	AnalysisBasisLast  = "last"
+	AnalysisBasisBest  = "best"
+
+	analysisGroupRatio      = 0.27
+	distractorMinShare      = 0.05 // distraktor dipilih < 5% → tidak berfungsi
+	analysisQuestionTextCap = 120
+)
+
+type ItemOptionStat struct {
+	Key        string  `json:"key"`
+	IsKey      bool    `json:"is_key"`
+	Count      int     `json:"count"`
+	Proportion float64 `json:"proportion"`
+	Upper      int     `json:"upper"`
+	Lower      int     `json:"lower"`
+}
+
+type ItemStat struct {
+	No                  int                     `json:"no"`
+	QuizQuestionID      uuid.UUID               `json:"quiz_question_id"`
+	Type                qmodel.QuizQuestionType `json:"type"`
+	Text                string                  `json:"text"`
+	Points              float64                 `json:"points"`
+	Correct             *string                 `json:"correct,omitempty"`
+	Deleted             bool                    `json:"deleted,omitempty"`
+	IsPool              bool                    `json:"is_pool,omitempty"`
+	Respondents         int                     `json:"respondents"`
+	Ungraded            int                     `json:"ungraded,omitempty"` // essay belum dinilai (tidak dihitung)
+	PValue              *float64                `json:"p_value"`
+	DifficultyLabel     string                  `json:"difficulty_label,omitempty"`
+	UpperP              *float64                `json:"upper_p"`
+	LowerP              *float64                `json:"lower_p"`
+	Discrimination      *float64                `json:"discrimination"`
+	DiscriminationLabel string                  `json:"discrimination_label,omitempty"`
+	Options             []ItemOptionStat        `json:"options,omitempty"`
+	Blank               int                     `json:"blank,omitempty"`
+	Flags               []string                `json:"flags,omitempty"`
+}
+
+type ItemAnalysisReport struct {
+	QuizID        uuid.UUID  `json:"quiz_id"`
+	Basis         string     `json:"basis"`
+	Students      int        `json:"students"`
+	GroupSize     int        `json:"group_size"` // jumlah siswa di kelompok atas/bawah
+	MeanPercent   float64    `json:"mean_percent"`
+	StdDevPercent float64    `json:"stddev_percent"`
+	KR20          *float64   `json:"kr20"`
+	KR20Items     int        `json:"kr20_items"`
+	Items         []ItemStat `json:"items"`
+	GeneratedAt   time.Time  `json:"generated_at"`
+}
+
+// skor satu siswa pada satu soal
+type itemResponse struct {
+	score  float64 // 0..1
+	answer *string // single: key jawaban
+}
+
+type analysedStudent struct {
+	percent   float64
+	responses map[uuid.UUID]itemResponse
+}
+
+// pickAttempt: attempt yang dipakai sebagai basis analisis.
+func pickAttempt(history []qmodel.StudentQuizAttemptHistoryItem, basis string) *qmodel.StudentQuizAttemptHistoryItem {
+	if len(history) == 0 {
+		return nil
+	}
+	switch basis {
+	case AnalysisBasisLast:
+		return &history[len(history)-1]
+	case AnalysisBasisBest:
+		best := &history[0]
+		for i := range history {
+			if history[i].AttemptPercent > best.AttemptPercent {
+				best = &history[i]
+			}
+		}
+		return best
+	}
+	return &history[0]
+}
+
+// itemScore: single → benar/salah; essay → proporsi nilai (ok=false kalau belum dinilai).
+func itemScore(it qmodel.StudentQuizAttemptQuestionItem) (float64, bool) {
+	if it.QuizQuestionType == qmodel.QuizQuestionTypeEssay {
+		if it.IsCorrect == nil {
+			return 0, false
+		}
+		if it.Points <= 0 {
+			if *it.IsCorrect {
+				return 1, true
+			}
+			return 0, true
+		}
+		return math.Max(0, math.Min(1, it.PointsEarned/it.Points)), true
+	}
+	if it.IsCorrect != nil && *it.IsCorrect {
+		return 1, true
+	}
+	return 0, true
+}
+
+func difficultyLabel(p float64) string {
+	switch {
+	case p < 0.3:
+		return "sukar"
+	case p <= 0.7:
+		return "sedang"
+	}
+	return "mudah"
+}
+
+func discriminationLabel(d float64) string {
+	switch {
+	case d < 0:
+		return "negatif"
+	case d < 0.2:
+		return "jelek"
+	case d < 0.3:
+		return "cukup"
+	case d < 0.4:
+		return "baik"
+	}
+	return "sangat_baik"
+}
+
+func truncateText(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n]) + "…"
+}
+
+func proportion(sum float64, n int) *float64 {
+	if n == 0 {
+		return nil
+	}
+	v := round3(sum / float64(n))
+	return &v
+}
+
+// ItemAnalysis: hitung statistik butir soal satu quiz.
+func (s *QuizGradingService) ItemAnalysis(ctx context.Context, schoolID, quizID uuid.UUID, basis string, now time.Time) (*ItemAnalysisReport, error) {
+	basis = strings.ToLower(strings.TrimSpace(basis))
+	if basis == "" {
+		basis = AnalysisBasisFirst
+	}
+	if basis != AnalysisBasisFirst && basis != AnalysisBasisLast && basis != AnalysisBasisBest {
+		return nil, ErrAnalysisBasisInvalid
+	}
+
+	var n int64
+	if err := s.DB.WithContext(ctx).Model(&qmodel.QuizModel{}).
+		Where("quiz_id = ? AND quiz_school_id = ?", quizID, schoolID).
+		Count(&n).Error; err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, ErrAnalysisQuizNotFound
+	}
+
+	// soal yang sudah dihapus tetap dianalisis kalau masih ada di history
+	var questions []qmodel.QuizQuestionModel
+	if err := s.DB.WithContext(ctx).Unscoped().
+		Where("quiz_question_quiz_id = ? AND quiz_question_school_id = ?", quizID, schoolID).
+		Order("quiz_question_created_at ASC, quiz_question_id ASC").
+		Find(&questions).Error; err != nil {
+		return nil, err
+	}
+
+	var attempts []qmodel.StudentQuizAttemptModel
+	if err := s.DB.WithContext(ctx).
+		Select("student_quiz_attempt_id, student_quiz_attempt_history").
+		Where("student_quiz_attempt_quiz_id = ? AND student_quiz_attempt_school_id = ? AND student_quiz_attempt_count > 0", quizID, schoolID).
+		Find(&attempts).Error; err != nil {
+		return nil, err
+	}
+
+	/* ===== 1) Respon per siswa ===== */
+	students := make([]analysedStudent, 0, len(attempts))
+	seen := map[uuid.UUID]bool{}
+	for i := range attempts {
+		history, err := attempts[i].ParseAttemptHistory()
+		if err != nil {
+			continue
+		}
+		h := pickAttempt(history, basis)
+		if h == nil {
+			continue
+		}
+		st := analysedStudent{percent: h.AttemptPercent, responses: make(map[uuid.UUID]itemResponse, len(h.Items))}
+		for _, it := range h.Items {
+			seen[it.QuizQuestionID] = true
+			sc, ok := itemScore(it)
+			if !ok {
+				st.responses[it.QuizQuestionID] = itemResponse{score: -1} // essay belum dinilai
+				continue
+			}
+			st.responses[it.QuizQuestionID] = itemResponse{score: sc, answer: it.AnswerSingle}
+		}
+		students = append(students, st)
+	}
+
+	rep := &ItemAnalysisReport{QuizID: quizID, Basis: basis, Students: len(students), Items: []ItemStat{}, GeneratedAt: now}
+	if len(students) > 0 {
+		var sum float64
+		for _, st := range students {
+			sum += st.percent
+		}
+		mean := sum / float64(len(students))
+		var ss float64
+		for _, st := range students {
+			ss += (st.percent - mean) * (st.percent - mean)
+		}
+		rep.MeanPercent = round3(mean)
+		rep.StdDevPercent = round3(math.Sqrt(ss / float64(len(students))))
+	}
+
+	/* ===== 2) Kelompok atas & bawah (27%) ===== */
+	sort.SliceStable(students, func(i, j int) bool { return students[i].percent > students[j].percent })
+	group := int(math.Round(analysisGroupRatio * float64(len(students))))
+	if len(students) >= 2 && group < 1 {
+		group = 1
+	}
+	if group*2 > len(students) {
+		group = len(students) / 2
+	}
+	rep.GroupSize = group
+	upper := students[:group]
+	lower := students[len(students)-group:]
+
+	/* ===== 3) Statistik per soal ===== */
+	var kr20Items []uuid.UUID
+	no := 0
+	for i := range questions {
+		q := &questions[i]
+		if !seen[q.QuizQuestionID] && q.QuizQuestionDeletedAt.Valid {
+			continue
+		}
+		no++
+		st := ItemStat{
+			No:             no,
+			QuizQuestionID: q.QuizQuestionID,
+			Type:           q.QuizQuestionType,
+			Text:           truncateText(q.QuizQuestionText, analysisQuestionTextCap),
+			Points:         q.QuizQuestionPoints,
+			Correct:        q.QuizQuestionCorrect,
+			Deleted:        q.QuizQuestionDeletedAt.Valid,
+			IsPool:         q.QuizQuestionIsPool,
+		}
+		isSingle := q.QuizQuestionType == qmodel.QuizQuestionTypeSingle
+
+		// opsi: key saat ini + key lama yang masih muncul di jawaban
+		optIdx := map[string]int{}
+		if isSingle {
+			for _, k := range optionKeys(q.QuizQuestionAnswers) {
+				optIdx[k] = len(st.Options)
+				st.Options = append(st.Options, ItemOptionStat{Key: k, IsKey: q.QuizQuestionCorrect != nil && *q.QuizQuestionCorrect == k})
+			}
+		}
+		option := func(ans *string) *ItemOptionStat {
+			if ans == nil || strings.TrimSpace(*ans) == "" {
+				return nil
+			}
+			k := strings.TrimSpace(*ans)
+			idx, ok := optIdx[k]
+			if !ok {
+				idx = len(st.Options)
+				optIdx[k] = idx
+				st.Options = append(st.Options, ItemOptionStat{Key: k})
+			}
+			return &st.Options[idx]
+		}
+
+		var sum float64
+		for _, s := range students {
+			r, ok := s.responses[q.QuizQuestionID]
+			if !ok {
+				continue
+			}
+			if r.score < 0 {
+				st.Ungraded++
+				continue
+			}
+			st.Respondents++
+			sum += r.score
+			if isSingle {
+				if o := option(r.answer); o != nil {
+					o.Count++
+				} else {
+					st.Blank++
+				}
+			}
+		}
+		st.PValue = proportion(sum, st.Respondents)
+		if st.PValue != nil {
+			st.DifficultyLabel = difficultyLabel(*st.PValue)
+		}
+
+		groupP := func(g []analysedStudent, mark func(o *ItemOptionStat)) *float64 {
+			var sum float64
+			var cnt int
+			for _, s := range g {
+				r, ok := s.responses[q.QuizQuestionID]
+				if !ok || r.score < 0 {
+					continue
+				}
+				cnt++
+				sum += r.score
+				if isSingle {
+					if o := option(r.answer); o != nil {
+						mark(o)
+					}
+				}
+			}
+			return proportion(sum, cnt)
+		}
+		st.UpperP = groupP(upper, func(o *ItemOptionStat) { o.Upper++ })
+		st.LowerP = groupP(lower, func(o *ItemOptionStat) { o.Lower++ })
+		if st.UpperP != nil && st.LowerP != nil {
+			d := round3(*st.UpperP - *st.LowerP)
+			st.Discrimination = &d
+			st.DiscriminationLabel = discriminationLabel(d)
+		}
+
+		// Tanda untuk guru
+		if st.Discrimination != nil && *st.Discrimination < 0 {
+			st.Flags = append(st.Flags, "daya_beda_negatif")
+		}
+		if st.PValue != nil && (*st.PValue < 0.1 || *st.PValue > 0.95) {
+			st.Flags = append(st.Flags, "tidak_membedakan")
+		}
+		var keyUpper int
+		for _, o := range st.Options {
+			if o.IsKey {
+				keyUpper = o.Upper
+			}
+		}
+		for j := range st.Options {
+			o := &st.Options[j]
+			if st.Respondents > 0 {
+				o.Proportion = round3(float64(o.Count) / float64(st.Respondents))
+			}
+			if o.IsKey || st.Respondents == 0 {
+				continue
+			}
+			if o.Proportion < distractorMinShare {
+				st.Flags = append(st.Flags, "distraktor_tidak_berfungsi:"+o.Key)
+			}
+			if o.Upper > o.Lower {
+				st.Flags = append(st.Flags, "distraktor_menyesatkan:"+o.Key)
+			}
+			if o.Upper > keyUpper {
+				st.Flags = append(st.Flags, "kunci_perlu_dicek")
+			}
+		}
+		st.Flags = uniqueStrings(st.Flags)
+
+		// KR-20 hanya soal single (dikotomis) yang dijawab semua siswa
+		if isSingle && st.Respondents == len(students) && st.Respondents > 0 {
+			kr20Items = append(kr20Items, q.QuizQuestionID)
+		}
+		rep.Items = append(rep.Items, st)
+	}
+
+	/* ===== 4) KR-20 ===== */
+	rep.KR20Items = len(kr20Items)
+	rep.KR20 = kr20(students, kr20Items)
+	return rep, nil
+}
+
+// kr20 = k/(k-1) × (1 − Σpq / σ²), σ² = varians skor total pada soal yang dianalisis.
+func kr20(students []analysedStudent, items []uuid.UUID) *float64 {
+	k := len(items)
+	if k < 2 || len(students) < 2 {
+		return nil
+	}
+	totals := make([]float64, len(students))
+	var sumPQ float64
+	for _, id := range items {
+		var correct float64
+		for i, s := range students {
+			sc := s.responses[id].score
+			correct += sc
+			totals[i] += sc
+		}
+		p := correct / float64(len(students))
+		sumPQ += p * (1 - p)
+	}
+	var mean float64
+	for _, t := range totals {
+		mean += t
+	}
+	mean /= float64(len(totals))
+	var variance float64
+	for _, t := range totals {
+		variance += (t - mean) * (t - mean)
+	}
+	variance /= float64(len(totals))
+	if variance == 0 {
+		return nil
+	}
+	v := round3(float64(k) / float64(k-1) * (1 - sumPQ/variance))
+	return &v
+}
+
+func uniqueStrings(in []string) []string {
+	if len(in) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool, len(in))
+	out := in[:0]
+	for _, s := range in {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
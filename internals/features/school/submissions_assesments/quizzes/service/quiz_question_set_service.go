@@ -0,0 +1,253 @@
+// file: internals/features/school/submissions_assesments/quizzes/service/quiz_question_set_service.go
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	qbmodel "madinahsalam_backend/internals/features/school/submissions_assesments/question_bank/model"
+	qbsvc "madinahsalam_backend/internals/features/school/submissions_assesments/question_bank/service"
+	qmodel "madinahsalam_backend/internals/features/school/submissions_assesments/quizzes/model"
+)
+
+/* =========================================================
+   SET SOAL PER ATTEMPT
+   - soal tetap quiz (non-pool) + tarikan acak bank soal per aturan
+   - urutan soal / opsi diacak sesuai assessment type
+   - disimpan di student_quiz_attempt_question_set → penilaian deterministik
+========================================================= */
+
+var ErrQuestionSetEmpty = errors.New("quiz belum punya soal")
+
+type QuizQuestionSetService struct {
+	DB *gorm.DB
+}
+
+func NewQuizQuestionSetService(db *gorm.DB) *QuizQuestionSetService {
+	return &QuizQuestionSetService{DB: db}
+}
+
+// quizTypeSettings: pengaturan pengerjaan dari assessment type.
+type quizTypeSettings struct {
+	ShuffleQuestions   bool `gorm:"column:assessment_type_shuffle_questions"`
+	ShuffleOptions     bool `gorm:"column:assessment_type_shuffle_options"`
+	StrictMode         bool `gorm:"column:assessment_type_strict_mode"`
+	TimePerQuestionSec *int `gorm:"column:assessment_type_time_per_question_sec"`
+}
+
+// resolveTypeSettings: assessment type dari quiz, fallback ke assessment induknya.
+func resolveTypeSettings(tx *gorm.DB, quiz *qmodel.QuizModel) (quizTypeSettings, error) {
+	var f quizTypeSettings
+	typeID := quiz.QuizAssessmentTypeID
+	if typeID == nil && quiz.QuizAssessmentID != nil {
+		var row struct {
+			TypeID *uuid.UUID `gorm:"column:assessment_type_id"`
+		}
+		if err := tx.Table("assessments").
+			Select("assessment_type_id").
+			Where("assessment_id = ? AND assessment_deleted_at IS NULL", *quiz.QuizAssessmentID).
+			Scan(&row).Error; err != nil {
+			return f, err
+		}
+		typeID = row.TypeID
+	}
+	if typeID == nil {
+		return f, nil
+	}
+	err := tx.Table("assessment_types").
+		Select("assessment_type_shuffle_questions, assessment_type_shuffle_options, assessment_type_strict_mode, assessment_type_time_per_question_sec").
+		Where("assessment_type_id = ?", *typeID).
+		Scan(&f).Error
+	return f, err
+}
+
+// PrepareQuestionSet: bangun set soal untuk attempt berikutnya (count+1).
+// Set yang sudah ada untuk attempt yang sama dipakai ulang (idempotent saat reload).
+func (s *QuizQuestionSetService) PrepareQuestionSet(ctx context.Context, attemptID uuid.UUID) (*qmodel.StudentQuizAttemptQuestionSet, error) {
+	var out *qmodel.StudentQuizAttemptQuestionSet
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var attempt qmodel.StudentQuizAttemptModel
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("student_quiz_attempt_id = ?", attemptID).
+			Take(&attempt).Error; err != nil {
+			return err
+		}
+		if set := attempt.PendingQuestionSet(); set != nil {
+			out = set
+			return nil
+		}
+
+		var quiz qmodel.QuizModel
+		if err := tx.Where("quiz_id = ? AND quiz_school_id = ?", attempt.StudentQuizAttemptQuizID, attempt.StudentQuizAttemptSchoolID).
+			Take(&quiz).Error; err != nil {
+			return err
+		}
+		flags, err := resolveTypeSettings(tx, &quiz)
+		if err != nil {
+			return err
+		}
+
+		// 1) soal tetap
+		var fixed []qmodel.QuizQuestionModel
+		if err := tx.Where("quiz_question_quiz_id = ? AND quiz_question_is_pool = FALSE", quiz.QuizID).
+			Order("quiz_question_created_at ASC").
+			Find(&fixed).Error; err != nil {
+			return err
+		}
+		taken := make(map[uuid.UUID]bool, len(fixed))
+		questions := make([]*qmodel.QuizQuestionModel, 0, len(fixed))
+		ruleOf := map[uuid.UUID]uuid.UUID{}
+		for i := range fixed {
+			if fixed[i].QuizQuestionBankItemID != nil {
+				taken[*fixed[i].QuizQuestionBankItemID] = true
+			}
+			questions = append(questions, &fixed[i])
+		}
+
+		// 2) tarikan acak per aturan
+		var rules []qmodel.QuizDrawRuleModel
+		if err := tx.Where("quiz_draw_rule_quiz_id = ?", quiz.QuizID).
+			Order("quiz_draw_rule_order ASC, quiz_draw_rule_created_at ASC").
+			Find(&rules).Error; err != nil {
+			return err
+		}
+		for i := range rules {
+			r := &rules[i]
+			q := qbsvc.MatchRule(tx.Model(&qbmodel.QuestionBankItemModel{}), r)
+			if len(taken) > 0 {
+				ids := make([]uuid.UUID, 0, len(taken))
+				for id := range taken {
+					ids = append(ids, id)
+				}
+				q = q.Where("question_bank_item_id NOT IN ?", ids)
+			}
+			var items []qbmodel.QuestionBankItemModel
+			if err := q.Order("RANDOM()").Limit(r.QuizDrawRuleCount).Find(&items).Error; err != nil {
+				return err
+			}
+			for j := range items {
+				pq, err := poolQuestion(tx, quiz.QuizID, &items[j])
+				if err != nil {
+					return err
+				}
+				taken[items[j].QuestionBankItemID] = true
+				ruleOf[pq.QuizQuestionID] = r.QuizDrawRuleID
+				questions = append(questions, pq)
+			}
+		}
+		if len(questions) == 0 {
+			return ErrQuestionSetEmpty
+		}
+
+		// 3) acak urutan soal & opsi
+		if flags.ShuffleQuestions {
+			rand.Shuffle(len(questions), func(i, j int) { questions[i], questions[j] = questions[j], questions[i] })
+		}
+		set := &qmodel.StudentQuizAttemptQuestionSet{
+			AttemptNo: attempt.StudentQuizAttemptCount + 1,
+			DrawnAt:   time.Now().UTC(),
+			Questions: make([]qmodel.StudentQuizAttemptQuestionSetItem, 0, len(questions)),
+		}
+		for _, q := range questions {
+			it := qmodel.StudentQuizAttemptQuestionSetItem{
+				QuizQuestionID:      q.QuizQuestionID,
+				QuizQuestionVersion: q.QuizQuestionVersion,
+				BankItemID:          q.QuizQuestionBankItemID,
+			}
+			if rid, ok := ruleOf[q.QuizQuestionID]; ok {
+				rid := rid
+				it.DrawRuleID = &rid
+			}
+			if flags.ShuffleOptions && q.QuizQuestionType == qmodel.QuizQuestionTypeSingle {
+				it.OptionOrder = optionKeys(q.QuizQuestionAnswers)
+				rand.Shuffle(len(it.OptionOrder), func(i, j int) {
+					it.OptionOrder[i], it.OptionOrder[j] = it.OptionOrder[j], it.OptionOrder[i]
+				})
+			}
+			set.Questions = append(set.Questions, it)
+		}
+
+		buf, err := json.Marshal(set)
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&qmodel.StudentQuizAttemptModel{}).
+			Where("student_quiz_attempt_id = ?", attempt.StudentQuizAttemptID).
+			Update("student_quiz_attempt_question_set", datatypes.JSON(buf)).Error; err != nil {
+			return err
+		}
+		out = set
+		return nil
+	})
+	return out, err
+}
+
+// poolQuestion: 1 baris quiz_question (is_pool) per soal bank per quiz, dipakai ulang lintas siswa.
+func poolQuestion(tx *gorm.DB, quizID uuid.UUID, it *qbmodel.QuestionBankItemModel) (*qmodel.QuizQuestionModel, error) {
+	var q qmodel.QuizQuestionModel
+	err := tx.Where("quiz_question_quiz_id = ? AND quiz_question_bank_item_id = ? AND quiz_question_is_pool = TRUE", quizID, it.QuestionBankItemID).
+		Take(&q).Error
+	if err == nil {
+		return &q, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	nq := it.AsQuizQuestion(quizID, qmodel.QuizQuestionBankModeReference, true)
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(nq).Error; err != nil {
+		return nil, err
+	}
+	// race: baris dibuat attempt lain → ambil ulang
+	if err := tx.Where("quiz_question_quiz_id = ? AND quiz_question_bank_item_id = ? AND quiz_question_is_pool = TRUE", quizID, it.QuestionBankItemID).
+		Take(&q).Error; err != nil {
+		return nil, err
+	}
+	return &q, nil
+}
+
+func optionKeys(raw datatypes.JSON) []string {
+	var obj map[string]any
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return nil
+	}
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// LoadSetQuestions: soal sesuai urutan set (soal yang sudah dihapus dilewati).
+func LoadSetQuestions(ctx context.Context, db *gorm.DB, schoolID uuid.UUID, set *qmodel.StudentQuizAttemptQuestionSet) ([]qmodel.QuizQuestionModel, error) {
+	ids := make([]uuid.UUID, 0, len(set.Questions))
+	for _, it := range set.Questions {
+		ids = append(ids, it.QuizQuestionID)
+	}
+	var rows []qmodel.QuizQuestionModel
+	if err := db.WithContext(ctx).
+		Where("quiz_question_school_id = ? AND quiz_question_id IN ?", schoolID, ids).
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]qmodel.QuizQuestionModel, len(rows))
+	for _, r := range rows {
+		byID[r.QuizQuestionID] = r
+	}
+	out := make([]qmodel.QuizQuestionModel, 0, len(rows))
+	for _, it := range set.Questions {
+		if r, ok := byID[it.QuizQuestionID]; ok {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
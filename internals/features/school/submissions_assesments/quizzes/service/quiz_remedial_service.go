@@ -0,0 +1,478 @@
+// file: internals/features/school/submissions_assesments/quizzes/service/quiz_remedial_service.go
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+
+	qbsvc "madinahsalam_backend/internals/features/school/submissions_assesments/question_bank/service"
+	qmodel "madinahsalam_backend/internals/features/school/submissions_assesments/quizzes/model"
+	helper "madinahsalam_backend/internals/helpers"
+)
+
+/* =========================================================
+   REMEDIAL OTOMATIS
+   - cari siswa di bawah KKM (max(passing assessment type, KKM mapel))
+   - buat quiz remedial: clone quiz induk / rakit dari soal pilihan + bank soal
+   - quiz remedial hanya terlihat & bisa dikerjakan peserta (quiz_remedial_participants)
+   - nilai digabung sesuai quiz_remedial_policy saat submission disinkron
+========================================================= */
+
+var (
+	ErrRemedialQuizNotFound    = errors.New("quiz tidak ditemukan")
+	ErrRemedialModeInvalid     = errors.New("mode harus clone atau assemble")
+	ErrRemedialPolicyInvalid   = errors.New("policy harus cap_kkm, max, atau average")
+	ErrRemedialNoPassing       = errors.New("KKM belum diatur di assessment type / mapel; isi passing_percent")
+	ErrRemedialPassingRange    = errors.New("passing_percent harus 0..100")
+	ErrRemedialNoStudents      = errors.New("tidak ada siswa di bawah KKM yang belum ikut remedial ronde ini")
+	ErrRemedialStudentInvalid  = errors.New("student_ids harus siswa di bawah KKM")
+	ErrRemedialNoQuestions     = errors.New("remedial rakitan wajib punya question_ids atau bank_item_ids")
+	ErrRemedialQuestionInvalid = errors.New("question_ids harus soal dari quiz induk")
+	ErrRemedialNotParticipant  = errors.New("quiz remedial ini hanya untuk siswa yang ditetapkan")
+)
+
+const (
+	RemedialModeClone    = "clone"
+	RemedialModeAssemble = "assemble"
+
+	remedialMaxDepth = 20
+)
+
+type QuizRemedialService struct {
+	DB *gorm.DB
+}
+
+func NewQuizRemedialService(db *gorm.DB) *QuizRemedialService {
+	return &QuizRemedialService{DB: db}
+}
+
+type RemedialInput struct {
+	Mode           string
+	Policy         string
+	PassingPercent *float64    // override KKM
+	StudentIDs     []uuid.UUID // opsional: subset kandidat
+	QuestionIDs    []uuid.UUID // assemble: soal dari quiz induk
+	BankItemIDs    []uuid.UUID // assemble: soal bank (disalin)
+	Title          *string
+	TimeLimitSec   *int
+	Publish        bool
+	DryRun         bool
+}
+
+type RemedialCandidate struct {
+	StudentID uuid.UUID `json:"student_id"`
+	Name      *string   `json:"name,omitempty"`
+	Percent   float64   `json:"percent"`
+}
+
+type RemedialResult struct {
+	ParentQuizID    uuid.UUID           `json:"parent_quiz_id"`
+	RootQuizID      uuid.UUID           `json:"root_quiz_id"`
+	Round           int                 `json:"round"`
+	PassingPercent  float64             `json:"passing_percent"`
+	PassingSource   string              `json:"passing_source"` // assessment_type | class_subject | parent | manual
+	Policy          string              `json:"policy"`
+	Candidates      []RemedialCandidate `json:"candidates"`
+	DryRun          bool                `json:"dry_run"`
+	Quiz            *qmodel.QuizModel   `json:"quiz,omitempty"`
+	QuestionsCopied int                 `json:"questions_copied"`
+	RulesCopied     int                 `json:"rules_copied"`
+}
+
+/* ===== KKM ===== */
+
+// resolvePassing: max(passing assessment type, KKM mapel) dari quiz & assessment-nya.
+func resolvePassing(tx *gorm.DB, quiz *qmodel.QuizModel) (float64, string, error) {
+	var typePassing, kkm float64
+	typeID := quiz.QuizAssessmentTypeID
+	if quiz.QuizAssessmentID != nil {
+		var row struct {
+			TypeID *uuid.UUID `gorm:"column:assessment_type_id"`
+			KKM    *float64   `gorm:"column:kkm"`
+		}
+		if err := tx.Raw(`
+			SELECT a.assessment_type_id,
+			       COALESCE(NULLIF(a.assesment_min_passing_score_class_subject_snapshot, 0),
+			                csst.csst_min_passing_score_class_subject_cache)::float8 AS kkm
+			  FROM assessments a
+			  LEFT JOIN class_section_subject_teachers csst
+			    ON csst.csst_id = a.assessment_class_section_subject_teacher_id
+			 WHERE a.assessment_id = ? AND a.assessment_deleted_at IS NULL
+		`, *quiz.QuizAssessmentID).Scan(&row).Error; err != nil {
+			return 0, "", err
+		}
+		if typeID == nil {
+			typeID = row.TypeID
+		}
+		if row.KKM != nil {
+			kkm = *row.KKM
+		}
+	}
+	if typeID != nil {
+		if err := tx.Table("assessment_types").
+			Select("assessment_type_passing_score_percent").
+			Where("assessment_type_id = ?", *typeID).
+			Scan(&typePassing).Error; err != nil {
+			return 0, "", err
+		}
+	}
+	if typePassing == 0 && kkm == 0 {
+		return 0, "", ErrRemedialNoPassing
+	}
+	if kkm > typePassing {
+		return kkm, "class_subject", nil
+	}
+	return typePassing, "assessment_type", nil
+}
+
+// rootQuiz: telusuri quiz_parent_quiz_id sampai quiz utama (non-remedial).
+func rootQuiz(tx *gorm.DB, schoolID uuid.UUID, quiz *qmodel.QuizModel) (*qmodel.QuizModel, error) {
+	cur := quiz
+	for i := 0; i < remedialMaxDepth && cur.QuizIsRemedial && cur.QuizParentQuizID != nil; i++ {
+		var p qmodel.QuizModel
+		if err := tx.Where("quiz_id = ? AND quiz_school_id = ?", *cur.QuizParentQuizID, schoolID).
+			Take(&p).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				break
+			}
+			return nil, err
+		}
+		cur = &p
+	}
+	return cur, nil
+}
+
+// candidates: siswa dengan nilai efektif di quiz induk < KKM.
+// - induk quiz utama : nilai attempt terakhir
+// - induk remedial   : nilai akhir peserta (belum mengerjakan = nilai asal)
+func candidates(tx *gorm.DB, schoolID uuid.UUID, parent *qmodel.QuizModel, passing float64) ([]RemedialCandidate, error) {
+	var rows []RemedialCandidate
+	var err error
+	if parent.QuizIsRemedial {
+		err = tx.Raw(`
+			SELECT p.quiz_remedial_participant_student_id AS student_id,
+			       a.student_quiz_attempt_user_profile_name_snapshot AS name,
+			       COALESCE(p.quiz_remedial_participant_final_percent, p.quiz_remedial_participant_original_percent)::float8 AS percent
+			  FROM quiz_remedial_participants p
+			  LEFT JOIN student_quiz_attempts a
+			    ON a.student_quiz_attempt_quiz_id = p.quiz_remedial_participant_quiz_id
+			   AND a.student_quiz_attempt_student_id = p.quiz_remedial_participant_student_id
+			 WHERE p.quiz_remedial_participant_quiz_id = ? AND p.quiz_remedial_participant_school_id = ?
+			   AND COALESCE(p.quiz_remedial_participant_final_percent, p.quiz_remedial_participant_original_percent) < ?
+		`, parent.QuizID, schoolID, passing).Scan(&rows).Error
+	} else {
+		err = tx.Raw(`
+			SELECT student_quiz_attempt_student_id AS student_id,
+			       student_quiz_attempt_user_profile_name_snapshot AS name,
+			       student_quiz_attempt_last_percent::float8 AS percent
+			  FROM student_quiz_attempts
+			 WHERE student_quiz_attempt_quiz_id = ? AND student_quiz_attempt_school_id = ?
+			   AND student_quiz_attempt_count > 0
+			   AND student_quiz_attempt_last_percent < ?
+		`, parent.QuizID, schoolID, passing).Scan(&rows).Error
+	}
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].Percent < rows[j].Percent })
+	return rows, nil
+}
+
+// CreateRemedial: siapkan (atau simulasikan) quiz remedial untuk siswa di bawah KKM.
+func (s *QuizRemedialService) CreateRemedial(ctx context.Context, schoolID, parentID uuid.UUID, in RemedialInput) (*RemedialResult, error) {
+	in.Mode = strings.ToLower(strings.TrimSpace(in.Mode))
+	if in.Mode == "" {
+		in.Mode = RemedialModeClone
+	}
+	if in.Mode != RemedialModeClone && in.Mode != RemedialModeAssemble {
+		return nil, ErrRemedialModeInvalid
+	}
+	in.Policy = strings.ToLower(strings.TrimSpace(in.Policy))
+	if in.Policy == "" {
+		in.Policy = qmodel.QuizRemedialPolicyCapKKM
+	}
+	if !qmodel.ValidRemedialPolicy(in.Policy) {
+		return nil, ErrRemedialPolicyInvalid
+	}
+	if in.PassingPercent != nil && (*in.PassingPercent < 0 || *in.PassingPercent > 100) {
+		return nil, ErrRemedialPassingRange
+	}
+	if in.Mode == RemedialModeAssemble && len(in.QuestionIDs) == 0 && len(in.BankItemIDs) == 0 {
+		return nil, ErrRemedialNoQuestions
+	}
+
+	res := &RemedialResult{ParentQuizID: parentID, Policy: in.Policy, DryRun: in.DryRun, Candidates: []RemedialCandidate{}}
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var parent qmodel.QuizModel
+		if err := tx.Where("quiz_id = ? AND quiz_school_id = ?", parentID, schoolID).Take(&parent).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrRemedialQuizNotFound
+			}
+			return err
+		}
+		root, err := rootQuiz(tx, schoolID, &parent)
+		if err != nil {
+			return err
+		}
+		res.RootQuizID = root.QuizID
+		res.Round = 1
+		if parent.QuizIsRemedial && parent.QuizRemedialRound != nil {
+			res.Round = *parent.QuizRemedialRound + 1
+		}
+
+		// KKM: override > KKM yang dipakai ronde sebelumnya > assessment type / mapel
+		switch {
+		case in.PassingPercent != nil:
+			res.PassingPercent, res.PassingSource = *in.PassingPercent, "manual"
+		case parent.QuizRemedialPassingPercent != nil:
+			res.PassingPercent, res.PassingSource = *parent.QuizRemedialPassingPercent, "parent"
+		default:
+			if res.PassingPercent, res.PassingSource, err = resolvePassing(tx, root); err != nil {
+				return err
+			}
+		}
+
+		// kandidat, minus siswa yang sudah punya remedial ronde ini
+		all, err := candidates(tx, schoolID, &parent, res.PassingPercent)
+		if err != nil {
+			return err
+		}
+		var assigned []uuid.UUID
+		if err := tx.Model(&qmodel.QuizRemedialParticipantModel{}).
+			Joins("JOIN quizzes ON quiz_id = quiz_remedial_participant_quiz_id AND quiz_deleted_at IS NULL").
+			Where("quiz_remedial_participant_root_quiz_id = ? AND quiz_remedial_participant_round = ?", root.QuizID, res.Round).
+			Pluck("quiz_remedial_participant_student_id", &assigned).Error; err != nil {
+			return err
+		}
+		skip := make(map[uuid.UUID]bool, len(assigned))
+		for _, id := range assigned {
+			skip[id] = true
+		}
+		byStudent := make(map[uuid.UUID]RemedialCandidate, len(all))
+		for _, c := range all {
+			if !skip[c.StudentID] {
+				byStudent[c.StudentID] = c
+				res.Candidates = append(res.Candidates, c)
+			}
+		}
+		if len(in.StudentIDs) > 0 {
+			picked := make([]RemedialCandidate, 0, len(in.StudentIDs))
+			for _, id := range in.StudentIDs {
+				c, ok := byStudent[id]
+				if !ok {
+					return ErrRemedialStudentInvalid
+				}
+				picked = append(picked, c)
+			}
+			res.Candidates = picked
+		}
+		if len(res.Candidates) == 0 {
+			return ErrRemedialNoStudents
+		}
+		if in.DryRun {
+			return nil
+		}
+
+		/* ===== Quiz remedial ===== */
+		title := fmt.Sprintf("Remedial %d - %s", res.Round, root.QuizTitle)
+		if in.Title != nil && strings.TrimSpace(*in.Title) != "" {
+			title = strings.TrimSpace(*in.Title)
+		}
+		slug, err := helper.EnsureUniqueSlugCI(ctx, tx, "quizzes", "quiz_slug", helper.Slugify(title, 160),
+			func(q *gorm.DB) *gorm.DB {
+				return q.Where("quiz_school_id = ? AND quiz_deleted_at IS NULL", schoolID)
+			}, 160)
+		if err != nil {
+			return err
+		}
+		round := res.Round
+		passing := res.PassingPercent
+		policy := in.Policy
+		timeLimit := parent.QuizTimeLimitSec
+		if in.TimeLimitSec != nil {
+			timeLimit = in.TimeLimitSec
+		}
+		rq := &qmodel.QuizModel{
+			QuizSchoolID:               schoolID,
+			QuizAssessmentID:           parent.QuizAssessmentID, // nilai akhir masuk ke submission assessment yang sama
+			QuizAssessmentTypeID:       parent.QuizAssessmentTypeID,
+			QuizSlug:                   &slug,
+			QuizTitle:                  title,
+			QuizDescription:            parent.QuizDescription,
+			QuizIsPublished:            in.Publish,
+			QuizTimeLimitSec:           timeLimit,
+			QuizIsRemedial:             true,
+			QuizParentQuizID:           &parent.QuizID,
+			QuizRemedialRound:          &round,
+			QuizRemedialPolicy:         &policy,
+			QuizRemedialPassingPercent: &passing,
+		}
+		if err := tx.Create(rq).Error; err != nil {
+			return err
+		}
+
+		/* ===== Soal ===== */
+		qq := tx.Where("quiz_question_quiz_id = ? AND quiz_question_school_id = ? AND quiz_question_is_pool = FALSE", parent.QuizID, schoolID)
+		if in.Mode == RemedialModeAssemble {
+			qq = qq.Where("quiz_question_id IN ?", in.QuestionIDs)
+		}
+		var src []qmodel.QuizQuestionModel
+		if in.Mode == RemedialModeClone || len(in.QuestionIDs) > 0 {
+			if err := qq.Order("quiz_question_created_at ASC").Find(&src).Error; err != nil {
+				return err
+			}
+		}
+		if in.Mode == RemedialModeAssemble && len(src) != len(uniqueIDs(in.QuestionIDs)) {
+			return ErrRemedialQuestionInvalid
+		}
+		for i := range src {
+			cp := src[i]
+			cp.QuizQuestionID = uuid.Nil
+			cp.QuizQuestionQuizID = rq.QuizID
+			cp.QuizQuestionVersion = 1
+			cp.QuizQuestionHistory = datatypes.JSON([]byte("[]"))
+			cp.QuizQuestionCreatedAt, cp.QuizQuestionUpdatedAt = time.Time{}, time.Time{}
+			cp.Quiz = nil
+			if err := tx.Create(&cp).Error; err != nil {
+				return err
+			}
+			res.QuestionsCopied++
+		}
+		if len(in.BankItemIDs) > 0 {
+			imp, err := qbsvc.ImportToQuiz(ctx, tx, schoolID, rq.QuizID, in.BankItemIDs, qmodel.QuizQuestionBankModeCopy)
+			if err != nil {
+				return err
+			}
+			res.QuestionsCopied += len(imp.Imported)
+		}
+
+		// clone: aturan tarik acak bank soal ikut disalin
+		drawn := 0
+		if in.Mode == RemedialModeClone {
+			var rules []qmodel.QuizDrawRuleModel
+			if err := tx.Where("quiz_draw_rule_quiz_id = ? AND quiz_draw_rule_school_id = ?", parent.QuizID, schoolID).
+				Find(&rules).Error; err != nil {
+				return err
+			}
+			for i := range rules {
+				r := rules[i]
+				r.QuizDrawRuleID = uuid.Nil
+				r.QuizDrawRuleQuizID = rq.QuizID
+				r.QuizDrawRuleCreatedAt, r.QuizDrawRuleUpdatedAt = time.Time{}, time.Time{}
+				if err := tx.Create(&r).Error; err != nil {
+					return err
+				}
+				res.RulesCopied++
+				drawn += r.QuizDrawRuleCount
+			}
+		}
+		if res.QuestionsCopied+drawn == 0 {
+			return ErrQuestionSetEmpty
+		}
+		rq.QuizTotalQuestions = res.QuestionsCopied + drawn
+		if err := tx.Model(rq).Update("quiz_total_questions", rq.QuizTotalQuestions).Error; err != nil {
+			return err
+		}
+
+		/* ===== Peserta ===== */
+		parts := make([]qmodel.QuizRemedialParticipantModel, 0, len(res.Candidates))
+		for _, c := range res.Candidates {
+			parts = append(parts, qmodel.QuizRemedialParticipantModel{
+				QuizRemedialParticipantSchoolID:        schoolID,
+				QuizRemedialParticipantQuizID:          rq.QuizID,
+				QuizRemedialParticipantRootQuizID:      root.QuizID,
+				QuizRemedialParticipantStudentID:       c.StudentID,
+				QuizRemedialParticipantRound:           round,
+				QuizRemedialParticipantOriginalPercent: math.Round(c.Percent*1000) / 1000,
+			})
+		}
+		if err := tx.Create(&parts).Error; err != nil {
+			return err
+		}
+		res.Quiz = rq
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	out := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+/* =========================================================
+   PESERTA & AKSES
+========================================================= */
+
+type RemedialParticipantRow struct {
+	qmodel.QuizRemedialParticipantModel
+	StudentName *string `json:"student_name,omitempty"`
+}
+
+// ListParticipants: peserta quiz remedial + nilai asal / remedial / akhir.
+func (s *QuizRemedialService) ListParticipants(ctx context.Context, schoolID, quizID uuid.UUID) ([]RemedialParticipantRow, error) {
+	var n int64
+	if err := s.DB.WithContext(ctx).Model(&qmodel.QuizModel{}).
+		Where("quiz_id = ? AND quiz_school_id = ? AND quiz_is_remedial = TRUE", quizID, schoolID).
+		Count(&n).Error; err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, ErrRemedialQuizNotFound
+	}
+	rows := []RemedialParticipantRow{}
+	err := s.DB.WithContext(ctx).
+		Table("quiz_remedial_participants p").
+		Select(`p.*, COALESCE(ss.school_student_user_profile_name_cache, a.student_quiz_attempt_user_profile_name_snapshot) AS student_name`).
+		Joins("LEFT JOIN school_students ss ON ss.school_student_id = p.quiz_remedial_participant_student_id").
+		Joins(`LEFT JOIN student_quiz_attempts a
+			ON a.student_quiz_attempt_quiz_id = p.quiz_remedial_participant_root_quiz_id
+			AND a.student_quiz_attempt_student_id = p.quiz_remedial_participant_student_id`).
+		Where("p.quiz_remedial_participant_quiz_id = ? AND p.quiz_remedial_participant_school_id = ?", quizID, schoolID).
+		Order("p.quiz_remedial_participant_original_percent ASC").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// EnsureRemedialAccess: quiz remedial dengan daftar peserta hanya boleh dikerjakan pesertanya.
+// Remedial lama (tanpa peserta) tetap terbuka untuk semua siswa.
+func EnsureRemedialAccess(ctx context.Context, db *gorm.DB, quizID, studentID uuid.UUID) error {
+	var row struct {
+		IsRemedial    bool `gorm:"column:quiz_is_remedial"`
+		HasList       bool `gorm:"column:has_list"`
+		IsParticipant bool `gorm:"column:is_participant"`
+	}
+	if err := db.WithContext(ctx).Raw(`
+		SELECT q.quiz_is_remedial,
+		       EXISTS (SELECT 1 FROM quiz_remedial_participants p WHERE p.quiz_remedial_participant_quiz_id = q.quiz_id) AS has_list,
+		       EXISTS (SELECT 1 FROM quiz_remedial_participants p WHERE p.quiz_remedial_participant_quiz_id = q.quiz_id
+		                 AND p.quiz_remedial_participant_student_id = ?) AS is_participant
+		  FROM quizzes q
+		 WHERE q.quiz_id = ?
+	`, studentID, quizID).Scan(&row).Error; err != nil {
+		return err
+	}
+	if row.IsRemedial && row.HasList && !row.IsParticipant {
+		return ErrRemedialNotParticipant
+	}
+	return nil
+}
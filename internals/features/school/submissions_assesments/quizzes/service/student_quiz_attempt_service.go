@@ -12,6 +12,8 @@ import (
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 
+	webhookmodel "madinahsalam_backend/internals/features/lembaga/webhooks/model"
+	webhooksvc "madinahsalam_backend/internals/features/lembaga/webhooks/service"
 	qmodel "madinahsalam_backend/internals/features/school/submissions_assesments/quizzes/model"
 	subsvc "madinahsalam_backend/internals/features/school/submissions_assesments/submissions/service"
 )
@@ -47,6 +49,15 @@ type SubmitQuizAttemptInput struct {
 	//        - SINGLE: key option ("A","B","C",dst)
 	//        - ESSAY : text bebas
 	Answers map[uuid.UUID]string
+
+	// Token sesi dari "mulai attempt" (wajib untuk quiz berbatas waktu / strict)
+	SessionToken string
+
+	// Staff mengisi atas nama siswa → token tidak dicek (jam tetap dari server)
+	Staff bool
+
+	// Dipanggil worker saat deadline lewat → pakai jawaban draft
+	AutoSubmit bool
 }
 
 /* =========================================================
@@ -100,11 +111,70 @@ func (s *StudentQuizAttemptService) SubmitAttempt(
 		attempt.StudentQuizAttemptCount,
 	)
 
-	// 2) Load semua soal di quiz ini (tenant-safe)
+	// 1b) Sesi server: token, waktu mulai/deadline, jawaban draft
+	now := time.Now().UTC()
+	prevCount := attempt.StudentQuizAttemptCount
+	session := attempt.ActiveSession()
+	late := false
+	timedOut := 0
+	if session == nil {
+		if in.AutoSubmit {
+			// sesi basi (attempt sudah disubmit) → bersihkan deadline agar tidak dipindai lagi
+			_ = s.DB.WithContext(ctx).Model(&qmodel.StudentQuizAttemptModel{}).
+				Where("student_quiz_attempt_id = ?", attempt.StudentQuizAttemptID).
+				Updates(map[string]any{"student_quiz_attempt_session": nil, "student_quiz_attempt_deadline_at": nil}).Error
+			return nil, ErrSessionNotFound
+		}
+		var quiz qmodel.QuizModel
+		if err := s.DB.WithContext(ctx).
+			Where("quiz_id = ?", attempt.StudentQuizAttemptQuizID).
+			Take(&quiz).Error; err != nil {
+			return nil, err
+		}
+		st, err := resolveTypeSettings(s.DB.WithContext(ctx), &quiz)
+		if err != nil {
+			return nil, err
+		}
+		if !in.Staff && requiresSession(&quiz, st) {
+			return nil, ErrSessionRequired
+		}
+	} else {
+		if !in.AutoSubmit && !in.Staff && in.SessionToken != session.Token {
+			return nil, ErrSessionTokenInvalid
+		}
+		late = in.AutoSubmit || (session.DeadlineAt != nil && now.After(session.DeadlineAt.Add(AttemptGrace)))
+		in.Answers, timedOut = finalAnswers(session, in.Answers, late, now)
+		kind := qmodel.AttemptEventLateSubmit
+		if in.AutoSubmit {
+			kind = qmodel.AttemptEventAutoSubmit
+		}
+		if late {
+			if err := recordEvent(s.DB.WithContext(ctx), &attempt, session.AttemptNo, kind, nil); err != nil {
+				log.Printf("[StudentQuizAttemptService] record %s event error: %v", kind, err)
+			}
+		}
+	}
+
+	// 2) Load soal attempt ini (tenant-safe):
+	//    - ada set soal (bank soal / acak) → pakai set tsb, urut sesuai set
+	//    - tidak ada → semua soal tetap quiz (non-pool)
 	var questions []qmodel.QuizQuestionModel
-	if err := s.DB.WithContext(ctx).
+	optionOrder := map[uuid.UUID][]string{}
+	if set := attempt.PendingQuestionSet(); set != nil {
+		qs, err := LoadSetQuestions(ctx, s.DB, attempt.StudentQuizAttemptSchoolID, set)
+		if err != nil {
+			log.Printf("[StudentQuizAttemptService] ERROR load question set: %v", err)
+			return nil, err
+		}
+		questions = qs
+		for _, it := range set.Questions {
+			if len(it.OptionOrder) > 0 {
+				optionOrder[it.QuizQuestionID] = it.OptionOrder
+			}
+		}
+	} else if err := s.DB.WithContext(ctx).
 		Where("quiz_question_quiz_id = ? AND quiz_question_school_id = ?", attempt.StudentQuizAttemptQuizID, attempt.StudentQuizAttemptSchoolID).
-		Where("quiz_question_deleted_at IS NULL").
+		Where("quiz_question_deleted_at IS NULL AND quiz_question_is_pool = FALSE").
 		Find(&questions).Error; err != nil {
 
 		log.Printf("[StudentQuizAttemptService] ERROR load questions: %v", err)
@@ -131,6 +201,7 @@ func (s *StudentQuizAttemptService) SubmitAttempt(
 			QuizQuestionType:    q.QuizQuestionType,
 			Points:              q.QuizQuestionPoints,
 			PointsEarned:        0, // default 0
+			OptionOrder:         optionOrder[q.QuizQuestionID],
 		}
 
 		switch q.QuizQuestionType {
@@ -169,17 +240,23 @@ func (s *StudentQuizAttemptService) SubmitAttempt(
 		)
 	}
 
-	// 4) Tentukan startedAt & finishedAt untuk attempt kali ini
-	now := time.Now().UTC()
-
+	// 4) Tentukan startedAt & finishedAt untuk attempt kali ini (jam server;
+	//    finished_at dari klien hanya dipakai bila masuk akal: antara mulai & sekarang)
 	startedAt := now
-	if attempt.StudentQuizAttemptStartedAt != nil {
+	if session != nil {
+		startedAt = session.StartedAt
+	} else if attempt.StudentQuizAttemptStartedAt != nil {
 		startedAt = *attempt.StudentQuizAttemptStartedAt
 	}
 
 	finishedAt := now
-	if in.FinishedAt != nil {
-		finishedAt = in.FinishedAt.UTC()
+	if in.FinishedAt != nil && session == nil {
+		if f := in.FinishedAt.UTC(); !f.Before(startedAt) && !f.After(now) {
+			finishedAt = f
+		}
+	}
+	if late && session.DeadlineAt != nil && finishedAt.After(*session.DeadlineAt) {
+		finishedAt = *session.DeadlineAt
 	}
 
 	log.Printf(
@@ -238,11 +315,41 @@ func (s *StudentQuizAttemptService) SubmitAttempt(
 	// 6) Update status & timestamps global (status = selesai)
 	attempt.StudentQuizAttemptStatus = qmodel.StudentQuizAttemptFinished
 	attempt.StudentQuizAttemptFinishedAt = &finishedAt
-
-	// 7) Persist
-	if err := s.DB.WithContext(ctx).Save(&attempt).Error; err != nil {
-		log.Printf("[StudentQuizAttemptService] ERROR Save attempt: %v", err)
-		return nil, err
+	// set soal sudah terpakai (tersimpan per item di history)
+	attempt.StudentQuizAttemptQuestionSet = nil
+
+	// ringkasan integritas + tutup sesi
+	if session != nil {
+		counts, err := eventCounts(s.DB.WithContext(ctx), attempt.StudentQuizAttemptID, session.AttemptNo)
+		if err != nil {
+			log.Printf("[StudentQuizAttemptService] event counts error: %v", err)
+		}
+		if err := attempt.SetLastIntegrity(&qmodel.StudentQuizAttemptIntegrity{
+			ServerStartedAt:   session.StartedAt,
+			DeadlineAt:        session.DeadlineAt,
+			DurationSec:       int(finishedAt.Sub(session.StartedAt).Seconds()),
+			Late:              late && !in.AutoSubmit,
+			AutoSubmitted:     late,
+			TimedOutQuestions: timedOut,
+			EventCounts:       counts,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	_ = attempt.SetSession(nil)
+
+	// 7) Persist (optimistic: gagal kalau attempt yang sama sudah disubmit duluan)
+	res := s.DB.WithContext(ctx).
+		Model(&qmodel.StudentQuizAttemptModel{}).
+		Where("student_quiz_attempt_id = ? AND student_quiz_attempt_count = ?", attempt.StudentQuizAttemptID, prevCount).
+		Select("*").
+		Updates(&attempt)
+	if res.Error != nil {
+		log.Printf("[StudentQuizAttemptService] ERROR Save attempt: %v", res.Error)
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, ErrAttemptConflict
 	}
 
 	log.Printf(
@@ -259,5 +366,37 @@ func (s *StudentQuizAttemptService) SubmitAttempt(
 		// kalau mau keras, bisa return err; sekarang cuma log biar quiz tetap "berhasil"
 	}
 
+	// 9) Webhook assessment.graded (1 event per attempt ke-n)
+	s.emitGradedWebhook(ctx, &attempt)
+
 	return &attempt, nil
 }
+
+/* =========================================================
+   Outbound webhook: assessment.graded
+========================================================= */
+
+func (s *StudentQuizAttemptService) emitGradedWebhook(ctx context.Context, attempt *qmodel.StudentQuizAttemptModel) {
+	var quiz struct {
+		AssessmentID *uuid.UUID `gorm:"column:quiz_assessment_id"`
+	}
+	_ = s.DB.WithContext(ctx).
+		Model(&qmodel.QuizModel{}).
+		Select("quiz_assessment_id").
+		Where("quiz_id = ?", attempt.StudentQuizAttemptQuizID).
+		Scan(&quiz).Error
+
+	_ = webhooksvc.Emit(ctx, s.DB, attempt.StudentQuizAttemptSchoolID, webhookmodel.EventAssessmentGraded,
+		fmt.Sprintf("quiz-attempt:%s:%d", attempt.StudentQuizAttemptID, attempt.StudentQuizAttemptCount),
+		map[string]any{
+			"student_quiz_attempt_id": attempt.StudentQuizAttemptID,
+			"quiz_id":                 attempt.StudentQuizAttemptQuizID,
+			"assessment_id":           quiz.AssessmentID,
+			"school_student_id":       attempt.StudentQuizAttemptStudentID,
+			"attempt_count":           attempt.StudentQuizAttemptCount,
+			"score_raw":               attempt.StudentQuizAttemptLastRaw,
+			"score_percent":           attempt.StudentQuizAttemptLastPercent,
+			"best_percent":            attempt.StudentQuizAttemptBestPercent,
+			"finished_at":             attempt.StudentQuizAttemptFinishedAt,
+		})
+}
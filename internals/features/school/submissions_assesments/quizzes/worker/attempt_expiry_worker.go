@@ -0,0 +1,51 @@
+// file: internals/features/school/submissions_assesments/quizzes/worker/attempt_expiry_worker.go
+package worker
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"gorm.io/gorm"
+
+	svc "madinahsalam_backend/internals/features/school/submissions_assesments/quizzes/service"
+)
+
+const expiryBatch = 50
+
+// RunAttemptExpiryWorker: auto-submit attempt quiz yang lewat deadline sampai ctx dibatalkan.
+// Interval via QUIZ_EXPIRY_POLL_INTERVAL (default 30s).
+func RunAttemptExpiryWorker(ctx context.Context, db *gorm.DB) {
+	interval := 30 * time.Second
+	if v, err := time.ParseDuration(os.Getenv("QUIZ_EXPIRY_POLL_INTERVAL")); err == nil && v > 0 {
+		interval = v
+	}
+
+	log.Printf("[QUIZ-EXPIRY] worker started interval=%s", interval)
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		for {
+			n, err := svc.AutoSubmitExpired(ctx, db, time.Now().UTC(), expiryBatch)
+			if err != nil {
+				log.Printf("[QUIZ-EXPIRY] scan error: %v", err)
+				break
+			}
+			if n > 0 {
+				log.Printf("[QUIZ-EXPIRY] auto-submitted %d attempt(s)", n)
+			}
+			if n < expiryBatch {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Printf("[QUIZ-EXPIRY] worker stopped")
+			return
+		case <-t.C:
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// file: internals/features/school/submissions_assesments/rubrics/controller/rubric_grading_controller.go
+package controller
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	dto "madinahsalam_backend/internals/features/school/submissions_assesments/rubrics/dto"
+	svc "madinahsalam_backend/internals/features/school/submissions_assesments/rubrics/service"
+	helper "madinahsalam_backend/internals/helpers"
+	helperAuth "madinahsalam_backend/internals/helpers/auth"
+	"madinahsalam_backend/internals/helpers/dbtime"
+)
+
+/*
+Penilaian berbasis rubrik (assessment assignment_upload)
+
+PUT    /api/u/assessments/:id/rubric                 {"rubric_id"|null}
+GET    /api/u/assessments/:id/rubric-grades          grid submission terakhir tiap siswa × kriteria
+POST   /api/u/assessments/:id/rubric-grades/bulk     {"grades":[{"submission_id","criteria":[{"criterion_id","level_id"|"points","comment"}],"feedback"}],"release"}
+POST   /api/u/assessments/:id/rubric-grades/release  graded → returned
+
+PUT    /api/u/submissions/:id/rubric-grades          {"criteria":[...],"feedback","release"}
+GET    /api/u/submissions/:id/rubric                 guru: kapan saja; siswa: miliknya & sudah dirilis (returned)
+
+Nilai akhir = poin/maks × assessment_max_score, dipotong assessment_late_penalty_percent_snapshot bila telat.
+*/
+
+// PUT /assessments/:id/rubric
+func (h *RubricController) AttachToAssessment(c *fiber.Ctx) error {
+	schoolID, id, err := h.staffScope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	var req dto.AttachRubricRequest
+	if err := c.BodyParser(&req); err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "Payload tidak valid")
+	}
+	if req.RubricID != nil && *req.RubricID == uuid.Nil {
+		req.RubricID = nil
+	}
+	a, err := svc.AttachRubric(c.Context(), h.DB, schoolID, id, req.RubricID)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonUpdated(c, "Rubrik assessment diperbarui", fiber.Map{
+		"assessment_id":        a.AssessmentID,
+		"assessment_rubric_id": a.AssessmentRubricID,
+	})
+}
+
+// GET /assessments/:id/rubric-grades
+func (h *RubricController) AssessmentGrid(c *fiber.Ctx) error {
+	schoolID, id, err := h.staffScope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	g, err := svc.AssessmentGrid(c.Context(), h.DB, schoolID, id)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonOK(c, "OK", g)
+}
+
+// POST /assessments/:id/rubric-grades/bulk
+func (h *RubricController) BulkGrade(c *fiber.Ctx) error {
+	schoolID, id, err := h.staffScope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	var req dto.BulkGradeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "Payload tidak valid")
+	}
+	if len(req.Grades) == 0 {
+		return helper.JsonError(c, fiber.StatusBadRequest, "grades wajib diisi")
+	}
+	res, err := svc.BulkGrade(c.Context(), h.DB, schoolID, id, req.ToInput(), req.Release, teacherOf(c, schoolID), dbtime.NowInSchool(c))
+	if err != nil {
+		return writeErr(c, err)
+	}
+	ok := 0
+	for _, r := range res {
+		if r.Error == "" {
+			ok++
+		}
+	}
+	return helper.JsonOK(c, "Penilaian massal selesai", fiber.Map{
+		"succeeded": ok,
+		"failed":    len(res) - ok,
+		"items":     res,
+	})
+}
+
+// POST /assessments/:id/rubric-grades/release
+func (h *RubricController) Release(c *fiber.Ctx) error {
+	schoolID, id, err := h.staffScope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	n, err := svc.ReleaseGrades(c.Context(), h.DB, schoolID, id, dbtime.NowInSchool(c))
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonUpdated(c, "Nilai dirilis ke siswa", fiber.Map{"released": n})
+}
+
+// PUT /submissions/:id/rubric-grades
+func (h *RubricController) GradeSubmission(c *fiber.Ctx) error {
+	schoolID, id, err := h.staffScope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	var req dto.GradeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "Payload tidak valid")
+	}
+	res, err := svc.GradeSubmission(c.Context(), h.DB, schoolID, nil, req.ToInput(id), teacherOf(c, schoolID), dbtime.NowInSchool(c))
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonUpdated(c, "Nilai rubrik disimpan", res)
+}
+
+// GET /submissions/:id/rubric
+func (h *RubricController) SubmissionBreakdown(c *fiber.Ctx) error {
+	schoolID, id, err := scope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	var studentID *uuid.UUID
+	if requireStaff(c, schoolID) != nil {
+		if !helperAuth.IsStudentInSchool(c, schoolID) {
+			return helper.JsonError(c, fiber.StatusForbidden, "Akses ditolak")
+		}
+		sid, err := helperAuth.GetSchoolStudentIDForSchool(c, schoolID)
+		if err != nil || sid == uuid.Nil {
+			return helper.JsonError(c, fiber.StatusForbidden, "Akses ditolak")
+		}
+		studentID = &sid
+	}
+	b, err := svc.SubmissionBreakdown(c.Context(), h.DB, schoolID, id, studentID)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonOK(c, "OK", b)
+}
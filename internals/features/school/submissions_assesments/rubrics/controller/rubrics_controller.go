@@ -0,0 +1,219 @@
+// file: internals/features/school/submissions_assesments/rubrics/controller/rubrics_controller.go
+package controller
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	permsvc "madinahsalam_backend/internals/features/lembaga/permissions/service"
+	dto "madinahsalam_backend/internals/features/school/submissions_assesments/rubrics/dto"
+	svc "madinahsalam_backend/internals/features/school/submissions_assesments/rubrics/service"
+	helper "madinahsalam_backend/internals/helpers"
+	helperAuth "madinahsalam_backend/internals/helpers/auth"
+)
+
+/*
+Rubrik penilaian (reusable per sekolah / per mapel)
+
+GET    /api/u/rubrics?class_subject_id=&active=&q=     (+ rubrik umum sekolah)
+POST   /api/u/rubrics                                 {"name","description","class_subject_id","criteria":[{"name","levels":[{"label","points"}]}]}
+GET    /api/u/rubrics/:id
+PATCH  /api/u/rubrics/:id                             criteria hanya bisa diganti selama belum dipakai menilai
+POST   /api/u/rubrics/:id/duplicate                   {"name"}
+DELETE /api/u/rubrics/:id
+
+/api/a/rubrics → sama, dijaga permission grades.*
+*/
+
+type RubricController struct {
+	DB    *gorm.DB
+	Admin bool // route /api/a: akses sudah dijaga permission grades.*
+}
+
+func NewRubricController(db *gorm.DB) *RubricController {
+	return &RubricController{DB: db}
+}
+
+func NewRubricAdminController(db *gorm.DB) *RubricController {
+	return &RubricController{DB: db, Admin: true}
+}
+
+func writeErr(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, svc.ErrRubricNotFound), errors.Is(err, svc.ErrAssessmentNotFound), errors.Is(err, svc.ErrSubmissionNotFound):
+		return helper.JsonError(c, fiber.StatusNotFound, err.Error())
+	case errors.Is(err, svc.ErrRubricInUse), errors.Is(err, svc.ErrRubricAttached), errors.Is(err, svc.ErrRubricLocked):
+		return helper.JsonError(c, fiber.StatusConflict, err.Error())
+	case errors.Is(err, svc.ErrNotReleased):
+		return helper.JsonError(c, fiber.StatusForbidden, err.Error())
+	case errors.Is(err, svc.ErrRubricNameEmpty), errors.Is(err, svc.ErrRubricNoCriteria), errors.Is(err, svc.ErrCriterionNameEmpty),
+		errors.Is(err, svc.ErrCriterionNoLevels), errors.Is(err, svc.ErrLevelLabelEmpty), errors.Is(err, svc.ErrLevelPoints),
+		errors.Is(err, svc.ErrSubjectInvalid), errors.Is(err, svc.ErrAssessmentNotUpload), errors.Is(err, svc.ErrAssessmentNoRubric),
+		errors.Is(err, svc.ErrRubricInactive), errors.Is(err, svc.ErrSubmissionMismatch), errors.Is(err, svc.ErrCriterionInvalid),
+		errors.Is(err, svc.ErrLevelInvalid), errors.Is(err, svc.ErrPointsOutOfRange), errors.Is(err, svc.ErrNoCriteriaGraded):
+		return helper.JsonError(c, fiber.StatusBadRequest, err.Error())
+	}
+	var fe *fiber.Error
+	if errors.As(err, &fe) {
+		return helper.JsonError(c, fe.Code, fe.Message)
+	}
+	return helper.JsonError(c, fiber.StatusInternalServerError, err.Error())
+}
+
+// scope: school aktif + :id (bila ada di route).
+func scope(c *fiber.Ctx) (uuid.UUID, uuid.UUID, error) {
+	schoolID, err := permsvc.SchoolFromRequest(c)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	if c.Params("id") == "" {
+		return schoolID, uuid.Nil, nil
+	}
+	id, err := uuid.Parse(strings.TrimSpace(c.Params("id")))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fiber.NewError(fiber.StatusBadRequest, "id tidak valid")
+	}
+	return schoolID, id, nil
+}
+
+// requireStaff: /api/u hanya untuk guru/DKM/owner (admin sudah dijaga permission).
+func requireStaff(c *fiber.Ctx, schoolID uuid.UUID) error {
+	if helperAuth.IsOwner(c) || helperAuth.IsDKMInSchool(c, schoolID) || helperAuth.IsTeacherInSchool(c, schoolID) {
+		return nil
+	}
+	return fiber.NewError(fiber.StatusForbidden, "Hanya guru/DKM yang boleh mengelola rubrik")
+}
+
+func teacherOf(c *fiber.Ctx, schoolID uuid.UUID) *uuid.UUID {
+	if !helperAuth.IsTeacherInSchool(c, schoolID) {
+		return nil
+	}
+	if tid, err := helperAuth.GetSchoolTeacherIDForSchool(c, schoolID); err == nil && tid != uuid.Nil {
+		return &tid
+	}
+	return nil
+}
+
+// staffScope: scope + guard staff (dilewati untuk controller admin).
+func (h *RubricController) staffScope(c *fiber.Ctx) (uuid.UUID, uuid.UUID, error) {
+	schoolID, id, err := scope(c)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	if h.Admin {
+		return schoolID, id, nil
+	}
+	if err := requireStaff(c, schoolID); err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	return schoolID, id, nil
+}
+
+/* =========================================================
+   CRUD
+========================================================= */
+
+// GET /rubrics
+func (h *RubricController) List(c *fiber.Ctx) error {
+	schoolID, _, err := h.staffScope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	f := svc.ListFilter{Q: c.Query("q")}
+	if v := strings.TrimSpace(c.Query("class_subject_id")); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return helper.JsonError(c, fiber.StatusBadRequest, "class_subject_id tidak valid")
+		}
+		f.ClassSubjectID = &id
+	}
+	switch strings.ToLower(strings.TrimSpace(c.Query("active"))) {
+	case "1", "true", "yes":
+		f.OnlyActive = true
+	}
+	p := helper.ResolvePaging(c, 50, 200)
+	f.Offset, f.Limit = p.Offset, p.Limit
+	rows, total, err := svc.ListRubrics(c.Context(), h.DB, schoolID, f)
+	if err != nil {
+		return helper.JsonError(c, fiber.StatusInternalServerError, "Gagal mengambil rubrik")
+	}
+	return helper.JsonList(c, "OK", rows, helper.BuildPaginationFromPage(total, p.Page, p.PerPage))
+}
+
+// GET /rubrics/:id
+func (h *RubricController) Get(c *fiber.Ctx) error {
+	schoolID, id, err := h.staffScope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	m, err := svc.GetRubric(c.Context(), h.DB, schoolID, id)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonOK(c, "OK", m)
+}
+
+// POST /rubrics
+func (h *RubricController) Create(c *fiber.Ctx) error {
+	schoolID, _, err := h.staffScope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	var req dto.RubricRequest
+	if err := c.BodyParser(&req); err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "Payload tidak valid")
+	}
+	m, err := svc.CreateRubric(c.Context(), h.DB, schoolID, req.ToInput(), teacherOf(c, schoolID))
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonCreated(c, "Rubrik dibuat", m)
+}
+
+// PATCH /rubrics/:id
+func (h *RubricController) Update(c *fiber.Ctx) error {
+	schoolID, id, err := h.staffScope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	var req dto.RubricRequest
+	if err := c.BodyParser(&req); err != nil {
+		return helper.JsonError(c, fiber.StatusBadRequest, "Payload tidak valid")
+	}
+	m, err := svc.UpdateRubric(c.Context(), h.DB, schoolID, id, req.ToInput())
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonUpdated(c, "Rubrik diperbarui", m)
+}
+
+// POST /rubrics/:id/duplicate
+func (h *RubricController) Duplicate(c *fiber.Ctx) error {
+	schoolID, id, err := h.staffScope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	var req dto.DuplicateRubricRequest
+	_ = c.BodyParser(&req)
+	m, err := svc.DuplicateRubric(c.Context(), h.DB, schoolID, id, req.Name, teacherOf(c, schoolID))
+	if err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonCreated(c, "Rubrik diduplikasi", m)
+}
+
+// DELETE /rubrics/:id
+func (h *RubricController) Delete(c *fiber.Ctx) error {
+	schoolID, id, err := h.staffScope(c)
+	if err != nil {
+		return writeErr(c, err)
+	}
+	if err := svc.DeleteRubric(c.Context(), h.DB, schoolID, id); err != nil {
+		return writeErr(c, err)
+	}
+	return helper.JsonDeleted(c, "Rubrik dihapus", fiber.Map{"rubric_id": id})
+}
@@ -0,0 +1,107 @@
+// file: internals/features/school/submissions_assesments/rubrics/dto/rubrics_dto.go
+package dto
+
+import (
+	"github.com/google/uuid"
+
+	svc "madinahsalam_backend/internals/features/school/submissions_assesments/rubrics/service"
+)
+
+/* =========================================================
+   RUBRIK
+========================================================= */
+
+type LevelRequest struct {
+	Label       string  `json:"label"`
+	Description *string `json:"description"`
+	Points      float64 `json:"points"`
+}
+
+type CriterionRequest struct {
+	Name        string         `json:"name"`
+	Description *string        `json:"description"`
+	Levels      []LevelRequest `json:"levels"`
+}
+
+// Kriteria dikirim lengkap (urutan = urutan array); poin maksimal kriteria = poin level tertinggi.
+type RubricRequest struct {
+	Name           *string             `json:"name"`
+	Description    *string             `json:"description"`
+	ClassSubjectID *uuid.UUID          `json:"class_subject_id"` // uuid nol (PATCH) = rubrik umum sekolah
+	IsActive       *bool               `json:"is_active"`
+	Criteria       *[]CriterionRequest `json:"criteria"`
+}
+
+func (r RubricRequest) ToInput() svc.RubricInput {
+	in := svc.RubricInput{
+		Name:           r.Name,
+		Description:    r.Description,
+		ClassSubjectID: r.ClassSubjectID,
+		IsActive:       r.IsActive,
+	}
+	if r.Criteria != nil {
+		cs := make([]svc.CriterionInput, 0, len(*r.Criteria))
+		for _, c := range *r.Criteria {
+			ci := svc.CriterionInput{Name: c.Name, Description: c.Description}
+			for _, l := range c.Levels {
+				ci.Levels = append(ci.Levels, svc.LevelInput{Label: l.Label, Description: l.Description, Points: l.Points})
+			}
+			cs = append(cs, ci)
+		}
+		in.Criteria = &cs
+	}
+	return in
+}
+
+type DuplicateRubricRequest struct {
+	Name *string `json:"name"`
+}
+
+/* =========================================================
+   PENILAIAN
+========================================================= */
+
+type AttachRubricRequest struct {
+	RubricID *uuid.UUID `json:"rubric_id"` // null = lepas rubrik
+}
+
+// Isi "level_id" ATAU "points" per kriteria.
+type CriterionGradeRequest struct {
+	CriterionID uuid.UUID  `json:"criterion_id"`
+	LevelID     *uuid.UUID `json:"level_id"`
+	Points      *float64   `json:"points"`
+	Comment     *string    `json:"comment"`
+}
+
+type GradeRequest struct {
+	SubmissionID uuid.UUID               `json:"submission_id"` // hanya dipakai di bulk
+	Criteria     []CriterionGradeRequest `json:"criteria"`
+	Feedback     *string                 `json:"feedback"`
+	Release      bool                    `json:"release"`
+}
+
+func (r GradeRequest) ToInput(submissionID uuid.UUID) svc.GradeInput {
+	in := svc.GradeInput{SubmissionID: submissionID, Feedback: r.Feedback, Release: r.Release}
+	for _, g := range r.Criteria {
+		in.Criteria = append(in.Criteria, svc.CriterionGrade{
+			CriterionID: g.CriterionID,
+			LevelID:     g.LevelID,
+			Points:      g.Points,
+			Comment:     g.Comment,
+		})
+	}
+	return in
+}
+
+type BulkGradeRequest struct {
+	Grades  []GradeRequest `json:"grades"`
+	Release bool           `json:"release"`
+}
+
+func (r BulkGradeRequest) ToInput() []svc.GradeInput {
+	out := make([]svc.GradeInput, 0, len(r.Grades))
+	for _, g := range r.Grades {
+		out = append(out, g.ToInput(g.SubmissionID))
+	}
+	return out
+}
@@ -0,0 +1,93 @@
+// file: internals/features/school/submissions_assesments/rubrics/model/rubrics_model.go
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+/* =========================================================
+   rubrics — rubrik reusable (sekolah / mapel)
+========================================================= */
+
+type RubricModel struct {
+	RubricID                 uuid.UUID  `gorm:"column:rubric_id;type:uuid;default:gen_random_uuid();primaryKey" json:"rubric_id"`
+	RubricSchoolID           uuid.UUID  `gorm:"column:rubric_school_id;type:uuid;not null" json:"rubric_school_id"`
+	RubricClassSubjectID     *uuid.UUID `gorm:"column:rubric_class_subject_id;type:uuid" json:"rubric_class_subject_id,omitempty"`
+	RubricName               string     `gorm:"column:rubric_name;type:varchar(160);not null" json:"rubric_name"`
+	RubricDescription        *string    `gorm:"column:rubric_description;type:text" json:"rubric_description,omitempty"`
+	RubricMaxPoints          float64    `gorm:"column:rubric_max_points;type:numeric(7,2);not null;default:0" json:"rubric_max_points"`
+	RubricIsActive           bool       `gorm:"column:rubric_is_active;not null;default:true" json:"rubric_is_active"`
+	RubricCreatedByTeacherID *uuid.UUID `gorm:"column:rubric_created_by_teacher_id;type:uuid" json:"rubric_created_by_teacher_id,omitempty"`
+
+	RubricCreatedAt time.Time      `gorm:"column:rubric_created_at;autoCreateTime" json:"rubric_created_at"`
+	RubricUpdatedAt time.Time      `gorm:"column:rubric_updated_at;autoUpdateTime" json:"rubric_updated_at"`
+	RubricDeletedAt gorm.DeletedAt `gorm:"column:rubric_deleted_at;index" json:"-"`
+
+	Criteria []RubricCriterionModel `gorm:"foreignKey:RubricCriterionRubricID;references:RubricID" json:"criteria,omitempty"`
+}
+
+func (RubricModel) TableName() string { return "rubrics" }
+
+/* =========================================================
+   rubric_criteria & rubric_levels
+========================================================= */
+
+type RubricCriterionModel struct {
+	RubricCriterionID          uuid.UUID `gorm:"column:rubric_criterion_id;type:uuid;default:gen_random_uuid();primaryKey" json:"rubric_criterion_id"`
+	RubricCriterionSchoolID    uuid.UUID `gorm:"column:rubric_criterion_school_id;type:uuid;not null" json:"-"`
+	RubricCriterionRubricID    uuid.UUID `gorm:"column:rubric_criterion_rubric_id;type:uuid;not null" json:"rubric_criterion_rubric_id"`
+	RubricCriterionName        string    `gorm:"column:rubric_criterion_name;type:varchar(160);not null" json:"rubric_criterion_name"`
+	RubricCriterionDescription *string   `gorm:"column:rubric_criterion_description;type:text" json:"rubric_criterion_description,omitempty"`
+	RubricCriterionOrder       int       `gorm:"column:rubric_criterion_order;not null;default:0" json:"rubric_criterion_order"`
+	RubricCriterionMaxPoints   float64   `gorm:"column:rubric_criterion_max_points;type:numeric(6,2);not null;default:0" json:"rubric_criterion_max_points"`
+
+	RubricCriterionCreatedAt time.Time `gorm:"column:rubric_criterion_created_at;autoCreateTime" json:"-"`
+	RubricCriterionUpdatedAt time.Time `gorm:"column:rubric_criterion_updated_at;autoUpdateTime" json:"-"`
+
+	Levels []RubricLevelModel `gorm:"foreignKey:RubricLevelCriterionID;references:RubricCriterionID" json:"levels,omitempty"`
+}
+
+func (RubricCriterionModel) TableName() string { return "rubric_criteria" }
+
+type RubricLevelModel struct {
+	RubricLevelID          uuid.UUID `gorm:"column:rubric_level_id;type:uuid;default:gen_random_uuid();primaryKey" json:"rubric_level_id"`
+	RubricLevelSchoolID    uuid.UUID `gorm:"column:rubric_level_school_id;type:uuid;not null" json:"-"`
+	RubricLevelCriterionID uuid.UUID `gorm:"column:rubric_level_criterion_id;type:uuid;not null" json:"rubric_level_criterion_id"`
+	RubricLevelLabel       string    `gorm:"column:rubric_level_label;type:varchar(80);not null" json:"rubric_level_label"`
+	RubricLevelDescription *string   `gorm:"column:rubric_level_description;type:text" json:"rubric_level_description,omitempty"`
+	RubricLevelPoints      float64   `gorm:"column:rubric_level_points;type:numeric(6,2);not null" json:"rubric_level_points"`
+	RubricLevelOrder       int       `gorm:"column:rubric_level_order;not null;default:0" json:"rubric_level_order"`
+
+	RubricLevelCreatedAt time.Time `gorm:"column:rubric_level_created_at;autoCreateTime" json:"-"`
+}
+
+func (RubricLevelModel) TableName() string { return "rubric_levels" }
+
+/* =========================================================
+   submission_rubric_scores — nilai per kriteria
+========================================================= */
+
+type SubmissionRubricScoreModel struct {
+	SubmissionRubricScoreID           uuid.UUID  `gorm:"column:submission_rubric_score_id;type:uuid;default:gen_random_uuid();primaryKey" json:"submission_rubric_score_id"`
+	SubmissionRubricScoreSchoolID     uuid.UUID  `gorm:"column:submission_rubric_score_school_id;type:uuid;not null" json:"-"`
+	SubmissionRubricScoreSubmissionID uuid.UUID  `gorm:"column:submission_rubric_score_submission_id;type:uuid;not null" json:"submission_rubric_score_submission_id"`
+	SubmissionRubricScoreRubricID     uuid.UUID  `gorm:"column:submission_rubric_score_rubric_id;type:uuid;not null" json:"submission_rubric_score_rubric_id"`
+	SubmissionRubricScoreCriterionID  *uuid.UUID `gorm:"column:submission_rubric_score_criterion_id;type:uuid" json:"submission_rubric_score_criterion_id,omitempty"`
+	SubmissionRubricScoreLevelID      *uuid.UUID `gorm:"column:submission_rubric_score_level_id;type:uuid" json:"submission_rubric_score_level_id,omitempty"`
+
+	SubmissionRubricScoreCriterionName  string  `gorm:"column:submission_rubric_score_criterion_name;type:varchar(160);not null" json:"submission_rubric_score_criterion_name"`
+	SubmissionRubricScoreCriterionOrder int     `gorm:"column:submission_rubric_score_criterion_order;not null;default:0" json:"submission_rubric_score_criterion_order"`
+	SubmissionRubricScoreLevelLabel     *string `gorm:"column:submission_rubric_score_level_label;type:varchar(80)" json:"submission_rubric_score_level_label,omitempty"`
+	SubmissionRubricScorePoints         float64 `gorm:"column:submission_rubric_score_points;type:numeric(6,2);not null" json:"submission_rubric_score_points"`
+	SubmissionRubricScoreMaxPoints      float64 `gorm:"column:submission_rubric_score_max_points;type:numeric(6,2);not null" json:"submission_rubric_score_max_points"`
+	SubmissionRubricScoreComment        *string `gorm:"column:submission_rubric_score_comment;type:text" json:"submission_rubric_score_comment,omitempty"`
+
+	SubmissionRubricScoreGradedByTeacherID *uuid.UUID `gorm:"column:submission_rubric_score_graded_by_teacher_id;type:uuid" json:"submission_rubric_score_graded_by_teacher_id,omitempty"`
+	SubmissionRubricScoreCreatedAt         time.Time  `gorm:"column:submission_rubric_score_created_at;autoCreateTime" json:"submission_rubric_score_created_at"`
+	SubmissionRubricScoreUpdatedAt         time.Time  `gorm:"column:submission_rubric_score_updated_at;autoUpdateTime" json:"submission_rubric_score_updated_at"`
+}
+
+func (SubmissionRubricScoreModel) TableName() string { return "submission_rubric_scores" }
@@ -0,0 +1,50 @@
+// file: internals/features/school/submissions_assesments/rubrics/route/rubrics_route.go
+package route
+
+import (
+	rubricController "madinahsalam_backend/internals/features/school/submissions_assesments/rubrics/controller"
+	schoolkuMiddleware "madinahsalam_backend/internals/middlewares/features"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// /api/u → rubrik & penilaian per kriteria (guru/DKM), rincian nilai (siswa setelah dirilis)
+func RubricUserRoutes(api fiber.Router, db *gorm.DB) {
+	ctl := rubricController.NewRubricController(db)
+
+	r := api.Group("/rubrics")
+	r.Get("/", ctl.List)
+	r.Post("/", ctl.Create)
+	r.Get("/:id", ctl.Get)
+	r.Patch("/:id", ctl.Update)
+	r.Post("/:id/duplicate", ctl.Duplicate)
+	r.Delete("/:id", ctl.Delete)
+
+	a := api.Group("/assessments")
+	a.Put("/:id/rubric", ctl.AttachToAssessment)
+	a.Get("/:id/rubric-grades", ctl.AssessmentGrid)
+	a.Post("/:id/rubric-grades/bulk", ctl.BulkGrade)
+	a.Post("/:id/rubric-grades/release", ctl.Release)
+
+	s := api.Group("/submissions")
+	s.Put("/:id/rubric-grades", ctl.GradeSubmission)
+	s.Get("/:id/rubric", ctl.SubmissionBreakdown)
+}
+
+// /api/a/rubrics → kelola rubrik sekolah (permission grades.*)
+func RubricAdminRoutes(api fiber.Router, db *gorm.DB) {
+	ctl := rubricController.NewRubricAdminController(db)
+
+	read := schoolkuMiddleware.RequirePermission("grades.read")
+	write := schoolkuMiddleware.RequirePermission("grades.write")
+	del := schoolkuMiddleware.RequirePermission("grades.delete")
+
+	r := api.Group("/rubrics")
+	r.Get("/", read, ctl.List)
+	r.Post("/", write, ctl.Create)
+	r.Get("/:id", read, ctl.Get)
+	r.Patch("/:id", write, ctl.Update)
+	r.Post("/:id/duplicate", write, ctl.Duplicate)
+	r.Delete("/:id", del, ctl.Delete)
+}
@@ -0,0 +1,542 @@
+// file: internals/features/school/submissions_assesments/rubrics/service/grading.go
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	assessmentModel "madinahsalam_backend/internals/features/school/submissions_assesments/assesments/model"
+	model "madinahsalam_backend/internals/features/school/submissions_assesments/rubrics/model"
+	submissionModel "madinahsalam_backend/internals/features/school/submissions_assesments/submissions/model"
+)
+
+var (
+	ErrAssessmentNotFound  = errors.New("assessment tidak ditemukan")
+	ErrAssessmentNotUpload = errors.New("rubrik hanya untuk assessment jenis assignment_upload")
+	ErrAssessmentNoRubric  = errors.New("assessment belum memakai rubrik")
+	ErrRubricInactive      = errors.New("rubrik tidak aktif")
+	ErrRubricLocked        = errors.New("rubrik tidak bisa diganti karena sudah ada nilai rubrik untuk assessment ini")
+	ErrSubmissionNotFound  = errors.New("submission tidak ditemukan")
+	ErrSubmissionMismatch  = errors.New("submission bukan milik assessment ini")
+	ErrCriterionInvalid    = errors.New("kriteria bukan bagian dari rubrik assessment")
+	ErrLevelInvalid        = errors.New("level bukan bagian dari kriteria")
+	ErrPointsOutOfRange    = errors.New("poin kriteria di luar rentang 0..maksimal")
+	ErrNoCriteriaGraded    = errors.New("minimal satu kriteria harus dinilai")
+	ErrNotReleased         = errors.New("nilai rubrik belum dirilis")
+)
+
+/* =========================================================
+   Assessment ↔ rubrik
+========================================================= */
+
+func loadAssessment(ctx context.Context, db *gorm.DB, schoolID, id uuid.UUID) (*assessmentModel.AssessmentModel, error) {
+	var a assessmentModel.AssessmentModel
+	err := db.WithContext(ctx).
+		Where("assessment_id = ? AND assessment_school_id = ?", id, schoolID).
+		Take(&a).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrAssessmentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// AttachRubric: pasang / lepas (rubricID nil) rubrik pada assessment assignment_upload.
+func AttachRubric(ctx context.Context, db *gorm.DB, schoolID, assessmentID uuid.UUID, rubricID *uuid.UUID) (*assessmentModel.AssessmentModel, error) {
+	a, err := loadAssessment(ctx, db, schoolID, assessmentID)
+	if err != nil {
+		return nil, err
+	}
+	if a.AssessmentKind != assessmentModel.AssessmentKindAssignmentUpload {
+		return nil, ErrAssessmentNotUpload
+	}
+	if rubricID != nil {
+		r, err := GetRubric(ctx, db, schoolID, *rubricID)
+		if err != nil {
+			return nil, err
+		}
+		if !r.RubricIsActive {
+			return nil, ErrRubricInactive
+		}
+	}
+	if a.AssessmentRubricID != nil && (rubricID == nil || *rubricID != *a.AssessmentRubricID) {
+		// sudah ada nilai dengan rubrik lama → jangan diganti diam-diam
+		var n int64
+		if err := db.WithContext(ctx).Model(&model.SubmissionRubricScoreModel{}).
+			Joins("JOIN submissions s ON s.submission_id = submission_rubric_score_submission_id").
+			Where("s.submission_assessment_id = ? AND submission_rubric_score_rubric_id = ?", a.AssessmentID, *a.AssessmentRubricID).
+			Count(&n).Error; err != nil {
+			return nil, err
+		}
+		if n > 0 {
+			return nil, ErrRubricLocked
+		}
+	}
+	if err := db.WithContext(ctx).Model(a).
+		Update("assessment_rubric_id", rubricID).Error; err != nil {
+		return nil, err
+	}
+	a.AssessmentRubricID = rubricID
+	return a, nil
+}
+
+/* =========================================================
+   Penilaian per kriteria
+========================================================= */
+
+type CriterionGrade struct {
+	CriterionID uuid.UUID
+	LevelID     *uuid.UUID
+	Points      *float64
+	Comment     *string
+}
+
+type GradeInput struct {
+	SubmissionID uuid.UUID
+	Criteria     []CriterionGrade
+	Feedback     *string
+	Release      bool
+}
+
+type GradeResult struct {
+	SubmissionID       uuid.UUID `json:"submission_id"`
+	Complete           bool      `json:"complete"`
+	GradedCriteria     int       `json:"graded_criteria"`
+	TotalCriteria      int       `json:"total_criteria"`
+	Points             float64   `json:"points"`
+	MaxPoints          float64   `json:"max_points"`
+	RawScore           *float64  `json:"raw_score,omitempty"`
+	LatePenaltyPercent float64   `json:"late_penalty_percent"`
+	FinalScore         *float64  `json:"final_score,omitempty"`
+	Status             string    `json:"status"`
+}
+
+// finalScore: skala ke assessment_max_score lalu potong penalti telat (snapshot).
+func finalScore(points, max, assessmentMax float64, late bool, penalty float64) (raw, final float64) {
+	if max <= 0 {
+		return 0, 0
+	}
+	raw = round2(points / max * assessmentMax)
+	final = raw
+	if late && penalty > 0 {
+		final = round2(raw * (1 - penalty/100))
+	}
+	if final < 0 {
+		final = 0
+	}
+	return raw, final
+}
+
+// GradeSubmission: upsert nilai kriteria; bila semua kriteria sudah dinilai → tulis submission_score.
+func GradeSubmission(ctx context.Context, db *gorm.DB, schoolID uuid.UUID, assessmentID *uuid.UUID, in GradeInput, teacherID *uuid.UUID, now time.Time) (*GradeResult, error) {
+	if len(in.Criteria) == 0 {
+		return nil, ErrNoCriteriaGraded
+	}
+	var out *GradeResult
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var sub submissionModel.SubmissionModel
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("submission_id = ? AND submission_school_id = ?", in.SubmissionID, schoolID).
+			Take(&sub).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSubmissionNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if assessmentID != nil && sub.SubmissionAssessmentID != *assessmentID {
+			return ErrSubmissionMismatch
+		}
+		a, err := loadAssessment(ctx, tx, schoolID, sub.SubmissionAssessmentID)
+		if err != nil {
+			return err
+		}
+		if a.AssessmentKind != assessmentModel.AssessmentKindAssignmentUpload {
+			return ErrAssessmentNotUpload
+		}
+		if a.AssessmentRubricID == nil {
+			return ErrAssessmentNoRubric
+		}
+		r, err := GetRubric(ctx, tx, schoolID, *a.AssessmentRubricID)
+		if err != nil {
+			return err
+		}
+
+		crit := make(map[uuid.UUID]*model.RubricCriterionModel, len(r.Criteria))
+		for i := range r.Criteria {
+			crit[r.Criteria[i].RubricCriterionID] = &r.Criteria[i]
+		}
+
+		rows := make([]model.SubmissionRubricScoreModel, 0, len(in.Criteria))
+		for _, g := range in.Criteria {
+			cr, ok := crit[g.CriterionID]
+			if !ok {
+				return ErrCriterionInvalid
+			}
+			row := model.SubmissionRubricScoreModel{
+				SubmissionRubricScoreSchoolID:          schoolID,
+				SubmissionRubricScoreSubmissionID:      sub.SubmissionID,
+				SubmissionRubricScoreRubricID:          r.RubricID,
+				SubmissionRubricScoreCriterionID:       &cr.RubricCriterionID,
+				SubmissionRubricScoreCriterionName:     cr.RubricCriterionName,
+				SubmissionRubricScoreCriterionOrder:    cr.RubricCriterionOrder,
+				SubmissionRubricScoreMaxPoints:         cr.RubricCriterionMaxPoints,
+				SubmissionRubricScoreComment:           trimPtr(g.Comment),
+				SubmissionRubricScoreGradedByTeacherID: teacherID,
+			}
+			switch {
+			case g.LevelID != nil:
+				var lv *model.RubricLevelModel
+				for j := range cr.Levels {
+					if cr.Levels[j].RubricLevelID == *g.LevelID {
+						lv = &cr.Levels[j]
+						break
+					}
+				}
+				if lv == nil {
+					return ErrLevelInvalid
+				}
+				row.SubmissionRubricScoreLevelID = &lv.RubricLevelID
+				row.SubmissionRubricScoreLevelLabel = &lv.RubricLevelLabel
+				row.SubmissionRubricScorePoints = lv.RubricLevelPoints
+			case g.Points != nil:
+				if *g.Points < 0 || *g.Points > cr.RubricCriterionMaxPoints {
+					return ErrPointsOutOfRange
+				}
+				row.SubmissionRubricScorePoints = round2(*g.Points)
+			default:
+				return ErrPointsOutOfRange
+			}
+			rows = append(rows, row)
+		}
+
+		if err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "submission_rubric_score_submission_id"}, {Name: "submission_rubric_score_criterion_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"submission_rubric_score_rubric_id",
+				"submission_rubric_score_level_id",
+				"submission_rubric_score_level_label",
+				"submission_rubric_score_criterion_name",
+				"submission_rubric_score_criterion_order",
+				"submission_rubric_score_points",
+				"submission_rubric_score_max_points",
+				"submission_rubric_score_comment",
+				"submission_rubric_score_graded_by_teacher_id",
+				"submission_rubric_score_updated_at",
+			}),
+		}).Create(&rows).Error; err != nil {
+			return err
+		}
+
+		// hitung ulang dari seluruh nilai kriteria rubrik ini
+		var all []model.SubmissionRubricScoreModel
+		if err := tx.Where("submission_rubric_score_submission_id = ? AND submission_rubric_score_rubric_id = ?", sub.SubmissionID, r.RubricID).
+			Order("submission_rubric_score_criterion_order ASC").
+			Find(&all).Error; err != nil {
+			return err
+		}
+		res := &GradeResult{
+			SubmissionID:       sub.SubmissionID,
+			GradedCriteria:     len(all),
+			TotalCriteria:      len(r.Criteria),
+			MaxPoints:          r.RubricMaxPoints,
+			LatePenaltyPercent: a.AssessmentLatePenaltyPercentSnapshot,
+			Status:             string(sub.SubmissionStatus),
+		}
+		for _, s := range all {
+			res.Points += s.SubmissionRubricScorePoints
+		}
+		res.Points = round2(res.Points)
+		res.Complete = res.GradedCriteria >= res.TotalCriteria
+
+		updates := map[string]any{"submission_updated_at": now}
+		if in.Feedback != nil {
+			updates["submission_feedback"] = trimPtr(in.Feedback)
+		}
+		if res.Complete {
+			raw, final := finalScore(res.Points, r.RubricMaxPoints, a.AssessmentMaxScore, sub.SubmissionIsLate, a.AssessmentLatePenaltyPercentSnapshot)
+			res.RawScore, res.FinalScore = &raw, &final
+
+			criteria := make([]map[string]any, 0, len(all))
+			for _, s := range all {
+				criteria = append(criteria, map[string]any{
+					"criterion_id": s.SubmissionRubricScoreCriterionID,
+					"name":         s.SubmissionRubricScoreCriterionName,
+					"level":        s.SubmissionRubricScoreLevelLabel,
+					"points":       s.SubmissionRubricScorePoints,
+					"max_points":   s.SubmissionRubricScoreMaxPoints,
+				})
+			}
+			scores := datatypes.JSONMap{}
+			for k, v := range sub.SubmissionScores {
+				scores[k] = v
+			}
+			scores["rubric"] = map[string]any{
+				"rubric_id":            r.RubricID,
+				"points":               res.Points,
+				"max_points":           r.RubricMaxPoints,
+				"raw_score":            raw,
+				"late_penalty_percent": a.AssessmentLatePenaltyPercentSnapshot,
+				"is_late":              sub.SubmissionIsLate,
+				"final_score":          final,
+				"criteria":             criteria,
+			}
+
+			status := submissionModel.SubmissionStatusGraded
+			if in.Release || sub.SubmissionStatus == submissionModel.SubmissionStatusReturned {
+				status = submissionModel.SubmissionStatusReturned
+			}
+			updates["submission_score"] = final
+			updates["submission_scores"] = scores
+			updates["submission_graded_by_teacher_id"] = teacherID
+			updates["submission_graded_at"] = now
+			updates["submission_status"] = status
+			res.Status = string(status)
+		}
+		if err := tx.Model(&submissionModel.SubmissionModel{}).
+			Where("submission_id = ?", sub.SubmissionID).
+			Updates(updates).Error; err != nil {
+			return err
+		}
+		if res.Complete {
+			if err := refreshGradedTotal(ctx, tx, a.AssessmentID); err != nil {
+				return err
+			}
+		}
+		out = res
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func refreshGradedTotal(ctx context.Context, db *gorm.DB, assessmentID uuid.UUID) error {
+	return db.WithContext(ctx).Exec(`
+UPDATE assessments SET assessment_submissions_graded_total = (
+  SELECT COUNT(DISTINCT submission_student_id) FROM submissions
+  WHERE submission_assessment_id = ?
+    AND submission_deleted_at IS NULL
+    AND submission_status IN ('graded','returned')
+)
+WHERE assessment_id = ?`, assessmentID, assessmentID).Error
+}
+
+/* =========================================================
+   Bulk (satu section sekaligus) & rilis
+========================================================= */
+
+type BulkItemResult struct {
+	SubmissionID uuid.UUID    `json:"submission_id"`
+	Result       *GradeResult `json:"result,omitempty"`
+	Error        string       `json:"error,omitempty"`
+}
+
+// BulkGrade: setiap submission dinilai dalam transaksinya sendiri; kegagalan satu baris tidak membatalkan yang lain.
+func BulkGrade(ctx context.Context, db *gorm.DB, schoolID, assessmentID uuid.UUID, items []GradeInput, release bool, teacherID *uuid.UUID, now time.Time) ([]BulkItemResult, error) {
+	if _, err := loadAssessment(ctx, db, schoolID, assessmentID); err != nil {
+		return nil, err
+	}
+	out := make([]BulkItemResult, 0, len(items))
+	for _, it := range items {
+		it.Release = it.Release || release
+		res, err := GradeSubmission(ctx, db, schoolID, &assessmentID, it, teacherID, now)
+		row := BulkItemResult{SubmissionID: it.SubmissionID, Result: res}
+		if err != nil {
+			row.Error = err.Error()
+		}
+		out = append(out, row)
+	}
+	return out, nil
+}
+
+// ReleaseGrades: semua submission berstatus graded → returned (siswa bisa melihat rincian).
+func ReleaseGrades(ctx context.Context, db *gorm.DB, schoolID, assessmentID uuid.UUID, now time.Time) (int64, error) {
+	if _, err := loadAssessment(ctx, db, schoolID, assessmentID); err != nil {
+		return 0, err
+	}
+	res := db.WithContext(ctx).Model(&submissionModel.SubmissionModel{}).
+		Where("submission_assessment_id = ? AND submission_school_id = ? AND submission_status = ?",
+			assessmentID, schoolID, submissionModel.SubmissionStatusGraded).
+		Updates(map[string]any{
+			"submission_status":     submissionModel.SubmissionStatusReturned,
+			"submission_updated_at": now,
+		})
+	return res.RowsAffected, res.Error
+}
+
+/* =========================================================
+   Rincian & grid
+========================================================= */
+
+type Breakdown struct {
+	SubmissionID uuid.UUID                          `json:"submission_id"`
+	AssessmentID uuid.UUID                          `json:"assessment_id"`
+	StudentID    uuid.UUID                          `json:"school_student_id"`
+	Status       string                             `json:"status"`
+	IsLate       bool                               `json:"is_late"`
+	Score        *float64                           `json:"score,omitempty"`
+	Feedback     *string                            `json:"feedback,omitempty"`
+	Rubric       *model.RubricModel                 `json:"rubric,omitempty"`
+	Scores       []model.SubmissionRubricScoreModel `json:"scores"`
+	Points       float64                            `json:"points"`
+	Complete     bool                               `json:"complete"`
+}
+
+// SubmissionBreakdown: studentID != nil → hanya submission milik siswa tsb & sudah dirilis.
+func SubmissionBreakdown(ctx context.Context, db *gorm.DB, schoolID, submissionID uuid.UUID, studentID *uuid.UUID) (*Breakdown, error) {
+	var sub submissionModel.SubmissionModel
+	err := db.WithContext(ctx).
+		Where("submission_id = ? AND submission_school_id = ?", submissionID, schoolID).
+		Take(&sub).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrSubmissionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if studentID != nil {
+		if sub.SubmissionStudentID != *studentID {
+			return nil, ErrSubmissionNotFound
+		}
+		if sub.SubmissionStatus != submissionModel.SubmissionStatusReturned {
+			return nil, ErrNotReleased
+		}
+	}
+	a, err := loadAssessment(ctx, db, schoolID, sub.SubmissionAssessmentID)
+	if err != nil {
+		return nil, err
+	}
+	b := &Breakdown{
+		SubmissionID: sub.SubmissionID,
+		AssessmentID: sub.SubmissionAssessmentID,
+		StudentID:    sub.SubmissionStudentID,
+		Status:       string(sub.SubmissionStatus),
+		IsLate:       sub.SubmissionIsLate,
+		Score:        sub.SubmissionScore,
+		Feedback:     sub.SubmissionFeedback,
+		Scores:       []model.SubmissionRubricScoreModel{},
+	}
+	if a.AssessmentRubricID == nil {
+		return b, nil
+	}
+	if r, err := GetRubric(ctx, db, schoolID, *a.AssessmentRubricID); err == nil {
+		b.Rubric = r
+	} else if !errors.Is(err, ErrRubricNotFound) {
+		return nil, err
+	}
+	if err := db.WithContext(ctx).
+		Where("submission_rubric_score_submission_id = ? AND submission_rubric_score_rubric_id = ?", sub.SubmissionID, *a.AssessmentRubricID).
+		Order("submission_rubric_score_criterion_order ASC").
+		Find(&b.Scores).Error; err != nil {
+		return nil, err
+	}
+	for _, s := range b.Scores {
+		b.Points += s.SubmissionRubricScorePoints
+	}
+	b.Points = round2(b.Points)
+	if b.Rubric != nil {
+		b.Complete = len(b.Scores) >= len(b.Rubric.Criteria)
+	}
+	return b, nil
+}
+
+type GridRow struct {
+	SubmissionID uuid.UUID              `json:"submission_id"`
+	StudentID    uuid.UUID              `json:"school_student_id"`
+	Attempt      int                    `json:"attempt"`
+	Status       string                 `json:"status"`
+	IsLate       bool                   `json:"is_late"`
+	Score        *float64               `json:"score,omitempty"`
+	Criteria     map[uuid.UUID]GridCell `json:"criteria"`
+	Points       float64                `json:"points"`
+}
+
+type GridCell struct {
+	LevelID *uuid.UUID `json:"level_id,omitempty"`
+	Level   *string    `json:"level,omitempty"`
+	Points  float64    `json:"points"`
+}
+
+type Grid struct {
+	Rubric *model.RubricModel `json:"rubric"`
+	Rows   []GridRow          `json:"rows"`
+}
+
+// AssessmentGrid: submission terakhir tiap siswa + nilai per kriteria (untuk penilaian satu section).
+func AssessmentGrid(ctx context.Context, db *gorm.DB, schoolID, assessmentID uuid.UUID) (*Grid, error) {
+	a, err := loadAssessment(ctx, db, schoolID, assessmentID)
+	if err != nil {
+		return nil, err
+	}
+	if a.AssessmentRubricID == nil {
+		return nil, ErrAssessmentNoRubric
+	}
+	r, err := GetRubric(ctx, db, schoolID, *a.AssessmentRubricID)
+	if err != nil {
+		return nil, err
+	}
+
+	var subs []submissionModel.SubmissionModel
+	if err := db.WithContext(ctx).Raw(`
+SELECT DISTINCT ON (submission_student_id) *
+FROM submissions
+WHERE submission_assessment_id = ? AND submission_school_id = ? AND submission_deleted_at IS NULL
+ORDER BY submission_student_id, submission_attempt_count DESC, submission_created_at DESC`,
+		assessmentID, schoolID).Scan(&subs).Error; err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, 0, len(subs))
+	for _, s := range subs {
+		ids = append(ids, s.SubmissionID)
+	}
+	var scores []model.SubmissionRubricScoreModel
+	if len(ids) > 0 {
+		if err := db.WithContext(ctx).
+			Where("submission_rubric_score_submission_id IN ? AND submission_rubric_score_rubric_id = ?", ids, r.RubricID).
+			Find(&scores).Error; err != nil {
+			return nil, err
+		}
+	}
+	bySub := map[uuid.UUID][]model.SubmissionRubricScoreModel{}
+	for _, s := range scores {
+		bySub[s.SubmissionRubricScoreSubmissionID] = append(bySub[s.SubmissionRubricScoreSubmissionID], s)
+	}
+
+	g := &Grid{Rubric: r, Rows: make([]GridRow, 0, len(subs))}
+	for _, s := range subs {
+		row := GridRow{
+			SubmissionID: s.SubmissionID,
+			StudentID:    s.SubmissionStudentID,
+			Attempt:      s.SubmissionAttemptCount,
+			Status:       string(s.SubmissionStatus),
+			IsLate:       s.SubmissionIsLate,
+			Score:        s.SubmissionScore,
+			Criteria:     map[uuid.UUID]GridCell{},
+		}
+		for _, sc := range bySub[s.SubmissionID] {
+			if sc.SubmissionRubricScoreCriterionID == nil {
+				continue
+			}
+			row.Criteria[*sc.SubmissionRubricScoreCriterionID] = GridCell{
+				LevelID: sc.SubmissionRubricScoreLevelID,
+				Level:   sc.SubmissionRubricScoreLevelLabel,
+				Points:  sc.SubmissionRubricScorePoints,
+			}
+			row.Points += sc.SubmissionRubricScorePoints
+		}
+		row.Points = round2(row.Points)
+		g.Rows = append(g.Rows, row)
+	}
+	return g, nil
+}
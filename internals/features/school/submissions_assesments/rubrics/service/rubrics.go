@@ -0,0 +1,330 @@
+// file: internals/features/school/submissions_assesments/rubrics/service/rubrics.go
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	model "madinahsalam_backend/internals/features/school/submissions_assesments/rubrics/model"
+)
+
+var (
+	ErrRubricNotFound     = errors.New("rubrik tidak ditemukan")
+	ErrRubricNameEmpty    = errors.New("nama rubrik wajib diisi")
+	ErrRubricNoCriteria   = errors.New("rubrik minimal punya 1 kriteria")
+	ErrCriterionNameEmpty = errors.New("nama kriteria wajib diisi")
+	ErrCriterionNoLevels  = errors.New("setiap kriteria minimal punya 1 level")
+	ErrLevelLabelEmpty    = errors.New("label level wajib diisi")
+	ErrLevelPoints        = errors.New("poin level harus >= 0 dan kriteria harus punya poin maksimal > 0")
+	ErrRubricInUse        = errors.New("rubrik sudah dipakai menilai; duplikat rubrik untuk mengubah kriteria")
+	ErrRubricAttached     = errors.New("rubrik masih dipakai tugas; lepas dari tugas terlebih dahulu")
+	ErrSubjectInvalid     = errors.New("mapel tidak ditemukan di sekolah ini")
+)
+
+func round2(v float64) float64 { return math.Round(v*100) / 100 }
+
+func trimPtr(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	v := strings.TrimSpace(*s)
+	if v == "" {
+		return nil
+	}
+	return &v
+}
+
+type LevelInput struct {
+	Label       string
+	Description *string
+	Points      float64
+}
+
+type CriterionInput struct {
+	Name        string
+	Description *string
+	Levels      []LevelInput
+}
+
+type RubricInput struct {
+	Name           *string
+	Description    *string
+	ClassSubjectID *uuid.UUID
+	IsActive       *bool
+	Criteria       *[]CriterionInput
+}
+
+// buildCriteria: validasi + susun model kriteria/level (urutan mengikuti input).
+func buildCriteria(schoolID, rubricID uuid.UUID, in []CriterionInput) ([]model.RubricCriterionModel, float64, error) {
+	if len(in) == 0 {
+		return nil, 0, ErrRubricNoCriteria
+	}
+	out := make([]model.RubricCriterionModel, 0, len(in))
+	total := 0.0
+	for i, ci := range in {
+		name := strings.TrimSpace(ci.Name)
+		if name == "" {
+			return nil, 0, ErrCriterionNameEmpty
+		}
+		if len(ci.Levels) == 0 {
+			return nil, 0, ErrCriterionNoLevels
+		}
+		cr := model.RubricCriterionModel{
+			RubricCriterionID:          uuid.New(),
+			RubricCriterionSchoolID:    schoolID,
+			RubricCriterionRubricID:    rubricID,
+			RubricCriterionName:        name,
+			RubricCriterionDescription: trimPtr(ci.Description),
+			RubricCriterionOrder:       i + 1,
+		}
+		for j, li := range ci.Levels {
+			label := strings.TrimSpace(li.Label)
+			if label == "" {
+				return nil, 0, ErrLevelLabelEmpty
+			}
+			if li.Points < 0 {
+				return nil, 0, ErrLevelPoints
+			}
+			pts := round2(li.Points)
+			if pts > cr.RubricCriterionMaxPoints {
+				cr.RubricCriterionMaxPoints = pts
+			}
+			cr.Levels = append(cr.Levels, model.RubricLevelModel{
+				RubricLevelSchoolID:    schoolID,
+				RubricLevelCriterionID: cr.RubricCriterionID,
+				RubricLevelLabel:       label,
+				RubricLevelDescription: trimPtr(li.Description),
+				RubricLevelPoints:      pts,
+				RubricLevelOrder:       j + 1,
+			})
+		}
+		if cr.RubricCriterionMaxPoints <= 0 {
+			return nil, 0, ErrLevelPoints
+		}
+		total += cr.RubricCriterionMaxPoints
+		out = append(out, cr)
+	}
+	return out, round2(total), nil
+}
+
+func subjectInSchool(ctx context.Context, db *gorm.DB, schoolID, subjectID uuid.UUID) (bool, error) {
+	var n int64
+	err := db.WithContext(ctx).Table("class_subjects").
+		Where("class_subject_id = ? AND class_subject_school_id = ? AND class_subject_deleted_at IS NULL", subjectID, schoolID).
+		Count(&n).Error
+	return n > 0, err
+}
+
+func preloadTree(q *gorm.DB) *gorm.DB {
+	return q.
+		Preload("Criteria", func(db *gorm.DB) *gorm.DB { return db.Order("rubric_criterion_order ASC") }).
+		Preload("Criteria.Levels", func(db *gorm.DB) *gorm.DB { return db.Order("rubric_level_order ASC") })
+}
+
+/* =========================================================
+   CRUD
+========================================================= */
+
+type ListFilter struct {
+	ClassSubjectID *uuid.UUID // juga menyertakan rubrik umum sekolah
+	OnlyActive     bool
+	Q              string
+	Offset, Limit  int
+}
+
+func ListRubrics(ctx context.Context, db *gorm.DB, schoolID uuid.UUID, f ListFilter) ([]model.RubricModel, int64, error) {
+	q := db.WithContext(ctx).Model(&model.RubricModel{}).Where("rubric_school_id = ?", schoolID)
+	if f.ClassSubjectID != nil {
+		q = q.Where("(rubric_class_subject_id = ? OR rubric_class_subject_id IS NULL)", *f.ClassSubjectID)
+	}
+	if f.OnlyActive {
+		q = q.Where("rubric_is_active = TRUE")
+	}
+	if s := strings.TrimSpace(f.Q); s != "" {
+		q = q.Where("rubric_name ILIKE ?", "%"+s+"%")
+	}
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var rows []model.RubricModel
+	err := preloadTree(q).Order("rubric_name ASC").Offset(f.Offset).Limit(f.Limit).Find(&rows).Error
+	return rows, total, err
+}
+
+func GetRubric(ctx context.Context, db *gorm.DB, schoolID, id uuid.UUID) (*model.RubricModel, error) {
+	var m model.RubricModel
+	err := preloadTree(db.WithContext(ctx)).
+		Where("rubric_id = ? AND rubric_school_id = ?", id, schoolID).
+		Take(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRubricNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func CreateRubric(ctx context.Context, db *gorm.DB, schoolID uuid.UUID, in RubricInput, teacherID *uuid.UUID) (*model.RubricModel, error) {
+	if in.Name == nil || strings.TrimSpace(*in.Name) == "" {
+		return nil, ErrRubricNameEmpty
+	}
+	if in.Criteria == nil {
+		return nil, ErrRubricNoCriteria
+	}
+	if in.ClassSubjectID != nil {
+		if ok, err := subjectInSchool(ctx, db, schoolID, *in.ClassSubjectID); err != nil {
+			return nil, err
+		} else if !ok {
+			return nil, ErrSubjectInvalid
+		}
+	}
+	m := &model.RubricModel{
+		RubricID:                 uuid.New(),
+		RubricSchoolID:           schoolID,
+		RubricClassSubjectID:     in.ClassSubjectID,
+		RubricName:               strings.TrimSpace(*in.Name),
+		RubricDescription:        trimPtr(in.Description),
+		RubricIsActive:           true,
+		RubricCreatedByTeacherID: teacherID,
+	}
+	if in.IsActive != nil {
+		m.RubricIsActive = *in.IsActive
+	}
+	criteria, total, err := buildCriteria(schoolID, m.RubricID, *in.Criteria)
+	if err != nil {
+		return nil, err
+	}
+	m.RubricMaxPoints = total
+	m.Criteria = criteria
+
+	// Create dengan asosiasi → rubrics, rubric_criteria, rubric_levels sekaligus
+	if err := db.WithContext(ctx).Create(m).Error; err != nil {
+		return nil, err
+	}
+	return GetRubric(ctx, db, schoolID, m.RubricID)
+}
+
+func rubricUsed(ctx context.Context, db *gorm.DB, rubricID uuid.UUID) (bool, error) {
+	var n int64
+	err := db.WithContext(ctx).Model(&model.SubmissionRubricScoreModel{}).
+		Where("submission_rubric_score_rubric_id = ?", rubricID).
+		Limit(1).Count(&n).Error
+	return n > 0, err
+}
+
+// UpdateRubric: kriteria (bila dikirim) diganti seluruhnya — hanya selama belum dipakai menilai.
+func UpdateRubric(ctx context.Context, db *gorm.DB, schoolID, id uuid.UUID, in RubricInput) (*model.RubricModel, error) {
+	m, err := GetRubric(ctx, db, schoolID, id)
+	if err != nil {
+		return nil, err
+	}
+	if in.Name != nil {
+		if strings.TrimSpace(*in.Name) == "" {
+			return nil, ErrRubricNameEmpty
+		}
+		m.RubricName = strings.TrimSpace(*in.Name)
+	}
+	if in.Description != nil {
+		m.RubricDescription = trimPtr(in.Description)
+	}
+	if in.ClassSubjectID != nil {
+		if *in.ClassSubjectID == uuid.Nil {
+			m.RubricClassSubjectID = nil // uuid nol = jadikan rubrik umum
+		} else {
+			if ok, err := subjectInSchool(ctx, db, schoolID, *in.ClassSubjectID); err != nil {
+				return nil, err
+			} else if !ok {
+				return nil, ErrSubjectInvalid
+			}
+			m.RubricClassSubjectID = in.ClassSubjectID
+		}
+	}
+	if in.IsActive != nil {
+		m.RubricIsActive = *in.IsActive
+	}
+
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if in.Criteria != nil {
+			used, err := rubricUsed(ctx, tx, m.RubricID)
+			if err != nil {
+				return err
+			}
+			if used {
+				return ErrRubricInUse
+			}
+			criteria, total, err := buildCriteria(schoolID, m.RubricID, *in.Criteria)
+			if err != nil {
+				return err
+			}
+			if err := tx.Where("rubric_criterion_rubric_id = ?", m.RubricID).
+				Delete(&model.RubricCriterionModel{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Create(&criteria).Error; err != nil {
+				return err
+			}
+			m.RubricMaxPoints = total
+		}
+		m.Criteria = nil
+		return tx.Omit("Criteria").Save(m).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return GetRubric(ctx, db, schoolID, id)
+}
+
+// DuplicateRubric: salinan lengkap (kriteria & level) dengan nama baru.
+func DuplicateRubric(ctx context.Context, db *gorm.DB, schoolID, id uuid.UUID, name *string, teacherID *uuid.UUID) (*model.RubricModel, error) {
+	src, err := GetRubric(ctx, db, schoolID, id)
+	if err != nil {
+		return nil, err
+	}
+	criteria := make([]CriterionInput, 0, len(src.Criteria))
+	for _, c := range src.Criteria {
+		ci := CriterionInput{Name: c.RubricCriterionName, Description: c.RubricCriterionDescription}
+		for _, l := range c.Levels {
+			ci.Levels = append(ci.Levels, LevelInput{Label: l.RubricLevelLabel, Description: l.RubricLevelDescription, Points: l.RubricLevelPoints})
+		}
+		criteria = append(criteria, ci)
+	}
+	n := src.RubricName + " (salinan)"
+	if name != nil && strings.TrimSpace(*name) != "" {
+		n = *name
+	}
+	return CreateRubric(ctx, db, schoolID, RubricInput{
+		Name:           &n,
+		Description:    src.RubricDescription,
+		ClassSubjectID: src.RubricClassSubjectID,
+		Criteria:       &criteria,
+	}, teacherID)
+}
+
+func DeleteRubric(ctx context.Context, db *gorm.DB, schoolID, id uuid.UUID) error {
+	var n int64
+	if err := db.WithContext(ctx).Table("assessments").
+		Where("assessment_rubric_id = ? AND assessment_deleted_at IS NULL", id).
+		Count(&n).Error; err != nil {
+		return err
+	}
+	if n > 0 {
+		return ErrRubricAttached
+	}
+	res := db.WithContext(ctx).
+		Where("rubric_id = ? AND rubric_school_id = ?", id, schoolID).
+		Delete(&model.RubricModel{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrRubricNotFound
+	}
+	return nil
+}
@@ -215,6 +215,18 @@ func (ctrl *SubmissionController) List(c *fiber.Ctx) error {
 		if includeURLs {
 			resp.SubmissionURLs = urlBySubmission[rows[i].SubmissionID]
 		}
+		// Rincian rubrik baru terlihat oleh siswa setelah nilai dirilis (returned)
+		if isStudent && !isTeacher && !isDKM && rows[i].SubmissionStatus != model.SubmissionStatusReturned {
+			if _, ok := resp.SubmissionScores["rubric"]; ok {
+				scores := make(map[string]any, len(resp.SubmissionScores))
+				for k, v := range resp.SubmissionScores {
+					if k != "rubric" {
+						scores[k] = v
+					}
+				}
+				resp.SubmissionScores = scores
+			}
+		}
 		items = append(items, resp)
 	}
 
@@ -0,0 +1,132 @@
+// file: internals/features/school/submissions_assesments/submissions/service/submission_remedial_score.go
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	qmodel "madinahsalam_backend/internals/features/school/submissions_assesments/quizzes/model"
+)
+
+// quizScore: nilai quiz yang masuk ke submission.
+//   - tanpa peserta remedial : last_percent attempt (perilaku lama)
+//   - ada rantai remedial    : dihitung ulang dari quiz utama, ronde demi ronde
+//     (nilai asal ronde n = nilai akhir ronde n-1), sesuai quiz_remedial_policy tiap ronde.
+//     Ringkasannya dikembalikan untuk submission_scores["remedial"].
+func (s *SubmissionService) quizScore(ctx context.Context, attempt *qmodel.StudentQuizAttemptModel) (*float64, map[string]any, error) {
+	db := s.DB.WithContext(ctx)
+	studentID := attempt.StudentQuizAttemptStudentID
+
+	rootID := attempt.StudentQuizAttemptQuizID
+	var own qmodel.QuizRemedialParticipantModel
+	err := db.Where("quiz_remedial_participant_quiz_id = ? AND quiz_remedial_participant_student_id = ?",
+		attempt.StudentQuizAttemptQuizID, studentID).Take(&own).Error
+	switch {
+	case err == nil:
+		rootID = own.QuizRemedialParticipantRootQuizID
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, nil, err
+	}
+
+	var parts []qmodel.QuizRemedialParticipantModel
+	if err := db.Select("quiz_remedial_participants.*").
+		Joins("JOIN quizzes ON quiz_id = quiz_remedial_participant_quiz_id AND quiz_deleted_at IS NULL").
+		Where("quiz_remedial_participant_root_quiz_id = ? AND quiz_remedial_participant_student_id = ?", rootID, studentID).
+		Order("quiz_remedial_participant_round ASC, quiz_remedial_participant_created_at ASC").
+		Find(&parts).Error; err != nil {
+		return nil, nil, err
+	}
+	if len(parts) == 0 {
+		return attempt.StudentQuizAttemptLastPercent, nil, nil
+	}
+
+	quizIDs := []uuid.UUID{rootID}
+	for _, p := range parts {
+		quizIDs = append(quizIDs, p.QuizRemedialParticipantQuizID)
+	}
+
+	// nilai terakhir tiap quiz di rantai (attempt yang sedang disinkron dipakai langsung)
+	var attempts []struct {
+		QuizID  uuid.UUID `gorm:"column:student_quiz_attempt_quiz_id"`
+		Percent *float64  `gorm:"column:student_quiz_attempt_last_percent"`
+	}
+	if err := db.Table("student_quiz_attempts").
+		Select("student_quiz_attempt_quiz_id, student_quiz_attempt_last_percent").
+		Where("student_quiz_attempt_student_id = ? AND student_quiz_attempt_quiz_id IN ? AND student_quiz_attempt_count > 0", studentID, quizIDs).
+		Scan(&attempts).Error; err != nil {
+		return nil, nil, err
+	}
+	percent := make(map[uuid.UUID]*float64, len(attempts)+1)
+	for _, a := range attempts {
+		percent[a.QuizID] = a.Percent
+	}
+	percent[attempt.StudentQuizAttemptQuizID] = attempt.StudentQuizAttemptLastPercent
+
+	var quizzes []qmodel.QuizModel
+	if err := db.Select("quiz_id, quiz_remedial_policy, quiz_remedial_passing_percent").
+		Where("quiz_id IN ?", quizIDs).Find(&quizzes).Error; err != nil {
+		return nil, nil, err
+	}
+	quizByID := make(map[uuid.UUID]*qmodel.QuizModel, len(quizzes))
+	for i := range quizzes {
+		quizByID[quizzes[i].QuizID] = &quizzes[i]
+	}
+
+	round3 := func(v float64) float64 { return math.Round(v*1000) / 1000 }
+	current := 0.0
+	if p := percent[rootID]; p != nil {
+		current = *p
+	}
+	breakdown := map[string]any{"root_quiz_id": rootID, "original_percent": round3(current)}
+	rounds := make([]map[string]any, 0, len(parts))
+	now := time.Now().UTC()
+
+	for i := range parts {
+		p := &parts[i]
+		policy, passing := qmodel.QuizRemedialPolicyCapKKM, 0.0
+		if q := quizByID[p.QuizRemedialParticipantQuizID]; q != nil {
+			if q.QuizRemedialPolicy != nil {
+				policy = *q.QuizRemedialPolicy
+			}
+			if q.QuizRemedialPassingPercent != nil {
+				passing = *q.QuizRemedialPassingPercent
+			}
+		}
+
+		upd := map[string]any{"quiz_remedial_participant_original_percent": round3(current)}
+		item := map[string]any{
+			"round":            p.QuizRemedialParticipantRound,
+			"quiz_id":          p.QuizRemedialParticipantQuizID,
+			"policy":           policy,
+			"passing_percent":  passing,
+			"original_percent": round3(current),
+		}
+		if rp := percent[p.QuizRemedialParticipantQuizID]; rp != nil {
+			final := round3(qmodel.CombineRemedialScore(policy, current, *rp, passing))
+			upd["quiz_remedial_participant_remedial_percent"] = round3(*rp)
+			upd["quiz_remedial_participant_final_percent"] = final
+			if p.QuizRemedialParticipantCompletedAt == nil {
+				upd["quiz_remedial_participant_completed_at"] = now
+			}
+			item["remedial_percent"] = round3(*rp)
+			item["final_percent"] = final
+			current = final
+		}
+		if err := db.Model(&qmodel.QuizRemedialParticipantModel{}).
+			Where("quiz_remedial_participant_id = ?", p.QuizRemedialParticipantID).
+			Updates(upd).Error; err != nil {
+			return nil, nil, err
+		}
+		rounds = append(rounds, item)
+	}
+
+	final := round3(current)
+	breakdown["rounds"] = rounds
+	breakdown["final_percent"] = final
+	return &final, breakdown, nil
+}
@@ -25,7 +25,7 @@ func NewSubmissionService(db *gorm.DB) *SubmissionService {
 // UpsertSubmissionFromQuizAttempt:
 // - Ambil assessment_id dari quiz
 // - Upsert ke submission attempt TERAKHIR untuk student×assessment×school
-// - Pakai last_percent sebagai SubmissionScore
+// - Pakai last_percent sebagai SubmissionScore (atau nilai akhir rantai remedial, lihat quizScore)
 // Catatan:
 // - Untuk quiz, biasanya kita UPDATE attempt terakhir (bukan create attempt baru tiap progress)
 // - attempt baru dibuat oleh flow "start attempt" / "submit attempt" kalau kamu mau strict
@@ -76,6 +76,13 @@ func (s *SubmissionService) UpsertSubmissionFromQuizAttempt(
 	log.Printf("[SubmissionService] Mapped quiz_id=%s -> assessment_id=%s",
 		attempt.StudentQuizAttemptQuizID, assessmentID)
 
+	// 1b) Nilai yang dipakai: last_percent, atau nilai akhir rantai remedial
+	score, remedial, err := s.quizScore(ctx, attempt)
+	if err != nil {
+		log.Printf("[SubmissionService] ERROR compute remedial score: %v", err)
+		return err
+	}
+
 	// 2) Ambil submission attempt TERAKHIR (by attempt_count desc)
 	var sub smodel.SubmissionModel
 	err = s.DB.WithContext(ctx).
@@ -112,8 +119,11 @@ func (s *SubmissionService) UpsertSubmissionFromQuizAttempt(
 				SubmissionQuizFinished: 1,
 			}
 
-			if attempt.StudentQuizAttemptLastPercent != nil {
-				sub.SubmissionScore = attempt.StudentQuizAttemptLastPercent
+			if score != nil {
+				sub.SubmissionScore = score
+			}
+			if remedial != nil {
+				sub.SubmissionScores = map[string]any{"remedial": remedial}
 			}
 
 			if err := s.DB.WithContext(ctx).Create(&sub).Error; err != nil {
@@ -138,8 +148,16 @@ func (s *SubmissionService) UpsertSubmissionFromQuizAttempt(
 	sub.SubmissionStatus = smodel.SubmissionStatusSubmitted
 	sub.SubmissionSubmittedAt = &now
 
-	if attempt.StudentQuizAttemptLastPercent != nil {
-		sub.SubmissionScore = attempt.StudentQuizAttemptLastPercent
+	if score != nil {
+		sub.SubmissionScore = score
+	}
+	if remedial != nil {
+		scores := make(map[string]any, len(sub.SubmissionScores)+1)
+		for k, v := range sub.SubmissionScores {
+			scores[k] = v
+		}
+		scores["remedial"] = remedial
+		sub.SubmissionScores = scores
 	}
 
 	// Sementara: anggap 1 quiz -> finished=1
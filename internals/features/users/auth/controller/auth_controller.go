@@ -61,9 +61,17 @@ func (ac *AuthController) Login(c *fiber.Ctx) error {
 	return service.Login(ac.DB, c)
 }
 
-// func (ac *AuthController) LoginGoogle(c *fiber.Ctx) error {
-// 	return service.LoginGoogle(ac.DB, c)
-// }
+func (ac *AuthController) LoginGoogle(c *fiber.Ctx) error {
+	return service.LoginGoogle(ac.DB, c)
+}
+
+func (ac *AuthController) LinkGoogle(c *fiber.Ctx) error {
+	return service.LinkGoogle(ac.DB, c)
+}
+
+func (ac *AuthController) UnlinkGoogle(c *fiber.Ctx) error {
+	return service.UnlinkGoogle(ac.DB, c)
+}
 
 func (ac *AuthController) Logout(c *fiber.Ctx) error {
 	return service.Logout(ac.DB, c)
@@ -85,3 +93,69 @@ func (ac *AuthController) ResetPassword(c *fiber.Ctx) error {
 func (ac *AuthController) CSRF(c *fiber.Ctx) error {
 	return service.CSRF(ac.DB, c)
 }
+
+// ===== Sesi & perangkat =====
+func (ac *AuthController) ListMySessions(c *fiber.Ctx) error {
+	return service.ListMySessions(ac.DB, c)
+}
+
+func (ac *AuthController) RevokeMySession(c *fiber.Ctx) error {
+	return service.RevokeMySession(ac.DB, c)
+}
+
+func (ac *AuthController) RevokeMyOtherSessions(c *fiber.Ctx) error {
+	return service.RevokeMyOtherSessions(ac.DB, c)
+}
+
+func (ac *AuthController) AdminListUserSessions(c *fiber.Ctx) error {
+	return service.AdminListUserSessions(ac.DB, c)
+}
+
+func (ac *AuthController) AdminRevokeUserSessions(c *fiber.Ctx) error {
+	return service.AdminRevokeUserSessions(ac.DB, c)
+}
+
+// ===== Verifikasi dua langkah (TOTP) =====
+func (ac *AuthController) VerifyLoginMFA(c *fiber.Ctx) error {
+	return service.VerifyLoginMFA(ac.DB, c)
+}
+
+func (ac *AuthController) BeginLoginMFASetup(c *fiber.Ctx) error {
+	return service.BeginLoginMFASetup(ac.DB, c)
+}
+
+func (ac *AuthController) ConfirmLoginMFASetup(c *fiber.Ctx) error {
+	return service.ConfirmLoginMFASetup(ac.DB, c)
+}
+
+func (ac *AuthController) GetMyMFAStatus(c *fiber.Ctx) error {
+	return service.GetMyMFAStatus(ac.DB, c)
+}
+
+func (ac *AuthController) BeginMyMFASetup(c *fiber.Ctx) error {
+	return service.BeginMyMFASetup(ac.DB, c)
+}
+
+func (ac *AuthController) ConfirmMyMFASetup(c *fiber.Ctx) error {
+	return service.ConfirmMyMFASetup(ac.DB, c)
+}
+
+func (ac *AuthController) RegenerateMyRecoveryCodes(c *fiber.Ctx) error {
+	return service.RegenerateMyRecoveryCodes(ac.DB, c)
+}
+
+func (ac *AuthController) DisableMyMFA(c *fiber.Ctx) error {
+	return service.DisableMyMFA(ac.DB, c)
+}
+
+func (ac *AuthController) GetSchoolMFAPolicy(c *fiber.Ctx) error {
+	return service.GetSchoolMFAPolicy(ac.DB, c)
+}
+
+func (ac *AuthController) UpdateSchoolMFAPolicy(c *fiber.Ctx) error {
+	return service.UpdateSchoolMFAPolicy(ac.DB, c)
+}
+
+func (ac *AuthController) AdminResetUserMFA(c *fiber.Ctx) error {
+	return service.AdminResetUserMFA(ac.DB, c)
+}
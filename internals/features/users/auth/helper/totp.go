@@ -0,0 +1,178 @@
+// file: internals/features/users/auth/helper/totp.go
+package helpers
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+/* =========================================================
+   TOTP (RFC 6238) — SHA1, 6 digit, periode 30 detik
+   (default Google Authenticator / Authy / Microsoft Authenticator)
+========================================================= */
+
+const (
+	TOTPDigits = 6
+	TOTPPeriod = 30
+	totpSkew   = 1 // toleransi ±1 langkah (jam HP sedikit melenceng)
+)
+
+var b32 = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateTOTPSecret: 20 byte acak → base32 tanpa padding.
+func GenerateTOTPSecret() (string, error) {
+	buf := make([]byte, 20)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return b32.EncodeToString(buf), nil
+}
+
+// TOTPProvisioningURI: isi QR code (otpauth://totp/Issuer:akun?...).
+func TOTPProvisioningURI(issuer, account, secret string) string {
+	label := url.PathEscape(issuer + ":" + account)
+	q := url.Values{}
+	q.Set("secret", secret)
+	q.Set("issuer", issuer)
+	q.Set("algorithm", "SHA1")
+	q.Set("digits", fmt.Sprint(TOTPDigits))
+	q.Set("period", fmt.Sprint(TOTPPeriod))
+	return "otpauth://totp/" + label + "?" + q.Encode()
+}
+
+func totpAt(key []byte, step int64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(step))
+	m := hmac.New(sha1.New, key)
+	m.Write(msg[:])
+	sum := m.Sum(nil)
+	off := sum[len(sum)-1] & 0x0f
+	bin := binary.BigEndian.Uint32(sum[off:off+4]) & 0x7fffffff
+	return fmt.Sprintf("%06d", bin%1_000_000)
+}
+
+// VerifyTOTP: kembalikan langkah (step) yang cocok; step <= lastStep ditolak (replay).
+func VerifyTOTP(secret, code string, now time.Time, lastStep int64) (int64, bool) {
+	code = strings.ReplaceAll(strings.TrimSpace(code), " ", "")
+	if len(code) != TOTPDigits {
+		return 0, false
+	}
+	key, err := b32.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return 0, false
+	}
+	cur := now.Unix() / TOTPPeriod
+	for d := -totpSkew; d <= totpSkew; d++ {
+		step := cur + int64(d)
+		if step <= lastStep {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(totpAt(key, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+/* =========================================================
+   Recovery codes: "xxxxx-xxxxx" (base32 huruf kecil), simpan SHA-256
+========================================================= */
+
+func GenerateRecoveryCodes(n int) ([]string, error) {
+	out := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		buf := make([]byte, 7)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+		s := strings.ToLower(b32.EncodeToString(buf))[:10]
+		out = append(out, s[:5]+"-"+s[5:])
+	}
+	return out, nil
+}
+
+// HashRecoveryCode: normalisasi (tanpa spasi/strip, huruf kecil) lalu SHA-256 hex.
+func HashRecoveryCode(code string) string {
+	c := strings.ToLower(strings.TrimSpace(code))
+	c = strings.NewReplacer("-", "", " ", "").Replace(c)
+	sum := sha256.Sum256([]byte(c))
+	return hex.EncodeToString(sum[:])
+}
+
+/* =========================================================
+   Enkripsi secret at-rest (AES-256-GCM)
+   Kunci: MFA_ENCRYPTION_KEY (fallback JWT_SECRET) → SHA-256
+========================================================= */
+
+var ErrMFAKeyMissing = errors.New("MFA_ENCRYPTION_KEY/JWT_SECRET belum diset")
+
+func mfaKey() ([]byte, error) {
+	k := strings.TrimSpace(os.Getenv("MFA_ENCRYPTION_KEY"))
+	if k == "" {
+		k = strings.TrimSpace(os.Getenv("JWT_SECRET"))
+	}
+	if k == "" {
+		return nil, ErrMFAKeyMissing
+	}
+	sum := sha256.Sum256([]byte("totp:" + k))
+	return sum[:], nil
+}
+
+func mfaGCM() (cipher.AEAD, error) {
+	key, err := mfaKey()
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func EncryptTOTPSecret(secret string) (string, error) {
+	gcm, err := mfaGCM()
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(secret), nil)
+	return base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+func DecryptTOTPSecret(enc string) (string, error) {
+	gcm, err := mfaGCM()
+	if err != nil {
+		return "", err
+	}
+	raw, err := base64.RawStdEncoding.DecodeString(enc)
+	if err != nil {
+		return "", err
+	}
+	if len(raw) < gcm.NonceSize() {
+		return "", errors.New("secret TOTP rusak")
+	}
+	plain, err := gcm.Open(nil, raw[:gcm.NonceSize()], raw[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
@@ -0,0 +1,101 @@
+// file: internals/features/users/auth/helper/user_agent.go
+package helpers
+
+import (
+	"regexp"
+	"strings"
+)
+
+// DeviceInfo: hasil parse User-Agent sederhana untuk daftar sesi (tanpa dependensi).
+type DeviceInfo struct {
+	Browser        string `json:"browser"`
+	BrowserVersion string `json:"browser_version,omitempty"`
+	OS             string `json:"os"`
+	DeviceType     string `json:"device_type"` // desktop | mobile | tablet | app | unknown
+	Label          string `json:"label"`       // "Chrome di Android"
+}
+
+var (
+	reEdge    = regexp.MustCompile(`Edg(?:e|A|iOS)?/([\d.]+)`)
+	reOpera   = regexp.MustCompile(`(?:OPR|Opera)/([\d.]+)`)
+	reSamsung = regexp.MustCompile(`SamsungBrowser/([\d.]+)`)
+	reFirefox = regexp.MustCompile(`(?:Firefox|FxiOS)/([\d.]+)`)
+	reChrome  = regexp.MustCompile(`(?:Chrome|CriOS)/([\d.]+)`)
+	reSafari  = regexp.MustCompile(`Version/([\d.]+).*Safari/`)
+	reAndroid = regexp.MustCompile(`Android ([\d.]+)`)
+	reIOS     = regexp.MustCompile(`OS (\d+[_\d]*) like Mac OS X`)
+	reApp     = regexp.MustCompile(`^(okhttp|Dart|Dalvik|CFNetwork|Expo)`)
+)
+
+// ParseUserAgent: cukup untuk label "Chrome di Windows"; bukan deteksi lengkap.
+func ParseUserAgent(ua string) DeviceInfo {
+	ua = strings.TrimSpace(ua)
+	if ua == "" {
+		return DeviceInfo{Browser: "Tidak diketahui", OS: "Tidak diketahui", DeviceType: "unknown", Label: "Perangkat tidak dikenal"}
+	}
+
+	d := DeviceInfo{Browser: "Lainnya", OS: "Lainnya", DeviceType: "desktop"}
+
+	switch {
+	case reApp.MatchString(ua):
+		d.Browser, d.DeviceType = "Aplikasi", "app"
+	case reEdge.MatchString(ua):
+		d.Browser, d.BrowserVersion = "Edge", major(reEdge, ua)
+	case reOpera.MatchString(ua):
+		d.Browser, d.BrowserVersion = "Opera", major(reOpera, ua)
+	case reSamsung.MatchString(ua):
+		d.Browser, d.BrowserVersion = "Samsung Internet", major(reSamsung, ua)
+	case reFirefox.MatchString(ua):
+		d.Browser, d.BrowserVersion = "Firefox", major(reFirefox, ua)
+	case reChrome.MatchString(ua):
+		d.Browser, d.BrowserVersion = "Chrome", major(reChrome, ua)
+	case reSafari.MatchString(ua):
+		d.Browser, d.BrowserVersion = "Safari", major(reSafari, ua)
+	}
+
+	switch {
+	case reAndroid.MatchString(ua):
+		d.OS = "Android " + major(reAndroid, ua)
+		if d.DeviceType != "app" {
+			d.DeviceType = "mobile"
+			if !strings.Contains(ua, "Mobile") {
+				d.DeviceType = "tablet"
+			}
+		}
+	case strings.Contains(ua, "iPad"):
+		d.OS = "iPadOS " + strings.ReplaceAll(major(reIOS, ua), "_", ".")
+		if d.DeviceType != "app" {
+			d.DeviceType = "tablet"
+		}
+	case reIOS.MatchString(ua):
+		d.OS = "iOS " + strings.ReplaceAll(major(reIOS, ua), "_", ".")
+		if d.DeviceType != "app" {
+			d.DeviceType = "mobile"
+		}
+	case strings.Contains(ua, "Windows"):
+		d.OS = "Windows"
+	case strings.Contains(ua, "Mac OS X"), strings.Contains(ua, "Macintosh"):
+		d.OS = "macOS"
+	case strings.Contains(ua, "CrOS"):
+		d.OS = "ChromeOS"
+	case strings.Contains(ua, "Linux"):
+		d.OS = "Linux"
+	}
+	d.OS = strings.TrimSpace(d.OS)
+
+	d.Label = d.Browser + " di " + d.OS
+	return d
+}
+
+// major: ambil versi mayor dari grup pertama ("120.0.6099" → "120", "17_2" → "17").
+func major(re *regexp.Regexp, ua string) string {
+	m := re.FindStringSubmatch(ua)
+	if len(m) < 2 {
+		return ""
+	}
+	v := m[1]
+	if i := strings.IndexAny(v, "._"); i > 0 {
+		return v[:i]
+	}
+	return v
+}
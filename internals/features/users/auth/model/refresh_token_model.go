@@ -6,21 +6,30 @@ import (
 	"github.com/google/uuid"
 )
 
+// RefreshTokenModel = 1 sesi login (perangkat). ID stabil selama rotasi refresh
+// dan ikut di access token sebagai klaim "sid".
 type RefreshTokenModel struct {
-	ID        uuid.UUID  `gorm:"column:id;type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
-	UserID    uuid.UUID  `gorm:"column:user_id;type:uuid;not null" json:"user_id"`
+	ID     uuid.UUID `gorm:"column:id;type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	UserID uuid.UUID `gorm:"column:user_id;type:uuid;not null" json:"user_id"`
 
 	// simpan HASH token (bukan plaintext)
-	Token []byte     `gorm:"column:token;type:bytea;not null" json:"-"`
+	Token []byte `gorm:"column:token;type:bytea;not null" json:"-"`
 
-	ExpiresAt time.Time  `gorm:"column:expires_at;type:timestamptz;not null" json:"expires_at"`
-	RevokedAt *time.Time `gorm:"column:revoked_at;type:timestamptz" json:"revoked_at,omitempty"`
+	ExpiresAt    time.Time  `gorm:"column:expires_at;type:timestamptz;not null" json:"expires_at"`
+	RevokedAt    *time.Time `gorm:"column:revoked_at;type:timestamptz" json:"revoked_at,omitempty"`
+	RevokedBy    *uuid.UUID `gorm:"column:revoked_by;type:uuid" json:"revoked_by,omitempty"`
+	RevokeReason *string    `gorm:"column:revoke_reason;type:varchar(32)" json:"revoke_reason,omitempty"`
 
-	UserAgent *string    `gorm:"column:user_agent" json:"user_agent,omitempty"`
-	IP        *string    `gorm:"column:ip;type:inet" json:"ip,omitempty"`
+	UserAgent *string `gorm:"column:user_agent" json:"user_agent,omitempty"`
+	IP        *string `gorm:"column:ip;type:inet" json:"ip,omitempty"`
 
-	CreatedAt time.Time  `gorm:"column:created_at;type:timestamptz;autoCreateTime" json:"created_at"`
-	UpdatedAt time.Time  `gorm:"column:updated_at;type:timestamptz;autoUpdateTime" json:"updated_at"`
+	// HMAC access token terakhir (format token_blacklist) → revoke = blacklist langsung
+	AccessTokenHash *string    `gorm:"column:access_token_hash;type:text" json:"-"`
+	AccessExpiresAt *time.Time `gorm:"column:access_expires_at;type:timestamptz" json:"-"`
+	LastUsedAt      *time.Time `gorm:"column:last_used_at;type:timestamptz" json:"last_used_at,omitempty"`
+
+	CreatedAt time.Time `gorm:"column:created_at;type:timestamptz;autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at;type:timestamptz;autoUpdateTime" json:"updated_at"`
 }
 
 // TableName override
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// UserTOTPModel: secret TOTP per user. EnabledAt nil = enrollment belum dikonfirmasi.
+type UserTOTPModel struct {
+	UserID         uuid.UUID  `gorm:"column:user_id;type:uuid;primaryKey" json:"user_id"`
+	SecretEnc      string     `gorm:"column:secret_enc;type:text;not null" json:"-"`
+	EnabledAt      *time.Time `gorm:"column:enabled_at;type:timestamptz" json:"enabled_at,omitempty"`
+	LastUsedStep   int64      `gorm:"column:last_used_step;not null;default:0" json:"-"`
+	FailedAttempts int        `gorm:"column:failed_attempts;not null;default:0" json:"-"`
+	LockedUntil    *time.Time `gorm:"column:locked_until;type:timestamptz" json:"locked_until,omitempty"`
+
+	CreatedAt time.Time `gorm:"column:created_at;type:timestamptz;autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at;type:timestamptz;autoUpdateTime" json:"updated_at"`
+}
+
+func (UserTOTPModel) TableName() string { return "user_totp" }
+
+// UserRecoveryCodeModel: kode cadangan sekali pakai (yang disimpan hanya hash).
+type UserRecoveryCodeModel struct {
+	ID        uuid.UUID  `gorm:"column:id;type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	UserID    uuid.UUID  `gorm:"column:user_id;type:uuid;not null" json:"user_id"`
+	CodeHash  string     `gorm:"column:code_hash;type:char(64);not null" json:"-"`
+	UsedAt    *time.Time `gorm:"column:used_at;type:timestamptz" json:"used_at,omitempty"`
+	CreatedAt time.Time  `gorm:"column:created_at;type:timestamptz;autoCreateTime" json:"created_at"`
+}
+
+func (UserRecoveryCodeModel) TableName() string { return "user_recovery_codes" }
+
+// SchoolMFAPolicyModel: role yang wajib 2FA di sebuah sekolah.
+type SchoolMFAPolicyModel struct {
+	SchoolID      uuid.UUID      `gorm:"column:school_id;type:uuid;primaryKey" json:"school_id"`
+	RequiredRoles pq.StringArray `gorm:"column:required_roles;type:text[];not null;default:'{}'" json:"required_roles"`
+	UpdatedBy     *uuid.UUID     `gorm:"column:updated_by;type:uuid" json:"updated_by,omitempty"`
+
+	CreatedAt time.Time `gorm:"column:created_at;type:timestamptz;autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at;type:timestamptz;autoUpdateTime" json:"updated_at"`
+}
+
+func (SchoolMFAPolicyModel) TableName() string { return "school_mfa_policies" }
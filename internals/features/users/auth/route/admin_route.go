@@ -0,0 +1,45 @@
+// file: internals/features/users/auth/route/admin_route.go
+package route
+
+import (
+	"madinahsalam_backend/internals/constants"
+	controller "madinahsalam_backend/internals/features/users/auth/controller"
+	authMiddleware "madinahsalam_backend/internals/middlewares/auth"
+	schoolkuMiddleware "madinahsalam_backend/internals/middlewares/features"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// /api/a/user-sessions → admin sekolah mencabut sesi user di sekolahnya
+func AuthSessionAdminRoutes(api fiber.Router, db *gorm.DB) {
+	ctl := controller.NewAuthController(db)
+
+	g := api.Group("/user-sessions",
+		authMiddleware.OnlyRolesSlice(
+			constants.RoleErrorAdmin("mengelola sesi user"),
+			constants.AdminAndAbove,
+		),
+		schoolkuMiddleware.IsSchoolAdmin(),
+	)
+
+	g.Get("/:user_id", ctl.AdminListUserSessions)
+	g.Post("/:user_id/revoke", ctl.AdminRevokeUserSessions)
+}
+
+// /api/a/mfa-policy → kebijakan 2FA wajib per role di sekolah
+func AuthMFAAdminRoutes(api fiber.Router, db *gorm.DB) {
+	ctl := controller.NewAuthController(db)
+
+	g := api.Group("/mfa-policy",
+		authMiddleware.OnlyRolesSlice(
+			constants.RoleErrorAdmin("mengatur kebijakan 2FA"),
+			constants.AdminAndAbove,
+		),
+		schoolkuMiddleware.IsSchoolAdmin(),
+	)
+
+	g.Get("/", ctl.GetSchoolMFAPolicy)
+	g.Put("/", ctl.UpdateSchoolMFAPolicy)
+	g.Post("/users/:user_id/reset", ctl.AdminResetUserMFA)
+}
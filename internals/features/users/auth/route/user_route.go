@@ -2,8 +2,13 @@
 package route
 
 import (
+	"context"
+	"os"
+
 	controller "madinahsalam_backend/internals/features/users/auth/controller"
+	helpersAuth "madinahsalam_backend/internals/helpers/auth"
 	rateLimiter "madinahsalam_backend/internals/middlewares"
+	authJWT "madinahsalam_backend/internals/middlewares/auth_school"
 
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
@@ -15,6 +20,18 @@ func AuthRoutes(app *fiber.App, db *gorm.DB) {
 	// rate limiter global
 	app.Use(rateLimiter.GlobalRateLimiter())
 
+	// link/unlink Google & sesi butuh user yang sedang login.
+	// Blacklist dicek di sini karena middleware blacklist global melewati /api/auth/*.
+	secret := os.Getenv("JWT_SECRET")
+	requireUser := authJWT.AuthJWT(authJWT.AuthJWTOpts{
+		Secret:              secret,
+		AllowCookieFallback: true,
+		BlacklistChecker: func(raw string) (bool, error) {
+			return helpersAuth.IsBlacklisted(context.Background(), db, raw, secret)
+		},
+		SessionChecker: authJWT.SessionRevokedChecker(db),
+	})
+
 	// ==========================
 	// GLOBAL AUTH (TANPA school_slug)
 	// Base: /api/auth
@@ -29,8 +46,12 @@ func AuthRoutes(app *fiber.App, db *gorm.DB) {
 	baseAuth.Post("/login", rateLimiter.LoginRateLimiter(), authController.Login)
 	baseAuth.Post("/register", rateLimiter.RegisterRateLimiter(), authController.Register)
 	baseAuth.Post("/forgot-password/reset", authController.ResetPassword)
-	// Kalau nanti login-google mau global juga, bisa taruh di sini:
-	// baseAuth.Post("/login-google", authController.LoginGoogle)
+	baseAuth.Post("/login-google", rateLimiter.LoginRateLimiter(), authController.LoginGoogle)
+	baseAuth.Post("/login/2fa", rateLimiter.LoginRateLimiter(), authController.VerifyLoginMFA)
+	baseAuth.Post("/login/2fa/setup", rateLimiter.LoginRateLimiter(), authController.BeginLoginMFASetup)
+	baseAuth.Post("/login/2fa/setup/confirm", rateLimiter.LoginRateLimiter(), authController.ConfirmLoginMFASetup)
+	baseAuth.Post("/google/link", requireUser, authController.LinkGoogle)
+	baseAuth.Post("/google/unlink", requireUser, authController.UnlinkGoogle)
 
 	// (Opsional, tapi enak punya versi global juga)
 	baseAuth.Post("/logout", authController.Logout)
@@ -40,6 +61,18 @@ func AuthRoutes(app *fiber.App, db *gorm.DB) {
 	baseAuth.Get("/me/simple-context", authController.GetMySimpleContext)
 	baseAuth.Get("/me/profile-completion", authController.GetMyProfileCompletion)
 
+	// Sesi & perangkat aktif
+	baseAuth.Get("/me/sessions", requireUser, authController.ListMySessions)
+	baseAuth.Post("/me/sessions/revoke-others", requireUser, authController.RevokeMyOtherSessions)
+	baseAuth.Delete("/me/sessions/:id", requireUser, authController.RevokeMySession)
+
+	// Verifikasi dua langkah (TOTP)
+	baseAuth.Get("/me/2fa", requireUser, authController.GetMyMFAStatus)
+	baseAuth.Post("/me/2fa/setup", requireUser, authController.BeginMyMFASetup)
+	baseAuth.Post("/me/2fa/setup/confirm", requireUser, authController.ConfirmMyMFASetup)
+	baseAuth.Post("/me/2fa/recovery-codes", requireUser, authController.RegenerateMyRecoveryCodes)
+	baseAuth.Post("/me/2fa/disable", requireUser, authController.DisableMyMFA)
+
 	// ==========================
 	// PUBLIC (SCOPED BY school_slug)
 	// Base: /api/:school_slug/auth
@@ -49,7 +82,10 @@ func AuthRoutes(app *fiber.App, db *gorm.DB) {
 	publicAuth.Post("/login", rateLimiter.LoginRateLimiter(), authController.Login)
 	publicAuth.Post("/register", rateLimiter.RegisterRateLimiter(), authController.Register)
 	publicAuth.Post("/forgot-password/reset", authController.ResetPassword)
-	// publicAuth.Post("/login-google", authController.LoginGoogle) // kalau nanti diaktifin, juga ikut slug
+	publicAuth.Post("/login-google", rateLimiter.LoginRateLimiter(), authController.LoginGoogle)
+	publicAuth.Post("/login/2fa", rateLimiter.LoginRateLimiter(), authController.VerifyLoginMFA)
+	publicAuth.Post("/login/2fa/setup", rateLimiter.LoginRateLimiter(), authController.BeginLoginMFASetup)
+	publicAuth.Post("/login/2fa/setup/confirm", rateLimiter.LoginRateLimiter(), authController.ConfirmLoginMFASetup)
 
 	// ==========================
 	// PROTECTED (SCOPED BY school_slug)
@@ -60,7 +96,17 @@ func AuthRoutes(app *fiber.App, db *gorm.DB) {
 	protectedAuth.Post("/logout", authController.Logout)
 	protectedAuth.Post("/change-password", authController.ChangePassword)
 	protectedAuth.Put("/update-user-name", authController.UpdateUserName)
+	protectedAuth.Post("/google/link", requireUser, authController.LinkGoogle)
+	protectedAuth.Post("/google/unlink", requireUser, authController.UnlinkGoogle)
 	protectedAuth.Get("/me/context", authController.GetMyContext)
 	protectedAuth.Get("/me/simple-context", authController.GetMySimpleContext)
 	protectedAuth.Get("/me/profile-completion", authController.GetMyProfileCompletion)
+	protectedAuth.Get("/me/sessions", requireUser, authController.ListMySessions)
+	protectedAuth.Post("/me/sessions/revoke-others", requireUser, authController.RevokeMyOtherSessions)
+	protectedAuth.Delete("/me/sessions/:id", requireUser, authController.RevokeMySession)
+	protectedAuth.Get("/me/2fa", requireUser, authController.GetMyMFAStatus)
+	protectedAuth.Post("/me/2fa/setup", requireUser, authController.BeginMyMFASetup)
+	protectedAuth.Post("/me/2fa/setup/confirm", requireUser, authController.ConfirmMyMFASetup)
+	protectedAuth.Post("/me/2fa/recovery-codes", requireUser, authController.RegenerateMyRecoveryCodes)
+	protectedAuth.Post("/me/2fa/disable", requireUser, authController.DisableMyMFA)
 }
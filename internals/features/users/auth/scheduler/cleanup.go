@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+
+	"madinahsalam_backend/internals/helpers/telemetry"
 )
 
 func envInt(key string, def int) int {
@@ -29,6 +31,7 @@ func StartBlacklistCleanupScheduler(db *gorm.DB) {
 		for range ticker.C {
 			now := time.Now().UTC()
 			res := db.Exec(`DELETE FROM token_blacklist WHERE expired_at <= ?`, now)
+			telemetry.ObserveWorkerRun("blacklist_cleanup", now, res.Error)
 			if res.Error != nil {
 				log.Printf("[BL-CLEANUP] error: %v", res.Error)
 				continue
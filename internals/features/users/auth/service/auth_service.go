@@ -586,14 +586,8 @@ func Register(db *gorm.DB, c *fiber.Ctx) error {
 		f := strings.TrimSpace(*u.FullName)
 		u.FullName = &f
 	}
-	if u.GoogleID != nil {
-		g := strings.TrimSpace(*u.GoogleID)
-		if g == "" {
-			u.GoogleID = nil
-		} else {
-			u.GoogleID = &g
-		}
-	}
+	// google_id TIDAK boleh dari body (tanpa verifikasi) → hanya via /login-google atau /google/link
+	u.GoogleID = nil
 	if u.Password != nil {
 		p := strings.TrimSpace(*u.Password)
 		if p == "" {
@@ -612,9 +606,9 @@ func Register(db *gorm.DB, c *fiber.Ctx) error {
 		return helpers.JsonError(c, fiber.StatusBadRequest, "register_as harus 'student' atau 'teacher'")
 	}
 
-	// ---------- Validasi bisnis: minimal password ATAU google_id ----------
-	if (u.Password == nil || *u.Password == "") && (u.GoogleID == nil || *u.GoogleID == "") {
-		return helpers.JsonError(c, fiber.StatusBadRequest, "password atau google_id wajib diisi salah satu")
+	// ---------- Validasi bisnis: password wajib (akun Google daftar via /login-google) ----------
+	if u.Password == nil || *u.Password == "" {
+		return helpers.JsonError(c, fiber.StatusBadRequest, "password wajib diisi (atau daftar dengan Google)")
 	}
 
 	// ---------- Validasi field sesuai tag di model ----------
@@ -986,10 +980,6 @@ func Login(db *gorm.DB, c *fiber.Ctx) error {
 		return helpers.JsonError(c, fiber.StatusBadRequest, err.Error())
 	}
 
-	// ⬇️ Ambil slug dari URL params: /api/:school_slug/auth/login
-	//    SEKARANG OPSIONAL: kalau kosong → login global
-	schoolSlug := strings.TrimSpace(c.Params("school_slug"))
-
 	// Ambil minimal user (include kolom password)
 	userLight, err := authRepo.FindUserByEmailOrUsernameLight(db, input.Identifier)
 	if err != nil {
@@ -1016,6 +1006,25 @@ func Login(db *gorm.DB, c *fiber.Ctx) error {
 		return helpers.JsonError(c, fiber.StatusInternalServerError, "Gagal mengambil data user")
 	}
 
+	return issueTokensForLoginScope(c, db, userFull)
+}
+
+// issueTokensForLoginScope: dipakai login password & Google.
+// school_slug di URL (opsional) menentukan scope token.
+// Bila user punya/wajib 2FA, token BELUM diterbitkan → balas challenge (mfa_token).
+func issueTokensForLoginScope(c *fiber.Ctx, db *gorm.DB, userFull *userModel.UserModel) error {
+	// ⬇️ Ambil slug dari URL params: /api/:school_slug/auth/login
+	//    OPSIONAL: kalau kosong → login global
+	schoolSlug := strings.TrimSpace(c.Params("school_slug"))
+
+	if handled, err := startMFAChallenge(c, db, userFull, schoolSlug); handled {
+		return err
+	}
+	return issueTokensForSlug(c, db, userFull, schoolSlug)
+}
+
+// issueTokensForSlug: terbitkan token (faktor kedua sudah lolos / tidak diperlukan).
+func issueTokensForSlug(c *fiber.Ctx, db *gorm.DB, userFull *userModel.UserModel, schoolSlug string) error {
 	// Roles (roles_global & school_roles) — masih full multi-school
 	rolesClaim, err := getUserRolesClaim(c.Context(), db, userFull.ID)
 	if err != nil {
@@ -1189,11 +1198,12 @@ func buildStudentRecords(db *gorm.DB, userID uuid.UUID, rc helpersAuth.RolesClai
 // Helpers (JWT claims & resp)
 // ==========================
 
-func buildRefreshClaims(userID uuid.UUID, now time.Time) jwt.MapClaims {
+func buildRefreshClaims(userID, sessionID uuid.UUID, now time.Time) jwt.MapClaims {
 	return jwt.MapClaims{
 		"typ": "refresh",
 		"sub": userID.String(),
 		"id":  userID.String(),
+		"sid": sessionID.String(),
 		"iat": now.Unix(),
 		"exp": now.Add(refreshTTLDefault).Unix(),
 	}
@@ -1491,7 +1501,10 @@ func issueTokensWithRoles(
 		now,
 	)
 
-	refreshClaims := buildRefreshClaims(user.ID, now)
+	// 1 login = 1 sesi (refresh_tokens.id); sid ikut di access & refresh token
+	sessionID := uuid.New()
+	accessClaims["sid"] = sessionID.String()
+	refreshClaims := buildRefreshClaims(user.ID, sessionID, now)
 
 	accessToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims).
 		SignedString([]byte(jwtSecret))
@@ -1507,12 +1520,18 @@ func issueTokensWithRoles(
 
 	// simpan hash refresh
 	tokenHash := computeRefreshHash(refreshToken, refreshSecret)
+	accessHash := helpersAuth.HashAccessToken(accessToken, jwtSecret)
+	accessExp := now.Add(accessTTLDefault)
 	if err := createRefreshTokenFast(db, &authModel.RefreshTokenModel{
-		UserID:    user.ID,
-		Token:     tokenHash,
-		ExpiresAt: now.Add(refreshTTLDefault),
-		UserAgent: strptr(c.Get("User-Agent")),
-		IP:        strptr(c.IP()),
+		ID:              sessionID,
+		UserID:          user.ID,
+		Token:           tokenHash,
+		ExpiresAt:       now.Add(refreshTTLDefault),
+		UserAgent:       strptr(c.Get("User-Agent")),
+		IP:              strptr(c.IP()),
+		AccessTokenHash: &accessHash,
+		AccessExpiresAt: &accessExp,
+		LastUsedAt:      &now,
 	}); err != nil {
 		return helpers.JsonError(c, fiber.StatusInternalServerError, "Gagal menyimpan refresh token")
 	}
@@ -1646,231 +1665,3 @@ func resolveBlacklistTTL(accessToken string) time.Duration {
 // func CheckSecurityAnswer(db *gorm.DB, c *fiber.Ctx) error {
 // 	return helpers.JsonError(c, fiber.StatusGone, "Security Q/A sudah tidak didukung. Gunakan alur reset password via email OTP atau magic link.")
 // }
-
-/* ==========================
-   LOGIN GOOGLE (refactor: tx + upsert profile + snapshot)
-========================== */
-
-// func LoginGoogle(db *gorm.DB, c *fiber.Ctx) error {
-// 	var in struct {
-// 		IDToken    string `json:"id_token"`
-// 		Credential string `json:"credential"` // fallback kalau FE kirim credential
-// 	}
-// 	if err := c.BodyParser(&in); err != nil {
-// 		return helpers.JsonError(c, fiber.StatusBadRequest, "Invalid request body")
-// 	}
-
-// 	idToken := strings.TrimSpace(in.IDToken)
-// 	if idToken == "" {
-// 		idToken = strings.TrimSpace(in.Credential)
-// 	}
-// 	if idToken == "" {
-// 		return helpers.JsonError(c, fiber.StatusBadRequest, "id_token is required")
-// 	}
-
-// 	clientID := strings.TrimSpace(configs.GoogleClientID)
-// 	if clientID == "" {
-// 		log.Printf("[login-google] GOOGLE_CLIENT_ID empty")
-// 		return helpers.JsonError(c, fiber.StatusInternalServerError, "Server misconfigured")
-// 	}
-
-// 	// === Decode terlebih dulu untuk diagnosa (aud/iss/exp) ===
-// 	claimSet, decErr := googleAuthIDTokenVerifier.Decode(idToken)
-// 	if decErr != nil {
-// 		log.Printf("[login-google] decode error: %v", decErr)
-// 		return helpers.JsonError(c, fiber.StatusUnauthorized, "Invalid Google ID Token")
-// 	}
-
-// 	// Cek expiry (toleransi kecil jika perlu)
-// 	now := time.Now().Unix()
-// 	if claimSet.Exp <= now {
-// 		log.Printf("[login-google] token expired: exp=%d now=%d", claimSet.Exp, now)
-// 		return helpers.JsonError(c, fiber.StatusUnauthorized, "Expired Google ID Token")
-// 	}
-
-// 	// Cek issuer (dua value yang valid)
-// 	iss := strings.TrimSpace(claimSet.Iss)
-// 	if iss != "accounts.google.com" && iss != "https://accounts.google.com" {
-// 		log.Printf("[login-google] invalid issuer: %s", iss)
-// 		return helpers.JsonError(c, fiber.StatusUnauthorized, "Invalid Google ID Token (issuer)")
-// 	}
-
-// 	// Cek audience HARUS = clientID FE
-// 	if strings.TrimSpace(claimSet.Aud) != clientID {
-// 		log.Printf("[login-google] audience mismatch: token.aud=%q server.clientID=%q", claimSet.Aud, clientID)
-// 		return helpers.JsonError(c, fiber.StatusUnauthorized, "Invalid Google ID Token (audience)")
-// 	}
-
-// 	// === Verifikasi signature menggunakan lib bawaan kamu ===
-// 	v := googleAuthIDTokenVerifier.Verifier{}
-// 	if err := v.VerifyIDToken(idToken, []string{clientID}); err != nil {
-// 		log.Printf("[login-google] signature verify failed: %v", err)
-// 		return helpers.JsonError(c, fiber.StatusUnauthorized, "Invalid Google ID Token")
-// 	}
-
-// 	// Ambil data penting
-// 	email := strings.ToLower(strings.TrimSpace(claimSet.Email))
-// 	name := strings.TrimSpace(claimSet.Name)
-// 	googleID := strings.TrimSpace(claimSet.Sub)
-// 	if email == "" || googleID == "" {
-// 		log.Printf("[login-google] missing email/sub: email=%q sub=%q", email, googleID)
-// 		return helpers.JsonError(c, fiber.StatusUnauthorized, "Google token missing required fields")
-// 	}
-
-// 	// helper
-// 	ptrIfNotEmpty := func(s string) *string {
-// 		t := strings.TrimSpace(s)
-// 		if t == "" {
-// 			return nil
-// 		}
-// 		return &t
-// 	}
-
-// 	// === Upsert/link user (kode kamu seperti semula, dipersingkat di sini) ===
-// 	var user *userModel.UserModel
-// 	if u, err := authRepo.FindUserByGoogleID(db, googleID); err == nil && u != nil {
-// 		// sudah terhubung
-// 		if txErr := db.Transaction(func(tx *gorm.DB) error {
-// 			snap := u.FullName
-// 			if snap == nil || strings.TrimSpace(*snap) == "" {
-// 				snap = ptrIfNotEmpty(name)
-// 			}
-// 			if err := userProfileService.EnsureProfileRow(c.Context(), tx, u.ID, snap); err != nil {
-// 				return err
-// 			}
-// 			if err := grantDefaultUserRole(c.Context(), tx, u.ID); err != nil {
-// 				log.Printf("[login-google] grant role fail: %v", err)
-// 			}
-// 			return nil
-// 		}); txErr != nil {
-// 			return helpers.JsonError(c, fiber.StatusInternalServerError, "Failed to prepare user profile")
-// 		}
-// 		user = u
-// 	} else if ue, err2 := authRepo.FindUserByEmail(db, email); err2 == nil && ue != nil {
-// 		// link ke akun email
-// 		if err := db.Transaction(func(tx *gorm.DB) error {
-// 			now := time.Now().UTC()
-// 			if ue.GoogleID == nil || *ue.GoogleID == "" {
-// 				ue.GoogleID = &googleID
-// 				if ue.EmailVerifiedAt == nil {
-// 					ue.EmailVerifiedAt = &now
-// 				}
-// 				if err := tx.Model(ue).Updates(map[string]any{
-// 					"google_id":         ue.GoogleID,
-// 					"email_verified_at": ue.EmailVerifiedAt,
-// 					"updated_at":        now,
-// 				}).Error; err != nil {
-// 					return err
-// 				}
-// 			}
-// 			snap := ue.FullName
-// 			if snap == nil || strings.TrimSpace(*snap) == "" {
-// 				snap = ptrIfNotEmpty(name)
-// 			}
-// 			if err := userProfileService.EnsureProfileRow(c.Context(), tx, ue.ID, snap); err != nil {
-// 				return err
-// 			}
-// 			if err := grantDefaultUserRole(c.Context(), tx, ue.ID); err != nil {
-// 				log.Printf("[login-google] grant role fail: %v", err)
-// 			}
-// 			return nil
-// 		}); err != nil {
-// 			return helpers.JsonError(c, fiber.StatusInternalServerError, "Failed to link Google account")
-// 		}
-// 		user = ue
-// 	} else {
-// 		// buat user baru
-// 		if err := db.Transaction(func(tx *gorm.DB) error {
-// 			now := time.Now().UTC()
-// 			base := suggestUsername(name, email)
-// 			username := base
-// 			for i := 0; i < 5; i++ {
-// 				exists, _ := authRepo.IsUsernameTaken(tx, username)
-// 				if !exists {
-// 					break
-// 				}
-// 				username = base + "-" + shortRand()
-// 			}
-// 			newUser := userModel.UserModel{
-// 				UserName:        username,
-// 				FullName:        ptrIfNotEmpty(name),
-// 				Email:           email,
-// 				Password:        nil,
-// 				GoogleID:        &googleID,
-// 				IsActive:        true,
-// 				EmailVerifiedAt: &now,
-// 				CreatedAt:       now,
-// 				UpdatedAt:       now,
-// 			}
-// 			if err := authRepo.CreateUser(tx, &newUser); err != nil {
-// 				return err
-// 			}
-// 			if err := userProfileService.EnsureProfileRow(c.Context(), tx, newUser.ID, newUser.FullName); err != nil {
-// 				return err
-// 			}
-// 			if err := grantDefaultUserRole(c.Context(), tx, newUser.ID); err != nil {
-// 				log.Printf("[login-google] grant role fail: %v", err)
-// 			}
-// 			user = &newUser
-// 			return nil
-// 		}); err != nil {
-// 			return helpers.JsonError(c, fiber.StatusInternalServerError, "Failed to create Google user")
-// 		}
-// 	}
-
-// 	// Guard aktif + roles + issue token/cookie (kode kamu)
-// 	userFull, err := authRepo.FindUserByID(db, user.ID)
-// 	if err != nil {
-// 		return helpers.JsonError(c, fiber.StatusInternalServerError, "Gagal mengambil data user")
-// 	}
-// 	if !userFull.IsActive {
-// 		return helpers.JsonError(c, fiber.StatusForbidden, "Akun Anda telah dinonaktifkan. Hubungi admin.")
-// 	}
-// 	rolesClaim, err := getUserRolesClaim(c.Context(), db, userFull.ID)
-// 	if err != nil {
-// 		return helpers.JsonError(c, fiber.StatusInternalServerError, "Gagal mengambil roles user")
-// 	}
-
-// 	return issueTokensWithRoles(c, db, *userFull, rolesClaim)
-// }
-
-// suggestUsername: dari nama → slug-ish; fallback ambil bagian local dari email
-// func suggestUsername(name, email string) string {
-// 	cand := strings.ToLower(strings.TrimSpace(name))
-// 	cand = strings.ReplaceAll(cand, "  ", " ")
-// 	cand = strings.ReplaceAll(cand, " ", "-")
-// 	cand = sanitizeUsername(cand)
-// 	if cand == "" {
-// 		if i := strings.Index(email, "@"); i > 0 {
-// 			cand = sanitizeUsername(email[:i])
-// 		}
-// 	}
-// 	if cand == "" {
-// 		cand = "user"
-// 	}
-// 	if len(cand) > 50 {
-// 		cand = cand[:50]
-// 	}
-// 	return cand
-// }
-
-// sanitizeUsername: simpan huruf/angka/dash/underscore saja
-// func sanitizeUsername(s string) string {
-// 	var b strings.Builder
-// 	for _, r := range s {
-// 		switch {
-// 		case r >= 'a' && r <= 'z':
-// 			b.WriteRune(r)
-// 		case r >= '0' && r <= '9':
-// 			b.WriteRune(r)
-// 		case r == '-' || r == '_':
-// 			b.WriteRune(r)
-// 		}
-// 	}
-// 	return b.String()
-// }
-
-// func shortRand() string {
-// 	// ringkas: 4 chars hex dari unixnano
-// 	return strconv.FormatInt(time.Now().UnixNano()%0xffff, 16)
-// }
@@ -0,0 +1,354 @@
+// internals/features/users/auth/service/google_auth_service.go
+package service
+
+import (
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	authRepo "madinahsalam_backend/internals/features/users/auth/repository"
+	userModel "madinahsalam_backend/internals/features/users/users/model"
+	userProfileService "madinahsalam_backend/internals/features/users/users/service"
+	helpers "madinahsalam_backend/internals/helpers"
+)
+
+/* ==========================
+   LOGIN GOOGLE
+   POST /api/auth/login-google                 (global)
+   POST /api/:school_slug/auth/login-google    (scope 1 sekolah, sama seperti /login)
+   body: {id_token} atau {credential} (Google Identity Services)
+========================== */
+
+func readGoogleIDToken(c *fiber.Ctx) (string, error) {
+	var in struct {
+		IDToken    string `json:"id_token"`
+		Credential string `json:"credential"` // fallback kalau FE kirim credential
+	}
+	if err := c.BodyParser(&in); err != nil {
+		return "", fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	tok := strings.TrimSpace(in.IDToken)
+	if tok == "" {
+		tok = strings.TrimSpace(in.Credential)
+	}
+	if tok == "" {
+		return "", fiber.NewError(fiber.StatusBadRequest, "id_token is required")
+	}
+	return tok, nil
+}
+
+func verifyGoogleFromRequest(c *fiber.Ctx) (*GoogleIDClaims, error) {
+	idToken, err := readGoogleIDToken(c)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := VerifyGoogleIDToken(c.Context(), idToken)
+	if err != nil {
+		if errors.Is(err, ErrGoogleMisconfigured) {
+			log.Printf("[login-google] %v", err)
+			return nil, fiber.NewError(fiber.StatusInternalServerError, "Server misconfigured")
+		}
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "Invalid Google ID Token")
+	}
+	return claims, nil
+}
+
+func LoginGoogle(db *gorm.DB, c *fiber.Ctx) error {
+	claims, err := verifyGoogleFromRequest(c)
+	if err != nil {
+		return writeFiberErr(c, err)
+	}
+	googleID := strings.TrimSpace(claims.Subject)
+	email := claims.Email
+	if email == "" {
+		return helpers.JsonError(c, fiber.StatusUnauthorized, "Google token missing email")
+	}
+
+	var user *userModel.UserModel
+	err = db.Transaction(func(tx *gorm.DB) error {
+		// 1) sudah terhubung
+		if u, err := authRepo.FindUserByGoogleID(tx, googleID); err == nil && u != nil {
+			user = u
+			return ensureGoogleUserProfile(c, tx, u, claims.Name)
+		} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		// 2) akun email yang sama → auto-link HANYA bila Google menjamin email-nya
+		if ue, err := authRepo.FindUserByEmail(tx, email); err == nil && ue != nil {
+			if !claims.IsEmailVerified() {
+				return fiber.NewError(fiber.StatusForbidden, ErrGoogleEmailUnverify.Error())
+			}
+			if ue.GoogleID != nil && *ue.GoogleID != "" && *ue.GoogleID != googleID {
+				return fiber.NewError(fiber.StatusConflict, "Email ini sudah terhubung ke akun Google lain")
+			}
+			now := time.Now().UTC()
+			upd := map[string]any{"google_id": googleID, "updated_at": now}
+			if ue.EmailVerifiedAt == nil {
+				upd["email_verified_at"] = now
+				ue.EmailVerifiedAt = &now
+			}
+			if err := tx.Model(ue).Updates(upd).Error; err != nil {
+				return err
+			}
+			ue.GoogleID = &googleID
+			user = ue
+			return ensureGoogleUserProfile(c, tx, ue, claims.Name)
+		} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		// 3) user baru (tanpa password)
+		if !claims.IsEmailVerified() {
+			return fiber.NewError(fiber.StatusForbidden, ErrGoogleEmailUnverify.Error())
+		}
+		now := time.Now().UTC()
+		base := suggestUsername(claims.Name, email)
+		username := base
+		for i := 0; i < 5; i++ {
+			taken, err := authRepo.IsUsernameTaken(tx, username)
+			if err != nil || !taken {
+				break
+			}
+			username = trimTo(base, 45) + "-" + shortRand()
+		}
+		nu := userModel.UserModel{
+			UserName:        username,
+			FullName:        ptrIfNotEmpty(claims.Name),
+			Email:           email,
+			GoogleID:        &googleID,
+			IsActive:        true,
+			EmailVerifiedAt: &now,
+			CreatedAt:       now,
+			UpdatedAt:       now,
+		}
+		if err := authRepo.CreateUser(tx, &nu); err != nil {
+			return err
+		}
+		user = &nu
+		return ensureGoogleUserProfile(c, tx, &nu, claims.Name)
+	})
+	if err != nil {
+		var fe *fiber.Error
+		if errors.As(err, &fe) {
+			return helpers.JsonError(c, fe.Code, fe.Message)
+		}
+		log.Printf("[login-google] tx error: %v", err)
+		return helpers.JsonError(c, fiber.StatusInternalServerError, "Gagal memproses login Google")
+	}
+
+	userFull, err := authRepo.FindUserByID(db, user.ID)
+	if err != nil {
+		return helpers.JsonError(c, fiber.StatusInternalServerError, "Gagal mengambil data user")
+	}
+	if !userFull.IsActive {
+		return helpers.JsonError(c, fiber.StatusForbidden, "Akun Anda telah dinonaktifkan. Hubungi admin.")
+	}
+	return issueTokensForLoginScope(c, db, userFull)
+}
+
+func ensureGoogleUserProfile(c *fiber.Ctx, tx *gorm.DB, u *userModel.UserModel, name string) error {
+	snap := u.FullName
+	if snap == nil || strings.TrimSpace(*snap) == "" {
+		snap = ptrIfNotEmpty(name)
+	}
+	if err := userProfileService.EnsureProfileRow(c.Context(), tx, u.ID, snap); err != nil {
+		return err
+	}
+	if err := grantDefaultUserRole(c.Context(), tx, u.ID); err != nil {
+		log.Printf("[login-google] grant role fail: %v", err)
+	}
+	return nil
+}
+
+/* ==========================
+   LINK / UNLINK (akun password yang sudah login)
+   POST /api/auth/google/link     {id_token}
+   POST /api/auth/google/unlink
+========================== */
+
+func userIDFromLocals(c *fiber.Ctx) (uuid.UUID, error) {
+	s, ok := c.Locals("user_id").(string)
+	if !ok || s == "" {
+		return uuid.Nil, fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return uuid.Nil, fiber.NewError(fiber.StatusUnauthorized, "Invalid user id")
+	}
+	return id, nil
+}
+
+func LinkGoogle(db *gorm.DB, c *fiber.Ctx) error {
+	userID, err := userIDFromLocals(c)
+	if err != nil {
+		return writeFiberErr(c, err)
+	}
+	claims, err := verifyGoogleFromRequest(c)
+	if err != nil {
+		return writeFiberErr(c, err)
+	}
+	googleID := strings.TrimSpace(claims.Subject)
+
+	user, err := authRepo.FindUserByID(db, userID)
+	if err != nil {
+		return helpers.JsonError(c, fiber.StatusUnauthorized, "User not found")
+	}
+	var other *userModel.UserModel
+	if o, err := authRepo.FindUserByGoogleID(db, googleID); err == nil {
+		other = o
+	}
+	linked, err := checkGoogleLink(user, googleID, other)
+	if err != nil {
+		return helpers.JsonError(c, fiber.StatusConflict, err.Error())
+	}
+	if linked {
+		return helpers.JsonOK(c, "Akun Google sudah terhubung", fiber.Map{"google_linked": true})
+	}
+
+	if err := db.Model(&userModel.UserModel{}).
+		Where("id = ? AND (google_id IS NULL OR google_id = '')", userID).
+		Updates(map[string]any{"google_id": googleID, "updated_at": time.Now().UTC()}).Error; err != nil {
+		low := strings.ToLower(err.Error())
+		if strings.Contains(low, "google_id") && (strings.Contains(low, "unique") || strings.Contains(low, "duplicate")) {
+			return helpers.JsonError(c, fiber.StatusConflict, ErrGoogleLinkedElsewhere.Error())
+		}
+		return helpers.JsonError(c, fiber.StatusInternalServerError, "Gagal menghubungkan akun Google")
+	}
+	return helpers.JsonOK(c, "Akun Google berhasil dihubungkan", fiber.Map{
+		"google_linked": true,
+		"google_email":  claims.Email,
+	})
+}
+
+func UnlinkGoogle(db *gorm.DB, c *fiber.Ctx) error {
+	userID, err := userIDFromLocals(c)
+	if err != nil {
+		return writeFiberErr(c, err)
+	}
+	user, err := authRepo.FindUserByID(db, userID)
+	if err != nil {
+		return helpers.JsonError(c, fiber.StatusUnauthorized, "User not found")
+	}
+	linked, err := checkGoogleUnlink(user)
+	if err != nil {
+		return helpers.JsonError(c, fiber.StatusBadRequest, err.Error())
+	}
+	if !linked {
+		return helpers.JsonOK(c, "Akun Google tidak terhubung", fiber.Map{"google_linked": false})
+	}
+
+	if err := db.Model(&userModel.UserModel{}).
+		Where("id = ?", userID).
+		Updates(map[string]any{"google_id": nil, "updated_at": time.Now().UTC()}).Error; err != nil {
+		return helpers.JsonError(c, fiber.StatusInternalServerError, "Gagal memutuskan akun Google")
+	}
+	return helpers.JsonOK(c, "Akun Google berhasil diputuskan", fiber.Map{"google_linked": false})
+}
+
+/* ==========================
+   Aturan link / unlink (tanpa DB → bisa diuji langsung)
+========================== */
+
+var (
+	ErrGoogleAlreadyLinked   = errors.New("Akun ini sudah terhubung ke akun Google lain. Putuskan dulu.")
+	ErrGoogleLinkedElsewhere = errors.New("Akun Google ini sudah terhubung ke user lain")
+	ErrGoogleOnlyLogin       = errors.New("Set password terlebih dahulu sebelum memutuskan akun Google")
+)
+
+// checkGoogleLink: linked=true bila user sudah terhubung ke googleID yang sama (idempotent).
+// other = pemilik googleID saat ini (nil bila belum dipakai).
+func checkGoogleLink(user *userModel.UserModel, googleID string, other *userModel.UserModel) (linked bool, err error) {
+	if user.GoogleID != nil && *user.GoogleID != "" {
+		if *user.GoogleID == googleID {
+			return true, nil
+		}
+		return false, ErrGoogleAlreadyLinked
+	}
+	if other != nil && other.ID != user.ID {
+		return false, ErrGoogleLinkedElsewhere
+	}
+	return false, nil
+}
+
+// checkGoogleUnlink: linked=false bila memang tidak terhubung.
+// Akun Google-only wajib set password dulu supaya user tidak terkunci.
+func checkGoogleUnlink(user *userModel.UserModel) (linked bool, err error) {
+	if user.GoogleID == nil || *user.GoogleID == "" {
+		return false, nil
+	}
+	if user.Password == nil || *user.Password == "" {
+		return true, ErrGoogleOnlyLogin
+	}
+	return true, nil
+}
+
+/* ==========================
+   UTIL
+========================== */
+
+func writeFiberErr(c *fiber.Ctx, err error) error {
+	var fe *fiber.Error
+	if errors.As(err, &fe) {
+		return helpers.JsonError(c, fe.Code, fe.Message)
+	}
+	return helpers.JsonError(c, fiber.StatusInternalServerError, err.Error())
+}
+
+func ptrIfNotEmpty(s string) *string {
+	t := strings.TrimSpace(s)
+	if t == "" {
+		return nil
+	}
+	return &t
+}
+
+func trimTo(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
+
+// suggestUsername: dari nama → slug-ish; fallback ambil bagian local dari email
+func suggestUsername(name, email string) string {
+	cand := strings.ToLower(strings.TrimSpace(name))
+	cand = strings.Join(strings.Fields(cand), "-")
+	cand = sanitizeUsername(cand)
+	if len(cand) < 3 {
+		if i := strings.Index(email, "@"); i > 0 {
+			cand = sanitizeUsername(strings.ToLower(email[:i]))
+		}
+	}
+	if len(cand) < 3 {
+		cand = "user"
+	}
+	return trimTo(cand, 50)
+}
+
+// sanitizeUsername: simpan huruf/angka/dash/underscore saja
+func sanitizeUsername(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z':
+			b.WriteRune(r)
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '-' || r == '_':
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func shortRand() string {
+	// ringkas: 4 chars hex dari unixnano
+	return strconv.FormatInt(time.Now().UnixNano()%0xffff, 16)
+}
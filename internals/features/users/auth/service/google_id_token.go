@@ -0,0 +1,238 @@
+// internals/features/users/auth/service/google_id_token.go
+package service
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"madinahsalam_backend/internals/configs"
+)
+
+/* ==========================
+   Google ID token (RS256 + JWKS)
+
+   ENV:
+   - GOOGLE_CLIENT_ID   : boleh lebih dari satu, pisah koma (web, android, ios)
+   - GOOGLE_JWKS_URL    : default https://www.googleapis.com/oauth2/v3/certs
+                          (arahkan ke stub lokal saat testing)
+
+   Kenapa tidak pakai google.golang.org/api/idtoken:
+   - modul itu belum ada di go.mod dan menarik google.golang.org/api + oauth2/grpc
+     hanya untuk 1 verifikasi RS256; golang-jwt sudah dipakai untuk token kita sendiri.
+   - idtoken.Validate hanya menerima 1 audience, sedangkan kita punya beberapa
+     client ID (web, android, ios).
+   - URL sertifikat idtoken tidak bisa diarahkan ke stub, jadi jalur verifikasi
+     tidak bisa diuji end-to-end (lihat google_id_token_test.go).
+   Yang dicek tetap sama: alg RS256, kid dari JWKS Google (cache ikut max-age),
+   iss accounts.google.com, aud ∈ GOOGLE_CLIENT_ID, exp, sub.
+========================== */
+
+const defaultGoogleJWKSURL = "https://www.googleapis.com/oauth2/v3/certs"
+
+var (
+	ErrGoogleTokenInvalid  = errors.New("invalid Google ID token")
+	ErrGoogleEmailUnverify = errors.New("email Google belum terverifikasi")
+	ErrGoogleMisconfigured = errors.New("GOOGLE_CLIENT_ID belum diset")
+)
+
+var googleIssuers = map[string]struct{}{
+	"accounts.google.com":         {},
+	"https://accounts.google.com": {},
+}
+
+type GoogleIDClaims struct {
+	Email         string `json:"email"`
+	EmailVerified any    `json:"email_verified"` // bool atau "true" (token lama)
+	Name          string `json:"name"`
+	Picture       string `json:"picture"`
+	HostedDomain  string `json:"hd"`
+	jwt.RegisteredClaims
+}
+
+func (g *GoogleIDClaims) IsEmailVerified() bool {
+	switch v := g.EmailVerified.(type) {
+	case bool:
+		return v
+	case string:
+		return strings.EqualFold(v, "true")
+	}
+	return false
+}
+
+/* ==========================
+   JWKS cache
+========================== */
+
+type jwksCache struct {
+	mu        sync.RWMutex
+	url       string
+	keys      map[string]*rsa.PublicKey
+	expiresAt time.Time
+	lastFetch time.Time
+	client    *http.Client
+}
+
+var googleKeys = &jwksCache{client: &http.Client{Timeout: 5 * time.Second}}
+
+func googleJWKSURL() string {
+	if v := strings.TrimSpace(os.Getenv("GOOGLE_JWKS_URL")); v != "" {
+		return v
+	}
+	return defaultGoogleJWKSURL
+}
+
+func googleClientIDs() []string {
+	return sanitizeListCSV(configs.GoogleClientID)
+}
+
+func (j *jwksCache) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	url := googleJWKSURL()
+
+	j.mu.RLock()
+	k, ok := j.keys[kid]
+	fresh := j.url == url && time.Now().Before(j.expiresAt)
+	j.mu.RUnlock()
+	if ok && fresh {
+		return k, nil
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	// kid tak dikenal → refetch, tapi maksimal 1x per 30 detik (cegah amplifikasi)
+	if j.url == url && time.Now().Before(j.expiresAt) && time.Since(j.lastFetch) < 30*time.Second {
+		if k, ok := j.keys[kid]; ok {
+			return k, nil
+		}
+		return nil, ErrGoogleTokenInvalid
+	}
+	if err := j.fetchLocked(ctx, url); err != nil {
+		return nil, err
+	}
+	if k, ok := j.keys[kid]; ok {
+		return k, nil
+	}
+	return nil, ErrGoogleTokenInvalid
+}
+
+func (j *jwksCache) fetchLocked(ctx context.Context, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("fetch jwks: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetch jwks: status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Keys []struct {
+			Kid string `json:"kid"`
+			Kty string `json:"kty"`
+			Alg string `json:"alg"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("decode jwks: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(body.Keys))
+	for _, k := range body.Keys {
+		if k.Kty != "RSA" || k.Kid == "" {
+			continue
+		}
+		nb, err1 := base64.RawURLEncoding.DecodeString(k.N)
+		eb, err2 := base64.RawURLEncoding.DecodeString(k.E)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(nb),
+			E: int(new(big.Int).SetBytes(eb).Int64()),
+		}
+	}
+
+	now := time.Now()
+	j.url = url
+	j.keys = keys
+	j.lastFetch = now
+	j.expiresAt = now.Add(maxAgeOr(resp.Header.Get("Cache-Control"), time.Hour))
+	return nil
+}
+
+func maxAgeOr(cacheControl string, def time.Duration) time.Duration {
+	for _, part := range strings.Split(cacheControl, ",") {
+		part = strings.TrimSpace(part)
+		if v, ok := strings.CutPrefix(part, "max-age="); ok {
+			if n, err := strconv.Atoi(v); err == nil && n > 0 {
+				return time.Duration(n) * time.Second
+			}
+		}
+	}
+	return def
+}
+
+/* ==========================
+   Verify
+========================== */
+
+// VerifyGoogleIDToken memvalidasi signature (JWKS), iss, aud (GOOGLE_CLIENT_ID), exp.
+func VerifyGoogleIDToken(ctx context.Context, idToken string) (*GoogleIDClaims, error) {
+	clientIDs := googleClientIDs()
+	if len(clientIDs) == 0 {
+		return nil, ErrGoogleMisconfigured
+	}
+
+	claims := &GoogleIDClaims{}
+	parser := jwt.Parser{ValidMethods: []string{jwt.SigningMethodRS256.Alg()}}
+	tok, err := parser.ParseWithClaims(idToken, claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		if kid == "" {
+			return nil, ErrGoogleTokenInvalid
+		}
+		return googleKeys.key(ctx, kid)
+	})
+	if err != nil || !tok.Valid {
+		return nil, ErrGoogleTokenInvalid
+	}
+
+	if _, ok := googleIssuers[claims.Issuer]; !ok {
+		return nil, ErrGoogleTokenInvalid
+	}
+	audOK := false
+	for _, id := range clientIDs {
+		if claims.VerifyAudience(id, true) {
+			audOK = true
+			break
+		}
+	}
+	if !audOK || claims.ExpiresAt == nil {
+		return nil, ErrGoogleTokenInvalid
+	}
+	if strings.TrimSpace(claims.Subject) == "" {
+		return nil, ErrGoogleTokenInvalid
+	}
+
+	claims.Email = strings.ToLower(strings.TrimSpace(claims.Email))
+	claims.Name = strings.TrimSpace(claims.Name)
+	return claims, nil
+}
@@ -0,0 +1,204 @@
+// internals/features/users/auth/service/google_id_token_test.go
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+
+	"madinahsalam_backend/internals/configs"
+	userModel "madinahsalam_backend/internals/features/users/users/model"
+)
+
+const (
+	testKID      = "test-kid-1"
+	testClientID = "web-client.apps.googleusercontent.com"
+)
+
+// stubGoogle: JWKS lokal (httptest) + GOOGLE_JWKS_URL / GOOGLE_CLIENT_ID untuk 1 test.
+func stubGoogle(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "public, max-age=600")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{{
+				"kid": testKID,
+				"kty": "RSA",
+				"alg": "RS256",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	t.Setenv("GOOGLE_JWKS_URL", srv.URL)
+	prev := configs.GoogleClientID
+	configs.GoogleClientID = "android-client.apps.googleusercontent.com," + testClientID
+	t.Cleanup(func() { configs.GoogleClientID = prev })
+	return key
+}
+
+func signGoogle(t *testing.T, key *rsa.PrivateKey, kid string, mutate func(*GoogleIDClaims)) string {
+	t.Helper()
+	now := time.Now()
+	claims := &GoogleIDClaims{
+		Email:         "Guru@Example.com",
+		EmailVerified: true,
+		Name:          " Guru Satu ",
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    "https://accounts.google.com",
+			Subject:   "1234567890",
+			Audience:  jwt.ClaimStrings{testClientID},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		},
+	}
+	if mutate != nil {
+		mutate(claims)
+	}
+	tok := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	tok.Header["kid"] = kid
+	s, err := tok.SignedString(key)
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	return s
+}
+
+func TestVerifyGoogleIDToken_Valid(t *testing.T) {
+	key := stubGoogle(t)
+
+	claims, err := VerifyGoogleIDToken(context.Background(), signGoogle(t, key, testKID, nil))
+	if err != nil {
+		t.Fatalf("expected valid token, got %v", err)
+	}
+	if claims.Subject != "1234567890" {
+		t.Errorf("sub = %q", claims.Subject)
+	}
+	if claims.Email != "guru@example.com" || claims.Name != "Guru Satu" {
+		t.Errorf("email/name tidak dinormalisasi: %q / %q", claims.Email, claims.Name)
+	}
+	if !claims.IsEmailVerified() {
+		t.Error("email_verified harus true")
+	}
+}
+
+func TestVerifyGoogleIDToken_Rejects(t *testing.T) {
+	key := stubGoogle(t)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	cases := []struct {
+		name  string
+		token func() string
+	}{
+		{"wrong aud", func() string {
+			return signGoogle(t, key, testKID, func(c *GoogleIDClaims) {
+				c.Audience = jwt.ClaimStrings{"someone-else.apps.googleusercontent.com"}
+			})
+		}},
+		{"wrong iss", func() string {
+			return signGoogle(t, key, testKID, func(c *GoogleIDClaims) {
+				c.Issuer = "https://evil.example.com"
+			})
+		}},
+		{"expired", func() string {
+			return signGoogle(t, key, testKID, func(c *GoogleIDClaims) {
+				c.IssuedAt = jwt.NewNumericDate(time.Now().Add(-2 * time.Hour))
+				c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+			})
+		}},
+		{"unknown kid", func() string {
+			return signGoogle(t, key, "rotated-away", nil)
+		}},
+		{"signed by other key", func() string {
+			return signGoogle(t, otherKey, testKID, nil)
+		}},
+		{"missing sub", func() string {
+			return signGoogle(t, key, testKID, func(c *GoogleIDClaims) { c.Subject = "" })
+		}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := VerifyGoogleIDToken(context.Background(), tc.token())
+			if !errors.Is(err, ErrGoogleTokenInvalid) {
+				t.Fatalf("expected ErrGoogleTokenInvalid, got %v", err)
+			}
+		})
+	}
+}
+
+func TestVerifyGoogleIDToken_Misconfigured(t *testing.T) {
+	key := stubGoogle(t)
+	configs.GoogleClientID = ""
+
+	_, err := VerifyGoogleIDToken(context.Background(), signGoogle(t, key, testKID, nil))
+	if !errors.Is(err, ErrGoogleMisconfigured) {
+		t.Fatalf("expected ErrGoogleMisconfigured, got %v", err)
+	}
+}
+
+func TestCheckGoogleLink(t *testing.T) {
+	me := uuid.New()
+
+	cases := []struct {
+		name       string
+		user       *userModel.UserModel
+		other      *userModel.UserModel
+		wantLinked bool
+		wantErr    error
+	}{
+		{"belum terhubung", &userModel.UserModel{ID: me}, nil, false, nil},
+		{"sudah terhubung ke sub yang sama", &userModel.UserModel{ID: me, GoogleID: ptrStr("g-1")}, nil, true, nil},
+		{"terhubung ke akun Google lain", &userModel.UserModel{ID: me, GoogleID: ptrStr("g-2")}, nil, false, ErrGoogleAlreadyLinked},
+		{"akun Google dipakai user lain", &userModel.UserModel{ID: me}, &userModel.UserModel{ID: uuid.New(), GoogleID: ptrStr("g-1")}, false, ErrGoogleLinkedElsewhere},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			linked, err := checkGoogleLink(tc.user, "g-1", tc.other)
+			if !errors.Is(err, tc.wantErr) || linked != tc.wantLinked {
+				t.Fatalf("got (%v, %v), want (%v, %v)", linked, err, tc.wantLinked, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckGoogleUnlink(t *testing.T) {
+	cases := []struct {
+		name       string
+		user       *userModel.UserModel
+		wantLinked bool
+		wantErr    error
+	}{
+		{"tidak terhubung", &userModel.UserModel{}, false, nil},
+		{"satu-satunya metode login", &userModel.UserModel{GoogleID: ptrStr("g-1")}, true, ErrGoogleOnlyLogin},
+		{"punya password", &userModel.UserModel{GoogleID: ptrStr("g-1"), Password: ptrStr("$2a$10$hash")}, true, nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			linked, err := checkGoogleUnlink(tc.user)
+			if !errors.Is(err, tc.wantErr) || linked != tc.wantLinked {
+				t.Fatalf("got (%v, %v), want (%v, %v)", linked, err, tc.wantLinked, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,774 @@
+// internals/features/users/auth/service/mfa_service.go
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"madinahsalam_backend/internals/constants"
+	authHelper "madinahsalam_backend/internals/features/users/auth/helper"
+	authModel "madinahsalam_backend/internals/features/users/auth/model"
+	authRepo "madinahsalam_backend/internals/features/users/auth/repository"
+	userModel "madinahsalam_backend/internals/features/users/users/model"
+	helpers "madinahsalam_backend/internals/helpers"
+	helpersAuth "madinahsalam_backend/internals/helpers/auth"
+)
+
+/* =========================================================
+   TWO-FACTOR (TOTP) — step-up saat login + enrollment
+
+   Login (password / Google) lolos faktor pertama:
+     - user punya TOTP aktif         → {mfa_required: true, mfa_token}
+     - wajib (kebijakan) tapi belum  → {mfa_enrollment_required: true, mfa_token}
+   POST /api/auth/login/2fa                  {mfa_token, code | recovery_code}
+   POST /api/auth/login/2fa/setup            {mfa_token}        → secret, otpauth_uri, recovery_codes
+   POST /api/auth/login/2fa/setup/confirm    {mfa_token, code}  → token login
+
+   User login:
+   GET  /api/auth/me/2fa
+   POST /api/auth/me/2fa/setup | /setup/confirm | /recovery-codes | /disable
+
+   Admin sekolah:
+   GET/PUT /api/a/mfa-policy        {required_roles: [...]}
+   POST    /api/a/mfa-policy/users/:user_id/reset
+========================================================= */
+
+const (
+	mfaTokenTTL       = 5 * time.Minute
+	mfaMaxFailed      = 5
+	mfaLockDuration   = 5 * time.Minute
+	recoveryCodeCount = 10
+
+	mfaPurposeVerify = "verify"
+	mfaPurposeEnroll = "enroll"
+)
+
+var (
+	ErrMFATokenInvalid   = fiber.NewError(fiber.StatusUnauthorized, "mfa_token tidak valid atau kedaluwarsa, silakan login ulang")
+	ErrMFACodeInvalid    = fiber.NewError(fiber.StatusUnauthorized, "Kode verifikasi salah")
+	ErrMFALocked         = fiber.NewError(fiber.StatusTooManyRequests, "Terlalu banyak percobaan kode, coba lagi beberapa menit lagi")
+	ErrMFANotEnabled     = fiber.NewError(fiber.StatusConflict, "Verifikasi dua langkah belum aktif")
+	ErrMFAAlreadyEnabled = fiber.NewError(fiber.StatusConflict, "Verifikasi dua langkah sudah aktif")
+	ErrMFANoPendingSetup = fiber.NewError(fiber.StatusConflict, "Belum ada setup 2FA yang berjalan, mulai dari /2fa/setup")
+	ErrMFARequired       = fiber.NewError(fiber.StatusForbidden, "2FA wajib untuk role Anda dan tidak bisa dinonaktifkan")
+	ErrMFAInvalidRole    = fiber.NewError(fiber.StatusBadRequest, "required_roles berisi role yang tidak didukung")
+	ErrMFAResetForeign   = fiber.NewError(fiber.StatusForbidden, "User juga staf di sekolah/yayasan lain; reset 2FA hanya oleh owner atau admin yayasan terkait")
+)
+
+/* =========================================================
+   mfa_token: JWT singkat, ditandatangani kunci TERPISAH dari access token
+   (middleware AuthJWT tidak membedakan typ, jadi jangan pakai JWT_SECRET mentah)
+========================================================= */
+
+func mfaTokenSecret() ([]byte, error) {
+	s, err := getJWTSecret()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(s + ":mfa-challenge"), nil
+}
+
+func signMFAToken(userID uuid.UUID, slug, purpose string, now time.Time) (string, error) {
+	key, err := mfaTokenSecret()
+	if err != nil {
+		return "", err
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"typ":     "mfa",
+		"id":      userID.String(),
+		"slug":    slug,
+		"purpose": purpose,
+		"iat":     now.Unix(),
+		"exp":     now.Add(mfaTokenTTL).Unix(),
+	}).SignedString(key)
+}
+
+func parseMFAToken(raw, purpose string) (uuid.UUID, string, error) {
+	key, err := mfaTokenSecret()
+	if err != nil {
+		return uuid.Nil, "", err
+	}
+	tok, err := jwt.Parse(strings.TrimSpace(raw), func(t *jwt.Token) (any, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, ErrMFATokenInvalid
+		}
+		return key, nil
+	})
+	if err != nil || !tok.Valid {
+		return uuid.Nil, "", ErrMFATokenInvalid
+	}
+	claims, _ := tok.Claims.(jwt.MapClaims)
+	if claims["typ"] != "mfa" || claims["purpose"] != purpose {
+		return uuid.Nil, "", ErrMFATokenInvalid
+	}
+	idStr, _ := claims["id"].(string)
+	uid, err := uuid.Parse(idStr)
+	if err != nil {
+		return uuid.Nil, "", ErrMFATokenInvalid
+	}
+	slug, _ := claims["slug"].(string)
+	return uid, slug, nil
+}
+
+/* =========================================================
+   Status & kebijakan
+========================================================= */
+
+type mfaState struct {
+	Enabled  bool
+	Required bool
+	TOTP     *authModel.UserTOTPModel
+}
+
+// Role global yang wajib 2FA di semua sekolah (mis. "owner"), dari ENV.
+func globalRequiredRoles() pq.StringArray {
+	out := pq.StringArray{}
+	for _, r := range strings.Split(os.Getenv("MFA_REQUIRED_GLOBAL_ROLES"), ",") {
+		if r = constants.NormalizeRole(r); r != "" && constants.ContainsRole(constants.TwoFactorEligibleRoles, r) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func loadMFAState(ctx context.Context, db *gorm.DB, userID uuid.UUID) (mfaState, error) {
+	var st mfaState
+
+	var t authModel.UserTOTPModel
+	err := db.WithContext(ctx).Where("user_id = ?", userID).Take(&t).Error
+	switch {
+	case err == nil:
+		st.TOTP = &t
+		st.Enabled = t.EnabledAt != nil
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return st, err
+	}
+
+	// wajib bila salah satu role user di sekolah mana pun tercantum di kebijakan sekolah tsb
+	if err := db.WithContext(ctx).Raw(`
+		SELECT EXISTS (
+		  SELECT 1 FROM user_roles ur
+		  JOIN roles r ON r.role_id = ur.role_id
+		  JOIN school_mfa_policies p ON p.school_id = ur.school_id
+		  WHERE ur.user_id = ? AND ur.deleted_at IS NULL
+		    AND r.role_name = ANY(p.required_roles)
+		) OR EXISTS (
+		  SELECT 1 FROM user_roles ur
+		  JOIN roles r ON r.role_id = ur.role_id
+		  WHERE ur.user_id = ? AND ur.school_id IS NULL AND ur.deleted_at IS NULL
+		    AND r.role_name = ANY(?::text[])
+		)`, userID, userID, globalRequiredRoles()).Scan(&st.Required).Error; err != nil {
+		return st, err
+	}
+	return st, nil
+}
+
+// startMFAChallenge: dipanggil sebelum token diterbitkan.
+// handled=true → respons challenge sudah ditulis (err = hasil JsonOK/JsonError).
+func startMFAChallenge(c *fiber.Ctx, db *gorm.DB, user *userModel.UserModel, slug string) (bool, error) {
+	st, err := loadMFAState(c.Context(), db, user.ID)
+	if err != nil {
+		log.Printf("[mfa] load state user=%s: %v", user.ID, err)
+		return true, helpers.JsonError(c, fiber.StatusInternalServerError, "Gagal memeriksa status 2FA")
+	}
+	if !st.Enabled && !st.Required {
+		return false, nil
+	}
+
+	purpose := mfaPurposeVerify
+	if !st.Enabled {
+		purpose = mfaPurposeEnroll
+	}
+	now := time.Now()
+	tok, err := signMFAToken(user.ID, slug, purpose, now)
+	if err != nil {
+		return true, writeFiberErr(c, err)
+	}
+
+	if purpose == mfaPurposeEnroll {
+		return true, helpers.JsonOK(c, "Verifikasi dua langkah wajib diaktifkan untuk akun ini", fiber.Map{
+			"mfa_enrollment_required": true,
+			"mfa_token":               tok,
+			"mfa_expires_in":          int(mfaTokenTTL.Seconds()),
+		})
+	}
+	return true, helpers.JsonOK(c, "Masukkan kode verifikasi dua langkah", fiber.Map{
+		"mfa_required":   true,
+		"mfa_token":      tok,
+		"mfa_expires_in": int(mfaTokenTTL.Seconds()),
+	})
+}
+
+/* =========================================================
+   Core: enrollment & verifikasi
+========================================================= */
+
+type mfaSetupResult struct {
+	Secret        string   `json:"secret"`
+	OTPAuthURI    string   `json:"otpauth_uri"`
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+func mfaIssuer() string {
+	if v := strings.TrimSpace(os.Getenv("MFA_ISSUER")); v != "" {
+		return v
+	}
+	return "Madinah Salam"
+}
+
+// beginEnrollment: secret baru (belum aktif) + recovery codes baru.
+// Recovery codes baru berlaku setelah enrollment dikonfirmasi.
+func beginEnrollment(ctx context.Context, db *gorm.DB, user *userModel.UserModel) (*mfaSetupResult, error) {
+	secret, err := authHelper.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	enc, err := authHelper.EncryptTOTPSecret(secret)
+	if err != nil {
+		return nil, err
+	}
+	codes, err := authHelper.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var cur authModel.UserTOTPModel
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ?", user.ID).Take(&cur).Error
+		if err == nil && cur.EnabledAt != nil {
+			return ErrMFAAlreadyEnabled
+		}
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		now := time.Now()
+		if err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.Assignments(map[string]any{
+				"secret_enc":      enc,
+				"enabled_at":      nil,
+				"last_used_step":  0,
+				"failed_attempts": 0,
+				"locked_until":    nil,
+				"updated_at":      now,
+			}),
+		}).Create(&authModel.UserTOTPModel{UserID: user.ID, SecretEnc: enc}).Error; err != nil {
+			return err
+		}
+		return replaceRecoveryCodes(tx, user.ID, codes)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	account := user.Email
+	if strings.TrimSpace(account) == "" {
+		account = user.UserName
+	}
+	return &mfaSetupResult{
+		Secret:        secret,
+		OTPAuthURI:    authHelper.TOTPProvisioningURI(mfaIssuer(), account, secret),
+		RecoveryCodes: codes,
+	}, nil
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, userID uuid.UUID, codes []string) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&authModel.UserRecoveryCodeModel{}).Error; err != nil {
+		return err
+	}
+	rows := make([]authModel.UserRecoveryCodeModel, 0, len(codes))
+	for _, c := range codes {
+		rows = append(rows, authModel.UserRecoveryCodeModel{UserID: userID, CodeHash: authHelper.HashRecoveryCode(c)})
+	}
+	return tx.Create(&rows).Error
+}
+
+// confirmEnrollment: kode pertama dari aplikasi authenticator → aktifkan.
+func confirmEnrollment(ctx context.Context, db *gorm.DB, userID uuid.UUID, code string) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var t authModel.UserTOTPModel
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ?", userID).Take(&t).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrMFANoPendingSetup
+		}
+		if err != nil {
+			return err
+		}
+		if t.EnabledAt != nil {
+			return ErrMFAAlreadyEnabled
+		}
+		secret, err := authHelper.DecryptTOTPSecret(t.SecretEnc)
+		if err != nil {
+			return err
+		}
+		step, ok := authHelper.VerifyTOTP(secret, code, time.Now(), 0)
+		if !ok {
+			return ErrMFACodeInvalid
+		}
+		return tx.Model(&authModel.UserTOTPModel{}).
+			Where("user_id = ?", userID).
+			Updates(map[string]any{
+				"enabled_at":      time.Now(),
+				"last_used_step":  step,
+				"failed_attempts": 0,
+				"updated_at":      time.Now(),
+			}).Error
+	})
+}
+
+// verifySecondFactor: kode TOTP (anti replay) ATAU recovery code sekali pakai.
+// Percobaan gagal dicatat (commit) lalu dikunci sementara setelah mfaMaxFailed kali.
+func verifySecondFactor(ctx context.Context, db *gorm.DB, userID uuid.UUID, code, recovery string) error {
+	var verr error
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var t authModel.UserTOTPModel
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ? AND enabled_at IS NOT NULL", userID).Take(&t).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			verr = ErrMFANotEnabled
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		if t.LockedUntil != nil && t.LockedUntil.After(now) {
+			verr = ErrMFALocked
+			return nil
+		}
+
+		ok := false
+		upd := map[string]any{"updated_at": now}
+		if strings.TrimSpace(recovery) != "" {
+			res := tx.Model(&authModel.UserRecoveryCodeModel{}).
+				Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, authHelper.HashRecoveryCode(recovery)).
+				Update("used_at", now)
+			if res.Error != nil {
+				return res.Error
+			}
+			ok = res.RowsAffected == 1
+		} else {
+			secret, err := authHelper.DecryptTOTPSecret(t.SecretEnc)
+			if err != nil {
+				return err
+			}
+			var step int64
+			if step, ok = authHelper.VerifyTOTP(secret, code, now, t.LastUsedStep); ok {
+				upd["last_used_step"] = step
+			}
+		}
+
+		if ok {
+			upd["failed_attempts"] = 0
+			upd["locked_until"] = nil
+		} else {
+			verr = ErrMFACodeInvalid
+			upd["failed_attempts"] = t.FailedAttempts + 1
+			if t.FailedAttempts+1 >= mfaMaxFailed {
+				upd["failed_attempts"] = 0
+				upd["locked_until"] = now.Add(mfaLockDuration)
+				verr = ErrMFALocked
+			}
+		}
+		return tx.Model(&authModel.UserTOTPModel{}).Where("user_id = ?", userID).Updates(upd).Error
+	})
+	if err != nil {
+		return err
+	}
+	return verr
+}
+
+func disableMFA(ctx context.Context, db *gorm.DB, userID uuid.UUID) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&authModel.UserRecoveryCodeModel{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&authModel.UserTOTPModel{}).Error
+	})
+}
+
+func countUnusedRecoveryCodes(ctx context.Context, db *gorm.DB, userID uuid.UUID) int64 {
+	var n int64
+	db.WithContext(ctx).Model(&authModel.UserRecoveryCodeModel{}).
+		Where("user_id = ? AND used_at IS NULL", userID).Count(&n)
+	return n
+}
+
+/* =========================================================
+   LOGIN step-up (pakai mfa_token, belum punya access token)
+========================================================= */
+
+type mfaLoginInput struct {
+	MFAToken     string `json:"mfa_token"`
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+func loadActiveUser(db *gorm.DB, userID uuid.UUID) (*userModel.UserModel, error) {
+	u, err := authRepo.FindUserByID(db, userID)
+	if err != nil {
+		return nil, ErrMFATokenInvalid
+	}
+	if !u.IsActive {
+		return nil, fiber.NewError(fiber.StatusForbidden, "Akun Anda telah dinonaktifkan. Hubungi admin.")
+	}
+	return u, nil
+}
+
+// POST /api/auth/login/2fa
+func VerifyLoginMFA(db *gorm.DB, c *fiber.Ctx) error {
+	var in mfaLoginInput
+	if err := c.BodyParser(&in); err != nil {
+		return helpers.JsonError(c, fiber.StatusBadRequest, "Invalid input format")
+	}
+	if strings.TrimSpace(in.Code) == "" && strings.TrimSpace(in.RecoveryCode) == "" {
+		return helpers.JsonError(c, fiber.StatusBadRequest, "code atau recovery_code wajib diisi")
+	}
+	userID, slug, err := parseMFAToken(in.MFAToken, mfaPurposeVerify)
+	if err != nil {
+		return writeFiberErr(c, err)
+	}
+	user, err := loadActiveUser(db, userID)
+	if err != nil {
+		return writeFiberErr(c, err)
+	}
+	if err := verifySecondFactor(c.Context(), db, userID, in.Code, in.RecoveryCode); err != nil {
+		return writeFiberErr(c, err)
+	}
+	return issueTokensForSlug(c, db, user, slug)
+}
+
+// POST /api/auth/login/2fa/setup (enrollment wajib saat login)
+func BeginLoginMFASetup(db *gorm.DB, c *fiber.Ctx) error {
+	var in mfaLoginInput
+	if err := c.BodyParser(&in); err != nil {
+		return helpers.JsonError(c, fiber.StatusBadRequest, "Invalid input format")
+	}
+	userID, _, err := parseMFAToken(in.MFAToken, mfaPurposeEnroll)
+	if err != nil {
+		return writeFiberErr(c, err)
+	}
+	user, err := loadActiveUser(db, userID)
+	if err != nil {
+		return writeFiberErr(c, err)
+	}
+	res, err := beginEnrollment(c.Context(), db, user)
+	if err != nil {
+		return writeFiberErr(c, err)
+	}
+	return helpers.JsonOK(c, "Pindai QR lalu konfirmasi dengan kode dari aplikasi authenticator", res)
+}
+
+// POST /api/auth/login/2fa/setup/confirm → aktifkan lalu terbitkan token
+func ConfirmLoginMFASetup(db *gorm.DB, c *fiber.Ctx) error {
+	var in mfaLoginInput
+	if err := c.BodyParser(&in); err != nil {
+		return helpers.JsonError(c, fiber.StatusBadRequest, "Invalid input format")
+	}
+	userID, slug, err := parseMFAToken(in.MFAToken, mfaPurposeEnroll)
+	if err != nil {
+		return writeFiberErr(c, err)
+	}
+	user, err := loadActiveUser(db, userID)
+	if err != nil {
+		return writeFiberErr(c, err)
+	}
+	if err := confirmEnrollment(c.Context(), db, userID, in.Code); err != nil {
+		return writeFiberErr(c, err)
+	}
+	return issueTokensForSlug(c, db, user, slug)
+}
+
+/* =========================================================
+   USER (sudah login): /me/2fa
+========================================================= */
+
+// GET /api/auth/me/2fa
+func GetMyMFAStatus(db *gorm.DB, c *fiber.Ctx) error {
+	userID, err := userIDFromLocals(c)
+	if err != nil {
+		return writeFiberErr(c, err)
+	}
+	st, err := loadMFAState(c.Context(), db, userID)
+	if err != nil {
+		return helpers.JsonError(c, fiber.StatusInternalServerError, "Gagal memeriksa status 2FA")
+	}
+	out := fiber.Map{
+		"enabled":       st.Enabled,
+		"required":      st.Required,
+		"pending_setup": st.TOTP != nil && !st.Enabled,
+	}
+	if st.Enabled {
+		out["enabled_at"] = st.TOTP.EnabledAt
+		out["recovery_codes_remaining"] = countUnusedRecoveryCodes(c.Context(), db, userID)
+	}
+	return helpers.JsonOK(c, "OK", out)
+}
+
+// POST /api/auth/me/2fa/setup
+func BeginMyMFASetup(db *gorm.DB, c *fiber.Ctx) error {
+	userID, err := userIDFromLocals(c)
+	if err != nil {
+		return writeFiberErr(c, err)
+	}
+	user, err := authRepo.FindUserByID(db, userID)
+	if err != nil {
+		return helpers.JsonError(c, fiber.StatusInternalServerError, "Gagal mengambil data user")
+	}
+	res, err := beginEnrollment(c.Context(), db, user)
+	if err != nil {
+		return writeFiberErr(c, err)
+	}
+	return helpers.JsonOK(c, "Pindai QR lalu konfirmasi dengan kode dari aplikasi authenticator", res)
+}
+
+// POST /api/auth/me/2fa/setup/confirm {code}
+func ConfirmMyMFASetup(db *gorm.DB, c *fiber.Ctx) error {
+	userID, err := userIDFromLocals(c)
+	if err != nil {
+		return writeFiberErr(c, err)
+	}
+	var in mfaLoginInput
+	if err := c.BodyParser(&in); err != nil {
+		return helpers.JsonError(c, fiber.StatusBadRequest, "Invalid input format")
+	}
+	if err := confirmEnrollment(c.Context(), db, userID, in.Code); err != nil {
+		return writeFiberErr(c, err)
+	}
+	return helpers.JsonOK(c, "Verifikasi dua langkah aktif", fiber.Map{"enabled": true})
+}
+
+// POST /api/auth/me/2fa/recovery-codes {code} → set baru, set lama hangus
+func RegenerateMyRecoveryCodes(db *gorm.DB, c *fiber.Ctx) error {
+	userID, err := userIDFromLocals(c)
+	if err != nil {
+		return writeFiberErr(c, err)
+	}
+	var in mfaLoginInput
+	if err := c.BodyParser(&in); err != nil {
+		return helpers.JsonError(c, fiber.StatusBadRequest, "Invalid input format")
+	}
+	if err := verifySecondFactor(c.Context(), db, userID, in.Code, ""); err != nil {
+		return writeFiberErr(c, err)
+	}
+	codes, err := authHelper.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return helpers.JsonError(c, fiber.StatusInternalServerError, "Gagal membuat recovery code")
+	}
+	if err := db.WithContext(c.Context()).Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, userID, codes)
+	}); err != nil {
+		return helpers.JsonError(c, fiber.StatusInternalServerError, "Gagal menyimpan recovery code")
+	}
+	return helpers.JsonOK(c, "Recovery code baru dibuat; simpan di tempat aman", fiber.Map{"recovery_codes": codes})
+}
+
+// POST /api/auth/me/2fa/disable {code | recovery_code}
+func DisableMyMFA(db *gorm.DB, c *fiber.Ctx) error {
+	userID, err := userIDFromLocals(c)
+	if err != nil {
+		return writeFiberErr(c, err)
+	}
+	var in mfaLoginInput
+	if err := c.BodyParser(&in); err != nil {
+		return helpers.JsonError(c, fiber.StatusBadRequest, "Invalid input format")
+	}
+	st, err := loadMFAState(c.Context(), db, userID)
+	if err != nil {
+		return helpers.JsonError(c, fiber.StatusInternalServerError, "Gagal memeriksa status 2FA")
+	}
+	if st.Required {
+		return writeFiberErr(c, ErrMFARequired)
+	}
+	if err := verifySecondFactor(c.Context(), db, userID, in.Code, in.RecoveryCode); err != nil {
+		return writeFiberErr(c, err)
+	}
+	if err := disableMFA(c.Context(), db, userID); err != nil {
+		return helpers.JsonError(c, fiber.StatusInternalServerError, "Gagal menonaktifkan 2FA")
+	}
+	return helpers.JsonOK(c, "Verifikasi dua langkah dinonaktifkan", fiber.Map{"enabled": false})
+}
+
+/* =========================================================
+   ADMIN sekolah: kebijakan & reset
+========================================================= */
+
+func adminSchool(c *fiber.Ctx) (uuid.UUID, error) {
+	schoolID, err := helpersAuth.ResolveSchoolIDFromContext(c)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if err := helpersAuth.EnsureDKMSchool(c, schoolID); err != nil {
+		return uuid.Nil, err
+	}
+	return schoolID, nil
+}
+
+// GET /api/a/mfa-policy
+func GetSchoolMFAPolicy(db *gorm.DB, c *fiber.Ctx) error {
+	schoolID, err := adminSchool(c)
+	if err != nil {
+		return writeFiberErr(c, err)
+	}
+	var p authModel.SchoolMFAPolicyModel
+	err = db.WithContext(c.Context()).Where("school_id = ?", schoolID).Take(&p).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return helpers.JsonError(c, fiber.StatusInternalServerError, "Gagal mengambil kebijakan 2FA")
+	}
+	roles := []string(p.RequiredRoles)
+	if roles == nil {
+		roles = []string{}
+	}
+	return helpers.JsonOK(c, "OK", fiber.Map{
+		"school_id":       schoolID,
+		"required_roles":  roles,
+		"available_roles": constants.TwoFactorEligibleRoles,
+		"global_roles":    globalRequiredRoles(),
+		"updated_at":      p.UpdatedAt,
+	})
+}
+
+// PUT /api/a/mfa-policy {required_roles: ["dkm","treasurer"]}  (kosong = tidak wajib)
+func UpdateSchoolMFAPolicy(db *gorm.DB, c *fiber.Ctx) error {
+	schoolID, err := adminSchool(c)
+	if err != nil {
+		return writeFiberErr(c, err)
+	}
+	var in struct {
+		RequiredRoles []string `json:"required_roles"`
+	}
+	if err := c.BodyParser(&in); err != nil {
+		return helpers.JsonError(c, fiber.StatusBadRequest, "Invalid input format")
+	}
+	roles := pq.StringArray{}
+	seen := map[string]bool{}
+	for _, r := range in.RequiredRoles {
+		r = constants.NormalizeRole(r)
+		if !constants.ContainsRole(constants.TwoFactorEligibleRoles, r) {
+			return writeFiberErr(c, ErrMFAInvalidRole)
+		}
+		if !seen[r] {
+			seen[r] = true
+			roles = append(roles, r)
+		}
+	}
+
+	var by *uuid.UUID
+	if uid, err := helpersAuth.GetUserIDFromToken(c); err == nil && uid != uuid.Nil {
+		by = &uid
+	}
+	row := authModel.SchoolMFAPolicyModel{SchoolID: schoolID, RequiredRoles: roles, UpdatedBy: by}
+	if err := db.WithContext(c.Context()).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "school_id"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"required_roles": roles,
+			"updated_by":     by,
+			"updated_at":     time.Now(),
+		}),
+	}).Create(&row).Error; err != nil {
+		return helpers.JsonError(c, fiber.StatusInternalServerError, "Gagal menyimpan kebijakan 2FA")
+	}
+	return helpers.JsonUpdated(c, "Kebijakan 2FA diperbarui", fiber.Map{
+		"school_id":      schoolID,
+		"required_roles": roles,
+	})
+}
+
+// foreignStaffSchool: sekolah lain tempat target punya role staf (+ yayasan pemiliknya).
+type foreignStaffSchool struct {
+	SchoolID  uuid.UUID  `gorm:"column:school_id"`
+	YayasanID *uuid.UUID `gorm:"column:school_yayasan_id"`
+}
+
+// checkMFAResetScope: 2FA berlaku lintas sekolah, jadi admin sekolah hanya boleh reset
+// bila seluruh role staf target ada di sekolahnya. Selebihnya hanya owner, atau admin
+// yayasan yang menaungi semua sekolah/yayasan lain milik target.
+func checkMFAResetScope(foreign []foreignStaffSchool, targetYayasans []uuid.UUID, callerOwner bool, callerYayasans map[uuid.UUID]bool) error {
+	if callerOwner {
+		return nil
+	}
+	for _, f := range foreign {
+		if f.YayasanID == nil || !callerYayasans[*f.YayasanID] {
+			return ErrMFAResetForeign
+		}
+	}
+	for _, y := range targetYayasans {
+		if !callerYayasans[y] {
+			return ErrMFAResetForeign
+		}
+	}
+	return nil
+}
+
+func loadMFAResetScope(ctx context.Context, db *gorm.DB, schoolID, targetID, callerID uuid.UUID) (
+	foreign []foreignStaffSchool, targetYayasans []uuid.UUID, callerYayasans map[uuid.UUID]bool, err error) {
+
+	q := db.WithContext(ctx)
+	if err = q.Raw(`
+		SELECT DISTINCT ur.school_id, s.school_yayasan_id
+		  FROM user_roles ur
+		  JOIN roles r ON r.role_id = ur.role_id
+		  LEFT JOIN schools s ON s.school_id = ur.school_id
+		 WHERE ur.user_id = ?
+		   AND ur.school_id IS NOT NULL
+		   AND ur.school_id <> ?
+		   AND ur.deleted_at IS NULL
+		   AND r.role_name NOT IN (?, ?)
+	`, targetID, schoolID, constants.RoleStudent, constants.RoleUser).Scan(&foreign).Error; err != nil {
+		return
+	}
+	yayasansOf := func(uid uuid.UUID) ([]uuid.UUID, error) {
+		var ids []uuid.UUID
+		err := q.Table("yayasan_admins").
+			Where("yayasan_admin_user_id = ? AND yayasan_admin_deleted_at IS NULL", uid).
+			Pluck("yayasan_admin_yayasan_id", &ids).Error
+		return ids, err
+	}
+	if targetYayasans, err = yayasansOf(targetID); err != nil {
+		return
+	}
+	callerYayasans = map[uuid.UUID]bool{}
+	if callerID == uuid.Nil {
+		return
+	}
+	ids, err := yayasansOf(callerID)
+	for _, id := range ids {
+		callerYayasans[id] = true
+	}
+	return
+}
+
+// POST /api/a/mfa-policy/users/:user_id/reset → HP hilang; user enroll ulang saat login berikutnya
+func AdminResetUserMFA(db *gorm.DB, c *fiber.Ctx) error {
+	schoolID, targetID, err := resolveAdminTarget(c, db)
+	if err != nil {
+		return writeSessionErr(c, err)
+	}
+	callerID, _ := helpersAuth.GetUserIDFromToken(c)
+	foreign, targetYayasans, callerYayasans, err := loadMFAResetScope(c.Context(), db, schoolID, targetID, callerID)
+	if err != nil {
+		return helpers.JsonError(c, fiber.StatusInternalServerError, "Gagal memeriksa cakupan role user")
+	}
+	if err := checkMFAResetScope(foreign, targetYayasans, helpersAuth.IsOwner(c), callerYayasans); err != nil {
+		return writeSessionErr(c, err)
+	}
+	if err := disableMFA(c.Context(), db, targetID); err != nil {
+		return helpers.JsonError(c, fiber.StatusInternalServerError, "Gagal mereset 2FA")
+	}
+	log.Printf("[mfa] school=%s reset 2FA user=%s", schoolID, targetID)
+	return helpers.JsonOK(c, "2FA user direset", fiber.Map{"user_id": targetID})
+}
@@ -0,0 +1,41 @@
+// internals/features/users/auth/service/mfa_service_test.go
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestCheckMFAResetScope(t *testing.T) {
+	yayasanA, yayasanB := uuid.New(), uuid.New()
+	otherSchool := foreignStaffSchool{SchoolID: uuid.New(), YayasanID: &yayasanA}
+	independent := foreignStaffSchool{SchoolID: uuid.New()}
+
+	cases := []struct {
+		name           string
+		foreign        []foreignStaffSchool
+		targetYayasans []uuid.UUID
+		owner          bool
+		callerYayasans map[uuid.UUID]bool
+		wantErr        error
+	}{
+		{"staf hanya di sekolah sendiri", nil, nil, false, nil, nil},
+		{"staf di sekolah lain → DKM ditolak", []foreignStaffSchool{otherSchool}, nil, false, nil, ErrMFAResetForeign},
+		{"staf di sekolah tanpa yayasan → DKM ditolak", []foreignStaffSchool{independent}, nil, false, map[uuid.UUID]bool{yayasanA: true}, ErrMFAResetForeign},
+		{"staf di sekolah lain → owner boleh", []foreignStaffSchool{otherSchool, independent}, []uuid.UUID{yayasanB}, true, nil, nil},
+		{"admin yayasan pemilik sekolah lain boleh", []foreignStaffSchool{otherSchool}, nil, false, map[uuid.UUID]bool{yayasanA: true}, nil},
+		{"admin yayasan lain ditolak", []foreignStaffSchool{otherSchool}, nil, false, map[uuid.UUID]bool{yayasanB: true}, ErrMFAResetForeign},
+		{"target admin yayasan lain → DKM ditolak", nil, []uuid.UUID{yayasanB}, false, nil, ErrMFAResetForeign},
+		{"target admin yayasan yang sama boleh", nil, []uuid.UUID{yayasanA}, false, map[uuid.UUID]bool{yayasanA: true}, nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkMFAResetScope(tc.foreign, tc.targetYayasans, tc.owner, tc.callerYayasans)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("got %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,327 @@
+// internals/features/users/auth/service/session_service.go
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	authHelper "madinahsalam_backend/internals/features/users/auth/helper"
+	authModel "madinahsalam_backend/internals/features/users/auth/model"
+	helpers "madinahsalam_backend/internals/helpers"
+	helpersAuth "madinahsalam_backend/internals/helpers/auth"
+)
+
+/* =========================================================
+   SESI & PERANGKAT
+   - 1 baris refresh_tokens = 1 sesi (id = klaim "sid" di access token)
+   - revoke: revoked_at diisi + access token terakhir sesi di-blacklist,
+     jadi access token yang masih hidup langsung ditolak (tidak menunggu exp)
+   - AuthJWT juga menolak token yang klaim "sid"-nya menunjuk sesi revoked/terhapus,
+     dan rotasi refresh mem-blacklist access token sebelumnya
+
+   GET    /api/auth/me/sessions
+   DELETE /api/auth/me/sessions/:id
+   POST   /api/auth/me/sessions/revoke-others
+   GET    /api/a/user-sessions/:user_id          (admin sekolah)
+   POST   /api/a/user-sessions/:user_id/revoke   (admin sekolah; semua sesi user)
+========================================================= */
+
+const (
+	RevokeReasonSelf        = "self"
+	RevokeReasonOthers      = "self_others"
+	RevokeReasonAdmin       = "admin"
+	RevokeReasonAdminSingle = "admin_single"
+)
+
+var (
+	ErrSessionNotFound  = errors.New("sesi tidak ditemukan atau sudah berakhir")
+	ErrCurrentSessionNA = errors.New("sesi saat ini tidak dikenali (token lama); silakan login ulang lalu coba lagi")
+	ErrUserNotInSchool  = errors.New("user tidak terdaftar di sekolah ini")
+	ErrCannotKickOwner  = errors.New("sesi owner tidak bisa dicabut dari level sekolah")
+)
+
+type SessionItem struct {
+	ID         uuid.UUID             `json:"id"`
+	Device     authHelper.DeviceInfo `json:"device"`
+	UserAgent  *string               `json:"user_agent,omitempty"`
+	IP         *string               `json:"ip,omitempty"`
+	CreatedAt  time.Time             `json:"created_at"`
+	LastUsedAt time.Time             `json:"last_used_at"`
+	ExpiresAt  time.Time             `json:"expires_at"`
+	IsCurrent  bool                  `json:"is_current"`
+}
+
+func toSessionItem(m authModel.RefreshTokenModel, current uuid.UUID) SessionItem {
+	ua := ""
+	if m.UserAgent != nil {
+		ua = *m.UserAgent
+	}
+	last := m.CreatedAt
+	if m.LastUsedAt != nil {
+		last = *m.LastUsedAt
+	}
+	return SessionItem{
+		ID:         m.ID,
+		Device:     authHelper.ParseUserAgent(ua),
+		UserAgent:  m.UserAgent,
+		IP:         m.IP,
+		CreatedAt:  m.CreatedAt,
+		LastUsedAt: last,
+		ExpiresAt:  m.ExpiresAt,
+		IsCurrent:  current != uuid.Nil && m.ID == current,
+	}
+}
+
+/* =========================================================
+   Core (dipakai handler user & admin)
+========================================================= */
+
+func listActiveSessions(ctx context.Context, db *gorm.DB, userID uuid.UUID) ([]authModel.RefreshTokenModel, error) {
+	var rows []authModel.RefreshTokenModel
+	err := db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > NOW()", userID).
+		Order("COALESCE(last_used_at, created_at) DESC").
+		Find(&rows).Error
+	return rows, err
+}
+
+type revokeScope struct {
+	OnlyID   *uuid.UUID // revoke 1 sesi
+	ExceptID *uuid.UUID // revoke semua kecuali sesi ini
+}
+
+// revokeSessions: tandai revoked + blacklist access token terakhir tiap sesi (1 transaksi).
+func revokeSessions(ctx context.Context, db *gorm.DB, userID uuid.UUID, scope revokeScope, by *uuid.UUID, reason string) (int, error) {
+	type revoked struct {
+		ID              uuid.UUID
+		AccessTokenHash *string
+		AccessExpiresAt *time.Time
+	}
+	var rows []revoked
+
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		q := `
+			UPDATE refresh_tokens
+			SET revoked_at = NOW(), revoked_by = ?, revoke_reason = ?, updated_at = NOW()
+			WHERE user_id = ? AND revoked_at IS NULL AND expires_at > NOW()`
+		args := []any{by, reason, userID}
+		if scope.OnlyID != nil {
+			q += ` AND id = ?`
+			args = append(args, *scope.OnlyID)
+		}
+		if scope.ExceptID != nil {
+			q += ` AND id <> ?`
+			args = append(args, *scope.ExceptID)
+		}
+		q += ` RETURNING id, access_token_hash, access_expires_at`
+		if err := tx.Raw(q, args...).Scan(&rows).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for _, r := range rows {
+			// sesi sebelum kolom access_token_hash ada → access token habis sendiri (TTL)
+			if r.AccessTokenHash == nil || r.AccessExpiresAt == nil || !r.AccessExpiresAt.After(now) {
+				continue
+			}
+			if err := helpersAuth.AddHash(ctx, tx, *r.AccessTokenHash, *r.AccessExpiresAt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return len(rows), err
+}
+
+// currentSessionID: klaim "sid" dari access token (kosong untuk token lama).
+func currentSessionID(c *fiber.Ctx) uuid.UUID {
+	claims, ok := c.Locals("jwt_claims").(jwt.MapClaims)
+	if !ok {
+		return uuid.Nil
+	}
+	s, _ := claims["sid"].(string)
+	id, err := uuid.Parse(strings.TrimSpace(s))
+	if err != nil {
+		return uuid.Nil
+	}
+	return id
+}
+
+func writeSessionErr(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, ErrSessionNotFound), errors.Is(err, ErrUserNotInSchool):
+		return helpers.JsonError(c, fiber.StatusNotFound, err.Error())
+	case errors.Is(err, ErrCurrentSessionNA):
+		return helpers.JsonError(c, fiber.StatusConflict, err.Error())
+	case errors.Is(err, ErrCannotKickOwner):
+		return helpers.JsonError(c, fiber.StatusForbidden, err.Error())
+	}
+	var fe *fiber.Error
+	if errors.As(err, &fe) {
+		return helpers.JsonError(c, fe.Code, fe.Message)
+	}
+	return helpers.JsonError(c, fiber.StatusInternalServerError, err.Error())
+}
+
+/* =========================================================
+   USER: /me/sessions
+========================================================= */
+
+// GET /api/auth/me/sessions
+func ListMySessions(db *gorm.DB, c *fiber.Ctx) error {
+	userID, err := userIDFromLocals(c)
+	if err != nil {
+		return writeSessionErr(c, err)
+	}
+	rows, err := listActiveSessions(c.Context(), db, userID)
+	if err != nil {
+		return helpers.JsonError(c, fiber.StatusInternalServerError, "Gagal mengambil daftar sesi")
+	}
+	current := currentSessionID(c)
+	out := make([]SessionItem, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, toSessionItem(r, current))
+	}
+	return helpers.JsonOK(c, "OK", out)
+}
+
+// DELETE /api/auth/me/sessions/:id
+func RevokeMySession(db *gorm.DB, c *fiber.Ctx) error {
+	userID, err := userIDFromLocals(c)
+	if err != nil {
+		return writeSessionErr(c, err)
+	}
+	sid, err := uuid.Parse(strings.TrimSpace(c.Params("id")))
+	if err != nil {
+		return helpers.JsonError(c, fiber.StatusBadRequest, "id sesi tidak valid")
+	}
+	n, err := revokeSessions(c.Context(), db, userID, revokeScope{OnlyID: &sid}, &userID, RevokeReasonSelf)
+	if err != nil {
+		return writeSessionErr(c, err)
+	}
+	if n == 0 {
+		return writeSessionErr(c, ErrSessionNotFound)
+	}
+	return helpers.JsonOK(c, "Sesi dicabut", fiber.Map{
+		"revoked":    n,
+		"is_current": sid == currentSessionID(c),
+	})
+}
+
+// POST /api/auth/me/sessions/revoke-others
+func RevokeMyOtherSessions(db *gorm.DB, c *fiber.Ctx) error {
+	userID, err := userIDFromLocals(c)
+	if err != nil {
+		return writeSessionErr(c, err)
+	}
+	current := currentSessionID(c)
+	if current == uuid.Nil {
+		return writeSessionErr(c, ErrCurrentSessionNA)
+	}
+	n, err := revokeSessions(c.Context(), db, userID, revokeScope{ExceptID: &current}, &userID, RevokeReasonOthers)
+	if err != nil {
+		return writeSessionErr(c, err)
+	}
+	return helpers.JsonOK(c, "Sesi lain dicabut", fiber.Map{"revoked": n})
+}
+
+/* =========================================================
+   ADMIN: cabut semua sesi user di sekolahnya (akun diretas, guru keluar, dst)
+========================================================= */
+
+func resolveAdminTarget(c *fiber.Ctx, db *gorm.DB) (schoolID, targetID uuid.UUID, err error) {
+	schoolID, err = helpersAuth.ResolveSchoolIDFromContext(c)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	if err = helpersAuth.EnsureDKMSchool(c, schoolID); err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	targetID, err = uuid.Parse(strings.TrimSpace(c.Params("user_id")))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fiber.NewError(fiber.StatusBadRequest, "user_id tidak valid")
+	}
+
+	var flags struct {
+		InSchool bool
+		IsOwner  bool
+	}
+	if err = db.WithContext(c.Context()).Raw(`
+		SELECT
+		  EXISTS (SELECT 1 FROM user_roles ur
+		          WHERE ur.user_id = ? AND ur.school_id = ? AND ur.deleted_at IS NULL) AS in_school,
+		  EXISTS (SELECT 1 FROM user_roles ur JOIN roles r ON r.role_id = ur.role_id
+		          WHERE ur.user_id = ? AND ur.school_id IS NULL AND r.role_name = 'owner'
+		            AND ur.deleted_at IS NULL) AS is_owner
+	`, targetID, schoolID, targetID).Scan(&flags).Error; err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	if !flags.InSchool {
+		return uuid.Nil, uuid.Nil, ErrUserNotInSchool
+	}
+	if flags.IsOwner {
+		return uuid.Nil, uuid.Nil, ErrCannotKickOwner
+	}
+	return schoolID, targetID, nil
+}
+
+// GET /api/a/user-sessions/:user_id
+func AdminListUserSessions(db *gorm.DB, c *fiber.Ctx) error {
+	_, targetID, err := resolveAdminTarget(c, db)
+	if err != nil {
+		return writeSessionErr(c, err)
+	}
+	rows, err := listActiveSessions(c.Context(), db, targetID)
+	if err != nil {
+		return helpers.JsonError(c, fiber.StatusInternalServerError, "Gagal mengambil daftar sesi")
+	}
+	out := make([]SessionItem, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, toSessionItem(r, uuid.Nil))
+	}
+	return helpers.JsonOK(c, "OK", out)
+}
+
+// POST /api/a/user-sessions/:user_id/revoke   body opsional: {"session_id": "..."}
+func AdminRevokeUserSessions(db *gorm.DB, c *fiber.Ctx) error {
+	schoolID, targetID, err := resolveAdminTarget(c, db)
+	if err != nil {
+		return writeSessionErr(c, err)
+	}
+
+	var body struct {
+		SessionID *uuid.UUID `json:"session_id"`
+	}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&body); err != nil {
+			return helpers.JsonError(c, fiber.StatusBadRequest, "Payload tidak valid")
+		}
+	}
+
+	var by *uuid.UUID
+	if uid, err := helpersAuth.GetUserIDFromToken(c); err == nil && uid != uuid.Nil {
+		by = &uid
+	}
+	scope, reason := revokeScope{}, RevokeReasonAdmin
+	if body.SessionID != nil {
+		scope.OnlyID, reason = body.SessionID, RevokeReasonAdminSingle
+	}
+
+	n, err := revokeSessions(c.Context(), db, targetID, scope, by, reason)
+	if err != nil {
+		return writeSessionErr(c, err)
+	}
+	if body.SessionID != nil && n == 0 {
+		return writeSessionErr(c, ErrSessionNotFound)
+	}
+	log.Printf("[sessions] admin=%v school=%s revoked %d sesi user=%s", by, schoolID, n, targetID)
+	return helpers.JsonOK(c, "Sesi user dicabut", fiber.Map{"revoked": n})
+}
@@ -2,7 +2,8 @@
 package service
 
 import (
-	"log"
+	"context"
+	"errors"
 	"strings"
 	"time"
 
@@ -15,6 +16,7 @@ import (
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // ========================== REFRESH TOKEN ==========================
@@ -48,14 +50,17 @@ func RefreshToken(db *gorm.DB, c *fiber.Ctx) error {
 	}
 	userID, _ := uuid.Parse(sub)
 
-	// Pastikan hash refresh ada di DB
+	// Pastikan hash refresh ada di DB & sesinya masih aktif (belum di-revoke / expired)
 	h := computeRefreshHash(refreshCookie, refreshSecret)
-	var exists bool
-	if err := db.Raw(`SELECT EXISTS(SELECT 1 FROM refresh_tokens WHERE token = ?)`, h).Scan(&exists).Error; err != nil {
+	session, err := FindRefreshTokenByHashActive(db.WithContext(c.Context()), h)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return helpers.JsonError(c, fiber.StatusUnauthorized, "Sesi sudah berakhir atau dicabut")
+	}
+	if err != nil {
 		return helpers.JsonError(c, fiber.StatusInternalServerError, "DB error")
 	}
-	if !exists {
-		return helpers.JsonError(c, fiber.StatusUnauthorized, "Refresh token tidak dikenal")
+	if session.UserID != userID {
+		return helpers.JsonError(c, fiber.StatusUnauthorized, "Refresh token invalid")
 	}
 
 	// Ambil user + roles
@@ -71,11 +76,6 @@ func RefreshToken(db *gorm.DB, c *fiber.Ctx) error {
 		return helpers.JsonError(c, fiber.StatusInternalServerError, "Gagal ambil roles")
 	}
 
-	// ROTATE: hapus token lama
-	if err := deleteRefreshTokenByHash(c.Context(), db, refreshCookie); err != nil {
-		log.Printf("[refresh] delete old hash failed: %v", err)
-	}
-
 	// issue access & refresh baru (re-use logic tanpa set cookie access)
 	jwtSecret, _ := getJWTSecret()
 	now := nowUTC()
@@ -120,7 +120,9 @@ func RefreshToken(db *gorm.DB, c *fiber.Ctx) error {
 		now,
 	)
 
-	refreshClaims := buildRefreshClaims(userFull.ID, now)
+	// sesi tetap sama (id stabil) → rotasi in-place
+	accessClaims["sid"] = session.ID.String()
+	refreshClaims := buildRefreshClaims(userFull.ID, session.ID, now)
 
 	newAccess, err := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims).SignedString([]byte(jwtSecret))
 	if err != nil {
@@ -131,16 +133,23 @@ func RefreshToken(db *gorm.DB, c *fiber.Ctx) error {
 		return helpers.JsonError(c, fiber.StatusInternalServerError, "Gagal buat refresh baru")
 	}
 
-	// simpan hash refresh baru
-	if err := createRefreshTokenFast(db, &authModel.RefreshTokenModel{
-		UserID:    userFull.ID,
-		Token:     computeRefreshHash(newRefresh, refreshSecret),
-		ExpiresAt: now.Add(refreshTTLDefault),
-		UserAgent: strptr(c.Get("User-Agent")),
-		IP:        strptr(c.IP()),
-	}); err != nil {
+	// ROTATE: ganti hash refresh di baris sesi yang sama (hash lama langsung tidak berlaku)
+	rotated, err := rotateRefreshToken(c.Context(), db, session.ID, h, &authModel.RefreshTokenModel{
+		Token:           computeRefreshHash(newRefresh, refreshSecret),
+		ExpiresAt:       now.Add(refreshTTLDefault),
+		UserAgent:       strptr(c.Get("User-Agent")),
+		IP:              strptr(c.IP()),
+		AccessTokenHash: ptrStr(helpersAuth.HashAccessToken(newAccess, jwtSecret)),
+		AccessExpiresAt: ptrTime(now.Add(accessTTLDefault)),
+		LastUsedAt:      &now,
+	})
+	if err != nil {
 		return helpers.JsonError(c, fiber.StatusInternalServerError, "Gagal simpan refresh baru")
 	}
+	if !rotated {
+		// refresh paralel / replay token lama
+		return helpers.JsonError(c, fiber.StatusUnauthorized, "Refresh token sudah dipakai")
+	}
 
 	// set cookie refresh baru + XSRF baru
 	setAuthCookiesOnlyRefreshAndXsrf(c, newRefresh, now)
@@ -167,6 +176,50 @@ func FindRefreshTokenByHashActive(db *gorm.DB, hash []byte) (*authModel.RefreshT
 	return &rt, nil
 }
 
+// rotateRefreshToken: update hash & metadata sesi; false bila hash lama sudah berganti
+// (optimistic: WHERE token = hash lama). Access token sebelumnya ikut di-blacklist
+// supaya revoke sesi (yang hanya mem-blacklist access_token_hash terakhir) tetap menutup semuanya.
+func rotateRefreshToken(ctx context.Context, db *gorm.DB, sessionID uuid.UUID, oldHash []byte, next *authModel.RefreshTokenModel) (bool, error) {
+	rotated := false
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var prev authModel.RefreshTokenModel
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND token = ? AND revoked_at IS NULL", sessionID, oldHash).
+			Limit(1).
+			Find(&prev).Error; err != nil {
+			return err
+		}
+		if prev.ID == uuid.Nil {
+			return nil
+		}
+
+		res := tx.Model(&authModel.RefreshTokenModel{}).
+			Where("id = ? AND token = ? AND revoked_at IS NULL", sessionID, oldHash).
+			Updates(map[string]any{
+				"token":             next.Token,
+				"expires_at":        next.ExpiresAt,
+				"user_agent":        next.UserAgent,
+				"ip":                next.IP,
+				"access_token_hash": next.AccessTokenHash,
+				"access_expires_at": next.AccessExpiresAt,
+				"last_used_at":      next.LastUsedAt,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		rotated = res.RowsAffected > 0
+
+		if prev.AccessTokenHash == nil || prev.AccessExpiresAt == nil || !prev.AccessExpiresAt.After(time.Now()) {
+			return nil
+		}
+		return helpersAuth.AddHash(ctx, tx, *prev.AccessTokenHash, *prev.AccessExpiresAt)
+	})
+	return rotated, err
+}
+
+func ptrStr(s string) *string        { return &s }
+func ptrTime(t time.Time) *time.Time { return &t }
+
 // Revoke by ID
 func RevokeRefreshTokenByID(db *gorm.DB, id uuid.UUID) error {
 	now := time.Now().UTC()
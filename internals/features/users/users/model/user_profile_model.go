@@ -32,6 +32,7 @@ type UserProfileModel struct {
 	UserProfileDateOfBirth  *time.Time `gorm:"type:date;column:user_profile_date_of_birth" json:"user_profile_date_of_birth,omitempty"`
 	UserProfilePlaceOfBirth *string    `gorm:"size:100;column:user_profile_place_of_birth" json:"user_profile_place_of_birth,omitempty"`
 	UserProfileGender       *Gender    `gorm:"type:varchar(10);column:user_profile_gender" json:"user_profile_gender,omitempty"`
+	UserProfileNISN         *string    `gorm:"size:20;column:user_profile_nisn" json:"user_profile_nisn,omitempty"`
 	UserProfileLocation     *string    `gorm:"size:100;column:user_profile_location" json:"user_profile_location,omitempty"`
 	UserProfileCity         *string    `gorm:"size:100;column:user_profile_city" json:"user_profile_city,omitempty"`
 	UserProfileBio          *string    `gorm:"size:300;column:user_profile_bio" json:"user_profile_bio,omitempty"`
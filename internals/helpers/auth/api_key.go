@@ -0,0 +1,33 @@
+// file: internals/helpers/auth/api_key.go
+package helper
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+/* ============================================
+   Principal API key (diisi middleware AuthAPIKey)
+   ============================================ */
+
+// IsAPIKey: true bila request diautentikasi lewat API key sekolah.
+func IsAPIKey(c *fiber.Ctx) bool {
+	s, _ := c.Locals(LocAPIKeyID).(string)
+	return strings.TrimSpace(s) != ""
+}
+
+// GetAPIKeyPrefix: prefix key (aman untuk log/audit), "" bila bukan API key.
+func GetAPIKeyPrefix(c *fiber.Ctx) string {
+	s, _ := c.Locals(LocAPIKeyPrefix).(string)
+	return strings.TrimSpace(s)
+}
+
+// GetAPIKeyScopes: pola permission milik key; ok=false bila bukan API key.
+func GetAPIKeyScopes(c *fiber.Ctx) ([]string, bool) {
+	if !IsAPIKey(c) {
+		return nil, false
+	}
+	scopes, _ := c.Locals(LocAPIKeyScopes).([]string)
+	return scopes, true
+}
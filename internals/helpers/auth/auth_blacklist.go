@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -51,12 +52,25 @@ func getRawAccessToken(c *fiber.Ctx) string {
    =========================================================
 */
 
+// HashAccessToken: HMAC(access_token) hex — nilai yang disimpan di token_blacklist.token.
+// Dipakai juga untuk mencatat access token terakhir per sesi (refresh_tokens.access_token_hash).
+func HashAccessToken(rawAccessToken, jwtSecret string) string {
+	return hmacHex(rawAccessToken, jwtSecret)
+}
+
 // Add: simpan HMAC(access_token) (hex) ke kolom token TEXT.
 func Add(ctx context.Context, db *gorm.DB, rawAccessToken, jwtSecret string, expiresAt time.Time) error {
 	if db == nil || strings.TrimSpace(rawAccessToken) == "" || strings.TrimSpace(jwtSecret) == "" {
 		return nil
 	}
-	tokenHex := hmacHex(rawAccessToken, jwtSecret)
+	return AddHash(ctx, db, hmacHex(rawAccessToken, jwtSecret), expiresAt)
+}
+
+// AddHash: blacklist dari hash yang sudah dihitung (revoke sesi lain tanpa raw token).
+func AddHash(ctx context.Context, db *gorm.DB, tokenHex string, expiresAt time.Time) error {
+	if db == nil || strings.TrimSpace(tokenHex) == "" {
+		return nil
+	}
 	// ON CONFLICT sesuai unique(token) di skema kamu
 	return db.WithContext(ctx).Exec(`
 		INSERT INTO token_blacklist (token, expired_at)
@@ -86,6 +100,24 @@ func IsBlacklisted(ctx context.Context, db *gorm.DB, rawAccessToken, jwtSecret s
 	return exists, err
 }
 
+// IsSessionRevoked: sesi (refresh_tokens.id = klaim "sid") sudah dicabut / logout (baris dihapus).
+// Menutup semua access token sesi tsb, bukan hanya access token terakhir yang di-blacklist.
+func IsSessionRevoked(ctx context.Context, db *gorm.DB, sessionID uuid.UUID) (bool, error) {
+	if db == nil || sessionID == uuid.Nil {
+		return false, nil
+	}
+	var alive bool
+	err := db.WithContext(ctx).Raw(`
+		SELECT EXISTS (
+		  SELECT 1
+		  FROM refresh_tokens
+		  WHERE id = ?
+		    AND revoked_at IS NULL
+		)
+	`, sessionID).Scan(&alive).Error
+	return !alive, err
+}
+
 // PurgeExpired: hapus yang sudah lewat (atau ganti ke soft-delete sesuai preferensi)
 func PurgeExpired(ctx context.Context, db *gorm.DB) error {
 	if db == nil {
@@ -38,6 +38,15 @@ const (
 	LocSchoolID  = "school_id"  // string UUID
 	LocTeacherID = "teacher_id" // string UUID
 	LocStudentID = "student_id" // string UUID
+
+	// Diisi middleware permission bila request lolos lewat permission matrix
+	// (override/custom role), bukan lewat role admin bawaan.
+	LocPermissionGuard = "__perm_guard_ok" // string UUID school
+
+	// Diisi AuthAPIKey bila request memakai API key sekolah (bukan JWT user)
+	LocAPIKeyID     = "api_key_id"     // string UUID
+	LocAPIKeyPrefix = "api_key_prefix" // string (msk_xxxxxxxx)
+	LocAPIKeyScopes = "api_key_scopes" // []string pola permission
 )
 
 /* ============================================
@@ -358,6 +367,33 @@ func HasRoleInSchool(c *fiber.Ctx, schoolID uuid.UUID, role string) bool {
 	return false
 }
 
+// GetRolesInSchool: semua role user di school tsb (dari school_roles token).
+func GetRolesInSchool(c *fiber.Ctx, schoolID uuid.UUID) []string {
+	entries, err := parseSchoolRoles(c)
+	if err != nil {
+		return nil
+	}
+	for _, e := range entries {
+		if e.SchoolID == schoolID {
+			return e.Roles
+		}
+	}
+	return nil
+}
+
+// MarkPermissionGranted: request sudah diotorisasi permission matrix untuk school ini;
+// guard Ensure*School berikutnya tidak menolak ulang berdasarkan role bawaan.
+func MarkPermissionGranted(c *fiber.Ctx, schoolID uuid.UUID) {
+	if schoolID != uuid.Nil {
+		c.Locals(LocPermissionGuard, schoolID.String())
+	}
+}
+
+func IsPermissionGranted(c *fiber.Ctx, schoolID uuid.UUID) bool {
+	v, _ := c.Locals(LocPermissionGuard).(string)
+	return v != "" && schoolID != uuid.Nil && strings.EqualFold(v, schoolID.String())
+}
+
 func hasAnyRole(userRoles, allowed []string) bool {
 	m := make(map[string]struct{}, len(userRoles))
 	for _, r := range userRoles {
@@ -809,6 +845,12 @@ func ensureRolesInSchool(
 		return nil
 	}
 
+	// 1b) Sudah lolos permission matrix untuk school ini (RequirePermission)
+	if IsPermissionGranted(c, schoolID) {
+		markGuardOK(c, schoolID)
+		return nil
+	}
+
 	// 2) Presence gate: pastikan school ini memang ada di token
 	if !isSchoolPresentInToken(c, schoolID) {
 		return helper.JsonError(c, fiber.StatusForbidden, "School ini tidak ada dalam token Anda")
@@ -10,6 +10,8 @@ import (
 	"github.com/aliyun/aliyun-oss-go-sdk/oss"
 	"github.com/robfig/cron/v3"
 	"gorm.io/gorm"
+
+	"madinahsalam_backend/internals/helpers/telemetry"
 )
 
 type TrashReaperConfig struct {
@@ -90,16 +92,22 @@ func StartTrashReaperCron(db *gorm.DB) {
 		ctx, cancel := context.WithTimeout(context.Background(), 4*time.Minute)
 		defer cancel()
 		retention := time.Duration(cfg.RetentionDays) * 24 * time.Hour
+		start := time.Now()
 
 		// 1) OSS spam cleaner
 		if bucket != nil {
-			if err := runOSSReaper(ctx, bucket, cfg.Prefix, retention, cfg.DryRun); err != nil {
+			err := runOSSReaper(ctx, bucket, cfg.Prefix, retention, cfg.DryRun)
+			telemetry.ObserveWorkerRun("trash_reaper_oss", start, err)
+			if err != nil {
 				log.Printf("[TRASH-REAPER] OSS error: %v", err)
 			}
 		}
 
 		// 2) DB soft-delete cleaner
-		if err := runDBReaper(ctx, db, retention); err != nil {
+		dbStart := time.Now()
+		err := runDBReaper(ctx, db, retention)
+		telemetry.ObserveWorkerRun("trash_reaper_db", dbStart, err)
+		if err != nil {
 			log.Printf("[TRASH-REAPER] DB error: %v", err)
 		}
 	})
@@ -0,0 +1,115 @@
+// file: internals/helpers/telemetry/gorm.go
+package telemetry
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+/* =========================================================
+   Callback GORM: span + histogram per query
+
+   - Span hanya dibuat bila context membawa span request (child span);
+     query worker tanpa parent tidak membuat root span baru.
+   - db.statement = SQL ber-placeholder (tanpa nilai parameter).
+   - Metrik latency tetap dicatat untuk semua query.
+========================================================= */
+
+const (
+	settingStart = "otel:start"
+	settingSpan  = "otel:span"
+)
+
+// InstrumentGORM memasang callback tracing/metrics + collector pool koneksi (panggil sekali saat init DB).
+func InstrumentGORM(db *gorm.DB) error {
+	cb := db.Callback()
+	ops := []struct {
+		name   string
+		before func(string, func(*gorm.DB)) error
+		after  func(string, func(*gorm.DB)) error
+	}{
+		{"create", cb.Create().Before("gorm:create").Register, cb.Create().After("gorm:create").Register},
+		{"query", cb.Query().Before("gorm:query").Register, cb.Query().After("gorm:query").Register},
+		{"update", cb.Update().Before("gorm:update").Register, cb.Update().After("gorm:update").Register},
+		{"delete", cb.Delete().Before("gorm:delete").Register, cb.Delete().After("gorm:delete").Register},
+		{"row", cb.Row().Before("gorm:row").Register, cb.Row().After("gorm:row").Register},
+		{"raw", cb.Raw().Before("gorm:raw").Register, cb.Raw().After("gorm:raw").Register},
+	}
+	for _, op := range ops {
+		if err := op.before("otel:before_"+op.name, before); err != nil {
+			return err
+		}
+		if err := op.after("otel:after_"+op.name, after(op.name)); err != nil {
+			return err
+		}
+	}
+
+	if sqlDB, err := db.DB(); err == nil {
+		if err := RegisterDBStats(sqlDB, "postgres"); err != nil {
+			return err
+		}
+	}
+	log.Println("[OTEL] GORM instrumentation aktif")
+	return nil
+}
+
+func before(db *gorm.DB) {
+	db.InstanceSet(settingStart, time.Now())
+
+	ctx := SpanContext(db.Statement.Context)
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return
+	}
+	_, span := Tracer().Start(ctx, "gorm.query", trace.WithSpanKind(trace.SpanKindClient))
+	db.InstanceSet(settingSpan, span)
+}
+
+func after(op string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		var dur time.Duration
+		if v, ok := db.InstanceGet(settingStart); ok {
+			if start, ok := v.(time.Time); ok {
+				dur = time.Since(start)
+			}
+		}
+		failed := db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound)
+		table := db.Statement.Table
+		ObserveDBQuery(op, table, failed, dur)
+
+		v, ok := db.InstanceGet(settingSpan)
+		if !ok {
+			return
+		}
+		span, ok := v.(trace.Span)
+		if !ok {
+			return
+		}
+		defer span.End()
+
+		name := "gorm." + op
+		if table != "" {
+			name += " " + table
+		}
+		span.SetName(name)
+		span.SetAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.operation", op),
+			attribute.String("db.sql.table", table),
+			attribute.String("db.statement", db.Statement.SQL.String()),
+			attribute.Int64("db.rows_affected", db.Statement.RowsAffected),
+		)
+		if rid := RequestID(db.Statement.Context); rid != "" {
+			span.SetAttributes(attribute.String("http.request_id", rid))
+		}
+		if failed {
+			span.RecordError(db.Error)
+			span.SetStatus(codes.Error, db.Error.Error())
+		}
+	}
+}
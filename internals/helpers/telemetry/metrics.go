@@ -0,0 +1,162 @@
+// file: internals/helpers/telemetry/metrics.go
+package telemetry
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+/* =========================================================
+   Metrics (Prometheus) → GET /metrics
+
+   ENV:
+     METRICS_TOKEN  wajib untuk mengaktifkan /metrics ("Authorization: Bearer <token>");
+                    kosong → /metrics 404
+
+   Registry sendiri (bukan default global) supaya isi /metrics hanya
+   metrik yang didaftarkan di sini + runtime Go & proses.
+========================================================= */
+
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Latency request HTTP per route.",
+		Buckets: []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+	}, []string{"method", "route", "status"})
+
+	tenantRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_tenant_requests_total",
+		Help: "Jumlah request per sekolah (tenant).",
+	}, []string{"school_id"})
+
+	dbQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "Latency query GORM per operasi & tabel.",
+		Buckets: []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"operation", "table", "result"})
+
+	webhookDeliveries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "webhook_deliveries_total",
+		Help: "Hasil percobaan kirim webhook (succeeded/retry/dead).",
+	}, []string{"event", "result"})
+
+	webhookDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "webhook_delivery_duration_seconds",
+		Help:    "Durasi request HTTP ke endpoint webhook sekolah.",
+		Buckets: []float64{.05, .1, .25, .5, 1, 2.5, 5, 10},
+	}, []string{"result"})
+
+	workerRuns = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "worker_runs_total",
+		Help: "Jumlah eksekusi job background per worker & hasil.",
+	}, []string{"worker", "result"})
+
+	workerDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "worker_run_duration_seconds",
+		Help:    "Durasi eksekusi job background.",
+		Buckets: []float64{.01, .1, .5, 1, 5, 15, 60, 300},
+	}, []string{"worker"})
+
+	workerLastSuccess = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "worker_last_success_timestamp_seconds",
+		Help: "Unix time eksekusi sukses terakhir (alert bila basi).",
+	}, []string{"worker"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpDuration, tenantRequests, dbQueryDuration,
+		webhookDeliveries, webhookDuration,
+		workerRuns, workerDuration, workerLastSuccess,
+	)
+}
+
+// RegisterDBStats: open/idle/in-use connection, wait count & durasi dari sql.DB.Stats().
+func RegisterDBStats(sqlDB *sql.DB, dbName string) error {
+	return Registry.Register(collectors.NewDBStatsCollector(sqlDB, dbName))
+}
+
+/* =========================================================
+   Recorder
+========================================================= */
+
+func ObserveHTTP(method, route string, status int, dur time.Duration) {
+	httpDuration.WithLabelValues(method, route, strconv.Itoa(status)).Observe(dur.Seconds())
+}
+
+// CountTenant: hanya UUID valid; request tanpa sekolah → "none" (label tetap terbatas).
+func CountTenant(schoolID string) {
+	label := "none"
+	if id, err := uuid.Parse(strings.TrimSpace(schoolID)); err == nil && id != uuid.Nil {
+		label = id.String()
+	}
+	tenantRequests.WithLabelValues(label).Inc()
+}
+
+func ObserveDBQuery(operation, table string, failed bool, dur time.Duration) {
+	result := "ok"
+	if failed {
+		result = "error"
+	}
+	if table == "" {
+		table = "raw"
+	}
+	dbQueryDuration.WithLabelValues(operation, table, result).Observe(dur.Seconds())
+}
+
+// ObserveWebhookDelivery: dur 0 = ditutup tanpa request HTTP (subscription hilang/nonaktif).
+func ObserveWebhookDelivery(event, result string, dur time.Duration) {
+	webhookDeliveries.WithLabelValues(event, result).Inc()
+	if dur > 0 {
+		webhookDuration.WithLabelValues(result).Observe(dur.Seconds())
+	}
+}
+
+// ObserveWorkerRun: panggil di akhir satu eksekusi job (defer dengan start & err).
+func ObserveWorkerRun(worker string, start time.Time, err error) {
+	workerDuration.WithLabelValues(worker).Observe(time.Since(start).Seconds())
+	if err != nil {
+		workerRuns.WithLabelValues(worker, "error").Inc()
+		return
+	}
+	workerRuns.WithLabelValues(worker, "ok").Inc()
+	workerLastSuccess.WithLabelValues(worker).SetToCurrentTime()
+}
+
+/* =========================================================
+   Handler
+========================================================= */
+
+// MetricsHandler: endpoint scrape Prometheus, wajib bearer METRICS_TOKEN.
+// METRICS_TOKEN kosong → 404 (fail-closed; metrik memuat label route & tenant).
+func MetricsHandler() fiber.Handler {
+	token := strings.TrimSpace(os.Getenv("METRICS_TOKEN"))
+	if token == "" {
+		log.Println("[TELEMETRY] METRICS_TOKEN kosong → /metrics nonaktif (404)")
+		return func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusNotFound) }
+	}
+	h := adaptor.HTTPHandler(promhttp.HandlerFor(Registry, promhttp.HandlerOpts{}))
+	return func(c *fiber.Ctx) error {
+		got := strings.TrimSpace(strings.TrimPrefix(c.Get(fiber.HeaderAuthorization), "Bearer "))
+		if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			return c.SendStatus(fiber.StatusUnauthorized)
+		}
+		return h(c)
+	}
+}
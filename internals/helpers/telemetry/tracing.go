@@ -0,0 +1,140 @@
+// file: internals/helpers/telemetry/tracing.go
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+/* =========================================================
+   Tracing (OpenTelemetry)
+
+   ENV:
+     OTEL_TRACES_EXPORTER      none (default) | stdout | otlp
+     OTEL_SERVICE_NAME         default "madinahsalam_backend"
+     OTEL_TRACES_SAMPLER_ARG   rasio sampling root span 0..1 (default 1)
+     OTEL_EXPORTER_OTLP_*      endpoint/header/timeout standar exporter OTLP/HTTP
+     APP_ENV                   → deployment.environment
+
+   none = tracer no-op (lokal); propagator W3C tetap aktif supaya
+   traceparent dari upstream diteruskan apa adanya.
+========================================================= */
+
+const (
+	instrumentationName = "madinahsalam_backend"
+
+	// LocalsKey: key c.Locals tempat middleware menyimpan context ber-span.
+	// Fiber Locals = fasthttp UserValue, dan RequestCtx.Value(string) membaca UserValue,
+	// jadi query yang memakai WithContext(c.Context()) tetap menempel ke span request.
+	LocalsKey = "otel_ctx"
+
+	// RequestIDKey: key c.Locals request id (dipasang middleware request).
+	RequestIDKey = "reqid"
+)
+
+type Config struct {
+	Exporter    string
+	ServiceName string
+	Environment string
+	SampleRatio float64
+}
+
+func LoadConfig() Config {
+	cfg := Config{
+		Exporter:    strings.ToLower(strings.TrimSpace(os.Getenv("OTEL_TRACES_EXPORTER"))),
+		ServiceName: strings.TrimSpace(os.Getenv("OTEL_SERVICE_NAME")),
+		Environment: strings.TrimSpace(os.Getenv("APP_ENV")),
+		SampleRatio: 1,
+	}
+	if cfg.Exporter == "" {
+		cfg.Exporter = "none"
+	}
+	if cfg.ServiceName == "" {
+		cfg.ServiceName = instrumentationName
+	}
+	if v, err := strconv.ParseFloat(strings.TrimSpace(os.Getenv("OTEL_TRACES_SAMPLER_ARG")), 64); err == nil && v >= 0 && v <= 1 {
+		cfg.SampleRatio = v
+	}
+	return cfg
+}
+
+// InitTracing memasang tracer provider global. Shutdown wajib dipanggil saat exit (flush span).
+func InitTracing(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{},
+	))
+
+	var (
+		exp sdktrace.SpanExporter
+		err error
+	)
+	switch cfg.Exporter {
+	case "none", "noop":
+		log.Println("[OTEL] tracing no-op (OTEL_TRACES_EXPORTER=none)")
+		return func(context.Context) error { return nil }, nil
+	case "stdout":
+		exp, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case "otlp":
+		exp, err = otlptracehttp.New(ctx)
+	default:
+		return nil, fmt.Errorf("OTEL_TRACES_EXPORTER tidak dikenal: %q", cfg.Exporter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	attrs := []attribute.KeyValue{attribute.String("service.name", cfg.ServiceName)}
+	if cfg.Environment != "" {
+		attrs = append(attrs, attribute.String("deployment.environment", cfg.Environment))
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithResource(resource.NewSchemaless(attrs...)),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(tp)
+
+	log.Printf("[OTEL] tracing aktif exporter=%s service=%s ratio=%.2f", cfg.Exporter, cfg.ServiceName, cfg.SampleRatio)
+	return tp.Shutdown, nil
+}
+
+func Tracer() trace.Tracer { return otel.Tracer(instrumentationName) }
+
+// SpanContext: ctx yang membawa span aktif. Untuk *fasthttp.RequestCtx (c.Context())
+// span diambil dari Locals; context biasa dikembalikan apa adanya.
+func SpanContext(ctx context.Context) context.Context {
+	if ctx == nil {
+		return context.Background()
+	}
+	if trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+	if parent, ok := ctx.Value(LocalsKey).(context.Context); ok && parent != nil {
+		return trace.ContextWithSpan(ctx, trace.SpanFromContext(parent))
+	}
+	return ctx
+}
+
+// RequestID dari context request (Locals "reqid"); "" untuk worker.
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if s, ok := ctx.Value(RequestIDKey).(string); ok {
+		return s
+	}
+	return ""
+}
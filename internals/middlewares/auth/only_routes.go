@@ -2,11 +2,19 @@ package auth
 
 import (
 	"github.com/gofiber/fiber/v2"
+
+	helperAuth "madinahsalam_backend/internals/helpers/auth"
 )
 
 // OnlyRolesSlice memungkinkan akses jika user memiliki salah satu dari role yang diizinkan.
 func OnlyRolesSlice(message string, allowedRoles []string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		// sudah diotorisasi permission matrix (override / custom role) untuk school aktif;
+		// tanda untuk school lain tidak berlaku di sini
+		if mid, err := helperAuth.GetActiveSchoolIDFromToken(c); err == nil && helperAuth.IsPermissionGranted(c, mid) {
+			return c.Next()
+		}
+
 		role, ok := c.Locals("role").(string)
 		if !ok {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
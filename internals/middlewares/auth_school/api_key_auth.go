@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	"madinahsalam_backend/internals/constants"
+	apikeysvc "madinahsalam_backend/internals/features/lembaga/api_keys/service"
+	helperAuth "madinahsalam_backend/internals/helpers/auth"
+)
+
+// HeaderAPIKey: header utama untuk API key (Authorization: Bearer msk_... juga diterima).
+const HeaderAPIKey = "X-API-Key"
+
+// rawAPIKey: ambil key dari X-API-Key atau Bearer yang berawalan msk_.
+func rawAPIKey(c *fiber.Ctx) string {
+	if v := strings.TrimSpace(c.Get(HeaderAPIKey)); v != "" {
+		return v
+	}
+	if authz := strings.TrimSpace(c.Get(fiber.HeaderAuthorization)); strings.HasPrefix(strings.ToLower(authz), "bearer ") {
+		if tok := strings.TrimSpace(authz[7:]); apikeysvc.LooksLikeKey(tok) {
+			return tok
+		}
+	}
+	return ""
+}
+
+// AuthAPIKey: autentikasi mesin-ke-mesin dengan API key sekolah.
+// Locals diisi sama seperti AuthJWT (school_roles, active_school_id, roles_claim, role)
+// dengan role "api_key" di sekolah pemilik key; tidak ada user_id.
+func AuthAPIKey(db *gorm.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		raw := rawAPIKey(c)
+		if raw == "" {
+			return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+		}
+		// helper lama membaca klaim Authorization tanpa verifikasi → jangan campur dua kredensial
+		if c.Get(HeaderAPIKey) != "" && strings.TrimSpace(c.Get(fiber.HeaderAuthorization)) != "" {
+			return fiber.NewError(fiber.StatusBadRequest, "Gunakan X-API-Key atau Authorization, bukan keduanya")
+		}
+
+		k, err := apikeysvc.Authenticate(c.Context(), db, raw, c.IP())
+		switch {
+		case err == nil:
+		case errors.Is(err, apikeysvc.ErrKeyIPNotAllowed):
+			return fiber.NewError(fiber.StatusForbidden, err.Error())
+		case errors.Is(err, apikeysvc.ErrKeyInvalid),
+			errors.Is(err, apikeysvc.ErrKeyRevoked),
+			errors.Is(err, apikeysvc.ErrKeyExpired):
+			return fiber.NewError(fiber.StatusUnauthorized, err.Error())
+		default:
+			log.Printf("[AuthAPIKey] verify: %v", err)
+			return fiber.NewError(fiber.StatusInternalServerError, "Gagal memverifikasi api key")
+		}
+
+		sid := k.SchoolAPIKeySchoolID.String()
+		scopes := append([]string(nil), k.SchoolAPIKeyScopes...)
+
+		c.Locals(helperAuth.LocAPIKeyID, k.SchoolAPIKeyID.String())
+		c.Locals(helperAuth.LocAPIKeyPrefix, k.SchoolAPIKeyPrefix)
+		c.Locals(helperAuth.LocAPIKeyScopes, scopes)
+
+		// bentuk sama dengan klaim JWT (generic []any) supaya semua guard bisa membaca
+		c.Locals(helperAuth.LocRolesGlobal, []string{})
+		c.Locals(helperAuth.LocSchoolRoles, []any{
+			map[string]any{"school_id": sid, "roles": []any{constants.RoleAPIKey}},
+		})
+		c.Locals(helperAuth.LocActiveSchoolID, sid)
+		c.Locals(helperAuth.LocSchoolID, sid)
+		c.Locals("roles_claim", helperAuth.RolesClaim{
+			RolesGlobal: []string{},
+			SchoolRoles: []helperAuth.SchoolRolesEntry{
+				{SchoolID: k.SchoolAPIKeySchoolID, Roles: []string{constants.RoleAPIKey}},
+			},
+		})
+		c.Locals(helperAuth.LocRole, constants.RoleAPIKey)
+
+		return c.Next()
+	}
+}
+
+// AuthJWTOrAPIKey: pakai AuthAPIKey bila request membawa API key, selain itu AuthJWT.
+func AuthJWTOrAPIKey(o AuthJWTOpts, db *gorm.DB) fiber.Handler {
+	jwtAuth := AuthJWT(o)
+	keyAuth := AuthAPIKey(db)
+	return func(c *fiber.Ctx) error {
+		if rawAPIKey(c) != "" {
+			return keyAuth(c)
+		}
+		return jwtAuth(c)
+	}
+}
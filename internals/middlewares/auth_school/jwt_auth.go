@@ -1,12 +1,15 @@
 package middleware
 
 import (
+	"context"
+	"log"
 	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 
 	helperAuth "madinahsalam_backend/internals/helpers/auth"
 )
@@ -15,6 +18,14 @@ type AuthJWTOpts struct {
 	Secret              string
 	BlacklistChecker    func(rawToken string) (bool, error) // return true if blacklisted
 	AllowCookieFallback bool                                // pakai cookie access_token jika tidak ada Bearer
+	SessionChecker      func(sid uuid.UUID) (bool, error)   // return true if sesi (klaim "sid") sudah dicabut
+}
+
+// SessionRevokedChecker: SessionChecker berbasis refresh_tokens (lihat helperAuth.IsSessionRevoked).
+func SessionRevokedChecker(db *gorm.DB) func(sid uuid.UUID) (bool, error) {
+	return func(sid uuid.UUID) (bool, error) {
+		return helperAuth.IsSessionRevoked(context.Background(), db, sid)
+	}
 }
 
 func AuthJWT(o AuthJWTOpts) fiber.Handler {
@@ -58,6 +69,22 @@ func AuthJWT(o AuthJWTOpts) fiber.Handler {
 			return fiber.NewError(fiber.StatusUnauthorized, "Invalid token claims")
 		}
 
+		// 4) Sesi dicabut (revoke / logout) → semua access token sesi itu ditolak,
+		//    termasuk yang terbit sebelum rotasi terakhir. Token lama tanpa "sid" dilewati.
+		//    Gagal cek (DB error) → tolak (fail-closed), jangan biarkan sesi dicabut tetap jalan.
+		if o.SessionChecker != nil {
+			if sid, err := uuid.Parse(strClaim(claims, "sid")); err == nil {
+				revoked, err := o.SessionChecker(sid)
+				if err != nil {
+					log.Printf("[AuthJWT] session check sid=%s: %v", sid, err)
+					return fiber.NewError(fiber.StatusServiceUnavailable, "Tidak bisa memverifikasi sesi, coba lagi")
+				}
+				if revoked {
+					return fiber.NewError(fiber.StatusUnauthorized, "Session revoked")
+				}
+			}
+		}
+
 		// Simpan raw claims (opsional)
 		c.Locals("jwt_claims", claims)
 
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	auditService "madinahsalam_backend/internals/features/lembaga/audit_logs/service"
+	helper "madinahsalam_backend/internals/helpers/auth"
+)
+
+/* ==========================
+   Audit: identitas aktor per request
+========================== */
+
+// AuditContext menyimpan aktor (user, sekolah aktif, role, request id) ke Locals
+// supaya callback audit GORM bisa membacanya dari context query.
+// Pasang SETELAH AuthJWT / IsSchoolAdmin agar user & sekolah sudah ter-resolve.
+func AuditContext() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		switch c.Method() {
+		case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+			return c.Next()
+		}
+
+		a := &auditService.Actor{
+			Role:      trimLower(asString(c.Locals("active_role"))),
+			RequestID: asString(c.Locals("reqid")),
+			IP:        c.IP(),
+			UserAgent: c.Get(fiber.HeaderUserAgent),
+			Method:    c.Method(),
+			Path:      c.Path(),
+		}
+		if prefix := helper.GetAPIKeyPrefix(c); prefix != "" {
+			// principal mesin: tidak ada user, role mencatat key yang dipakai
+			a.Role = "api_key:" + prefix
+		} else if uid, err := helper.GetUserIDFromToken(c); err == nil && uid != uuid.Nil {
+			a.UserID = &uid
+		}
+		if sid, err := uuid.Parse(strings.TrimSpace(asString(c.Locals("school_id")))); err == nil && sid != uuid.Nil {
+			a.SchoolID = &sid
+		}
+		c.Locals(auditService.LocalsKey, a)
+		return c.Next()
+	}
+}
@@ -352,7 +352,7 @@ func extractSchoolIDStrict(c *fiber.Ctx) string {
 // UseSchoolScope (strict-ish):
 // - Coba ambil school_id dari PATH/param (UUID).
 // - Kalau kosong, fallback ke GetActiveSchoolIDFromToken (1 sesi = 1 sekolah).
-// - Non-owner: school harus ada di token (school_roles).
+// - Non-owner: school harus ada di token (school_roles), atau user admin yayasan pemilik school (role yayasan_admin).
 // - Role: jika dikirim user, harus ada di school tsb; kalau tidak, pilih best role di school tsb.
 // - Set locals: active_school_id, active_role (+ kompat: school_id, role).
 func UseSchoolScope() fiber.Handler {
@@ -454,12 +454,23 @@ func UseSchoolScope() fiber.Handler {
 			}
 		}
 
+		// admin yayasan → berlaku di semua school anggota yayasan (diverifikasi ke DB)
+		yayasanScoped := false
+		if (len(rolesAtSchool) == 0 || reqRole == constants.RoleYayasanAdmin) && yayasanAdminScope(c, reqSchool) {
+			rolesAtSchool = []string{constants.RoleYayasanAdmin}
+			yayasanScoped = true
+		}
+
 		if len(rolesAtSchool) == 0 {
 			return fiber.NewError(fiber.StatusForbidden, "Bukan anggota pada school yang diminta")
 		}
 
 		activeRole := reqRole
-		if activeRole != "" {
+		if yayasanScoped {
+			activeRole = constants.RoleYayasanAdmin
+			// guard lama (IsSchoolAdmin / Ensure*School / OnlyRolesSlice) menghormati tanda ini
+			helper.MarkPermissionGranted(c, uuid.MustParse(reqSchool))
+		} else if activeRole != "" {
 			if !roleInSchool(c, reqSchool, activeRole) {
 				return fiber.NewError(fiber.StatusForbidden, "Role tidak tersedia pada school tersebut")
 			}
@@ -507,6 +518,10 @@ func RequirePathScopeMatch() fiber.Handler {
 		if !strings.EqualFold(pathID, active) {
 			return fiber.NewError(fiber.StatusForbidden, "Scope school tidak cocok dengan path")
 		}
+		// scope yayasan_admin hanya sah untuk school yang diverifikasi di UseSchoolScope
+		if trimLower(asString(c.Locals("active_role"))) == constants.RoleYayasanAdmin && !isYayasanScoped(c, pathID) {
+			return fiber.NewError(fiber.StatusForbidden, "Bukan admin yayasan pemilik school ini")
+		}
 		return c.Next()
 	}
 }
@@ -534,6 +549,11 @@ func IsSchoolAdmin() fiber.Handler {
 			return c.Next()
 		}
 
+		// sudah lolos permission matrix untuk school ini (RequireAdminOrPermission)
+		if v, _ := c.Locals(helper.LocPermissionGuard).(string); v != "" && strings.EqualFold(v, mid) {
+			return c.Next()
+		}
+
 		// only admin/dkm
 		switch role {
 		case "admin", constants.RoleDKM:
@@ -0,0 +1,82 @@
+// file: internals/middlewares/features/require_permission.go
+package middleware
+
+import (
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"madinahsalam_backend/internals/constants"
+	permsvc "madinahsalam_backend/internals/features/lembaga/permissions/service"
+	helper "madinahsalam_backend/internals/helpers/auth"
+)
+
+/* ==========================
+   Permission matrix (resource.action)
+========================== */
+
+// RequirePermission: izinkan bila user punya permission di school aktif
+// (default role ± override sekolah ∪ custom role). Contoh: RequirePermission("payments.read").
+func RequirePermission(perm string) fiber.Handler {
+	if !permsvc.ValidPattern(perm) || strings.Contains(perm, "*") {
+		panic("RequirePermission: permission tidak terdaftar: " + perm)
+	}
+	return func(c *fiber.Ctx) error {
+		schoolID, err := permsvc.SchoolFromRequest(c)
+		if err != nil || schoolID == uuid.Nil {
+			var fe *fiber.Error
+			if errors.As(err, &fe) {
+				return fe
+			}
+			return fiber.NewError(fiber.StatusBadRequest, "Scope school belum ditentukan")
+		}
+		return checkPermission(c, schoolID, perm)
+	}
+}
+
+// RequireAdminOrPermission (pengganti IsSchoolAdmin di grup /api/a):
+// - owner/admin/dkm → sama persis dengan IsSchoolAdmin
+// - yayasan_admin pada school anggota yayasannya → seperti admin
+// - role lain → hanya path yang terpetakan ke permission & diberikan sekolah
+func RequireAdminOrPermission() fiber.Handler {
+	adminOnly := IsSchoolAdmin()
+	return func(c *fiber.Ctx) error {
+		role := trimLower(asString(c.Locals("active_role")))
+		if helper.IsOwner(c) || role == constants.RoleAdmin || role == constants.RoleDKM {
+			return adminOnly(c)
+		}
+		// admin yayasan pada school anggota (UseSchoolScope) → setara admin school
+		if role == constants.RoleYayasanAdmin && isYayasanScoped(c, asString(c.Locals("active_school_id"))) {
+			return adminOnly(c)
+		}
+
+		mid, err := uuid.Parse(strings.TrimSpace(asString(c.Locals("active_school_id"))))
+		if err != nil || role == "" {
+			return fiber.NewError(fiber.StatusUnauthorized, "Scope school/role belum ditentukan")
+		}
+		perm := permsvc.PermissionForAdminPath(c.Method(), c.Path())
+		if perm == "" {
+			return fiber.NewError(fiber.StatusForbidden, "Role tidak berhak mengakses endpoint ini")
+		}
+		return checkPermission(c, mid, perm)
+	}
+}
+
+func checkPermission(c *fiber.Ctx, schoolID uuid.UUID, perm string) error {
+	eff, err := permsvc.ForRequest(c, schoolID)
+	if err != nil {
+		log.Println("[WARN] permission resolve:", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Gagal memeriksa hak akses")
+	}
+	if !eff.Has(perm) {
+		return fiber.NewError(fiber.StatusForbidden, "Tidak memiliki izin: "+perm)
+	}
+
+	// guard lama (IsSchoolAdmin / Ensure*School / OnlyRolesSlice) menghormati tanda ini
+	helper.MarkPermissionGranted(c, schoolID)
+	c.Locals("school_id", schoolID.String())
+	return c.Next()
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"log"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	subsService "madinahsalam_backend/internals/features/finance/school_subscriptions/service"
+	helper "madinahsalam_backend/internals/helpers/auth"
+)
+
+/* ==========================
+   Guard: langganan lapse (read-only)
+========================== */
+
+// RequireWritableSubscription:
+// - Sekolah yang langganannya lapse dengan aksi read_only hanya boleh GET/HEAD/OPTIONS.
+// - Pengecualian hanya POST /subscription & POST /subscription/invoices/:id/pay (lunasi tagihan).
+// - Dipasang di /api/a dan /api/u (scoped); tanpa scope di locals → sekolah aktif dari token.
+func RequireWritableSubscription(db *gorm.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		switch c.Method() {
+		case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+			return c.Next()
+		}
+		if isSubscriptionBillingPath(c.Method(), c.Path()) {
+			return c.Next()
+		}
+		if helper.IsOwner(c) {
+			return c.Next()
+		}
+
+		mid, err := uuid.Parse(strings.TrimSpace(asString(c.Locals("active_school_id"))))
+		if err != nil {
+			if mid, err = helper.GetActiveSchoolIDFromToken(c); err != nil || mid == uuid.Nil {
+				return c.Next() // scope belum ada → biar guard lain yang menolak
+			}
+		}
+
+		ro, err := subsService.IsSchoolReadOnly(c.Context(), db, mid)
+		if err != nil {
+			log.Println("[WARN] RequireWritableSubscription:", err)
+			return c.Next()
+		}
+		if ro {
+			return fiber.NewError(fiber.StatusPaymentRequired, "Langganan sekolah berakhir; mode baca-saja sampai tagihan dibayar")
+		}
+		return c.Next()
+	}
+}
+
+// isSubscriptionBillingPath: /api/{a|u}[/:school_id]/subscription atau .../subscription/invoices/:id/pay.
+func isSubscriptionBillingPath(method, path string) bool {
+	if method != fiber.MethodPost {
+		return false
+	}
+	segs := strings.Split(strings.Trim(strings.ToLower(path), "/"), "/")
+	if len(segs) < 3 || segs[0] != "api" {
+		return false
+	}
+	rest := segs[2:]
+	if _, err := uuid.Parse(rest[0]); err == nil {
+		rest = rest[1:]
+	}
+	switch {
+	case len(rest) == 1:
+		return rest[0] == "subscription"
+	case len(rest) == 4:
+		_, err := uuid.Parse(rest[2])
+		return rest[0] == "subscription" && rest[1] == "invoices" && err == nil && rest[3] == "pay"
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"log"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"madinahsalam_backend/internals/constants"
+	permsvc "madinahsalam_backend/internals/features/lembaga/permissions/service"
+	ySvc "madinahsalam_backend/internals/features/lembaga/school_yayasans/yayasans/service"
+	helper "madinahsalam_backend/internals/helpers/auth"
+)
+
+/* ==========================
+   Scope yayasan_admin (lintas school)
+========================== */
+
+// IsYayasanAdmin:
+// - Wajib ada :yayasan_id di path.
+// - Owner global bypass; selain itu user harus terdaftar di yayasan_admins.
+// - Set Locals "yayasan_id" untuk handler.
+// - Endpoint per-school (/api/a) untuk school anggota yayasan lewat UseSchoolScope (role yayasan_admin).
+func IsYayasanAdmin(db *gorm.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		yid, err := uuid.Parse(strings.TrimSpace(c.Params("yayasan_id")))
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "yayasan_id tidak valid")
+		}
+
+		if !helper.IsOwner(c) {
+			uid, err := helper.GetUserIDFromToken(c)
+			if err != nil {
+				return fiber.NewError(fiber.StatusUnauthorized, "User tidak terautentik")
+			}
+			ok, err := ySvc.IsYayasanAdmin(c.Context(), db, uid, yid)
+			if err != nil {
+				log.Println("[WARN] IsYayasanAdmin:", err)
+				return fiber.NewError(fiber.StatusInternalServerError, "Gagal memeriksa scope yayasan")
+			}
+			if !ok {
+				return fiber.NewError(fiber.StatusForbidden, "Akses khusus admin yayasan")
+			}
+		}
+
+		c.Locals("yayasan_id", yid.String())
+		return c.Next()
+	}
+}
+
+// yayasanAdminScope: user bukan anggota school (atau minta role yayasan_admin) tapi admin
+// yayasan pemilik school tsb → boleh masuk scope school dengan role yayasan_admin.
+// DB dari permsvc.Init (dipasang sekali di SetupRoutes); belum di-init → false.
+func yayasanAdminScope(c *fiber.Ctx, schoolID string) bool {
+	db, err := permsvc.DB()
+	if err != nil {
+		return false
+	}
+	sid, err := uuid.Parse(schoolID)
+	if err != nil {
+		return false
+	}
+	uid, err := helper.GetUserIDFromToken(c)
+	if err != nil || uid == uuid.Nil {
+		return false
+	}
+	ok, err := ySvc.IsYayasanAdminOfSchool(c.Context(), db, uid, sid)
+	if err != nil {
+		log.Println("[WARN] yayasanAdminScope:", err)
+		return false
+	}
+	return ok
+}
+
+// isYayasanScoped: scope aktif hasil yayasanAdminScope untuk school ini.
+func isYayasanScoped(c *fiber.Ctx, schoolID string) bool {
+	return trimLower(asString(c.Locals("active_role"))) == constants.RoleYayasanAdmin &&
+		strings.EqualFold(asString(c.Locals(helper.LocPermissionGuard)), schoolID)
+}
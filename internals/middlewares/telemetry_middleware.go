@@ -0,0 +1,132 @@
+// middlewares/telemetry_middleware.go
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"madinahsalam_backend/internals/helpers/telemetry"
+)
+
+// fiberCarrier: header request fasthttp sebagai TextMapCarrier (baca traceparent upstream).
+type fiberCarrier struct{ c *fiber.Ctx }
+
+func (f fiberCarrier) Get(key string) string { return f.c.Get(key) }
+func (f fiberCarrier) Set(key, val string)   { f.c.Request().Header.Set(key, val) }
+func (f fiberCarrier) Keys() []string {
+	keys := make([]string, 0, 16)
+	f.c.Request().Header.VisitAll(func(k, _ []byte) { keys = append(keys, string(k)) })
+	return keys
+}
+
+// path tanpa span (probe & scrape → noise)
+func skipTrace(path string) bool {
+	return path == "/health" || path == "/metrics"
+}
+
+// RequestTelemetry: request id + span server + metrik latency/tenant + log [REQ].
+// Dipasang sekali di app (sebelum semua route).
+// redactURL: nilai query ?token= (mis. token push mesin absen /iclock) tidak ikut ke log.
+func redactURL(raw string) string {
+	path, query, ok := strings.Cut(raw, "?")
+	if !ok || !strings.Contains(strings.ToLower(query), "token=") {
+		return raw
+	}
+	q, err := url.ParseQuery(query)
+	if err != nil {
+		return path + "?<redacted>"
+	}
+	for k := range q {
+		if strings.EqualFold(k, "token") {
+			q[k] = []string{"***"}
+		}
+	}
+	return path + "?" + q.Encode()
+}
+
+func RequestTelemetry() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Get("X-Request-ID")
+		if id == "" {
+			id = utils.UUID()
+		}
+		c.Set("X-Request-ID", id)
+		c.Locals(telemetry.RequestIDKey, id)
+
+		start := time.Now()
+		var ctx context.Context = c.Context()
+		span := trace.SpanFromContext(ctx)
+		if !skipTrace(c.Path()) {
+			ctx = otel.GetTextMapPropagator().Extract(ctx, fiberCarrier{c})
+			ctx, span = telemetry.Tracer().Start(ctx, c.Method()+" "+c.Path(),
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					attribute.String("http.request.method", c.Method()),
+					attribute.String("url.path", c.Path()),
+					attribute.String("http.request_id", id),
+					attribute.String("client.address", c.IP()),
+					attribute.String("user_agent.original", c.Get(fiber.HeaderUserAgent)),
+				))
+			defer span.End()
+			c.Locals(telemetry.LocalsKey, ctx)
+		}
+		traceID := ""
+		if sc := span.SpanContext(); sc.IsValid() {
+			traceID = sc.TraceID().String()
+			c.Set("X-Trace-ID", traceID)
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		c.SetUserContext(ctx)
+
+		err := c.Next()
+
+		// error handler fiber jalan setelah middleware → status final dihitung di sini
+		status := c.Response().StatusCode()
+		if err != nil {
+			var fe *fiber.Error
+			if errors.As(err, &fe) {
+				status = fe.Code
+			} else {
+				status = fiber.StatusInternalServerError
+			}
+		}
+		route := c.Route().Path
+		if status == fiber.StatusNotFound && route == "/" && c.Path() != "/" {
+			route = "unmatched" // hanya middleware global yang match → tidak ada handler
+		}
+		dur := time.Since(start)
+
+		telemetry.ObserveHTTP(c.Method(), route, status, dur)
+		telemetry.CountTenant(fmt.Sprint(c.Locals("school_id")))
+
+		span.SetName(c.Method() + " " + route)
+		span.SetAttributes(
+			attribute.String("http.route", route),
+			attribute.Int("http.response.status_code", status),
+		)
+		if err != nil {
+			span.RecordError(err)
+		}
+		if status >= 500 {
+			span.SetStatus(codes.Error, fmt.Sprintf("HTTP %d", status))
+		}
+
+		log.Printf("[REQ] id=%s trace=%s %s %s status=%d dur=%s",
+			id, traceID, c.Method(), redactURL(c.OriginalURL()), status, dur)
+		return err
+	}
+}
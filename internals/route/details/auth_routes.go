@@ -11,4 +11,9 @@ func AuthRoutes(app *fiber.App, db *gorm.DB) {
 
 	authRoute.AuthRoutes(app, db)
 
-}
\ No newline at end of file
+}
+
+func AuthAdminRoutes(r fiber.Router, db *gorm.DB) {
+	authRoute.AuthSessionAdminRoutes(r, db)
+	authRoute.AuthMFAAdminRoutes(r, db)
+}
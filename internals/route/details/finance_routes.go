@@ -5,6 +5,7 @@ import (
 	BillingRoute "madinahsalam_backend/internals/features/finance/billings/routes"
 	GeneralBillingRoute "madinahsalam_backend/internals/features/finance/general_billings/route"
 	PaymentRoute "madinahsalam_backend/internals/features/finance/payments/route" // ⬅️ pastikan paketnya "router"
+	SchoolSubscriptionRoute "madinahsalam_backend/internals/features/finance/school_subscriptions/route"
 
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
@@ -20,6 +21,11 @@ func FinanceAdminRoutes(r fiber.Router, db *gorm.DB, midtransServerKey string, u
 	GeneralBillingRoute.AdminGeneralBillingRoutes(r, db)
 	PaymentRoute.PaymentAdminRoutes(r, db, midtransServerKey, useProd) // ✅ pass 4 args
 	BillingRoute.BillingsAdminRoutes(r, db)
+	SchoolSubscriptionRoute.SchoolSubscriptionAdminRoutes(r, db)
+}
+
+func FinanceOwnerRoutes(r fiber.Router, db *gorm.DB) {
+	SchoolSubscriptionRoute.SchoolSubscriptionOwnerRoutes(r, db)
 }
 
 func FinanceUserRoutes(r fiber.Router, db *gorm.DB) {
@@ -12,6 +12,16 @@ import (
 
 	LembagaSchoolTeacher "madinahsalam_backend/internals/features/lembaga/school_yayasans/teachers_students/route"
 
+	StudentTransferRoutes "madinahsalam_backend/internals/features/lembaga/school_yayasans/student_transfers/route"
+
+	ImportRoutes "madinahsalam_backend/internals/features/lembaga/school_yayasans/imports/route"
+
+	AuditLogRoutes "madinahsalam_backend/internals/features/lembaga/audit_logs/route"
+
+	APIKeyRoutes "madinahsalam_backend/internals/features/lembaga/api_keys/route"
+	PermissionRoutes "madinahsalam_backend/internals/features/lembaga/permissions/route"
+	WebhookRoutes "madinahsalam_backend/internals/features/lembaga/webhooks/route"
+
 	// Tambahkan import route lain di sini saat modul siap:
 	// SectionRoutes "madinahsalam_backend/internals/features/lembaga/sections/main/route"
 	// StudentRoutes "madinahsalam_backend/internals/features/lembaga/students/main/route"
@@ -37,6 +47,7 @@ func LembagaUserRoutes(r fiber.Router, db *gorm.DB) {
 	ScheduleRoutes.ScheduleUserRoutes(r, db)
 	LembagaSchoolTeacher.LembagaTeacherStudentUserRoutes(r, db)
 	LembagaRoutes.LembagaUserRoutes(r, db)
+	PermissionRoutes.PermissionUserRoutes(r, db)
 
 }
 
@@ -45,10 +56,17 @@ func LembagaUserRoutes(r fiber.Router, db *gorm.DB) {
 func LembagaAdminRoutes(r fiber.Router, db *gorm.DB) {
 	LembagaRoutes.SchoolAdminRoutes(r, db)
 	LembagaSchoolTeacher.LembagaTeacherStudentAdminRoutes(r, db)
+	StudentTransferRoutes.StudentTransferAdminRoutes(r, db)
+	ImportRoutes.ImportJobAdminRoutes(r, db)
+	AuditLogRoutes.AuditLogAdminRoutes(r, db)
+	PermissionRoutes.PermissionAdminRoutes(r, db)
+	WebhookRoutes.WebhookAdminRoutes(r, db)
+	APIKeyRoutes.APIKeyAdminRoutes(r, db)
 }
 
 /* ===================== SUPER ADMIN ===================== */
 // Endpoint khusus super admin (token + guard super admin)
 func LembagaOwnerRoutes(r fiber.Router, db *gorm.DB) {
 	LembagaRoutes.SchoolOwnerRoutes(r, db)
+	AuditLogRoutes.AuditLogOwnerRoutes(r, db)
 }
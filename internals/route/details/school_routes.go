@@ -12,15 +12,19 @@ import (
 	// CertificateRoutes "madinahsalam_backend/internals/features/school/academics/certificates/route"
 	RoomsRoutes "madinahsalam_backend/internals/features/school/academics/rooms/route"
 	SubjectRoutes "madinahsalam_backend/internals/features/school/academics/subjects/route"
+	AttendanceDeviceRoutes "madinahsalam_backend/internals/features/school/class_others/attendance_devices/route"
 	ClassAttendanceSessionsRoutes "madinahsalam_backend/internals/features/school/class_others/class_attendance_sessions/route"
 	EventRoutes "madinahsalam_backend/internals/features/school/class_others/class_events/route"
 	ScheduleRoutes "madinahsalam_backend/internals/features/school/class_others/class_schedules/route"
+	TahfidzRoutes "madinahsalam_backend/internals/features/school/class_others/tahfidz/route"
 	ClassSectionsRoutes "madinahsalam_backend/internals/features/school/classes/class_sections/route"
 	ClassesRoutes "madinahsalam_backend/internals/features/school/classes/classes/route"
 	AttendanceSettingsRoute "madinahsalam_backend/internals/features/school/others/assesments_settings/route"
 	AssessmentsRoutes "madinahsalam_backend/internals/features/school/submissions_assesments/assesments/route"
 	QuizzesRoutes "madinahsalam_backend/internals/features/school/submissions_assesments/quizzes/route"
 	SubmissionsRoutes "madinahsalam_backend/internals/features/school/submissions_assesments/submissions/route"
+	RubricRoutes "madinahsalam_backend/internals/features/school/submissions_assesments/rubrics/route"
+	QuestionBankRoutes "madinahsalam_backend/internals/features/school/submissions_assesments/question_bank/route"
 
 	CSSTRoutes "madinahsalam_backend/internals/features/school/classes/class_section_subject_teachers/route"
 
@@ -65,11 +69,14 @@ func SchoolUserRoutes(r fiber.Router, db *gorm.DB) {
 	SemesterStatsRoutes.UserClassAttendanceSemesterUserRoutes(r, db)
 	ClassSectionsRoutes.ClassSectionUserRoutes(r, db)
 	ClassAttendanceSessionsRoutes.AttendanceSessionsTeacherRoutes(r, db)
+	TahfidzRoutes.TahfidzUserRoutes(r, db)
 
 	// CertificateRoutes.CertificateUserRoutes(r, db)
 	AssessmentsRoutes.AssessmentUserRoutes(r, db)
 	AssessmentsRoutes.AssessmentTeacherRoutes(r, db)
 	SubmissionsRoutes.SubmissionUserRoutes(r, db)
+	RubricRoutes.RubricUserRoutes(r, db)
+	QuestionBankRoutes.QuestionBankUserRoutes(r, db)
 	QuizzesRoutes.QuizzesTeacherRoutes(r, db)
 	QuizzesRoutes.QuizzesUserRoutes(r, db)
 	AcademicYearRoutes.AcademicUserTermsRoutes(r, db)
@@ -80,6 +87,9 @@ func SchoolUserRoutes(r fiber.Router, db *gorm.DB) {
 	CSSTRoutes.CSSTUserRoutes(r, db)
 
 	ClassParentRoutes.ClassParentUserRoutes(r, db)
+
+	// Dashboard konsolidasi yayasan (scope yayasan_admin)
+	YayasanRoutes.YayasanUserRoutes(r, db)
 }
 
 /* ===================== ADMIN ===================== */
@@ -98,9 +108,13 @@ func SchoolAdminRoutes(r fiber.Router, db *gorm.DB) {
 	RoomsRoutes.RoomsAdminRoutes(r, db)
 	ScheduleRoutes.ScheduleAdminRoutes(r, db)
 	ClassAttendanceSessionsRoutes.AttendanceSessionsAdminRoutes(r, db)
+	AttendanceDeviceRoutes.AttendanceDeviceAdminRoutes(r, db)
+	TahfidzRoutes.TahfidzAdminRoutes(r, db)
 	// CertificateRoutes.CertificateAdminRoutes(r, db)
 	AssessmentsRoutes.AssessmentAdminRoutes(r, db)
 	SubmissionsRoutes.SubmissionAdminRoutes(r, db)
+	RubricRoutes.RubricAdminRoutes(r, db)
+	QuestionBankRoutes.QuestionBankAdminRoutes(r, db)
 	QuizzesRoutes.QuizzesAdminRoutes(r, db)
 	EventRoutes.EventAdminRoutes(r, db)
 	CSSTRoutes.CSSTAdminRoutes(r, db)
@@ -108,8 +122,14 @@ func SchoolAdminRoutes(r fiber.Router, db *gorm.DB) {
 	ClassParentRoutes.ClassParentAdminRoutes(r, db)
 }
 
+/* ===================== DEVICE ===================== */
+// Endpoint mesin absen (ADMS push) — tanpa JWT, di root app
+func SchoolDeviceRoutes(app fiber.Router, db *gorm.DB) {
+	AttendanceDeviceRoutes.AttendanceDeviceADMSRoutes(app, db)
+}
+
 /* ===================== SUPER ADMIN ===================== */
 // Endpoint khusus super admin (token + guard super admin)
 func SchoolOwnerRoutes(r fiber.Router, db *gorm.DB) {
-
+	YayasanRoutes.YayasanOwnerRoutes(r, db)
 }
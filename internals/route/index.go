@@ -10,6 +10,8 @@ import (
 	schoolkuMiddleware "madinahsalam_backend/internals/middlewares/auth_school"
 	featuresMiddleware "madinahsalam_backend/internals/middlewares/features"
 
+	permsvc "madinahsalam_backend/internals/features/lembaga/permissions/service"
+
 	routeDetails "madinahsalam_backend/internals/route/details"
 
 	"github.com/gofiber/fiber/v2"
@@ -21,6 +23,9 @@ var startTime time.Time
 func SetupRoutes(app *fiber.App, db *gorm.DB) {
 	startTime = time.Now()
 
+	// permission matrix (RequirePermission / RequireAdminOrPermission) butuh DB
+	permsvc.Init(db)
+
 	// ===================== AUTH / USER BASE =====================
 	log.Println("[INFO] Setting up AuthRoutes...")
 	routeDetails.AuthRoutes(app, db)
@@ -28,6 +33,9 @@ func SetupRoutes(app *fiber.App, db *gorm.DB) {
 	log.Println("[INFO] Setting up UserRoutes...")
 	routeDetails.UserRoutes(app, db)
 
+	// mesin absen (ADMS) push ke /iclock di root, autentikasi via SN + token push per mesin
+	routeDetails.SchoolDeviceRoutes(app, db)
+
 	// ===================== GROUPS =====================
 
 	// PUBLIC → JWT opsional
@@ -40,7 +48,9 @@ func SetupRoutes(app *fiber.App, db *gorm.DB) {
 		schoolkuMiddleware.AuthJWT(schoolkuMiddleware.AuthJWTOpts{
 			Secret:              os.Getenv("JWT_SECRET"),
 			AllowCookieFallback: true,
+			SessionChecker:      schoolkuMiddleware.SessionRevokedChecker(db),
 		}),
+		featuresMiddleware.AuditContext(),
 	)
 
 	log.Println("[INFO] Setting up PRIVATE (scoped) group...")
@@ -48,19 +58,27 @@ func SetupRoutes(app *fiber.App, db *gorm.DB) {
 		schoolkuMiddleware.AuthJWT(schoolkuMiddleware.AuthJWTOpts{
 			Secret:              os.Getenv("JWT_SECRET"),
 			AllowCookieFallback: true,
+			SessionChecker:      schoolkuMiddleware.SessionRevokedChecker(db),
 		}),
+		// sekolah lapse read_only → tulis via /api/u juga diblok (bukan cuma /api/a)
+		featuresMiddleware.RequireWritableSubscription(db),
+		featuresMiddleware.AuditContext(),
 	)
 
 	// ===================== ADMIN (per school) =====================
 	log.Println("[INFO] Setting up ADMIN group (Auth + Scope + RoleCheck)...")
 	admin := app.Group("/api/a",
-		schoolkuMiddleware.AuthJWT(schoolkuMiddleware.AuthJWTOpts{
+		// JWT user atau API key sekolah (X-API-Key / Bearer msk_...)
+		schoolkuMiddleware.AuthJWTOrAPIKey(schoolkuMiddleware.AuthJWTOpts{
 			Secret:              os.Getenv("JWT_SECRET"),
 			AllowCookieFallback: true,
-		}),
+			SessionChecker:      schoolkuMiddleware.SessionRevokedChecker(db),
+		}, db),
 		featuresMiddleware.UseSchoolScope(),
 		featuresMiddleware.RequirePathScopeMatch(),
-		featuresMiddleware.IsSchoolAdmin(),
+		featuresMiddleware.RequireAdminOrPermission(), // admin/dkm/owner; role lain via permission matrix
+		featuresMiddleware.RequireWritableSubscription(db),
+		featuresMiddleware.AuditContext(),
 	)
 
 	// ===================== OWNER (GLOBAL) =====================
@@ -69,8 +87,10 @@ func SetupRoutes(app *fiber.App, db *gorm.DB) {
 		schoolkuMiddleware.AuthJWT(schoolkuMiddleware.AuthJWTOpts{
 			Secret:              os.Getenv("JWT_SECRET"),
 			AllowCookieFallback: true,
+			SessionChecker:      schoolkuMiddleware.SessionRevokedChecker(db),
 		}),
 		featuresMiddleware.IsOwnerGlobal(),
+		featuresMiddleware.AuditContext(),
 	)
 
 	// ===== Midtrans config (dipass ke FinanceAdminRoutes) =====
@@ -93,6 +113,9 @@ func SetupRoutes(app *fiber.App, db *gorm.DB) {
 	routeDetails.LembagaAdminRoutes(admin, db)
 	routeDetails.LembagaOwnerRoutes(owner, db)
 
+	log.Println("[INFO] Mounting Auth admin routes...")
+	routeDetails.AuthAdminRoutes(admin, db)
+
 	// 🔓 Mount route JOIN GLOBAL (tanpa school_id) KE privateLoose
 	routeDetails.ClassSectionUserGlobalRoutes(privateLoose, db)
 
@@ -101,6 +124,7 @@ func SetupRoutes(app *fiber.App, db *gorm.DB) {
 	// routeDetails.FinanceUserRoutes(privateScoped, db)
 	routeDetails.FinanceAdminRoutes(admin, db, midtransServerKey, useMidtransProd) // ⬅️ FIX: pass 4 argumen
 	routeDetails.FinanceUserRoutes(privateScoped, db)
+	routeDetails.FinanceOwnerRoutes(owner, db)
 
 	log.Println("[INFO] Mounting School routes...")
 	routeDetails.SchoolPublicRoutes(public, db)
@@ -1,12 +1,14 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 	"strings"
 
 	"madinahsalam_backend/internals/configs"
 	"madinahsalam_backend/internals/seeds"
+	"madinahsalam_backend/internals/seeds/demo"
 
 	// users "madinahsalam_backend/internals/seeds/users/auth"
 	survey "madinahsalam_backend/internals/seeds/users/surveys/survey_questions"
@@ -35,6 +37,16 @@ func main() {
 
 	case "schools":
 		// schools.SeedSchoolsFromJSON(db, "internals/seeds/schools/school/data_schools.json")
+	case "demo":
+		opt, err := demo.ParseArgs(os.Args[2:])
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		stats, err := demo.Run(context.Background(), db, opt)
+		if err != nil {
+			log.Fatalf("❌ Seed demo gagal: %v", err)
+		}
+		stats.Print()
 	default:
 		log.Fatalf("❌ Argumen '%s' tidak dikenali", os.Args[1])
 	}
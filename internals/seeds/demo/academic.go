@@ -0,0 +1,501 @@
+// internals/seeds/demo/academic.go
+package demo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	termModel "madinahsalam_backend/internals/features/school/academics/academic_terms/model"
+	subjectModel "madinahsalam_backend/internals/features/school/academics/subjects/model"
+	parentModel "madinahsalam_backend/internals/features/school/classes/class_parents/model"
+	csstModel "madinahsalam_backend/internals/features/school/classes/class_section_subject_teachers/model"
+	sectionModel "madinahsalam_backend/internals/features/school/classes/class_sections/model"
+	classModel "madinahsalam_backend/internals/features/school/classes/classes/model"
+)
+
+// Ukuran rombel (kapasitas section) yang dipakai generator.
+const sectionSize = 28
+
+type termRow struct {
+	ID         uuid.UUID
+	Name, Slug string
+	Year       string // "2025/2026"
+	Angkatan   int
+	Start, End time.Time
+}
+
+type parentRow struct {
+	ID               uuid.UUID
+	Name, Code, Slug string
+	Level            int16
+}
+
+type classRow struct {
+	ID         uuid.UUID
+	Name, Slug string
+	ParentIdx  int
+}
+
+type sectionRow struct {
+	ID               uuid.UUID
+	Name, Slug, Code string
+	ClassIdx         int
+	ParentIdx        int
+	HomeroomIdx      int
+	Students         []int // index ke sc.students
+}
+
+type subjectRow struct {
+	ID               uuid.UUID
+	Code, Name, Slug string
+	Hours            int
+	ClassSubjectIDs  []uuid.UUID // per parentIdx
+	Teachers         []int       // pool guru mapel (index ke sc.teachers)
+}
+
+type csstRow struct {
+	ID         uuid.UUID
+	Slug       string
+	SectionIdx int
+	SubjectIdx int
+	TeacherIdx int
+	MinPass    int
+}
+
+/* =========================================================
+   Terms: semester aktif (memuat today) + semester sebelumnya
+========================================================= */
+
+func semesterOf(t time.Time) termRow {
+	loc := t.Location()
+	y := t.Year()
+	if t.Month() >= time.July {
+		return termRow{
+			Name:     "Ganjil",
+			Year:     fmt.Sprintf("%d/%d", y, y+1),
+			Angkatan: y,
+			Start:    time.Date(y, time.July, 1, 0, 0, 0, 0, loc),
+			End:      time.Date(y, time.December, 31, 0, 0, 0, 0, loc),
+		}
+	}
+	return termRow{
+		Name:     "Genap",
+		Year:     fmt.Sprintf("%d/%d", y-1, y),
+		Angkatan: y - 1,
+		Start:    time.Date(y, time.January, 1, 0, 0, 0, 0, loc),
+		End:      time.Date(y, time.June, 30, 0, 0, 0, 0, loc),
+	}
+}
+
+func (g *gen) seedTerms(ctx context.Context, sc *schoolCtx) error {
+	cur := semesterOf(g.opt.Today)
+	prev := semesterOf(cur.Start.AddDate(0, 0, -1))
+
+	for _, t := range []*termRow{&prev, &cur} {
+		t.ID = g.id()
+		t.Slug = slugify(t.Year + "-" + t.Name)
+		code := strings.ReplaceAll(t.Year, "/", "") + "-" + strings.ToUpper(t.Name[:2])
+		m := &termModel.AcademicTermModel{
+			AcademicTermID:           t.ID,
+			AcademicTermSchoolID:     sc.ID,
+			AcademicTermAcademicYear: t.Year,
+			AcademicTermName:         t.Name,
+			AcademicTermStartDate:    t.Start,
+			AcademicTermEndDate:      t.End,
+			AcademicTermIsActive:     t == &cur,
+			AcademicTermCode:         &code,
+			AcademicTermSlug:         &t.Slug,
+			AcademicTermAngkatan:     &t.Angkatan,
+		}
+		// is_active punya default:true → false harus dikirim eksplisit
+		if err := g.tx.WithContext(ctx).Create(m).Error; err != nil {
+			return err
+		}
+		if !m.AcademicTermIsActive {
+			if err := g.tx.WithContext(ctx).Model(m).Update("academic_term_is_active", false).Error; err != nil {
+				return err
+			}
+		}
+	}
+	sc.term, sc.prevTerm = cur, prev
+	return nil
+}
+
+/* =========================================================
+   Class parents → classes → sections
+========================================================= */
+
+// studentsPerLevel: sebar --students merata ke semua tingkat.
+func (g *gen) studentsPerLevel(sc *schoolCtx) []int {
+	n := len(sc.levels)
+	out := make([]int, n)
+	for i := range out {
+		out[i] = g.opt.Students / n
+		if i < g.opt.Students%n {
+			out[i]++
+		}
+	}
+	return out
+}
+
+func (g *gen) sectionCount(sc *schoolCtx) int {
+	total := 0
+	for _, n := range g.studentsPerLevel(sc) {
+		total += (n + sectionSize - 1) / sectionSize
+	}
+	return total
+}
+
+func (g *gen) seedClasses(ctx context.Context, sc *schoolCtx) error {
+	tx := g.tx.WithContext(ctx)
+	perLevel := g.studentsPerLevel(sc)
+	angkatan := fmt.Sprint(sc.term.Angkatan)
+	homeroom := 0
+
+	for li, level := range sc.levels {
+		lv := level
+		p := parentRow{
+			ID:    g.id(),
+			Name:  fmt.Sprintf("Kelas %d", level),
+			Code:  fmt.Sprintf("K%d", level),
+			Slug:  fmt.Sprintf("kelas-%d", level),
+			Level: level,
+		}
+		if err := tx.Create(&parentModel.ClassParentModel{
+			ClassParentID:       p.ID,
+			ClassParentSchoolID: sc.ID,
+			ClassParentName:     p.Name,
+			ClassParentCode:     &p.Code,
+			ClassParentSlug:     &p.Slug,
+			ClassParentLevel:    &lv,
+			ClassParentIsActive: true,
+		}).Error; err != nil {
+			return err
+		}
+		sc.parents = append(sc.parents, p)
+
+		c := classRow{
+			ID:        g.id(),
+			Name:      fmt.Sprintf("%s %s %s", p.Name, sc.term.Name, sc.term.Year),
+			Slug:      p.Slug + "-" + sc.term.Slug,
+			ParentIdx: li,
+		}
+		quota := perLevel[li]
+		start, end := sc.term.Start, sc.term.End
+		if err := tx.Create(&classModel.ClassModel{
+			ClassID:                            c.ID,
+			ClassSchoolID:                      sc.ID,
+			ClassName:                          &c.Name,
+			ClassSlug:                          c.Slug,
+			ClassStartDate:                     &start,
+			ClassEndDate:                       &end,
+			ClassQuotaTotal:                    &quota,
+			ClassStatus:                        "active",
+			ClassClassParentID:                 p.ID,
+			ClassClassParentCodeCache:          &p.Code,
+			ClassClassParentNameCache:          &p.Name,
+			ClassClassParentSlugCache:          &p.Slug,
+			ClassClassParentLevelCache:         &lv,
+			ClassAcademicTermID:                &sc.term.ID,
+			ClassAcademicTermAcademicYearCache: &sc.term.Year,
+			ClassAcademicTermNameCache:         &sc.term.Name,
+			ClassAcademicTermSlugCache:         &sc.term.Slug,
+			ClassAcademicTermAngkatanCache:     &angkatan,
+		}).Error; err != nil {
+			return err
+		}
+		sc.classes = append(sc.classes, c)
+		ci := len(sc.classes) - 1
+
+		nSec := (perLevel[li] + sectionSize - 1) / sectionSize
+		for k := 0; k < nSec; k++ {
+			letter := string(rune('A' + k))
+			s := sectionRow{
+				ID:          g.id(),
+				Name:        fmt.Sprintf("%d%s", level, letter),
+				Slug:        fmt.Sprintf("%s-%s", p.Slug, strings.ToLower(letter)),
+				Code:        fmt.Sprintf("%d%s", level, letter),
+				ClassIdx:    ci,
+				ParentIdx:   li,
+				HomeroomIdx: homeroom % len(sc.teachers),
+			}
+			homeroom++
+			t := sc.teachers[s.HomeroomIdx]
+			q := sectionSize
+			if err := tx.Create(&sectionModel.ClassSectionModel{
+				ClassSectionID:                            s.ID,
+				ClassSectionSchoolID:                      sc.ID,
+				ClassSectionSlug:                          s.Slug,
+				ClassSectionName:                          s.Name,
+				ClassSectionCode:                          &s.Code,
+				ClassSectionQuotaTotal:                    &q,
+				ClassSectionClassID:                       &c.ID,
+				ClassSectionClassNameCache:                &c.Name,
+				ClassSectionClassSlugCache:                &c.Slug,
+				ClassSectionClassParentID:                 &p.ID,
+				ClassSectionClassParentNameCache:          &p.Name,
+				ClassSectionClassParentSlugCache:          &p.Slug,
+				ClassSectionClassParentLevelCache:         &lv,
+				ClassSectionSchoolTeacherID:               &t.ID,
+				ClassSectionSchoolTeacherSlugCache:        &t.Slug,
+				ClassSectionAcademicTermID:                &sc.term.ID,
+				ClassSectionAcademicTermNameCache:         &sc.term.Name,
+				ClassSectionAcademicTermSlugCache:         &sc.term.Slug,
+				ClassSectionAcademicTermAcademicYearCache: &sc.term.Year,
+				ClassSectionAcademicTermAngkatanCache:     &sc.term.Angkatan,
+				ClassSectionStatus:                        sectionModel.ClassStatusActive,
+			}).Error; err != nil {
+				return err
+			}
+			sc.sections = append(sc.sections, s)
+		}
+	}
+	g.stats.Sections += len(sc.sections)
+	return nil
+}
+
+// recountClasses: isi counter section/class/parent/term dari data in-memory.
+func (g *gen) recountClasses(ctx context.Context, sc *schoolCtx) error {
+	tx := g.tx.WithContext(ctx)
+	type cnt struct{ total, male, female, sections int }
+	byClass := make([]cnt, len(sc.classes))
+
+	for _, s := range sc.sections {
+		var c cnt
+		for _, si := range s.Students {
+			c.total++
+			if sc.students[si].Female {
+				c.female++
+			} else {
+				c.male++
+			}
+		}
+		if err := tx.Model(&sectionModel.ClassSectionModel{}).
+			Where("class_section_id = ?", s.ID).
+			Updates(map[string]any{
+				"class_section_quota_taken":                  c.total,
+				"class_section_total_students_active":        c.total,
+				"class_section_total_students_male":          c.male,
+				"class_section_total_students_female":        c.female,
+				"class_section_total_students_male_active":   c.male,
+				"class_section_total_students_female_active": c.female,
+			}).Error; err != nil {
+			return err
+		}
+		b := &byClass[s.ClassIdx]
+		b.total += c.total
+		b.male += c.male
+		b.female += c.female
+		b.sections++
+	}
+
+	for i, c := range sc.classes {
+		b := byClass[i]
+		if err := tx.Model(&classModel.ClassModel{}).
+			Where("class_id = ?", c.ID).
+			Updates(map[string]any{
+				"class_quota_taken":                 b.total,
+				"class_class_section_count":         b.sections,
+				"class_class_section_active_count":  b.sections,
+				"class_student_count":               b.total,
+				"class_student_active_count":        b.total,
+				"class_student_male_count":          b.male,
+				"class_student_male_active_count":   b.male,
+				"class_student_female_count":        b.female,
+				"class_student_female_active_count": b.female,
+			}).Error; err != nil {
+			return err
+		}
+		// 1 class per parent di demo → counter parent = counter class
+		if err := tx.Model(&parentModel.ClassParentModel{}).
+			Where("class_parent_id = ?", sc.parents[c.ParentIdx].ID).
+			Updates(map[string]any{
+				"class_parent_class_count":                 1,
+				"class_parent_class_active_count":          1,
+				"class_parent_class_section_count":         b.sections,
+				"class_parent_class_section_active_count":  b.sections,
+				"class_parent_student_count":               b.total,
+				"class_parent_student_active_count":        b.total,
+				"class_parent_student_male_count":          b.male,
+				"class_parent_student_male_active_count":   b.male,
+				"class_parent_student_female_count":        b.female,
+				"class_parent_student_female_active_count": b.female,
+			}).Error; err != nil {
+			return err
+		}
+	}
+
+	var male, female int
+	for _, st := range sc.students {
+		if st.Female {
+			female++
+		} else {
+			male++
+		}
+	}
+	return tx.Model(&termModel.AcademicTermModel{}).
+		Where("academic_term_id = ?", sc.term.ID).
+		Updates(map[string]any{
+			"academic_term_class_count":                 len(sc.classes),
+			"academic_term_class_active_count":          len(sc.classes),
+			"academic_term_class_section_count":         len(sc.sections),
+			"academic_term_class_section_active_count":  len(sc.sections),
+			"academic_term_student_count":               len(sc.students),
+			"academic_term_student_active_count":        len(sc.students),
+			"academic_term_student_male_count":          male,
+			"academic_term_student_male_active_count":   male,
+			"academic_term_student_female_count":        female,
+			"academic_term_student_female_active_count": female,
+			"academic_term_teacher_count":               len(sc.teachers),
+			"academic_term_teacher_active_count":        len(sc.teachers),
+		}).Error
+}
+
+/* =========================================================
+   Subjects + class_subjects
+========================================================= */
+
+func (g *gen) seedSubjects(ctx context.Context, sc *schoolCtx) error {
+	tx := g.tx.WithContext(ctx)
+	for si := range sc.subjects {
+		sub := &sc.subjects[si]
+		if err := tx.Create(&subjectModel.SubjectModel{
+			SubjectID:       sub.ID,
+			SubjectSchoolID: sc.ID,
+			SubjectCode:     sub.Code,
+			SubjectName:     sub.Name,
+			SubjectSlug:     sub.Slug,
+			SubjectIsActive: true,
+		}).Error; err != nil {
+			return err
+		}
+
+		sub.ClassSubjectIDs = make([]uuid.UUID, len(sc.parents))
+		for pi, p := range sc.parents {
+			id := g.id()
+			slug := p.Slug + "-" + sub.Slug
+			order, hours, minPass := si+1, sub.Hours, 70
+			lv := p.Level
+			if err := tx.Create(&subjectModel.ClassSubjectModel{
+				ClassSubjectID:                    id,
+				ClassSubjectSchoolID:              sc.ID,
+				ClassSubjectClassParentID:         p.ID,
+				ClassSubjectSubjectID:             sub.ID,
+				ClassSubjectSlug:                  &slug,
+				ClassSubjectOrderIndex:            &order,
+				ClassSubjectHoursPerWeek:          &hours,
+				ClassSubjectMinPassingScore:       &minPass,
+				ClassSubjectIsCore:                true,
+				ClassSubjectIsActive:              true,
+				ClassSubjectSubjectNameCache:      &sub.Name,
+				ClassSubjectSubjectCodeCache:      &sub.Code,
+				ClassSubjectSubjectSlugCache:      &sub.Slug,
+				ClassSubjectClassParentCodeCache:  &p.Code,
+				ClassSubjectClassParentSlugCache:  &p.Slug,
+				ClassSubjectClassParentLevelCache: &lv,
+				ClassSubjectClassParentNameCache:  &p.Name,
+			}).Error; err != nil {
+				return err
+			}
+			sub.ClassSubjectIDs[pi] = id
+		}
+	}
+	return nil
+}
+
+/* =========================================================
+   CSST (section × mapel) + enrolment siswa
+========================================================= */
+
+func (g *gen) seedCSSTs(ctx context.Context, sc *schoolCtx) error {
+	tx := g.tx.WithContext(ctx)
+	var rows []*csstModel.StudentClassSectionSubjectTeacherModel
+	from := sc.term.Start
+
+	for seci, sec := range sc.sections {
+		for subi, sub := range sc.subjects {
+			ti := sub.Teachers[seci%len(sub.Teachers)]
+			t := sc.teachers[ti]
+			c := csstRow{
+				ID:         g.id(),
+				Slug:       sec.Slug + "-" + strings.ToLower(sub.Code),
+				SectionIdx: seci,
+				SubjectIdx: subi,
+				TeacherIdx: ti,
+				MinPass:    70,
+			}
+			quota := sectionSize
+			meetings := sub.Hours * 18
+			if err := tx.Create(&csstModel.ClassSectionSubjectTeacherModel{
+				CSSTID:                               c.ID,
+				CSSTSchoolID:                         sc.ID,
+				CSSTSlug:                             &c.Slug,
+				CSSTTotalMeetingsTarget:              &meetings,
+				CSSTQuotaTotal:                       &quota,
+				CSSTQuotaTaken:                       len(sec.Students),
+				CSSTClassSectionID:                   sec.ID,
+				CSSTClassSectionSlugCache:            &sec.Slug,
+				CSSTClassSectionNameCache:            &sec.Name,
+				CSSTClassSectionCodeCache:            &sec.Code,
+				CSSTSchoolTeacherID:                  &t.ID,
+				CSSTSchoolTeacherSlugCache:           &t.Slug,
+				CSSTClassSubjectID:                   sub.ClassSubjectIDs[sec.ParentIdx],
+				CSSTSubjectID:                        &sub.ID,
+				CSSTSubjectNameCache:                 &sub.Name,
+				CSSTSubjectCodeCache:                 &sub.Code,
+				CSSTSubjectSlugCache:                 &sub.Slug,
+				CSSTAcademicTermID:                   &sc.term.ID,
+				CSSTAcademicTermNameCache:            &sc.term.Name,
+				CSSTAcademicTermSlugCache:            &sc.term.Slug,
+				CSSTAcademicYearCache:                &sc.term.Year,
+				CSSTAcademicTermAngkatanCache:        &sc.term.Angkatan,
+				CSSTMinPassingScoreClassSubjectCache: &c.MinPass,
+			}).Error; err != nil {
+				return err
+			}
+			sc.csts = append(sc.csts, c)
+
+			for _, si := range sec.Students {
+				st := &sc.students[si]
+				rows = append(rows, &csstModel.StudentClassSectionSubjectTeacherModel{
+					StudentCSSTID:                          g.id(),
+					StudentCSSTSchoolID:                    sc.ID,
+					StudentCSSTStudentID:                   st.ID,
+					StudentCSSTCSSTID:                      c.ID,
+					StudentCSSTIsActive:                    true,
+					StudentCSSTFrom:                        &from,
+					StudentCSSTUserProfileNameCache:        &st.Name,
+					StudentCSSTUserProfileParentNameCache:  &st.ParentName,
+					StudentCSSTUserProfileParentWAURLCache: &st.ParentWA,
+					StudentCSSTUserProfileGenderCache:      ptr(st.gender()),
+					StudentCSSTSchoolStudentCodeCache:      &st.Code,
+				})
+			}
+		}
+		if err := tx.Model(&sectionModel.ClassSectionModel{}).
+			Where("class_section_id = ?", sec.ID).
+			Updates(map[string]any{
+				"class_section_total_class_class_section_subject_teachers":        len(sc.subjects),
+				"class_section_total_class_class_section_subject_teachers_active": len(sc.subjects),
+			}).Error; err != nil {
+			return err
+		}
+	}
+	g.stats.CSSTs += len(sc.csts)
+	return tx.CreateInBatches(rows, batchSize).Error
+}
+
+// cstsOfSection: index CSST milik 1 section (urut mapel).
+func (sc *schoolCtx) cstsOfSection(seci int) []int {
+	var out []int
+	for i, c := range sc.csts {
+		if c.SectionIdx == seci {
+			out = append(out, i)
+		}
+	}
+	return out
+}
@@ -0,0 +1,203 @@
+// internals/seeds/demo/assessment.go
+package demo
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+
+	csstModel "madinahsalam_backend/internals/features/school/classes/class_section_subject_teachers/model"
+	asmModel "madinahsalam_backend/internals/features/school/submissions_assesments/assesments/model"
+	subModel "madinahsalam_backend/internals/features/school/submissions_assesments/submissions/model"
+)
+
+/* =========================================================
+   Assessment types + assessments per CSST + submissions
+========================================================= */
+
+type asmTypeSeed struct {
+	Key      string
+	Name     string
+	Category asmModel.AssessmentTypeCategory
+	Weight   float64
+	Kind     asmModel.AssessmentKind
+}
+
+var asmTypeSeeds = []asmTypeSeed{
+	{"tugas", "Tugas / Latihan", asmModel.AssessmentTypeCategoryTraining, 30, asmModel.AssessmentKindAssignmentUpload},
+	{"uh", "Ulangan Harian", asmModel.AssessmentTypeCategoryDailyExam, 30, asmModel.AssessmentKindOffline},
+	{"pts", "Penilaian Tengah Semester", asmModel.AssessmentTypeCategoryExam, 40, asmModel.AssessmentKindOffline},
+}
+
+// rencana per CSST: (index type, hari sebelum today; negatif = belum mulai)
+var asmPlan = []struct {
+	Type    int
+	DaysAgo int
+}{
+	{0, 30}, {0, 16}, {1, 9}, {0, -3},
+}
+
+func (g *gen) seedAssessments(ctx context.Context, sc *schoolCtx) error {
+	tx := g.tx.WithContext(ctx)
+	now := g.opt.Today
+
+	typeIDs := make([]uuid.UUID, len(asmTypeSeeds))
+	for i, t := range asmTypeSeeds {
+		typeIDs[i] = g.id()
+		if err := tx.Create(&asmModel.AssessmentTypeModel{
+			AssessmentTypeID:                      typeIDs[i],
+			AssessmentTypeSchoolID:                sc.ID,
+			AssessmentTypeKey:                     t.Key,
+			AssessmentTypeName:                    t.Name,
+			AssessmentTypeCategory:                string(t.Category),
+			AssessmentTypeWeightPercent:           t.Weight,
+			AssessmentTypeShowCorrectAfterSubmit:  true,
+			AssessmentTypeAttemptsAllowed:         1,
+			AssessmentTypeRequireLogin:            true,
+			AssessmentTypeIsActive:                true,
+			AssessmentTypeIsGraded:                true,
+			AssessmentTypeAllowLateSubmission:     t.Category == asmModel.AssessmentTypeCategoryTraining,
+			AssessmentTypeLatePenaltyPercent:      10,
+			AssessmentTypePassingScorePercent:     70,
+			AssessmentTypeScoreAggregationMode:    "latest",
+			AssessmentTypeShowScoreAfterSubmit:    true,
+			AssessmentTypeAllowReviewBeforeSubmit: true,
+			AssessmentTypeRequireCompleteAttempt:  true,
+			AssessmentTypeCreatedAt:               now,
+			AssessmentTypeUpdatedAt:               now,
+		}).Error; err != nil {
+			return err
+		}
+	}
+
+	var (
+		asms []*asmModel.AssessmentModel
+		subs []*subModel.SubmissionModel
+	)
+	for ci, c := range sc.csts {
+		sub := sc.subjects[c.SubjectIdx]
+		teacherID := sc.teachers[c.TeacherIdx].ID
+		byType := map[int]int{}
+		var counts [3]int
+
+		for _, plan := range asmPlan {
+			t := asmTypeSeeds[plan.Type]
+			start := g.opt.Today.AddDate(0, 0, -plan.DaysAgo).Add(7 * time.Hour)
+			if start.Before(sc.term.Start) {
+				continue
+			}
+			due := start.AddDate(0, 0, 7)
+			titles := assessmentTitles[string(t.Category)]
+			title := fmt.Sprintf("%s %s", titles[byType[plan.Type]%len(titles)], sub.Name)
+			byType[plan.Type]++
+			counts[plan.Type]++
+			slug := fmt.Sprintf("%s-%s-%d", c.Slug, t.Key, byType[plan.Type])
+			typeID := typeIDs[plan.Type]
+			startAt, dueAt := start, due
+
+			a := &asmModel.AssessmentModel{
+				AssessmentID:                                  g.id(),
+				AssessmentSchoolID:                            sc.ID,
+				AssessmentClassSectionSubjectTeacherID:        &sc.csts[ci].ID,
+				AssessmentTypeID:                              &typeID,
+				AssessmentTypeCategorySnapshot:                t.Category,
+				AssessmentSlug:                                &slug,
+				AssessmentTitle:                               title,
+				AssessmentStartAt:                             &startAt,
+				AssessmentDueAt:                               &dueAt,
+				AssessmentKind:                                t.Kind,
+				AssessmentStatus:                              asmModel.AssessmentStatusPublished,
+				AssessmentTotalAttemptsAllowed:                1,
+				AssessmentMaxScore:                            100,
+				AssessmentTypeIsGradedSnapshot:                true,
+				AssessmentAllowLateSubmissionSnapshot:         t.Category == asmModel.AssessmentTypeCategoryTraining,
+				AssessmentLatePenaltyPercentSnapshot:          10,
+				AssessmentMinPassingScoreClassSubjectSnapshot: float64(c.MinPass),
+				AssessmentCreatedByTeacherID:                  &teacherID,
+			}
+
+			if plan.DaysAgo > 0 {
+				for _, st := range sc.studentsOf(c.SectionIdx) {
+					s := g.submission(sc, a, st, teacherID, start, due)
+					if s == nil {
+						continue
+					}
+					a.AssessmentSubmissionsTotal++
+					if s.SubmissionStatus == subModel.SubmissionStatusGraded {
+						a.AssessmentSubmissionsGradedTotal++
+					}
+					subs = append(subs, s)
+				}
+			}
+			asms = append(asms, a)
+		}
+
+		if err := tx.Model(&csstModel.ClassSectionSubjectTeacherModel{}).
+			Where("csst_id = ?", c.ID).
+			Updates(map[string]any{
+				"csst_total_assessments":            counts[0] + counts[1] + counts[2],
+				"csst_total_assessments_training":   counts[0],
+				"csst_total_assessments_daily_exam": counts[1],
+				"csst_total_assessments_exam":       counts[2],
+			}).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := tx.CreateInBatches(asms, batchSize).Error; err != nil {
+		return err
+	}
+	if err := tx.CreateInBatches(subs, batchSize).Error; err != nil {
+		return err
+	}
+	g.stats.Assessments += len(asms)
+	g.stats.Submissions += len(subs)
+	return nil
+}
+
+// submission: ±92% mengumpulkan, ±85% dari yang masuk sudah dinilai.
+func (g *gen) submission(sc *schoolCtx, a *asmModel.AssessmentModel, st *studentRow, teacherID uuid.UUID, start, due time.Time) *subModel.SubmissionModel {
+	if !g.chance(0.70 + 0.25*st.Ability) {
+		return nil
+	}
+	window := due.Sub(start)
+	at := start.Add(time.Duration(g.rng.Int63n(int64(window))))
+	late := false
+	if a.AssessmentAllowLateSubmissionSnapshot && g.chance(0.05) {
+		at = due.Add(time.Duration(g.between(1, 48)) * time.Hour)
+		late = true
+	}
+	if at.After(g.opt.Today) {
+		at = g.opt.Today.Add(-time.Duration(g.between(1, 12)) * time.Hour)
+	}
+
+	s := &subModel.SubmissionModel{
+		SubmissionID:           g.id(),
+		SubmissionSchoolID:     sc.ID,
+		SubmissionAssessmentID: a.AssessmentID,
+		SubmissionStudentID:    st.ID,
+		SubmissionStatus:       subModel.SubmissionStatusSubmitted,
+		SubmissionSubmittedAt:  &at,
+		SubmissionIsLate:       late,
+	}
+	if g.chance(0.85) {
+		score := math.Round(g.normal(st.Ability*100, 9, 20, 100))
+		if late {
+			score = math.Round(score * 0.9)
+		}
+		graded := at.Add(time.Duration(g.between(2, 72)) * time.Hour)
+		if graded.After(g.opt.Today) {
+			graded = g.opt.Today
+		}
+		fb := g.pick(feedbackSamples)
+		s.SubmissionStatus = subModel.SubmissionStatusGraded
+		s.SubmissionScore = &score
+		s.SubmissionFeedback = &fb
+		s.SubmissionGradedByTeacherID = &teacherID
+		s.SubmissionGradedAt = &graded
+	}
+	return s
+}
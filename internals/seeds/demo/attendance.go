@@ -0,0 +1,288 @@
+// internals/seeds/demo/attendance.go
+package demo
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	sessModel "madinahsalam_backend/internals/features/school/class_others/class_attendance_sessions/model"
+	schedModel "madinahsalam_backend/internals/features/school/class_others/class_schedules/model"
+	schedService "madinahsalam_backend/internals/features/school/class_others/class_schedules/services"
+)
+
+/* =========================================================
+   Jadwal mingguan → sesi (Generator) → absensi sesi lampau
+========================================================= */
+
+const (
+	slotsPerDay  = 4
+	firstSlotMin = 7*60 + 30 // 07:30
+	slotLenMin   = 70
+	slotGapMin   = 5
+)
+
+func slotTimes(slot int) (schedModel.TimeOnly, schedModel.TimeOnly) {
+	start := firstSlotMin + slot*(slotLenMin+slotGapMin)
+	mk := func(m int) schedModel.TimeOnly {
+		return schedModel.TimeOnly{Time: time.Date(2000, 1, 1, m/60, m%60, 0, 0, time.Local)}
+	}
+	return mk(start), mk(start + slotLenMin)
+}
+
+func (g *gen) seedSchedulesAndAttendance(ctx context.Context, sc *schoolCtx) error {
+	tx := g.tx.WithContext(ctx)
+
+	start := g.opt.Today.AddDate(0, 0, -7*g.opt.PastWeeks)
+	if start.Before(sc.term.Start) {
+		start = sc.term.Start
+	}
+	end := g.opt.Today.AddDate(0, 0, 7*g.opt.NextWeeks)
+	if end.After(sc.term.End) {
+		end = sc.term.End
+	}
+	if end.Before(start) {
+		end = start
+	}
+
+	slug := "jadwal-" + sc.term.Slug
+	sch := &schedModel.ClassScheduleModel{
+		ClassScheduleID:        g.id(),
+		ClassScheduleSchoolID:  sc.ID,
+		ClassScheduleSlug:      &slug,
+		ClassScheduleStartDate: start,
+		ClassScheduleEndDate:   end,
+		ClassScheduleStatus:    schedModel.SessionStatusScheduled,
+		ClassScheduleIsActive:  true,
+	}
+	if err := tx.Create(sch).Error; err != nil {
+		return err
+	}
+
+	rules := g.weeklyRules(sc, sch.ClassScheduleID)
+	if err := tx.CreateInBatches(rules, batchSize).Error; err != nil {
+		return err
+	}
+
+	gen := &schedService.Generator{DB: g.tx}
+	if _, err := gen.GenerateSessionsForScheduleWithOpts(ctx, sch.ClassScheduleID.String(),
+		&schedService.GenerateOptions{TZName: "Asia/Jakarta"}); err != nil {
+		return err
+	}
+
+	return g.seedAttendance(ctx, sc, sch.ClassScheduleID)
+}
+
+// weeklyRules: tiap section dapat jam mapel (hours/minggu) di slot Senin–Jumat;
+// guru yang sama tidak dipasang di 2 section pada slot yang sama (kalau masih ada slot).
+func (g *gen) weeklyRules(sc *schoolCtx, scheduleID uuid.UUID) []*schedModel.ClassScheduleRuleModel {
+	const cells = 5 * slotsPerDay
+	busy := map[int]map[int]bool{} // teacherIdx → cell
+
+	var out []*schedModel.ClassScheduleRuleModel
+	for seci := range sc.sections {
+		var hours []int // index CSST, diulang sebanyak jam
+		for _, ci := range sc.cstsOfSection(seci) {
+			for h := 0; h < sc.subjects[sc.csts[ci].SubjectIdx].Hours; h++ {
+				hours = append(hours, ci)
+			}
+		}
+		g.rng.Shuffle(len(hours), func(i, j int) { hours[i], hours[j] = hours[j], hours[i] })
+
+		used := [cells]bool{}
+		for _, ci := range hours {
+			t := sc.csts[ci].TeacherIdx
+			if busy[t] == nil {
+				busy[t] = map[int]bool{}
+			}
+			cell := -1
+			// isi per hari dulu (slot 0 semua hari, lalu slot 1, ...) supaya rata
+			for k := 0; k < cells; k++ {
+				c := (k%5)*slotsPerDay + k/5
+				if !used[c] && !busy[t][c] {
+					cell = c
+					break
+				}
+			}
+			if cell < 0 {
+				for c := 0; c < cells; c++ {
+					if !used[c] {
+						cell = c
+						break
+					}
+				}
+			}
+			if cell < 0 {
+				break // lebih dari 20 jam/minggu → sisanya tidak dijadwalkan
+			}
+			used[cell] = true
+			busy[t][cell] = true
+
+			st, en := slotTimes(cell % slotsPerDay)
+			out = append(out, &schedModel.ClassScheduleRuleModel{
+				ClassScheduleRuleID:         g.id(),
+				ClassScheduleRuleSchoolID:   sc.ID,
+				ClassScheduleRuleScheduleID: scheduleID,
+				ClassScheduleRuleDayOfWeek:  cell/slotsPerDay + 1, // ISO: 1 = Senin
+				ClassScheduleRuleStartTime:  st,
+				ClassScheduleRuleEndTime:    en,
+				ClassScheduleRuleCSSTID:     sc.csts[ci].ID,
+			})
+		}
+	}
+	return out
+}
+
+type sessionRow struct {
+	ID       uuid.UUID  `gorm:"column:class_attendance_session_id"`
+	CSSTID   uuid.UUID  `gorm:"column:class_attendance_session_csst_id"`
+	Date     time.Time  `gorm:"column:class_attendance_session_date"`
+	StartsAt *time.Time `gorm:"column:class_attendance_session_starts_at"`
+}
+
+func (g *gen) seedAttendance(ctx context.Context, sc *schoolCtx, scheduleID uuid.UUID) error {
+	tx := g.tx.WithContext(ctx)
+
+	var sessions []sessionRow
+	if err := tx.Raw(`
+		SELECT class_attendance_session_id, class_attendance_session_csst_id,
+		       class_attendance_session_date, class_attendance_session_starts_at
+		FROM class_attendance_sessions
+		WHERE class_attendance_session_schedule_id = ?
+		  AND class_attendance_session_deleted_at IS NULL
+		ORDER BY class_attendance_session_date, class_attendance_session_starts_at, class_attendance_session_csst_id
+	`, scheduleID).Scan(&sessions).Error; err != nil {
+		return err
+	}
+	g.stats.Sessions += len(sessions)
+
+	csstIdx := make(map[uuid.UUID]int, len(sc.csts))
+	for i, c := range sc.csts {
+		csstIdx[c.ID] = i
+	}
+
+	var (
+		canceled []uuid.UUID
+		done     []uuid.UUID
+		parts    []*sessModel.ClassAttendanceSessionParticipantModel
+	)
+	method := "manual"
+	for _, s := range sessions {
+		if !s.Date.Before(g.opt.Today) {
+			continue // hari ini & ke depan: tetap scheduled/open
+		}
+		if g.chance(0.02) {
+			canceled = append(canceled, s.ID)
+			continue
+		}
+		done = append(done, s.ID)
+
+		ci, ok := csstIdx[s.CSSTID]
+		if !ok {
+			continue
+		}
+		c := sc.csts[ci]
+		teacherID := sc.teachers[c.TeacherIdx].ID
+		base := s.Date
+		if s.StartsAt != nil {
+			base = *s.StartsAt
+		}
+
+		for _, st := range sc.studentsOf(c.SectionIdx) {
+			state, late := g.attendanceState(st.Ability)
+			marked := base.Add(time.Duration(g.between(0, 10)) * time.Minute)
+			p := &sessModel.ClassAttendanceSessionParticipantModel{
+				ClassAttendanceSessionParticipantID:                        g.id(),
+				ClassAttendanceSessionParticipantSchoolID:                  sc.ID,
+				ClassAttendanceSessionParticipantSessionID:                 s.ID,
+				ClassAttendanceSessionParticipantKind:                      sessModel.ParticipantKindStudent,
+				ClassAttendanceSessionParticipantSchoolStudentID:           &st.ID,
+				ClassAttendanceSessionParticipantState:                     state,
+				ClassAttendanceSessionParticipantMarkedAt:                  &marked,
+				ClassAttendanceSessionParticipantMarkedByTeacherID:         &teacherID,
+				ClassAttendanceSessionParticipantMethod:                    &method,
+				ClassAttendanceSessionParticipantUserProfileNameSnapshot:   &st.Name,
+				ClassAttendanceSessionParticipantUserProfileGenderSnapshot: ptr(st.gender()),
+			}
+			switch state {
+			case sessModel.AttendanceStatePresent:
+				p.ClassAttendanceSessionParticipantCheckinAt = &marked
+			case sessModel.AttendanceStateLate:
+				in := base.Add(time.Duration(late) * time.Second)
+				p.ClassAttendanceSessionParticipantCheckinAt = &in
+				p.ClassAttendanceSessionParticipantMarkedAt = &in
+				p.ClassAttendanceSessionParticipantLateSeconds = &late
+			}
+			parts = append(parts, p)
+		}
+	}
+
+	if err := tx.CreateInBatches(parts, batchSize).Error; err != nil {
+		return err
+	}
+	g.stats.Participants += len(parts)
+
+	if len(canceled) > 0 {
+		if err := tx.Model(&sessModel.ClassAttendanceSessionModel{}).
+			Where("class_attendance_session_id IN ?", canceled).
+			Updates(map[string]any{
+				"class_attendance_session_status":            sessModel.SessionStatusCanceled,
+				"class_attendance_session_is_canceled":       true,
+				"class_attendance_session_attendance_status": sessModel.AttendanceStatusClosed,
+				"class_attendance_session_override_reason":   "Libur / kegiatan sekolah",
+			}).Error; err != nil {
+			return err
+		}
+	}
+	if len(done) == 0 {
+		return nil
+	}
+
+	// Sesi lampau: completed + closed + rekap jumlah per state
+	return tx.Exec(`
+		UPDATE class_attendance_sessions s SET
+		  class_attendance_session_status            = 'completed',
+		  class_attendance_session_attendance_status = 'closed',
+		  class_attendance_session_present_count     = a.present,
+		  class_attendance_session_absent_count      = a.absent,
+		  class_attendance_session_late_count        = a.late,
+		  class_attendance_session_excused_count     = a.excused,
+		  class_attendance_session_sick_count        = a.sick,
+		  class_attendance_session_leave_count       = a.leave,
+		  class_attendance_session_updated_at        = now()
+		FROM (
+		  SELECT class_attendance_session_participant_session_id AS sid,
+		    COUNT(*) FILTER (WHERE class_attendance_session_participant_state = 'present') AS present,
+		    COUNT(*) FILTER (WHERE class_attendance_session_participant_state = 'absent')  AS absent,
+		    COUNT(*) FILTER (WHERE class_attendance_session_participant_state = 'late')    AS late,
+		    COUNT(*) FILTER (WHERE class_attendance_session_participant_state = 'excused') AS excused,
+		    COUNT(*) FILTER (WHERE class_attendance_session_participant_state = 'sick')    AS sick,
+		    COUNT(*) FILTER (WHERE class_attendance_session_participant_state = 'leave')   AS leave
+		  FROM class_attendance_session_participants
+		  WHERE class_attendance_session_participant_session_id IN ?
+		  GROUP BY class_attendance_session_participant_session_id
+		) a
+		WHERE s.class_attendance_session_id = a.sid
+	`, done).Error
+}
+
+// attendanceState: siswa dengan ability rendah lebih sering alpa/terlambat.
+func (g *gen) attendanceState(ability float64) (sessModel.AttendanceState, int) {
+	r := g.rng.Float64()
+	absent := 0.01 + 0.06*(1-ability)
+	late := 0.03 + 0.08*(1-ability)
+	switch {
+	case r < 0.025:
+		return sessModel.AttendanceStateSick, 0
+	case r < 0.040:
+		return sessModel.AttendanceStateExcused, 0
+	case r < 0.045:
+		return sessModel.AttendanceStateLeave, 0
+	case r < 0.045+absent:
+		return sessModel.AttendanceStateAbsent, 0
+	case r < 0.045+absent+late:
+		return sessModel.AttendanceStateLate, g.between(60, 20*60)
+	}
+	return sessModel.AttendanceStatePresent, 0
+}
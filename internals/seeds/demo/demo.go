@@ -0,0 +1,262 @@
+// internals/seeds/demo/demo.go
+package demo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+
+	yayasanModel "madinahsalam_backend/internals/features/lembaga/school_yayasans/yayasans/model"
+)
+
+/* =========================================================
+   Demo tenant generator
+
+   seed demo --schools=3 --students=500 --seed=42
+
+   - deterministik: seed + --today yang sama → data (nama, UUID, nilai,
+     status tagihan) yang sama
+   - semua user demo: <peran>.<n>@<slug>.test, password --password
+   - 1 transaksi: gagal di tengah → tidak ada sisa data setengah jadi
+========================================================= */
+
+var ErrAlreadySeeded = errors.New("data demo untuk seed ini sudah ada (yayasan slug sama); pakai --seed lain")
+
+type Options struct {
+	Schools   int       // jumlah sekolah dalam 1 yayasan
+	Students  int       // siswa per sekolah
+	Seed      int64     // seed RNG
+	Password  string    // password semua akun demo
+	Today     time.Time // patokan "hari ini" (default: tanggal sekarang)
+	PastWeeks int       // minggu sesi yang sudah lewat (ada absensi)
+	NextWeeks int       // minggu sesi ke depan (masih scheduled)
+}
+
+func DefaultOptions() Options {
+	return Options{
+		Schools:   3,
+		Students:  500,
+		Seed:      42,
+		Password:  "demo12345",
+		PastWeeks: 6,
+		NextWeeks: 2,
+	}
+}
+
+// ParseArgs: --schools=3 --students=500 --seed=42 --password=x --today=2025-10-01 --weeks=6
+func ParseArgs(args []string) (Options, error) {
+	opt := DefaultOptions()
+	for _, a := range args {
+		k, v, ok := strings.Cut(strings.TrimPrefix(a, "--"), "=")
+		if !strings.HasPrefix(a, "--") || !ok {
+			return opt, fmt.Errorf("argumen tidak dikenali: %s", a)
+		}
+		var err error
+		switch strings.ToLower(k) {
+		case "schools":
+			opt.Schools, err = strconv.Atoi(v)
+		case "students":
+			opt.Students, err = strconv.Atoi(v)
+		case "seed":
+			opt.Seed, err = strconv.ParseInt(v, 10, 64)
+		case "password":
+			opt.Password = v
+		case "today":
+			opt.Today, err = time.ParseInLocation("2006-01-02", v, jakarta())
+		case "weeks":
+			opt.PastWeeks, err = strconv.Atoi(v)
+		case "next-weeks":
+			opt.NextWeeks, err = strconv.Atoi(v)
+		default:
+			return opt, fmt.Errorf("flag tidak dikenali: --%s", k)
+		}
+		if err != nil {
+			return opt, fmt.Errorf("--%s tidak valid: %v", k, err)
+		}
+	}
+	return opt, nil
+}
+
+func (o *Options) normalize() error {
+	if o.Schools < 1 || o.Schools > len(schoolNames) {
+		return fmt.Errorf("--schools harus 1..%d", len(schoolNames))
+	}
+	if o.Students < 10 || o.Students > 5000 {
+		return errors.New("--students harus 10..5000 (per sekolah)")
+	}
+	if len(o.Password) < 8 {
+		return errors.New("--password minimal 8 karakter")
+	}
+	if o.PastWeeks < 1 {
+		o.PastWeeks = 1
+	}
+	if o.NextWeeks < 0 {
+		o.NextWeeks = 0
+	}
+	if o.Today.IsZero() {
+		o.Today = time.Now().In(jakarta())
+	}
+	o.Today = dateOnly(o.Today)
+	return nil
+}
+
+/* =========================================================
+   Ringkasan hasil
+========================================================= */
+
+type Stats struct {
+	YayasanID   uuid.UUID
+	YayasanSlug string
+	Schools     []SchoolInfo
+
+	Users, Teachers, Students, Sections, CSSTs int
+	Sessions, Participants                     int
+	Assessments, Submissions                   int
+	Bills, Payments                            int
+}
+
+type SchoolInfo struct {
+	ID         uuid.UUID
+	Slug       string
+	Name       string
+	AdminEmail string
+}
+
+func (s *Stats) Print() {
+	log.Printf("✅ Demo yayasan %s (%s)", s.YayasanSlug, s.YayasanID)
+	for _, sc := range s.Schools {
+		log.Printf("   • %-32s slug=%s admin=%s", sc.Name, sc.Slug, sc.AdminEmail)
+	}
+	log.Printf("   users=%d teachers=%d students=%d sections=%d csst=%d",
+		s.Users, s.Teachers, s.Students, s.Sections, s.CSSTs)
+	log.Printf("   sessions=%d participants=%d assessments=%d submissions=%d",
+		s.Sessions, s.Participants, s.Assessments, s.Submissions)
+	log.Printf("   bills=%d payments=%d", s.Bills, s.Payments)
+}
+
+/* =========================================================
+   Entry point
+========================================================= */
+
+type gen struct {
+	tx     *gorm.DB
+	rng    *rand.Rand
+	opt    Options
+	tag    string // demo-<seed>
+	pwHash string
+	stats  *Stats
+
+	yayasan *yayasanCtx
+	userSeq int
+}
+
+func Run(ctx context.Context, db *gorm.DB, opt Options) (*Stats, error) {
+	if err := opt.normalize(); err != nil {
+		return nil, err
+	}
+	tag := fmt.Sprintf("demo-%d", opt.Seed)
+
+	var n int64
+	if err := db.WithContext(ctx).Model(&yayasanModel.YayasanModel{}).
+		Unscoped().Where("yayasan_slug = ?", tag).Count(&n).Error; err != nil {
+		return nil, err
+	}
+	if n > 0 {
+		return nil, ErrAlreadySeeded
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(opt.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &Stats{YayasanSlug: tag}
+	started := time.Now()
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		g := &gen{
+			tx:     tx,
+			rng:    rand.New(rand.NewSource(opt.Seed)),
+			opt:    opt,
+			tag:    tag,
+			pwHash: string(hash),
+			stats:  stats,
+		}
+		if err := g.seedYayasan(ctx); err != nil {
+			return fmt.Errorf("yayasan: %w", err)
+		}
+		for i := 0; i < opt.Schools; i++ {
+			t0 := time.Now()
+			sc, err := g.seedSchool(ctx, i)
+			if err != nil {
+				return fmt.Errorf("school #%d: %w", i+1, err)
+			}
+			log.Printf("🏫 %s selesai (%s)", sc.Name, time.Since(t0).Round(time.Millisecond))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("⏱️  demo seed selesai dalam %s", time.Since(started).Round(time.Second))
+	return stats, nil
+}
+
+/* =========================================================
+   RNG helpers (semua acak lewat g.rng → deterministik)
+========================================================= */
+
+func (g *gen) id() uuid.UUID {
+	return uuid.Must(uuid.NewRandomFromReader(g.rng))
+}
+
+func (g *gen) pick(xs []string) string { return xs[g.rng.Intn(len(xs))] }
+
+func (g *gen) chance(p float64) bool { return g.rng.Float64() < p }
+
+func (g *gen) between(lo, hi int) int { return lo + g.rng.Intn(hi-lo+1) }
+
+// normal: N(mean, sd) dipotong ke [lo, hi]
+func (g *gen) normal(mean, sd, lo, hi float64) float64 {
+	v := g.rng.NormFloat64()*sd + mean
+	return math.Max(lo, math.Min(hi, v))
+}
+
+func (g *gen) personName(female bool) string {
+	first := g.pick(firstNamesMale)
+	if female {
+		first = g.pick(firstNamesFemale)
+	}
+	return first + " " + g.pick(lastNames)
+}
+
+/* =========================================================
+   Small utils
+========================================================= */
+
+func jakarta() *time.Location {
+	loc, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		return time.FixedZone("Asia/Jakarta", 7*3600)
+	}
+	return loc
+}
+
+func dateOnly(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+func ptr[T any](v T) *T { return &v }
+
+var bulanID = []string{"", "Januari", "Februari", "Maret", "April", "Mei", "Juni",
+	"Juli", "Agustus", "September", "Oktober", "November", "Desember"}
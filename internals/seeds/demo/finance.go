@@ -0,0 +1,366 @@
+// internals/seeds/demo/finance.go
+package demo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+
+	billModel "madinahsalam_backend/internals/features/finance/billings/model"
+	gbModel "madinahsalam_backend/internals/features/finance/general_billings/model"
+	payModel "madinahsalam_backend/internals/features/finance/payments/model"
+)
+
+/* =========================================================
+   Fee rules → tagihan SPP bulanan → pembayaran (berbagai status)
+========================================================= */
+
+var vaBanks = []string{"bca", "bni", "bri", "mandiri"}
+
+// sppAmount: tarif SPP per jenjang (T1 = reguler).
+func sppAmount(level int16) int {
+	switch {
+	case level <= 6:
+		return 350_000
+	case level <= 9:
+		return 450_000
+	}
+	return 550_000
+}
+
+type finCtx struct {
+	sc        *schoolCtx
+	ruleID    uuid.UUID
+	subsidy   map[int]bool // index siswa → opsi T2 (subsidi 40%)
+	paymentNo int64
+
+	bills    []*gbModel.UserGeneralBillingModel
+	payments []*payModel.PaymentModel
+	items    []*payModel.PaymentItemModel
+}
+
+func (g *gen) seedFinance(ctx context.Context, sc *schoolCtx) error {
+	tx := g.tx.WithContext(ctx)
+	level := sc.levels[0]
+	base := sppAmount(level)
+	from := sc.term.Start
+
+	f := &finCtx{sc: sc, ruleID: g.id(), subsidy: map[int]bool{}}
+	if err := tx.Create(&billModel.FeeRuleModel{
+		FeeRuleID:          f.ruleID,
+		FeeRuleSchoolID:    sc.ID,
+		FeeRuleScope:       billModel.FeeScopeTenant,
+		FeeRuleTermID:      &sc.term.ID,
+		FeeRuleCategory:    billModel.GeneralBillingCategorySPP,
+		FeeRuleBillCode:    "SPP",
+		FeeRuleOptionCode:  "T1",
+		FeeRuleOptionLabel: ptr("Reguler"),
+		FeeRuleIsDefault:   true,
+		FeeRuleAmountOptions: []billModel.AmountOption{
+			{Code: "T1", Label: "Reguler", Amount: base},
+			{Code: "T2", Label: "Subsidi", Amount: base * 6 / 10},
+		},
+		FeeRuleEffectiveFrom: &from,
+		FeeRuleNote:          ptr("SPP bulanan " + sc.term.Year),
+	}).Error; err != nil {
+		return err
+	}
+	if err := tx.Create(&billModel.FeeRuleModel{
+		FeeRuleID:          g.id(),
+		FeeRuleSchoolID:    sc.ID,
+		FeeRuleScope:       billModel.FeeScopeTenant,
+		FeeRuleTermID:      &sc.term.ID,
+		FeeRuleCategory:    billModel.GeneralBillingCategoryRegistration,
+		FeeRuleBillCode:    "DAFTAR_ULANG",
+		FeeRuleOptionCode:  "T1",
+		FeeRuleOptionLabel: ptr("Daftar ulang"),
+		FeeRuleIsDefault:   true,
+		FeeRuleAmountOptions: []billModel.AmountOption{
+			{Code: "T1", Label: "Daftar ulang", Amount: base * 3},
+		},
+		FeeRuleEffectiveFrom: &from,
+	}).Error; err != nil {
+		return err
+	}
+
+	for i := range sc.students {
+		if g.chance(0.1) {
+			f.subsidy[i] = true
+		}
+	}
+
+	// bulan term.Start .. bulan today (maks. akhir term)
+	cur := time.Date(sc.term.Start.Year(), sc.term.Start.Month(), 1, 0, 0, 0, 0, sc.term.Start.Location())
+	last := time.Date(g.opt.Today.Year(), g.opt.Today.Month(), 1, 0, 0, 0, 0, cur.Location())
+	for ; !cur.After(last) && !cur.After(sc.term.End); cur = cur.AddDate(0, 1, 0) {
+		current := cur.Equal(last)
+		for ci := range sc.classes {
+			if err := g.seedMonthlyBills(ctx, f, ci, cur, current); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := tx.CreateInBatches(f.bills, batchSize).Error; err != nil {
+		return err
+	}
+	if err := tx.CreateInBatches(f.payments, batchSize).Error; err != nil {
+		return err
+	}
+	if err := tx.CreateInBatches(f.items, batchSize).Error; err != nil {
+		return err
+	}
+	g.stats.Bills += len(f.bills)
+	g.stats.Payments += len(f.payments)
+	return nil
+}
+
+func (g *gen) seedMonthlyBills(ctx context.Context, f *finCtx, ci int, month time.Time, current bool) error {
+	sc := f.sc
+	c := sc.classes[ci]
+	p := sc.parents[c.ParentIdx]
+	m, y := int16(month.Month()), int16(month.Year())
+	due := time.Date(month.Year(), month.Month(), 10, 0, 0, 0, 0, month.Location())
+	amount := sppAmount(p.Level)
+	title := fmt.Sprintf("SPP %s %d - %s", bulanID[m], y, p.Name)
+	code := fmt.Sprintf("SPP-%d%02d-%s", y, m, p.Code)
+
+	gb := &gbModel.GeneralBillingModel{
+		GeneralBillingID:               g.id(),
+		GeneralBillingSchoolID:         sc.ID,
+		GeneralBillingCategory:         gbModel.GeneralBillingCategorySPP,
+		GeneralBillingBillCode:         "SPP",
+		GeneralBillingCode:             &code,
+		GeneralBillingTitle:            title,
+		GeneralBillingClassID:          &c.ID,
+		GeneralBillingTermID:           &sc.term.ID,
+		GeneralBillingMonth:            &m,
+		GeneralBillingYear:             &y,
+		GeneralBillingDueDate:          &due,
+		GeneralBillingIsActive:         true,
+		GeneralBillingDefaultAmountIDR: &amount,
+	}
+	if err := g.tx.WithContext(ctx).Create(gb).Error; err != nil {
+		return err
+	}
+
+	batch := &billModel.BillBatchModel{
+		BillBatchID:       g.id(),
+		BillBatchSchoolID: sc.ID,
+		BillBatchClassID:  &c.ID,
+		BillBatchMonth:    &m,
+		BillBatchYear:     &y,
+		BillBatchTermID:   &sc.term.ID,
+		BillBatchCategory: gbModel.GeneralBillingCategorySPP,
+		BillBatchBillCode: "SPP",
+		BillBatchTitle:    title,
+		BillBatchDueDate:  &due,
+	}
+
+	payRate := 0.80
+	if current {
+		payRate = 0.35
+	}
+	cat := gbModel.GeneralBillingCategorySPP
+	billCode := "SPP"
+
+	for _, sec := range sc.sections {
+		if sec.ClassIdx != ci {
+			continue
+		}
+		for _, si := range sec.Students {
+			st := &sc.students[si]
+			opt, optIdx, amt := "T1", int16(1), amount
+			if f.subsidy[si] {
+				opt, optIdx, amt = "T2", 2, amount*6/10
+			}
+			now := g.opt.Today
+			ugb := &gbModel.UserGeneralBillingModel{
+				UserGeneralBillingID:               g.id(),
+				UserGeneralBillingSchoolID:         sc.ID,
+				UserGeneralBillingSchoolStudentID:  &st.ID,
+				UserGeneralBillingPayerUserID:      &st.UserID,
+				UserGeneralBillingBillingID:        gb.GeneralBillingID,
+				UserGeneralBillingAmountIDR:        amt,
+				UserGeneralBillingStatus:           gbModel.UserGeneralBillingStatusUnpaid,
+				UserGeneralBillingTitleSnapshot:    &title,
+				UserGeneralBillingCategorySnapshot: &cat,
+				UserGeneralBillingBillCodeSnapshot: &billCode,
+				UserGeneralBillingMeta:             datatypes.JSONMap{"fee_rule_option_code": opt, "bill_batch_id": batch.BillBatchID.String()},
+				UserGeneralBillingCreatedAt:        month,
+				UserGeneralBillingUpdatedAt:        now,
+			}
+			batch.BillBatchTotalAmountIDR += amt
+			batch.BillBatchTotalStudents++
+
+			line := &payLine{ugb: ugb, gb: gb, batch: batch, st: st, classID: c.ID,
+				opt: opt, optIdx: optIdx, month: month}
+
+			switch {
+			case g.chance(payRate + 0.15*(st.Ability-0.7)):
+				paidAt := g.paidAt(month)
+				if g.chance(0.01) {
+					// dibayar lalu di-refund → tagihan kembali unpaid
+					g.addPayment(f, line, payModel.PaymentStatusRefunded, paidAt)
+					break
+				}
+				ugb.UserGeneralBillingStatus = gbModel.UserGeneralBillingStatusPaid
+				ugb.UserGeneralBillingPaidAt = &paidAt
+				batch.BillBatchTotalPaidIDR += amt
+				batch.BillBatchTotalStudentsPaid++
+				g.addPayment(f, line, payModel.PaymentStatusPaid, paidAt)
+
+			case g.chance(0.25):
+				// percobaan bayar yang tidak berhasil
+				at := g.paidAt(month)
+				status := []payModel.PaymentStatus{
+					payModel.PaymentStatusExpired, payModel.PaymentStatusFailed, payModel.PaymentStatusCanceled,
+				}[g.rng.Intn(3)]
+				if current && g.chance(0.5) {
+					status = payModel.PaymentStatusPending
+				}
+				g.addPayment(f, line, status, at)
+			}
+			f.bills = append(f.bills, ugb)
+		}
+	}
+	return g.tx.WithContext(ctx).Create(batch).Error
+}
+
+// paidAt: tanggal 1..15 bulan tagihan, jam 07–21, tidak melewati today.
+func (g *gen) paidAt(month time.Time) time.Time {
+	t := month.AddDate(0, 0, g.between(0, 14)).
+		Add(time.Duration(g.between(7, 21))*time.Hour + time.Duration(g.between(0, 59))*time.Minute)
+	if !t.Before(g.opt.Today) {
+		t = g.opt.Today.Add(-time.Duration(g.between(1, 6)) * time.Hour)
+	}
+	return t
+}
+
+type payLine struct {
+	ugb     *gbModel.UserGeneralBillingModel
+	gb      *gbModel.GeneralBillingModel
+	batch   *billModel.BillBatchModel
+	st      *studentRow
+	classID uuid.UUID
+	opt     string
+	optIdx  int16
+	month   time.Time
+}
+
+func (g *gen) addPayment(f *finCtx, l *payLine, status payModel.PaymentStatus, at time.Time) {
+	sc := f.sc
+	f.paymentNo++
+	no := f.paymentNo
+	amt := l.ugb.UserGeneralBillingAmountIDR
+	requested := at.Add(-time.Duration(g.between(5, 90)) * time.Minute)
+	desc := *l.ugb.UserGeneralBillingTitleSnapshot + " - " + l.st.Name
+
+	pay := &payModel.PaymentModel{
+		PaymentID:               g.id(),
+		PaymentSchoolID:         &sc.ID,
+		PaymentUserID:           &l.st.UserID,
+		PaymentNumber:           &no,
+		PaymentAmountIDR:        amt,
+		PaymentCurrency:         "IDR",
+		PaymentStatus:           status,
+		PaymentEntryType:        payModel.PaymentEntryPayment,
+		PaymentSubjectUserID:    &l.st.UserID,
+		PaymentFullNameSnapshot: &l.st.Name,
+		PaymentDescription:      &desc,
+		PaymentRequestedAt:      &requested,
+	}
+
+	// metode: gateway (VA) 60%, tunai 25%, transfer manual 15%;
+	// status selain paid/refunded selalu lewat gateway
+	r := g.rng.Float64()
+	if status != payModel.PaymentStatusPaid && status != payModel.PaymentStatusRefunded {
+		r = 0
+	}
+	switch {
+	case r < 0.60:
+		bank := g.pick(vaBanks)
+		prov := payModel.GatewayProviderMidtrans
+		ext := fmt.Sprintf("DEMO-%s-%06d", sc.ID.String()[:8], no)
+		va := fmt.Sprintf("%d%011d", 8800+g.rng.Intn(100), g.rng.Int63n(1e11))
+		exp := requested.Add(24 * time.Hour)
+		pay.PaymentMethod = payModel.PaymentMethodGateway
+		pay.PaymentGatewayProvider = &prov
+		pay.PaymentExternalID = &ext
+		pay.PaymentChannelSnapshot = ptr("bank_transfer")
+		pay.PaymentBankSnapshot = &bank
+		pay.PaymentVANumberSnapshot = &va
+		pay.PaymentVANameSnapshot = &l.st.Name
+		pay.PaymentExpiresAt = &exp
+	case r < 0.85:
+		pay.PaymentMethod = payModel.PaymentMethodCash
+		pay.PaymentManualChannel = ptr("cash")
+		pay.PaymentManualReceivedByUser = &sc.adminID
+		pay.PaymentManualVerifiedByUser = &sc.adminID
+		pay.PaymentManualVerifiedAt = &at
+	default:
+		ref := fmt.Sprintf("TRF%d%06d", at.Unix()%100000, no)
+		pay.PaymentMethod = payModel.PaymentMethodBankTransfer
+		pay.PaymentManualChannel = ptr("bank_transfer")
+		pay.PaymentManualReference = &ref
+		pay.PaymentManualVerifiedByUser = &sc.adminID
+		pay.PaymentManualVerifiedAt = &at
+	}
+
+	switch status {
+	case payModel.PaymentStatusPaid:
+		pay.PaymentPaidAt = &at
+	case payModel.PaymentStatusRefunded:
+		refunded := at.Add(time.Duration(g.between(24, 96)) * time.Hour)
+		if refunded.After(g.opt.Today) {
+			refunded = g.opt.Today
+		}
+		pay.PaymentPaidAt = &at
+		pay.PaymentRefundedAt = &refunded
+		pay.PaymentNote = ptr("Dobel bayar, dana dikembalikan")
+	case payModel.PaymentStatusFailed:
+		pay.PaymentFailedAt = &at
+	case payModel.PaymentStatusCanceled:
+		pay.PaymentCanceledAt = &at
+	case payModel.PaymentStatusExpired:
+		exp := requested.Add(24 * time.Hour)
+		pay.PaymentExpiresAt = &exp
+	case payModel.PaymentStatusPending:
+		exp := g.opt.Today.Add(24 * time.Hour)
+		pay.PaymentExpiresAt = &exp
+	}
+	f.payments = append(f.payments, pay)
+
+	scope := payModel.FeeScope(billModel.FeeScopeTenant)
+	angkatan := fmt.Sprint(sc.term.Angkatan)
+	inv := fmt.Sprintf("INV/%d/%02d/%06d", l.month.Year(), int(l.month.Month()), no)
+	f.items = append(f.items, &payModel.PaymentItemModel{
+		PaymentItemID:                         g.id(),
+		PaymentItemSchoolID:                   sc.ID,
+		PaymentItemPaymentID:                  pay.PaymentID,
+		PaymentItemIndex:                      1,
+		PaymentItemUserGeneralBillingID:       &l.ugb.UserGeneralBillingID,
+		PaymentItemGeneralBillingID:           &l.gb.GeneralBillingID,
+		PaymentItemBillBatchID:                &l.batch.BillBatchID,
+		PaymentItemSchoolStudentID:            &l.st.ID,
+		PaymentItemClassID:                    &l.classID,
+		PaymentItemAmountIDR:                  amt,
+		PaymentItemFeeRuleID:                  &f.ruleID,
+		PaymentItemFeeRuleOptionCodeSnapshot:  &l.opt,
+		PaymentItemFeeRuleOptionIndexSnapshot: &l.optIdx,
+		PaymentItemFeeRuleAmountSnapshot:      &amt,
+		PaymentItemFeeRuleScopeSnapshot:       &scope,
+		PaymentItemAcademicTermID:             &sc.term.ID,
+		PaymentItemAcademicTermAcademicYear:   &sc.term.Year,
+		PaymentItemAcademicTermName:           &sc.term.Name,
+		PaymentItemAcademicTermSlug:           &sc.term.Slug,
+		PaymentItemAcademicTermAngkatan:       &angkatan,
+		PaymentItemInvoiceNumber:              &inv,
+		PaymentItemInvoiceTitle:               l.ugb.UserGeneralBillingTitleSnapshot,
+		PaymentItemInvoiceDue:                 l.gb.GeneralBillingDueDate,
+		PaymentItemTitle:                      l.ugb.UserGeneralBillingTitleSnapshot,
+	})
+}
@@ -0,0 +1,72 @@
+// internals/seeds/demo/names.go
+package demo
+
+// Data mentah untuk generator nama/alamat (Indonesia). Urutan slice = bagian
+// dari determinisme seed, jangan diurutkan ulang sembarangan.
+
+var firstNamesMale = []string{
+	"Ahmad", "Muhammad", "Abdullah", "Fauzan", "Rizky", "Hafiz", "Ilham", "Fikri",
+	"Yusuf", "Ibrahim", "Umar", "Ali", "Hasan", "Husein", "Zaki", "Farhan",
+	"Naufal", "Rafi", "Daffa", "Alif", "Arkan", "Bilal", "Hamzah", "Salman",
+	"Fadhil", "Ridwan", "Syahid", "Taufiq", "Wildan", "Zidan", "Akbar", "Luthfi",
+}
+
+var firstNamesFemale = []string{
+	"Aisyah", "Fatimah", "Khadijah", "Zahra", "Nabila", "Salsabila", "Hana", "Alya",
+	"Azizah", "Rania", "Nadia", "Syifa", "Annisa", "Maryam", "Zainab", "Humaira",
+	"Kayla", "Naura", "Qonita", "Shafa", "Tsabita", "Yasmin", "Adiba", "Hilya",
+	"Farah", "Latifa", "Nayla", "Raisa", "Safira", "Ulya", "Wardah", "Zulfa",
+}
+
+var lastNames = []string{
+	"Pratama", "Saputra", "Hidayat", "Nugroho", "Ramadhan", "Kurniawan", "Firmansyah",
+	"Hakim", "Maulana", "Syahputra", "Wibowo", "Setiawan", "Rahman", "Hasibuan",
+	"Siregar", "Lubis", "Nasution", "Harahap", "Putri", "Lestari", "Rahmawati",
+	"Fitriani", "Azzahra", "Utami", "Wulandari", "Kusuma", "Anwar", "Basri",
+}
+
+var parentPrefixes = []string{"Bapak", "Ibu"}
+
+var cities = []string{
+	"Bandung", "Bogor", "Depok", "Bekasi", "Yogyakarta", "Surakarta", "Malang",
+	"Semarang", "Medan", "Padang", "Makassar", "Banjarmasin",
+}
+
+var yayasanNames = []string{
+	"Yayasan Madinah Salam", "Yayasan Al-Hikmah Nusantara", "Yayasan Darul Ilmi",
+	"Yayasan Baitul Qur'an", "Yayasan Insan Mulia",
+}
+
+var schoolNames = []string{
+	"SDIT Madinah Salam", "SMPIT Madinah Salam", "SMAIT Madinah Salam",
+	"MI Al-Hikmah", "MTs Al-Hikmah", "MA Al-Hikmah",
+	"SDIT Darul Ilmi", "SMPIT Darul Ilmi", "Pesantren Tahfidz Baitul Qur'an",
+}
+
+// Mapel default (kode, nama, jam/minggu)
+type subjectSeed struct {
+	Code  string
+	Name  string
+	Hours int
+}
+
+var subjectSeeds = []subjectSeed{
+	{"MTK", "Matematika", 2},
+	{"BIN", "Bahasa Indonesia", 2},
+	{"BIG", "Bahasa Inggris", 1},
+	{"IPA", "Ilmu Pengetahuan Alam", 2},
+	{"PAI", "Pendidikan Agama Islam", 1},
+	{"THF", "Tahfidz Al-Qur'an", 2},
+	{"BAR", "Bahasa Arab", 1},
+}
+
+var assessmentTitles = map[string][]string{
+	"training":   {"Latihan Bab 1", "Latihan Bab 2", "Tugas Rumah", "Latihan Soal Cerita"},
+	"daily_exam": {"Ulangan Harian 1", "Ulangan Harian 2"},
+	"exam":       {"Penilaian Tengah Semester"},
+}
+
+var feedbackSamples = []string{
+	"Bagus, pertahankan.", "Perlu latihan lagi di bagian akhir.", "Jawaban rapi dan lengkap.",
+	"Kerjakan lebih teliti.", "Sudah baik, tingkatkan kecepatan.",
+}
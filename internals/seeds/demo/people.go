@@ -0,0 +1,246 @@
+// internals/seeds/demo/people.go
+package demo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	personModel "madinahsalam_backend/internals/features/lembaga/school_yayasans/teachers_students/model"
+	sectionModel "madinahsalam_backend/internals/features/school/classes/class_sections/model"
+	userTeacherService "madinahsalam_backend/internals/features/users/user_teachers/service"
+	userModel "madinahsalam_backend/internals/features/users/users/model"
+)
+
+// Ukuran batch insert massal (users, siswa, peserta absensi, dst).
+const batchSize = 500
+
+type teacherRow struct {
+	ID     uuid.UUID // school_teacher_id
+	UserID uuid.UUID
+	Slug   string
+	Name   string
+}
+
+type studentRow struct {
+	ID         uuid.UUID // school_student_id
+	UserID     uuid.UUID
+	Name       string
+	Female     bool
+	Code       string
+	ParentName string
+	ParentWA   string
+	SectionIdx int
+	Ability    float64 // 0..1, dipakai untuk nilai & kehadiran
+}
+
+func (s *studentRow) gender() string {
+	if s.Female {
+		return string(userModel.Female)
+	}
+	return string(userModel.Male)
+}
+
+/* =========================================================
+   Guru: pool per mapel (1 guru pegang ±6 section)
+========================================================= */
+
+func (g *gen) seedTeachers(ctx context.Context, sc *schoolCtx) error {
+	perSubject := (g.sectionCount(sc) + 5) / 6
+	now := g.opt.Today
+
+	var userIDs []uuid.UUID
+	for _, s := range subjectSeeds {
+		sub := subjectRow{
+			ID:    g.id(),
+			Code:  s.Code,
+			Name:  s.Name,
+			Slug:  slugify(s.Name),
+			Hours: s.Hours,
+		}
+		for k := 0; k < perSubject; k++ {
+			female := g.chance(0.5)
+			name := g.personName(female)
+			u, err := g.createUser(ctx, name, "guru", sc.Slug)
+			if err != nil {
+				return err
+			}
+			ut, err := userTeacherService.EnsureUserTeacherFromUser(ctx, g.tx, u)
+			if err != nil {
+				return err
+			}
+
+			slug := fmt.Sprintf("%s-%d", slugify(name), len(sc.teachers)+1)
+			code := fmt.Sprintf("G%03d", len(sc.teachers)+1)
+			gender := string(userModel.Male)
+			if female {
+				gender = string(userModel.Female)
+			}
+			t := &personModel.SchoolTeacherModel{
+				SchoolTeacherID:                       g.id(),
+				SchoolTeacherSchoolID:                 sc.ID,
+				SchoolTeacherUserTeacherID:            ut.UserTeacherID,
+				SchoolTeacherCode:                     &code,
+				SchoolTeacherSlug:                     &slug,
+				SchoolTeacherIsActive:                 true,
+				SchoolTeacherJoinedAt:                 &now,
+				SchoolTeacherIsVerified:               true,
+				SchoolTeacherIsPublic:                 true,
+				SchoolTeacherUserTeacherFullNameCache: &name,
+				SchoolTeacherUserTeacherGenderCache:   &gender,
+			}
+			if err := g.tx.WithContext(ctx).Create(t).Error; err != nil {
+				return err
+			}
+			sub.Teachers = append(sub.Teachers, len(sc.teachers))
+			sc.teachers = append(sc.teachers, teacherRow{ID: t.SchoolTeacherID, UserID: u.ID, Slug: slug, Name: name})
+			userIDs = append(userIDs, u.ID)
+		}
+		sc.subjects = append(sc.subjects, sub)
+	}
+	g.stats.Teachers += len(sc.teachers)
+	return g.grantRoles(ctx, "teacher", sc.ID, sc.adminID, userIDs...)
+}
+
+/* =========================================================
+   Siswa: users → profiles → school_students → student_class_sections
+   (insert massal; section sudah dibuat di seedClasses)
+========================================================= */
+
+func (g *gen) seedStudents(ctx context.Context, sc *schoolCtx) error {
+	tx := g.tx.WithContext(ctx)
+	perLevel := g.studentsPerLevel(sc)
+	assigned := g.opt.Today
+	joined := sc.term.Start
+
+	var (
+		users    []*userModel.UserModel
+		profiles []*userModel.UserProfileModel
+		students []*personModel.SchoolStudentModel
+		members  []*sectionModel.StudentClassSection
+	)
+
+	for li, level := range sc.levels {
+		// section milik tingkat ini
+		var secs []int
+		for i, s := range sc.sections {
+			if s.ParentIdx == li {
+				secs = append(secs, i)
+			}
+		}
+		for k := 0; k < perLevel[li]; k++ {
+			female := g.chance(0.5)
+			st := studentRow{
+				ID:         g.id(),
+				UserID:     g.id(),
+				Name:       g.personName(female),
+				Female:     female,
+				Code:       fmt.Sprintf("%d%02d%04d", sc.term.Angkatan%100, level, len(sc.students)+1),
+				SectionIdx: secs[k%len(secs)],
+				Ability:    g.normal(0.72, 0.14, 0.2, 1),
+			}
+			father := g.personName(false)
+			st.ParentName = parentPrefixes[g.rng.Intn(len(parentPrefixes))] + " " + father
+			st.ParentWA = fmt.Sprintf("https://wa.me/628%010d", g.rng.Int63n(1e10))
+
+			g.userSeq++
+			email := fmt.Sprintf("siswa.%d@%s.test", g.userSeq, sc.Slug)
+			users = append(users, &userModel.UserModel{
+				ID:       st.UserID,
+				UserName: userName(st.Name, g.userSeq),
+				FullName: &st.Name,
+				Email:    email,
+				Password: &g.pwHash,
+				IsActive: true,
+			})
+
+			gender := userModel.Male
+			if female {
+				gender = userModel.Female
+			}
+			age := int(level) + 6
+			dob := time.Date(g.opt.Today.Year()-age, time.Month(g.between(1, 12)), g.between(1, 28), 0, 0, 0, 0, time.UTC)
+			pob := g.pick(cities)
+			nisn := fmt.Sprintf("%010d", g.rng.Int63n(1e10))
+			uname := users[len(users)-1].UserName
+			profID := g.id()
+			profiles = append(profiles, &userModel.UserProfileModel{
+				UserProfileID:                profID,
+				UserProfileUserID:            st.UserID,
+				UserProfileFullNameCache:     &st.Name,
+				UserProfileUserNameCache:     &uname,
+				UserProfileGender:            &gender,
+				UserProfileDateOfBirth:       &dob,
+				UserProfilePlaceOfBirth:      &pob,
+				UserProfileNISN:              &nisn,
+				UserProfileCity:              &pob,
+				UserProfileParentName:        &st.ParentName,
+				UserProfileParentWhatsappURL: &st.ParentWA,
+			})
+
+			slug := fmt.Sprintf("%s-%d", slugify(st.Name), len(sc.students)+1)
+			genderStr := st.gender()
+			students = append(students, &personModel.SchoolStudentModel{
+				SchoolStudentID:                                st.ID,
+				SchoolStudentSchoolID:                          sc.ID,
+				SchoolStudentUserProfileID:                     profID,
+				SchoolStudentSlug:                              slug,
+				SchoolStudentCode:                              &st.Code,
+				SchoolStudentStatus:                            personModel.SchoolStudentActive,
+				SchoolStudentJoinedAt:                          &joined,
+				SchoolStudentUserProfileNameCache:              &st.Name,
+				SchoolStudentUserProfileParentNameCache:        &st.ParentName,
+				SchoolStudentUserProfileParentWhatsappURLCache: &st.ParentWA,
+				SchoolStudentUserProfileGenderCache:            &genderStr,
+			})
+
+			sec := &sc.sections[st.SectionIdx]
+			members = append(members, &sectionModel.StudentClassSection{
+				StudentClassSectionID:                                g.id(),
+				StudentClassSectionSchoolStudentID:                   st.ID,
+				StudentClassSectionSchoolID:                          sc.ID,
+				StudentClassSectionSectionID:                         sec.ID,
+				StudentClassSectionSectionSlugCache:                  sec.Slug,
+				StudentClassSectionStatus:                            sectionModel.StudentClassSectionActive,
+				StudentClassSectionAssignedAt:                        assigned,
+				StudentClassSectionUserProfileNameCache:              &st.Name,
+				StudentClassSectionUserProfileParentNameCache:        &st.ParentName,
+				StudentClassSectionUserProfileParentWhatsappURLCache: &st.ParentWA,
+				StudentClassSectionUserProfileGenderCache:            &genderStr,
+				StudentClassSectionStudentCodeCache:                  &st.Code,
+			})
+
+			sec.Students = append(sec.Students, len(sc.students))
+			sc.students = append(sc.students, st)
+		}
+	}
+
+	for _, batch := range []any{users, profiles, students, members} {
+		if err := tx.CreateInBatches(batch, batchSize).Error; err != nil {
+			return err
+		}
+	}
+
+	ids := make([]uuid.UUID, len(sc.students))
+	for i, st := range sc.students {
+		ids[i] = st.UserID
+	}
+	if err := g.grantRoles(ctx, "student", sc.ID, sc.adminID, ids...); err != nil {
+		return err
+	}
+	g.stats.Users += len(users)
+	g.stats.Students += len(sc.students)
+	return g.recountClasses(ctx, sc)
+}
+
+// studentsOf: data siswa 1 section (untuk snapshot nama di absensi/nilai).
+func (sc *schoolCtx) studentsOf(seci int) []*studentRow {
+	idx := sc.sections[seci].Students
+	out := make([]*studentRow, len(idx))
+	for i, si := range idx {
+		out[i] = &sc.students[si]
+	}
+	return out
+}
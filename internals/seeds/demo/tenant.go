@@ -0,0 +1,286 @@
+// internals/seeds/demo/tenant.go
+package demo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	schoolModel "madinahsalam_backend/internals/features/lembaga/school_yayasans/schools/model"
+	yayasanModel "madinahsalam_backend/internals/features/lembaga/school_yayasans/yayasans/model"
+	statsSvc "madinahsalam_backend/internals/features/lembaga/stats/lembaga_stats/service"
+	userModel "madinahsalam_backend/internals/features/users/users/model"
+)
+
+/* =========================================================
+   State per sekolah (in-memory, dipakai lintas tahap seed)
+========================================================= */
+
+type schoolCtx struct {
+	SchoolInfo
+	levels  []int16 // level kelas (1..6 / 7..9 / 10..12)
+	adminID uuid.UUID
+
+	term     termRow // term aktif (memuat "hari ini")
+	prevTerm termRow
+
+	teachers []teacherRow
+	students []studentRow
+	parents  []parentRow
+	classes  []classRow
+	sections []sectionRow
+	subjects []subjectRow
+	csts     []csstRow
+}
+
+type yayasanCtx struct {
+	id      uuid.UUID
+	adminID uuid.UUID
+}
+
+/* =========================================================
+   Yayasan
+========================================================= */
+
+func (g *gen) seedYayasan(ctx context.Context) error {
+	name := g.pick(yayasanNames)
+	city := g.pick(cities)
+
+	y := &yayasanModel.YayasanModel{
+		YayasanID:                 g.id(),
+		YayasanName:               name,
+		YayasanSlug:               g.tag,
+		YayasanCity:               &city,
+		YayasanIsActive:           true,
+		YayasanIsVerified:         true,
+		YayasanVerificationStatus: yayasanModel.YayasanVerificationApproved,
+		YayasanDescription:        ptr("Data demo (seed " + g.tag + ")"),
+	}
+	if err := g.tx.WithContext(ctx).Create(y).Error; err != nil {
+		return err
+	}
+	g.stats.YayasanID = y.YayasanID
+
+	admin, err := g.createUser(ctx, "Admin "+name, "yayasan", "")
+	if err != nil {
+		return err
+	}
+	ya := &yayasanModel.YayasanAdminModel{
+		YayasanAdminID:        g.id(),
+		YayasanAdminYayasanID: y.YayasanID,
+		YayasanAdminUserID:    admin.ID,
+	}
+	if err := g.tx.WithContext(ctx).Create(ya).Error; err != nil {
+		return err
+	}
+	g.yayasan = &yayasanCtx{id: y.YayasanID, adminID: admin.ID}
+	return nil
+}
+
+/* =========================================================
+   Sekolah: semua tahap berurutan
+========================================================= */
+
+func (g *gen) seedSchool(ctx context.Context, idx int) (*schoolCtx, error) {
+	sc, err := g.createSchool(ctx, idx)
+	if err != nil {
+		return nil, err
+	}
+	steps := []struct {
+		name string
+		fn   func(context.Context, *schoolCtx) error
+	}{
+		{"staff", g.seedStaff},
+		{"terms", g.seedTerms},
+		{"teachers", g.seedTeachers},
+		{"classes", g.seedClasses},
+		{"students", g.seedStudents},
+		{"subjects", g.seedSubjects},
+		{"csst", g.seedCSSTs},
+		{"attendance", g.seedSchedulesAndAttendance},
+		{"assessments", g.seedAssessments},
+		{"finance", g.seedFinance},
+		{"stats", g.seedSchoolStats},
+	}
+	for _, s := range steps {
+		if err := s.fn(ctx, sc); err != nil {
+			return nil, fmt.Errorf("%s: %w", s.name, err)
+		}
+	}
+	g.stats.Schools = append(g.stats.Schools, sc.SchoolInfo)
+	return sc, nil
+}
+
+func (g *gen) createSchool(ctx context.Context, idx int) (*schoolCtx, error) {
+	name := schoolNames[idx]
+	slug := fmt.Sprintf("%s-%s", g.tag, slugify(name))
+	if len(slug) > 100 {
+		slug = slug[:100]
+	}
+	city := g.pick(cities)
+	tz := "Asia/Jakarta"
+	minPass := 70
+	quota := sectionSize
+
+	levels := levelsFor(name)
+	levelsJSON := make([]string, 0, len(levels))
+	for _, l := range levels {
+		levelsJSON = append(levelsJSON, fmt.Sprintf("%d", l))
+	}
+
+	s := &schoolModel.SchoolModel{
+		SchoolID:                     g.id(),
+		SchoolYayasanID:              &g.yayasan.id,
+		SchoolName:                   name,
+		SchoolSlug:                   slug,
+		SchoolCity:                   &city,
+		SchoolLocation:               ptr("Jl. Pendidikan No. " + fmt.Sprint(g.between(1, 200)) + ", " + city),
+		SchoolIsActive:               true,
+		SchoolIsVerified:             true,
+		SchoolVerificationStatus:     schoolModel.VerificationApproved,
+		SchoolIsIslamicSchool:        true,
+		SchoolTenantProfile:          schoolModel.TenantProfileSchoolPlus,
+		SchoolLevels:                 []byte("[" + strings.Join(levelsJSON, ",") + "]"),
+		SchoolTimezone:               &tz,
+		SchoolDefaultMinPassingScore: &minPass,
+		SchoolDefaultClassQouta:      &quota,
+	}
+	// school_number diisi sequence DB
+	if err := g.tx.WithContext(ctx).Omit("school_number").Create(s).Error; err != nil {
+		return nil, err
+	}
+	return &schoolCtx{
+		SchoolInfo: SchoolInfo{ID: s.SchoolID, Slug: slug, Name: name},
+		levels:     levels,
+	}, nil
+}
+
+// levelsFor: jenjang dari prefix nama sekolah.
+func levelsFor(name string) []int16 {
+	switch {
+	case strings.HasPrefix(name, "SD"), strings.HasPrefix(name, "MI "):
+		return []int16{1, 2, 3, 4, 5, 6}
+	case strings.HasPrefix(name, "SMA"), strings.HasPrefix(name, "MA "):
+		return []int16{10, 11, 12}
+	default:
+		return []int16{7, 8, 9}
+	}
+}
+
+/* =========================================================
+   Staff: admin (dkm) + bendahara
+========================================================= */
+
+func (g *gen) seedStaff(ctx context.Context, sc *schoolCtx) error {
+	admin, err := g.createUser(ctx, g.personName(false), "admin", sc.Slug)
+	if err != nil {
+		return err
+	}
+	sc.adminID = admin.ID
+	sc.AdminEmail = admin.Email
+	if err := g.grantRoles(ctx, "dkm", sc.ID, g.yayasan.adminID, admin.ID); err != nil {
+		return err
+	}
+
+	treasurer, err := g.createUser(ctx, g.personName(true), "bendahara", sc.Slug)
+	if err != nil {
+		return err
+	}
+	return g.grantRoles(ctx, "bendahara", sc.ID, admin.ID, treasurer.ID)
+}
+
+/* =========================================================
+   Statistik lembaga
+========================================================= */
+
+func (g *gen) seedSchoolStats(ctx context.Context, sc *schoolCtx) error {
+	stats := statsSvc.NewLembagaStatsService()
+	tx := g.tx.WithContext(ctx)
+	if err := stats.EnsureForSchool(tx, sc.ID); err != nil {
+		return err
+	}
+	return stats.ApplyDelta(tx, sc.ID, statsSvc.Delta{
+		Classes:  len(sc.classes),
+		Sections: len(sc.sections),
+		Students: len(sc.students),
+		Teachers: len(sc.teachers),
+	})
+}
+
+/* =========================================================
+   Users
+========================================================= */
+
+// createUser: email <kind>.<n>@<domain>.test (domain = slug sekolah / tag yayasan).
+func (g *gen) createUser(ctx context.Context, fullName, kind, domain string) (*userModel.UserModel, error) {
+	g.userSeq++
+	if domain == "" {
+		domain = g.tag
+	}
+	u := &userModel.UserModel{
+		ID:       g.id(),
+		UserName: userName(fullName, g.userSeq),
+		FullName: &fullName,
+		Email:    fmt.Sprintf("%s.%d@%s.test", kind, g.userSeq, domain),
+		Password: &g.pwHash,
+		IsActive: true,
+	}
+	if err := g.tx.WithContext(ctx).Create(u).Error; err != nil {
+		return nil, err
+	}
+	g.stats.Users++
+	return u, nil
+}
+
+func userName(fullName string, seq int) string {
+	base := strings.ToLower(strings.ReplaceAll(fullName, " ", "."))
+	base = strings.Map(func(r rune) rune {
+		if r == '.' || (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return -1
+	}, base)
+	suffix := fmt.Sprintf(".%d", seq)
+	if len(base)+len(suffix) > 50 {
+		base = base[:50-len(suffix)]
+	}
+	return base + suffix
+}
+
+// grantRoles: fn_grant_role massal (1 statement) untuk banyak user sekaligus.
+func (g *gen) grantRoles(ctx context.Context, role string, schoolID, assignedBy uuid.UUID, userIDs ...uuid.UUID) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	ids := make([]string, len(userIDs))
+	for i, id := range userIDs {
+		ids[i] = id.String()
+	}
+	return g.tx.WithContext(ctx).Exec(
+		`SELECT fn_grant_role(u::uuid, ?::text, ?::uuid, ?::uuid) FROM unnest(?::text[]) AS u`,
+		role, schoolID, assignedBy, pq.Array(ids),
+	).Error
+}
+
+/* =========================================================
+   Slug
+========================================================= */
+
+func slugify(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9'):
+			b.WriteRune(r)
+			dash = false
+		case !dash && b.Len() > 0:
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
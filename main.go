@@ -14,13 +14,21 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/compress"
 	"github.com/gofiber/fiber/v2/middleware/etag"
 	"github.com/gofiber/fiber/v2/middleware/recover"
-	"github.com/gofiber/utils"
 	"gorm.io/gorm"
 
+	"madinahsalam_backend/internals/commands"
 	"madinahsalam_backend/internals/configs"
 	database "madinahsalam_backend/internals/databases"
 
 	// attend "madinahsalam_backend/internals/features/school/classes/class_attendance_sessions/service"
+	subsched "madinahsalam_backend/internals/features/finance/school_subscriptions/scheduler"
+	apikeysvc "madinahsalam_backend/internals/features/lembaga/api_keys/service"
+	auditsched "madinahsalam_backend/internals/features/lembaga/audit_logs/scheduler"
+	auditsvc "madinahsalam_backend/internals/features/lembaga/audit_logs/service"
+	importworker "madinahsalam_backend/internals/features/lembaga/school_yayasans/imports/worker"
+	webhookworker "madinahsalam_backend/internals/features/lembaga/webhooks/worker"
+	attdeviceworker "madinahsalam_backend/internals/features/school/class_others/attendance_devices/worker"
+	quizworker "madinahsalam_backend/internals/features/school/submissions_assesments/quizzes/worker"
 	authsched "madinahsalam_backend/internals/features/users/auth/scheduler"
 
 	osshelper "madinahsalam_backend/internals/helpers/oss"
@@ -28,11 +36,24 @@ import (
 
 	payctl "madinahsalam_backend/internals/features/finance/payments/controller/payments"
 	helperAuth "madinahsalam_backend/internals/helpers/auth"
+	"madinahsalam_backend/internals/helpers/telemetry"
 	middlewares "madinahsalam_backend/internals/middlewares"
 )
 
 func main() {
 	configs.LoadEnv()
+
+	// Subcommand (migrate, schema-check, ...) → jalan lalu exit, tanpa server HTTP
+	if len(os.Args) > 1 {
+		os.Exit(commands.Run(os.Args[1:]))
+	}
+
+	// Tracing: exporter via OTEL_TRACES_EXPORTER (default none = no-op)
+	shutdownTracing, err := telemetry.InitTracing(context.Background(), telemetry.LoadConfig())
+	if err != nil {
+		log.Fatalf("tracing init error: %v", err)
+	}
+
 	db := initDB()
 
 	workersCtx, cancelWorkers := context.WithCancel(context.Background())
@@ -60,6 +81,7 @@ func main() {
 		}
 		p := c.Path()
 		if strings.HasPrefix(p, "/health") ||
+			strings.HasPrefix(p, "/metrics") ||
 			strings.HasPrefix(p, "/api/auth/") ||
 			strings.HasPrefix(p, "/public/donations/midtrans/webhook") { // ✅ whitelist baru
 			return c.Next()
@@ -82,7 +104,7 @@ func main() {
 		}
 	}()
 
-	waitForShutdown(app, cancelWorkers, db)
+	waitForShutdown(app, cancelWorkers, db, shutdownTracing)
 }
 
 func buildApp() *fiber.App {
@@ -106,27 +128,11 @@ func buildApp() *fiber.App {
 	// ✅ Pastikan preflight dihandle cepat (header CORS sudah dipasang oleh middleware di atas)
 	app.Options("/*", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusNoContent) })
 
-	// observability ringkas
-	app.Use(func(c *fiber.Ctx) error {
-		id := c.Get("X-Request-ID")
-		if id == "" {
-			id = utils.UUID()
-		}
-		c.Set("X-Request-ID", id)
-		c.Locals("reqid", id)
-
-		start := time.Now()
-		ctx, cancel := context.WithTimeout(c.Context(), 5*time.Second)
-		defer cancel()
-		c.SetUserContext(ctx)
-
-		err := c.Next()
-		log.Printf("[REQ] id=%s %s %s status=%d dur=%s",
-			id, c.Method(), c.OriginalURL(), c.Response().StatusCode(), time.Since(start))
-		return err
-	})
+	// observability: request id, span OTel, metrik Prometheus, log [REQ]
+	app.Use(middlewares.RequestTelemetry())
 
 	app.Get("/health", func(c *fiber.Ctx) error { return c.SendString("ok") })
+	app.Get("/metrics", telemetry.MetricsHandler())
 	return app
 }
 
@@ -145,6 +151,16 @@ func initDB() *gorm.DB {
 		sqlDB.SetMaxIdleConns(20) // default kamu 10
 		sqlDB.SetConnMaxLifetime(10 * time.Minute)
 	}
+
+	// Audit trail: callback create/update/delete → audit_logs
+	if err := auditsvc.Register(database.DB, auditsvc.LoadConfig()); err != nil {
+		log.Fatalf("audit register error: %v", err)
+	}
+
+	// Tracing & metrik query + pool koneksi
+	if err := telemetry.InstrumentGORM(database.DB); err != nil {
+		log.Fatalf("telemetry register error: %v", err)
+	}
 	return database.DB
 }
 
@@ -168,13 +184,34 @@ func startWorkers(ctx context.Context, db *gorm.DB) {
 
 	// 4) OSS trash reaper (gabungan cron pembersih)
 	osshelper.StartTrashReaperCron(db)
+
+	// 5) Langganan sekolah: invoice perpanjangan, grace & lapse
+	subsched.StartSchoolSubscriptionScheduler(db)
+
+	// 6) Bulk import siswa/guru (XLSX/CSV) — job async
+	go importworker.RunImportWorker(ctx, db)
+
+	// 7) Audit trail: purge log melewati retensi
+	auditsched.StartAuditRetentionScheduler(db)
+
+	// 8) Webhook keluar: kirim antrean + retry backoff
+	go webhookworker.RunWebhookWorker(ctx, db)
+
+	// 9) API key: flush counter pemakaian (usage_count + rekap harian)
+	go apikeysvc.RunUsageFlusher(ctx, db)
+
+	// 10) Mesin absen: terapkan tap fingerprint/RFID ke sesi kehadiran
+	go attdeviceworker.RunPunchWorker(ctx, db)
+
+	// 11) Quiz berbatas waktu: auto-submit attempt yang lewat deadline
+	go quizworker.RunAttemptExpiryWorker(ctx, db)
 }
 
 /* ===============================
    Shutdown
    =============================== */
 
-func waitForShutdown(app *fiber.App, cancelWorkers context.CancelFunc, db *gorm.DB) {
+func waitForShutdown(app *fiber.App, cancelWorkers context.CancelFunc, db *gorm.DB, shutdownTracing func(context.Context) error) {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
@@ -191,6 +228,11 @@ func waitForShutdown(app *fiber.App, cancelWorkers context.CancelFunc, db *gorm.
 	if sqlDB, err := db.DB(); err == nil {
 		_ = sqlDB.Close()
 	}
+
+	// 4) Flush span yang masih di buffer exporter
+	if err := shutdownTracing(ctx); err != nil {
+		log.Printf("tracing shutdown error: %v", err)
+	}
 	log.Println("👋 Shutdown complete.")
 }
 